@@ -298,6 +298,21 @@ bool MVCCIsValidSplitKey(DBSlice key);
 DBStatus MVCCFindSplitKey(DBIterator* iter, DBKey start, DBKey end, DBKey min_split,
                           int64_t target_size, DBString* split_key);
 
+// DBIgnoredSeqNumRange is an inclusive range of sequence numbers
+// that were rolled back by a transaction. Its layout must match that
+// of enginepb.IgnoredSeqNumRange, which is passed in directly.
+typedef struct {
+  int32_t start_seqnum;
+  int32_t end_seqnum;
+} DBIgnoredSeqNumRange;
+
+// DBIgnoredSeqNums is the sorted, non-overlapping list of sequence
+// number ranges rolled back by a transaction.
+typedef struct {
+  DBIgnoredSeqNumRange* ranges;
+  int len;
+} DBIgnoredSeqNums;
+
 // DBTxn contains the fields from a roachpb.Transaction that are
 // necessary for MVCC Get and Scan operations. Note that passing a
 // serialized roachpb.Transaction appears to be a non-starter as an
@@ -311,6 +326,7 @@ typedef struct {
   uint32_t epoch;
   int32_t sequence;
   DBTimestamp max_timestamp;
+  DBIgnoredSeqNums ignored_seqnums;
 } DBTxn;
 
 typedef struct {
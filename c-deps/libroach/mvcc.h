@@ -59,6 +59,7 @@ template <bool reverse> class mvccScanner {
         txn_epoch_(txn.epoch),
         txn_sequence_(txn.sequence),
         txn_max_timestamp_(txn.max_timestamp),
+        txn_ignored_seqnums_(txn.ignored_seqnums),
         inconsistent_(inconsistent),
         tombstones_(tombstones),
         ignore_sequence_(ignore_sequence),
@@ -164,6 +165,21 @@ template <bool reverse> class mvccScanner {
     return results_;
   }
 
+  // seqNumIsIgnored returns true iff the sequence number falls within
+  // one of the ranges rolled back by the transaction.
+  bool seqNumIsIgnored(int32_t sequence) const {
+    // The ranges are sorted and non-overlapping. Search from the end
+    // since recently rolled back writes are the most likely to be
+    // encountered.
+    for (int i = txn_ignored_seqnums_.len - 1; i >= 0; i--) {
+      if (sequence < txn_ignored_seqnums_.ranges[i].start_seqnum) {
+        continue;
+      }
+      return sequence <= txn_ignored_seqnums_.ranges[i].end_seqnum;
+    }
+    return false;
+  }
+
   bool getFromIntentHistory() {
     cockroach::storage::engine::enginepb::MVCCMetadata_SequencedIntent readIntent;
     readIntent.set_sequence(txn_sequence_);
@@ -178,10 +194,15 @@ template <bool reverse> class mvccScanner {
            const cockroach::storage::engine::enginepb::MVCCMetadata_SequencedIntent& b) -> bool {
           return a.sequence() < b.sequence();
         });
+    // Skip over the values written at sequence numbers that were rolled
+    // back by the transaction.
+    while (up != meta_.intent_history().begin() && seqNumIsIgnored((up - 1)->sequence())) {
+      --up;
+    }
     if (up == meta_.intent_history().begin()) {
       // It is possible that no intent exists such that the sequence is less
-      // than the read sequence. In this case, we cannot read a value from the
-      // intent history.
+      // than the read sequence (and that was not rolled back). In this case,
+      // we cannot read a value from the intent history.
       return false;
     }
     const auto intent = *(up - 1);
@@ -299,7 +320,8 @@ template <bool reverse> class mvccScanner {
     }
 
     if (txn_epoch_ == meta_.txn().epoch()) {
-      if ((ignore_sequence_) || (txn_sequence_ >= meta_.txn().sequence())) {
+      if ((ignore_sequence_) || (txn_sequence_ >= meta_.txn().sequence() &&
+                                 !seqNumIsIgnored(meta_.txn().sequence()))) {
         // 8. We're reading our own txn's intent at an equal or higher sequence.
         // Note that we read at the intent timestamp, not at our read timestamp
         // as the intent timestamp may have been pushed forward by another
@@ -307,12 +329,13 @@ template <bool reverse> class mvccScanner {
         return seekVersion(meta_timestamp, false);
       } else {
         // 9. We're reading our own txn's intent at a lower sequence than is
-        // currently present in the intent. This means the intent we're seeing
-        // was written at a higher sequence than the read and that there may or
-        // may not be earlier versions of the intent (with lower sequence
-        // numbers) that we should read. If there exists a value in the intent
-        // history that has a sequence number equal to or less than the read
-        // sequence, read that value.
+        // currently present in the intent, or the intent's current value was
+        // written at a sequence number that has since been rolled back. This
+        // means the intent we're seeing is not visible to the read and that
+        // there may or may not be earlier versions of the intent (with lower
+        // sequence numbers) that we should read. If there exists a value in
+        // the intent history that has a sequence number equal to or less than
+        // the read sequence and that was not rolled back, read that value.
         const bool found = getFromIntentHistory();
         if (found) {
           return advanceKey();
@@ -652,6 +675,7 @@ template <bool reverse> class mvccScanner {
   const uint32_t txn_epoch_;
   const int32_t txn_sequence_;
   const DBTimestamp txn_max_timestamp_;
+  const DBIgnoredSeqNums txn_ignored_seqnums_;
   const bool inconsistent_;
   const bool tombstones_;
   const bool ignore_sequence_;
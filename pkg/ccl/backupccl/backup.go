@@ -35,7 +35,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -43,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -563,6 +566,30 @@ func ResolveTargetsToDescriptors(
 	return matched.descs, matched.expandedDB, nil
 }
 
+// checkBackupSupported returns an error if the given descriptor can't be
+// backed up. RESTORE can't recreate user-defined types and functions, so
+// neither they nor the tables using the types can be backed up.
+func checkBackupSupported(desc sqlbase.Descriptor) error {
+	if typeDesc := desc.GetType(); typeDesc != nil {
+		return unimplemented.Newf("backup-user-defined-types",
+			"cannot back up user-defined type %q", typeDesc.Name)
+	}
+	if funcDesc := desc.GetFunction(); funcDesc != nil {
+		return unimplemented.Newf("backup-user-defined-functions",
+			"cannot back up user-defined function %q", funcDesc.Name)
+	}
+	if tableDesc := desc.GetTable(); tableDesc != nil {
+		for i := range tableDesc.Columns {
+			if col := &tableDesc.Columns[i]; col.Type.UserDefined() {
+				return unimplemented.Newf("backup-user-defined-types",
+					"cannot back up table %q: column %q has user-defined type %s",
+					tableDesc.Name, col.Name, col.Type.SQLString())
+			}
+		}
+	}
+	return nil
+}
+
 type spanAndTime struct {
 	span       roachpb.Span
 	start, end hlc.Timestamp
@@ -898,6 +925,9 @@ func backupPlanHook(
 				}
 				tables = append(tables, tableDesc)
 			}
+			if err := checkBackupSupported(desc); err != nil {
+				return err
+			}
 		}
 
 		if err := ensureInterleavesIncluded(tables); err != nil {
@@ -1084,6 +1114,7 @@ type backupResumer struct {
 	job      *jobs.Job
 	settings *cluster.Settings
 	res      roachpb.BulkOpSummary
+	pts      protectedts.Storage
 }
 
 // Resume is part of the jobs.Resumer interface.
@@ -1125,6 +1156,12 @@ func (b *backupResumer) Resume(
 		// implementations.
 		log.Warningf(ctx, "unable to load backup checkpoint while resuming job %d: %v", *b.job.ID(), err)
 	}
+	b.pts = p.ExecCfg().ProtectedTimestampProvider
+	if details.ProtectedTimestampRecord == uuid.Nil {
+		if err := b.protectTimestamp(ctx, p.ExecCfg().DB, &backupDesc, details); err != nil {
+			return errors.Wrapf(err, "protecting the data read by the backup")
+		}
+	}
 	res, err := backup(
 		ctx,
 		p.ExecCfg().DB,
@@ -1140,11 +1177,62 @@ func (b *backupResumer) Resume(
 	return err
 }
 
+// protectTimestamp keeps the data read by the backup from being garbage
+// collected while the backup runs. The record is written in the same
+// transaction which stores its ID in the job details, so that if the job goes
+// away without releasing it, the protected timestamp reconciler does.
+func (b *backupResumer) protectTimestamp(
+	ctx context.Context,
+	db *client.DB,
+	backupDesc *BackupDescriptor,
+	details jobspb.BackupDetails,
+) error {
+	// A backup with revision history reads every revision since StartTime,
+	// while other backups only read the data as of EndTime.
+	ts := backupDesc.EndTime
+	if backupDesc.MVCCFilter == MVCCFilter_All && !backupDesc.StartTime.IsEmpty() {
+		ts = backupDesc.StartTime
+	}
+	rec := protectedts.Record{
+		ID:        uuid.MakeV4(),
+		Timestamp: ts,
+		JobID:     *b.job.ID(),
+		Spans:     backupDesc.Spans,
+	}
+	return db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		if err := b.pts.Protect(ctx, txn, &rec); err != nil {
+			return err
+		}
+		details.ProtectedTimestampRecord = rec.ID
+		return b.job.WithTxn(txn).SetDetails(ctx, details)
+	})
+}
+
+// releaseProtectedTimestamp releases the record written by protectTimestamp,
+// in the transaction which marks the job as terminal. A job canceled from a
+// node which didn't run it has no access to the protected timestamp storage;
+// its record is released by the reconciler instead.
+func (b *backupResumer) releaseProtectedTimestamp(ctx context.Context, txn *client.Txn) error {
+	details := b.job.Details().(jobspb.BackupDetails)
+	if b.pts == nil || details.ProtectedTimestampRecord == uuid.Nil {
+		return nil
+	}
+	err := b.pts.Release(ctx, txn, details.ProtectedTimestampRecord)
+	if err == protectedts.ErrNotExists {
+		return nil
+	}
+	return err
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface.
-func (b *backupResumer) OnFailOrCancel(context.Context, *client.Txn) error { return nil }
+func (b *backupResumer) OnFailOrCancel(ctx context.Context, txn *client.Txn) error {
+	return b.releaseProtectedTimestamp(ctx, txn)
+}
 
 // OnSuccess is part of the jobs.Resumer interface.
-func (b *backupResumer) OnSuccess(context.Context, *client.Txn) error { return nil }
+func (b *backupResumer) OnSuccess(ctx context.Context, txn *client.Txn) error {
+	return b.releaseProtectedTimestamp(ctx, txn)
+}
 
 // OnTerminal is part of the jobs.Resumer interface.
 func (b *backupResumer) OnTerminal(
@@ -53,6 +53,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/cockroach/pkg/workload/bank"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
 	"github.com/gogo/protobuf/proto"
@@ -1158,6 +1159,50 @@ func TestBackupRestoreInterleaved(t *testing.T) {
 	})
 }
 
+func TestBackupUserDefinedTypes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `SET database = d`)
+	sqlDB.Exec(t, `CREATE TYPE greeting AS ENUM ('hello', 'hi')`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (x greeting)`)
+	sqlDB.Exec(t, `CREATE TABLE d.u (x INT)`)
+	sqlDB.Exec(t, `SET database = data`)
+
+	// RESTORE can't recreate the types, so neither they nor the tables using
+	// them are backed up.
+	sqlDB.ExpectErr(t, `cannot back up table "t": column "x" has user-defined type`,
+		`BACKUP d.t TO $1`, localFoo)
+	sqlDB.ExpectErr(t, `cannot back up user-defined type "greeting"`,
+		`BACKUP d.* TO $1`, localFoo)
+	sqlDB.ExpectErr(t, `cannot back up`, `BACKUP DATABASE d TO $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP d.u TO $1`, localFoo)
+}
+
+func TestBackupUserDefinedFunctions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `SET database = d`)
+	sqlDB.Exec(t, `CREATE FUNCTION add_one(x INT) RETURNS INT AS 'SELECT x + 1' LANGUAGE SQL`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (x INT)`)
+	sqlDB.Exec(t, `SET database = data`)
+
+	// RESTORE can't recreate the function, so the database can't be backed up
+	// as a whole.
+	sqlDB.ExpectErr(t, `cannot back up user-defined function "add_one"`,
+		`BACKUP DATABASE d TO $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP d.t TO $1`, localFoo)
+}
+
 func TestBackupRestoreCrossTableReferences(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -2064,6 +2109,61 @@ func TestAsOfSystemTimeOnRestoredData(t *testing.T) {
 	}
 }
 
+// TestBackupProtectedTimestamp checks that a backup protects the data it reads
+// while it runs, and releases the protection once it finishes.
+func TestBackupProtectedTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Block the export responses, so that the protected timestamp records can
+	// be inspected while the backup is running.
+	var allowResponse chan struct{}
+	params := base.TestClusterArgs{}
+	params.ServerArgs.Knobs.Store = &storage.StoreTestingKnobs{
+		TestingResponseFilter: func(ba roachpb.BatchRequest, br *roachpb.BatchResponse) *roachpb.Error {
+			for _, ru := range br.Responses {
+				if _, ok := ru.GetInner().(*roachpb.ExportResponse); ok {
+					<-allowResponse
+				}
+			}
+			return nil
+		},
+	}
+
+	const numAccounts = 1000
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetupWithParams(t, singleNode, numAccounts, initNone, params)
+	defer cleanupFn()
+	conn := sqlDB.DB.(*gosql.DB)
+
+	allowResponse = make(chan struct{})
+	backupDone := make(chan error)
+	go func() {
+		_, err := conn.Exec(`BACKUP DATABASE data TO $1 WITH revision_history`, localFoo)
+		backupDone <- err
+	}()
+
+	// The backup protects its data before it sends any export request.
+	allowResponse <- struct{}{}
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"1"}})
+
+	close(allowResponse)
+	if err := <-backupDone; err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"0"}})
+
+	var payloadBytes []byte
+	sqlDB.QueryRow(t, `SELECT payload FROM system.jobs WHERE id = (
+		SELECT job_id FROM crdb_internal.jobs WHERE job_type = 'BACKUP'
+	)`).Scan(&payloadBytes)
+	var payload jobspb.Payload
+	if err := protoutil.Unmarshal(payloadBytes, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.GetBackup().ProtectedTimestampRecord == uuid.Nil {
+		t.Fatal("expected the backup job to record its protected timestamp record")
+	}
+}
+
 func TestBackupRestoreChecksum(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -54,6 +54,10 @@ type descriptorResolver struct {
 	dbsByName map[string]sqlbase.ID
 	// Map: dbID -> obj name -> obj ID
 	objsByName map[sqlbase.ID]map[string]sqlbase.ID
+	// Map: dbID -> IDs of the user-defined types and functions of the
+	// database. They are not objects that can be named by targets, but they
+	// are part of the expansion of their database.
+	typesAndFuncsByDB map[sqlbase.ID][]sqlbase.ID
 }
 
 // LookupSchema implements the tree.TableNameTargetResolver interface.
@@ -95,9 +99,10 @@ func (r *descriptorResolver) LookupObject(
 // known set of descriptors.
 func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, error) {
 	r := &descriptorResolver{
-		descByID:   make(map[sqlbase.ID]sqlbase.Descriptor),
-		dbsByName:  make(map[string]sqlbase.ID),
-		objsByName: make(map[sqlbase.ID]map[string]sqlbase.ID),
+		descByID:          make(map[sqlbase.ID]sqlbase.Descriptor),
+		dbsByName:         make(map[string]sqlbase.ID),
+		objsByName:        make(map[sqlbase.ID]map[string]sqlbase.ID),
+		typesAndFuncsByDB: make(map[sqlbase.ID][]sqlbase.ID),
 	}
 
 	// Iterate to find the databases first. We need that because we also
@@ -144,6 +149,12 @@ func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, err
 			objMap[tbDesc.Name] = tbDesc.ID
 			r.objsByName[parentDesc.GetID()] = objMap
 		}
+		if typeDesc := desc.GetType(); typeDesc != nil {
+			r.typesAndFuncsByDB[typeDesc.ParentID] = append(r.typesAndFuncsByDB[typeDesc.ParentID], typeDesc.ID)
+		}
+		if funcDesc := desc.GetFunction(); funcDesc != nil {
+			r.typesAndFuncsByDB[funcDesc.ParentID] = append(r.typesAndFuncsByDB[funcDesc.ParentID], funcDesc.ID)
+		}
 	}
 
 	return r, nil
@@ -261,6 +272,9 @@ func descriptorsMatchingTargets(
 				ret.descs = append(ret.descs, resolver.descByID[tblID])
 			}
 		}
+		for _, id := range resolver.typesAndFuncsByDB[dbID] {
+			ret.descs = append(ret.descs, resolver.descByID[id])
+		}
 	}
 
 	return ret, nil
@@ -64,6 +64,8 @@ const (
 	avroSchemaLong    = `long`
 	avroSchemaNull    = `null`
 	avroSchemaString  = `string`
+
+	avroSchemaNoSuffix = ``
 )
 
 type avroLogicalType struct {
@@ -125,7 +127,7 @@ type avroMetadata map[string]interface{}
 // avroEnvelopeOpts controls which fields in avroEnvelopeRecord are set.
 type avroEnvelopeOpts struct {
 	updatedField, resolvedField bool
-	afterField                  bool
+	beforeField, afterField     bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -133,8 +135,8 @@ type avroEnvelopeOpts struct {
 type avroEnvelopeRecord struct {
 	avroRecord
 
-	opts  avroEnvelopeOpts
-	after *avroDataRecord
+	opts          avroEnvelopeOpts
+	before, after *avroDataRecord
 }
 
 // columnDescToAvroSchema converts a column descriptor into its corresponding
@@ -377,10 +379,34 @@ func indexToAvroSchema(
 
 // tableToAvroSchema converts a column descriptor into its corresponding avro
 // record schema. The fields are kept in the same order as `tableDesc.Columns`.
-func tableToAvroSchema(tableDesc *sqlbase.TableDescriptor) (*avroDataRecord, error) {
+// If a name suffix is provided (as opposed to avroSchemaNoSuffix), it is
+// appended to the name of the record, so that the same table can appear more
+// than once in an envelope schema.
+func tableToAvroSchema(
+	tableDesc *sqlbase.TableDescriptor, nameSuffix string,
+) (*avroDataRecord, error) {
+	return familyToAvroSchema(tableDesc, nil /* family */, nameSuffix)
+}
+
+// familyToAvroSchema is like tableToAvroSchema, but if family is set, the
+// record only has fields for the primary key columns and the columns of that
+// column family, and the name of the family is appended to the name of the
+// record.
+func familyToAvroSchema(
+	tableDesc *sqlbase.TableDescriptor, family *sqlbase.ColumnFamilyDescriptor, nameSuffix string,
+) (*avroDataRecord, error) {
+	name := SQLNameToAvroName(tableDesc.Name)
+	var colIDs map[sqlbase.ColumnID]struct{}
+	if family != nil {
+		name = name + `_` + SQLNameToAvroName(family.Name)
+		colIDs = valueColumnIDs(tableDesc, family)
+	}
+	if nameSuffix != avroSchemaNoSuffix {
+		name = name + `_` + nameSuffix
+	}
 	schema := &avroDataRecord{
 		avroRecord: avroRecord{
-			Name:       SQLNameToAvroName(tableDesc.Name),
+			Name:       name,
 			SchemaType: `record`,
 		},
 		fieldIdxByName:   make(map[string]int),
@@ -388,6 +414,9 @@ func tableToAvroSchema(tableDesc *sqlbase.TableDescriptor) (*avroDataRecord, err
 	}
 	for colIdx := range tableDesc.Columns {
 		col := &tableDesc.Columns[colIdx]
+		if _, ok := colIDs[col.ID]; !ok && colIDs != nil {
+			continue
+		}
 		field, err := columnDescToAvroSchema(col)
 		if err != nil {
 			return nil, err
@@ -489,7 +518,7 @@ func (r *avroDataRecord) rowFromNative(native interface{}) (sqlbase.EncDatumRow,
 // envelopeToAvroSchema creates an avro record schema for an envelope containing
 // before and after versions of a row change and metadata about that row change.
 func envelopeToAvroSchema(
-	topic string, opts avroEnvelopeOpts, after *avroDataRecord,
+	topic string, opts avroEnvelopeOpts, before, after *avroDataRecord,
 ) (*avroEnvelopeRecord, error) {
 	schema := &avroEnvelopeRecord{
 		avroRecord: avroRecord{
@@ -515,6 +544,15 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, resolvedField)
 	}
+	if opts.beforeField {
+		schema.before = before
+		beforeField := &avroSchemaField{
+			Name:       `before`,
+			SchemaType: []avroSchemaType{avroSchemaNull, before},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, beforeField)
+	}
 	if opts.afterField {
 		schema.after = after
 		afterField := &avroSchemaField{
@@ -539,11 +577,9 @@ func envelopeToAvroSchema(
 // BinaryFromRow encodes the given metadata and row data into avro's defined
 // binary format.
 func (r *avroEnvelopeRecord) BinaryFromRow(
-	buf []byte, meta avroMetadata, row sqlbase.EncDatumRow,
+	buf []byte, meta avroMetadata, beforeRow, afterRow sqlbase.EncDatumRow,
 ) ([]byte, error) {
-	native := map[string]interface{}{
-		`after`: nil,
-	}
+	native := map[string]interface{}{}
 	if r.opts.updatedField {
 		native[`updated`] = nil
 		if u, ok := meta[`updated`]; ok {
@@ -567,11 +603,22 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 		}
 	}
 	// WIP verify that meta is now empty
+	if r.opts.beforeField {
+		if beforeRow == nil {
+			native[`before`] = nil
+		} else {
+			beforeNative, err := r.before.nativeFromRow(beforeRow)
+			if err != nil {
+				return nil, err
+			}
+			native[`before`] = goavro.Union(avroUnionKey(&r.before.avroRecord), beforeNative)
+		}
+	}
 	if r.opts.afterField {
-		if row == nil {
+		if afterRow == nil {
 			native[`after`] = nil
 		} else {
-			afterNative, err := r.after.nativeFromRow(row)
+			afterNative, err := r.after.nativeFromRow(afterRow)
 			if err != nil {
 				return nil, err
 			}
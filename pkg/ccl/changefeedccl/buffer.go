@@ -26,7 +26,11 @@ import (
 )
 
 type bufferEntry struct {
-	kv       roachpb.KeyValue
+	kv roachpb.KeyValue
+	// prevVal is the value of kv.Key before this change. It is only populated
+	// if the changefeed was started with the diff option. Its timestamp is not
+	// set.
+	prevVal  roachpb.Value
 	resolved *jobspb.ResolvedSpan
 	// Timestamp of the schema that should be used to read this KV.
 	// If unset (zero-valued), the value's timestamp will be used instead.
@@ -48,9 +52,9 @@ func makeBuffer() *buffer {
 // AddKV inserts a changed kv into the buffer. Individual keys must be added in
 // increasing mvcc order.
 func (b *buffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, schemaTimestamp hlc.Timestamp,
+	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, schemaTimestamp hlc.Timestamp,
 ) error {
-	return b.addEntry(ctx, bufferEntry{kv: kv, schemaTimestamp: schemaTimestamp})
+	return b.addEntry(ctx, bufferEntry{kv: kv, prevVal: prevVal, schemaTimestamp: schemaTimestamp})
 }
 
 // AddResolved inserts a resolved timestamp notification in the buffer.
@@ -58,6 +62,14 @@ func (b *buffer) AddResolved(ctx context.Context, span roachpb.Span, ts hlc.Time
 	return b.addEntry(ctx, bufferEntry{resolved: &jobspb.ResolvedSpan{Span: span, Timestamp: ts}})
 }
 
+// AddSchemaChangeStop inserts a resolved timestamp notification for a schema
+// change boundary at which the changefeed stops.
+func (b *buffer) AddSchemaChangeStop(ctx context.Context, span roachpb.Span, ts hlc.Timestamp) error {
+	return b.addEntry(ctx, bufferEntry{
+		resolved: &jobspb.ResolvedSpan{Span: span, Timestamp: ts, SchemaChangeStop: true},
+	})
+}
+
 func (b *buffer) addEntry(ctx context.Context, e bufferEntry) error {
 	select {
 	case <-ctx.Done():
@@ -96,6 +108,7 @@ var memBufferColTypes = []types.T{
 	*types.Int,   // ts.Logical
 	*types.Int,   // schemaTimestamp.WallTime
 	*types.Int,   // schemaTimestamp.Logical
+	*types.Bytes, // prevVal.RawBytes
 }
 
 // memBuffer is an in-memory buffer for changed KV and resolved timestamp
@@ -136,7 +149,7 @@ func (b *memBuffer) Close(ctx context.Context) {
 // AddKV inserts a changed kv into the buffer. Individual keys must be added in
 // increasing mvcc order.
 func (b *memBuffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, schemaTimestamp hlc.Timestamp,
+	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, schemaTimestamp hlc.Timestamp,
 ) error {
 	b.allocMu.Lock()
 	row := tree.Datums{
@@ -148,6 +161,7 @@ func (b *memBuffer) AddKV(
 		b.allocMu.a.NewDInt(tree.DInt(kv.Value.Timestamp.Logical)),
 		b.allocMu.a.NewDInt(tree.DInt(schemaTimestamp.WallTime)),
 		b.allocMu.a.NewDInt(tree.DInt(schemaTimestamp.Logical)),
+		b.allocMu.a.NewDBytes(tree.DBytes(prevVal.RawBytes)),
 	}
 	b.allocMu.Unlock()
 	return b.addRow(ctx, row)
@@ -165,6 +179,7 @@ func (b *memBuffer) AddResolved(ctx context.Context, span roachpb.Span, ts hlc.T
 		b.allocMu.a.NewDInt(tree.DInt(ts.Logical)),
 		tree.DNull,
 		tree.DNull,
+		tree.DNull,
 	}
 	b.allocMu.Unlock()
 	return b.addRow(ctx, row)
@@ -194,6 +209,9 @@ func (b *memBuffer) Get(ctx context.Context) (bufferEntry, error) {
 			WallTime: int64(*row[6].(*tree.DInt)),
 			Logical:  int32(*row[7].(*tree.DInt)),
 		}
+		if prevVal := []byte(*row[8].(*tree.DBytes)); len(prevVal) > 0 {
+			e.prevVal = roachpb.Value{RawBytes: prevVal}
+		}
 		return e, nil
 	}
 	e.resolved = &jobspb.ResolvedSpan{
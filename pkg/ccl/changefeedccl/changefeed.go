@@ -10,20 +10,26 @@ package changefeedccl
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 var changefeedPollInterval = func() *settings.DurationSetting {
@@ -56,20 +62,99 @@ type emitEntry struct {
 // kvsToRows gets changed kvs from a closure and converts them into sql rows. It
 // returns a closure that may be repeatedly called to advance the changefeed.
 // The returned closure is not threadsafe.
+//
+// Each kv of a table with multiple column families only holds the columns of
+// one family. Unless the changefeed splits column families, the kvs of the
+// families of a row written at the same timestamp are assembled into a single
+// row, even when they are not adjacent. This requires all of them to have been
+// received, so they are held until a resolved timestamp covers them. The
+// families which were not written are then read from db as of the timestamp
+// of the change, in one batch for all the rows with that timestamp. These
+// reads don't block on intents, since there are none below a resolved
+// timestamp, but fail if the values have been garbage collected.
 func kvsToRows(
 	leaseMgr *sql.LeaseManager,
+	db *client.DB,
 	details jobspb.ChangefeedDetails,
 	inputFn func(context.Context) (bufferEntry, error),
 ) func(context.Context) ([]emitEntry, error) {
 	rfCache := newRowFetcherCache(leaseMgr)
+	_, withDiff := details.Opts[optDiff]
+	_, splitFamilies := details.Opts[optSplitColumnFamilies]
 
-	var kvs row.SpanKVFetcher
-	appendEmitEntryForKV := func(
-		ctx context.Context, output []emitEntry, kv roachpb.KeyValue, schemaTimestamp hlc.Timestamp,
+	// pendingRows are the rows of tables with multiple column families that
+	// are being assembled, in the order in which their first kv was received.
+	var pendingRows []*pendingRow
+	pendingByKey := make(map[pendingRowKey]*pendingRow)
+
+	var kvs, prevKVs row.SpanKVFetcher
+	// decodeRows appends the rows decoded from kvs by rf to output.
+	decodeRows := func(
+		ctx context.Context,
+		output []emitEntry,
+		rf *row.Fetcher,
+		updated hlc.Timestamp,
+		family *sqlbase.ColumnFamilyDescriptor,
 		bufferGetTimestamp time.Time,
 	) ([]emitEntry, error) {
-		// Reuse kvs to save allocations.
-		kvs.KVs = kvs.KVs[:0]
+		if err := rf.StartScanFrom(ctx, &kvs); err != nil {
+			return nil, err
+		}
+		for {
+			var r emitEntry
+			var err error
+			r.bufferGetTimestamp = bufferGetTimestamp
+			r.row.datums, r.row.tableDesc, _, err = rf.NextRow(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if r.row.datums == nil {
+				return output, nil
+			}
+			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
+			r.row.deleted = rf.RowIsDeleted()
+			r.row.updated = updated
+			r.row.family = family
+			output = append(output, r)
+		}
+	}
+	// setPrevRows sets the previous value of the given rows to the row decoded
+	// from prevKVs by prevRF. A missing previous value decodes as a deleted row.
+	setPrevRows := func(
+		ctx context.Context,
+		rows []emitEntry,
+		prevRF *row.Fetcher,
+		prevDesc *sqlbase.ImmutableTableDescriptor,
+	) error {
+		if err := prevRF.StartScanFrom(ctx, &prevKVs); err != nil {
+			return err
+		}
+		prevDatums, prevTableDesc, _, err := prevRF.NextRow(ctx)
+		if err != nil {
+			return err
+		}
+		prevDeleted := prevDatums == nil || prevRF.RowIsDeleted()
+		prevDatums = append(sqlbase.EncDatumRow(nil), prevDatums...)
+		if prevTableDesc == nil {
+			prevTableDesc = prevDesc.TableDesc()
+		}
+		for i := range rows {
+			rows[i].row.prevDatums = prevDatums
+			rows[i].row.prevDeleted = prevDeleted
+			rows[i].row.prevTableDesc = prevTableDesc
+		}
+		return nil
+	}
+
+	appendEmitEntryForKV := func(
+		ctx context.Context, output []emitEntry, input bufferEntry,
+	) ([]emitEntry, error) {
+		kv := input.kv
+		schemaTimestamp := kv.Value.Timestamp
+		isScan := input.schemaTimestamp != (hlc.Timestamp{})
+		if isScan {
+			schemaTimestamp = input.schemaTimestamp
+		}
 
 		desc, err := rfCache.TableDescForKey(ctx, kv.Key, schemaTimestamp)
 		if err != nil {
@@ -83,30 +168,160 @@ func kvsToRows(
 			return nil, nil
 		}
 
-		rf, err := rfCache.RowFetcherForTableDesc(desc)
+		var family *sqlbase.ColumnFamilyDescriptor
+		if len(desc.Families) > 1 {
+			rowKey, err := keys.EnsureSafeSplitKey(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+			if !splitFamilies {
+				key := pendingRowKey{rowKey: string(rowKey), updated: schemaTimestamp}
+				r, ok := pendingByKey[key]
+				if !ok {
+					r = &pendingRow{
+						desc:               desc,
+						rowKey:             rowKey,
+						updated:            schemaTimestamp,
+						isScan:             isScan,
+						bufferGetTimestamp: input.bufferGetTimestamp,
+					}
+					pendingByKey[key] = r
+					pendingRows = append(pendingRows, r)
+				}
+				r.kvs = append(r.kvs, kv)
+				if withDiff && !isScan {
+					r.prevKVs = append(r.prevKVs, roachpb.KeyValue{Key: kv.Key, Value: input.prevVal})
+				}
+				return output, nil
+			}
+			if family, err = familyForKey(desc.TableDesc(), kv.Key, rowKey); err != nil {
+				return nil, err
+			}
+		}
+
+		rowFetcherFor := func(desc *sqlbase.ImmutableTableDescriptor) (*row.Fetcher, error) {
+			if family == nil {
+				return rfCache.RowFetcherForTableDesc(desc)
+			}
+			familyDesc, err := desc.FindFamilyByID(family.ID)
+			if err != nil {
+				return nil, err
+			}
+			return rfCache.RowFetcherForColumnFamily(desc, familyDesc)
+		}
+		rf, err := rowFetcherFor(desc)
 		if err != nil {
 			return nil, err
 		}
-		// TODO(dan): Handle tables with multiple column families.
-		kvs.KVs = append(kvs.KVs, kv)
-		if err := rf.StartScanFrom(ctx, &kvs); err != nil {
+		// Reuse kvs to save allocations.
+		kvs.KVs = append(kvs.KVs[:0], kv)
+		firstEntry := len(output)
+		if output, err = decodeRows(
+			ctx, output, rf, schemaTimestamp, family, input.bufferGetTimestamp,
+		); err != nil {
 			return nil, err
 		}
+		if !withDiff || len(output) == firstEntry {
+			return output, nil
+		}
 
-		for {
-			var r emitEntry
-			r.bufferGetTimestamp = bufferGetTimestamp
-			r.row.datums, r.row.tableDesc, _, err = rf.NextRow(ctx)
-			if err != nil {
+		// Decode the value of the row before this change. Rows emitted by scans
+		// have no previous value.
+		prevDesc := desc
+		if isScan {
+			if prevDesc, err = rfCache.TableDescForKey(ctx, kv.Key, schemaTimestamp.Prev()); err != nil {
 				return nil, err
 			}
-			if r.row.datums == nil {
-				break
+		}
+		prevRF, err := rowFetcherFor(prevDesc)
+		if err != nil {
+			return nil, err
+		}
+		prevKVs.KVs = prevKVs.KVs[:0]
+		if !isScan {
+			prevKVs.KVs = append(prevKVs.KVs, roachpb.KeyValue{Key: kv.Key, Value: input.prevVal})
+		}
+		if err := setPrevRows(ctx, output[firstEntry:], prevRF, prevDesc); err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+
+	appendEmitEntryForRow := func(
+		ctx context.Context, output []emitEntry, r *pendingRow,
+	) ([]emitEntry, error) {
+		kvs.KVs = appendLiveKVs(kvs.KVs[:0], r.kvs)
+		if len(kvs.KVs) == 0 {
+			// None of the families exist as of the change, so the row was deleted.
+			kvs.KVs = append(kvs.KVs, roachpb.KeyValue{Key: r.kvs[0].Key})
+		}
+		rf, err := rfCache.RowFetcherForTableDesc(r.desc)
+		if err != nil {
+			return nil, err
+		}
+		firstEntry := len(output)
+		if output, err = decodeRows(
+			ctx, output, rf, r.updated, nil /* family */, r.bufferGetTimestamp,
+		); err != nil {
+			return nil, err
+		}
+		if !withDiff || len(output) == firstEntry {
+			return output, nil
+		}
+
+		prevDesc := r.desc
+		if r.isScan {
+			if prevDesc, err = rfCache.TableDescForKey(ctx, r.rowKey, r.updated.Prev()); err != nil {
+				return nil, err
+			}
+		}
+		prevRF, err := rfCache.RowFetcherForTableDesc(prevDesc)
+		if err != nil {
+			return nil, err
+		}
+		prevKVs.KVs = appendLiveKVs(prevKVs.KVs[:0], r.prevKVs)
+		if err := setPrevRows(ctx, output[firstEntry:], prevRF, prevDesc); err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+
+	// flushPendingRows appends the pending rows covered by the given resolved
+	// span to output, in the order of their timestamps, and forgets them.
+	flushPendingRows := func(
+		ctx context.Context, output []emitEntry, resolved *jobspb.ResolvedSpan,
+	) ([]emitEntry, error) {
+		var ready []*pendingRow
+		remaining := pendingRows[:0]
+		for _, r := range pendingRows {
+			if !resolved.Timestamp.Less(r.updated) && resolved.Span.ContainsKey(r.rowKey) {
+				ready = append(ready, r)
+				delete(pendingByKey, pendingRowKey{rowKey: string(r.rowKey), updated: r.updated})
+			} else {
+				remaining = append(remaining, r)
+			}
+		}
+		for i := len(remaining); i < len(pendingRows); i++ {
+			pendingRows[i] = nil
+		}
+		pendingRows = remaining
+		if len(ready) == 0 {
+			return output, nil
+		}
+
+		// The kvs of a row are received in the order of their timestamps, but
+		// the kvs of different families of a row are not.
+		sort.SliceStable(ready, func(i, j int) bool {
+			return ready[i].updated.Less(ready[j].updated)
+		})
+		if err := readUnwrittenFamilies(ctx, db, ready, withDiff); err != nil {
+			return nil, err
+		}
+		for _, r := range ready {
+			var err error
+			if output, err = appendEmitEntryForRow(ctx, output, r); err != nil {
+				return nil, err
 			}
-			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
-			r.row.deleted = rf.RowIsDeleted()
-			r.row.updated = schemaTimestamp
-			output = append(output, r)
 		}
 		return output, nil
 	}
@@ -124,17 +339,15 @@ func kvsToRows(
 				if log.V(3) {
 					log.Infof(ctx, "changed key %s %s", input.kv.Key, input.kv.Value.Timestamp)
 				}
-				schemaTimestamp := input.kv.Value.Timestamp
-				if input.schemaTimestamp != (hlc.Timestamp{}) {
-					schemaTimestamp = input.schemaTimestamp
-				}
-				output, err = appendEmitEntryForKV(
-					ctx, output, input.kv, schemaTimestamp, input.bufferGetTimestamp)
+				output, err = appendEmitEntryForKV(ctx, output, input)
 				if err != nil {
 					return nil, err
 				}
 			}
 			if input.resolved != nil {
+				if output, err = flushPendingRows(ctx, output, input.resolved); err != nil {
+					return nil, err
+				}
 				output = append(output, emitEntry{
 					resolved:           input.resolved,
 					bufferGetTimestamp: input.bufferGetTimestamp,
@@ -147,12 +360,143 @@ func kvsToRows(
 	}
 }
 
+// pendingRow is a changed row of a table with multiple column families, which
+// is assembled from the kvs of the families written by the change.
+type pendingRow struct {
+	desc   *sqlbase.ImmutableTableDescriptor
+	rowKey roachpb.Key
+	// updated is the timestamp of the change, or the timestamp of the scan for
+	// a row emitted by a scan.
+	updated hlc.Timestamp
+	// isScan is set for a row emitted by a scan, whose kvs hold all the
+	// families of the row.
+	isScan bool
+	// kvs and prevKVs are the values of the families as of and before the
+	// change. prevKVs is only populated with the diff option.
+	kvs, prevKVs []roachpb.KeyValue
+	// bufferGetTimestamp is the time the first kv of the row came out of the
+	// buffer.
+	bufferGetTimestamp time.Time
+}
+
+type pendingRowKey struct {
+	rowKey  string
+	updated hlc.Timestamp
+}
+
+// appendLiveKVs appends the kvs of src which are not deletions to dst, sorted
+// by key.
+func appendLiveKVs(dst, src []roachpb.KeyValue) []roachpb.KeyValue {
+	first := len(dst)
+	for _, kv := range src {
+		if kv.Value.IsPresent() {
+			dst = append(dst, kv)
+		}
+	}
+	live := dst[first:]
+	sort.Slice(live, func(i, j int) bool { return live[i].Key.Compare(live[j].Key) < 0 })
+	return dst
+}
+
+// familyForKey returns the column family of the given kv key of a table, whose
+// row prefix is rowKey.
+func familyForKey(
+	tableDesc *sqlbase.TableDescriptor, key, rowKey roachpb.Key,
+) (*sqlbase.ColumnFamilyDescriptor, error) {
+	_, familyID, err := encoding.DecodeUvarintAscending(key[len(rowKey):])
+	if err != nil {
+		return nil, err
+	}
+	return tableDesc.FindFamilyByID(sqlbase.FamilyID(familyID))
+}
+
+// readUnwrittenFamilies adds the values of the column families which were not
+// written by the changes of the given rows to their kvs. Since these families
+// didn't change, their values are added to the prevKVs of the rows as well if
+// withDiff is set. The values are read as of the timestamps of the changes, in
+// one batch per timestamp. The changes must be resolved, so that the reads
+// don't block on intents.
+func readUnwrittenFamilies(
+	ctx context.Context, db *client.DB, rows []*pendingRow, withDiff bool,
+) error {
+	var timestamps []hlc.Timestamp
+	byTimestamp := make(map[hlc.Timestamp][]*pendingRow)
+	for _, r := range rows {
+		if r.isScan || len(r.kvs) >= len(r.desc.Families) {
+			continue
+		}
+		if _, ok := byTimestamp[r.updated]; !ok {
+			timestamps = append(timestamps, r.updated)
+		}
+		byTimestamp[r.updated] = append(byTimestamp[r.updated], r)
+	}
+
+	for _, ts := range timestamps {
+		rows := byTimestamp[ts]
+		// results holds the read kvs of each row, which are only added to the
+		// row once the transaction succeeds, since it may be retried.
+		results := make([][]roachpb.KeyValue, len(rows))
+		if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			txn.SetFixedTimestamp(ctx, ts)
+			b := txn.NewBatch()
+			var rowIdxs []int
+			for i, r := range rows {
+				results[i] = results[i][:0]
+				for j := range r.desc.Families {
+					key := roachpb.Key(keys.MakeFamilyKey(r.rowKey, uint32(r.desc.Families[j].ID)))
+					if !hasKey(r.kvs, key) {
+						b.Get(key)
+						rowIdxs = append(rowIdxs, i)
+					}
+				}
+			}
+			if err := txn.Run(ctx, b); err != nil {
+				return err
+			}
+			for i, res := range b.Results {
+				idx := rowIdxs[i]
+				for _, kv := range res.Rows {
+					if kv.Value != nil {
+						results[idx] = append(results[idx], roachpb.KeyValue{Key: kv.Key, Value: *kv.Value})
+					}
+				}
+			}
+			return nil
+		}); err != nil {
+			if _, ok := errors.Cause(err).(*roachpb.BatchTimestampBeforeGCError); ok {
+				return errors.Wrapf(err,
+					"reading the column families of rows changed at %s, which have been garbage "+
+						"collected since the changefeed fell behind the gc.ttlseconds of their table", ts)
+			}
+			return err
+		}
+		for i, r := range rows {
+			r.kvs = append(r.kvs, results[i]...)
+			if withDiff {
+				r.prevKVs = append(r.prevKVs, results[i]...)
+			}
+		}
+	}
+	return nil
+}
+
+// hasKey returns whether kvs contains a kv with the given key.
+func hasKey(kvs []roachpb.KeyValue, key roachpb.Key) bool {
+	for i := range kvs {
+		if kvs[i].Key.Equal(key) {
+			return true
+		}
+	}
+	return false
+}
+
 // emitEntries connects to a sink, receives rows from a closure, and repeatedly
 // emits them to the sink. It returns a closure that may be repeatedly called to
 // advance the changefeed and which returns span-level resolved timestamp
 // updates. The returned closure is not threadsafe.
 func emitEntries(
 	settings *cluster.Settings,
+	evalCtx *tree.EvalContext,
 	details jobspb.ChangefeedDetails,
 	watchedSpans []roachpb.Span,
 	encoder Encoder,
@@ -161,8 +505,101 @@ func emitEntries(
 	knobs TestingKnobs,
 	metrics *Metrics,
 ) func(context.Context) ([]jobspb.ResolvedSpan, error) {
+	var sel *tree.SelectClause
+	projections := make(map[tableIDAndVersion]*projection)
+	projectionFor := func(tableDesc *sqlbase.TableDescriptor) (*projection, error) {
+		cacheKey := makeTableIDAndVersion(tableDesc.ID, tableDesc.Version)
+		if p, ok := projections[cacheKey]; ok {
+			return p, nil
+		}
+		if sel == nil {
+			var err error
+			if sel, err = parseChangefeedSelect(details.Select); err != nil {
+				return nil, err
+			}
+		}
+		p, err := makeProjection(sel, tableDesc)
+		if err != nil {
+			return nil, err
+		}
+		projections[cacheKey] = p
+		return p, nil
+	}
+	// projectRow evaluates the AS SELECT clause of the changefeed against a
+	// row and, if it has one, the previous value of the row. It returns false
+	// if the row was not deleted and doesn't pass the filter of the clause, in
+	// which case it shouldn't be emitted. Deletions are always emitted, because
+	// the deleted row may have passed the filter.
+	projectRow := func(row encodeRow) (encodeRow, bool, error) {
+		p, err := projectionFor(row.tableDesc)
+		if err != nil {
+			return encodeRow{}, false, err
+		}
+		projected := encodeRow{
+			updated:     row.updated,
+			deleted:     row.deleted,
+			tableDesc:   p.tableDesc,
+			prevDeleted: row.prevDeleted,
+		}
+		if !row.deleted {
+			var ok bool
+			projected.datums, ok, err = p.project(evalCtx, row.datums, true /* applyFilter */)
+			if err != nil || !ok {
+				return encodeRow{}, false, err
+			}
+		}
+		if row.prevTableDesc != nil {
+			prevP, err := projectionFor(row.prevTableDesc)
+			if err != nil {
+				return encodeRow{}, false, err
+			}
+			projected.prevTableDesc = prevP.tableDesc
+			if row.prevDatums != nil && !row.prevDeleted {
+				projected.prevDatums, _, err = prevP.project(
+					evalCtx, row.prevDatums, false /* applyFilter */)
+				if err != nil {
+					return encodeRow{}, false, err
+				}
+			}
+		}
+		return projected, true, nil
+	}
+
+	partitionColumn, partitionByColumn := details.Opts[optKafkaPartitionColumn]
+	partitionColumnIdxs := make(map[tableIDAndVersion]int)
+	var partitionKeyAlloc sqlbase.DatumAlloc
+	// partitionKeyFor returns the encoded value of the partition column of a
+	// row. The partition column is part of the primary key, so it's set even
+	// for deleted rows and every change to a row has the same partition key.
+	partitionKeyFor := func(row encodeRow, buf []byte) ([]byte, error) {
+		cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+		colIdx, ok := partitionColumnIdxs[cacheKey]
+		if !ok {
+			var err error
+			if colIdx, err = partitionColumnIdx(row.tableDesc, partitionColumn); err != nil {
+				return nil, err
+			}
+			partitionColumnIdxs[cacheKey] = colIdx
+		}
+		return row.datums[colIdx].Encode(
+			&row.tableDesc.Columns[colIdx].Type, &partitionKeyAlloc,
+			sqlbase.DatumEncoding_ASCENDING_KEY, buf)
+	}
+
 	var scratch bufalloc.ByteAllocator
+	var partitionKeyBuf []byte
 	emitRowFn := func(ctx context.Context, row encodeRow) error {
+		if details.Select != `` {
+			projected, ok, err := projectRow(row)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			row.projected = &projected
+		}
+
 		var keyCopy, valueCopy []byte
 		encodedKey, err := encoder.EncodeKey(row)
 		if err != nil {
@@ -174,6 +611,13 @@ func emitEntries(
 			return err
 		}
 		scratch, valueCopy = scratch.Copy(encodedValue, 0 /* extraCap */)
+		var partitionKey []byte
+		if partitionByColumn {
+			if partitionKeyBuf, err = partitionKeyFor(row, partitionKeyBuf[:0]); err != nil {
+				return err
+			}
+			scratch, partitionKey = scratch.Copy(partitionKeyBuf, 0 /* extraCap */)
+		}
 
 		if knobs.BeforeEmitRow != nil {
 			if err := knobs.BeforeEmitRow(ctx); err != nil {
@@ -181,7 +625,7 @@ func emitEntries(
 			}
 		}
 		if err := sink.EmitRow(
-			ctx, row.tableDesc, keyCopy, valueCopy, row.updated,
+			ctx, row.tableDesc, keyCopy, valueCopy, row.updated, partitionKey,
 		); err != nil {
 			return err
 		}
@@ -204,6 +648,9 @@ func emitEntries(
 		if err != nil {
 			return nil, err
 		}
+		// A changefeed that stops at a schema change gets no more resolved
+		// timestamps after the boundary, so those must be forwarded right away.
+		schemaChangeStop := false
 		for _, input := range inputs {
 			if input.bufferGetTimestamp == (time.Time{}) {
 				// We could gracefully handle this instead of panic'ing, but
@@ -224,6 +671,7 @@ func emitEntries(
 			if input.resolved != nil {
 				_ = watchedSF.Forward(input.resolved.Span, input.resolved.Timestamp)
 				resolvedSpans = append(resolvedSpans, *input.resolved)
+				schemaChangeStop = schemaChangeStop || input.resolved.SchemaChangeStop
 			}
 		}
 
@@ -252,7 +700,8 @@ func emitEntries(
 		} else {
 			timeBetweenFlushes = changefeedPollInterval.Get(&settings.SV) / 5
 		}
-		if len(resolvedSpans) == 0 || timeutil.Since(lastFlush) < timeBetweenFlushes {
+		if len(resolvedSpans) == 0 ||
+			(timeutil.Since(lastFlush) < timeBetweenFlushes && !schemaChangeStop) {
 			return nil, nil
 		}
 
@@ -124,6 +124,7 @@ func distChangefeedFlow(
 					ChangeAggregator: &distsqlpb.ChangeAggregatorSpec{
 						Watches: watches,
 						Feed:    details,
+						JobID:   jobID,
 					},
 				},
 				Output: []distsqlpb.OutputRouterSpec{{Type: distsqlpb.OutputRouterSpec_PASS_THROUGH}},
@@ -66,6 +66,14 @@ type changeAggregator struct {
 	// resolvedSpanBuf contains resolved span updates to send to changeFrontier.
 	// If sink is a bufferSink, it must be emptied before these are sent.
 	resolvedSpanBuf encDatumRowBuffer
+
+	// status tracks the resolved timestamps of the watched spans and what was
+	// emitted to the sink, for crdb_internal.changefeed_status.
+	status *changefeedStatus
+	// metrics are monitoring counters shared between all changefeeds.
+	metrics *Metrics
+	// metricsID is the id under which status is registered in metrics.
+	metricsID int
 }
 
 var _ distsqlrun.Processor = &changeAggregator{}
@@ -147,12 +155,20 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 			initialHighWater = watch.InitialResolved
 		}
 	}
+	sf := makeSpanFrontier(spans...)
+	for _, watch := range ca.spec.Watches {
+		sf.Forward(watch.Span, watch.InitialResolved)
+	}
+	ca.status = makeChangefeedStatus(
+		ca.spec.JobID, changefeedProcessorAggregator, ca.spec.Feed.SinkURI, sf)
 
 	// The job registry has a set of metrics used to monitor the various jobs it
 	// runs. They're all stored as the `metric.Struct` interface because of
 	// dependency cycles.
 	metrics := ca.flowCtx.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
-	ca.sink = makeMetricsSink(metrics, ca.sink)
+	ca.metrics = metrics
+	ca.metricsID = metrics.registerStatus(ca.status)
+	ca.sink = makeMetricsSink(metrics, ca.status, ca.sink)
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 
 	var knobs TestingKnobs
@@ -178,10 +194,11 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		ca.flowCtx.Settings, ca.flowCtx.ClientDB, ca.flowCtx.ClientDB.Clock(), ca.flowCtx.Gossip,
 		spans, ca.spec.Feed, initialHighWater, buf, leaseMgr, metrics, ca.pollerMemMon,
 	)
-	rowsFn := kvsToRows(leaseMgr, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(leaseMgr, ca.flowCtx.ClientDB, ca.spec.Feed, buf.Get)
 
 	ca.tickFn = emitEntries(
-		ca.flowCtx.Settings, ca.spec.Feed, spans, ca.encoder, ca.sink, rowsFn, knobs, metrics)
+		ca.flowCtx.Settings, ca.flowCtx.NewEvalCtx(), ca.spec.Feed, spans, ca.encoder, ca.sink,
+		rowsFn, knobs, metrics)
 
 	// Give errCh enough buffer both possible errors from supporting goroutines,
 	// but only the first one is ever used.
@@ -228,6 +245,11 @@ func (ca *changeAggregator) close() {
 				log.Warningf(ca.Ctx, `error closing sink. goroutines may have leaked: %v`, err)
 			}
 		}
+		if ca.metrics != nil {
+			ca.metrics.mu.Lock()
+			delete(ca.metrics.mu.statuses, ca.metricsID)
+			ca.metrics.mu.Unlock()
+		}
 		ca.memAcc.Close(ca.Ctx)
 		if ca.pollerMemMon != nil {
 			ca.pollerMemMon.Stop(ca.Ctx)
@@ -269,6 +291,7 @@ func (ca *changeAggregator) tick() error {
 	}
 
 	for _, resolvedSpan := range resolvedSpans {
+		ca.status.forward(resolvedSpan.Span, resolvedSpan.Timestamp)
 		resolvedBytes, err := protoutil.Marshal(&resolvedSpan)
 		if err != nil {
 			return err
@@ -348,6 +371,17 @@ type changeFrontier struct {
 	// metricsID is used as the unique id of this changefeed in the
 	// metrics.MaxBehindNanos map.
 	metricsID int
+	// status exposes sf and what was emitted to the sink for
+	// crdb_internal.changefeed_status. Forwarding sf must go through it.
+	status *changefeedStatus
+	// initialScanOnly is set if the changefeed only outputs its initial scan,
+	// in which case it's finished once every tracked span is resolved at the
+	// statement time.
+	initialScanOnly bool
+	// schemaChangeStop, if set, is the schema change boundary at which a
+	// changefeed with `schema_change_policy=stop` stops once every tracked span
+	// is resolved at it.
+	schemaChangeStop hlc.Timestamp
 }
 
 var _ distsqlrun.Processor = &changeFrontier{}
@@ -399,6 +433,10 @@ func newChangeFrontierProcessor(
 		cf.freqEmitResolved = emitNoResolved
 	}
 
+	_, cf.initialScanOnly = cf.spec.Feed.Opts[optInitialScanOnly]
+	cf.status = makeChangefeedStatus(
+		spec.JobID, changefeedProcessorFrontier, spec.Feed.SinkURI, cf.sf)
+
 	var err error
 	if cf.encoder, err = getEncoder(spec.Feed.Opts); err != nil {
 		return nil, err
@@ -437,7 +475,7 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 	// runs. They're all stored as the `metric.Struct` interface because of
 	// dependency cycles.
 	cf.metrics = cf.flowCtx.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
-	cf.sink = makeMetricsSink(cf.metrics, cf.sink)
+	cf.sink = makeMetricsSink(cf.metrics, cf.status, cf.sink)
 	cf.sink = &errorWrapperSink{wrapped: cf.sink}
 
 	cf.highWaterAtStart = cf.spec.Feed.StatementTime
@@ -455,10 +493,7 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 		}
 	}
 
-	cf.metrics.mu.Lock()
-	cf.metricsID = cf.metrics.mu.id
-	cf.metrics.mu.id++
-	cf.metrics.mu.Unlock()
+	cf.metricsID = cf.metrics.registerStatus(cf.status)
 	// TODO(dan): It's very important that we de-register from the metric because
 	// if we orphan an entry in there, our monitoring will lie (say the changefeed
 	// is behind when it may not be). We call this in `close` but that doesn't
@@ -490,12 +525,14 @@ func (cf *changeFrontier) close() {
 }
 
 // closeMetrics de-registers from the progress registry that powers
-// `changefeed.max_behind_nanos`. This method is idempotent.
+// `changefeed.max_behind_nanos` and crdb_internal.changefeed_status. This
+// method is idempotent.
 func (cf *changeFrontier) closeMetrics() {
 	// Delete this feed from the MaxBehindNanos metric so it's no longer
 	// considered by the gauge.
 	cf.metrics.mu.Lock()
 	delete(cf.metrics.mu.resolved, cf.metricsID)
+	delete(cf.metrics.mu.statuses, cf.metricsID)
 	cf.metricsID = -1
 	cf.metrics.mu.Unlock()
 }
@@ -509,6 +546,13 @@ func (cf *changeFrontier) Next() (sqlbase.EncDatumRow, *distsqlpb.ProducerMetada
 			return cf.resolvedBuf.Pop(), nil
 		}
 
+		if cf.initialScanOnly && !cf.sf.Frontier().Less(cf.spec.Feed.StatementTime) {
+			// Every row of the initial scan has been emitted (and, with a sink,
+			// flushed), so the changefeed is done.
+			cf.MoveToDraining(nil /* err */)
+			break
+		}
+
 		row, meta := cf.input.Next()
 		if meta != nil {
 			if meta.Err != nil {
@@ -566,7 +610,13 @@ func (cf *changeFrontier) noteResolvedSpan(d sqlbase.EncDatum) error {
 		return nil
 	}
 
-	frontierChanged := cf.sf.Forward(resolved.Span, resolved.Timestamp)
+	if resolved.SchemaChangeStop {
+		if cf.schemaChangeStop.IsEmpty() || resolved.Timestamp.Less(cf.schemaChangeStop) {
+			cf.schemaChangeStop = resolved.Timestamp
+		}
+	}
+
+	frontierChanged := cf.status.forward(resolved.Span, resolved.Timestamp)
 	if frontierChanged {
 		newResolved := cf.sf.Frontier()
 		cf.metrics.mu.Lock()
@@ -588,6 +638,12 @@ func (cf *changeFrontier) noteResolvedSpan(d sqlbase.EncDatum) error {
 		}
 	}
 
+	// Everything up to the schema change has been emitted and checkpointed, so
+	// a changefeed created with a cursor at the high-water can pick up from here.
+	if !cf.schemaChangeStop.IsEmpty() && !cf.sf.Frontier().Less(cf.schemaChangeStop) {
+		return errors.Errorf(`schema change occurred at %s`, cf.schemaChangeStop)
+	}
+
 	// Potentially log the most behind span in the frontier for debugging. These
 	// two cluster setting values represent the target responsiveness of poller
 	// and range feed. The cluster setting for switching between poller and
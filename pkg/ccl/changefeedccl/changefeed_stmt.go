@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
@@ -46,29 +47,65 @@ func init() {
 
 type envelopeType string
 type formatType string
+type kafkaPartitionerType string
+type schemaChangeEventClass string
+type schemaChangePolicy string
 
 const (
 	optConfluentSchemaRegistry = `confluent_schema_registry`
 	optCursor                  = `cursor`
+	optDiff                    = `diff`
 	optEnvelope                = `envelope`
+	optExactlyOnce             = `exactly_once`
 	optFormat                  = `format`
+	optInitialScanOnly         = `initial_scan_only`
+	optKafkaPartitionColumn    = `kafka_partition_column`
+	optKafkaPartitioner        = `kafka_partitioner`
+	optKafkaSinkConfig         = `kafka_sink_config`
 	optKeyInValue              = `key_in_value`
+	optNoInitialScan           = `no_initial_scan`
 	optResolvedTimestamps      = `resolved`
+	optSchemaChangeEvents      = `schema_change_events`
+	optSchemaChangePolicy      = `schema_change_policy`
+	optSplitColumnFamilies     = `split_column_families`
 	optUpdatedTimestamps       = `updated`
+	optWebhookHeaders          = `webhook_headers`
 
 	optEnvelopeKeyOnly       envelopeType = `key_only`
 	optEnvelopeRow           envelopeType = `row`
 	optEnvelopeDeprecatedRow envelopeType = `deprecated_row`
 	optEnvelopeWrapped       envelopeType = `wrapped`
 
-	optFormatJSON formatType = `json`
-	optFormatAvro formatType = `experimental_avro`
+	optFormatJSON     formatType = `json`
+	optFormatAvro     formatType = `experimental_avro`
+	optFormatProtobuf formatType = `protobuf`
+
+	optKafkaPartitionerHash       kafkaPartitionerType = `hash`
+	optKafkaPartitionerRoundRobin kafkaPartitionerType = `round_robin`
+
+	// optSchemaChangeEventClassDefault only considers schema changes that
+	// backfill a column to be schema change events.
+	optSchemaChangeEventClassDefault schemaChangeEventClass = `default`
+	// optSchemaChangeEventClassColumnChange considers any addition, removal,
+	// rename or type change of a column to be a schema change event.
+	optSchemaChangeEventClassColumnChange schemaChangeEventClass = `column_changes`
+
+	// optSchemaChangePolicyBackfill emits every row again after a schema change
+	// event.
+	optSchemaChangePolicyBackfill schemaChangePolicy = `backfill`
+	// optSchemaChangePolicyNoBackfill continues past a schema change event
+	// without emitting the rows again.
+	optSchemaChangePolicyNoBackfill schemaChangePolicy = `nobackfill`
+	// optSchemaChangePolicyStop stops the changefeed with an error once
+	// everything up to a schema change event has been emitted.
+	optSchemaChangePolicyStop schemaChangePolicy = `stop`
 
 	sinkParamCACert           = `ca_cert`
 	sinkParamFileSize         = `file_size`
 	sinkParamSchemaTopic      = `schema_topic`
 	sinkParamTLSEnabled       = `tls_enabled`
 	sinkParamTopicPrefix      = `topic_prefix`
+	sinkParamTopicTemplate    = `topic_template`
 	sinkSchemeBuffer          = ``
 	sinkSchemeExperimentalSQL = `experimental-sql`
 	sinkSchemeKafka           = `kafka`
@@ -76,16 +113,38 @@ const (
 	sinkParamSASLHandshake    = `sasl_handshake`
 	sinkParamSASLUser         = `sasl_user`
 	sinkParamSASLPassword     = `sasl_password`
+
+	sinkSchemeWebhookHTTPS = `webhook-https`
+	sinkParamBatchSize     = `batch_size`
+	sinkParamClientCert    = `client_cert`
+	sinkParamClientKey     = `client_key`
 )
 
 var changefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	optConfluentSchemaRegistry: sql.KVStringOptRequireValue,
 	optCursor:                  sql.KVStringOptRequireValue,
+	optDiff:                    sql.KVStringOptRequireNoValue,
 	optEnvelope:                sql.KVStringOptRequireValue,
+	optExactlyOnce:             sql.KVStringOptRequireNoValue,
 	optFormat:                  sql.KVStringOptRequireValue,
+	optInitialScanOnly:         sql.KVStringOptRequireNoValue,
+	optKafkaPartitionColumn:    sql.KVStringOptRequireValue,
+	optKafkaPartitioner:        sql.KVStringOptRequireValue,
+	optKafkaSinkConfig:         sql.KVStringOptRequireValue,
 	optKeyInValue:              sql.KVStringOptRequireNoValue,
+	optNoInitialScan:           sql.KVStringOptRequireNoValue,
 	optResolvedTimestamps:      sql.KVStringOptAny,
+	optSchemaChangeEvents:      sql.KVStringOptRequireValue,
+	optSchemaChangePolicy:      sql.KVStringOptRequireValue,
+	optSplitColumnFamilies:     sql.KVStringOptRequireNoValue,
 	optUpdatedTimestamps:       sql.KVStringOptRequireNoValue,
+	optWebhookHeaders:          sql.KVStringOptRequireValue,
+}
+
+// changefeedOptionSensitive are the options whose values may contain secrets,
+// which are redacted from the job description.
+var changefeedOptionSensitive = map[string]struct{}{
+	optWebhookHeaders: {},
 }
 
 // changefeedPlanHook implements sql.PlanHookFn.
@@ -196,15 +255,32 @@ func changefeedPlanHook(
 		if err != nil {
 			return err
 		}
+		databaseNames := make(map[sqlbase.ID]string)
+		for _, desc := range targetDescs {
+			if dbDesc := desc.GetDatabase(); dbDesc != nil {
+				databaseNames[dbDesc.ID] = dbDesc.Name
+			}
+		}
 		targets := make(jobspb.ChangefeedTargets, len(targetDescs))
 		for _, desc := range targetDescs {
 			if tableDesc := desc.GetTable(); tableDesc != nil {
 				targets[tableDesc.ID] = jobspb.ChangefeedTarget{
-					StatementTimeName: tableDesc.Name,
+					StatementTimeName:         tableDesc.Name,
+					StatementTimeDatabaseName: databaseNames[tableDesc.ParentID],
 				}
 				if err := validateChangefeedTable(targets, tableDesc); err != nil {
 					return err
 				}
+				if col, ok := opts[optKafkaPartitionColumn]; ok {
+					if _, err := partitionColumnIdx(tableDesc, col); err != nil {
+						return err
+					}
+				}
+				if changefeedStmt.Select != nil {
+					if _, err := makeProjection(changefeedStmt.Select, tableDesc); err != nil {
+						return err
+					}
+				}
 			}
 		}
 
@@ -214,6 +290,9 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if changefeedStmt.Select != nil {
+			details.Select = tree.AsString(changefeedStmt.Select)
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{HighWater: &initialHighWater},
 			Details: &jobspb.Progress_Changefeed{
@@ -344,9 +423,13 @@ func changefeedJobDescription(
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
 		SinkURI: tree.NewDString(cleanedSinkURI),
+		Select:  changefeed.Select,
 	}
 	for k, v := range opts {
 		opt := tree.KVOption{Key: tree.Name(k)}
+		if _, ok := changefeedOptionSensitive[k]; ok {
+			v = `redacted`
+		}
 		if len(v) > 0 {
 			opt.Value = tree.NewDString(v)
 		}
@@ -386,17 +469,78 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		return jobspb.ChangefeedDetails{}, errors.Errorf(
 			`unknown %s: %s`, optEnvelope, details.Opts[optEnvelope])
 	}
+	if _, ok := details.Opts[optDiff]; ok {
+		if envelopeType(details.Opts[optEnvelope]) != optEnvelopeWrapped {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s is only usable with %s=%s`, optDiff, optEnvelope, optEnvelopeWrapped)
+		}
+	}
+
+	// Exactly-once delivery needs a transactional kafka producer, whose
+	// transactions commit along with the changefeed's checkpoints. The vendored
+	// sarama doesn't support transactions.
+	if _, ok := details.Opts[optExactlyOnce]; ok {
+		return jobspb.ChangefeedDetails{}, unimplemented.Newf(`changefeed-exactly-once`,
+			`%s is not supported`, optExactlyOnce)
+	}
+
+	if _, ok := details.Opts[optInitialScanOnly]; ok {
+		for _, opt := range []string{optNoInitialScan, optCursor} {
+			if _, ok := details.Opts[opt]; ok {
+				return jobspb.ChangefeedDetails{}, errors.Errorf(
+					`%s cannot be used with %s`, optInitialScanOnly, opt)
+			}
+		}
+	}
+
+	if _, ok := details.Opts[optSplitColumnFamilies]; ok && details.Select != `` {
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`%s cannot be used with CREATE CHANGEFEED ... AS SELECT`, optSplitColumnFamilies)
+	}
+
+	switch kafkaPartitionerType(details.Opts[optKafkaPartitioner]) {
+	case ``, optKafkaPartitionerHash:
+	case optKafkaPartitionerRoundRobin:
+		if _, ok := details.Opts[optKafkaPartitionColumn]; ok {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(`%s cannot be used with %s=%s`,
+				optKafkaPartitionColumn, optKafkaPartitioner, optKafkaPartitionerRoundRobin)
+		}
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optKafkaPartitioner, details.Opts[optKafkaPartitioner])
+	}
 
 	switch formatType(details.Opts[optFormat]) {
 	case ``, optFormatJSON:
 		details.Opts[optFormat] = string(optFormatJSON)
-	case optFormatAvro:
+	case optFormatAvro, optFormatProtobuf:
 		// No-op.
 	default:
 		return jobspb.ChangefeedDetails{}, errors.Errorf(
 			`unknown %s: %s`, optFormat, details.Opts[optFormat])
 	}
 
+	// The schema change options are filled in even when unspecified, so that
+	// the chosen behavior is recorded in the job details.
+	switch schemaChangeEventClass(details.Opts[optSchemaChangeEvents]) {
+	case ``, optSchemaChangeEventClassDefault:
+		details.Opts[optSchemaChangeEvents] = string(optSchemaChangeEventClassDefault)
+	case optSchemaChangeEventClassColumnChange:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangeEvents, details.Opts[optSchemaChangeEvents])
+	}
+	switch schemaChangePolicy(details.Opts[optSchemaChangePolicy]) {
+	case ``, optSchemaChangePolicyBackfill:
+		details.Opts[optSchemaChangePolicy] = string(optSchemaChangePolicyBackfill)
+	case optSchemaChangePolicyNoBackfill, optSchemaChangePolicyStop:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangePolicy, details.Opts[optSchemaChangePolicy])
+	}
+
 	return details, nil
 }
 
@@ -425,12 +569,6 @@ func validateChangefeedTable(
 	if tableDesc.IsSequence() {
 		return errors.Errorf(`CHANGEFEED cannot target sequences: %s`, tableDesc.Name)
 	}
-	if len(tableDesc.Families) != 1 {
-		return errors.Errorf(
-			`CHANGEFEEDs are currently supported on tables with exactly 1 column family: %s has %d`,
-			tableDesc.Name, len(tableDesc.Families))
-	}
-
 	if tableDesc.State == sqlbase.TableDescriptor_DROP {
 		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
 	}
@@ -127,6 +127,159 @@ func TestChangefeedEnvelope(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'updated')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH diff`)
+		defer closeFeed(t, foo)
+
+		// The initial scan has no previous values.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "updated"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": null}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'c'), (1, 'd')`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "c"}, "before": {"a": 0, "b": "updated"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "d"}, "before": {"a": 1, "b": "a"}}`,
+		})
+
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "d"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'e')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "e"}, "before": null}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedProjection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'zero', 0), (1, 'one', 10)`)
+
+		t.Run(`projection`, func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED AS SELECT a, upper(b) AS b, c + 1 FROM foo`)
+			defer closeFeed(t, foo)
+
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": {"a": 0, "b": "ZERO", "c + 1": 1}}`,
+				`foo: [1]->{"after": {"a": 1, "b": "ONE", "c + 1": 11}}`,
+			})
+			sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'uno', 100)`)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "UNO", "c + 1": 101}}`,
+			})
+		})
+
+		t.Run(`filter`, func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED WITH diff AS SELECT * FROM foo WHERE c > 5`)
+			defer closeFeed(t, foo)
+
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "uno", "c": 100}, "before": null}`,
+			})
+			sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'two', 2), (3, 'three', 30)`)
+			sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'zero', 50)`)
+			assertPayloads(t, foo, []string{
+				`foo: [3]->{"after": {"a": 3, "b": "three", "c": 30}, "before": null}`,
+				`foo: [0]->{"after": {"a": 0, "b": "zero", "c": 50}, "before": {"a": 0, "b": "zero", "c": 0}}`,
+			})
+			// Deletes are emitted even if the deleted row didn't pass the filter.
+			sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+			assertPayloads(t, foo, []string{
+				`foo: [2]->{"after": null, "before": {"a": 2, "b": "two", "c": 2}}`,
+			})
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedInitialScanOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_only`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+
+		// The changefeed finishes after the initial scan, so the new row is
+		// never emitted.
+		if e, ok := foo.(*cdctest.TableFeed); ok {
+			testutils.SucceedsSoon(t, func() error {
+				var status string
+				sqlDB.QueryRow(t, `SELECT status FROM [SHOW JOBS] WHERE job_id = $1`, e.JobID).Scan(&status)
+				if jobs.Status(status) != jobs.StatusSucceeded {
+					return errors.Errorf(`expected job to succeed, got %s`, status)
+				}
+				return nil
+			})
+		} else {
+			m, err := foo.Next()
+			require.NoError(t, err)
+			require.Nil(t, m)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedNoInitialScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH no_initial_scan`)
+		defer closeFeed(t, foo)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'd')`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "d"}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedMultiTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -687,6 +840,59 @@ func TestChangefeedAfterSchemaChangeBackfill(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedSchemaChangePolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+
+		t.Run(`nobackfill`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE policy_nobackfill (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO policy_nobackfill VALUES (1)`)
+			noBackfill := feed(t, f, `CREATE CHANGEFEED FOR policy_nobackfill `+
+				`WITH schema_change_events='column_changes', schema_change_policy='nobackfill'`)
+			defer closeFeed(t, noBackfill)
+			assertPayloads(t, noBackfill, []string{
+				`policy_nobackfill: [1]->{"after": {"a": 1}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE policy_nobackfill ADD COLUMN b STRING`)
+			sqlDB.Exec(t, `INSERT INTO policy_nobackfill VALUES (2, 'two')`)
+			// Row 1 is not re-emitted with the new column.
+			assertPayloads(t, noBackfill, []string{
+				`policy_nobackfill: [2]->{"after": {"a": 2, "b": "two"}}`,
+			})
+		})
+
+		t.Run(`stop`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE policy_stop (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO policy_stop VALUES (1)`)
+			stop := feed(t, f, `CREATE CHANGEFEED FOR policy_stop `+
+				`WITH schema_change_events='column_changes', schema_change_policy='stop'`)
+			defer closeFeed(t, stop)
+			assertPayloads(t, stop, []string{
+				`policy_stop: [1]->{"after": {"a": 1}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE policy_stop ADD COLUMN b STRING`)
+			sqlDB.Exec(t, `INSERT INTO policy_stop VALUES (2, 'two')`)
+			for {
+				m, err := stop.Next()
+				if err != nil {
+					if !testutils.IsError(err, `schema change occurred at`) {
+						t.Fatalf(`expected "schema change occurred at" error got: %+v`, err)
+					}
+					break
+				}
+				if len(m.Key) > 0 && string(m.Key) != `[1]` {
+					t.Fatalf(`unexpected row emitted after schema change: %s->%s`, m.Key, m.Value)
+				}
+			}
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedInterleaved(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -744,12 +950,59 @@ func TestChangefeedColumnFamily(t *testing.T) {
 		sqlDB := sqlutils.MakeSQLRunner(db)
 
 		// Table with 2 column families.
-		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, FAMILY (a), FAMILY (b))`)
-		if strings.Contains(t.Name(), `enterprise`) {
-			sqlDB.ExpectErr(t, `exactly 1 column family`, `CREATE CHANGEFEED FOR foo`)
-		} else {
-			sqlDB.ExpectErr(t, `exactly 1 column family`, `EXPERIMENTAL CHANGEFEED FOR foo`)
-		}
+		sqlDB.Exec(t, `CREATE TABLE foo (
+			a INT PRIMARY KEY, b STRING, c STRING, FAMILY f_ab (a, b), FAMILY f_c (c)
+		)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'dog', 'cat')`)
+
+		t.Run(`full rows`, func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH diff`)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": {"a": 0, "b": "dog", "c": "cat"}, "before": null}`,
+			})
+
+			// The values of families which were not written are included.
+			sqlDB.Exec(t, `UPDATE foo SET c = 'mouse' WHERE a = 0`)
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": {"a": 0, "b": "dog", "c": "mouse"}, ` +
+					`"before": {"a": 0, "b": "dog", "c": "cat"}}`,
+			})
+			// Setting every column of a family to NULL deletes its kv.
+			sqlDB.Exec(t, `UPDATE foo SET c = NULL WHERE a = 0`)
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": {"a": 0, "b": "dog", "c": null}, ` +
+					`"before": {"a": 0, "b": "dog", "c": "mouse"}}`,
+			})
+			// The families written by a transaction emit the row once, even when
+			// other rows are written in between.
+			sqlDB.Exec(t, `BEGIN; UPDATE foo SET b = 'wolf' WHERE a = 0; `+
+				`INSERT INTO foo VALUES (2, 'cow', 'pig'); UPDATE foo SET c = 'rat' WHERE a = 0; COMMIT`)
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": {"a": 0, "b": "wolf", "c": "rat"}, ` +
+					`"before": {"a": 0, "b": "dog", "c": null}}`,
+				`foo: [2]->{"after": {"a": 2, "b": "cow", "c": "pig"}, "before": null}`,
+			})
+			sqlDB.Exec(t, `DELETE FROM foo WHERE a IN (0, 2)`)
+			assertPayloads(t, foo, []string{
+				`foo: [0]->{"after": null, "before": {"a": 0, "b": "wolf", "c": "rat"}}`,
+				`foo: [2]->{"after": null, "before": {"a": 2, "b": "cow", "c": "pig"}}`,
+			})
+		})
+
+		t.Run(`split_column_families`, func(t *testing.T) {
+			sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'dog', 'cat')`)
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH split_column_families`)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "dog"}}`,
+				`foo: [1]->{"after": {"a": 1, "c": "cat"}}`,
+			})
+			sqlDB.Exec(t, `UPDATE foo SET c = 'mouse' WHERE a = 1`)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "c": "mouse"}}`,
+			})
+		})
 
 		// Table with a second column family added after the changefeed starts.
 		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, FAMILY f_a (a))`)
@@ -760,10 +1013,10 @@ func TestChangefeedColumnFamily(t *testing.T) {
 			`bar: [0]->{"after": {"a": 0}}`,
 		})
 		sqlDB.Exec(t, `ALTER TABLE bar ADD COLUMN b STRING CREATE FAMILY f_b`)
-		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
-		if _, err := bar.Next(); !testutils.IsError(err, `exactly 1 column family`) {
-			t.Errorf(`expected "exactly 1 column family" error got: %+v`, err)
-		}
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1, 'one')`)
+		assertPayloads(t, bar, []string{
+			`bar: [1]->{"after": {"a": 1, "b": "one"}}`,
+		})
 	}
 
 	t.Run(`sinkless`, sinklessTest(testFn))
@@ -1024,6 +1277,57 @@ func TestChangefeedMonitoring(t *testing.T) {
 	})
 }
 
+func TestChangefeedStatus(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{`foo: [1]->{"after": {"a": 1}}`})
+		expectResolvedTimestamp(t, foo)
+
+		jobIDPredicate := `job_id IS NULL`
+		if e, ok := foo.(*cdctest.TableFeed); ok {
+			jobIDPredicate = fmt.Sprintf(`job_id = %d`, e.JobID)
+		}
+		testutils.SucceedsSoon(t, func() error {
+			var aggregatorMessages, unresolved int
+			sqlDB.QueryRow(t, `SELECT
+				COALESCE(sum(emitted_messages) FILTER (WHERE processor = 'aggregator'), 0),
+				count(*) FILTER (WHERE resolved IS NULL)
+				FROM crdb_internal.changefeed_status WHERE `+jobIDPredicate,
+			).Scan(&aggregatorMessages, &unresolved)
+			if aggregatorMessages < 1 {
+				return errors.Errorf(`expected >= 1 emitted messages got %d`, aggregatorMessages)
+			}
+			if unresolved != 0 {
+				return errors.Errorf(`expected all spans to be resolved, %d are not`, unresolved)
+			}
+			return nil
+		})
+		// Each processor has exactly one span marked as the slowest.
+		sqlDB.CheckQueryResults(t, `SELECT processor, count(*) FILTER (WHERE slowest)
+			FROM crdb_internal.changefeed_status WHERE `+jobIDPredicate+`
+			GROUP BY processor ORDER BY processor`,
+			[][]string{{`aggregator`, `1`}, {`frontier`, `1`}},
+		)
+
+		if e, ok := foo.(*cdctest.TableFeed); ok {
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT status, high_water_timestamp IS NOT NULL
+				FROM [SHOW CHANGEFEED JOBS] WHERE job_id = %d`, e.JobID),
+				[][]string{{`running`, `true`}},
+			)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedRetryableError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer utilccl.TestingEnableEnterprise()()
@@ -1284,6 +1588,18 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `unknown envelope: nope`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `exactly_once is not supported`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH exactly_once`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown schema_change_events: nope`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH schema_change_events=nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown schema_change_policy: nope`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH schema_change_policy=nope`,
+	)
 	sqlDB.ExpectErr(
 		t, `negative durations are not accepted: resolved='-1s'`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH resolved='-1s'`,
@@ -1420,6 +1736,37 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `sasl_enabled must be enabled if a SASL password is provided`,
 		`CREATE CHANGEFEED FOR foo INTO $1`, `kafka://nope/?sasl_password=a`,
 	)
+	sqlDB.ExpectErr(
+		t, `param topic_template must only use the placeholders {database} and {table}: {schema}`,
+		`CREATE CHANGEFEED FOR foo INTO $1`, `kafka://nope/?topic_template={schema}`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown kafka_partitioner: nope`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_partitioner=nope`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `kafka_partition_column cannot be used with kafka_partitioner=round_robin`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_partitioner=round_robin, kafka_partition_column=a`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `kafka_partition_column must be a primary key column of foo: b`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_partition_column=b`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `kafka_partition_column: column "c" does not exist`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_partition_column=c`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `option kafka_sink_config has an unknown Compression: BZIP`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_sink_config=$2`,
+		`kafka://nope`, `{"Compression": "BZIP"}`,
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option kafka_sink_config`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_sink_config=$2`,
+		`experimental-nodelocal:///bar`, `{"RequiredAcks": "ALL"}`,
+	)
 
 	// The avro format doesn't support key_in_value yet.
 	sqlDB.ExpectErr(
@@ -1449,6 +1796,59 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `key_in_value is only usable with envelope=wrapped`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH key_in_value, envelope='row'`, `kafka://nope`,
 	)
+
+	// WITH diff requires envelope=wrapped
+	sqlDB.ExpectErr(
+		t, `diff is only usable with envelope=wrapped`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, envelope='key_only'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `diff is only usable with envelope=wrapped`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, envelope='row'`, `kafka://nope`,
+	)
+
+	// initial_scan_only can't be combined with options that skip the initial
+	// scan.
+	sqlDB.ExpectErr(
+		t, `initial_scan_only cannot be used with no_initial_scan`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH initial_scan_only, no_initial_scan`, `kafka://nope`,
+	)
+	var ts string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+	sqlDB.ExpectErr(
+		t, `initial_scan_only cannot be used with cursor`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH initial_scan_only, cursor = $2`, `kafka://nope`, ts,
+	)
+
+	// Only deterministic, row-local expressions are allowed in AS SELECT.
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT nope FROM foo`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `impure functions are not allowed in CHANGEFEED`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT a, random() FROM foo`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `aggregate functions are not allowed in CHANGEFEED`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT max(a) FROM foo`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `subqueries are not allowed in CHANGEFEED`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT a FROM foo WHERE a IN (SELECT 1)`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `argument of WHERE must be type bool, not type int`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT a FROM foo WHERE a`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED selects column "a" more than once`,
+		`CREATE CHANGEFEED INTO $1 AS SELECT a, a FROM foo`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `split_column_families cannot be used with CREATE CHANGEFEED ... AS SELECT`,
+		`CREATE CHANGEFEED INTO $1 WITH split_column_families AS SELECT a FROM foo`, `kafka://nope`,
+	)
 }
 
 func TestChangefeedPermissions(t *testing.T) {
@@ -1517,6 +1917,24 @@ func TestChangefeedDescription(t *testing.T) {
 		if description != expected {
 			t.Errorf(`got "%s" expected "%s"`, description, expected)
 		}
+
+		// Secrets also get removed from the options.
+		srv := makeWebhookTestServer(t)
+		defer srv.Close()
+		sqlDB.QueryRow(t,
+			`CREATE CHANGEFEED FOR foo INTO $1 WITH webhook_headers = $2`,
+			srv.sinkURI(url.Values{}), `{"Authorization": "Bearer secret"}`,
+		).Scan(&jobID)
+		defer sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+
+		sqlDB.QueryRow(t,
+			`SELECT description FROM [SHOW JOBS] WHERE job_id = $1`, jobID,
+		).Scan(&description)
+		expected = `CREATE CHANGEFEED FOR TABLE foo INTO 'webhook-` + srv.URL +
+			`' WITH webhook_headers = 'redacted'`
+		if description != expected {
+			t.Errorf(`got "%s" expected "%s"`, description, expected)
+		}
 	}
 
 	// Only the enterprise version uses jobs.
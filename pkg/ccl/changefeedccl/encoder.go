@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,12 @@ const (
 	confluentSubjectSuffixKey    = `-key`
 	confluentSubjectSuffixValue  = `-value`
 	confluentAvroWireFormatMagic = byte(0)
+
+	// The schema registry assumes avro if the type of a schema isn't
+	// specified, so it's left empty for avro schemas for compatibility with
+	// older registries.
+	confluentSchemaTypeAvro     = ``
+	confluentSchemaTypeProtobuf = `PROTOBUF`
 )
 
 // encodeRow holds all the pieces necessary to encode a row change into a key or
@@ -47,6 +55,48 @@ type encodeRow struct {
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `updated`.
 	tableDesc *sqlbase.TableDescriptor
+	// prevDatums is the old value of a changed table row. It is only set if
+	// the changefeed was started with the diff option.
+	prevDatums sqlbase.EncDatumRow
+	// prevDeleted is true if the row didn't exist before this change. In this
+	// case, prevDatums should be ignored.
+	prevDeleted bool
+	// prevTableDesc is a TableDescriptor for the table containing `prevDatums`.
+	// It's valid for interpreting the row before `updated`.
+	prevTableDesc *sqlbase.TableDescriptor
+	// family is set if the changefeed splits column families. In this case,
+	// only the primary key columns and the columns of this family are set in
+	// `datums` and `prevDatums`, and only they are encoded in values.
+	family *sqlbase.ColumnFamilyDescriptor
+	// projected is set if the changefeed was created with CREATE CHANGEFEED
+	// ... AS SELECT. It is the result of evaluating the SELECT clause against
+	// this row and `prevDatums`, and values are encoded from it instead of from
+	// this row. Keys are still encoded from `datums`.
+	projected *encodeRow
+}
+
+// valueRow returns the row that values are encoded from.
+func (r encodeRow) valueRow() encodeRow {
+	if r.projected != nil {
+		return *r.projected
+	}
+	return r
+}
+
+// valueColumnIDs returns the IDs of the columns encoded in the values of rows
+// of the given column family: the primary key columns and the columns of the
+// family.
+func valueColumnIDs(
+	tableDesc *sqlbase.TableDescriptor, family *sqlbase.ColumnFamilyDescriptor,
+) map[sqlbase.ColumnID]struct{} {
+	colIDs := make(map[sqlbase.ColumnID]struct{})
+	for _, colID := range tableDesc.PrimaryIndex.ColumnIDs {
+		colIDs[colID] = struct{}{}
+	}
+	for _, colID := range family.ColumnIDs {
+		colIDs[colID] = struct{}{}
+	}
+	return colIDs
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -74,6 +124,8 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 		return makeJSONEncoder(opts)
 	case optFormatAvro:
 		return newConfluentAvroEncoder(opts)
+	case optFormatProtobuf:
+		return newProtobufEncoder(opts)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, optFormat, opts[optFormat])
 	}
@@ -84,7 +136,7 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
-	updatedField, wrapped, keyOnly, keyInValue bool
+	updatedField, beforeField, wrapped, keyOnly, keyInValue bool
 
 	alloc sqlbase.DatumAlloc
 	buf   bytes.Buffer
@@ -98,6 +150,11 @@ func makeJSONEncoder(opts map[string]string) (*jsonEncoder, error) {
 		wrapped: envelopeType(opts[optEnvelope]) == optEnvelopeWrapped,
 	}
 	_, e.updatedField = opts[optUpdatedTimestamps]
+	_, e.beforeField = opts[optDiff]
+	if e.beforeField && !e.wrapped {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			optDiff, optEnvelope, optEnvelopeWrapped)
+	}
 	_, e.keyInValue = opts[optKeyInValue]
 	if e.keyInValue && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
@@ -148,21 +205,22 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		return nil, nil
 	}
 
+	valueRow := row.valueRow()
 	var after map[string]interface{}
-	if !row.deleted {
-		columns := row.tableDesc.Columns
-		after = make(map[string]interface{}, len(columns))
-		for i := range columns {
-			col := &columns[i]
-			datum := row.datums[i]
-			if err := datum.EnsureDecoded(&col.Type, &e.alloc); err != nil {
-				return nil, err
-			}
-			var err error
-			after[col.Name], err = tree.AsJSON(datum.Datum)
-			if err != nil {
-				return nil, err
-			}
+	if !valueRow.deleted {
+		var err error
+		after, err = e.encodeRowRaw(valueRow.tableDesc, valueRow.family, valueRow.datums)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var before map[string]interface{}
+	if e.beforeField && valueRow.prevDatums != nil && !valueRow.prevDeleted {
+		var err error
+		before, err = e.encodeRowRaw(valueRow.prevTableDesc, valueRow.family, valueRow.prevDatums)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -173,6 +231,13 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		} else {
 			jsonEntries = map[string]interface{}{`after`: nil}
 		}
+		if e.beforeField {
+			if before != nil {
+				jsonEntries[`before`] = before
+			} else {
+				jsonEntries[`before`] = nil
+			}
+		}
 		if e.keyInValue {
 			keyEntries, err := e.encodeKeyRaw(row)
 			if err != nil {
@@ -204,6 +269,38 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 	return e.buf.Bytes(), nil
 }
 
+// encodeRowRaw returns a map of every column name in the given table to its
+// value in datums. If family is set, only the primary key columns and the
+// columns of that family are included.
+func (e *jsonEncoder) encodeRowRaw(
+	tableDesc *sqlbase.TableDescriptor,
+	family *sqlbase.ColumnFamilyDescriptor,
+	datums sqlbase.EncDatumRow,
+) (map[string]interface{}, error) {
+	var colIDs map[sqlbase.ColumnID]struct{}
+	if family != nil {
+		colIDs = valueColumnIDs(tableDesc, family)
+	}
+	columns := tableDesc.Columns
+	jsonEntries := make(map[string]interface{}, len(columns))
+	for i := range columns {
+		col := &columns[i]
+		if _, ok := colIDs[col.ID]; !ok && colIDs != nil {
+			continue
+		}
+		datum := datums[i]
+		if err := datum.EnsureDecoded(&col.Type, &e.alloc); err != nil {
+			return nil, err
+		}
+		var err error
+		jsonEntries[col.Name], err = tree.AsJSON(datum.Datum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jsonEntries, nil
+}
+
 // EncodeResolvedTimestamp implements the Encoder interface.
 func (e *jsonEncoder) EncodeResolvedTimestamp(_ string, resolved hlc.Timestamp) ([]byte, error) {
 	meta := map[string]interface{}{
@@ -224,16 +321,24 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(_ string, resolved hlc.Timestamp)
 // JSON format. Keys are the primary key columns in a record. Values are all
 // columns in a record.
 type confluentAvroEncoder struct {
-	registryURL           string
-	updatedField, keyOnly bool
+	registryURL                        string
+	updatedField, beforeField, keyOnly bool
 
 	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
-	valueCache    map[tableIDAndVersion]confluentRegisteredEnvelopeSchema
+	valueCache    map[valueCacheKey]confluentRegisteredEnvelopeSchema
 	resolvedCache map[string]confluentRegisteredEnvelopeSchema
 }
 
 type tableIDAndVersion uint64
 
+// valueCacheKey identifies the table versions used to encode the after and
+// before rows of a value and, if the changefeed splits column families, the
+// column family of the value. Encoders with schemas cache them by it.
+type valueCacheKey struct {
+	after, before tableIDAndVersion
+	familyID      sqlbase.FamilyID
+}
+
 func makeTableIDAndVersion(id sqlbase.ID, version sqlbase.DescriptorVersion) tableIDAndVersion {
 	return tableIDAndVersion(id)<<32 + tableIDAndVersion(version)
 }
@@ -262,6 +367,11 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 			optEnvelope, opts[optEnvelope], optFormat, optFormatAvro)
 	}
 	_, e.updatedField = opts[optUpdatedTimestamps]
+	_, e.beforeField = opts[optDiff]
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			optDiff, optEnvelope, optEnvelopeWrapped)
+	}
 
 	if _, ok := opts[optKeyInValue]; ok {
 		return nil, errors.Errorf(`%s is not supported with %s=%s`,
@@ -274,7 +384,7 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 	}
 
 	e.keyCache = make(map[tableIDAndVersion]confluentRegisteredKeySchema)
-	e.valueCache = make(map[tableIDAndVersion]confluentRegisteredEnvelopeSchema)
+	e.valueCache = make(map[valueCacheKey]confluentRegisteredEnvelopeSchema)
 	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
 	return e, nil
 }
@@ -315,17 +425,50 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 	if e.keyOnly {
 		return nil, nil
 	}
+	row = row.valueRow()
 
-	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	// The before row is encoded with the table version it was read with, which
+	// may differ from the version of the after row.
+	prevTableDesc := row.tableDesc
+	if e.beforeField && row.prevTableDesc != nil {
+		prevTableDesc = row.prevTableDesc
+	}
+	cacheKey := valueCacheKey{
+		after: makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version),
+	}
+	if e.beforeField {
+		cacheKey.before = makeTableIDAndVersion(prevTableDesc.ID, prevTableDesc.Version)
+	}
+	if row.family != nil {
+		cacheKey.familyID = row.family.ID
+	}
 	registered, ok := e.valueCache[cacheKey]
 	if !ok {
-		afterDataSchema, err := tableToAvroSchema(row.tableDesc)
+		var beforeDataSchema *avroDataRecord
+		if e.beforeField {
+			prevFamily := row.family
+			var err error
+			if prevFamily != nil {
+				if prevFamily, err = prevTableDesc.FindFamilyByID(row.family.ID); err != nil {
+					return nil, err
+				}
+			}
+			beforeDataSchema, err = familyToAvroSchema(prevTableDesc, prevFamily, `before`)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		afterDataSchema, err := familyToAvroSchema(row.tableDesc, row.family, avroSchemaNoSuffix)
 		if err != nil {
 			return nil, err
 		}
 
-		opts := avroEnvelopeOpts{afterField: true, updatedField: e.updatedField}
-		registered.schema, err = envelopeToAvroSchema(row.tableDesc.Name, opts, afterDataSchema)
+		opts := avroEnvelopeOpts{
+			afterField: true, beforeField: e.beforeField, updatedField: e.updatedField,
+		}
+		registered.schema, err = envelopeToAvroSchema(
+			row.tableDesc.Name, opts, beforeDataSchema, afterDataSchema)
 		if err != nil {
 			return nil, err
 		}
@@ -346,9 +489,12 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 			`updated`: row.updated,
 		}
 	}
-	var datums sqlbase.EncDatumRow
+	var beforeDatums, afterDatums sqlbase.EncDatumRow
+	if row.prevDatums != nil && !row.prevDeleted {
+		beforeDatums = row.prevDatums
+	}
 	if !row.deleted {
-		datums = row.datums
+		afterDatums = row.datums
 	}
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
 	header := []byte{
@@ -356,7 +502,7 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, datums)
+	return registered.schema.BinaryFromRow(header, meta, beforeDatums, afterDatums)
 }
 
 // EncodeResolvedTimestamp implements the Encoder interface.
@@ -367,7 +513,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	if !ok {
 		opts := avroEnvelopeOpts{resolvedField: true}
 		var err error
-		registered.schema, err = envelopeToAvroSchema(topic, opts, nil /* after */)
+		registered.schema, err = envelopeToAvroSchema(topic, opts, nil /* before */, nil /* after */)
 		if err != nil {
 			return nil, err
 		}
@@ -394,29 +540,38 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, nil /* row */)
+	return registered.schema.BinaryFromRow(header, meta, nil /* beforeRow */, nil /* afterRow */)
 }
 
 func (e *confluentAvroEncoder) register(schema *avroRecord, subject string) (int32, error) {
+	return registerConfluentSchema(e.registryURL, confluentSchemaTypeAvro, schema.codec.Schema(), subject)
+}
+
+// registerConfluentSchema registers a schema of the given type under the given
+// subject with the schema registry at registryURL, and returns the ID of the
+// schema.
+func registerConfluentSchema(
+	registryURL, schemaType, schemaStr, subject string,
+) (int32, error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType,omitempty"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
 	}
 
-	url, err := url.Parse(e.registryURL)
+	url, err := url.Parse(registryURL)
 	if err != nil {
 		return 0, err
 	}
 	url.Path = filepath.Join(url.EscapedPath(), `subjects`, subject, `versions`)
 
-	schemaStr := schema.codec.Schema()
 	if log.V(1) {
-		log.Infof(context.TODO(), "registering avro schema %s %s", url, schemaStr)
+		log.Infof(context.TODO(), "registering schema %s %s", url, schemaStr)
 	}
 
-	req := confluentSchemaVersionRequest{Schema: schemaStr}
+	req := confluentSchemaVersionRequest{Schema: schemaStr, SchemaType: schemaType}
 	var buf bytes.Buffer
 	if err := gojson.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -438,3 +593,266 @@ func (e *confluentAvroEncoder) register(schema *avroRecord, subject string) (int
 
 	return res.ID, nil
 }
+
+// protobufEncoder encodes changefeed entries as protobuf messages. Keys are
+// messages with a field for each primary key column. Values are envelope
+// messages with the row after and, with the diff option, before the change.
+//
+// If a schema registry is configured, the `.proto` schema of each message type
+// is registered with it and messages use the Confluent wire format, which
+// references the schema by ID. Otherwise, each message is wrapped in a
+// self-describing message that carries the descriptor of its type:
+//
+//	message SelfDescribingMessage {
+//	  optional google.protobuf.FileDescriptorSet descriptor_set = 1;
+//	  optional google.protobuf.Any message = 2;
+//	}
+type protobufEncoder struct {
+	registryURL                        string
+	updatedField, beforeField, keyOnly bool
+
+	keyCache      map[tableIDAndVersion]protobufRegisteredKeySchema
+	valueCache    map[valueCacheKey]protobufRegisteredEnvelopeSchema
+	resolvedCache map[string]protobufRegisteredEnvelopeSchema
+}
+
+// protobufSchema is what's needed to frame messages of one protobuf message
+// type. Which fields are set depends on whether the encoder uses a schema
+// registry.
+type protobufSchema struct {
+	// registryID is the ID of the schema in the schema registry.
+	registryID int32
+	// descriptorSet is the serialized FileDescriptorSet of the schema and
+	// typeURL identifies the message type in it.
+	descriptorSet []byte
+	typeURL       string
+}
+
+type protobufRegisteredKeySchema struct {
+	message *protobufDataMessage
+	schema  protobufSchema
+}
+
+type protobufRegisteredEnvelopeSchema struct {
+	message *protobufEnvelopeMessage
+	schema  protobufSchema
+}
+
+var _ Encoder = &protobufEncoder{}
+
+func newProtobufEncoder(opts map[string]string) (*protobufEncoder, error) {
+	e := &protobufEncoder{registryURL: opts[optConfluentSchemaRegistry]}
+
+	switch opts[optEnvelope] {
+	case string(optEnvelopeKeyOnly):
+		e.keyOnly = true
+	case string(optEnvelopeWrapped):
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			optEnvelope, opts[optEnvelope], optFormat, optFormatProtobuf)
+	}
+	_, e.updatedField = opts[optUpdatedTimestamps]
+	_, e.beforeField = opts[optDiff]
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			optDiff, optEnvelope, optEnvelopeWrapped)
+	}
+
+	if _, ok := opts[optKeyInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			optKeyInValue, optFormat, optFormatProtobuf)
+	}
+
+	e.keyCache = make(map[tableIDAndVersion]protobufRegisteredKeySchema)
+	e.valueCache = make(map[valueCacheKey]protobufRegisteredEnvelopeSchema)
+	e.resolvedCache = make(map[string]protobufRegisteredEnvelopeSchema)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *protobufEncoder) EncodeKey(row encodeRow) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	registered, ok := e.keyCache[cacheKey]
+	if !ok {
+		var err error
+		registered.message, err = indexToProtobufMessage(row.tableDesc, &row.tableDesc.PrimaryIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.Name) + confluentSubjectSuffixKey
+		registered.schema, err = e.makeSchema(protobufFile(registered.message.desc), subject)
+		if err != nil {
+			return nil, err
+		}
+		// TODO(dan): Bound the size of this cache.
+		e.keyCache[cacheKey] = registered
+	}
+
+	msg, err := registered.message.BinaryFromRow(nil /* buf */, row.datums)
+	if err != nil {
+		return nil, err
+	}
+	return e.frame(registered.schema, msg), nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *protobufEncoder) EncodeValue(row encodeRow) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+	row = row.valueRow()
+
+	// The before row is encoded with the table version it was read with, which
+	// may differ from the version of the after row.
+	prevTableDesc := row.tableDesc
+	if e.beforeField && row.prevTableDesc != nil {
+		prevTableDesc = row.prevTableDesc
+	}
+	cacheKey := valueCacheKey{
+		after: makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version),
+	}
+	if e.beforeField {
+		cacheKey.before = makeTableIDAndVersion(prevTableDesc.ID, prevTableDesc.Version)
+	}
+	if row.family != nil {
+		cacheKey.familyID = row.family.ID
+	}
+	registered, ok := e.valueCache[cacheKey]
+	if !ok {
+		var beforeMessage *protobufDataMessage
+		if e.beforeField {
+			prevFamily := row.family
+			var err error
+			if prevFamily != nil {
+				if prevFamily, err = prevTableDesc.FindFamilyByID(row.family.ID); err != nil {
+					return nil, err
+				}
+			}
+			beforeMessage, err = familyToProtobufMessage(prevTableDesc, prevFamily, `before`)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		afterMessage, err := familyToProtobufMessage(row.tableDesc, row.family, protobufSchemaNoSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := protobufEnvelopeOpts{
+			afterField: true, beforeField: e.beforeField, updatedField: e.updatedField,
+		}
+		registered.message = envelopeToProtobufMessage(
+			row.tableDesc.Name, opts, beforeMessage, afterMessage)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.Name) + confluentSubjectSuffixValue
+		registered.schema, err = e.makeSchema(
+			protobufFile(registered.message.messages()...), subject)
+		if err != nil {
+			return nil, err
+		}
+		// TODO(dan): Bound the size of this cache.
+		e.valueCache[cacheKey] = registered
+	}
+	var meta map[string]string
+	if registered.message.opts.updatedField {
+		meta = map[string]string{
+			`updated`: row.updated.AsOfSystemTime(),
+		}
+	}
+	var beforeDatums, afterDatums sqlbase.EncDatumRow
+	if row.prevDatums != nil && !row.prevDeleted {
+		beforeDatums = row.prevDatums
+	}
+	if !row.deleted {
+		afterDatums = row.datums
+	}
+	msg, err := registered.message.BinaryFromRow(nil /* buf */, meta, beforeDatums, afterDatums)
+	if err != nil {
+		return nil, err
+	}
+	return e.frame(registered.schema, msg), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *protobufEncoder) EncodeResolvedTimestamp(
+	topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		opts := protobufEnvelopeOpts{resolvedField: true}
+		registered.message = envelopeToProtobufMessage(topic, opts, nil /* before */, nil /* after */)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		registered.schema, err = e.makeSchema(protobufFile(registered.message.messages()...), subject)
+		if err != nil {
+			return nil, err
+		}
+		// TODO(dan): Bound the size of this cache.
+		e.resolvedCache[topic] = registered
+	}
+	meta := map[string]string{
+		`resolved`: tree.TimestampToDecimal(resolved).Decimal.String(),
+	}
+	msg, err := registered.message.BinaryFromRow(nil /* buf */, meta, nil /* beforeRow */, nil /* afterRow */)
+	if err != nil {
+		return nil, err
+	}
+	return e.frame(registered.schema, msg), nil
+}
+
+// makeSchema registers the given file with the schema registry if the encoder
+// uses one, and serializes it otherwise. The type of the encoded messages must
+// be the first message type in the file.
+func (e *protobufEncoder) makeSchema(
+	file *descriptor.FileDescriptorProto, subject string,
+) (protobufSchema, error) {
+	var schema protobufSchema
+	var err error
+	if e.registryURL != `` {
+		schema.registryID, err = registerConfluentSchema(
+			e.registryURL, confluentSchemaTypeProtobuf, protobufFileToText(file), subject)
+		return schema, err
+	}
+	schema.descriptorSet, err = proto.Marshal(&descriptor.FileDescriptorSet{
+		File: []*descriptor.FileDescriptorProto{file},
+	})
+	schema.typeURL = `type.googleapis.com/` + file.MessageType[0].GetName()
+	return schema, err
+}
+
+// frame prepends the schema ID of an encoded message if the encoder uses a
+// schema registry, or wraps it in a self-describing message otherwise.
+func (e *protobufEncoder) frame(schema protobufSchema, msg []byte) []byte {
+	if e.registryURL != `` {
+		// https://docs.confluent.io/current/schema-registry/serializer-formatter.html#wire-format
+		header := []byte{
+			confluentAvroWireFormatMagic,
+			0, 0, 0, 0, // Placeholder for the ID.
+			0, // The message type is the first one in the schema.
+		}
+		binary.BigEndian.PutUint32(header[1:5], uint32(schema.registryID))
+		return append(header, msg...)
+	}
+
+	anyMsg := proto.NewBuffer(nil)
+	_ = anyMsg.EncodeVarint(protobufTag(1, proto.WireBytes))
+	_ = anyMsg.EncodeStringBytes(schema.typeURL)
+	_ = anyMsg.EncodeVarint(protobufTag(2, proto.WireBytes))
+	_ = anyMsg.EncodeRawBytes(msg)
+
+	wrapped := proto.NewBuffer(nil)
+	_ = wrapped.EncodeVarint(protobufTag(1, proto.WireBytes))
+	_ = wrapped.EncodeRawBytes(schema.descriptorSet)
+	_ = wrapped.EncodeVarint(protobufTag(2, proto.WireBytes))
+	_ = wrapped.EncodeRawBytes(anyMsg.Bytes())
+	return wrapped.Bytes()
+}
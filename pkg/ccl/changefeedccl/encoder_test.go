@@ -14,10 +14,13 @@ import (
 	"encoding/binary"
 	gojson "encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach-go/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -29,6 +32,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/linkedin/goavro"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -193,6 +198,147 @@ func TestEncoders(t *testing.T) {
 	}
 }
 
+func TestEncodersWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := sqlbase.EncDatumRow{
+		sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+		sqlbase.EncDatum{Datum: tree.NewDString(`bar`)},
+	}
+	prevRow := sqlbase.EncDatumRow{
+		sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+		sqlbase.EncDatum{Datum: tree.NewDString(`baz`)},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	expecteds := map[formatType]struct {
+		insert string
+		update string
+		delete string
+	}{
+		optFormatJSON: {
+			insert: `[1]->{"after": {"a": 1, "b": "bar"}, "before": null}`,
+			update: `[1]->{"after": {"a": 1, "b": "bar"}, "before": {"a": 1, "b": "baz"}}`,
+			delete: `[1]->{"after": null, "before": {"a": 1, "b": "baz"}}`,
+		},
+		optFormatAvro: {
+			insert: `{"a":{"long":1}}->` +
+				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},"before":null}`,
+			update: `{"a":{"long":1}}->` +
+				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
+				`"before":{"foo_before":{"a":{"long":1},"b":{"string":"baz"}}}}`,
+			delete: `{"a":{"long":1}}->` +
+				`{"after":null,"before":{"foo_before":{"a":{"long":1},"b":{"string":"baz"}}}}`,
+		},
+	}
+
+	for _, f := range []formatType{optFormatJSON, optFormatAvro} {
+		t.Run(string(f), func(t *testing.T) {
+			o := map[string]string{
+				optFormat:   string(f),
+				optEnvelope: string(optEnvelopeWrapped),
+				optDiff:     ``,
+			}
+			expected := expecteds[f]
+
+			rowStringFn := func(k, v []byte) string { return fmt.Sprintf(`%s->%s`, k, v) }
+			if f == optFormatAvro {
+				reg := makeTestSchemaRegistry()
+				defer reg.Close()
+				o[optConfluentSchemaRegistry] = reg.server.URL
+				rowStringFn = func(k, v []byte) string {
+					key, value := avroToJSON(t, reg, k), avroToJSON(t, reg, v)
+					return fmt.Sprintf(`%s->%s`, key, value)
+				}
+			}
+
+			e, err := getEncoder(o)
+			require.NoError(t, err)
+
+			for _, tc := range []struct {
+				row      encodeRow
+				expected string
+			}{
+				{
+					row: encodeRow{
+						datums: row, prevDeleted: true, updated: ts,
+						tableDesc: tableDesc, prevTableDesc: tableDesc,
+					},
+					expected: expected.insert,
+				},
+				{
+					row: encodeRow{
+						datums: row, prevDatums: prevRow, updated: ts,
+						tableDesc: tableDesc, prevTableDesc: tableDesc,
+					},
+					expected: expected.update,
+				},
+				{
+					row: encodeRow{
+						datums: row, deleted: true, prevDatums: prevRow, updated: ts,
+						tableDesc: tableDesc, prevTableDesc: tableDesc,
+					},
+					expected: expected.delete,
+				},
+			} {
+				key, err := e.EncodeKey(tc.row)
+				require.NoError(t, err)
+				key = append([]byte(nil), key...)
+				value, err := e.EncodeValue(tc.row)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, rowStringFn(key, value))
+			}
+		})
+	}
+
+	_, err = getEncoder(map[string]string{
+		optFormat: string(optFormatJSON), optEnvelope: string(optEnvelopeRow), optDiff: ``,
+	})
+	require.EqualError(t, err, `diff is only usable with envelope=wrapped`)
+}
+
+func TestEncodersSplitColumnFamilies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT, FAMILY (a, b), FAMILY f_c (c))`)
+	require.NoError(t, err)
+	family, err := tableDesc.FindFamilyByID(1)
+	require.NoError(t, err)
+	row := encodeRow{
+		datums: sqlbase.EncDatumRow{
+			sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+			sqlbase.EncDatum{},
+			sqlbase.EncDatum{Datum: tree.NewDInt(2)},
+		},
+		tableDesc: tableDesc,
+		family:    family,
+	}
+
+	e, err := getEncoder(map[string]string{
+		optFormat: string(optFormatJSON), optEnvelope: string(optEnvelopeWrapped),
+	})
+	require.NoError(t, err)
+	value, err := e.EncodeValue(row)
+	require.NoError(t, err)
+	require.Equal(t, `{"after": {"a": 1, "c": 2}}`, string(value))
+
+	reg := makeTestSchemaRegistry()
+	defer reg.Close()
+	e, err = getEncoder(map[string]string{
+		optFormat:                  string(optFormatAvro),
+		optEnvelope:                string(optEnvelopeWrapped),
+		optConfluentSchemaRegistry: reg.server.URL,
+	})
+	require.NoError(t, err)
+	value, err = e.EncodeValue(row)
+	require.NoError(t, err)
+	require.Equal(t, `{"after":{"foo_f_c":{"a":{"long":1},"c":{"long":2}}}}`,
+		string(avroToJSON(t, reg, value)))
+}
+
 type testSchemaRegistry struct {
 	server *httptest.Server
 	mu     struct {
@@ -388,3 +534,220 @@ func TestAvroLedger(t *testing.T) {
 	t.Run(`sinkless`, sinklessTest(testFn))
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
+
+// decodeSelfDescribingProtobuf decodes a message wrapped by a protobufEncoder
+// that doesn't use a schema registry into a string for comparisons in tests.
+func decodeSelfDescribingProtobuf(b []byte) (string, error) {
+	fields, err := splitProtobufFields(b)
+	if err != nil {
+		return ``, err
+	}
+	var set descriptor.FileDescriptorSet
+	if err := proto.Unmarshal(fields[1], &set); err != nil {
+		return ``, err
+	}
+	anyFields, err := splitProtobufFields(fields[2])
+	if err != nil {
+		return ``, err
+	}
+	typeName := strings.TrimPrefix(string(anyFields[1]), `type.googleapis.com/`)
+	return protobufToString(set.File[0], typeName, anyFields[2])
+}
+
+// splitProtobufFields returns the raw bytes of each field of a protobuf message
+// with only length-delimited fields.
+func splitProtobufFields(b []byte) (map[int32][]byte, error) {
+	fields := make(map[int32][]byte)
+	for len(b) > 0 {
+		tag, n := proto.DecodeVarint(b)
+		b = b[n:]
+		if tag&7 != proto.WireBytes {
+			return nil, errors.Errorf(`unexpected wire type %d`, tag&7)
+		}
+		l, n := proto.DecodeVarint(b)
+		fields[int32(tag>>3)] = b[n : n+int(l)]
+		b = b[n+int(l):]
+	}
+	return fields, nil
+}
+
+// protobufToString decodes a message of the given type in the given file.
+func protobufToString(
+	file *descriptor.FileDescriptorProto, typeName string, b []byte,
+) (string, error) {
+	var msg *descriptor.DescriptorProto
+	for _, m := range file.MessageType {
+		if m.GetName() == typeName {
+			msg = m
+		}
+	}
+	if msg == nil {
+		return ``, errors.Errorf(`unknown message type %s`, typeName)
+	}
+	var parts []string
+	for len(b) > 0 {
+		tag, n := proto.DecodeVarint(b)
+		b = b[n:]
+		var field *descriptor.FieldDescriptorProto
+		for _, f := range msg.Field {
+			if uint64(f.GetNumber()) == tag>>3 {
+				field = f
+			}
+		}
+		if field == nil {
+			return ``, errors.Errorf(`unknown field %d in %s`, tag>>3, typeName)
+		}
+		var value string
+		switch field.GetType() {
+		case descriptor.FieldDescriptorProto_TYPE_INT64:
+			x, n := proto.DecodeVarint(b)
+			b = b[n:]
+			value = fmt.Sprint(int64(x))
+		case descriptor.FieldDescriptorProto_TYPE_BOOL:
+			x, n := proto.DecodeVarint(b)
+			b = b[n:]
+			value = fmt.Sprint(x != 0)
+		case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+			value = fmt.Sprint(math.Float64frombits(binary.LittleEndian.Uint64(b[:8])))
+			b = b[8:]
+		default:
+			l, n := proto.DecodeVarint(b)
+			raw := b[n : n+int(l)]
+			b = b[n+int(l):]
+			if field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+				var err error
+				value, err = protobufToString(file, strings.TrimPrefix(field.GetTypeName(), `.`), raw)
+				if err != nil {
+					return ``, err
+				}
+			} else {
+				value = fmt.Sprintf(`%q`, raw)
+			}
+		}
+		parts = append(parts, field.GetName()+`:`+value)
+	}
+	return `{` + strings.Join(parts, ` `) + `}`, nil
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c FLOAT, d BOOL, e DECIMAL)`)
+	require.NoError(t, err)
+	row := sqlbase.EncDatumRow{
+		sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+		sqlbase.EncDatum{Datum: tree.NewDString(`bar`)},
+		sqlbase.EncDatum{Datum: tree.NewDFloat(1.5)},
+		sqlbase.EncDatum{Datum: tree.DBoolTrue},
+		sqlbase.EncDatum{Datum: tree.DNull},
+	}
+	prevRow := sqlbase.EncDatumRow{
+		sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+		sqlbase.EncDatum{Datum: tree.DNull},
+		sqlbase.EncDatum{Datum: tree.DNull},
+		sqlbase.EncDatum{Datum: tree.DBoolFalse},
+		sqlbase.EncDatum{Datum: &tree.DDecimal{Decimal: *apd.New(125, -2)}},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	tests := []struct {
+		opts     map[string]string
+		key      string
+		insert   string
+		update   string
+		delete   string
+		resolved string
+	}{
+		{
+			opts:     map[string]string{optEnvelope: string(optEnvelopeKeyOnly)},
+			key:      `{a:1}`,
+			resolved: `{resolved:"1.0000000002"}`,
+		},
+		{
+			opts:     map[string]string{optEnvelope: string(optEnvelopeWrapped)},
+			key:      `{a:1}`,
+			insert:   `{after:{a:1 b:"bar" c:1.5 d:true}}`,
+			update:   `{after:{a:1 b:"bar" c:1.5 d:true}}`,
+			delete:   `{}`,
+			resolved: `{resolved:"1.0000000002"}`,
+		},
+		{
+			opts: map[string]string{
+				optEnvelope: string(optEnvelopeWrapped), optUpdatedTimestamps: ``, optDiff: ``,
+			},
+			key:    `{a:1}`,
+			insert: `{after:{a:1 b:"bar" c:1.5 d:true} updated:"1.0000000002"}`,
+			update: `{after:{a:1 b:"bar" c:1.5 d:true} ` +
+				`before:{a:1 d:false e:"1.25"} updated:"1.0000000002"}`,
+			delete:   `{before:{a:1 d:false e:"1.25"} updated:"1.0000000002"}`,
+			resolved: `{resolved:"1.0000000002"}`,
+		},
+	}
+	for _, test := range tests {
+		e, err := newProtobufEncoder(test.opts)
+		require.NoError(t, err)
+
+		decode := func(b []byte, err error) string {
+			require.NoError(t, err)
+			if b == nil {
+				return ``
+			}
+			s, err := decodeSelfDescribingProtobuf(b)
+			require.NoError(t, err)
+			return s
+		}
+		insert := encodeRow{datums: row, updated: ts, tableDesc: tableDesc}
+		update := insert
+		update.prevDatums, update.prevTableDesc = prevRow, tableDesc
+		del := update
+		del.datums, del.deleted = prevRow, true
+
+		require.Equal(t, test.key, decode(e.EncodeKey(insert)))
+		require.Equal(t, test.insert, decode(e.EncodeValue(insert)))
+		require.Equal(t, test.update, decode(e.EncodeValue(update)))
+		require.Equal(t, test.delete, decode(e.EncodeValue(del)))
+		require.Equal(t, test.resolved, decode(e.EncodeResolvedTimestamp(`foo`, ts)))
+	}
+
+	t.Run(`registry`, func(t *testing.T) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		e, err := newProtobufEncoder(map[string]string{
+			optEnvelope:                string(optEnvelopeWrapped),
+			optConfluentSchemaRegistry: reg.server.URL,
+		})
+		require.NoError(t, err)
+		value, err := e.EncodeValue(encodeRow{datums: row, updated: ts, tableDesc: tableDesc})
+		require.NoError(t, err)
+		require.Equal(t, []byte{confluentAvroWireFormatMagic, 0, 0, 0, 0, 0}, value[:6])
+
+		reg.mu.Lock()
+		schema := reg.mu.schemas[0]
+		reg.mu.Unlock()
+		require.Equal(t, `syntax = "proto2";
+
+message foo_envelope {
+  optional foo after = 1;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional double c = 3;
+  optional bool d = 4;
+  optional string e = 5;
+}
+`, schema)
+	})
+
+	t.Run(`unsupported`, func(t *testing.T) {
+		_, err := newProtobufEncoder(map[string]string{optEnvelope: string(optEnvelopeRow)})
+		require.EqualError(t, err, `envelope=row is not supported with format=protobuf`)
+		_, err = newProtobufEncoder(map[string]string{
+			optEnvelope: string(optEnvelopeWrapped), optKeyInValue: ``,
+		})
+		require.EqualError(t, err, `key_in_value is not supported with format=protobuf`)
+	})
+}
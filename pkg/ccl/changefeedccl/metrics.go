@@ -10,9 +10,14 @@ package changefeedccl
 
 import (
 	"context"
+	"sort"
+	"sync/atomic"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -22,26 +27,37 @@ import (
 
 type metricsSink struct {
 	metrics *Metrics
+	// status, if non-nil, additionally counts what this one processor emitted.
+	status  *changefeedStatus
 	wrapped Sink
 }
 
-func makeMetricsSink(metrics *Metrics, s Sink) *metricsSink {
+func makeMetricsSink(metrics *Metrics, status *changefeedStatus, s Sink) *metricsSink {
 	m := &metricsSink{
 		metrics: metrics,
+		status:  status,
 		wrapped: s,
 	}
 	return m
 }
 
 func (s *metricsSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, updated hlc.Timestamp,
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	updated hlc.Timestamp,
+	partitionKey []byte,
 ) error {
 	start := timeutil.Now()
-	err := s.wrapped.EmitRow(ctx, table, key, value, updated)
+	err := s.wrapped.EmitRow(ctx, table, key, value, updated, partitionKey)
 	if err == nil {
 		s.metrics.EmittedMessages.Inc(1)
 		s.metrics.EmittedBytes.Inc(int64(len(key) + len(value)))
 		s.metrics.EmitNanos.Inc(timeutil.Since(start).Nanoseconds())
+		if s.status != nil {
+			atomic.AddInt64(&s.status.emittedMessages, 1)
+			atomic.AddInt64(&s.status.emittedBytes, int64(len(key)+len(value)))
+		}
 	}
 	return err
 }
@@ -57,6 +73,9 @@ func (s *metricsSink) EmitResolvedTimestamp(
 		// any number of times.
 		// s.metrics.EmittedBytes.Inc(int64(len(payload)))
 		s.metrics.EmitNanos.Inc(timeutil.Since(start).Nanoseconds())
+		if s.status != nil {
+			atomic.AddInt64(&s.status.emittedMessages, 1)
+		}
 	}
 	return err
 }
@@ -177,6 +196,9 @@ type Metrics struct {
 		syncutil.Mutex
 		id       int
 		resolved map[int]hlc.Timestamp
+		// statuses are the changefeed processors running on this node, keyed
+		// by the same ids as resolved.
+		statuses map[int]*changefeedStatus
 	}
 	MaxBehindNanos *metric.Gauge
 }
@@ -184,6 +206,41 @@ type Metrics struct {
 // MetricStruct implements the metric.Struct interface.
 func (*Metrics) MetricStruct() {}
 
+var _ jobs.ChangefeedStatusSource = (*Metrics)(nil)
+
+// registerStatus adds a changefeed processor to the set listed by
+// ChangefeedStatuses and returns the id under which it was registered.
+func (m *Metrics) registerStatus(status *changefeedStatus) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.mu.id
+	m.mu.id++
+	m.mu.statuses[id] = status
+	return id
+}
+
+// ChangefeedStatuses implements the jobs.ChangefeedStatusSource interface.
+func (m *Metrics) ChangefeedStatuses() []jobs.ChangefeedStatus {
+	m.mu.Lock()
+	statuses := make([]*changefeedStatus, 0, len(m.mu.statuses))
+	for _, status := range m.mu.statuses {
+		statuses = append(statuses, status)
+	}
+	m.mu.Unlock()
+
+	ret := make([]jobs.ChangefeedStatus, len(statuses))
+	for i, status := range statuses {
+		ret[i] = status.snapshot()
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].JobID != ret[j].JobID {
+			return ret[i].JobID < ret[j].JobID
+		}
+		return ret[i].Processor < ret[j].Processor
+	})
+	return ret
+}
+
 // MakeMetrics makes the metrics for changefeed monitoring.
 func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 	m := &Metrics{
@@ -216,6 +273,7 @@ func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 		FlushNanos:         metric.NewCounter(metaChangefeedFlushNanos),
 	}
 	m.mu.resolved = make(map[int]hlc.Timestamp)
+	m.mu.statuses = make(map[int]*changefeedStatus)
 
 	m.MaxBehindNanos = metric.NewFunctionalGauge(metaChangefeedMaxBehindNanos, func() int64 {
 		now := timeutil.Now()
@@ -235,3 +293,65 @@ func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 func init() {
 	jobs.MakeChangefeedMetricsHook = MakeMetrics
 }
+
+const (
+	changefeedProcessorAggregator = `aggregator`
+	changefeedProcessorFrontier   = `frontier`
+)
+
+// changefeedStatus is the live state of one changefeed processor. It's
+// registered with Metrics so that crdb_internal.changefeed_status can list it.
+type changefeedStatus struct {
+	jobID     int64
+	processor string
+	sinkURI   string
+
+	// emittedMessages and emittedBytes are accessed atomically.
+	emittedMessages int64
+	emittedBytes    int64
+
+	mu struct {
+		syncutil.Mutex
+		// sf is the resolved timestamp of each span tracked by the processor.
+		// The processor is its only writer and only needs to hold mu while
+		// forwarding it.
+		sf *spanFrontier
+	}
+}
+
+func makeChangefeedStatus(
+	jobID int64, processor string, sinkURI string, sf *spanFrontier,
+) *changefeedStatus {
+	s := &changefeedStatus{jobID: jobID, processor: processor}
+	// Like in the job description, credentials in the sink's query string must
+	// not be exposed.
+	if cleanedSinkURI, err := storageccl.SanitizeExportStorageURI(sinkURI); err == nil {
+		s.sinkURI = cleanedSinkURI
+	}
+	s.mu.sf = sf
+	return s
+}
+
+// forward advances the resolved timestamp of a span tracked by the processor.
+// True is returned if its frontier advanced as a result.
+func (s *changefeedStatus) forward(span roachpb.Span, ts hlc.Timestamp) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.sf.Forward(span, ts)
+}
+
+func (s *changefeedStatus) snapshot() jobs.ChangefeedStatus {
+	ret := jobs.ChangefeedStatus{
+		JobID:           s.jobID,
+		Processor:       s.processor,
+		SinkURI:         s.sinkURI,
+		EmittedMessages: atomic.LoadInt64(&s.emittedMessages),
+		EmittedBytes:    atomic.LoadInt64(&s.emittedBytes),
+	}
+	s.mu.Lock()
+	s.mu.sf.Entries(func(span roachpb.Span, ts hlc.Timestamp) {
+		ret.Spans = append(ret.Spans, jobspb.ResolvedSpan{Span: span, Timestamp: ts})
+	})
+	s.mu.Unlock()
+	return ret
+}
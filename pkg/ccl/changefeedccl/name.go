@@ -75,6 +75,13 @@ func SQLNameToAvroName(s string) string {
 	return escapeSQLName(s, avroDisallowedRE)
 }
 
+// SQLNameToProtobufName escapes a sql table name into a valid protobuf message
+// or field name. Protobuf identifiers follow the same rules as avro names, so
+// this is the same escaping as SQLNameToAvroName.
+func SQLNameToProtobufName(s string) string {
+	return SQLNameToAvroName(s)
+}
+
 // AvroNameToSQLName is the inverse of SQLNameToAvroName.
 func AvroNameToSQLName(s string) string {
 	return unescapeSQLName(s)
@@ -62,8 +62,10 @@ type poller struct {
 		// should pause and output a scan of *all keys* of the watched spans at the
 		// given timestamp. There are currently two situations where this occurs:
 		// the initial scan of the table when starting a new Changefeed, and when
-		// a backfilling schema change is marked as completed. This collection must
-		// be kept in sorted order (by timestamp ascending).
+		// a schema change event (see `schema_change_events`) happens. With
+		// `schema_change_policy=stop`, the changefeed instead stops at the first
+		// schema change event boundary. This collection must be kept in sorted
+		// order (by timestamp ascending).
 		scanBoundaries []hlc.Timestamp
 		// previousTableVersion is a map from tableID to the most recent version
 		// of the table descriptor seen by the poller. This is needed to determine
@@ -102,10 +104,12 @@ func makePoller(
 	p.mu.previousTableVersion = make(map[sqlbase.ID]*sqlbase.TableDescriptor)
 	// If no highWater is specified, set the highwater to the statement time
 	// and add a scanBoundary at the statement time to trigger an immediate output
-	// of the full table.
+	// of the full table, unless the changefeed was asked to skip it.
 	if highWater == (hlc.Timestamp{}) {
 		p.mu.highWater = details.StatementTime
-		p.mu.scanBoundaries = append(p.mu.scanBoundaries, details.StatementTime)
+		if _, ok := details.Opts[optNoInitialScan]; !ok {
+			p.mu.scanBoundaries = append(p.mu.scanBoundaries, details.StatementTime)
+		}
 	} else {
 		p.mu.highWater = highWater
 	}
@@ -152,6 +156,20 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 			p.mu.scanBoundaries = p.mu.scanBoundaries[1:]
 		}
 		p.mu.Unlock()
+		if scanTime != (hlc.Timestamp{}) && p.details.StatementTime.Less(scanTime) &&
+			schemaChangePolicy(p.details.Opts[optSchemaChangePolicy]) == optSchemaChangePolicyStop {
+			// Every boundary after the statement time is a schema change event.
+			// Everything before it has been emitted, so mark the watched spans
+			// as resolved at the boundary, which lets the changeFrontier stop the
+			// changefeed, and wait to be shut down.
+			for _, span := range p.spans {
+				if err := p.buf.AddSchemaChangeStop(ctx, span, scanTime); err != nil {
+					return err
+				}
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
 		if scanTime != (hlc.Timestamp{}) {
 			// TODO(dan): Now that we no longer have the poller, we should stop using
 			// ExportRequest and start using normal Scans.
@@ -160,6 +178,20 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 			}
 		}
 
+		if _, ok := p.details.Opts[optInitialScanOnly]; ok {
+			// The changefeed only outputs the initial scan, which has either
+			// just finished or was finished before the changefeed was restarted.
+			// Resolve the watched spans at the high-water, which lets the
+			// changeFrontier finish the changefeed, and wait to be shut down.
+			for _, span := range p.spans {
+				if err := p.buf.AddResolved(ctx, span, lastHighwater); err != nil {
+					return err
+				}
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
 		// Start rangefeeds, exit polling if we hit a resolved timestamp beyond
 		// the next scan boundary.
 
@@ -202,11 +234,12 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 		frontier := makeSpanFrontier(spans...)
 
 		rangeFeedStartTS := lastHighwater
+		_, withDiff := p.details.Opts[optDiff]
 		for _, span := range p.spans {
 			span := span
 			frontier.Forward(span, rangeFeedStartTS)
 			g.GoCtx(func(ctx context.Context) error {
-				return ds.RangeFeed(ctx, span, rangeFeedStartTS, eventC)
+				return ds.RangeFeed(ctx, span, rangeFeedStartTS, withDiff, eventC)
 			})
 		}
 		g.GoCtx(func(ctx context.Context) error {
@@ -216,7 +249,7 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 					switch t := e.GetValue().(type) {
 					case *roachpb.RangeFeedValue:
 						kv := roachpb.KeyValue{Key: t.Key, Value: t.Value}
-						if err := memBuf.AddKV(ctx, kv, hlc.Timestamp{}); err != nil {
+						if err := memBuf.AddKV(ctx, kv, t.PrevValue, hlc.Timestamp{}); err != nil {
 							return err
 						}
 					case *roachpb.RangeFeedCheckpoint:
@@ -229,6 +262,12 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 						if err := memBuf.AddResolved(ctx, t.Span, t.ResolvedTS); err != nil {
 							return err
 						}
+					case *roachpb.RangeFeedDeleteRange:
+						// Range tombstones are only written when a table is dropped or
+						// truncated, which the changefeed can't represent as row
+						// deletions.
+						return errors.Errorf(
+							"unexpected range deletion of %s at %s", t.Span, t.Timestamp)
 					default:
 						log.Fatalf(ctx, "unexpected RangeFeedEvent variant %v", t)
 					}
@@ -258,7 +297,7 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 					if pastBoundary {
 						continue
 					}
-					if err := p.buf.AddKV(ctx, e.kv, e.schemaTimestamp); err != nil {
+					if err := p.buf.AddKV(ctx, e.kv, e.prevVal, e.schemaTimestamp); err != nil {
 						return err
 					}
 				} else if e.resolved != nil {
@@ -471,7 +510,7 @@ func (p *poller) slurpSST(ctx context.Context, sst []byte, schemaTimestamp hlc.T
 	slurpKVs := func() error {
 		sort.Sort(byValueTimestamp(kvs))
 		for _, kv := range kvs {
-			if err := p.buf.AddKV(ctx, kv, schemaTimestamp); err != nil {
+			if err := p.buf.AddKV(ctx, kv, roachpb.Value{}, schemaTimestamp); err != nil {
 				return err
 			}
 		}
@@ -563,24 +602,28 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 		if desc.ModificationTime.Less(lastVersion.ModificationTime) {
 			return nil
 		}
-		if lastVersion.HasColumnBackfillMutation() && !desc.HasColumnBackfillMutation() {
+		if p.isSchemaChangeEvent(lastVersion, desc) {
 			boundaryTime := desc.GetModificationTime()
 			// Only mutations that happened after the changefeed started are
 			// interesting here.
 			if p.details.StatementTime.Less(boundaryTime) {
 				if boundaryTime.Less(p.mu.highWater) {
 					return errors.AssertionFailedf(
-						"error: detected table ID %d backfill completed at %s "+
+						"error: detected table ID %d schema change completed at %s "+
 							"earlier than highwater timestamp %s",
 						errors.Safe(desc.ID),
 						errors.Safe(boundaryTime),
 						errors.Safe(p.mu.highWater),
 					)
 				}
-				p.mu.scanBoundaries = append(p.mu.scanBoundaries, boundaryTime)
-				sort.Slice(p.mu.scanBoundaries, func(i, j int) bool {
-					return p.mu.scanBoundaries[i].Less(p.mu.scanBoundaries[j])
-				})
+				// With nobackfill, the changefeed simply continues past the schema
+				// change and emits the rows changed after it with the new schema.
+				if schemaChangePolicy(p.details.Opts[optSchemaChangePolicy]) != optSchemaChangePolicyNoBackfill {
+					p.mu.scanBoundaries = append(p.mu.scanBoundaries, boundaryTime)
+					sort.Slice(p.mu.scanBoundaries, func(i, j int) bool {
+						return p.mu.scanBoundaries[i].Less(p.mu.scanBoundaries[j])
+					})
+				}
 				// To avoid race conditions with the lease manager, at this point we force
 				// the manager to acquire the freshest descriptor of this table from the
 				// store. In normal operation, the lease manager returns the newest
@@ -598,6 +641,28 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 	return nil
 }
 
+// isSchemaChangeEvent returns whether going from the lastVersion of a table
+// descriptor to desc is a schema change event of the class selected by the
+// `schema_change_events` option.
+func (p *poller) isSchemaChangeEvent(lastVersion, desc *sqlbase.TableDescriptor) bool {
+	if lastVersion.HasColumnBackfillMutation() && !desc.HasColumnBackfillMutation() {
+		return true
+	}
+	if schemaChangeEventClass(p.details.Opts[optSchemaChangeEvents]) != optSchemaChangeEventClassColumnChange {
+		return false
+	}
+	if len(lastVersion.Columns) != len(desc.Columns) {
+		return true
+	}
+	for i := range desc.Columns {
+		prev, cur := &lastVersion.Columns[i], &desc.Columns[i]
+		if prev.ID != cur.ID || prev.Name != cur.Name || !prev.Type.Identical(&cur.Type) {
+			return true
+		}
+	}
+	return false
+}
+
 func fetchSpansForTargets(
 	ctx context.Context, db *client.DB, targets jobspb.ChangefeedTargets, ts hlc.Timestamp,
 ) ([]roachpb.Span, error) {
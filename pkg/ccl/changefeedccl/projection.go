@@ -0,0 +1,241 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/pkg/errors"
+)
+
+// projection evaluates the SELECT clause of a `CREATE CHANGEFEED ... AS
+// SELECT` statement against the rows of one version of its target table.
+//
+// Only deterministic expressions over the columns of a single row are allowed:
+// aggregates, window functions, generators, subqueries and impure functions
+// (such as `now()` or `random()`) are all rejected when the projection is
+// built.
+type projection struct {
+	// tableDesc describes the projected rows. It has the ID, version and name
+	// of the table version the projection was built for, but its columns are
+	// the output columns of the SELECT clause.
+	tableDesc *sqlbase.TableDescriptor
+	exprs     []tree.TypedExpr
+	// filter is the WHERE clause of the SELECT clause, or nil if it has none.
+	filter tree.TypedExpr
+
+	ivars projectionContainer
+	alloc sqlbase.DatumAlloc
+}
+
+// projectionContainer is a tree.IndexedVarContainer over one row of the table
+// a projection was built for.
+type projectionContainer struct {
+	cols []sqlbase.ColumnDescriptor
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &projectionContainer{}
+
+// IndexedVarEval implements tree.IndexedVarContainer.
+func (c *projectionContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements tree.IndexedVarContainer.
+func (c *projectionContainer) IndexedVarResolvedType(idx int) *types.T {
+	return &c.cols[idx].Type
+}
+
+// IndexedVarNodeFormatter implements tree.IndexedVarContainer.
+func (c *projectionContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].Name)
+	return &n
+}
+
+// parseChangefeedSelect parses the SELECT clause stored in the details of a
+// changefeed.
+func parseChangefeedSelect(sql string) (*tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return nil, err
+	}
+	if sel, ok := stmt.AST.(*tree.Select); ok {
+		if clause, ok := sel.Select.(*tree.SelectClause); ok {
+			return clause, nil
+		}
+	}
+	return nil, errors.Errorf(`expected a SELECT clause: %s`, sql)
+}
+
+// makeProjection resolves and type checks the given SELECT clause against the
+// columns of the given table version.
+func makeProjection(
+	sel *tree.SelectClause, tableDesc *sqlbase.TableDescriptor,
+) (*projection, error) {
+	if len(sel.From.Tables) != 1 {
+		return nil, errors.Errorf(`CHANGEFEED must select from exactly one table`)
+	}
+	aliased, ok := sel.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok {
+		return nil, errors.Errorf(`CHANGEFEED cannot select from %s`, tree.AsString(sel.From.Tables[0]))
+	}
+	tn, ok := aliased.Expr.(*tree.TableName)
+	if !ok {
+		return nil, errors.Errorf(`CHANGEFEED cannot select from %s`, tree.AsString(aliased.Expr))
+	}
+
+	p := &projection{
+		ivars: projectionContainer{
+			cols: tableDesc.Columns,
+			row:  make(tree.Datums, len(tableDesc.Columns)),
+		},
+	}
+	ivarHelper := tree.MakeIndexedVarHelper(&p.ivars, len(tableDesc.Columns))
+	sources := sqlbase.MakeMultiSourceInfo(sqlbase.NewSourceInfoForSingleTable(
+		*tn, sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
+	))
+	// Function names are resolved with the default search path, so that the
+	// meaning of the projection doesn't depend on the session it's evaluated
+	// in.
+	searchPath := sessiondata.MakeSearchPath(nil /* paths */)
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = &p.ivars
+
+	typeCheck := func(expr tree.Expr, desired *types.T, op string) (tree.TypedExpr, error) {
+		expr, _, hasStar, err := sqlbase.ResolveNames(expr, sources, ivarHelper, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		if hasStar {
+			return nil, errors.Errorf(`CHANGEFEED does not support * in %s`, tree.AsString(expr))
+		}
+		semaCtx.Properties.Require(`CHANGEFEED`,
+			tree.RejectSpecial|tree.RejectImpureFunctions|tree.RejectSubqueries)
+		if desired == types.Any {
+			return tree.TypeCheck(expr, &semaCtx, desired)
+		}
+		return tree.TypeCheckAndRequire(expr, &semaCtx, desired, op)
+	}
+
+	var cols []sqlbase.ColumnDescriptor
+	addColumn := func(name string, typ *types.T) error {
+		for i := range cols {
+			if cols[i].Name == name {
+				return errors.Errorf(`CHANGEFEED selects column "%s" more than once`, name)
+			}
+		}
+		cols = append(cols, sqlbase.ColumnDescriptor{
+			Name:     name,
+			ID:       sqlbase.ColumnID(len(cols) + 1),
+			Type:     *typ,
+			Nullable: true,
+		})
+		return nil
+	}
+	for i := range sel.Exprs {
+		selExpr := sel.Exprs[i]
+		if err := selExpr.NormalizeTopLevelVarName(); err != nil {
+			return nil, err
+		}
+		switch selExpr.Expr.(type) {
+		case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+			if selExpr.As != `` {
+				return nil, errors.Errorf(`"%s" cannot be aliased`, tree.AsString(selExpr.Expr))
+			}
+			for colIdx := range tableDesc.Columns {
+				col := &tableDesc.Columns[colIdx]
+				if col.Hidden {
+					continue
+				}
+				if err := addColumn(col.Name, &col.Type); err != nil {
+					return nil, err
+				}
+				p.exprs = append(p.exprs, ivarHelper.IndexedVar(colIdx))
+			}
+			continue
+		}
+
+		typedExpr, err := typeCheck(selExpr.Expr, types.Any, ``)
+		if err != nil {
+			return nil, err
+		}
+		typ := typedExpr.ResolvedType()
+		if typ.Family() == types.UnknownFamily {
+			return nil, errors.Errorf(`could not determine the type of %s`, tree.AsString(selExpr.Expr))
+		}
+		name := string(selExpr.As)
+		if name == `` {
+			if ivar, ok := typedExpr.(*tree.IndexedVar); ok {
+				name = tableDesc.Columns[ivar.Idx].Name
+			} else {
+				name = tree.AsString(selExpr.Expr)
+			}
+		}
+		if err := addColumn(name, typ); err != nil {
+			return nil, err
+		}
+		p.exprs = append(p.exprs, typedExpr)
+	}
+
+	if sel.Where != nil {
+		var err error
+		if p.filter, err = typeCheck(sel.Where.Expr, types.Bool, `WHERE`); err != nil {
+			return nil, err
+		}
+	}
+
+	p.tableDesc = &sqlbase.TableDescriptor{
+		ID:      tableDesc.ID,
+		Version: tableDesc.Version,
+		Name:    tableDesc.Name,
+		Columns: cols,
+	}
+	return p, nil
+}
+
+// project evaluates the projection against the given row of the table version
+// it was built for. If applyFilter is set and the row doesn't pass the filter
+// of the projection, it returns false and no row.
+func (p *projection) project(
+	evalCtx *tree.EvalContext, datums sqlbase.EncDatumRow, applyFilter bool,
+) (sqlbase.EncDatumRow, bool, error) {
+	for i := range datums {
+		if err := datums[i].EnsureDecoded(&p.ivars.cols[i].Type, &p.alloc); err != nil {
+			return nil, false, err
+		}
+		p.ivars.row[i] = datums[i].Datum
+	}
+
+	evalCtx.PushIVarContainer(&p.ivars)
+	defer evalCtx.PopIVarContainer()
+
+	if applyFilter && p.filter != nil {
+		d, err := p.filter.Eval(evalCtx)
+		if err != nil {
+			return nil, false, err
+		}
+		if d != tree.DBoolTrue {
+			return nil, false, nil
+		}
+	}
+
+	projected := make(sqlbase.EncDatumRow, len(p.exprs))
+	for i, expr := range p.exprs {
+		d, err := expr.Eval(evalCtx)
+		if err != nil {
+			return nil, false, err
+		}
+		projected[i] = sqlbase.DatumToEncDatum(&p.tableDesc.Columns[i].Type, d)
+	}
+	return projected, true, nil
+}
@@ -0,0 +1,400 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/pkg/errors"
+)
+
+// The file contains a mapping between protobuf and our SQL schemas, in the
+// spirit of avro.go. It's not intended to be a general purpose protobuf
+// utility.
+//
+// There is no generated code for the messages emitted by changefeeds: a
+// `DescriptorProto` is derived from each version of a table and rows are
+// encoded by hand following the protobuf wire format. Consumers either get the
+// descriptor along with each message, or look it up in a schema registry.
+//
+// Each column is mapped to a proto2 `optional` field, so that SQL NULLs can be
+// represented by an absent field, regardless of whether the column allows
+// NULLs. The field number of a column is its column ID. Column IDs are never
+// reused within a table, so the messages for all versions of a table are
+// compatible with each other: an added column is a new field and a dropped
+// column is a field that's no longer sent.
+//
+// Types without a native protobuf equivalent are encoded as their SQL string
+// representation, which means the original SQL types can't be recovered from
+// the protobuf schema.
+
+const (
+	protobufSchemaNoSuffix = ``
+
+	// These are the field numbers of the fields of an envelope message.
+	protobufEnvelopeFieldAfter    = 1
+	protobufEnvelopeFieldBefore   = 2
+	protobufEnvelopeFieldUpdated  = 3
+	protobufEnvelopeFieldResolved = 4
+)
+
+// protobufTag returns the tag that precedes a field with the given number and
+// wire type in the protobuf wire format.
+func protobufTag(fieldNum int32, wireType int) uint64 {
+	return uint64(fieldNum)<<3 | uint64(wireType)
+}
+
+// protobufField is a field of a protobuf message that holds a SQL column.
+type protobufField struct {
+	desc   *descriptor.FieldDescriptorProto
+	typ    *types.T
+	colIdx int
+
+	// encodeFn appends the field (tag included) to the buffer. It's never
+	// called with NULL.
+	encodeFn func(*proto.Buffer, tree.Datum) error
+}
+
+// columnDescToProtobufField converts a column descriptor into the field of a
+// protobuf message for the column at the given index in a row.
+func columnDescToProtobufField(
+	colDesc *sqlbase.ColumnDescriptor, colIdx int,
+) (*protobufField, error) {
+	fieldNum := int32(colDesc.ID)
+	field := &protobufField{
+		desc: &descriptor.FieldDescriptorProto{
+			Name:   proto.String(SQLNameToProtobufName(colDesc.Name)),
+			Number: proto.Int32(fieldNum),
+			Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		},
+		typ:    &colDesc.Type,
+		colIdx: colIdx,
+	}
+
+	encodeString := func(buf *proto.Buffer, s string) error {
+		if err := buf.EncodeVarint(protobufTag(fieldNum, proto.WireBytes)); err != nil {
+			return err
+		}
+		return buf.EncodeStringBytes(s)
+	}
+
+	var protoType descriptor.FieldDescriptorProto_Type
+	switch colDesc.Type.Family() {
+	case types.IntFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_INT64
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			if err := buf.EncodeVarint(protobufTag(fieldNum, proto.WireVarint)); err != nil {
+				return err
+			}
+			return buf.EncodeVarint(uint64(*d.(*tree.DInt)))
+		}
+	case types.BoolFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_BOOL
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			if err := buf.EncodeVarint(protobufTag(fieldNum, proto.WireVarint)); err != nil {
+				return err
+			}
+			var v uint64
+			if *d.(*tree.DBool) {
+				v = 1
+			}
+			return buf.EncodeVarint(v)
+		}
+	case types.FloatFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_DOUBLE
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			if err := buf.EncodeVarint(protobufTag(fieldNum, proto.WireFixed64)); err != nil {
+				return err
+			}
+			return buf.EncodeFixed64(math.Float64bits(float64(*d.(*tree.DFloat))))
+		}
+	case types.BytesFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_BYTES
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			if err := buf.EncodeVarint(protobufTag(fieldNum, proto.WireBytes)); err != nil {
+				return err
+			}
+			return buf.EncodeRawBytes([]byte(*d.(*tree.DBytes)))
+		}
+	case types.StringFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_STRING
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			return encodeString(buf, string(*d.(*tree.DString)))
+		}
+	case types.CollatedStringFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_STRING
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			return encodeString(buf, d.(*tree.DCollatedString).Contents)
+		}
+	case types.JsonFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_STRING
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			return encodeString(buf, d.(*tree.DJSON).JSON.String())
+		}
+	case types.DecimalFamily, types.DateFamily, types.TimeFamily, types.TimestampFamily,
+		types.TimestampTZFamily, types.IntervalFamily, types.UuidFamily, types.INetFamily:
+		protoType = descriptor.FieldDescriptorProto_TYPE_STRING
+		field.encodeFn = func(buf *proto.Buffer, d tree.Datum) error {
+			return encodeString(buf, tree.AsStringWithFlags(d, tree.FmtBareStrings))
+		}
+	default:
+		return nil, errors.Errorf(`column %s: type %s not yet supported with protobuf`,
+			colDesc.Name, colDesc.Type.SQLString())
+	}
+	field.desc.Type = protoType.Enum()
+	return field, nil
+}
+
+// protobufDataMessage is a protobuf message type with a field for each of a
+// set of columns of a table.
+type protobufDataMessage struct {
+	desc   *descriptor.DescriptorProto
+	fields []*protobufField
+
+	alloc sqlbase.DatumAlloc
+}
+
+func newProtobufDataMessage(name string) *protobufDataMessage {
+	return &protobufDataMessage{desc: &descriptor.DescriptorProto{Name: proto.String(name)}}
+}
+
+func (m *protobufDataMessage) addColumn(tableDesc *sqlbase.TableDescriptor, colIdx int) error {
+	field, err := columnDescToProtobufField(&tableDesc.Columns[colIdx], colIdx)
+	if err != nil {
+		return err
+	}
+	m.desc.Field = append(m.desc.Field, field.desc)
+	m.fields = append(m.fields, field)
+	return nil
+}
+
+// indexToProtobufMessage converts the columns of an index into a protobuf
+// message type.
+func indexToProtobufMessage(
+	tableDesc *sqlbase.TableDescriptor, indexDesc *sqlbase.IndexDescriptor,
+) (*protobufDataMessage, error) {
+	m := newProtobufDataMessage(SQLNameToProtobufName(tableDesc.Name))
+	colIdxByID := tableDesc.ColumnIdxMap()
+	for _, colID := range indexDesc.ColumnIDs {
+		colIdx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		if err := m.addColumn(tableDesc, colIdx); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// familyToProtobufMessage converts the columns of a table into a protobuf
+// message type. If family is set, the message only has fields for the primary
+// key columns and the columns of that column family, and the name of the
+// family is appended to the name of the message. If a name suffix is provided
+// (as opposed to protobufSchemaNoSuffix), it is appended to the name of the
+// message, so that the same table can appear more than once in a schema.
+func familyToProtobufMessage(
+	tableDesc *sqlbase.TableDescriptor, family *sqlbase.ColumnFamilyDescriptor, nameSuffix string,
+) (*protobufDataMessage, error) {
+	name := SQLNameToProtobufName(tableDesc.Name)
+	var colIDs map[sqlbase.ColumnID]struct{}
+	if family != nil {
+		name = name + `_` + SQLNameToProtobufName(family.Name)
+		colIDs = valueColumnIDs(tableDesc, family)
+	}
+	if nameSuffix != protobufSchemaNoSuffix {
+		name = name + `_` + nameSuffix
+	}
+	m := newProtobufDataMessage(name)
+	for colIdx := range tableDesc.Columns {
+		if _, ok := colIDs[tableDesc.Columns[colIdx].ID]; !ok && colIDs != nil {
+			continue
+		}
+		if err := m.addColumn(tableDesc, colIdx); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// BinaryFromRow appends the encoding of the given row to buf. Every NULL
+// column is left out of the message.
+func (m *protobufDataMessage) BinaryFromRow(buf []byte, row sqlbase.EncDatumRow) ([]byte, error) {
+	b := proto.NewBuffer(buf)
+	for _, field := range m.fields {
+		d := row[field.colIdx]
+		if err := d.EnsureDecoded(field.typ, &m.alloc); err != nil {
+			return nil, err
+		}
+		if d.Datum == tree.DNull {
+			continue
+		}
+		if err := field.encodeFn(b, d.Datum); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// protobufEnvelopeOpts controls which fields in protobufEnvelopeMessage are
+// set.
+type protobufEnvelopeOpts struct {
+	beforeField, afterField     bool
+	updatedField, resolvedField bool
+}
+
+// protobufEnvelopeMessage is the protobuf message type of changefeed values:
+// the row after and, optionally, before the change, along with metadata.
+type protobufEnvelopeMessage struct {
+	desc          *descriptor.DescriptorProto
+	opts          protobufEnvelopeOpts
+	before, after *protobufDataMessage
+}
+
+// envelopeToProtobufMessage creates a protobufEnvelopeMessage.
+func envelopeToProtobufMessage(
+	topic string, opts protobufEnvelopeOpts, before, after *protobufDataMessage,
+) *protobufEnvelopeMessage {
+	m := &protobufEnvelopeMessage{
+		desc: &descriptor.DescriptorProto{
+			Name: proto.String(SQLNameToProtobufName(topic) + `_envelope`),
+		},
+		opts:   opts,
+		before: before,
+		after:  after,
+	}
+	addField := func(name string, fieldNum int32, typ descriptor.FieldDescriptorProto_Type) {
+		field := &descriptor.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(fieldNum),
+			Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+		m.desc.Field = append(m.desc.Field, field)
+	}
+	if opts.afterField {
+		addField(`after`, protobufEnvelopeFieldAfter, descriptor.FieldDescriptorProto_TYPE_MESSAGE)
+		m.desc.Field[len(m.desc.Field)-1].TypeName = proto.String(`.` + after.desc.GetName())
+	}
+	if opts.beforeField {
+		addField(`before`, protobufEnvelopeFieldBefore, descriptor.FieldDescriptorProto_TYPE_MESSAGE)
+		m.desc.Field[len(m.desc.Field)-1].TypeName = proto.String(`.` + before.desc.GetName())
+	}
+	if opts.updatedField {
+		addField(`updated`, protobufEnvelopeFieldUpdated, descriptor.FieldDescriptorProto_TYPE_STRING)
+	}
+	if opts.resolvedField {
+		addField(`resolved`, protobufEnvelopeFieldResolved, descriptor.FieldDescriptorProto_TYPE_STRING)
+	}
+	return m
+}
+
+// messages returns the message types of the envelope, starting with the
+// envelope itself.
+func (m *protobufEnvelopeMessage) messages() []*descriptor.DescriptorProto {
+	msgs := []*descriptor.DescriptorProto{m.desc}
+	if m.opts.afterField {
+		msgs = append(msgs, m.after.desc)
+	}
+	if m.opts.beforeField {
+		msgs = append(msgs, m.before.desc)
+	}
+	return msgs
+}
+
+// BinaryFromRow appends the encoding of an envelope with the given metadata
+// and rows to buf. A nil row leaves the corresponding field out.
+func (m *protobufEnvelopeMessage) BinaryFromRow(
+	buf []byte, meta map[string]string, beforeRow, afterRow sqlbase.EncDatumRow,
+) ([]byte, error) {
+	b := proto.NewBuffer(buf)
+	encodeRow := func(fieldNum int32, msg *protobufDataMessage, row sqlbase.EncDatumRow) error {
+		encoded, err := msg.BinaryFromRow(nil /* buf */, row)
+		if err != nil {
+			return err
+		}
+		if err := b.EncodeVarint(protobufTag(fieldNum, proto.WireBytes)); err != nil {
+			return err
+		}
+		return b.EncodeRawBytes(encoded)
+	}
+	encodeString := func(fieldNum int32, s string) error {
+		if err := b.EncodeVarint(protobufTag(fieldNum, proto.WireBytes)); err != nil {
+			return err
+		}
+		return b.EncodeStringBytes(s)
+	}
+	if m.opts.afterField && afterRow != nil {
+		if err := encodeRow(protobufEnvelopeFieldAfter, m.after, afterRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.beforeField && beforeRow != nil {
+		if err := encodeRow(protobufEnvelopeFieldBefore, m.before, beforeRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.updatedField {
+		if err := encodeString(protobufEnvelopeFieldUpdated, meta[`updated`]); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.resolvedField {
+		if err := encodeString(protobufEnvelopeFieldResolved, meta[`resolved`]); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// protobufFile returns a proto2 file descriptor with the given message types.
+// The first one is the type of the messages the file is used for.
+func protobufFile(msgs ...*descriptor.DescriptorProto) *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:        proto.String(msgs[0].GetName() + `.proto`),
+		Syntax:      proto.String(`proto2`),
+		MessageType: msgs,
+	}
+}
+
+var protobufTypeNames = map[descriptor.FieldDescriptorProto_Type]string{
+	descriptor.FieldDescriptorProto_TYPE_BOOL:   `bool`,
+	descriptor.FieldDescriptorProto_TYPE_BYTES:  `bytes`,
+	descriptor.FieldDescriptorProto_TYPE_DOUBLE: `double`,
+	descriptor.FieldDescriptorProto_TYPE_INT64:  `int64`,
+	descriptor.FieldDescriptorProto_TYPE_STRING: `string`,
+}
+
+// protobufFileToText returns the `.proto` source of a file descriptor created
+// by protobufFile. This is the form schemas are registered in with a schema
+// registry.
+func protobufFileToText(file *descriptor.FileDescriptorProto) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "syntax = %q;\n", file.GetSyntax())
+	for _, msg := range file.MessageType {
+		fmt.Fprintf(&buf, "\nmessage %s {\n", msg.GetName())
+		for _, field := range msg.Field {
+			typeName := protobufTypeNames[field.GetType()]
+			if field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+				typeName = strings.TrimPrefix(field.GetTypeName(), `.`)
+			}
+			fmt.Fprintf(&buf, "  optional %s %s = %d;\n", typeName, field.GetName(), field.GetNumber())
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
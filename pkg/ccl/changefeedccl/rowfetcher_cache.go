@@ -27,15 +27,23 @@ import (
 // column families of one row) into a row.
 type rowFetcherCache struct {
 	leaseMgr *sql.LeaseManager
-	fetchers map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	fetchers map[rowFetcherKey]*row.Fetcher
 
 	a sqlbase.DatumAlloc
 }
 
+// rowFetcherKey identifies a cached Fetcher. The Fetcher decodes every column
+// of the table unless family is set, in which case it only decodes the primary
+// key columns and the columns of that column family.
+type rowFetcherKey struct {
+	tableDesc *sqlbase.ImmutableTableDescriptor
+	family    *sqlbase.ColumnFamilyDescriptor
+}
+
 func newRowFetcherCache(leaseMgr *sql.LeaseManager) *rowFetcherCache {
 	return &rowFetcherCache{
 		leaseMgr: leaseMgr,
-		fetchers: make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		fetchers: make(map[rowFetcherKey]*row.Fetcher),
 	}
 }
 
@@ -84,21 +92,46 @@ func (c *rowFetcherCache) TableDescForKey(
 func (c *rowFetcherCache) RowFetcherForTableDesc(
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 ) (*row.Fetcher, error) {
-	if rf, ok := c.fetchers[tableDesc]; ok {
+	return c.rowFetcher(rowFetcherKey{tableDesc: tableDesc})
+}
+
+// RowFetcherForColumnFamily returns a Fetcher which only decodes the primary
+// key columns and the columns of the given column family. The datums of the
+// other columns are left unset.
+func (c *rowFetcherCache) RowFetcherForColumnFamily(
+	tableDesc *sqlbase.ImmutableTableDescriptor, family *sqlbase.ColumnFamilyDescriptor,
+) (*row.Fetcher, error) {
+	return c.rowFetcher(rowFetcherKey{tableDesc: tableDesc, family: family})
+}
+
+func (c *rowFetcherCache) rowFetcher(key rowFetcherKey) (*row.Fetcher, error) {
+	if rf, ok := c.fetchers[key]; ok {
 		return rf, nil
 	}
 
-	// TODO(dan): Allow for decoding a subset of the columns.
+	tableDesc := key.tableDesc
+	var familyColIDs map[sqlbase.ColumnID]struct{}
+	if key.family != nil {
+		familyColIDs = valueColumnIDs(tableDesc.TableDesc(), key.family)
+	}
 	colIdxMap := make(map[sqlbase.ColumnID]int)
 	var valNeededForCol util.FastIntSet
 	for colIdx := range tableDesc.Columns {
-		colIdxMap[tableDesc.Columns[colIdx].ID] = colIdx
-		valNeededForCol.Add(colIdx)
+		colID := tableDesc.Columns[colIdx].ID
+		colIdxMap[colID] = colIdx
+		if _, ok := familyColIDs[colID]; ok || familyColIDs == nil {
+			valNeededForCol.Add(colIdx)
+		}
 	}
 
 	var rf row.Fetcher
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &c.a,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		&c.a,
 		row.FetcherTableArgs{
 			Spans:            tableDesc.AllIndexSpans(),
 			Desc:             tableDesc,
@@ -114,6 +147,6 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	// TODO(dan): Bound the size of the cache. Resolved notifications will let
 	// us evict anything for timestamps entirely before the notification. Then
 	// probably an LRU just in case?
-	c.fetchers[tableDesc] = &rf
+	c.fetchers[key] = &rf
 	return &rf, nil
 }
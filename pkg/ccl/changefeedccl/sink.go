@@ -14,6 +14,7 @@ import (
 	"crypto/x509"
 	gosql "database/sql"
 	"encoding/base64"
+	gojson "encoding/json"
 	"fmt"
 	"hash"
 	"hash/fnv"
@@ -45,12 +46,15 @@ import (
 // Sink is an abstraction for anything that a changefeed may emit into.
 type Sink interface {
 	// EmitRow enqueues a row message for asynchronous delivery on the sink. An
-	// error may be returned if a previously enqueued message has failed.
+	// error may be returned if a previously enqueued message has failed. If
+	// partitionKey is set, sinks that partition their messages use it instead
+	// of the key to pick the partition of this one.
 	EmitRow(
 		ctx context.Context,
 		table *sqlbase.TableDescriptor,
 		key, value []byte,
 		updated hlc.Timestamp,
+		partitionKey []byte,
 	) error
 	// EmitResolvedTimestamp enqueues a resolved timestamp message for
 	// asynchronous delivery on every topic that has been seen by EmitRow. An
@@ -78,6 +82,17 @@ func getSink(
 	}
 	q := u.Query()
 
+	if _, ok := opts[optWebhookHeaders]; ok && u.Scheme != sinkSchemeWebhookHTTPS {
+		return nil, errors.Errorf(`this sink is incompatible with option %s`, optWebhookHeaders)
+	}
+	if u.Scheme != sinkSchemeKafka {
+		for _, opt := range []string{optKafkaPartitionColumn, optKafkaPartitioner, optKafkaSinkConfig} {
+			if _, ok := opts[opt]; ok {
+				return nil, errors.Errorf(`this sink is incompatible with option %s`, opt)
+			}
+		}
+	}
+
 	// Use a function here to delay creation of the sink until after we've done
 	// all the parameter verification.
 	var makeSink func() (Sink, error)
@@ -88,6 +103,17 @@ func getSink(
 		var cfg kafkaSinkConfig
 		cfg.kafkaTopicPrefix = q.Get(sinkParamTopicPrefix)
 		q.Del(sinkParamTopicPrefix)
+		cfg.topicTemplate = q.Get(sinkParamTopicTemplate)
+		q.Del(sinkParamTopicTemplate)
+		if err := validateKafkaTopicTemplate(cfg.topicTemplate); err != nil {
+			return nil, err
+		}
+		cfg.partitioner = kafkaPartitionerType(opts[optKafkaPartitioner])
+		if sinkConfig, ok := opts[optKafkaSinkConfig]; ok {
+			if cfg.saramaConfig, err = parseSaramaConfig(sinkConfig); err != nil {
+				return nil, err
+			}
+		}
 		if schemaTopic := q.Get(sinkParamSchemaTopic); schemaTopic != `` {
 			return nil, errors.Errorf(`%s is not yet supported`, sinkParamSchemaTopic)
 		}
@@ -174,6 +200,41 @@ func getSink(
 		makeSink = func() (Sink, error) {
 			return makeCloudStorageSink(u.String(), nodeID, fileSize, settings, opts)
 		}
+	case u.Scheme == sinkSchemeWebhookHTTPS:
+		var cfg webhookSinkConfig
+		if batchSize := q.Get(sinkParamBatchSize); batchSize != `` {
+			if cfg.batchSize, err = strconv.Atoi(batchSize); err != nil || cfg.batchSize < 1 {
+				return nil, errors.Errorf(`param %s must be a positive integer: %s`, sinkParamBatchSize, batchSize)
+			}
+		}
+		q.Del(sinkParamBatchSize)
+		for _, param := range []struct {
+			name string
+			dest *[]byte
+		}{
+			{sinkParamCACert, &cfg.caCert},
+			{sinkParamClientCert, &cfg.clientCert},
+			{sinkParamClientKey, &cfg.clientKey},
+		} {
+			if value := q.Get(param.name); value != `` {
+				if *param.dest, err = base64.StdEncoding.DecodeString(value); err != nil {
+					return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, param.name, err)
+				}
+			}
+			q.Del(param.name)
+		}
+		if headers, ok := opts[optWebhookHeaders]; ok {
+			if cfg.headers, err = parseWebhookHeaders(headers); err != nil {
+				return nil, err
+			}
+		}
+		u.Scheme = strings.TrimPrefix(u.Scheme, `webhook-`)
+		// Any remaining query parameters are part of the endpoint.
+		u.RawQuery = q.Encode()
+		q = url.Values{}
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(u, cfg, opts)
+		}
 	case u.Scheme == sinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -213,9 +274,13 @@ type errorWrapperSink struct {
 }
 
 func (s errorWrapperSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, updated hlc.Timestamp,
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	updated hlc.Timestamp,
+	partitionKey []byte,
 ) error {
-	if err := s.wrapped.EmitRow(ctx, table, key, value, updated); err != nil {
+	if err := s.wrapped.EmitRow(ctx, table, key, value, updated, partitionKey); err != nil {
 		return MarkRetryableError(err)
 	}
 	return nil
@@ -270,6 +335,9 @@ func init() {
 
 type kafkaSinkConfig struct {
 	kafkaTopicPrefix string
+	topicTemplate    string
+	partitioner      kafkaPartitionerType
+	saramaConfig     *saramaConfig
 	tlsEnabled       bool
 	caCert           []byte
 	saslEnabled      bool
@@ -278,6 +346,134 @@ type kafkaSinkConfig struct {
 	saslPassword     string
 }
 
+const (
+	kafkaTopicTemplateDatabase = `{database}`
+	kafkaTopicTemplateTable    = `{table}`
+)
+
+// validateKafkaTopicTemplate returns an error if the given topic template has
+// placeholders other than `{database}` and `{table}`.
+func validateKafkaTopicTemplate(template string) error {
+	stripped := strings.NewReplacer(
+		kafkaTopicTemplateDatabase, ``, kafkaTopicTemplateTable, ``,
+	).Replace(template)
+	if strings.ContainsAny(stripped, `{}`) {
+		return errors.Errorf(`param %s must only use the placeholders %s and %s: %s`,
+			sinkParamTopicTemplate, kafkaTopicTemplateDatabase, kafkaTopicTemplateTable, template)
+	}
+	return nil
+}
+
+// topicName returns the name of the kafka topic for a table. If the sink has a
+// topic template, its `{database}` and `{table}` placeholders are replaced with
+// the escaped names of the database and the table. Otherwise, the topic is
+// named after the table. Either way, the topic prefix of the sink comes first.
+func (c *kafkaSinkConfig) topicName(databaseName, tableName string) string {
+	if c.topicTemplate == `` {
+		return c.kafkaTopicPrefix + SQLNameToKafkaName(tableName)
+	}
+	return c.kafkaTopicPrefix + strings.NewReplacer(
+		kafkaTopicTemplateDatabase, SQLNameToKafkaName(databaseName),
+		kafkaTopicTemplateTable, SQLNameToKafkaName(tableName),
+	).Replace(c.topicTemplate)
+}
+
+// saramaConfig is the JSON form of the kafka_sink_config option, which
+// overrides some of the producer settings of the kafka sink. For example:
+//
+//	{"Flush": {"Bytes": 65536, "Frequency": "10ms"}, "RequiredAcks": "ALL", "Compression": "GZIP"}
+type saramaConfig struct {
+	Flush struct {
+		// Bytes, Messages and Frequency replace the default flush triggers of
+		// the sink, which sends out buffered messages as soon as there are any.
+		// Because the sink has no way to make the producer send what it has
+		// buffered when the changefeed flushes, Frequency is required when
+		// either of the others is set.
+		Bytes     int
+		Messages  int
+		Frequency string
+		// MaxMessages is the most messages the producer sends in one request.
+		MaxMessages int
+	}
+	// RequiredAcks is one of NONE (or 0), ONE (or 1) and ALL (or -1).
+	RequiredAcks string
+	// Compression is one of NONE, GZIP, SNAPPY and LZ4.
+	Compression string
+
+	frequency    time.Duration
+	requiredAcks sarama.RequiredAcks
+	compression  sarama.CompressionCodec
+}
+
+// parseSaramaConfig parses and validates the value of the kafka_sink_config
+// option.
+func parseSaramaConfig(s string) (*saramaConfig, error) {
+	c := &saramaConfig{}
+	dec := gojson.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, errors.Wrapf(err, `option %s is not valid`, optKafkaSinkConfig)
+	}
+	if c.Flush.Bytes < 0 || c.Flush.Messages < 0 || c.Flush.MaxMessages < 0 {
+		return nil, errors.Errorf(`option %s must not have negative flush settings: %s`,
+			optKafkaSinkConfig, s)
+	}
+	if c.Flush.Frequency != `` {
+		var err error
+		if c.frequency, err = time.ParseDuration(c.Flush.Frequency); err != nil {
+			return nil, errors.Wrapf(err, `option %s has an invalid Flush.Frequency`, optKafkaSinkConfig)
+		}
+		if c.frequency <= 0 {
+			return nil, errors.Errorf(`option %s must have a positive Flush.Frequency: %s`,
+				optKafkaSinkConfig, c.Flush.Frequency)
+		}
+	} else if c.Flush.Bytes > 0 || c.Flush.Messages > 0 {
+		return nil, errors.Errorf(`option %s requires Flush.Frequency with Flush.Bytes or Flush.Messages`,
+			optKafkaSinkConfig)
+	}
+
+	switch strings.ToUpper(c.RequiredAcks) {
+	case ``, `ONE`, `1`:
+		c.requiredAcks = sarama.WaitForLocal
+	case `NONE`, `0`:
+		c.requiredAcks = sarama.NoResponse
+	case `ALL`, `-1`:
+		c.requiredAcks = sarama.WaitForAll
+	default:
+		return nil, errors.Errorf(`option %s has an unknown RequiredAcks: %s`,
+			optKafkaSinkConfig, c.RequiredAcks)
+	}
+
+	switch strings.ToUpper(c.Compression) {
+	case ``, `NONE`:
+		c.compression = sarama.CompressionNone
+	case `GZIP`:
+		c.compression = sarama.CompressionGZIP
+	case `SNAPPY`:
+		c.compression = sarama.CompressionSnappy
+	case `LZ4`:
+		c.compression = sarama.CompressionLZ4
+	default:
+		return nil, errors.Errorf(`option %s has an unknown Compression: %s`,
+			optKafkaSinkConfig, c.Compression)
+	}
+	return c, nil
+}
+
+// apply overrides the producer settings of the given sarama config.
+func (c *saramaConfig) apply(config *sarama.Config) {
+	if c.frequency > 0 {
+		config.Producer.Flush.Bytes = c.Flush.Bytes
+		config.Producer.Flush.Messages = c.Flush.Messages
+		config.Producer.Flush.Frequency = c.frequency
+	}
+	if c.Flush.MaxMessages > 0 {
+		config.Producer.Flush.MaxMessages = c.Flush.MaxMessages
+	}
+	config.Producer.RequiredAcks = c.requiredAcks
+	config.Producer.Compression = c.compression
+}
+
 // kafkaSink emits to Kafka asynchronously. It is not concurrency-safe; all
 // calls to Emit and Flush should be from the same goroutine.
 type kafkaSink struct {
@@ -285,6 +481,9 @@ type kafkaSink struct {
 	client   sarama.Client
 	producer sarama.AsyncProducer
 	topics   map[string]struct{}
+	// databaseNames maps the ID of each watched table to the name of its
+	// database, for topic templates.
+	databaseNames map[sqlbase.ID]string
 
 	lastMetadataRefresh time.Time
 
@@ -306,14 +505,20 @@ func makeKafkaSink(
 ) (Sink, error) {
 	sink := &kafkaSink{cfg: cfg}
 	sink.topics = make(map[string]struct{})
-	for _, t := range targets {
-		sink.topics[cfg.kafkaTopicPrefix+SQLNameToKafkaName(t.StatementTimeName)] = struct{}{}
+	sink.databaseNames = make(map[sqlbase.ID]string, len(targets))
+	for id, t := range targets {
+		sink.topics[cfg.topicName(t.StatementTimeDatabaseName, t.StatementTimeName)] = struct{}{}
+		sink.databaseNames[id] = t.StatementTimeDatabaseName
 	}
 
 	config := sarama.NewConfig()
 	config.ClientID = `CockroachDB`
 	config.Producer.Return.Successes = true
-	config.Producer.Partitioner = newChangefeedPartitioner
+	if cfg.partitioner == optKafkaPartitionerRoundRobin {
+		config.Producer.Partitioner = newChangefeedRoundRobinPartitioner
+	} else {
+		config.Producer.Partitioner = newChangefeedPartitioner
+	}
 
 	if cfg.caCert != nil {
 		if !cfg.tlsEnabled {
@@ -371,6 +576,10 @@ func makeKafkaSink(
 	// sarama prints scary things to the logs if we don't.
 	config.Producer.Flush.Frequency = time.Hour
 
+	if cfg.saramaConfig != nil {
+		cfg.saramaConfig.apply(config)
+	}
+
 	var err error
 	sink.client, err = sarama.NewClient(strings.Split(bootstrapServers, `,`), config)
 	if err != nil {
@@ -412,9 +621,13 @@ func (s *kafkaSink) Close() error {
 
 // EmitRow implements the Sink interface.
 func (s *kafkaSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	_ hlc.Timestamp,
+	partitionKey []byte,
 ) error {
-	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.Name)
+	topic := s.cfg.topicName(s.databaseNames[table.ID], table.Name)
 	if _, ok := s.topics[topic]; !ok {
 		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
 	}
@@ -424,6 +637,10 @@ func (s *kafkaSink) EmitRow(
 		Key:   sarama.ByteEncoder(key),
 		Value: sarama.ByteEncoder(value),
 	}
+	if partitionKey != nil {
+		// changefeedPartitioner hashes this instead of the key.
+		msg.Metadata = partitionKeyMetadata(partitionKey)
+	}
 	return s.emitMessage(ctx, msg)
 }
 
@@ -557,27 +774,73 @@ func (s *kafkaSink) workerLoop() {
 	}
 }
 
+// partitionColumnIdx returns the index of the given primary key column of a
+// table, which is the column used to partition its rows with the
+// kafka_partition_column option.
+func partitionColumnIdx(tableDesc *sqlbase.TableDescriptor, column string) (int, error) {
+	col, err := tableDesc.FindActiveColumnByName(column)
+	if err != nil {
+		return 0, errors.Wrapf(err, `%s`, optKafkaPartitionColumn)
+	}
+	for _, colID := range tableDesc.PrimaryIndex.ColumnIDs {
+		if colID == col.ID {
+			return tableDesc.ColumnIdxMap()[col.ID], nil
+		}
+	}
+	return 0, errors.Errorf(`%s must be a primary key column of %s: %s`,
+		optKafkaPartitionColumn, tableDesc.Name, column)
+}
+
+// partitionKeyMetadata is the sarama.ProducerMessage metadata of row messages
+// that are partitioned by something other than their key.
+type partitionKeyMetadata []byte
+
+// changefeedPartitioner sends resolved timestamp messages, which have no key,
+// to the partition they were created for. Row messages are partitioned by the
+// wrapped partitioner.
 type changefeedPartitioner struct {
-	hash sarama.Partitioner
+	rows sarama.Partitioner
 }
 
 var _ sarama.Partitioner = &changefeedPartitioner{}
 var _ sarama.PartitionerConstructor = newChangefeedPartitioner
+var _ sarama.PartitionerConstructor = newChangefeedRoundRobinPartitioner
 
+// newChangefeedPartitioner partitions row messages by the hash of their key,
+// or of their partition key if they have one. This keeps all the changes to a
+// row (or to rows with the same partition key) in order in one partition.
 func newChangefeedPartitioner(topic string) sarama.Partitioner {
 	return &changefeedPartitioner{
-		hash: sarama.NewHashPartitioner(topic),
+		rows: sarama.NewHashPartitioner(topic),
+	}
+}
+
+// newChangefeedRoundRobinPartitioner spreads row messages evenly over the
+// partitions of a topic. Changes to the same row may be in different
+// partitions, so consumers can see them out of order.
+func newChangefeedRoundRobinPartitioner(topic string) sarama.Partitioner {
+	return &changefeedPartitioner{
+		rows: sarama.NewRoundRobinPartitioner(topic),
 	}
 }
 
-func (p *changefeedPartitioner) RequiresConsistency() bool { return true }
+func (p *changefeedPartitioner) RequiresConsistency() bool {
+	return p.rows.RequiresConsistency()
+}
 func (p *changefeedPartitioner) Partition(
 	message *sarama.ProducerMessage, numPartitions int32,
 ) (int32, error) {
 	if message.Key == nil {
 		return message.Partition, nil
 	}
-	return p.hash.Partition(message, numPartitions)
+	if partitionKey, ok := message.Metadata.(partitionKeyMetadata); ok {
+		// Only the hash of the key is used by the hash partitioner, so hash a
+		// shallow copy of the message with the key swapped out.
+		keyed := *message
+		keyed.Key = sarama.ByteEncoder(partitionKey)
+		return p.rows.Partition(&keyed, numPartitions)
+	}
+	return p.rows.Partition(message, numPartitions)
 }
 
 const (
@@ -646,7 +909,11 @@ func makeSQLSink(uri, tableName string, targets jobspb.ChangefeedTargets) (*sqlS
 
 // EmitRow implements the Sink interface.
 func (s *sqlSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	_ hlc.Timestamp,
+	_ []byte,
 ) error {
 	topic := table.Name
 	if _, ok := s.topics[topic]; !ok {
@@ -760,7 +1027,11 @@ type bufferSink struct {
 
 // EmitRow implements the Sink interface.
 func (s *bufferSink) EmitRow(
-	_ context.Context, table *sqlbase.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+	_ context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	_ hlc.Timestamp,
+	_ []byte,
 ) error {
 	if s.closed {
 		return errors.New(`cannot EmitRow on a closed sink`)
@@ -170,7 +170,11 @@ func makeCloudStorageSink(
 
 // EmitRow implements the Sink interface.
 func (s *cloudStorageSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, _, value []byte, updated hlc.Timestamp,
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	_, value []byte,
+	updated hlc.Timestamp,
+	_ []byte,
 ) error {
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
@@ -77,7 +77,7 @@ func TestCloudStorageSink(t *testing.T) {
 		require.NoError(t, err)
 		s.(*cloudStorageSink).sinkID = 7 // Force a deterministic sinkID.
 
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.NoError(t, s.EmitResolvedTimestamp(ctx, e, ts(5)))
 
@@ -105,9 +105,9 @@ func TestCloudStorageSink(t *testing.T) {
 		require.Equal(t, []string(nil), slurpDir(t, dir))
 
 		// Emitting rows and flushing should write them out in one file per table.
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v2`), ts(1)))
-		require.NoError(t, s.EmitRow(ctx, t2, noKey, []byte(`w1`), ts(1)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v2`), ts(1), noPartitionKey))
+		require.NoError(t, s.EmitRow(ctx, t2, noKey, []byte(`w1`), ts(1), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.Equal(t, []string{
 			"v1\nv2\n",
@@ -122,7 +122,7 @@ func TestCloudStorageSink(t *testing.T) {
 		}, slurpDir(t, dir))
 
 		// Without a flush, nothing new shows up.
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v3`), ts(3)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v3`), ts(3), noPartitionKey))
 		require.Equal(t, []string{
 			"v1\nv2\n",
 			"w1\n",
@@ -139,9 +139,9 @@ func TestCloudStorageSink(t *testing.T) {
 		// Data from different versions of a table is put in different files, so
 		// that we can guarantee that all rows in any given file have the same
 		// schema.
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v4`), ts(4)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v4`), ts(4), noPartitionKey))
 		t1.Version = 2
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v5`), ts(5)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v5`), ts(5), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.Equal(t, []string{
 			"v1\nv2\n",
@@ -167,8 +167,8 @@ func TestCloudStorageSink(t *testing.T) {
 
 		// Each node writes some data at the same timestamp. When this data is
 		// written out, the files have different names and don't conflict.
-		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
-		require.NoError(t, s2.EmitRow(ctx, t1, noKey, []byte(`w1`), ts(1)))
+		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
+		require.NoError(t, s2.EmitRow(ctx, t1, noKey, []byte(`w1`), ts(1), noPartitionKey))
 		require.NoError(t, s1.Flush(ctx))
 		require.NoError(t, s2.Flush(ctx))
 		require.Equal(t, []string{
@@ -190,8 +190,8 @@ func TestCloudStorageSink(t *testing.T) {
 		s1R.(*cloudStorageSink).sinkID = 0
 		s2R.(*cloudStorageSink).sinkID = 7
 		// Each resends the data it did before.
-		require.NoError(t, s1R.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
-		require.NoError(t, s2R.EmitRow(ctx, t1, noKey, []byte(`w1`), ts(1)))
+		require.NoError(t, s1R.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
+		require.NoError(t, s2R.EmitRow(ctx, t1, noKey, []byte(`w1`), ts(1), noPartitionKey))
 		require.NoError(t, s1R.Flush(ctx))
 		require.NoError(t, s2R.Flush(ctx))
 		// The s1 data overwrites the old file, the s2 data ends up duplicated.
@@ -221,17 +221,17 @@ func TestCloudStorageSink(t *testing.T) {
 		s2.(*cloudStorageSink).sinkID = 8 // Force a deterministic sinkID.
 
 		// Good job writes
-		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
-		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v2`), ts(2)))
+		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
+		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v2`), ts(2), noPartitionKey))
 		require.NoError(t, s1.Flush(ctx))
 
 		// Zombie job writes partial duplicate data
-		require.NoError(t, s2.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1)))
+		require.NoError(t, s2.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), noPartitionKey))
 		require.NoError(t, s2.Flush(ctx))
 
 		// Good job continues. There are duplicates in the data but nothing was
 		// lost.
-		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v3`), ts(3)))
+		require.NoError(t, s1.EmitRow(ctx, t1, noKey, []byte(`v3`), ts(3), noPartitionKey))
 		require.NoError(t, s1.Flush(ctx))
 		require.Equal(t, []string{
 			"v1\nv2\n",
@@ -252,7 +252,8 @@ func TestCloudStorageSink(t *testing.T) {
 		// Writing more than the max file size chunks the file up and flushes it
 		// out as necessary.
 		for i := int64(1); i <= 5; i++ {
-			require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(fmt.Sprintf(`v%d`, i)), ts(i)))
+			require.NoError(t, s.EmitRow(
+				ctx, t1, noKey, []byte(fmt.Sprintf(`v%d`, i)), ts(i), noPartitionKey))
 		}
 		require.Equal(t, []string{
 			"v1\nv2\nv3\n",
@@ -268,7 +269,8 @@ func TestCloudStorageSink(t *testing.T) {
 		// Some more data is written. Some of it flushed out because of the max
 		// file size.
 		for i := int64(6); i < 10; i++ {
-			require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(fmt.Sprintf(`v%d`, i)), ts(i)))
+			require.NoError(t, s.EmitRow(
+				ctx, t1, noKey, []byte(fmt.Sprintf(`v%d`, i)), ts(i), noPartitionKey))
 		}
 		require.Equal(t, []string{
 			"v1\nv2\nv3\n",
@@ -312,18 +314,18 @@ func TestCloudStorageSink(t *testing.T) {
 
 		// Simulate initial scan, which emits data at a timestamp, then an equal
 		// resolved timestamp.
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`is1`), ts(1)))
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`is2`), ts(1)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`is1`), ts(1), noPartitionKey))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`is2`), ts(1), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.NoError(t, s.EmitResolvedTimestamp(ctx, e, ts(1)))
 
 		// Test some edge cases.
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e2`), ts(2)))
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3prev`), ts(3).Prev()))
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3`), ts(3)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e2`), ts(2), noPartitionKey))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3prev`), ts(3).Prev(), noPartitionKey))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3`), ts(3), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.NoError(t, s.EmitResolvedTimestamp(ctx, e, ts(3)))
-		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3next`), ts(3).Next()))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`e3next`), ts(3).Next(), noPartitionKey))
 		require.NoError(t, s.Flush(ctx))
 		require.NoError(t, s.EmitResolvedTimestamp(ctx, e, ts(4)))
 
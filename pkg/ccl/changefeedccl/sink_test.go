@@ -30,6 +30,7 @@ import (
 )
 
 var zeroTS hlc.Timestamp
+var noPartitionKey []byte
 
 type asyncProducerMock struct {
 	inputCh     chan *sarama.ProducerMessage
@@ -78,7 +79,7 @@ func TestKafkaSink(t *testing.T) {
 	}
 
 	// Timeout
-	if err := sink.EmitRow(ctx, table(`t`), []byte(`1`), nil, zeroTS); err != nil {
+	if err := sink.EmitRow(ctx, table(`t`), []byte(`1`), nil, zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m1 := <-p.inputCh
@@ -102,15 +103,15 @@ func TestKafkaSink(t *testing.T) {
 	}
 
 	// Mixed success and error.
-	if err := sink.EmitRow(ctx, table(`t`), []byte(`2`), nil, zeroTS); err != nil {
+	if err := sink.EmitRow(ctx, table(`t`), []byte(`2`), nil, zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m2 := <-p.inputCh
-	if err := sink.EmitRow(ctx, table(`t`), []byte(`3`), nil, zeroTS); err != nil {
+	if err := sink.EmitRow(ctx, table(`t`), []byte(`3`), nil, zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m3 := <-p.inputCh
-	if err := sink.EmitRow(ctx, table(`t`), []byte(`4`), nil, zeroTS); err != nil {
+	if err := sink.EmitRow(ctx, table(`t`), []byte(`4`), nil, zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m4 := <-p.inputCh
@@ -127,7 +128,7 @@ func TestKafkaSink(t *testing.T) {
 	}
 
 	// Check simple success again after error
-	if err := sink.EmitRow(ctx, table(`t`), []byte(`5`), nil, zeroTS); err != nil {
+	if err := sink.EmitRow(ctx, table(`t`), []byte(`5`), nil, zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m5 := <-p.inputCh
@@ -156,7 +157,8 @@ func TestKafkaSinkEscaping(t *testing.T) {
 	}
 	sink.start()
 	defer func() { require.NoError(t, sink.Close()) }()
-	if err := sink.EmitRow(ctx, table(`☃`), []byte(`k☃`), []byte(`v☃`), zeroTS); err != nil {
+	if err := sink.EmitRow(
+		ctx, table(`☃`), []byte(`k☃`), []byte(`v☃`), zeroTS, noPartitionKey); err != nil {
 		t.Fatal(err)
 	}
 	m := <-p.inputCh
@@ -165,6 +167,174 @@ func TestKafkaSinkEscaping(t *testing.T) {
 	require.Equal(t, sarama.ByteEncoder(`v☃`), m.Value)
 }
 
+func TestKafkaSinkTopicTemplate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	p := asyncProducerMock{
+		inputCh:     make(chan *sarama.ProducerMessage, 1),
+		successesCh: make(chan *sarama.ProducerMessage, 1),
+		errorsCh:    make(chan *sarama.ProducerError, 1),
+	}
+	cfg := kafkaSinkConfig{kafkaTopicPrefix: `cdc_`, topicTemplate: `{database}.{table}`}
+	sink := &kafkaSink{
+		cfg:           cfg,
+		producer:      p,
+		topics:        map[string]struct{}{`cdc_d.foo`: {}, `cdc__u2603_.bar`: {}},
+		databaseNames: map[sqlbase.ID]string{1: `d`, 2: `☃`},
+	}
+	sink.start()
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	require.NoError(t, sink.EmitRow(
+		ctx, &sqlbase.TableDescriptor{ID: 1, Name: `foo`}, []byte(`k`), []byte(`v`), zeroTS,
+		noPartitionKey))
+	require.Equal(t, `cdc_d.foo`, (<-p.inputCh).Topic)
+	require.NoError(t, sink.EmitRow(
+		ctx, &sqlbase.TableDescriptor{ID: 2, Name: `bar`}, []byte(`k`), []byte(`v`), zeroTS,
+		noPartitionKey))
+	require.Equal(t, `cdc__u2603_.bar`, (<-p.inputCh).Topic)
+
+	require.NoError(t, validateKafkaTopicTemplate(`{table}-{database}-{table}`))
+	require.EqualError(t, validateKafkaTopicTemplate(`{database}.{schema}.{table}`),
+		`param topic_template must only use the placeholders {database} and {table}: `+
+			`{database}.{schema}.{table}`)
+}
+
+func TestChangefeedPartitioner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numPartitions = 10
+	partition := func(p sarama.Partitioner, msg *sarama.ProducerMessage) int32 {
+		t.Helper()
+		partition, err := p.Partition(msg, numPartitions)
+		require.NoError(t, err)
+		return partition
+	}
+	keyed := func(key, partitionKey string) *sarama.ProducerMessage {
+		msg := &sarama.ProducerMessage{Key: sarama.StringEncoder(key)}
+		if partitionKey != `` {
+			msg.Metadata = partitionKeyMetadata(partitionKey)
+		}
+		return msg
+	}
+
+	// Resolved timestamps go to the partition they were created for.
+	for _, newPartitioner := range []sarama.PartitionerConstructor{
+		newChangefeedPartitioner, newChangefeedRoundRobinPartitioner,
+	} {
+		p := newPartitioner(`t`)
+		require.Equal(t, int32(7), partition(p, &sarama.ProducerMessage{Partition: 7}))
+	}
+
+	// Rows are hashed by their partition key, if they have one.
+	hash := newChangefeedPartitioner(`t`)
+	require.True(t, hash.RequiresConsistency())
+	require.Equal(t, partition(hash, keyed(`a`, ``)), partition(hash, keyed(`b`, `a`)))
+	for i := 0; i < numPartitions; i++ {
+		k := strconv.Itoa(i)
+		require.Equal(t, partition(hash, keyed(k, `x`)), partition(hash, keyed(`y`, `x`)))
+	}
+
+	// Round robin ignores the key.
+	roundRobin := newChangefeedRoundRobinPartitioner(`t`)
+	require.False(t, roundRobin.RequiresConsistency())
+	for i := 0; i < 2*numPartitions; i++ {
+		require.Equal(t, int32(i%numPartitions), partition(roundRobin, keyed(`a`, ``)))
+	}
+}
+
+func TestSaramaConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c, err := parseSaramaConfig(
+		`{"Flush": {"Bytes": 1024, "Frequency": "10ms", "MaxMessages": 10}, ` +
+			`"RequiredAcks": "all", "Compression": "GZIP"}`)
+	require.NoError(t, err)
+	config := sarama.NewConfig()
+	config.Producer.Flush.Messages = 1
+	config.Producer.Flush.MaxMessages = 1000
+	c.apply(config)
+	require.Equal(t, 1024, config.Producer.Flush.Bytes)
+	require.Equal(t, 0, config.Producer.Flush.Messages)
+	require.Equal(t, 10*time.Millisecond, config.Producer.Flush.Frequency)
+	require.Equal(t, 10, config.Producer.Flush.MaxMessages)
+	require.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+	require.Equal(t, sarama.CompressionGZIP, config.Producer.Compression)
+	require.NoError(t, config.Validate())
+
+	// Only the settings that are given are overridden.
+	c, err = parseSaramaConfig(`{"RequiredAcks": "0"}`)
+	require.NoError(t, err)
+	config = sarama.NewConfig()
+	config.Producer.Flush.Messages = 1
+	c.apply(config)
+	require.Equal(t, 1, config.Producer.Flush.Messages)
+	require.Equal(t, sarama.NoResponse, config.Producer.RequiredAcks)
+
+	for input, expected := range map[string]string{
+		`nope`:                               `option kafka_sink_config is not valid`,
+		`{"Acks": "ALL"}`:                    `option kafka_sink_config is not valid`,
+		`{"RequiredAcks": "SOME"}`:           `unknown RequiredAcks: SOME`,
+		`{"Compression": "BZIP"}`:            `unknown Compression: BZIP`,
+		`{"Flush": {"Bytes": 1}}`:            `requires Flush.Frequency`,
+		`{"Flush": {"Frequency": "soon"}}`:   `invalid Flush.Frequency`,
+		`{"Flush": {"Frequency": "-1s"}}`:    `must have a positive Flush.Frequency`,
+		`{"Flush": {"MaxMessages": -1}}`:     `must not have negative flush settings`,
+		`{"Flush": {"Messages": 1.5}}`:       `option kafka_sink_config is not valid`,
+		`{"RequiredAcks": "ALL", "x": true}`: `option kafka_sink_config is not valid`,
+	} {
+		_, err := parseSaramaConfig(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), expected, input)
+	}
+}
+
+// TestKafkaSinkMockBroker runs a kafka sink with a topic template and producer
+// settings against a mock kafka broker.
+func TestKafkaSinkMockBroker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	broker := sarama.NewMockBroker(t, 1 /* brokerID */)
+	defer broker.Close()
+	// Only the templated topic exists, so emitting to any other one fails.
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		`MetadataRequest`: sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(`d.foo`, 0, broker.BrokerID()).
+			SetLeader(`d.foo`, 1, broker.BrokerID()),
+		`ProduceRequest`: sarama.NewMockProduceResponse(t),
+	})
+
+	var cfg kafkaSinkConfig
+	cfg.topicTemplate = `{database}.{table}`
+	var err error
+	cfg.saramaConfig, err = parseSaramaConfig(`{"RequiredAcks": "ALL", "Compression": "GZIP"}`)
+	require.NoError(t, err)
+	targets := jobspb.ChangefeedTargets{
+		1: jobspb.ChangefeedTarget{StatementTimeName: `foo`, StatementTimeDatabaseName: `d`},
+	}
+	sink, err := makeKafkaSink(cfg, broker.Addr(), targets)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	table := &sqlbase.TableDescriptor{ID: 1, Name: `foo`}
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`k1`), []byte(`v1`), zeroTS, noPartitionKey))
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`k2`), []byte(`v2`), zeroTS, []byte(`p`)))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, hlc.Timestamp{WallTime: 1}))
+	require.NoError(t, sink.Flush(ctx))
+
+	var produced int
+	for _, rr := range broker.History() {
+		if req, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			require.Equal(t, sarama.WaitForAll, req.RequiredAcks)
+			produced++
+		}
+	}
+	require.True(t, produced > 0, `expected produce requests`)
+}
+
 type testEncoder struct{}
 
 func (testEncoder) EncodeKey(encodeRow) ([]byte, error)   { panic(`unimplemented`) }
@@ -203,10 +373,12 @@ func TestSQLSink(t *testing.T) {
 
 	// Undeclared topic
 	require.EqualError(t,
-		sink.EmitRow(ctx, table(`nope`), nil, nil, zeroTS), `cannot emit to undeclared topic: nope`)
+		sink.EmitRow(ctx, table(`nope`), nil, nil, zeroTS, noPartitionKey),
+		`cannot emit to undeclared topic: nope`)
 
 	// With one row, nothing flushes until Flush is called.
-	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`k1`), []byte(`v0`), zeroTS))
+	require.NoError(t, sink.EmitRow(
+		ctx, table(`foo`), []byte(`k1`), []byte(`v0`), zeroTS, noPartitionKey))
 	sqlDB.CheckQueryResults(t, `SELECT key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{},
 	)
@@ -219,8 +391,8 @@ func TestSQLSink(t *testing.T) {
 	// Verify the implicit flushing
 	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM sink`, [][]string{{`0`}})
 	for i := 0; i < sqlSinkRowBatchSize+1; i++ {
-		require.NoError(t,
-			sink.EmitRow(ctx, table(`foo`), []byte(`k1`), []byte(`v`+strconv.Itoa(i)), zeroTS))
+		require.NoError(t, sink.EmitRow(
+			ctx, table(`foo`), []byte(`k1`), []byte(`v`+strconv.Itoa(i)), zeroTS, noPartitionKey))
 	}
 	// Should have auto flushed after sqlSinkRowBatchSize
 	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM sink`, [][]string{{`3`}})
@@ -229,9 +401,12 @@ func TestSQLSink(t *testing.T) {
 	sqlDB.Exec(t, `TRUNCATE sink`)
 
 	// Two tables interleaved in time
-	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`kfoo`), []byte(`v0`), zeroTS))
-	require.NoError(t, sink.EmitRow(ctx, table(`bar`), []byte(`kbar`), []byte(`v0`), zeroTS))
-	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`kfoo`), []byte(`v1`), zeroTS))
+	require.NoError(t, sink.EmitRow(
+		ctx, table(`foo`), []byte(`kfoo`), []byte(`v0`), zeroTS, noPartitionKey))
+	require.NoError(t, sink.EmitRow(
+		ctx, table(`bar`), []byte(`kbar`), []byte(`v0`), zeroTS, noPartitionKey))
+	require.NoError(t, sink.EmitRow(
+		ctx, table(`foo`), []byte(`kfoo`), []byte(`v1`), zeroTS, noPartitionKey))
 	require.NoError(t, sink.Flush(ctx))
 	sqlDB.CheckQueryResults(t, `SELECT topic, key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{{`bar`, `kbar`, `v0`}, {`foo`, `kfoo`, `v0`}, {`foo`, `kfoo`, `v1`}},
@@ -241,12 +416,12 @@ func TestSQLSink(t *testing.T) {
 	// Multiple keys interleaved in time. Use sqlSinkNumPartitions+1 keys to
 	// guarantee that at lease two of them end up in the same partition.
 	for i := 0; i < sqlSinkNumPartitions+1; i++ {
-		require.NoError(t,
-			sink.EmitRow(ctx, table(`foo`), []byte(`v`+strconv.Itoa(i)), []byte(`v0`), zeroTS))
+		require.NoError(t, sink.EmitRow(
+			ctx, table(`foo`), []byte(`v`+strconv.Itoa(i)), []byte(`v0`), zeroTS, noPartitionKey))
 	}
 	for i := 0; i < sqlSinkNumPartitions+1; i++ {
-		require.NoError(t,
-			sink.EmitRow(ctx, table(`foo`), []byte(`v`+strconv.Itoa(i)), []byte(`v1`), zeroTS))
+		require.NoError(t, sink.EmitRow(
+			ctx, table(`foo`), []byte(`v`+strconv.Itoa(i)), []byte(`v1`), zeroTS, noPartitionKey))
 	}
 	require.NoError(t, sink.Flush(ctx))
 	sqlDB.CheckQueryResults(t, `SELECT partition, key, value FROM sink ORDER BY PRIMARY KEY sink`,
@@ -265,8 +440,9 @@ func TestSQLSink(t *testing.T) {
 
 	// Emit resolved
 	var e testEncoder
-	require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, zeroTS))
-	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`foo0`), []byte(`v0`), zeroTS))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, zeroTS, noPartitionKey))
+	require.NoError(t, sink.EmitRow(
+		ctx, table(`foo`), []byte(`foo0`), []byte(`v0`), zeroTS, noPartitionKey))
 	require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, hlc.Timestamp{WallTime: 1}))
 	require.NoError(t, sink.Flush(ctx))
 	sqlDB.CheckQueryResults(t,
@@ -0,0 +1,275 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	gojson "encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookSinkDefaultBatchSize = 100
+	webhookSinkRequestTimeout   = 30 * time.Second
+)
+
+// webhookSinkRetryOptions are the options used to retry failed requests. Once
+// the retries are exhausted, the error is returned to the changefeed, which
+// restarts from its last checkpoint.
+var webhookSinkRetryOptions = retry.Options{
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	MaxRetries:     5,
+}
+
+type webhookSinkConfig struct {
+	batchSize  int
+	caCert     []byte
+	clientCert []byte
+	clientKey  []byte
+	headers    map[string]string
+}
+
+// webhookMessage is the representation of a row in the body of the requests
+// sent by webhookSink.
+type webhookMessage struct {
+	Topic string            `json:"topic"`
+	Key   gojson.RawMessage `json:"key"`
+	Value gojson.RawMessage `json:"value"`
+}
+
+// webhookBatch is the body of the requests sent by webhookSink.
+type webhookBatch struct {
+	Payload []webhookMessage `json:"payload"`
+	Length  int              `json:"length"`
+}
+
+// webhookSink emits to an HTTPS endpoint. Rows are buffered and sent in
+// batches of up to batchSize rows, as POST requests whose JSON body is of the
+// form:
+//
+//	{"payload": [{"topic": "foo", "key": [1], "value": {...}}, ...], "length": 1}
+//
+// Resolved timestamps are sent in requests of their own, whose body is the
+// resolved timestamp message of the encoder. Buffered rows are always sent
+// before a resolved timestamp, so that an endpoint which receives a resolved
+// timestamp has received every row at or below it. Failed requests are retried
+// with exponential backoff, and rows may be delivered more than once.
+type webhookSink struct {
+	url       string
+	client    *http.Client
+	transport *http.Transport
+	headers   map[string]string
+	batchSize int
+	retryOpts retry.Options
+
+	batch []webhookMessage
+}
+
+func makeWebhookSink(u *url.URL, cfg webhookSinkConfig, opts map[string]string) (Sink, error) {
+	switch formatType(opts[optFormat]) {
+	case optFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			optFormat, opts[optFormat])
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`param %s does not contain a valid certificate`, sinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil || cfg.clientKey != nil {
+		if cfg.clientCert == nil || cfg.clientKey == nil {
+			return nil, errors.Errorf(`%s and %s must be provided together`,
+				sinkParamClientCert, sinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid client certificate`)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	s := &webhookSink{
+		url:       u.String(),
+		client:    &http.Client{Transport: transport, Timeout: webhookSinkRequestTimeout},
+		transport: transport,
+		headers:   cfg.headers,
+		batchSize: cfg.batchSize,
+		retryOpts: webhookSinkRetryOptions,
+	}
+	if s.batchSize == 0 {
+		s.batchSize = webhookSinkDefaultBatchSize
+	}
+	return s, nil
+}
+
+// parseWebhookHeaders parses the value of the webhook_headers option, which is
+// a JSON object of header names to values.
+func parseWebhookHeaders(value string) (map[string]string, error) {
+	var headers map[string]string
+	if err := gojson.Unmarshal([]byte(value), &headers); err != nil {
+		return nil, errors.Errorf(
+			`option %s must be a JSON object of header names to values: %s`, optWebhookHeaders, err)
+	}
+	return headers, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	_ hlc.Timestamp,
+	_ []byte,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+
+	// The key and value may be reused by the caller once we return.
+	msg := webhookMessage{
+		Topic: table.Name,
+		Key:   gojson.RawMessage(`null`),
+		Value: gojson.RawMessage(`null`),
+	}
+	if len(key) > 0 {
+		msg.Key = append(gojson.RawMessage(nil), key...)
+	}
+	if len(value) > 0 {
+		msg.Value = append(gojson.RawMessage(nil), value...)
+	}
+	s.batch = append(s.batch, msg)
+
+	if len(s.batch) >= s.batchSize {
+		return s.flushBatch(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitResolvedTimestamp on a closed sink`)
+	}
+
+	if err := s.flushBatch(ctx); err != nil {
+		return err
+	}
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(noTopic, resolved)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, payload)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	if s.client == nil {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+	return s.flushBatch(ctx)
+}
+
+// flushBatch sends the buffered rows, if any.
+func (s *webhookSink) flushBatch(ctx context.Context) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	body, err := gojson.Marshal(webhookBatch{Payload: s.batch, Length: len(s.batch)})
+	if err != nil {
+		return err
+	}
+	if err := s.send(ctx, body); err != nil {
+		return err
+	}
+	s.batch = s.batch[:0]
+	return nil
+}
+
+// send POSTs the given body to the endpoint, retrying on network errors and on
+// server errors.
+func (s *webhookSink) send(ctx context.Context, body []byte) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.retryOpts); r.Next(); {
+		var retryable bool
+		if retryable, err = s.post(ctx, body); err == nil || !retryable {
+			return err
+		}
+		log.Warningf(ctx, `webhook sink request failed, retrying: %v`, err)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// post sends a single request. It returns whether a failed request may be
+// retried.
+func (s *webhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(`Content-Type`, `application/json`)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return false, nil
+	}
+	const maxErrBodySize = 1 << 10
+	errBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrBodySize))
+	err = errors.Errorf(`webhook sink: %s: %s`, resp.Status, strings.TrimSpace(string(errBody)))
+	retryable := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return retryable, err
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	s.client = nil
+	s.batch = nil
+	s.transport.CloseIdleConnections()
+	return nil
+}
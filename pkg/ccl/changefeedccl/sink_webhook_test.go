@@ -0,0 +1,186 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookRequest struct {
+	auth string
+	body string
+}
+
+// webhookTestServer is an HTTPS server which records the requests it
+// receives. The next mu.failures requests are rejected with a retryable error,
+// and requests to /missing are rejected with a 404.
+type webhookTestServer struct {
+	*httptest.Server
+	mu struct {
+		syncutil.Mutex
+		failures int
+		requests []webhookRequest
+	}
+}
+
+func makeWebhookTestServer(t *testing.T) *webhookTestServer {
+	s := &webhookTestServer{}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if r.URL.Path == `/missing` {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.failures > 0 {
+			s.mu.failures--
+			http.Error(w, `try again`, http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.requests = append(s.mu.requests, webhookRequest{
+			auth: r.Header.Get(`Authorization`),
+			body: string(body),
+		})
+	}))
+	return s
+}
+
+func (s *webhookTestServer) sinkURI(params url.Values) string {
+	caCert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: s.Certificate().Raw})
+	params.Set(sinkParamCACert, base64.StdEncoding.EncodeToString(caCert))
+	return `webhook-` + s.URL + `?` + params.Encode()
+}
+
+func (s *webhookTestServer) requests() []webhookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]webhookRequest(nil), s.mu.requests...)
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	srv := makeWebhookTestServer(t)
+	defer srv.Close()
+
+	opts := map[string]string{
+		optFormat:         string(optFormatJSON),
+		optEnvelope:       string(optEnvelopeWrapped),
+		optWebhookHeaders: `{"Authorization": "Bearer foo"}`,
+	}
+	sink, err := getSink(srv.sinkURI(url.Values{sinkParamBatchSize: {`2`}}), 0, opts, nil, nil)
+	require.NoError(t, err)
+	sink.(*webhookSink).retryOpts.InitialBackoff = time.Millisecond
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	table := &sqlbase.TableDescriptor{Name: `foo`}
+	emit := func(key, value string) {
+		require.NoError(t, sink.EmitRow(
+			ctx, table, []byte(key), []byte(value), hlc.Timestamp{}, noPartitionKey))
+	}
+
+	// Rows are sent once a batch is full. The first attempt fails and is
+	// retried.
+	srv.mu.Lock()
+	srv.mu.failures = 1
+	srv.mu.Unlock()
+	emit(`[1]`, `{"after": {"a": 1}}`)
+	require.Empty(t, srv.requests())
+	emit(`[2]`, `{"after": {"a": 2}}`)
+	require.Equal(t, []webhookRequest{{
+		auth: `Bearer foo`,
+		body: `{"payload":[{"topic":"foo","key":[1],"value":{"after":{"a":1}}},` +
+			`{"topic":"foo","key":[2],"value":{"after":{"a":2}}}],"length":2}`,
+	}}, srv.requests())
+
+	// Partial batches are sent on Flush, and before resolved timestamps.
+	emit(`[3]`, ``)
+	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx))
+	emit(`[4]`, `{"after": {"a": 4}}`)
+	encoder, err := getEncoder(opts)
+	require.NoError(t, err)
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 2}))
+
+	requests := srv.requests()
+	require.Len(t, requests, 4)
+	require.Equal(t, `{"payload":[{"topic":"foo","key":[3],"value":null}],"length":1}`, requests[1].body)
+	require.Equal(t,
+		`{"payload":[{"topic":"foo","key":[4],"value":{"after":{"a":4}}}],"length":1}`, requests[2].body)
+	require.True(t, strings.Contains(requests[3].body, `"resolved"`), requests[3].body)
+
+	// Client errors are not retried.
+	sink.(*webhookSink).url = srv.URL + `/missing`
+	emit(`[5]`, `{}`)
+	require.Error(t, sink.Flush(ctx))
+}
+
+func TestWebhookSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	srv := makeWebhookTestServer(t)
+	defer srv.Close()
+
+	jsonOpts := map[string]string{optFormat: string(optFormatJSON)}
+	for _, tc := range []struct {
+		uri  string
+		opts map[string]string
+		err  string
+	}{
+		{
+			uri:  srv.sinkURI(url.Values{sinkParamBatchSize: {`0`}}),
+			opts: jsonOpts,
+			err:  `param batch_size must be a positive integer`,
+		},
+		{
+			uri:  srv.sinkURI(url.Values{sinkParamClientCert: {`Zm9v`}}),
+			opts: jsonOpts,
+			err:  `client_cert and client_key must be provided together`,
+		},
+		{
+			uri:  srv.sinkURI(url.Values{}),
+			opts: map[string]string{optFormat: string(optFormatAvro)},
+			err:  `this sink is incompatible with format=experimental_avro`,
+		},
+		{
+			uri:  srv.sinkURI(url.Values{}),
+			opts: map[string]string{optFormat: string(optFormatJSON), optWebhookHeaders: `foo`},
+			err:  `option webhook_headers must be a JSON object`,
+		},
+		{
+			uri:  `kafka://nope`,
+			opts: map[string]string{optWebhookHeaders: `{}`},
+			err:  `this sink is incompatible with option webhook_headers`,
+		},
+	} {
+		_, err := getSink(tc.uri, 0, tc.opts, nil, nil)
+		require.Error(t, err, tc.uri)
+		require.Contains(t, err.Error(), tc.err)
+	}
+}
@@ -105,6 +105,13 @@ func canUseFollowerRead(clusterID uuid.UUID, st *cluster.Settings, ts hlc.Timest
 // canSendToFollower implements the logic for checking whether a batch request
 // may be sent to a follower.
 func canSendToFollower(clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest) bool {
+	if ba.BoundedStaleness != nil {
+		// Bounded staleness reads are served at the closed timestamp of the
+		// replica they're sent to, so they can always try the closest one.
+		return ba.IsReadOnly() &&
+			storage.FollowerReadsEnabled.Get(&st.SV) &&
+			checkEnterpriseEnabled(clusterID, st) == nil
+	}
 	return batchCanBeEvaluatedOnFollower(ba) &&
 		txnCanPerformFollowerRead(ba.Txn) &&
 		canUseFollowerRead(clusterID, st, forward(ba.Txn.OrigTimestamp, ba.Txn.MaxTimestamp))
@@ -117,10 +117,27 @@ func TestCanSendToFollower(t *testing.T) {
 	if canSendToFollower(uuid.MakeV4(), st, roNew) {
 		t.Fatalf("should not be able to send a ro batch with new MaxTimestamp to a follower")
 	}
+	roBoundedStaleness := roachpb.BatchRequest{Header: roachpb.Header{
+		BoundedStaleness: &roachpb.BoundedStalenessHeader{
+			MinTimestampBound: hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
+		},
+	}}
+	roBoundedStaleness.Add(&roachpb.GetRequest{})
+	if !canSendToFollower(uuid.MakeV4(), st, roBoundedStaleness) {
+		t.Fatalf("should be able to send a bounded staleness ro batch to a follower")
+	}
+	rwBoundedStaleness := roachpb.BatchRequest{Header: roBoundedStaleness.Header}
+	rwBoundedStaleness.Add(&roachpb.PutRequest{})
+	if canSendToFollower(uuid.MakeV4(), st, rwBoundedStaleness) {
+		t.Fatalf("should not be able to send a bounded staleness rw batch to a follower")
+	}
 	disableEnterprise()
 	if canSendToFollower(uuid.MakeV4(), st, roOld) {
 		t.Fatalf("should not be able to send an old ro batch to a follower without enterprise enabled")
 	}
+	if canSendToFollower(uuid.MakeV4(), st, roBoundedStaleness) {
+		t.Fatalf("should not be able to send a bounded staleness ro batch to a follower without enterprise enabled")
+	}
 }
 
 func TestFollowerReadMultipleValidation(t *testing.T) {
@@ -10,8 +10,10 @@ package importccl
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -41,23 +43,36 @@ var exportHeader = sqlbase.ResultColumns{
 }
 
 const (
-	exportOptionDelimiter = "delimiter"
-	exportOptionNullAs    = "nullas"
-	exportOptionChunkSize = "chunk_rows"
-	exportOptionFileName  = "filename"
+	exportOptionDelimiter   = "delimiter"
+	exportOptionNullAs      = "nullas"
+	exportOptionChunkSize   = "chunk_rows"
+	exportOptionFileName    = "filename"
+	exportOptionCompression = "compression"
 )
 
 var exportOptionExpectValues = map[string]sql.KVStringOptValidate{
-	exportOptionChunkSize: sql.KVStringOptRequireValue,
-	exportOptionDelimiter: sql.KVStringOptRequireValue,
-	exportOptionFileName:  sql.KVStringOptRequireValue,
-	exportOptionNullAs:    sql.KVStringOptRequireValue,
+	exportOptionChunkSize:   sql.KVStringOptRequireValue,
+	exportOptionCompression: sql.KVStringOptRequireValue,
+	exportOptionDelimiter:   sql.KVStringOptRequireValue,
+	exportOptionFileName:    sql.KVStringOptRequireValue,
+	exportOptionNullAs:      sql.KVStringOptRequireValue,
 }
 
+// exportCSVOnlyOptions are the options which only apply to the CSV format.
+var exportCSVOnlyOptions = []string{exportOptionDelimiter, exportOptionNullAs}
+
 const exportChunkSizeDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
 
+// exportFileExtensions are the extensions of the files written for each
+// export format.
+var exportFileExtensions = map[distsqlpb.CSVWriterSpec_Format]string{
+	distsqlpb.CSVWriterSpec_CSV:     ".csv",
+	distsqlpb.CSVWriterSpec_Parquet: ".parquet",
+	distsqlpb.CSVWriterSpec_JSON:    ".json",
+}
+
 // exportPlanHook implements sql.PlanHook.
 func exportPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -72,7 +87,15 @@ func exportPlanHook(
 		return nil, nil, nil, false, err
 	}
 
-	if exportStmt.FileFormat != "CSV" {
+	var format distsqlpb.CSVWriterSpec_Format
+	switch exportStmt.FileFormat {
+	case "CSV":
+		format = distsqlpb.CSVWriterSpec_CSV
+	case "PARQUET":
+		format = distsqlpb.CSVWriterSpec_Parquet
+	case "JSON":
+		format = distsqlpb.CSVWriterSpec_JSON
+	default:
 		return nil, nil, nil, false, errors.Errorf("unsupported export format: %q", exportStmt.FileFormat)
 	}
 
@@ -85,6 +108,11 @@ func exportPlanHook(
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	cols := sql.PlanColumns(sel)
+	colNames := make([]string, len(cols))
+	for i := range cols {
+		colNames[i] = cols[i].Name
+	}
 
 	fn := func(ctx context.Context, plans []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		ctx, span := tracing.ChildSpan(ctx, exportStmt.StatementTag())
@@ -114,6 +142,15 @@ func exportPlanHook(
 			return err
 		}
 
+		if format != distsqlpb.CSVWriterSpec_CSV {
+			for _, opt := range exportCSVOnlyOptions {
+				if _, ok := opts[opt]; ok {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"%s option is only supported for CSV exports", opt)
+				}
+			}
+		}
+
 		csvOpts := roachpb.CSVOptions{}
 
 		if override, ok := opts[exportOptionDelimiter]; ok {
@@ -138,11 +175,39 @@ func exportPlanHook(
 			}
 		}
 
+		var compression distsqlpb.CSVWriterSpec_Compression
+		if override, ok := opts[exportOptionCompression]; ok {
+			switch strings.ToLower(override) {
+			case "none":
+				compression = distsqlpb.CSVWriterSpec_None
+			case "gzip":
+				compression = distsqlpb.CSVWriterSpec_Gzip
+			case "snappy":
+				if format != distsqlpb.CSVWriterSpec_Parquet {
+					return pgerror.New(pgcode.InvalidParameterValue,
+						"snappy compression is only supported for PARQUET exports")
+				}
+				compression = distsqlpb.CSVWriterSpec_Snappy
+			default:
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"unsupported compression: %q", override)
+			}
+		}
+
+		// Parquet files are compressed internally, and keep their extension.
+		namePattern := exportFilePatternPart + exportFileExtensions[format]
+		if compression == distsqlpb.CSVWriterSpec_Gzip && format != distsqlpb.CSVWriterSpec_Parquet {
+			namePattern += ".gz"
+		}
+
 		out := distsqlpb.ProcessorCoreUnion{CSVWriter: &distsqlpb.CSVWriterSpec{
 			Destination: file,
-			NamePattern: exportFilePatternDefault,
+			NamePattern: namePattern,
 			Options:     csvOpts,
 			ChunkRows:   int64(chunk),
+			Format:      format,
+			ColumnNames: colNames,
+			Compression: compression,
 		}}
 
 		rows := rowcontainer.NewRowContainer(
@@ -196,6 +261,73 @@ type csvWriter struct {
 
 var _ distsqlrun.Processor = &csvWriter{}
 
+// exportEncoder encodes rows into the contents of an exported file.
+type exportEncoder interface {
+	// writeRow encodes the given row.
+	writeRow(row tree.Datums) error
+	// finish flushes the encoded rows, followed by the trailer of the file if
+	// the format has one.
+	finish() error
+}
+
+// csvEncoder encodes rows as CSV records.
+type csvEncoder struct {
+	writer  *csv.Writer
+	nullsAs string
+	f       *tree.FmtCtx
+	record  []string
+}
+
+var _ exportEncoder = &csvEncoder{}
+
+// writeRow implements the exportEncoder interface.
+func (e *csvEncoder) writeRow(row tree.Datums) error {
+	for i, d := range row {
+		if d == tree.DNull {
+			e.record[i] = e.nullsAs
+			continue
+		}
+		d.Format(e.f)
+		e.record[i] = e.f.String()
+		e.f.Reset()
+	}
+	return e.writer.Write(e.record)
+}
+
+// finish implements the exportEncoder interface.
+func (e *csvEncoder) finish() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// newEncoder returns an exportEncoder which writes rows of the given types to
+// out, in the format of the spec.
+func (sp *csvWriter) newEncoder(
+	out io.Writer, typs []types.T, f *tree.FmtCtx, parquetCols []parquetColumn,
+) exportEncoder {
+	switch sp.spec.Format {
+	case distsqlpb.CSVWriterSpec_Parquet:
+		return newParquetWriter(out, parquetCols, sp.spec.Compression)
+	case distsqlpb.CSVWriterSpec_JSON:
+		return newJSONWriter(out, sp.spec.ColumnNames)
+	default:
+		writer := csv.NewWriter(out)
+		if sp.spec.Options.Comma != 0 {
+			writer.Comma = sp.spec.Options.Comma
+		}
+		nullsAs := ""
+		if sp.spec.Options.NullEncoding != nil {
+			nullsAs = *sp.spec.Options.NullEncoding
+		}
+		return &csvEncoder{
+			writer:  writer,
+			nullsAs: nullsAs,
+			f:       f,
+			record:  make([]string, len(typs)),
+		}
+	}
+}
+
 func (sp *csvWriter) OutputTypes() []types.T {
 	return sql.ExportPlanResultTypes
 }
@@ -216,25 +348,43 @@ func (sp *csvWriter) Run(ctx context.Context) {
 
 		alloc := &sqlbase.DatumAlloc{}
 
-		var buf bytes.Buffer
-		writer := csv.NewWriter(&buf)
-		if sp.spec.Options.Comma != 0 {
-			writer.Comma = sp.spec.Options.Comma
+		if sp.spec.Format != distsqlpb.CSVWriterSpec_CSV && len(sp.spec.ColumnNames) != len(typs) {
+			return errors.Errorf("expected %d column names, got %d", len(typs), len(sp.spec.ColumnNames))
 		}
-		nullsAs := ""
-		if sp.spec.Options.NullEncoding != nil {
-			nullsAs = *sp.spec.Options.NullEncoding
+		var parquetCols []parquetColumn
+		if sp.spec.Format == distsqlpb.CSVWriterSpec_Parquet {
+			parquetCols = make([]parquetColumn, len(typs))
+			for i := range typs {
+				parquetCols[i] = makeParquetColumn(sp.spec.ColumnNames[i], &typs[i])
+			}
 		}
+		// Parquet files are compressed page by page, while files of the other
+		// formats are compressed as a whole.
+		compressFile := sp.spec.Compression == distsqlpb.CSVWriterSpec_Gzip &&
+			sp.spec.Format != distsqlpb.CSVWriterSpec_Parquet
+
+		var buf bytes.Buffer
+		var gz *gzip.Writer
 		f := tree.NewFmtCtx(tree.FmtExport)
 		defer f.Close()
 
-		csvRow := make([]string, len(typs))
+		datums := make(tree.Datums, len(typs))
 
 		chunk := 0
 		done := false
 		for {
 			var rows int64
 			buf.Reset()
+			var out io.Writer = &buf
+			if compressFile {
+				if gz == nil {
+					gz = gzip.NewWriter(&buf)
+				} else {
+					gz.Reset(&buf)
+				}
+				out = gz
+			}
+			enc := sp.newEncoder(out, typs, f, parquetCols)
 			for {
 				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
 					break
@@ -251,24 +401,29 @@ func (sp *csvWriter) Run(ctx context.Context) {
 
 				for i, ed := range row {
 					if ed.IsNull() {
-						csvRow[i] = nullsAs
+						datums[i] = tree.DNull
 						continue
 					}
 					if err := ed.EnsureDecoded(&typs[i], alloc); err != nil {
 						return err
 					}
-					ed.Datum.Format(f)
-					csvRow[i] = f.String()
-					f.Reset()
+					datums[i] = ed.Datum
 				}
-				if err := writer.Write(csvRow); err != nil {
+				if err := enc.writeRow(datums); err != nil {
 					return err
 				}
 			}
 			if rows < 1 {
 				break
 			}
-			writer.Flush()
+			if err := enc.finish(); err != nil {
+				return err
+			}
+			if gz != nil {
+				if err := gz.Close(); err != nil {
+					return err
+				}
+			}
 
 			conf, err := storageccl.ExportStorageConfFromURI(sp.spec.Destination)
 			if err != nil {
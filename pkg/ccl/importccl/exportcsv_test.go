@@ -9,9 +9,13 @@
 package importccl_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -219,3 +223,87 @@ func TestExportShow(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, got)
 	}
 }
+
+func TestExportFormats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE t (i INT PRIMARY KEY, s STRING, j JSONB, a INT[], d DECIMAL(10, 2))`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a', '{"x": 1}', ARRAY[1, NULL], 1.5), (2, NULL, NULL, NULL, -3)`)
+
+	t.Run("json", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO JSON 'nodelocal:///json' FROM SELECT * FROM t ORDER BY i`)
+		content, err := ioutil.ReadFile(filepath.Join(dir, "json", "n1.0.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := `{"i": 1, "s": "a", "j": {"x": 1}, "a": [1, null], "d": 1.50}` + "\n" +
+			`{"i": 2, "s": null, "j": null, "a": null, "d": -3.00}` + "\n"
+		if got := string(content); expected != got {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("csv-gzip", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO CSV 'nodelocal:///gzip' WITH compression = 'gzip' FROM SELECT i, s FROM t ORDER BY i`)
+		f, err := os.Open(filepath.Join(dir, "gzip", "n1.0.csv.gz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := "1,a\n2,\n", string(content); expected != got {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		for _, compression := range []string{"none", "gzip", "snappy"} {
+			dest := "parquet-" + compression
+			rows := sqlDB.QueryStr(t, fmt.Sprintf(
+				`EXPORT INTO PARQUET 'nodelocal:///%s' WITH chunk_rows = '1', compression = '%s' FROM SELECT * FROM t`,
+				dest, compression,
+			))
+			if len(rows) != 2 {
+				t.Fatalf("expected 2 files, got %d", len(rows))
+			}
+			for _, row := range rows {
+				if !strings.HasSuffix(row[0], ".parquet") {
+					t.Fatalf("unexpected file name %s", row[0])
+				}
+				content, err := ioutil.ReadFile(filepath.Join(dir, dest, row[0]))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.HasPrefix(content, []byte("PAR1")) || !bytes.HasSuffix(content, []byte("PAR1")) {
+					t.Fatalf("%s is not a Parquet file", row[0])
+				}
+				footerLen := int(binary.LittleEndian.Uint32(content[len(content)-8:]))
+				if footerLen <= 0 || footerLen > len(content)-12 {
+					t.Fatalf("invalid footer length %d in %s", footerLen, row[0])
+				}
+			}
+		}
+	})
+
+	sqlDB.ExpectErr(t, `unsupported export format: "XML"`,
+		`EXPORT INTO XML 'nodelocal:///err' FROM SELECT * FROM t`)
+	sqlDB.ExpectErr(t, `delimiter option is only supported for CSV exports`,
+		`EXPORT INTO JSON 'nodelocal:///err' WITH delimiter = '|' FROM SELECT * FROM t`)
+	sqlDB.ExpectErr(t, `snappy compression is only supported for PARQUET exports`,
+		`EXPORT INTO CSV 'nodelocal:///err' WITH compression = 'snappy' FROM SELECT * FROM t`)
+	sqlDB.ExpectErr(t, `unsupported compression: "zstd"`,
+		`EXPORT INTO PARQUET 'nodelocal:///err' WITH compression = 'zstd' FROM SELECT * FROM t`)
+}
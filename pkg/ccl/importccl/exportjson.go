@@ -0,0 +1,62 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// jsonWriter encodes rows as newline-delimited JSON objects, which map the
+// names of the columns to their values. The keys of each object are in the
+// order of the columns.
+type jsonWriter struct {
+	out  io.Writer
+	keys []json.JSON
+	buf  bytes.Buffer
+}
+
+var _ exportEncoder = &jsonWriter{}
+
+func newJSONWriter(out io.Writer, names []string) *jsonWriter {
+	w := &jsonWriter{out: out, keys: make([]json.JSON, len(names))}
+	for i, name := range names {
+		w.keys[i] = json.FromString(name)
+	}
+	return w
+}
+
+// writeRow implements the exportEncoder interface.
+func (w *jsonWriter) writeRow(row tree.Datums) error {
+	w.buf.Reset()
+	w.buf.WriteByte('{')
+	for i, d := range row {
+		if i > 0 {
+			w.buf.WriteString(", ")
+		}
+		j, err := tree.AsJSON(tree.UnwrapDatum(nil /* evalCtx */, d))
+		if err != nil {
+			return err
+		}
+		w.keys[i].Format(&w.buf)
+		w.buf.WriteString(": ")
+		j.Format(&w.buf)
+	}
+	w.buf.WriteString("}\n")
+	_, err := w.out.Write(w.buf.Bytes())
+	return err
+}
+
+// finish implements the exportEncoder interface.
+func (w *jsonWriter) finish() error {
+	return nil
+}
@@ -0,0 +1,677 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// This file implements a minimal writer of Apache Parquet files. Each file
+// contains a single row group, in which every column chunk is made of a
+// single PLAIN encoded data page (version 1). Definition and repetition
+// levels are RLE encoded. See https://github.com/apache/parquet-format for
+// the specification of the format.
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types.
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet converted types, which annotate physical types with a logical
+// type. noConvertedType is used for columns without an annotation.
+const (
+	noConvertedType        = -1
+	parquetUTF8            = 0
+	parquetList            = 3
+	parquetEnum            = 4
+	parquetDecimal         = 5
+	parquetDate            = 6
+	parquetTimeMicros      = 8
+	parquetTimestampMicros = 10
+	parquetJSON            = 19
+)
+
+// Parquet field repetition types.
+const (
+	parquetOptional = 1
+	parquetRepeated = 2
+)
+
+// Parquet encodings, compression codecs and page types.
+const (
+	parquetPlainEncoding = 0
+	parquetRLEEncoding   = 3
+
+	parquetUncompressed = 0
+	parquetSnappy       = 1
+	parquetGzip         = 2
+
+	parquetDataPage = 0
+)
+
+// parquetColumn describes how a column of a given SQL type is stored in a
+// Parquet file.
+type parquetColumn struct {
+	name string
+	// typ is the SQL type of the column, or of the elements of the column if
+	// it is an array.
+	typ *types.T
+	// list is set for array columns, which are stored as a LIST of optional
+	// elements.
+	list bool
+
+	physicalType  int32
+	convertedType int32
+	scale         int32
+	precision     int32
+}
+
+// makeParquetColumn returns the Parquet representation of a column of the
+// given name and type. Types without a Parquet equivalent are stored as
+// strings.
+func makeParquetColumn(name string, typ *types.T) parquetColumn {
+	col := parquetColumn{name: name, typ: typ, convertedType: noConvertedType}
+	if typ.Family() == types.ArrayFamily {
+		col.list = true
+		col.typ = typ.ArrayContents()
+	}
+	switch col.typ.Family() {
+	case types.BoolFamily:
+		col.physicalType = parquetBoolean
+	case types.IntFamily:
+		col.physicalType = parquetInt64
+	case types.FloatFamily:
+		col.physicalType = parquetDouble
+	case types.DecimalFamily:
+		col.physicalType = parquetByteArray
+		if col.typ.Precision() > 0 {
+			// Decimals are stored as their unscaled value, which requires a fixed
+			// scale. Decimals of unconstrained types are stored as strings.
+			col.convertedType = parquetDecimal
+			col.precision = col.typ.Precision()
+			col.scale = col.typ.Scale()
+		} else {
+			col.convertedType = parquetUTF8
+		}
+	case types.BytesFamily:
+		col.physicalType = parquetByteArray
+	case types.JsonFamily:
+		col.physicalType = parquetByteArray
+		col.convertedType = parquetJSON
+	case types.EnumFamily:
+		col.physicalType = parquetByteArray
+		col.convertedType = parquetEnum
+	case types.DateFamily:
+		col.physicalType = parquetInt32
+		col.convertedType = parquetDate
+	case types.TimeFamily:
+		col.physicalType = parquetInt64
+		col.convertedType = parquetTimeMicros
+	case types.TimestampFamily, types.TimestampTZFamily:
+		col.physicalType = parquetInt64
+		col.convertedType = parquetTimestampMicros
+	default:
+		col.physicalType = parquetByteArray
+		col.convertedType = parquetUTF8
+	}
+	return col
+}
+
+// maxDefinitionLevel returns the maximum definition level of the values of
+// the column. All columns are optional, and so are the elements of lists.
+func (c *parquetColumn) maxDefinitionLevel() uint8 {
+	if c.list {
+		return 3
+	}
+	return 1
+}
+
+// maxRepetitionLevel returns the maximum repetition level of the values of
+// the column.
+func (c *parquetColumn) maxRepetitionLevel() uint8 {
+	if c.list {
+		return 1
+	}
+	return 0
+}
+
+// path returns the path of the leaf of the column in the schema.
+func (c *parquetColumn) path() []string {
+	if c.list {
+		return []string{c.name, "list", "element"}
+	}
+	return []string{c.name}
+}
+
+// parquetColumnChunk accumulates the values of a column.
+type parquetColumnChunk struct {
+	*parquetColumn
+	defLevels []uint8
+	repLevels []uint8
+	// values holds the PLAIN encoding of the non-NULL values, except for
+	// booleans, which are accumulated in bools and bit-packed when the page is
+	// written.
+	values  []byte
+	bools   []bool
+	scratch apd.Decimal
+}
+
+// add appends a datum to the column chunk.
+func (c *parquetColumnChunk) add(d tree.Datum) error {
+	if !c.list {
+		if d == tree.DNull {
+			c.defLevels = append(c.defLevels, 0)
+			return nil
+		}
+		c.defLevels = append(c.defLevels, 1)
+		return c.addValue(d)
+	}
+
+	if c.repLevels == nil {
+		c.repLevels = make([]uint8, 0, cap(c.defLevels))
+	}
+	if d == tree.DNull {
+		c.defLevels = append(c.defLevels, 0)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	arr, ok := d.(*tree.DArray)
+	if !ok {
+		return errors.Errorf("unexpected datum of type %T for array column %s", d, c.name)
+	}
+	if arr.Len() == 0 {
+		c.defLevels = append(c.defLevels, 1)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	for i, elem := range arr.Array {
+		var rep uint8
+		if i > 0 {
+			rep = 1
+		}
+		c.repLevels = append(c.repLevels, rep)
+		if elem == tree.DNull {
+			c.defLevels = append(c.defLevels, 2)
+			continue
+		}
+		c.defLevels = append(c.defLevels, 3)
+		if err := c.addValue(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addValue appends the PLAIN encoding of a non-NULL datum to the column
+// chunk.
+func (c *parquetColumnChunk) addValue(d tree.Datum) error {
+	d = tree.UnwrapDatum(nil /* evalCtx */, d)
+	switch c.physicalType {
+	case parquetBoolean:
+		c.bools = append(c.bools, bool(*d.(*tree.DBool)))
+		return nil
+	case parquetInt32:
+		days := d.(*tree.DDate).UnixEpochDays()
+		if days < math.MinInt32 || days > math.MaxInt32 {
+			return pgerror.Newf(pgcode.DatetimeFieldOverflow,
+				"date %s in column %s cannot be exported to Parquet", d, c.name)
+		}
+		c.values = appendUint32(c.values, uint32(days))
+		return nil
+	case parquetInt64:
+		var v int64
+		switch t := d.(type) {
+		case *tree.DInt:
+			v = int64(*t)
+		case *tree.DTime:
+			v = int64(*t)
+		case *tree.DTimestamp:
+			v = t.Unix()*1e6 + int64(t.Nanosecond()/1000)
+		case *tree.DTimestampTZ:
+			v = t.Unix()*1e6 + int64(t.Nanosecond()/1000)
+		default:
+			return errors.AssertionFailedf("unexpected datum of type %T for column %s", d, c.name)
+		}
+		c.values = appendUint64(c.values, uint64(v))
+		return nil
+	case parquetDouble:
+		c.values = appendUint64(c.values, math.Float64bits(float64(*d.(*tree.DFloat))))
+		return nil
+	}
+
+	var b []byte
+	switch t := d.(type) {
+	case *tree.DBytes:
+		b = []byte(*t)
+	case *tree.DString:
+		b = []byte(*t)
+	case *tree.DCollatedString:
+		b = []byte(t.Contents)
+	case *tree.DJSON:
+		b = []byte(t.JSON.String())
+	case *tree.DEnum:
+		b = []byte(t.LogicalRep)
+	case *tree.DDecimal:
+		if c.convertedType != parquetDecimal {
+			b = []byte(t.Decimal.String())
+			break
+		}
+		var err error
+		if b, err = c.unscaledDecimal(&t.Decimal); err != nil {
+			return err
+		}
+	default:
+		b = []byte(tree.AsStringWithFlags(d, tree.FmtBareStrings))
+	}
+	c.values = appendUint32(c.values, uint32(len(b)))
+	c.values = append(c.values, b...)
+	return nil
+}
+
+// unscaledDecimal returns the unscaled value of the given decimal, at the
+// scale of the column, as a big-endian two's complement integer.
+func (c *parquetColumnChunk) unscaledDecimal(d *apd.Decimal) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+			"decimal %s in column %s cannot be exported to Parquet", d, c.name)
+	}
+	if _, err := tree.DecimalCtx.Quantize(&c.scratch, d, -c.scale); err != nil {
+		return nil, err
+	}
+	v := &c.scratch.Coeff
+	if c.scratch.Negative {
+		v = new(big.Int).Neg(v)
+	}
+	return twosComplement(v), nil
+}
+
+// twosComplement returns the minimal big-endian two's complement
+// representation of the given integer.
+func twosComplement(v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// A negative value v is represented on n bytes as 2^(8n) + v, where n is
+	// large enough for the sign bit to be set.
+	n := new(big.Int).Not(v).BitLen()/8 + 1
+	m := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	return m.Add(m, v).Bytes()
+}
+
+// page returns the body of the data page holding the values of the column
+// chunk.
+func (c *parquetColumnChunk) page() []byte {
+	var page []byte
+	if c.maxRepetitionLevel() > 0 {
+		page = appendLevels(page, c.repLevels)
+	}
+	page = appendLevels(page, c.defLevels)
+	if c.physicalType == parquetBoolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, b := range c.bools {
+			if b {
+				packed[i/8] |= 1 << uint(i%8)
+			}
+		}
+		return append(page, packed...)
+	}
+	return append(page, c.values...)
+}
+
+// appendLevels appends the RLE encoding of the given repetition or
+// definition levels, prefixed by its length, to buf. The levels are encoded
+// as a sequence of RLE runs only, which is a valid instance of the
+// RLE/bit-packing hybrid encoding. Levels are at most 3, so that the value of
+// each run is stored on a single byte.
+func appendLevels(buf []byte, levels []uint8) []byte {
+	lenOffset := len(buf)
+	buf = appendUint32(buf, 0)
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		buf = appendUvarint(buf, uint64(j-i)<<1)
+		buf = append(buf, levels[i])
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[lenOffset:], uint32(len(buf)-lenOffset-4))
+	return buf
+}
+
+// parquetWriter encodes rows into a Parquet file.
+type parquetWriter struct {
+	out     io.Writer
+	columns []parquetColumn
+	chunks  []parquetColumnChunk
+	codec   int32
+	numRows int64
+}
+
+var _ exportEncoder = &parquetWriter{}
+
+func newParquetWriter(
+	out io.Writer, columns []parquetColumn, compression distsqlpb.CSVWriterSpec_Compression,
+) *parquetWriter {
+	w := &parquetWriter{out: out, columns: columns}
+	switch compression {
+	case distsqlpb.CSVWriterSpec_Gzip:
+		w.codec = parquetGzip
+	case distsqlpb.CSVWriterSpec_Snappy:
+		w.codec = parquetSnappy
+	default:
+		w.codec = parquetUncompressed
+	}
+	w.chunks = make([]parquetColumnChunk, len(columns))
+	for i := range columns {
+		w.chunks[i].parquetColumn = &w.columns[i]
+	}
+	return w
+}
+
+// writeRow implements the exportEncoder interface.
+func (w *parquetWriter) writeRow(row tree.Datums) error {
+	for i := range w.chunks {
+		if err := w.chunks[i].add(row[i]); err != nil {
+			return err
+		}
+	}
+	w.numRows++
+	return nil
+}
+
+// finish implements the exportEncoder interface. It writes the column
+// chunks, followed by the file metadata.
+func (w *parquetWriter) finish() error {
+	var buf bytes.Buffer
+	buf.WriteString(parquetMagic)
+
+	var totalSize int64
+	meta := make([]parquetColumnMeta, len(w.chunks))
+	for i := range w.chunks {
+		chunk := &w.chunks[i]
+		page := chunk.page()
+		compressed, err := w.compress(page)
+		if err != nil {
+			return err
+		}
+
+		var header thriftWriter
+		header.structBegin()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(page)))
+		header.i32Field(3, int32(len(compressed)))
+		header.structField(5)
+		header.i32Field(1, int32(len(chunk.defLevels)))
+		header.i32Field(2, parquetPlainEncoding)
+		header.i32Field(3, parquetRLEEncoding)
+		header.i32Field(4, parquetRLEEncoding)
+		header.structEnd()
+		header.structEnd()
+
+		meta[i] = parquetColumnMeta{
+			offset:           int64(buf.Len()),
+			numValues:        int64(len(chunk.defLevels)),
+			uncompressedSize: int64(len(header.buf) + len(page)),
+			compressedSize:   int64(len(header.buf) + len(compressed)),
+		}
+		totalSize += meta[i].uncompressedSize
+		buf.Write(header.buf)
+		buf.Write(compressed)
+	}
+
+	footer := w.fileMetadata(meta, totalSize)
+	buf.Write(footer)
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(footer)))
+	buf.Write(footerLen[:])
+	buf.WriteString(parquetMagic)
+
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+// compress returns the given page body compressed with the codec of the
+// file.
+func (w *parquetWriter) compress(page []byte) ([]byte, error) {
+	switch w.codec {
+	case parquetSnappy:
+		return snappy.Encode(nil, page), nil
+	case parquetGzip:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(page); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return page, nil
+	}
+}
+
+// parquetColumnMeta describes where a column chunk was written in the file.
+type parquetColumnMeta struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// fileMetadata returns the encoded FileMetaData structure of the file, which
+// holds the schema and the location of the column chunks.
+func (w *parquetWriter) fileMetadata(meta []parquetColumnMeta, totalSize int64) []byte {
+	numSchemaElements := 1
+	for i := range w.columns {
+		numSchemaElements += len(w.columns[i].path())
+	}
+
+	var t thriftWriter
+	t.structBegin()
+	t.i32Field(1, 1 /* version */)
+
+	t.listField(2, thriftStruct, numSchemaElements)
+	t.structBegin()
+	t.binaryField(4, "schema")
+	t.i32Field(5, int32(len(w.columns)))
+	t.structEnd()
+	for i := range w.columns {
+		col := &w.columns[i]
+		if col.list {
+			t.structBegin()
+			t.i32Field(3, parquetOptional)
+			t.binaryField(4, col.name)
+			t.i32Field(5, 1)
+			t.i32Field(6, parquetList)
+			t.structEnd()
+			t.structBegin()
+			t.i32Field(3, parquetRepeated)
+			t.binaryField(4, "list")
+			t.i32Field(5, 1)
+			t.structEnd()
+		}
+		path := col.path()
+		t.structBegin()
+		t.i32Field(1, col.physicalType)
+		t.i32Field(3, parquetOptional)
+		t.binaryField(4, path[len(path)-1])
+		if col.convertedType != noConvertedType {
+			t.i32Field(6, col.convertedType)
+		}
+		if col.convertedType == parquetDecimal {
+			t.i32Field(7, col.scale)
+			t.i32Field(8, col.precision)
+		}
+		t.structEnd()
+	}
+
+	t.i64Field(3, w.numRows)
+
+	t.listField(4, thriftStruct, 1)
+	t.structBegin()
+	t.listField(1, thriftStruct, len(w.columns))
+	for i := range w.columns {
+		col := &w.columns[i]
+		t.structBegin()
+		t.i64Field(2, meta[i].offset)
+		t.structField(3)
+		t.i32Field(1, col.physicalType)
+		t.listField(2, thriftI32, 2)
+		t.i32Elem(parquetPlainEncoding)
+		t.i32Elem(parquetRLEEncoding)
+		path := col.path()
+		t.listField(3, thriftBinary, len(path))
+		for _, p := range path {
+			t.binaryElem(p)
+		}
+		t.i32Field(4, w.codec)
+		t.i64Field(5, meta[i].numValues)
+		t.i64Field(6, meta[i].uncompressedSize)
+		t.i64Field(7, meta[i].compressedSize)
+		t.i64Field(9, meta[i].offset)
+		t.structEnd()
+		t.structEnd()
+	}
+	t.i64Field(2, totalSize)
+	t.i64Field(3, w.numRows)
+	t.structEnd()
+
+	t.binaryField(6, "CockroachDB")
+	t.structEnd()
+	return t.buf
+}
+
+// Thrift compact protocol types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Parquet metadata structures using the Thrift
+// compact protocol.
+type thriftWriter struct {
+	buf []byte
+	// lastField holds the ID of the last field written in each of the
+	// enclosing structs; field IDs are delta encoded.
+	lastField []int16
+}
+
+func (t *thriftWriter) structBegin() {
+	t.lastField = append(t.lastField, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf = append(t.buf, 0 /* stop */)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) fieldBegin(id int16, typ byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.buf = appendZigzag(t.buf, int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldBegin(id, thriftI32)
+	t.i32Elem(v)
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldBegin(id, thriftI64)
+	t.buf = appendZigzag(t.buf, v)
+}
+
+func (t *thriftWriter) binaryField(id int16, s string) {
+	t.fieldBegin(id, thriftBinary)
+	t.binaryElem(s)
+}
+
+// structField begins a field holding a struct, which must be terminated with
+// structEnd.
+func (t *thriftWriter) structField(id int16) {
+	t.fieldBegin(id, thriftStruct)
+	t.structBegin()
+}
+
+// listField begins a field holding a list of n elements of the given type,
+// which must be followed by the n elements.
+func (t *thriftWriter) listField(id int16, elemType byte, n int) {
+	t.fieldBegin(id, thriftList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n)<<4|elemType)
+	} else {
+		t.buf = append(t.buf, 0xf0|elemType)
+		t.buf = appendUvarint(t.buf, uint64(n))
+	}
+}
+
+func (t *thriftWriter) i32Elem(v int32) {
+	t.buf = appendZigzag(t.buf, int64(v))
+}
+
+func (t *thriftWriter) binaryElem(s string) {
+	t.buf = appendUvarint(t.buf, uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+func appendZigzag(buf []byte, v int64) []byte {
+	return appendUvarint(buf, uint64(v<<1)^uint64(v>>63))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
@@ -0,0 +1,482 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+func TestParquetTwosComplement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tests := []struct {
+		v        int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+		{-32768, []byte{0x80, 0x00}},
+	}
+	for _, tc := range tests {
+		if got := twosComplement(big.NewInt(tc.v)); !bytes.Equal(tc.expected, got) {
+			t.Errorf("%d: expected %x, got %x", tc.v, tc.expected, got)
+		}
+	}
+}
+
+func TestParquetColumnChunk(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	col := makeParquetColumn("a", types.MakeArray(types.Int))
+	chunk := parquetColumnChunk{parquetColumn: &col}
+	arr := tree.NewDArray(types.Int)
+	for _, d := range []tree.Datum{tree.NewDInt(1), tree.DNull, tree.NewDInt(2)} {
+		if err := arr.Append(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range []tree.Datum{arr, tree.DNull, tree.NewDArray(types.Int)} {
+		if err := chunk.add(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := []uint8{3, 2, 3, 0, 1}; !bytes.Equal(expected, chunk.defLevels) {
+		t.Errorf("expected definition levels %v, got %v", expected, chunk.defLevels)
+	}
+	if expected := []uint8{0, 1, 1, 0, 0}; !bytes.Equal(expected, chunk.repLevels) {
+		t.Errorf("expected repetition levels %v, got %v", expected, chunk.repLevels)
+	}
+
+	expected := []byte{
+		// Repetition levels: length, then runs of (count << 1, value).
+		6, 0, 0, 0, 2, 0, 4, 1, 4, 0,
+		// Definition levels.
+		10, 0, 0, 0, 2, 3, 2, 2, 2, 3, 2, 0, 2, 1,
+		// Values.
+		1, 0, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 0, 0, 0,
+	}
+	if got := chunk.page(); !bytes.Equal(expected, got) {
+		t.Errorf("expected page %v, got %v", expected, got)
+	}
+}
+
+// thriftReader decodes structures encoded with the Thrift compact protocol.
+// It is written independently of thriftWriter, following the protocol
+// specification, so that the files produced by parquetWriter are read back
+// the way a Parquet reader would read them.
+type thriftReader struct {
+	buf []byte
+}
+
+// thriftStructValue holds the fields of a decoded struct, by field ID.
+// Integers are decoded as int64, binary fields as strings, lists as
+// []interface{} and nested structs as thriftStructValue.
+type thriftStructValue map[int16]interface{}
+
+func (r *thriftReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		return 0, errors.New("malformed varint")
+	}
+	r.buf = r.buf[n:]
+	return v, nil
+}
+
+func (r *thriftReader) zigzag() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if len(r.buf) == 0 {
+		return 0, errors.New("unexpected end of input")
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b, nil
+}
+
+func (r *thriftReader) value(typ byte) (interface{}, error) {
+	switch typ {
+	case 1, 2:
+		// Boolean fields are encoded in their type. This does not hold for the
+		// elements of lists, which the writer never uses for booleans.
+		return typ == 1, nil
+	case 3:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+	case 4, 5, 6:
+		return r.zigzag()
+	case 7:
+		if len(r.buf) < 8 {
+			return nil, errors.New("unexpected end of input")
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf))
+		r.buf = r.buf[8:]
+		return v, nil
+	case 8:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(r.buf)) < n {
+			return nil, errors.New("unexpected end of input")
+		}
+		s := string(r.buf[:n])
+		r.buf = r.buf[n:]
+		return s, nil
+	case 9, 10:
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		n := uint64(header >> 4)
+		if n == 15 {
+			if n, err = r.uvarint(); err != nil {
+				return nil, err
+			}
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			if list[i], err = r.value(header & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case 12:
+		return r.readStruct()
+	default:
+		return nil, errors.Errorf("unsupported thrift type %d", typ)
+	}
+}
+
+func (r *thriftReader) readStruct() (thriftStructValue, error) {
+	s := make(thriftStructValue)
+	var lastID int16
+	for {
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return s, nil
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		if _, ok := s[id]; ok {
+			return nil, errors.Errorf("duplicate field %d", id)
+		}
+		if s[id], err = r.value(header & 0x0f); err != nil {
+			return nil, err
+		}
+		lastID = id
+	}
+}
+
+// readLevels decodes RLE encoded levels prefixed by their length, and returns
+// the rest of the page.
+func readLevels(page []byte) ([]uint8, []byte, error) {
+	if len(page) < 4 {
+		return nil, nil, errors.New("missing levels length")
+	}
+	n := binary.LittleEndian.Uint32(page)
+	if uint32(len(page)-4) < n {
+		return nil, nil, errors.New("levels overflow the page")
+	}
+	r := thriftReader{buf: page[4 : 4+n]}
+	var levels []uint8
+	for len(r.buf) > 0 {
+		header, err := r.uvarint()
+		if err != nil {
+			return nil, nil, err
+		}
+		if header&1 != 0 {
+			return nil, nil, errors.New("unexpected bit-packed run")
+		}
+		v, err := r.readByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, v)
+		}
+	}
+	return levels, page[4+n:], nil
+}
+
+func TestParquetReadBack(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	columns := []parquetColumn{
+		makeParquetColumn("a", types.Int),
+		makeParquetColumn("b", types.String),
+		makeParquetColumn("c", types.MakeArray(types.Int)),
+		makeParquetColumn("d", types.Bool),
+	}
+	arr := tree.NewDArray(types.Int)
+	for _, d := range []tree.Datum{tree.NewDInt(1), tree.DNull, tree.NewDInt(2)} {
+		if err := arr.Append(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows := []tree.Datums{
+		{tree.NewDInt(1), tree.NewDString("x"), arr, tree.DBoolTrue},
+		{tree.DNull, tree.DNull, tree.DNull, tree.DBoolFalse},
+		{tree.NewDInt(3), tree.NewDString("yz"), tree.NewDArray(types.Int), tree.DNull},
+	}
+
+	expectedSchema := []struct {
+		name           string
+		repetitionType int64
+		numChildren    int64
+		physicalType   int64
+		convertedType  int64
+	}{
+		{name: "schema", repetitionType: -1, numChildren: 4, physicalType: -1, convertedType: -1},
+		{name: "a", repetitionType: parquetOptional, numChildren: -1, physicalType: parquetInt64, convertedType: -1},
+		{name: "b", repetitionType: parquetOptional, numChildren: -1, physicalType: parquetByteArray, convertedType: parquetUTF8},
+		{name: "c", repetitionType: parquetOptional, numChildren: 1, physicalType: -1, convertedType: parquetList},
+		{name: "list", repetitionType: parquetRepeated, numChildren: 1, physicalType: -1, convertedType: -1},
+		{name: "element", repetitionType: parquetOptional, numChildren: -1, physicalType: parquetInt64, convertedType: -1},
+		{name: "d", repetitionType: parquetOptional, numChildren: -1, physicalType: parquetBoolean, convertedType: -1},
+	}
+	expectedColumns := []struct {
+		path      []interface{}
+		defLevels []uint8
+		repLevels []uint8
+		values    []byte
+	}{
+		{
+			path:      []interface{}{"a"},
+			defLevels: []uint8{1, 0, 1},
+			values:    []byte{1, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			path:      []interface{}{"b"},
+			defLevels: []uint8{1, 0, 1},
+			values:    []byte{1, 0, 0, 0, 'x', 2, 0, 0, 0, 'y', 'z'},
+		},
+		{
+			path:      []interface{}{"c", "list", "element"},
+			defLevels: []uint8{3, 2, 3, 0, 1},
+			repLevels: []uint8{0, 1, 1, 0, 0},
+			values:    []byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			path:      []interface{}{"d"},
+			defLevels: []uint8{1, 1, 0},
+			values:    []byte{0x01},
+		},
+	}
+
+	// field returns the given integer field of a struct, or -1 if it is unset.
+	field := func(s thriftStructValue, id int16) int64 {
+		if v, ok := s[id]; ok {
+			return v.(int64)
+		}
+		return -1
+	}
+
+	for _, compression := range []distsqlpb.CSVWriterSpec_Compression{
+		distsqlpb.CSVWriterSpec_None, distsqlpb.CSVWriterSpec_Gzip, distsqlpb.CSVWriterSpec_Snappy,
+	} {
+		t.Run(compression.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			w := newParquetWriter(&out, append([]parquetColumn(nil), columns...), compression)
+			for _, row := range rows {
+				if err := w.writeRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.finish(); err != nil {
+				t.Fatal(err)
+			}
+			file := out.Bytes()
+
+			// A Parquet file starts and ends with the magic number, which is
+			// preceded by the length of the footer.
+			if !bytes.HasPrefix(file, []byte(parquetMagic)) || !bytes.HasSuffix(file, []byte(parquetMagic)) {
+				t.Fatalf("missing magic number in %x", file)
+			}
+			footerEnd := len(file) - len(parquetMagic) - 4
+			footerStart := footerEnd - int(binary.LittleEndian.Uint32(file[footerEnd:]))
+			if footerStart < len(parquetMagic) {
+				t.Fatalf("invalid footer length")
+			}
+			r := thriftReader{buf: file[footerStart:footerEnd]}
+			meta, err := r.readStruct()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r.buf) != 0 {
+				t.Fatalf("%d trailing bytes after the file metadata", len(r.buf))
+			}
+
+			if version := field(meta, 1); version != 1 {
+				t.Errorf("expected version 1, got %d", version)
+			}
+			if numRows := field(meta, 3); numRows != int64(len(rows)) {
+				t.Errorf("expected %d rows, got %d", len(rows), numRows)
+			}
+			schema := meta[2].([]interface{})
+			if len(schema) != len(expectedSchema) {
+				t.Fatalf("expected %d schema elements, got %d", len(expectedSchema), len(schema))
+			}
+			for i, expected := range expectedSchema {
+				elem := schema[i].(thriftStructValue)
+				if name := elem[4].(string); name != expected.name {
+					t.Errorf("schema element %d: expected name %s, got %s", i, expected.name, name)
+				}
+				if v := field(elem, 3); v != expected.repetitionType {
+					t.Errorf("schema element %s: expected repetition type %d, got %d", expected.name, expected.repetitionType, v)
+				}
+				if v := field(elem, 5); v != expected.numChildren {
+					t.Errorf("schema element %s: expected %d children, got %d", expected.name, expected.numChildren, v)
+				}
+				if v := field(elem, 1); v != expected.physicalType {
+					t.Errorf("schema element %s: expected type %d, got %d", expected.name, expected.physicalType, v)
+				}
+				if v := field(elem, 6); v != expected.convertedType {
+					t.Errorf("schema element %s: expected converted type %d, got %d", expected.name, expected.convertedType, v)
+				}
+			}
+
+			rowGroups := meta[4].([]interface{})
+			if len(rowGroups) != 1 {
+				t.Fatalf("expected a single row group, got %d", len(rowGroups))
+			}
+			rowGroup := rowGroups[0].(thriftStructValue)
+			if numRows := field(rowGroup, 3); numRows != int64(len(rows)) {
+				t.Errorf("expected %d rows in the row group, got %d", len(rows), numRows)
+			}
+			chunks := rowGroup[1].([]interface{})
+			if len(chunks) != len(expectedColumns) {
+				t.Fatalf("expected %d column chunks, got %d", len(expectedColumns), len(chunks))
+			}
+
+			// The column chunks follow each other from the start of the file up
+			// to the footer.
+			offset := int64(len(parquetMagic))
+			var totalSize int64
+			for i, expected := range expectedColumns {
+				chunkMeta := chunks[i].(thriftStructValue)[3].(thriftStructValue)
+				if path := chunkMeta[3].([]interface{}); !reflect.DeepEqual(expected.path, path) {
+					t.Errorf("column %d: expected path %v, got %v", i, expected.path, path)
+				}
+				if codec := field(chunkMeta, 4); codec != int64(w.codec) {
+					t.Errorf("column %d: expected codec %d, got %d", i, w.codec, codec)
+				}
+				if pageOffset := field(chunkMeta, 9); pageOffset != offset {
+					t.Fatalf("column %d: expected data page at offset %d, got %d", i, offset, pageOffset)
+				}
+				numValues := field(chunkMeta, 5)
+				if numValues != int64(len(expected.defLevels)) {
+					t.Errorf("column %d: expected %d values, got %d", i, len(expected.defLevels), numValues)
+				}
+
+				r := thriftReader{buf: file[offset:footerStart]}
+				header, err := r.readStruct()
+				if err != nil {
+					t.Fatal(err)
+				}
+				headerLen := int64(footerStart) - offset - int64(len(r.buf))
+				if pageType := field(header, 1); pageType != parquetDataPage {
+					t.Errorf("column %d: expected a data page, got page type %d", i, pageType)
+				}
+				uncompressedSize, compressedSize := field(header, 2), field(header, 3)
+				if size := field(chunkMeta, 7); size != headerLen+compressedSize {
+					t.Errorf("column %d: expected compressed chunk size %d, got %d", i, headerLen+compressedSize, size)
+				}
+				if size := field(chunkMeta, 6); size != headerLen+uncompressedSize {
+					t.Errorf("column %d: expected uncompressed chunk size %d, got %d", i, headerLen+uncompressedSize, size)
+				}
+				totalSize += headerLen + uncompressedSize
+				dataPageHeader := header[5].(thriftStructValue)
+				if n := field(dataPageHeader, 1); n != numValues {
+					t.Errorf("column %d: expected %d values in the data page, got %d", i, numValues, n)
+				}
+
+				if compressedSize < 0 || compressedSize > int64(len(r.buf)) {
+					t.Fatalf("column %d: invalid compressed page size %d", i, compressedSize)
+				}
+				page := r.buf[:compressedSize]
+				switch compression {
+				case distsqlpb.CSVWriterSpec_Gzip:
+					gz, err := gzip.NewReader(bytes.NewReader(page))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if page, err = ioutil.ReadAll(gz); err != nil {
+						t.Fatal(err)
+					}
+				case distsqlpb.CSVWriterSpec_Snappy:
+					if page, err = snappy.Decode(nil, page); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if int64(len(page)) != uncompressedSize {
+					t.Errorf("column %d: expected a page of %d bytes, got %d", i, uncompressedSize, len(page))
+				}
+
+				var repLevels, defLevels []uint8
+				if expected.repLevels != nil {
+					if repLevels, page, err = readLevels(page); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if defLevels, page, err = readLevels(page); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(expected.repLevels, repLevels) {
+					t.Errorf("column %d: expected repetition levels %v, got %v", i, expected.repLevels, repLevels)
+				}
+				if !bytes.Equal(expected.defLevels, defLevels) {
+					t.Errorf("column %d: expected definition levels %v, got %v", i, expected.defLevels, defLevels)
+				}
+				if !bytes.Equal(expected.values, page) {
+					t.Errorf("column %d: expected values %v, got %v", i, expected.values, page)
+				}
+				offset += headerLen + compressedSize
+			}
+			if offset != int64(footerStart) {
+				t.Errorf("expected the footer at offset %d, got %d", offset, footerStart)
+			}
+			if size := field(rowGroup, 2); size != totalSize {
+				t.Errorf("expected a row group of %d bytes, got %d", totalSize, size)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"context"
 	"crypto/sha512"
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -20,7 +21,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/pkg/errors"
@@ -35,6 +38,8 @@ func declareKeysExport(
 ) {
 	batcheval.DefaultDeclareKeys(desc, header, req, spans)
 	spans.Add(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
+	prefix := keys.RangeTombstonePrefix(header.RangeID)
+	spans.Add(spanset.SpanReadOnly, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
 }
 
 // getDBEngine recursively searches for the underlying RocksDB or
@@ -135,6 +140,19 @@ func evalExport(
 		return result.Result{}, err
 	}
 
+	tombstones, err := engine.MVCCScanRangeTombstones(
+		ctx, batch, h.RangeID, args.Key, args.EndKey)
+	if err != nil {
+		return result.Result{}, err
+	}
+	if len(tombstones) > 0 {
+		data, summary.DataSize, err = applyRangeTombstones(
+			batch, data, tombstones, args.Span(), args.StartTime, h.Timestamp, exportAllRevisions)
+		if err != nil {
+			return result.Result{}, err
+		}
+	}
+
 	if summary.DataSize == 0 {
 		reply.Files = []roachpb.ExportResponse_File{}
 		return result.Result{}, nil
@@ -170,6 +188,147 @@ func evalExport(
 	return result.Result{}, nil
 }
 
+// applyRangeTombstones rewrites the exported data to account for the given
+// MVCC range tombstones, which ExportToSst does not take into account. Values
+// deleted by a range tombstone are removed unless all revisions are exported,
+// and the deletions performed by range tombstones in (startTime, endTime] are
+// added as regular deletion tombstones for each of the keys they delete, so
+// that the export can be restored without range tombstone support. Returns
+// the rewritten data and its size. Note that the export is materialized in
+// memory, which is acceptable since exports are bounded by the range size.
+func applyRangeTombstones(
+	reader engine.Reader,
+	data []byte,
+	tombstones []enginepb.MVCCRangeTombstone,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	exportAllRevisions bool,
+) ([]byte, int64, error) {
+	var kvs []engine.MVCCKeyValue
+	if len(data) > 0 {
+		iter, err := engine.NewMemSSTIterator(data, false /* verify */)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer iter.Close()
+		for iter.Seek(engine.MVCCKey{Key: span.Key}); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return nil, 0, err
+			} else if !ok {
+				break
+			}
+			unsafeKey := iter.UnsafeKey()
+			if !exportAllRevisions {
+				if _, ok := engine.CoveringRangeTombstone(
+					tombstones, unsafeKey.Key, unsafeKey.Timestamp, endTime,
+				); ok {
+					continue
+				}
+			}
+			kvs = append(kvs, engine.MVCCKeyValue{
+				Key: engine.MVCCKey{
+					Key:       append(roachpb.Key(nil), unsafeKey.Key...),
+					Timestamp: unsafeKey.Timestamp,
+				},
+				Value: append([]byte(nil), iter.UnsafeValue()...),
+			})
+		}
+	}
+
+	// A full export of the latest values doesn't contain deletions.
+	if exportAllRevisions || !startTime.IsEmpty() {
+		for _, t := range tombstones {
+			if !startTime.Less(t.Timestamp) || endTime.Less(t.Timestamp) {
+				continue
+			}
+			deletions, err := rangeTombstoneDeletions(reader, tombstones, t, span)
+			if err != nil {
+				return nil, 0, err
+			}
+			kvs = append(kvs, deletions...)
+		}
+	}
+
+	// Sort the keys and remove duplicates. Only the newest version of each
+	// key is retained when exporting the latest values.
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key.Less(kvs[j].Key)
+	})
+	sst, err := engine.MakeRocksDBSstFileWriter()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer sst.Close()
+	var prev engine.MVCCKey
+	for i, kv := range kvs {
+		if i > 0 && (kv.Key.Equal(prev) || (!exportAllRevisions && kv.Key.Key.Equal(prev.Key))) {
+			continue
+		}
+		prev = kv.Key
+		if err := sst.Add(kv); err != nil {
+			return nil, 0, err
+		}
+	}
+	if sst.DataSize == 0 {
+		return nil, 0, nil
+	}
+	res, err := sst.Finish()
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, sst.DataSize, nil
+}
+
+// rangeTombstoneDeletions returns a deletion tombstone at the timestamp of
+// the given range tombstone for each key in span that it deletes.
+func rangeTombstoneDeletions(
+	reader engine.Reader,
+	tombstones []enginepb.MVCCRangeTombstone,
+	t enginepb.MVCCRangeTombstone,
+	span roachpb.Span,
+) ([]engine.MVCCKeyValue, error) {
+	key, endKey := roachpb.Key(t.StartKey), roachpb.Key(t.EndKey)
+	if key.Compare(span.Key) < 0 {
+		key = span.Key
+	}
+	if span.EndKey.Compare(endKey) < 0 {
+		endKey = span.EndKey
+	}
+	iter := reader.NewIterator(engine.IterOptions{UpperBound: endKey})
+	defer iter.Close()
+
+	var deletions []engine.MVCCKeyValue
+	for iter.Seek(engine.MakeMVCCMetadataKey(key)); ; {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() || t.Timestamp.Less(unsafeKey.Timestamp) {
+			iter.Next()
+			continue
+		}
+		// This is the latest version of the key at or below the range
+		// tombstone. The range tombstone deletes it only if it's a value
+		// which wasn't already deleted by an older range tombstone.
+		if len(iter.UnsafeValue()) > 0 {
+			if ts, ok := engine.CoveringRangeTombstone(
+				tombstones, unsafeKey.Key, unsafeKey.Timestamp, t.Timestamp,
+			); ok && ts == t.Timestamp {
+				deletions = append(deletions, engine.MVCCKeyValue{
+					Key: engine.MVCCKey{
+						Key:       append(roachpb.Key(nil), unsafeKey.Key...),
+						Timestamp: t.Timestamp,
+					},
+				})
+			}
+		}
+		iter.NextKey()
+	}
+	return deletions, nil
+}
+
 // SHA512ChecksumData returns the SHA512 checksum of data.
 func SHA512ChecksumData(data []byte) ([]byte, error) {
 	h := sha512.New()
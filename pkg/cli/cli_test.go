@@ -2375,6 +2375,7 @@ writing ` + os.DevNull + `
   debug/crdb_internal.partitions.txt
   debug/crdb_internal.zones.txt
   debug/nodes/1/status.json
+  debug/nodes/1/crdb_internal.changefeed_status.txt
   debug/nodes/1/crdb_internal.feature_usage.txt
   debug/nodes/1/crdb_internal.gossip_alerts.txt
   debug/nodes/1/crdb_internal.gossip_liveness.txt
@@ -2412,6 +2413,7 @@ writing ` + os.DevNull + `
   debug/nodes/1/ranges/18.json
   debug/nodes/1/ranges/19.json
   debug/nodes/1/ranges/20.json
+  debug/nodes/1/ranges/21.json
   debug/schema/defaultdb@details.json
   debug/schema/postgres@details.json
   debug/schema/system@details.json
@@ -2422,6 +2424,7 @@ writing ` + os.DevNull + `
   debug/schema/system/lease.json
   debug/schema/system/locations.json
   debug/schema/system/namespace.json
+  debug/schema/system/protected_ts_records.json
   debug/schema/system/rangelog.json
   debug/schema/system/role_members.json
   debug/schema/system/settings.json
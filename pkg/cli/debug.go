@@ -532,11 +532,13 @@ func runDebugGCCmd(cmd *cobra.Command, args []string) error {
 	for _, desc := range descs {
 		snap := db.NewSnapshot()
 		defer snap.Close()
+		now := hlc.Timestamp{WallTime: timeutil.Now().UnixNano()}
 		info, err := storage.RunGC(
 			context.Background(),
 			&desc,
 			snap,
-			hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
+			now,
+			now,
 			config.GCPolicy{TTLSeconds: int32(gcTTLInSeconds)},
 			storage.NoopGCer{},
 			func(_ context.Context, _ []roachpb.Intent) error { return nil },
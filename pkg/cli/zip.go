@@ -67,6 +67,8 @@ var debugZipTablesPerCluster = []string{
 
 // Tables collected from each node in a debug zip.
 var debugZipTablesPerNode = []string{
+	"crdb_internal.changefeed_status",
+
 	"crdb_internal.feature_usage",
 
 	"crdb_internal.gossip_alerts",
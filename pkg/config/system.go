@@ -57,6 +57,13 @@ type zoneEntry struct {
 	combined *ZoneConfig
 }
 
+// SystemConfigProvider provides access to the latest SystemConfig.
+type SystemConfigProvider interface {
+	// GetSystemConfig returns the latest SystemConfig, or nil if it isn't
+	// available yet.
+	GetSystemConfig() *SystemConfig
+}
+
 // SystemConfig embeds a SystemConfigEntries message which contains an
 // entry for every system descriptor (e.g. databases, tables, zone
 // configs). It also has a map from object ID to unmarshaled zone
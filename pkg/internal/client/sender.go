@@ -205,6 +205,35 @@ type TxnSender interface {
 	// However, this is used by DistSQL for sending the transaction over the wire
 	// when it creates flows.
 	SerializeTxn() *roachpb.Transaction
+
+	// CreateSavepoint establishes a savepoint. The returned token can later be
+	// passed to RollbackToSavepoint or ReleaseSavepoint.
+	//
+	// This method is only valid when called on RootTxns.
+	CreateSavepoint(context.Context) (SavepointToken, error)
+
+	// RollbackToSavepoint rolls back to the given savepoint: the writes
+	// performed by the transaction after the savepoint was established
+	// become invisible to the transaction and are discarded when the
+	// transaction finishes. Savepoints established after the given one
+	// become invalid. The token remains valid and can be rolled back to
+	// again.
+	//
+	// An error is returned if the transaction has been restarted (i.e. its
+	// epoch has been bumped) since the savepoint was established.
+	RollbackToSavepoint(context.Context, SavepointToken) error
+
+	// ReleaseSavepoint releases the given savepoint. Savepoints established
+	// after the given one are also released.
+	ReleaseSavepoint(context.Context, SavepointToken) error
+}
+
+// SavepointToken represents a savepoint established in a transaction. It is
+// opaque to clients and only meaningful to the TxnSender that created it.
+type SavepointToken interface {
+	// Initial returns true if the savepoint was established before the
+	// transaction performed any writes.
+	Initial() bool
 }
 
 // TxnStatusOpt represents options for TxnSender.GetMeta().
@@ -356,6 +385,21 @@ func (m *MockTransactionalSender) SerializeTxn() *roachpb.Transaction {
 	return m.txn.Clone()
 }
 
+// CreateSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) CreateSavepoint(context.Context) (SavepointToken, error) {
+	panic("unimplemented")
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) RollbackToSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) ReleaseSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
 // UpdateStateOnRemoteRetryableErr is part of the TxnSender interface.
 func (m *MockTransactionalSender) UpdateStateOnRemoteRetryableErr(
 	ctx context.Context, pErr *roachpb.Error,
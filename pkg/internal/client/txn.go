@@ -75,6 +75,11 @@ type Txn struct {
 		// The txn has to be committed by this deadline. A nil value indicates no
 		// deadline.
 		deadline *hlc.Timestamp
+
+		// minTimestampBound, if set, is the minimum timestamp bound of the
+		// bounded staleness read which the txn has yet to perform. See
+		// SetMinTimestampBound.
+		minTimestampBound hlc.Timestamp
 	}
 }
 
@@ -785,7 +790,12 @@ func (txn *Txn) Send(
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
 	sender := txn.mu.sender
+	minTimestampBound := txn.mu.minTimestampBound
+	txn.mu.minTimestampBound = hlc.Timestamp{}
 	txn.mu.Unlock()
+	if minTimestampBound != (hlc.Timestamp{}) {
+		return txn.negotiateAndSend(ctx, ba, minTimestampBound)
+	}
 	br, pErr := txn.db.sendUsingSender(ctx, ba, sender)
 	if pErr == nil {
 		return br, nil
@@ -806,6 +816,27 @@ func (txn *Txn) Send(
 	return br, pErr
 }
 
+// negotiateAndSend sends the first batch of a txn which performs a bounded
+// staleness read. The batch is sent non-transactionally with the provided
+// minimum timestamp bound, and the txn's timestamp is then fixed to the
+// timestamp at which the replica serving the batch evaluated it.
+func (txn *Txn) negotiateAndSend(
+	ctx context.Context, ba roachpb.BatchRequest, minTimestampBound hlc.Timestamp,
+) (*roachpb.BatchResponse, *roachpb.Error) {
+	if !ba.IsReadOnly() {
+		return nil, roachpb.NewErrorf(
+			"the first batch of a bounded staleness txn must be read-only: %s", ba)
+	}
+	ba.BoundedStaleness = &roachpb.BoundedStalenessHeader{MinTimestampBound: minTimestampBound}
+	br, pErr := txn.db.sendUsingSender(ctx, ba, txn.db.NonTransactionalSender())
+	if pErr != nil {
+		return nil, pErr
+	}
+	log.VEventf(ctx, 2, "bounded staleness read served at %s", br.Timestamp)
+	txn.SetFixedTimestamp(ctx, br.Timestamp)
+	return br, nil
+}
+
 func (txn *Txn) handleErrIfRetryableLocked(ctx context.Context, err error) {
 	retryErr, ok := err.(*roachpb.TransactionRetryWithProtoRefreshError)
 	if !ok {
@@ -935,6 +966,22 @@ func (txn *Txn) SetFixedTimestamp(ctx context.Context, ts hlc.Timestamp) {
 	txn.mu.sender.SetFixedTimestamp(ctx, ts)
 }
 
+// SetMinTimestampBound makes the transaction perform a bounded staleness read.
+// Instead of running at a timestamp chosen up front, the transaction sends its
+// first batch, which must be read-only and confined to a single range, to the
+// closest replica. That replica serves it at the newest timestamp at or above
+// minTimestampBound that it can serve locally, and the transaction's timestamp
+// is then fixed to that timestamp as with SetFixedTimestamp.
+//
+// This is used to support AS OF SYSTEM TIME queries with with_max_staleness()
+// and with_min_timestamp(). It must be called before any operations are
+// performed on the transaction, and on every transaction retry.
+func (txn *Txn) SetMinTimestampBound(minTimestampBound hlc.Timestamp) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.minTimestampBound = minTimestampBound
+}
+
 // GenerateForcedRetryableError returns a TransactionRetryWithProtoRefreshError that will
 // cause the txn to be retried.
 //
@@ -1006,6 +1053,39 @@ func (txn *Txn) Serialize() *roachpb.Transaction {
 	return txn.mu.sender.SerializeTxn()
 }
 
+// CreateSavepoint establishes a savepoint.
+// This method is only valid when called on RootTxns.
+func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
+	if txn.typ != RootTxn {
+		return nil, errors.Errorf("CreateSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.CreateSavepoint(ctx)
+}
+
+// RollbackToSavepoint rolls back to the given savepoint.
+// This method is only valid when called on RootTxns.
+func (txn *Txn) RollbackToSavepoint(ctx context.Context, s SavepointToken) error {
+	if txn.typ != RootTxn {
+		return errors.Errorf("RollbackToSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.RollbackToSavepoint(ctx, s)
+}
+
+// ReleaseSavepoint releases the given savepoint.
+// This method is only valid when called on RootTxns.
+func (txn *Txn) ReleaseSavepoint(ctx context.Context, s SavepointToken) error {
+	if txn.typ != RootTxn {
+		return errors.Errorf("ReleaseSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.ReleaseSavepoint(ctx, s)
+}
+
 func (txn *Txn) deadline() *hlc.Timestamp {
 	txn.mu.Lock()
 	defer txn.mu.Unlock()
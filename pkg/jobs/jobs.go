@@ -113,6 +113,22 @@ func SimplifyInvalidStatusError(err error) error {
 	return errors.Errorf("job %s", ierr.status)
 }
 
+// JobNotFoundError is the error returned when a job does not exist in the
+// system.jobs table.
+type JobNotFoundError struct {
+	id int64
+}
+
+func (e *JobNotFoundError) Error() string {
+	return fmt.Sprintf("job with ID %d does not exist", e.id)
+}
+
+// HasJobNotFoundError returns whether the error is a *JobNotFoundError.
+func HasJobNotFoundError(err error) bool {
+	_, ok := errors.Cause(err).(*JobNotFoundError)
+	return ok
+}
+
 // ID returns the ID of the job that this Job is currently tracking. This will
 // be nil if Created has not yet been called.
 func (j *Job) ID() *int64 {
@@ -448,7 +464,7 @@ func (j *Job) load(ctx context.Context) error {
 			return err
 		}
 		if row == nil {
-			return fmt.Errorf("job with ID %d does not exist", *j.id)
+			return &JobNotFoundError{id: *j.id}
 		}
 		payload, err = UnmarshalPayload(row[0])
 		if err != nil {
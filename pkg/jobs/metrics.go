@@ -13,6 +13,7 @@ package jobs
 import (
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 )
 
@@ -34,3 +35,27 @@ func (m *Metrics) InitHooks(histogramWindowInterval time.Duration) {
 // MakeChangefeedMetricsHook allows for registration of changefeed metrics from
 // ccl code.
 var MakeChangefeedMetricsHook func(time.Duration) metric.Struct
+
+// ChangefeedStatus is a snapshot of one changefeed processor running on this
+// node. It's used to populate crdb_internal.changefeed_status.
+type ChangefeedStatus struct {
+	// JobID is the id of the changefeed's job, or 0 for a sinkless changefeed.
+	JobID int64
+	// Processor is the kind of changefeed processor: "aggregator" or "frontier".
+	Processor string
+	// SinkURI is the changefeed's sink with any secrets removed.
+	SinkURI string
+	// Spans are the spans tracked by the processor, each with its resolved
+	// timestamp.
+	Spans []jobspb.ResolvedSpan
+	// EmittedMessages and EmittedBytes are the totals written to the sink by the
+	// processor.
+	EmittedMessages, EmittedBytes int64
+}
+
+// ChangefeedStatusSource is implemented by the changefeed metrics (see
+// MakeChangefeedMetricsHook) to list the changefeed processors running on this
+// node.
+type ChangefeedStatusSource interface {
+	ChangefeedStatuses() []ChangefeedStatus
+}
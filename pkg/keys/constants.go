@@ -124,6 +124,9 @@ var (
 	LocalRangeLeaseSuffix = []byte("rll-")
 	// LocalLeaseAppliedIndexLegacySuffix is the suffix for the applied lease index.
 	LocalLeaseAppliedIndexLegacySuffix = []byte("rlla")
+	// LocalRangeTombstoneSuffix is the suffix for MVCC range tombstones. See
+	// enginepb.MVCCRangeTombstone.
+	LocalRangeTombstoneSuffix = []byte("rtmb")
 	// LocalRangeStatsLegacySuffix is the suffix for range statistics.
 	LocalRangeStatsLegacySuffix = []byte("stat")
 	// LocalTxnSpanGCThresholdSuffix is the suffix for the last txn span GC's
@@ -321,6 +324,8 @@ const (
 	RoleMembersTableID     = 23
 	CommentsTableID        = 24
 
+	ProtectedTimestampsRecordsTableID = 25
+
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
 	TableCommentType    = 1
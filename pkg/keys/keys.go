@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -216,6 +217,20 @@ func DecodeAbortSpanKey(key roachpb.Key, dest []byte) (uuid.UUID, error) {
 	return txnID, err
 }
 
+// RangeTombstonePrefix returns the range-ID local prefix under which the
+// MVCC range tombstones of the range are stored.
+func RangeTombstonePrefix(rangeID roachpb.RangeID) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RangeTombstonePrefix()
+}
+
+// RangeTombstoneKey returns a range-local key by Range ID for an MVCC range
+// tombstone, with detail specified by encoding the tombstone's start key
+// and timestamp. Range tombstones of a range thus sort by start key and,
+// for equal start keys, by ascending timestamp.
+func RangeTombstoneKey(rangeID roachpb.RangeID, startKey roachpb.Key, ts hlc.Timestamp) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RangeTombstoneKey(startKey, ts)
+}
+
 // RaftTombstoneIncorrectLegacyKey returns a system-local key for a raft tombstone.
 // This key was accidentally made replicated though it is not, requiring awkward
 // workarounds. This method and all users can be removed in any binary version > 2.1.
@@ -875,6 +890,20 @@ func (b RangeIDPrefixBuf) AbortSpanKey(txnID uuid.UUID) roachpb.Key {
 	return encoding.EncodeBytesAscending(key, txnID.GetBytes())
 }
 
+// RangeTombstonePrefix returns the range-ID local prefix for MVCC range
+// tombstones.
+func (b RangeIDPrefixBuf) RangeTombstonePrefix() roachpb.Key {
+	return append(b.replicatedPrefix(), LocalRangeTombstoneSuffix...)
+}
+
+// RangeTombstoneKey returns a range-local key by Range ID for an MVCC range
+// tombstone. See comment on RangeTombstoneKey function.
+func (b RangeIDPrefixBuf) RangeTombstoneKey(startKey roachpb.Key, ts hlc.Timestamp) roachpb.Key {
+	key := encoding.EncodeBytesAscending(b.RangeTombstonePrefix(), startKey)
+	key = encoding.EncodeUvarintAscending(key, uint64(ts.WallTime))
+	return encoding.EncodeUvarintAscending(key, uint64(ts.Logical))
+}
+
 // RaftTombstoneIncorrectLegacyKey returns a system-local key for a raft tombstone.
 func (b RangeIDPrefixBuf) RaftTombstoneIncorrectLegacyKey() roachpb.Key {
 	return append(b.replicatedPrefix(), LocalRaftTombstoneSuffix...)
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -151,6 +152,7 @@ func TestKeyAddressError(t *testing.T) {
 			RaftLogKey(0, 0),
 			RangeLastReplicaGCTimestampKey(0),
 			RangeLastVerificationTimestampKeyDeprecated(0),
+			RangeTombstoneKey(0, roachpb.Key("a"), hlc.Timestamp{WallTime: 1}),
 			RangeDescriptorKey(roachpb.RKey(RangeLastVerificationTimestampKeyDeprecated(0))),
 		},
 		"local key .* malformed": {
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -155,6 +156,7 @@ var (
 		{name: "RangeTxnSpanGCThreshold", suffix: LocalTxnSpanGCThresholdSuffix},
 		{name: "RangeFrozenStatus", suffix: LocalRangeFrozenStatusSuffix},
 		{name: "RangeLastGC", suffix: LocalRangeLastGCSuffix},
+		{name: "RangeTombstone", suffix: LocalRangeTombstoneSuffix, ppFunc: rangeTombstoneKeyPrint},
 	}
 
 	rangeSuffixDict = []struct {
@@ -478,6 +480,23 @@ func abortSpanKeyPrint(key roachpb.Key) string {
 	return fmt.Sprintf("/%q", txnID)
 }
 
+func rangeTombstoneKeyPrint(key roachpb.Key) string {
+	b, startKey, err := encoding.DecodeBytesAscending([]byte(key), nil)
+	if err != nil {
+		return fmt.Sprintf("/%q/err:%v", key, err)
+	}
+	b, wallTime, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return fmt.Sprintf("/%q/err:%v", key, err)
+	}
+	_, logical, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return fmt.Sprintf("/%q/err:%v", key, err)
+	}
+	ts := hlc.Timestamp{WallTime: int64(wallTime), Logical: int32(logical)}
+	return fmt.Sprintf("/%s/%s", roachpb.Key(startKey), ts)
+}
+
 func print(_ []encoding.Direction, key roachpb.Key) string {
 	return fmt.Sprintf("/%q", []byte(key))
 }
@@ -548,6 +548,18 @@ func (ds *DistSender) initAndVerifyBatch(
 		return roachpb.NewErrorf("empty batch")
 	}
 
+	// The timestamp of a bounded staleness read is chosen by the replica that
+	// serves it.
+	if ba.BoundedStaleness != nil {
+		if ba.Txn != nil || !ba.IsReadOnly() || ba.ReadConsistency != roachpb.CONSISTENT {
+			return roachpb.NewErrorf(
+				"bounded staleness reads must be consistent, read-only and non-transactional: %s", ba)
+		}
+		if ba.Timestamp != (hlc.Timestamp{}) {
+			return roachpb.NewErrorf("bounded staleness read must not set batch timestamp: %s", ba)
+		}
+	}
+
 	if ba.MaxSpanRequestKeys != 0 {
 		// Verify that the batch contains only specific range requests or the
 		// Begin/EndTransactionRequest. Verify that a batch with a ReverseScan
@@ -1095,6 +1107,12 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		mismatch := roachpb.NewRangeKeyMismatchError(rs.Key.AsRawKey(), rs.EndKey.AsRawKey(), ri.Desc())
 		return nil, roachpb.NewError(mismatch)
 	}
+	// A bounded staleness read negotiates its timestamp with a single range, so
+	// it can't be split.
+	if ba.BoundedStaleness != nil {
+		return nil, roachpb.NewErrorf(
+			"bounded staleness read spans multiple ranges: %s", rs)
+	}
 	// If there's no transaction and ba spans ranges, possibly re-run as part of
 	// a transaction for consistency. The case where we don't need to re-run is
 	// if the read consistency is not required.
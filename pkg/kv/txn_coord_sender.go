@@ -1089,6 +1089,13 @@ func (tc *TxnCoordSender) updateStateLocked(
 
 	// This is the non-retriable error case.
 	if errTxn := pErr.GetTxn(); errTxn != nil {
+		// A ConditionFailedError does not leave any trace of the failed write
+		// behind, so it is safe to let the transaction continue. In particular,
+		// this allows SQL to recover from it by rolling back to a savepoint.
+		if _, ok := pErr.GetDetail().(*roachpb.ConditionFailedError); ok {
+			tc.mu.txn.Update(errTxn)
+			return pErr
+		}
 		tc.mu.txnState = txnError
 		tc.mu.storedErr = roachpb.NewError(&roachpb.TxnAlreadyEncounteredErrorError{
 			PrevError: pErr.String(),
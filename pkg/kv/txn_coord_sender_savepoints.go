@@ -0,0 +1,145 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// savepoint captures the state of a TxnCoordSender that is necessary to roll
+// back to it. Savepoints are implemented on top of the sequence numbers
+// allocated by the txnSeqNumAllocator: rolling back to a savepoint marks all
+// the sequence numbers allocated since the savepoint was established as
+// ignored in the transaction proto. Reads performed by the transaction skip
+// writes at ignored sequence numbers and intent resolution discards them.
+type savepoint struct {
+	// txnID and epoch identify the transaction incarnation in which the
+	// savepoint was established. Savepoints do not survive epoch bumps since
+	// all the writes from the previous epoch are discarded anyway.
+	txnID uuid.UUID
+	epoch enginepb.TxnEpoch
+
+	// seqNum is the sequence number of the last write performed before the
+	// savepoint was established.
+	seqNum enginepb.TxnSeq
+}
+
+var _ client.SavepointToken = (*savepoint)(nil)
+
+// Initial is part of the client.SavepointToken interface.
+func (s *savepoint) Initial() bool {
+	return s.seqNum == 0
+}
+
+// CreateSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (client.SavepointToken, error) {
+	if tc.typ != client.RootTxn {
+		return nil, errors.Errorf("cannot get savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertSavepointUsableLocked(); err != nil {
+		return nil, err
+	}
+
+	return &savepoint{
+		txnID:  tc.mu.txn.ID,
+		epoch:  tc.mu.txn.Epoch,
+		seqNum: tc.interceptorAlloc.txnSeqNumAllocator.seqGen,
+	}, nil
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) RollbackToSavepoint(
+	ctx context.Context, s client.SavepointToken,
+) error {
+	if tc.typ != client.RootTxn {
+		return errors.Errorf("cannot rollback savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertSavepointUsableLocked(); err != nil {
+		return err
+	}
+	sp := s.(*savepoint)
+	if err := tc.checkSavepointLocked(sp); err != nil {
+		return err
+	}
+
+	// Mark all the sequence numbers allocated after the savepoint as ignored.
+	// If no writes were performed since the savepoint was established, there
+	// is nothing to do.
+	if seqGen := tc.interceptorAlloc.txnSeqNumAllocator.seqGen; seqGen > sp.seqNum {
+		tc.mu.txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{
+			Start: sp.seqNum + 1, End: seqGen,
+		})
+	}
+	return nil
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) ReleaseSavepoint(ctx context.Context, s client.SavepointToken) error {
+	if tc.typ != client.RootTxn {
+		return errors.Errorf("cannot release savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertSavepointUsableLocked(); err != nil {
+		return err
+	}
+	// Releasing a savepoint does not require any KV state changes: the writes
+	// performed since the savepoint simply become part of the enclosing
+	// savepoint (or of the transaction).
+	return tc.checkSavepointLocked(s.(*savepoint))
+}
+
+// assertSavepointUsableLocked returns an error if the transaction is in a state
+// that doesn't allow savepoint operations.
+func (tc *TxnCoordSender) assertSavepointUsableLocked() error {
+	switch tc.mu.txnState {
+	case txnFinalized:
+		return roachpb.NewTransactionStatusError(
+			"cannot use savepoints in a committed or rolled back transaction")
+	case txnError:
+		return tc.mu.storedErr.GoError()
+	}
+	if tc.mu.txn.Status != roachpb.PENDING {
+		return roachpb.NewTransactionStatusError(
+			"cannot use savepoints in a transaction that is not pending")
+	}
+	return nil
+}
+
+// checkSavepointLocked checks whether the provided savepoint is still valid.
+// Savepoints are invalidated by transaction restarts.
+func (tc *TxnCoordSender) checkSavepointLocked(s *savepoint) error {
+	if s.txnID != tc.mu.txn.ID || s.epoch != tc.mu.txn.Epoch {
+		return errors.Errorf("savepoint established in a previous transaction attempt " +
+			"is no longer valid after a transaction restart")
+	}
+	if s.seqNum > tc.interceptorAlloc.txnSeqNumAllocator.seqGen {
+		return errors.Errorf("invalid savepoint: seqnum %d > current seqnum %d",
+			s.seqNum, tc.interceptorAlloc.txnSeqNumAllocator.seqGen)
+	}
+	return nil
+}
@@ -2245,3 +2245,106 @@ func TestAugmentTxnCoordMetaAbortedTxn(t *testing.T) {
 		t.Fatalf("expected PENDING txn, got: %s", txnProto.Status)
 	}
 }
+
+// TestTxnCoordSenderRollbackToSavepointWithHeartbeat verifies that a heartbeat
+// response carrying the transaction's ignored seqnum ranges as of before a
+// savepoint rollback does not overwrite the ranges added by the rollback, even
+// when the rollback merged existing ranges into fewer ones.
+func TestTxnCoordSenderRollbackToSavepointWithHeartbeat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+	sender := &mockSender{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	var staleTxn *roachpb.Transaction
+	var endTxnIgnored []enginepb.IgnoredSeqNumRange
+	sender.match(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		br := ba.CreateReply()
+		br.Txn = ba.Txn.Clone()
+		if _, ok := ba.GetArg(roachpb.HeartbeatTxn); ok {
+			br.Txn = staleTxn.Clone()
+			br.Responses[0].GetHeartbeatTxn().Txn = staleTxn.Clone()
+		}
+		if _, ok := ba.GetArg(roachpb.EndTransaction); ok {
+			endTxnIgnored = ba.Txn.IgnoredSeqNums
+			br.Txn.Status = roachpb.COMMITTED
+		}
+		return br, nil
+	})
+	factory := NewTxnCoordSenderFactory(
+		TxnCoordSenderFactoryConfig{
+			AmbientCtx: ambient,
+			Clock:      clock,
+			Stopper:    stopper,
+			// Heartbeats are sent manually below.
+			HeartbeatInterval: time.Hour,
+		},
+		sender,
+	)
+	db := client.NewDB(testutils.MakeAmbientCtx(), factory, clock)
+	txn := client.NewTxn(ctx, db, 0 /* gatewayNodeID */, client.RootTxn)
+	tc := txn.Sender().(*TxnCoordSender)
+
+	put := func(key string) {
+		t.Helper()
+		if err := txn.Put(ctx, key, "val"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	savepoint := func() client.SavepointToken {
+		t.Helper()
+		sp, err := txn.CreateSavepoint(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sp
+	}
+	rollback := func(sp client.SavepointToken) {
+		t.Helper()
+		if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expIgnored := func(exp []enginepb.IgnoredSeqNumRange) {
+		t.Helper()
+		if a := txn.Serialize().IgnoredSeqNums; !reflect.DeepEqual(a, exp) {
+			t.Fatalf("expected ignored seqnums %v; got %v", exp, a)
+		}
+	}
+
+	put("a") // seq 1
+	sp1 := savepoint()
+	put("b") // seq 2
+	put("c") // seq 3
+	rollback(sp1)
+	put("d") // seq 4
+	sp2 := savepoint()
+	put("e") // seq 5
+	put("f") // seq 6
+	rollback(sp2)
+	expIgnored([]enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}, {Start: 5, End: 6}})
+
+	// Capture the txn as a heartbeat sent now would see it, then roll back to
+	// the outer savepoint, which merges both ranges into one.
+	staleTxn = txn.Serialize()
+	put("g") // seq 7
+	rollback(sp1)
+	merged := []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}
+	expIgnored(merged)
+
+	// The heartbeat response arrives after the rollback.
+	if !tc.interceptorAlloc.txnHeartbeater.heartbeat(ctx) {
+		t.Fatal("expected heartbeat loop to continue")
+	}
+	expIgnored(merged)
+
+	if err := txn.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(endTxnIgnored, merged) {
+		t.Fatalf("expected EndTransaction with ignored seqnums %v; got %v", merged, endTxnIgnored)
+	}
+}
@@ -502,6 +502,13 @@ func (tp *txnPipeliner) updateWriteTracking(
 					tp.footprint.insert(sp)
 				}
 			}
+		} else if roachpb.IsLocking(req) {
+			// If the request was a locking read, track the span of the keys it
+			// may have locked so that the locks are released when the
+			// transaction's intents are resolved.
+			if sp, ok := roachpb.ActualSpan(req, resp); ok {
+				tp.footprint.insert(sp)
+			}
 		}
 	}
 }
@@ -174,6 +174,15 @@ func CanBackpressure(args Request) bool {
 	return (args.flags() & canBackpressure) != 0
 }
 
+// IsLocking returns true if the request is a read which acquires
+// unreplicated locks on the keys it returns.
+func IsLocking(args Request) bool {
+	if lr, ok := args.(LockingReadRequest); ok {
+		return lr.KeyLockingStrength() != NON_LOCKING
+	}
+	return false
+}
+
 // Request is an interface for RPC requests.
 type Request interface {
 	protoutil.Message
@@ -188,6 +197,34 @@ type Request interface {
 	flags() int
 }
 
+// LockingReadRequest is implemented by read requests which can acquire
+// unreplicated locks on the keys they return.
+type LockingReadRequest interface {
+	Request
+	// KeyLockingStrength returns the strength of the locks acquired by the
+	// request, or NON_LOCKING if it doesn't acquire any.
+	KeyLockingStrength() KeyLockingStrength
+}
+
+var _ LockingReadRequest = &GetRequest{}
+var _ LockingReadRequest = &ScanRequest{}
+var _ LockingReadRequest = &ReverseScanRequest{}
+
+// KeyLockingStrength implements the LockingReadRequest interface.
+func (gr *GetRequest) KeyLockingStrength() KeyLockingStrength {
+	return gr.KeyLocking
+}
+
+// KeyLockingStrength implements the LockingReadRequest interface.
+func (sr *ScanRequest) KeyLockingStrength() KeyLockingStrength {
+	return sr.KeyLocking
+}
+
+// KeyLockingStrength implements the LockingReadRequest interface.
+func (rsr *ReverseScanRequest) KeyLockingStrength() KeyLockingStrength {
+	return rsr.KeyLocking
+}
+
 // leaseRequestor is implemented by requests dealing with leases.
 // Implementors return the previous lease at the time the request
 // was proposed.
@@ -1017,6 +1054,12 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isWrite | isRange | isAlone
 	}
+	// A DeleteRange that writes an MVCC range tombstone cannot be part of a
+	// transaction either. It consults the timestamp cache so that the
+	// tombstone is never written beneath a read that has already been served.
+	if drr.UseRangeTombstone {
+		return isWrite | isRange | isAlone | consultsTSCache
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave
 	// intents or tombstones for keys which don't yet exist. By updating
 	// the write timestamp cache, it forces subsequent writes to get a
@@ -236,6 +236,7 @@ func TestIntentSpanIterate(t *testing.T) {
 		{&ScanRequest{}, &ScanResponse{}, sp("a", "c"), sp("b", "c")},
 		{&ReverseScanRequest{}, &ReverseScanResponse{}, sp("d", "f"), sp("d", "e")},
 		{&DeleteRangeRequest{}, &DeleteRangeResponse{}, sp("g", "i"), sp("h", "i")},
+		{&ScanRequest{KeyLocking: EXCLUSIVE}, &ScanResponse{}, sp("j", "l"), sp("k", "l")},
 	}
 
 	// A batch request with a batch response with no ResumeSpan.
@@ -252,12 +253,10 @@ func TestIntentSpanIterate(t *testing.T) {
 		spans = append(spans, span)
 	}
 	ba.IntentSpanIterate(&br, fn)
-	// Only DeleteRangeResponse is a write request.
-	if e := 1; len(spans) != e {
-		t.Fatalf("unexpected number of spans: e = %d, found = %d", e, len(spans))
-	}
-	if e := testCases[2].span; !reflect.DeepEqual(e, spans[0]) {
-		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans[0])
+	// Only DeleteRangeRequest is a write request and only the last
+	// ScanRequest acquires locks.
+	if e := []Span{testCases[2].span, testCases[3].span}; !reflect.DeepEqual(e, spans) {
+		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans)
 	}
 
 	// A batch request with a batch response with a ResumeSpan.
@@ -272,12 +271,10 @@ func TestIntentSpanIterate(t *testing.T) {
 
 	spans = []Span{}
 	ba.IntentSpanIterate(&br, fn)
-	// Only DeleteRangeResponse is a write request.
-	if e := 1; len(spans) != e {
-		t.Fatalf("unexpected number of spans: e = %d, found = %d", e, len(spans))
-	}
-	if e := sp("g", "h"); !reflect.DeepEqual(e, spans[0]) {
-		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans[0])
+	// Only DeleteRangeRequest is a write request and only the last
+	// ScanRequest acquires locks.
+	if e := []Span{sp("g", "h"), sp("j", "k")}; !reflect.DeepEqual(e, spans) {
+		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans)
 	}
 }
 
@@ -296,6 +293,7 @@ func TestRefreshSpanIterate(t *testing.T) {
 		{&GetRequest{}, &GetResponse{}, sp("b", ""), Span{}},
 		{&ReverseScanRequest{}, &ReverseScanResponse{}, sp("d", "f"), sp("d", "e")},
 		{&DeleteRangeRequest{}, &DeleteRangeResponse{}, sp("g", "i"), sp("h", "i")},
+		{&ScanRequest{KeyLocking: EXCLUSIVE}, &ScanResponse{}, sp("j", "l"), sp("k", "l")},
 	}
 
 	// A batch request with a batch response with no ResumeSpan.
@@ -935,6 +935,7 @@ func (t *Transaction) Restart(
 	t.UpgradePriority(upgradePriority)
 	t.WriteTooOld = false
 	t.Sequence = 0
+	t.IgnoredSeqNums = nil
 }
 
 // BumpEpoch increments the transaction's epoch, allowing for an in-place
@@ -999,7 +1000,18 @@ func (t *Transaction) Update(o *Transaction) {
 
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
-	}
+		// The ignored seqnum ranges only apply to writes from the epoch
+		// they were recorded in.
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	}
+	// Within an epoch, the list of ignored seqnum ranges is left untouched.
+	// Ranges are only ever added by the transaction's coordinator, and since
+	// adjacent ranges are merged, neither list can be recognized as the more
+	// recent one: a response to a request sent before a savepoint rollback can
+	// carry a list that is longer than the coordinator's current one. Callers
+	// for which o is authoritative (e.g. the evaluation of an EndTransaction
+	// request against an existing transaction record) must copy the list
+	// explicitly.
 
 	t.Timestamp.Forward(o.Timestamp)
 	t.LastHeartbeat.Forward(o.LastHeartbeat)
@@ -1074,6 +1086,9 @@ func (t Transaction) String() string {
 	if nw := len(t.InFlightWrites); t.Status != PENDING && nw > 0 {
 		fmt.Fprintf(&buf, " ifw=%d", nw)
 	}
+	if nr := len(t.IgnoredSeqNums); nr > 0 {
+		fmt.Fprintf(&buf, " isn=%d", nr)
+	}
 	return buf.String()
 }
 
@@ -1098,9 +1113,32 @@ func (t Transaction) SafeMessage() string {
 	if nw := len(t.InFlightWrites); t.Status != PENDING && nw > 0 {
 		fmt.Fprintf(&buf, " ifw=%d", nw)
 	}
+	if nr := len(t.IgnoredSeqNums); nr > 0 {
+		fmt.Fprintf(&buf, " isn=%d", nr)
+	}
 	return buf.String()
 }
 
+// AddIgnoredSeqNumRange adds the given range to the transaction's list of
+// ignored seqnum ranges. Since none of the references held by a Transaction
+// allow interior mutations, the existing list is copied instead of being
+// mutated in place.
+//
+// The new range is expected to extend up to the transaction's current
+// sequence number, so any existing range that overlaps it or starts after it
+// is subsumed by it.
+func (t *Transaction) AddIgnoredSeqNumRange(newRange enginepb.IgnoredSeqNumRange) {
+	// Truncate the list at the last element not included in the new range.
+	list := t.IgnoredSeqNums
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].End >= newRange.Start
+	})
+	cpy := make([]enginepb.IgnoredSeqNumRange, i+1)
+	copy(cpy[:i], list[:i])
+	cpy[i] = newRange
+	t.IgnoredSeqNums = cpy
+}
+
 // ResetObservedTimestamps clears out all timestamps recorded from individual
 // nodes.
 func (t *Transaction) ResetObservedTimestamps() {
@@ -1144,6 +1182,7 @@ func (t *Transaction) AsRecord() TransactionRecord {
 	tr.LastHeartbeat = t.LastHeartbeat
 	tr.IntentSpans = t.IntentSpans
 	tr.InFlightWrites = t.InFlightWrites
+	tr.IgnoredSeqNums = t.IgnoredSeqNums
 	return tr
 }
 
@@ -1157,6 +1196,7 @@ func (tr *TransactionRecord) AsTransaction() Transaction {
 	t.LastHeartbeat = tr.LastHeartbeat
 	t.IntentSpans = tr.IntentSpans
 	t.InFlightWrites = tr.InFlightWrites
+	t.IgnoredSeqNums = tr.IgnoredSeqNums
 	return t
 }
 
@@ -1501,9 +1541,10 @@ func AsIntents(spans []Span, txn *Transaction) []Intent {
 	ret := make([]Intent, len(spans))
 	for i := range spans {
 		ret[i] = Intent{
-			Span:   spans[i],
-			Txn:    txn.TxnMeta,
-			Status: txn.Status,
+			Span:           spans[i],
+			Txn:            txn.TxnMeta,
+			Status:         txn.Status,
+			IgnoredSeqNums: txn.IgnoredSeqNums,
 		}
 	}
 	return ret
@@ -491,6 +491,7 @@ var nonZeroTxn = Transaction{
 	InFlightWrites:           []SequencedWrite{{Key: []byte("c"), Sequence: 1}},
 	DeprecatedMinTimestamp:   makeTS(1, 1),
 	OrigTimestampWasObserved: true,
+	IgnoredSeqNums:           []enginepb.IgnoredSeqNumRange{{Start: 888, End: 999}},
 }
 
 func TestTransactionUpdate(t *testing.T) {
@@ -581,6 +582,28 @@ func TestTransactionUpdateStaging(t *testing.T) {
 	}
 }
 
+func TestTransactionUpdateIgnoredSeqNums(t *testing.T) {
+	txn := nonZeroTxn
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}
+
+	// In same epoch, a list of ignored seqnum ranges captured before a
+	// rollback that merged ranges is not taken, even though it's longer.
+	txn2 := nonZeroTxn
+	txn2.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}, {Start: 5, End: 6}}
+	txn.Update(&txn2)
+	if a, e := txn.IgnoredSeqNums, []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}; !reflect.DeepEqual(a, e) {
+		t.Errorf("expected ignored seqnums %v; got %v", e, a)
+	}
+
+	// In later epoch, the list is replaced.
+	txn2.Epoch++
+	txn2.IgnoredSeqNums = nil
+	txn.Update(&txn2)
+	if a := txn.IgnoredSeqNums; a != nil {
+		t.Errorf("expected no ignored seqnums; got %v", a)
+	}
+}
+
 func TestTransactionClone(t *testing.T) {
 	txnPtr := nonZeroTxn.Clone()
 	txn := *txnPtr
@@ -592,6 +615,7 @@ func TestTransactionClone(t *testing.T) {
 	// listed below. If this test fails, please update the list below and/or
 	// Transaction.Clone().
 	expFields := []string{
+		"IgnoredSeqNums",
 		"InFlightWrites",
 		"InFlightWrites.Key",
 		"IntentSpans",
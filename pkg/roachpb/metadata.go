@@ -278,8 +278,11 @@ func (r ReplicaDescriptor) String() string {
 	} else {
 		fmt.Fprintf(&buf, "%d", r.ReplicaID)
 	}
-	if r.GetType() == ReplicaType_LEARNER {
+	switch r.GetType() {
+	case ReplicaType_LEARNER:
 		buf.WriteString("LEARNER")
+	case ReplicaType_NON_VOTER:
+		buf.WriteString("NON_VOTER")
 	}
 	return buf.String()
 }
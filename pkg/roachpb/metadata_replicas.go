@@ -36,8 +36,8 @@ func (d ReplicaDescriptors) Unwrap() []ReplicaDescriptor {
 	return d.wrapped
 }
 
-// All returns every replica in the set, including both voter replicas and
-// learner replicas.
+// All returns every replica in the set, including voter, learner and
+// non-voter replicas.
 func (d ReplicaDescriptors) All() []ReplicaDescriptor {
 	return d.wrapped
 }
@@ -46,7 +46,7 @@ func (d ReplicaDescriptors) All() []ReplicaDescriptor {
 func (d ReplicaDescriptors) Voters() []ReplicaDescriptor {
 	// Note that the wrapped replicas are sorted first by type.
 	for i := range d.wrapped {
-		if d.wrapped[i].GetType() == ReplicaType_LEARNER {
+		if d.wrapped[i].GetType() != ReplicaType_VOTER {
 			return d.wrapped[:i]
 		}
 	}
@@ -55,13 +55,27 @@ func (d ReplicaDescriptors) Voters() []ReplicaDescriptor {
 
 // Learners returns the learner replicas in the set.
 func (d ReplicaDescriptors) Learners() []ReplicaDescriptor {
+	return d.filterType(ReplicaType_LEARNER)
+}
+
+// NonVoters returns the non-voter replicas in the set.
+func (d ReplicaDescriptors) NonVoters() []ReplicaDescriptor {
+	return d.filterType(ReplicaType_NON_VOTER)
+}
+
+// filterType returns the replicas of the given type in the set.
+func (d ReplicaDescriptors) filterType(typ ReplicaType) []ReplicaDescriptor {
 	// Note that the wrapped replicas are sorted first by type.
-	for i := range d.wrapped {
-		if d.wrapped[i].GetType() == ReplicaType_LEARNER {
-			return d.wrapped[i:]
-		}
+	start := sort.Search(len(d.wrapped), func(i int) bool {
+		return d.wrapped[i].GetType() >= typ
+	})
+	end := sort.Search(len(d.wrapped), func(i int) bool {
+		return d.wrapped[i].GetType() > typ
+	})
+	if start == end {
+		return nil
 	}
-	return nil
+	return d.wrapped[start:end]
 }
 
 var _, _ = ReplicaDescriptors.All, ReplicaDescriptors.Learners
@@ -23,6 +23,7 @@ func newReplicaType(t ReplicaType) *ReplicaType {
 func TestVotersLearnersAll(t *testing.T) {
 	voter := newReplicaType(ReplicaType_VOTER)
 	learner := newReplicaType(ReplicaType_LEARNER)
+	nonVoter := newReplicaType(ReplicaType_NON_VOTER)
 	tests := [][]ReplicaDescriptor{
 		{},
 		{{Type: voter}},
@@ -32,6 +33,9 @@ func TestVotersLearnersAll(t *testing.T) {
 		{{Type: nil}, {Type: learner}, {Type: nil}},
 		{{Type: learner}, {Type: voter}, {Type: learner}},
 		{{Type: learner}, {Type: nil}, {Type: learner}},
+		{{Type: nonVoter}},
+		{{Type: nonVoter}, {Type: voter}, {Type: learner}, {Type: nonVoter}},
+		{{Type: nonVoter}, {Type: nil}, {Type: nonVoter}},
 	}
 	for i, test := range tests {
 		r := MakeReplicaDescriptors(test)
@@ -41,6 +45,10 @@ func TestVotersLearnersAll(t *testing.T) {
 		for _, learner := range r.Learners() {
 			assert.Equal(t, ReplicaType_LEARNER, learner.GetType(), "testcase %d", i)
 		}
+		for _, nonVoter := range r.NonVoters() {
+			assert.Equal(t, ReplicaType_NON_VOTER, nonVoter.GetType(), "testcase %d", i)
+		}
+		assert.Equal(t, len(test), len(r.Voters())+len(r.Learners())+len(r.NonVoters()), "testcase %d", i)
 		assert.Equal(t, len(test), len(r.All()), "testcase %d", i)
 	}
 }
@@ -58,6 +58,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/bulk"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/container"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts/ptprovider"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts/ptreconcile"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
@@ -186,11 +189,17 @@ type Server struct {
 	sessionRegistry    *sql.SessionRegistry
 	jobRegistry        *jobs.Registry
 	statsRefresher     *stats.Refresher
+	tempObjectCleaner  *sql.TemporaryObjectCleaner
 	engines            Engines
 	internalMemMetrics sql.MemoryMetrics
 	adminMemMetrics    sql.MemoryMetrics
 	// sqlMemMetrics are used to track memory usage of sql sessions.
 	sqlMemMetrics sql.MemoryMetrics
+	// protectedtsProvider stores the protected timestamp records and caches
+	// them for the GC queue; protectedtsReconciler releases the records of
+	// jobs which no longer exist or have finished.
+	protectedtsProvider   protectedts.Provider
+	protectedtsReconciler *ptreconcile.Reconciler
 }
 
 // NewServer creates a Server from a server.Config.
@@ -413,6 +422,13 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	// Similarly for execCfg.
 	var execCfg sql.ExecutorConfig
 
+	s.protectedtsProvider = ptprovider.New(ptprovider.Config{
+		Settings:             st,
+		DB:                   s.db,
+		InternalExecutor:     internalExecutor,
+		SystemConfigProvider: s.gossip,
+	})
+
 	// TODO(bdarnell): make StoreConfig configurable.
 	storeCfg := storage.StoreConfig{
 		DefaultZoneConfig:       &s.cfg.DefaultZoneConfig,
@@ -436,6 +452,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		LogRangeEvents:          s.cfg.EventLogEnabled,
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		TimeSeriesDataStore:     s.tsDB,
+		ProtectedTimestampCache: s.protectedtsProvider,
 
 		// Initialize the closed timestamp subsystem. Note that it won't
 		// be ready until it is .Start()ed, but the grpc server can be
@@ -722,9 +739,27 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	)
 	s.internalExecutor = internalExecutor
 	execCfg.InternalExecutor = internalExecutor
+	execCfg.ProtectedTimestampProvider = s.protectedtsProvider
 
 	s.execCfg = &execCfg
 
+	s.tempObjectCleaner = sql.NewTemporaryObjectCleaner(
+		s.st,
+		s.db,
+		s.internalExecutor,
+		s.status,
+		s.node.stores.IsMeta1Leaseholder,
+		s.clock,
+	)
+	s.protectedtsReconciler = ptreconcile.New(
+		s.st,
+		s.db,
+		s.protectedtsProvider,
+		s.jobRegistry,
+		s.node.stores.IsMeta1Leaseholder,
+		s.clock,
+	)
+
 	s.leaseMgr.SetInternalExecutor(execCfg.InternalExecutor)
 	s.leaseMgr.RefreshLeases(s.stopper, s.db, s.gossip)
 	s.leaseMgr.PeriodicallyRefreshSomeLeases()
@@ -1625,6 +1660,17 @@ func (s *Server) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Start the background thread for removing the temporary objects of the
+	// sessions that ended without cleaning them up.
+	s.tempObjectCleaner.Start(ctx, s.stopper)
+
+	// Start polling the protected timestamp records for the GC queue and the
+	// background thread for releasing the records of jobs which are gone.
+	if err := s.protectedtsProvider.Start(ctx, s.stopper); err != nil {
+		return err
+	}
+	s.protectedtsReconciler.Start(ctx, s.stopper)
+
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
 	// We have to do this after actually starting up the server to be able to
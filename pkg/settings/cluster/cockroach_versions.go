@@ -40,6 +40,9 @@ const (
 	VersionStickyBit
 	VersionParallelCommits
 	VersionGenerationComparable
+	VersionNonVoterReplicas
+	VersionRangeTombstones
+	VersionTemporarySchemas
 
 	// Add new versions here (step one of two).
 
@@ -483,6 +486,24 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionGenerationComparable,
 		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 5},
 	},
+	{
+		// VersionNonVoterReplicas enables long-lived non-voting replicas, which
+		// are configured through the num_voters field of zone configs.
+		Key:     VersionNonVoterReplicas,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 6},
+	},
+	{
+		// VersionRangeTombstones enables MVCC range tombstones, which are used
+		// to delete the data of dropped and truncated tables.
+		Key:     VersionRangeTombstones,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 7},
+	},
+	{
+		// VersionTemporarySchemas enables the per-session temporary schemas in
+		// which temporary tables are created.
+		Key:     VersionTemporarySchemas,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 8},
+	},
 
 	// Add new versions here (step two of two).
 
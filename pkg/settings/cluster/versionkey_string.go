@@ -17,11 +17,14 @@ func _() {
 	_ = x[VersionStickyBit-6]
 	_ = x[VersionParallelCommits-7]
 	_ = x[VersionGenerationComparable-8]
+	_ = x[VersionNonVoterReplicas-9]
+	_ = x[VersionRangeTombstones-10]
+	_ = x[VersionTemporarySchemas-11]
 }
 
-const _VersionKey_name = "Version2_1VersionUnreplicatedRaftTruncatedStateVersionSideloadedStorageNoReplicaIDVersion19_1VersionStart19_2VersionQueryTxnTimestampVersionStickyBitVersionParallelCommitsVersionGenerationComparable"
+const _VersionKey_name = "Version2_1VersionUnreplicatedRaftTruncatedStateVersionSideloadedStorageNoReplicaIDVersion19_1VersionStart19_2VersionQueryTxnTimestampVersionStickyBitVersionParallelCommitsVersionGenerationComparableVersionNonVoterReplicasVersionRangeTombstonesVersionTemporarySchemas"
 
-var _VersionKey_index = [...]uint8{0, 10, 47, 82, 93, 109, 133, 149, 171, 198}
+var _VersionKey_index = [...]uint8{0, 10, 47, 82, 93, 109, 133, 149, 171, 198, 221, 243, 266}
 
 func (i VersionKey) String() string {
 	if i < 0 || i >= VersionKey(len(_VersionKey_index)-1) {
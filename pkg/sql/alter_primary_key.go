@@ -0,0 +1,262 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// AlterPrimaryKey queues up the mutations needed to change the primary key of
+// a table.
+//
+// A primary key change is performed online, as a regular schema change: a new
+// index keyed on the new primary key columns and storing every other column
+// of the table is built using the primary index encoding, and all the
+// secondary indexes are rebuilt so that they refer to the new primary key.
+// Once all these indexes are backfilled, a PrimaryKeySwap mutation atomically
+// makes the new index the primary index of the table and replaces the old
+// secondary indexes with the rebuilt ones, moving their foreign key references
+// along. The old indexes are then dropped by a separate cleanup job.
+func (p *planner) AlterPrimaryKey(
+	ctx context.Context, tableDesc *MutableTableDescriptor, alterPKNode *tree.AlterTableAlterPrimaryKey,
+) error {
+	if tableDesc.Adding() {
+		return unimplemented.New("alter-primary-key-new-table",
+			"cannot change the primary key of a table created in the same transaction")
+	}
+	if len(tableDesc.Mutations) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %q is currently undergoing a schema change", tableDesc.Name)
+	}
+	// The foreign keys of the primary index move to the unique index that is
+	// added on the old primary key columns, so there must be one.
+	if isPrimaryIndexDefaultRowID(tableDesc) &&
+		(tableDesc.PrimaryIndex.ForeignKey.IsSet() || len(tableDesc.PrimaryIndex.ReferencedBy) > 0) {
+		return unimplemented.New("alter-primary-key-fk",
+			"cannot change the primary key of a table whose row ID column is referenced by a foreign key")
+	}
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.IsInterleaved() {
+			return unimplemented.New("alter-primary-key-interleaved",
+				"cannot change the primary key of an interleaved table")
+		}
+		if idx.Partitioning.NumColumns > 0 {
+			return unimplemented.New("alter-primary-key-partitioned",
+				"cannot change the primary key of a partitioned table")
+		}
+	}
+
+	newPrimaryIndexDesc := &sqlbase.IndexDescriptor{
+		Name:         generateUniqueIndexName(tableDesc, "new_primary_key"),
+		Unique:       true,
+		EncodingType: sqlbase.PrimaryIndexEncoding,
+		Type:         sqlbase.IndexDescriptor_FORWARD,
+	}
+	if err := newPrimaryIndexDesc.FillColumns(alterPKNode.Columns); err != nil {
+		return err
+	}
+	newPrimaryKeyColIDs := make(map[sqlbase.ColumnID]struct{}, len(alterPKNode.Columns))
+	for _, elem := range alterPKNode.Columns {
+		col, err := tableDesc.FindActiveColumnByName(string(elem.Column))
+		if err != nil {
+			return err
+		}
+		if col.Nullable {
+			return pgerror.Newf(pgcode.InvalidSchemaDefinition,
+				"cannot use nullable column %q in primary key", col.Name)
+		}
+		if _, ok := newPrimaryKeyColIDs[col.ID]; ok {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q appears twice in primary key", col.Name)
+		}
+		newPrimaryKeyColIDs[col.ID] = struct{}{}
+	}
+	for colID := range newPrimaryKeyColIDs {
+		if !columnInFamilyZero(tableDesc, colID) {
+			col, err := tableDesc.FindColumnByID(colID)
+			if err != nil {
+				return err
+			}
+			return pgerror.Newf(pgcode.InvalidSchemaDefinition,
+				"primary key column %q must be in column family 0", col.Name)
+		}
+	}
+
+	// If the new primary key is the same as the old one, there is nothing to
+	// do.
+	if sameIndexColumns(&tableDesc.PrimaryIndex, newPrimaryIndexDesc) {
+		return nil
+	}
+
+	// The new primary index stores all the columns that are not part of its
+	// key, so that it can become the primary index of the table.
+	for i := range tableDesc.Columns {
+		col := &tableDesc.Columns[i]
+		if _, ok := newPrimaryKeyColIDs[col.ID]; !ok {
+			newPrimaryIndexDesc.StoreColumnNames = append(newPrimaryIndexDesc.StoreColumnNames, col.Name)
+		}
+	}
+	if err := tableDesc.AddIndexMutation(newPrimaryIndexDesc, sqlbase.DescriptorMutation_ADD); err != nil {
+		return err
+	}
+	if err := tableDesc.AllocateIDs(); err != nil {
+		return err
+	}
+
+	// Rebuild all the secondary indexes so that they use the new primary key
+	// columns as their implicit columns.
+	swap := &sqlbase.PrimaryKeySwap{NewPrimaryIndexID: newPrimaryIndexDesc.ID}
+	for i := range tableDesc.Indexes {
+		oldIndex := &tableDesc.Indexes[i]
+		newIndex := protoutil.Clone(oldIndex).(*sqlbase.IndexDescriptor)
+		newIndex.ID = 0
+		newIndex.Name = generateUniqueIndexName(
+			tableDesc, fmt.Sprintf("%s_rewrite_for_primary_key_change", oldIndex.Name))
+		newIndex.ExtraColumnIDs = nil
+		// The foreign key references of the old index move to the new one when
+		// the primary key is swapped.
+		newIndex.ForeignKey = sqlbase.ForeignKeyReference{}
+		newIndex.ReferencedBy = nil
+		// Columns that become part of the primary key are implicitly part of every
+		// index, so they can no longer be stored.
+		newIndex.StoreColumnIDs = nil
+		newIndex.StoreColumnNames = nil
+		for j, colID := range oldIndex.StoreColumnIDs {
+			if _, ok := newPrimaryKeyColIDs[colID]; !ok {
+				newIndex.StoreColumnNames = append(newIndex.StoreColumnNames, oldIndex.StoreColumnNames[j])
+			}
+		}
+		if err := addIndexMutationWithSpecificPrimaryKey(tableDesc, newIndex, newPrimaryIndexDesc); err != nil {
+			return err
+		}
+		swap.OldIndexes = append(swap.OldIndexes, oldIndex.ID)
+		swap.NewIndexes = append(swap.NewIndexes, newIndex.ID)
+	}
+
+	// The old primary key columns must remain unique after the change, unless
+	// they were the hidden row ID column.
+	if !isPrimaryIndexDefaultRowID(tableDesc) {
+		name := generateUniqueIndexName(
+			tableDesc, fmt.Sprintf("%s_%s_key", tableDesc.Name, sqlbase.PrimaryKeyIndexName))
+		oldPrimaryKeyIndex := &sqlbase.IndexDescriptor{
+			Name:             name,
+			Unique:           true,
+			Type:             sqlbase.IndexDescriptor_FORWARD,
+			ColumnNames:      append([]string(nil), tableDesc.PrimaryIndex.ColumnNames...),
+			ColumnDirections: append([]sqlbase.IndexDescriptor_Direction(nil), tableDesc.PrimaryIndex.ColumnDirections...),
+		}
+		if err := addIndexMutationWithSpecificPrimaryKey(tableDesc, oldPrimaryKeyIndex, newPrimaryIndexDesc); err != nil {
+			return err
+		}
+	}
+
+	tableDesc.AddPrimaryKeySwapMutation(swap)
+	return nil
+}
+
+// addIndexMutationWithSpecificPrimaryKey adds an index mutation to tableDesc
+// for an index whose implicit columns are the key columns of primaryKey
+// rather than those of the current primary index of the table.
+func addIndexMutationWithSpecificPrimaryKey(
+	tableDesc *MutableTableDescriptor,
+	toAdd *sqlbase.IndexDescriptor,
+	primaryKey *sqlbase.IndexDescriptor,
+) error {
+	if err := tableDesc.AddIndexMutation(toAdd, sqlbase.DescriptorMutation_ADD); err != nil {
+		return err
+	}
+	if err := tableDesc.AllocateIDs(); err != nil {
+		return err
+	}
+	// AllocateIDs computed the implicit columns of the index using the current
+	// primary index; recompute them using the new primary key.
+	toAdd.ExtraColumnIDs = nil
+	for _, colID := range primaryKey.ColumnIDs {
+		if !toAdd.ContainsColumnID(colID) {
+			toAdd.ExtraColumnIDs = append(toAdd.ExtraColumnIDs, colID)
+		}
+	}
+	toAdd.CompositeColumnIDs = nil
+	for _, colIDs := range [][]sqlbase.ColumnID{toAdd.ColumnIDs, toAdd.ExtraColumnIDs} {
+		for _, colID := range colIDs {
+			col, err := tableDesc.FindColumnByID(colID)
+			if err != nil {
+				return err
+			}
+			if sqlbase.HasCompositeKeyEncoding(col.Type.Family()) {
+				toAdd.CompositeColumnIDs = append(toAdd.CompositeColumnIDs, colID)
+			}
+		}
+	}
+	return nil
+}
+
+// generateUniqueIndexName returns name if no index of tableDesc uses it, and
+// otherwise name suffixed with the smallest number that makes it unique.
+func generateUniqueIndexName(tableDesc *MutableTableDescriptor, name string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, _, err := tableDesc.FindIndexByName(candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// columnInFamilyZero returns whether the given column belongs to the first
+// column family of the table, which is required of primary key columns.
+func columnInFamilyZero(tableDesc *MutableTableDescriptor, colID sqlbase.ColumnID) bool {
+	if len(tableDesc.Families) == 0 {
+		return true
+	}
+	for _, id := range tableDesc.Families[0].ColumnIDs {
+		if id == colID {
+			return true
+		}
+	}
+	return false
+}
+
+// sameIndexColumns returns whether the two indexes have the same key columns,
+// in the same order and with the same directions.
+func sameIndexColumns(a, b *sqlbase.IndexDescriptor) bool {
+	if len(a.ColumnNames) != len(b.ColumnNames) {
+		return false
+	}
+	for i := range a.ColumnNames {
+		if a.ColumnNames[i] != b.ColumnNames[i] || a.ColumnDirections[i] != b.ColumnDirections[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isPrimaryIndexDefaultRowID returns whether the primary index of the table
+// is keyed on the hidden row ID column that is created for tables without an
+// explicit primary key.
+func isPrimaryIndexDefaultRowID(tableDesc *MutableTableDescriptor) bool {
+	if len(tableDesc.PrimaryIndex.ColumnIDs) != 1 {
+		return false
+	}
+	col, err := tableDesc.FindColumnByID(tableDesc.PrimaryIndex.ColumnIDs[0])
+	if err != nil {
+		return false
+	}
+	return col.Hidden
+}
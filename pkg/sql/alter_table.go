@@ -194,6 +194,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 					return pgerror.Newf(pgcode.Syntax,
 						"multiple primary keys for table %q are not allowed", n.tableDesc.Name)
 				}
+				if d.Deferrable != tree.NotDeferrable {
+					// The backfill of the index can't verify the uniqueness of the
+					// existing rows with the non-unique encoding.
+					return unimplemented.NewWithIssue(31632,
+						"cannot add a DEFERRABLE UNIQUE constraint to an existing table")
+				}
 				idx := sqlbase.IndexDescriptor{
 					Name:             string(d.Name),
 					Unique:           true,
@@ -349,6 +355,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return pgerror.Newf(pgcode.InvalidColumnReference,
 					"column %q is referenced by the primary key", col.Name)
 			}
+			if name, ok, err := findTriggerReferencingColumn(n.tableDesc, col); err != nil {
+				return err
+			} else if ok {
+				return pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"column %q is referenced by trigger %q", col.Name, name)
+			}
 			for _, idx := range n.tableDesc.AllNonDropIndexes() {
 				// We automatically drop indexes on that column that only
 				// index that column (and no other columns). If CASCADE is
@@ -567,6 +579,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = true
 
+		case *tree.AlterTableAlterPrimaryKey:
+			if err := params.p.AlterPrimaryKey(params.ctx, n.tableDesc, t); err != nil {
+				return err
+			}
+
 		case *tree.AlterTablePartitionBy:
 			partitioning, err := CreatePartitioning(
 				params.ctx, params.p.ExecCfg().Settings,
@@ -684,6 +701,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	// Columns added with a user-defined type must be updated when the type is
+	// altered.
+	if err := params.p.addTypeBackReferences(params.ctx, n.tableDesc); err != nil {
+		return err
+	}
+
 	// Record this table alteration in the event log. This is an auditable log
 	// event and is recorded in the same transaction as the table descriptor
 	// update.
@@ -0,0 +1,197 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/enum"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
+)
+
+type alterTypeNode struct {
+	n    *tree.AlterType
+	desc *sqlbase.TypeDescriptor
+}
+
+// AlterType applies a schema change on a user-defined type.
+// Privileges: CREATE on type.
+func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Type)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := getTypeDescByName(ctx, p.txn, dbDesc.ID, n.Type.Table())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &alterTypeNode{n: n, desc: desc}, nil
+}
+
+func (n *alterTypeNode) startExec(params runParams) error {
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterTypeAddValue:
+		added, err := addEnumValue(n.desc, t)
+		if err != nil || !added {
+			return err
+		}
+	default:
+		return errors.AssertionFailedf("unknown alter type cmd: %s", t)
+	}
+
+	updated, err := params.p.updateTypeReferences(params.ctx, n.desc)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// No table can hold values of the new member, so there are no nodes
+		// that could fail to decode them and it can be written right away.
+		n.desc.MakeEnumMembersWritable()
+	}
+	if err := params.p.writeTypeDesc(params.ctx, n.desc); err != nil {
+		return err
+	}
+
+	// Record this type alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the type descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterType,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.n.Type.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (n *alterTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterTypeNode) Close(context.Context)        {}
+
+// addEnumValue adds the member requested by an ALTER TYPE ... ADD VALUE
+// command to the given enum type descriptor. The physical representation of
+// the new member is chosen to sort between the members around it, so that the
+// values already written remain valid. The member is added in the read-only
+// state, since nodes using older versions of the tables that reference the type
+// cannot decode values of it; it is made writable by the schema changer once
+// these versions are gone. It returns false if the member already exists and
+// IF NOT EXISTS was specified.
+func addEnumValue(desc *sqlbase.TypeDescriptor, node *tree.AlterTypeAddValue) (bool, error) {
+	for i := range desc.EnumMembers {
+		if desc.EnumMembers[i].LogicalRepresentation == node.NewVal {
+			if node.IfNotExists {
+				return false, nil
+			}
+			return false, pgerror.Newf(pgcode.DuplicateObject,
+				"enum label %q already exists", node.NewVal)
+		}
+	}
+
+	// By default, the new member is added at the end of the enum.
+	pos := len(desc.EnumMembers)
+	if node.Placement != nil {
+		pos = -1
+		for i := range desc.EnumMembers {
+			if desc.EnumMembers[i].LogicalRepresentation == node.Placement.ExistingVal {
+				pos = i
+				break
+			}
+		}
+		if pos == -1 {
+			return false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%q is not an existing enum label", node.Placement.ExistingVal)
+		}
+		if !node.Placement.Before {
+			pos++
+		}
+	}
+
+	var prev, next []byte
+	if pos > 0 {
+		prev = desc.EnumMembers[pos-1].PhysicalRepresentation
+	}
+	if pos < len(desc.EnumMembers) {
+		next = desc.EnumMembers[pos].PhysicalRepresentation
+	}
+	member := sqlbase.TypeDescriptor_EnumMember{
+		PhysicalRepresentation: enum.GenByteStringBetween(prev, next),
+		LogicalRepresentation:  node.NewVal,
+		Capability:             sqlbase.TypeDescriptor_EnumMember_READ_ONLY,
+	}
+	desc.EnumMembers = append(desc.EnumMembers, sqlbase.TypeDescriptor_EnumMember{})
+	copy(desc.EnumMembers[pos+1:], desc.EnumMembers[pos:])
+	desc.EnumMembers[pos] = member
+	return true, nil
+}
+
+// updateTypeReferences rewrites the types of the columns that use the given
+// user-defined type in the tables that reference it. Columns carry a copy of
+// the metadata of their type, which must be kept in sync with the type
+// descriptor for the new members to be usable in the tables. It returns whether
+// any table was updated, in which case a schema change is queued for each of
+// them that eventually makes the read-only members of the type writable.
+func (p *planner) updateTypeReferences(
+	ctx context.Context, desc *sqlbase.TypeDescriptor,
+) (bool, error) {
+	typ := desc.MakeTypesT()
+	updateCol := func(col *sqlbase.ColumnDescriptor) bool {
+		if col.Type.StableTypeID() != uint32(desc.ID) {
+			return false
+		}
+		col.Type = *typ
+		return true
+	}
+	anyUpdated := false
+	for _, id := range desc.ReferencingDescriptorIDs {
+		tableDesc, err := p.Tables().getMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+				continue
+			}
+			return false, err
+		}
+		if tableDesc.Dropped() {
+			continue
+		}
+		updated := false
+		for i := range tableDesc.Columns {
+			updated = updateCol(&tableDesc.Columns[i]) || updated
+		}
+		for _, m := range tableDesc.Mutations {
+			if col := m.GetColumn(); col != nil {
+				updated = updateCol(col) || updated
+			}
+		}
+		if !updated {
+			continue
+		}
+		if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
+			return false, err
+		}
+		anyUpdated = true
+	}
+	return anyUpdated, nil
+}
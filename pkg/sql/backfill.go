@@ -174,6 +174,10 @@ func (sc *SchemaChanger) runBackfill(
 					constraintsToAddBeforeValidation = append(constraintsToAddBeforeValidation, *t.Constraint)
 					constraintsToValidate = append(constraintsToValidate, *t.Constraint)
 				}
+			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
+				// The primary key swap is performed when the mutation completes;
+				// the indexes it references are backfilled through their own
+				// mutations.
 			default:
 				return errors.AssertionFailedf(
 					"unsupported mutation: %+v", m)
@@ -194,6 +198,8 @@ func (sc *SchemaChanger) runBackfill(
 						"trying to drop constraint through schema changer outside of a rollback: %+v", t)
 				}
 				// no-op
+			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
+				// Only possible during a rollback; no-op.
 			default:
 				return errors.AssertionFailedf(
 					"unsupported mutation: %+v", m)
@@ -309,7 +315,7 @@ func (sc *SchemaChanger) AddConstraints(
 				} else {
 					idx.ForeignKey = constraint.ForeignKey
 					// Add backreference on the referenced table (which could be the same table)
-					backref := sqlbase.ForeignKeyReference{Table: sc.tableID, Index: constraint.ForeignKeyIndex}
+					backref := makeFKBackReference(sc.tableID, constraint.ForeignKeyIndex, &constraint.ForeignKey)
 					backrefTable, ok := descs[constraint.ForeignKey.Table]
 					if !ok {
 						return errors.AssertionFailedf("required table with ID %d not provided to update closure", sc.tableID)
@@ -1244,6 +1250,10 @@ func runSchemaChangesInTxn(
 				}
 				constraintsToValidate = append(constraintsToValidate, *t.Constraint)
 
+			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
+				return errors.AssertionFailedf(
+					"primary key swap mutation cannot be run within the same transaction: %+v", m)
+
 			default:
 				return errors.AssertionFailedf(
 					"unsupported mutation: %+v", m)
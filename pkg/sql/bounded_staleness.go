@@ -0,0 +1,68 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// setUpBoundedStalenessRead prepares the planner's txn to perform the bounded
+// staleness read of the current plan. Instead of running at a fixed timestamp,
+// the txn lets the replica serving the read choose the newest timestamp at or
+// above the minimum timestamp bound that it can serve locally.
+//
+// The read must not observe data older than the schema that it was planned
+// with, so the minimum timestamp bound is forwarded to the modification time
+// of the leased table descriptors.
+func (p *planner) setUpBoundedStalenessRead(ctx context.Context) error {
+	if err := checkBoundedStalenessPlan(&p.curPlan); err != nil {
+		return err
+	}
+	minTimestampBound := *p.semaCtx.AsOfTimestamp
+	minTimestampBound.Forward(p.Tables().maxLeasedModificationTime())
+	log.VEventf(ctx, 2, "bounded staleness read with min timestamp bound %s", minTimestampBound)
+	p.txn.SetMinTimestampBound(minTimestampBound)
+	return nil
+}
+
+// checkBoundedStalenessPlan verifies that a plan can be executed as a bounded
+// staleness read. The timestamp of such a read is negotiated with the single
+// range that it reads from, so only plans which read at most one row from a
+// single index are supported.
+func checkBoundedStalenessPlan(plan *planTop) error {
+	if len(plan.subqueryPlans) == 0 && len(plan.postqueryPlans) == 0 {
+		n := plan.plan
+		for n != nil {
+			switch t := n.(type) {
+			case *renderNode:
+				n = t.source.plan
+			case *filterNode:
+				n = t.source.plan
+			case *limitNode:
+				n = t.plan
+			case *scanNode:
+				if len(t.spans) == 1 && t.maxResults == 1 {
+					return nil
+				}
+				n = nil
+			default:
+				n = nil
+			}
+		}
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"AS OF SYSTEM TIME: bounded staleness reads are only supported for queries "+
+			"which read a single row from a single index")
+}
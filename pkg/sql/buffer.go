@@ -13,9 +13,8 @@ package sql
 import (
 	"context"
 
-	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // bufferNode consumes its input one row at a time, stores it in the buffer,
@@ -24,18 +23,16 @@ import (
 type bufferNode struct {
 	plan planNode
 
-	// TODO(yuzefovich): the buffer should probably be backed by disk. If so, the
-	// comments about TempStorage suggest that it should be used by DistSQL
-	// processors, but this node is local.
-	bufferedRows       *rowcontainer.RowContainer
-	passThruNextRowIdx int
+	// bufferedRows is backed by disk once the rows no longer fit in memory.
+	bufferedRows rowContainerHelper
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
 }
 
 func (n *bufferNode) startExec(params runParams) error {
-	n.bufferedRows = rowcontainer.NewRowContainer(
-		params.EvalContext().Mon.MakeBoundAccount(),
-		sqlbase.ColTypeInfoFromResCols(getPlanColumns(n.plan, false /* mut */)),
-		0, /* rowCapacity */
+	n.bufferedRows.init(
+		planTypes(n.plan), params.extendedEvalCtx, "buffer", /* opName */
 	)
 	return nil
 }
@@ -51,20 +48,19 @@ func (n *bufferNode) Next(params runParams) (bool, error) {
 	if !ok {
 		return false, nil
 	}
-	if _, err = n.bufferedRows.AddRow(params.ctx, n.plan.Values()); err != nil {
+	if err := n.bufferedRows.addRow(params.ctx, n.plan.Values()); err != nil {
 		return false, err
 	}
-	n.passThruNextRowIdx++
 	return true, nil
 }
 
 func (n *bufferNode) Values() tree.Datums {
-	return n.bufferedRows.At(n.passThruNextRowIdx - 1)
+	return n.plan.Values()
 }
 
 func (n *bufferNode) Close(ctx context.Context) {
 	n.plan.Close(ctx)
-	n.bufferedRows.Close(ctx)
+	n.bufferedRows.close(ctx)
 }
 
 // scanBufferNode behaves like an iterator into the bufferNode it is
@@ -73,21 +69,48 @@ func (n *bufferNode) Close(ctx context.Context) {
 type scanBufferNode struct {
 	buffer *bufferNode
 
-	nextRowIdx int
+	iterator   *rowContainerIterator
+	currentRow tree.Datums
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
 }
 
 func (n *scanBufferNode) startExec(runParams) error {
 	return nil
 }
 
-func (n *scanBufferNode) Next(runParams) (bool, error) {
-	n.nextRowIdx++
-	return n.nextRowIdx <= n.buffer.bufferedRows.Len(), nil
+func (n *scanBufferNode) Next(params runParams) (bool, error) {
+	// The iterator is created lazily so that all the rows of the buffer are
+	// visible to it.
+	if n.iterator == nil {
+		n.iterator = newRowContainerIterator(params.ctx, n.buffer.bufferedRows)
+	}
+	var err error
+	n.currentRow, err = n.iterator.next()
+	if n.currentRow == nil || err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (n *scanBufferNode) Values() tree.Datums {
-	return n.buffer.bufferedRows.At(n.nextRowIdx - 1)
+	return n.currentRow
 }
 
 func (n *scanBufferNode) Close(context.Context) {
+	if n.iterator != nil {
+		n.iterator.close()
+		n.iterator = nil
+	}
+}
+
+// planTypes returns the types of the columns of the given plan.
+func planTypes(plan planNode) []types.T {
+	columns := planColumns(plan)
+	typs := make([]types.T, len(columns))
+	for i := range columns {
+		typs[i] = *columns[i].Typ
+	}
+	return typs
 }
@@ -0,0 +1,132 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// rowContainerHelper is a wrapper around a disk-backed row container that
+// can be used by planNodes that need to store rows. The rows are kept in
+// memory until the memory limit (sql.distsql.temp_storage.workmem) is
+// reached, after which they are spilled to temporary storage.
+type rowContainerHelper struct {
+	rows        *rowcontainer.DiskBackedRowContainer
+	typs        []types.T
+	scratch     sqlbase.EncDatumRow
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+}
+
+// init initializes the helper for rows of the given types. opName is used to
+// name the memory and disk monitors.
+func (c *rowContainerHelper) init(
+	typs []types.T, evalContext *extendedEvalContext, opName string,
+) {
+	distSQLCfg := &evalContext.ExecCfg.DistSQLSrv.ServerConfig
+	ctx := evalContext.Context
+	limit := distsqlrun.SettingWorkMemBytes.Get(&evalContext.Settings.SV)
+	limitedMon := mon.MakeMonitorInheritWithLimit(
+		fmt.Sprintf("%s-limited", opName), limit, evalContext.Mon,
+	)
+	limitedMon.Start(ctx, evalContext.Mon, mon.BoundAccount{})
+	c.memMonitor = &limitedMon
+	c.diskMonitor = distsqlrun.NewMonitor(
+		ctx, distSQLCfg.DiskMonitor, fmt.Sprintf("%s-disk", opName),
+	)
+	c.rows = &rowcontainer.DiskBackedRowContainer{}
+	c.rows.Init(
+		nil /* ordering */, typs, &evalContext.EvalContext,
+		distSQLCfg.TempStorage, c.memMonitor, c.diskMonitor, 0, /* rowCapacity */
+	)
+	c.typs = typs
+	c.scratch = make(sqlbase.EncDatumRow, len(typs))
+}
+
+// addRow adds the given row to the container. The row is copied.
+func (c *rowContainerHelper) addRow(ctx context.Context, row tree.Datums) error {
+	for i := range row {
+		c.scratch[i] = sqlbase.DatumToEncDatum(&c.typs[i], row[i])
+	}
+	return c.rows.AddRow(ctx, c.scratch)
+}
+
+// len returns the number of rows in the container.
+func (c *rowContainerHelper) len() int {
+	return c.rows.Len()
+}
+
+// clear removes all the rows from the container.
+func (c *rowContainerHelper) clear(ctx context.Context) error {
+	return c.rows.UnsafeReset(ctx)
+}
+
+// close must be called once the helper is no longer needed to clean up any
+// resources.
+func (c *rowContainerHelper) close(ctx context.Context) {
+	if c.rows != nil {
+		c.rows.Close(ctx)
+		c.memMonitor.Stop(ctx)
+		c.diskMonitor.Stop(ctx)
+		c.rows = nil
+	}
+}
+
+// rowContainerIterator is a wrapper around a row iterator of the container
+// of a rowContainerHelper that returns the rows as tree.Datums.
+type rowContainerIterator struct {
+	iter rowcontainer.RowIterator
+
+	typs   []types.T
+	datums tree.Datums
+	da     sqlbase.DatumAlloc
+}
+
+// newRowContainerIterator returns a new iterator over the rows in the given
+// container. The iterator must be closed once it is no longer needed.
+func newRowContainerIterator(ctx context.Context, c rowContainerHelper) *rowContainerIterator {
+	i := &rowContainerIterator{
+		iter:   c.rows.NewIterator(ctx),
+		typs:   c.typs,
+		datums: make(tree.Datums, len(c.typs)),
+	}
+	i.iter.Rewind()
+	return i
+}
+
+// next returns the next row of the iterator, or nil if there are no more
+// rows. The returned row is only valid until the next call to next().
+func (i *rowContainerIterator) next() (tree.Datums, error) {
+	if valid, err := i.iter.Valid(); err != nil || !valid {
+		return nil, err
+	}
+	row, err := i.iter.Row()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlbase.EncDatumRowToDatums(i.typs, i.datums, row, &i.da); err != nil {
+		return nil, err
+	}
+	i.iter.Next()
+	return i.datums, nil
+}
+
+func (i *rowContainerIterator) close() {
+	i.iter.Close()
+}
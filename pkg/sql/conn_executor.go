@@ -382,6 +382,10 @@ func (s *Server) SetupConn(
 ) (ConnectionHandler, error) {
 	sd, sdMut := s.newSessionDataAndMutator(args)
 	ex, err := s.newConnExecutor(ctx, sd, sdMut, stmtBuf, clientComm, memMetrics, &s.Metrics)
+	if err != nil {
+		return ConnectionHandler{ex}, err
+	}
+	ex.cancelKeySecret, err = generateCancelSecret()
 	return ConnectionHandler{ex}, err
 }
 
@@ -409,6 +413,15 @@ func (h ConnectionHandler) GetUnqualifiedIntSize() *types.T {
 	}
 }
 
+// GetCancelKey returns the key that the client can use to cancel the queries
+// running on this connection through a pgwire CancelRequest.
+func (h ConnectionHandler) GetCancelKey() CancelKey {
+	return CancelKey{
+		NodeID: h.ex.server.cfg.NodeID.Get(),
+		Secret: h.ex.cancelKeySecret,
+	}
+}
+
 // GetStatusParam retrieves the configured value of the session
 // variable identified by varName. This is used for the initial
 // message sent to a client during a session set-up.
@@ -733,6 +746,14 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	ex.sessionEventf(ctx, "finishing connExecutor")
 
 	if closeType == normalClose {
+		// The KV txn is not rolled back when entering the Aborted state (so that
+		// the client can recover through ROLLBACK TO SAVEPOINT), so we roll it
+		// back here.
+		if _, ok := ex.machine.CurState().(stateAborted); ok {
+			if err := ex.state.mu.txn.Rollback(ctx); err != nil {
+				log.Warningf(ctx, "txn rollback failed: %s", err)
+			}
+		}
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
 		// This event is guaranteed to be accepted in every state.
 		ev := eventNonRetriableErr{IsCommit: fsm.FromBool(true)}
@@ -749,6 +770,16 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		log.Warningf(ctx, "error while cleaning up connExecutor: %s", err)
 	}
 
+	// Remove the temporary objects of the session, if it created any.
+	if closeType != panicClose && ex.sessionData.SearchPath.GetTemporarySchemaName() != "" {
+		if err := cleanupSessionTempObjects(
+			ctx, ex.server.cfg.DB, ex.server.cfg.InternalExecutor, ex.sessionID,
+		); err != nil {
+			log.Warningf(ctx, "error deleting temporary objects at session close, "+
+				"the temp tables deletion job will retry periodically: %s", err)
+		}
+	}
+
 	if closeType != panicClose {
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
@@ -872,6 +903,25 @@ type connExecutor struct {
 		// txnRewindPos is advanced. Prepared statements are shared between the two
 		// collections, but these collections are periodically reconciled.
 		prepStmtsNamespaceAtTxnRewindPos prepStmtNamespace
+
+		// savepoints maintains the stack of savepoints currently active in the
+		// transaction.
+		savepoints savepointStack
+
+		// savepointsAtTxnRewindPos is a snapshot of the savepoints stack before
+		// processing the command at position txnRewindPos. When rewinding, we're
+		// going to restore this snapshot.
+		savepointsAtTxnRewindPos savepointStack
+
+		// numDDL keeps track of how many DDL statements have been executed so far
+		// in the current transaction attempt.
+		numDDL int
+
+		// tempSchemaNameAtTxnStart is the name of the session's temporary schema
+		// when the transaction started. A temporary schema created by the
+		// transaction is recorded in the session data right away, so the name is
+		// restored if the transaction restarts or rolls back.
+		tempSchemaNameAtTxnStart string
 	}
 
 	// sessionData contains the user-configurable connection variables.
@@ -900,6 +950,10 @@ type connExecutor struct {
 	// If nil, canceling this session will be a no-op.
 	onCancelSession context.CancelFunc
 
+	// cancelKeySecret is the secret of the session's CancelKey. It is zero for
+	// sessions that are not served over pgwire (e.g. internal executors).
+	cancelKeySecret uint32
+
 	// planner is the "default planner" on a session, to save planner allocations
 	// during serial execution. Since planners are not threadsafe, this is only
 	// safe to use when a statement is not being parallelized. It must be reset
@@ -1018,6 +1072,8 @@ func (ex *connExecutor) resetExtraTxnState(
 	ctx context.Context, dbCacheHolder *databaseCacheHolder,
 ) error {
 	ex.extraTxnState.schemaChangers.reset()
+	ex.extraTxnState.numDDL = 0
+	ex.state.deferredChecks.Reset(ctx)
 
 	ex.extraTxnState.tables.releaseTables(ctx)
 
@@ -1383,6 +1439,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case rewind:
 		ex.rewindPrepStmtNamespace(ctx)
 		advInfo.rewCap.rewindAndUnlock(ctx)
+		if err := ex.rewindSavepoints(ctx); err != nil {
+			return err
+		}
 	case stayInPlace:
 		// Nothing to do. The same statement will be executed again.
 	default:
@@ -1508,6 +1567,7 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 	ex.extraTxnState.txnRewindPos = pos
 	ex.stmtBuf.ltrim(ctx, pos)
 	ex.commitPrepStmtNamespace(ctx)
+	ex.extraTxnState.savepointsAtTxnRewindPos = ex.extraTxnState.savepoints.clone()
 }
 
 // stmtDoesntNeedRetry returns true if the given statement does not need to be
@@ -1835,6 +1895,11 @@ func (ex *connExecutor) resetEvalCtx(
 	evalCtx.Mon = ex.state.mon
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
+	evalCtx.SessionID = ex.sessionID
+	evalCtx.DeferredChecks = nil
+	if !ex.implicitTxn() {
+		evalCtx.DeferredChecks = &ex.state.deferredChecks
+	}
 }
 
 // getTransactionState retrieves a text representation of the given state.
@@ -1885,6 +1950,8 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 	p.semaCtx.AsOfTimestamp = nil
 	p.semaCtx.Annotations = tree.MakeAnnotations(numAnnotations)
 
@@ -1893,6 +1960,7 @@ func (ex *connExecutor) resetPlanner(
 	p.autoCommit = false
 	p.isPreparing = false
 	p.avoidCachedDescriptors = false
+	p.boundedStaleness = false
 }
 
 // txnStateTransitionsApplyWrapper is a wrapper on top of Machine built with the
@@ -1928,6 +1996,8 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 	case noEvent:
 	case txnStart:
 		ex.extraTxnState.autoRetryCounter = 0
+		ex.extraTxnState.savepoints = nil
+		ex.extraTxnState.tempSchemaNameAtTxnStart = ex.sessionData.SearchPath.GetTemporarySchemaName()
 	case txnCommit:
 		if res.Err() != nil {
 			err := errorutil.UnexpectedWithIssueErrorf(
@@ -1990,11 +2060,25 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 		// Wait for the cache to reflect the dropped databases if any.
 		ex.extraTxnState.tables.waitForCacheToDropDatabases(ex.Ctx())
 
+		// The temporary schema created by the transaction, if any, is committed.
+		ex.extraTxnState.tempSchemaNameAtTxnStart = ex.sessionData.SearchPath.GetTemporarySchemaName()
+
 		fallthrough
 	case txnRestart, txnAborted:
+		if ex.dataMutator != nil {
+			ex.dataMutator.SetTemporarySchemaName(ex.extraTxnState.tempSchemaNameAtTxnStart)
+		}
 		if err := ex.resetExtraTxnState(ex.Ctx(), ex.server.dbCache); err != nil {
 			return advanceInfo{}, err
 		}
+	case txnErrored:
+		// The connection might stay in the Aborted state for an arbitrarily long
+		// time, so we don't hold on to the table leases, which would otherwise
+		// block schema changes. The rest of the txn state (uncommitted
+		// descriptors, queued schema changes, deferred FK checks) is kept: it's
+		// needed if the client recovers through ROLLBACK TO SAVEPOINT and commits.
+		// It is reset with txnAborted when the transaction is rolled back.
+		ex.extraTxnState.tables.releaseLeases(ex.Ctx())
 	default:
 		return advanceInfo{}, errors.AssertionFailedf(
 			"unexpected event: %v", errors.Safe(advInfo.txnEvent))
@@ -2048,6 +2132,23 @@ func (ex *connExecutor) cancelQuery(queryID ClusterWideID) bool {
 	return false
 }
 
+// cancelCurrentQueries is part of the registrySession interface.
+func (ex *connExecutor) cancelCurrentQueries() bool {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	canceled := false
+	for _, queryMeta := range ex.mu.ActiveQueries {
+		queryMeta.cancel()
+		canceled = true
+	}
+	return canceled
+}
+
+// cancelSecret is part of the registrySession interface.
+func (ex *connExecutor) cancelSecret() uint32 {
+	return ex.cancelKeySecret
+}
+
 // cancelSession is part of the registrySession interface.
 func (ex *connExecutor) cancelSession() {
 	if ex.onCancelSession == nil {
@@ -2263,15 +2364,19 @@ func (sc *StatementCounters) incrementCount(ex *connExecutor, stmt tree.Statemen
 	case *tree.RollbackTransaction:
 		sc.TxnRollbackCount.Inc()
 	case *tree.Savepoint:
-		if err := ex.validateSavepointName(t.Name); err == nil {
+		if ex.isRestartSavepoint(t.Name) {
 			sc.RestartSavepointCount.Inc()
 		} else {
 			sc.SavepointCount.Inc()
 		}
 	case *tree.ReleaseSavepoint:
-		sc.ReleaseRestartSavepointCount.Inc()
+		if ex.isRestartSavepoint(t.Savepoint) {
+			sc.ReleaseRestartSavepointCount.Inc()
+		}
 	case *tree.RollbackToSavepoint:
-		sc.RollbackToRestartSavepointCount.Inc()
+		if ex.isRestartSavepoint(t.Savepoint) {
+			sc.RollbackToRestartSavepointCount.Inc()
+		}
 	default:
 		if tree.CanModifySchema(stmt) {
 			sc.DdlCount.Inc()
@@ -37,7 +37,10 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-// RestartSavepointName is the only savepoint ident that we accept.
+// RestartSavepointName is the name of the savepoint that clients use to
+// perform client-directed transaction retries. It is handled differently from
+// other savepoints: rolling back to it restarts the transaction and releasing
+// it commits the transaction.
 const RestartSavepointName string = "cockroach_restart"
 
 var errSavepointNotUsed = pgerror.Newf(
@@ -232,16 +235,8 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.ReleaseSavepoint:
-		if err := ex.validateSavepointName(s.Savepoint); err != nil {
-			return makeErrEvent(err)
-		}
-		if !ex.machine.CurState().(stateOpen).RetryIntent.Get() {
-			return makeErrEvent(errSavepointNotUsed)
-		}
-
 		// ReleaseSavepoint is executed fully here; there's no plan for it.
-		ev, payload := ex.commitSQLTransaction(ctx, stmt.AST)
-		res.ResetStmtType((*tree.CommitTransaction)(nil))
+		ev, payload := ex.execReleaseSavepointInOpenState(ctx, s, res)
 		return ev, payload, nil
 
 	case *tree.RollbackTransaction:
@@ -250,44 +245,14 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.Savepoint:
-		// Ensure that the user isn't trying to run BEGIN; SAVEPOINT; SAVEPOINT;
-		if ex.state.activeSavepointName != "" {
-			err := unimplemented.NewWithIssueDetail(10735, "nested", "SAVEPOINT may not be nested")
-			return makeErrEvent(err)
-		}
-		if err := ex.validateSavepointName(s.Name); err != nil {
-			return makeErrEvent(err)
-		}
-		// We want to disallow SAVEPOINTs to be issued after a KV transaction has
-		// started running. The client txn's statement count indicates how many
-		// statements have been executed as part of this transaction. It is
-		// desirable to allow metadata queries against vtables to proceed
-		// before starting a SAVEPOINT for better ORM compatibility.
-		// See also:
-		// https://github.com/cockroachdb/cockroach/issues/15012
-		meta := ex.state.mu.txn.GetTxnCoordMeta(ctx)
-		if meta.CommandCount > 0 {
-			err := pgerror.Newf(pgcode.Syntax,
-				"SAVEPOINT %s needs to be the first statement in a "+
-					"transaction", RestartSavepointName)
-			return makeErrEvent(err)
-		}
-		ex.state.activeSavepointName = s.Name
 		// Note that Savepoint doesn't have a corresponding plan node.
 		// This here is all the execution there is.
-		return eventRetryIntentSet{}, nil /* payload */, nil
+		ev, payload := ex.execSavepointInOpenState(ctx, s)
+		return ev, payload, nil
 
 	case *tree.RollbackToSavepoint:
-		if err := ex.validateSavepointName(s.Savepoint); err != nil {
-			return makeErrEvent(err)
-		}
-		if !os.RetryIntent.Get() {
-			return makeErrEvent(errSavepointNotUsed)
-		}
-		ex.state.activeSavepointName = ""
-
-		res.ResetStmtType((*tree.Savepoint)(nil))
-		return eventTxnRestart{}, nil /* payload */, nil
+		ev, payload := ex.execRollbackToSavepointInOpenState(ctx, s, res)
+		return ev, payload, nil
 
 	case *tree.Prepare:
 		// This is handling the SQL statement "PREPARE". See execPrepare for
@@ -308,6 +273,11 @@ func (ex *connExecutor) execStmtInOpenState(
 			}
 			typeHints = make(tree.PlaceholderTypes, stmt.NumPlaceholders)
 			for i, t := range s.Types {
+				if t.IsUnresolvedUserDefinedType() {
+					err := unimplemented.New("prepare-user-defined-type",
+						"user-defined types cannot be used as PREPARE argument types")
+					return makeErrEvent(err)
+				}
 				typeHints[i] = t
 			}
 		}
@@ -361,38 +331,52 @@ func (ex *connExecutor) execStmtInOpenState(
 	// For regular statements (the ones that get to this point), we don't return
 	// any event unless an an error happens.
 
+	if tree.CanModifySchema(stmt.AST) {
+		ex.extraTxnState.numDDL++
+	}
+
 	p := &ex.planner
 	stmtTS := ex.server.cfg.Clock.PhysicalTime()
 	ex.resetPlanner(ctx, p, ex.state.mu.txn, stmtTS, stmt.NumAnnotations)
 
 	if os.ImplicitTxn.Get() {
-		asOfTs, err := p.isAsOf(stmt.AST)
+		asOf, err := p.isAsOf(stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if asOfTs != nil {
-			p.semaCtx.AsOfTimestamp = asOfTs
-			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
-			ex.state.setHistoricalTimestamp(ctx, *asOfTs)
+		if asOf != nil {
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+			if asOf.BoundedStaleness {
+				// The timestamp of a bounded staleness read is only known once
+				// the read has been served. See setUpBoundedStalenessRead.
+				p.boundedStaleness = true
+			} else {
+				p.extendedEvalCtx.SetTxnTimestamp(asOf.Timestamp.GoTime())
+				ex.state.setHistoricalTimestamp(ctx, asOf.Timestamp)
+			}
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
 		// the transaction's timestamp. This is useful for running AOST statements
 		// using the InternalExecutor inside an external transaction; one might want
 		// to do that to force p.avoidCachedDescriptors to be set below.
-		ts, err := p.isAsOf(stmt.AST)
+		asOf, err := p.isAsOf(stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
-			if origTs := ex.state.getOrigTimestamp(); *ts != origTs {
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				return makeErrEvent(pgerror.Newf(pgcode.FeatureNotSupported,
+					"AS OF SYSTEM TIME: bounded staleness reads cannot be used inside a transaction"))
+			}
+			if origTs := ex.state.getOrigTimestamp(); asOf.Timestamp != origTs {
 				err = pgerror.Newf(pgcode.Syntax,
 					"inconsistent AS OF SYSTEM TIME timestamp; expected: %s", origTs)
 				err = errors.WithHint(err,
 					"Generally AS OF SYSTEM TIME cannot be used inside a transaction.")
 				return makeErrEvent(err)
 			}
-			p.semaCtx.AsOfTimestamp = ts
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
 		}
 	}
 
@@ -566,7 +550,7 @@ func (ex *connExecutor) checkTableTwoVersionInvariant(ctx context.Context) error
 func (ex *connExecutor) commitSQLTransaction(
 	ctx context.Context, stmt tree.Statement,
 ) (fsm.Event, fsm.EventPayload) {
-	ex.state.activeSavepointName = ""
+	ex.extraTxnState.savepoints = nil
 	isRelease := false
 	if _, ok := stmt.(*tree.ReleaseSavepoint); ok {
 		isRelease = true
@@ -576,6 +560,12 @@ func (ex *connExecutor) commitSQLTransaction(
 		return ex.makeErrEvent(err, stmt)
 	}
 
+	// Run the checks of the constraints that were deferred to the end of the
+	// transaction.
+	if err := ex.state.deferredChecks.RunChecks(ctx, ex.state.mu.txn); err != nil {
+		return ex.makeErrEvent(err, stmt)
+	}
+
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return ex.makeErrEvent(err, stmt)
 	}
@@ -589,7 +579,7 @@ func (ex *connExecutor) commitSQLTransaction(
 // rollbackSQLTransaction executes a ROLLBACK statement: the KV transaction is
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(ctx context.Context) (fsm.Event, fsm.EventPayload) {
-	ex.state.activeSavepointName = ""
+	ex.extraTxnState.savepoints = nil
 	if err := ex.state.mu.txn.Rollback(ctx); err != nil {
 		log.Warningf(ctx, "txn rollback failed: %s", err)
 	}
@@ -686,11 +676,19 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
+	if planner.boundedStaleness {
+		if err := planner.setUpBoundedStalenessRead(ctx); err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
+
 	ex.sessionTracing.TracePlanCheckStart(ctx)
 	distributePlan := false
 	// If we use the optimizer and we are in "local" mode, don't try to
-	// distribute.
-	if ex.sessionData.OptimizerMode != sessiondata.OptimizerLocal {
+	// distribute. Bounded staleness reads aren't distributed either, since
+	// their timestamp is negotiated by the gateway's txn.
+	if ex.sessionData.OptimizerMode != sessiondata.OptimizerLocal && !planner.boundedStaleness {
 		planner.prepareForDistSQLSupportCheck()
 		distributePlan = shouldDistributePlan(
 			ctx, ex.sessionData.DistSQLMode, ex.server.cfg.DistSQLPlanner, planner.curPlan.plan)
@@ -1012,6 +1010,9 @@ func (ex *connExecutor) execStmtInNoTxnState(
 // - COMMIT / ROLLBACK: aborts the current transaction.
 // - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
 //   allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT <name> for a savepoint other than cockroach_restart:
+//   rolls back the effects of the statements executed since the savepoint was
+//   established and moves the transaction back to the Open state.
 func (ex *connExecutor) execStmtInAbortedState(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult,
 ) (fsm.Event, fsm.EventPayload) {
@@ -1024,12 +1025,14 @@ func (ex *connExecutor) execStmtInAbortedState(
 			ev, payload := ex.rollbackSQLTransaction(ctx)
 			return ev, payload
 		}
-		ex.state.activeSavepointName = ""
 
 		// Note: Postgres replies to COMMIT of failed txn with "ROLLBACK" too.
 		res.ResetStmtType((*tree.RollbackTransaction)(nil))
 
-		return eventTxnFinish{}, eventTxnFinishPayload{commit: false}
+		// The KV transaction is not rolled back when entering the Aborted state,
+		// so that it can be recovered through ROLLBACK TO SAVEPOINT. Roll it
+		// back now.
+		return ex.rollbackSQLTransaction(ctx)
 	case *tree.RollbackToSavepoint, *tree.Savepoint:
 		// We accept both the "ROLLBACK TO SAVEPOINT cockroach_restart" and the
 		// "SAVEPOINT cockroach_restart" commands to indicate client intent to
@@ -1045,24 +1048,36 @@ func (ex *connExecutor) execStmtInAbortedState(
 		default:
 			panic("unreachable")
 		}
-		// If the user issued a SAVEPOINT in the abort state, validate
-		// as though there were no active savepoint.
-		if !isRollback {
-			ex.state.activeSavepointName = ""
+		if isRollback {
+			if idx, ok := ex.extraTxnState.savepoints.find(spName); ok &&
+				!ex.extraTxnState.savepoints[idx].isRestart() {
+				return ex.execRollbackToSavepointInAbortedState(ctx, idx, inRestartWait)
+			}
 		}
-		if err := ex.validateSavepointName(spName); err != nil {
+		if !ex.isRestartSavepoint(spName) {
+			var err error
+			if isRollback {
+				err = pgerror.Newf(pgcode.InvalidSavepointSpecification,
+					"savepoint %s does not exist", tree.ErrString(&spName))
+			} else {
+				err = sqlbase.NewTransactionAbortedError("" /* customMsg */)
+			}
 			ev := eventNonRetriableErr{IsCommit: fsm.False}
 			payload := eventNonRetriableErrPayload{
 				err: err,
 			}
 			return ev, payload
 		}
-		// Either clear or reset the current savepoint name so that
-		// ROLLBACK TO; SAVEPOINT; works.
+		// A ROLLBACK TO SAVEPOINT needs to refer to the established
+		// cockroach_restart savepoint, if any.
 		if isRollback {
-			ex.state.activeSavepointName = ""
-		} else {
-			ex.state.activeSavepointName = spName
+			if _, err := ex.findSavepoint(spName); err != nil {
+				ev := eventNonRetriableErr{IsCommit: fsm.False}
+				payload := eventNonRetriableErrPayload{
+					err: err,
+				}
+				return ev, payload
+			}
 		}
 
 		if !(inRestartWait || ex.machine.CurState().(stateAborted).RetryIntent.Get()) {
@@ -1073,6 +1088,14 @@ func (ex *connExecutor) execStmtInAbortedState(
 			return ev, payload
 		}
 
+		// Either clear or reset the savepoint stack so that ROLLBACK TO;
+		// SAVEPOINT; works. All the savepoints nested inside the
+		// cockroach_restart savepoint are discarded by the restart.
+		ex.extraTxnState.savepoints = nil
+		if !isRollback {
+			ex.extraTxnState.savepoints = savepointStack{{name: spName}}
+		}
+
 		res.ResetStmtType((*tree.RollbackTransaction)(nil))
 
 		if inRestartWait {
@@ -1336,25 +1359,3 @@ func payloadHasError(payload fsm.EventPayload) bool {
 	_, hasErr := payload.(payloadWithError)
 	return hasErr
 }
-
-// validateSavepointName validates that it is that the provided ident
-// matches the active savepoint name, begins with RestartSavepointName,
-// or that force_savepoint_restart==true. We accept everything with the
-// desired prefix because at least the C++ libpqxx appends sequence
-// numbers to the savepoint name specified by the user.
-func (ex *connExecutor) validateSavepointName(savepoint tree.Name) error {
-	if ex.state.activeSavepointName != "" {
-		if savepoint == ex.state.activeSavepointName {
-			return nil
-		}
-		return pgerror.Newf(pgcode.InvalidSavepointSpecification,
-			`SAVEPOINT %q is in use`, tree.ErrString(&ex.state.activeSavepointName))
-	}
-	if !ex.sessionData.ForceSavepointRestart && !strings.HasPrefix(string(savepoint), RestartSavepointName) {
-		return unimplemented.NewWithIssueHint(10735,
-			"SAVEPOINT not supported except for "+RestartSavepointName,
-			"Retryable transactions with arbitrary SAVEPOINT names can be enabled "+
-				"with SET force_savepoint_restart=true")
-	}
-	return nil
-}
@@ -186,13 +186,17 @@ func (ex *connExecutor) populatePrepared(
 
 	p.extendedEvalCtx.PrepareOnly = true
 
-	protoTS, err := p.isAsOf(stmt.AST)
+	asOf, err := p.isAsOf(stmt.AST)
 	if err != nil {
 		return 0, err
 	}
-	if protoTS != nil {
-		p.semaCtx.AsOfTimestamp = protoTS
-		txn.SetFixedTimestamp(ctx, *protoTS)
+	if asOf != nil {
+		p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+		// The timestamp of a bounded staleness read is only known once the read
+		// has been served, so the statement is prepared at the present time.
+		if !asOf.BoundedStaleness {
+			txn.SetFixedTimestamp(ctx, asOf.Timestamp)
+		}
 	}
 
 	// PREPARE has a limited subset of statements it can be run with. Postgres
@@ -0,0 +1,270 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+)
+
+// savepoint represents a SQL savepoint established in the current
+// transaction.
+type savepoint struct {
+	name tree.Name
+
+	// kvToken is the KV savepoint backing this SQL savepoint. It is nil for the
+	// cockroach_restart savepoint, which does not correspond to a KV savepoint:
+	// rolling back to it restarts the transaction and releasing it commits the
+	// transaction.
+	kvToken client.SavepointToken
+
+	// numDDL is the number of DDL statements that had been executed in the
+	// transaction when the savepoint was established. Rolling back over DDL
+	// statements is not supported.
+	numDDL int
+
+	// numDeferredChecks is the number of deferred constraint checks that had
+	// been queued when the savepoint was established. The checks queued
+	// afterwards are discarded when rolling back to the savepoint.
+	numDeferredChecks int
+}
+
+// isRestart returns true if this is a cockroach_restart savepoint.
+func (sp *savepoint) isRestart() bool {
+	return sp.kvToken == nil
+}
+
+// savepointStack is the stack of savepoints established in the current
+// transaction. The innermost savepoint is last.
+type savepointStack []savepoint
+
+// find returns the index of the innermost savepoint with the given name.
+func (stack savepointStack) find(name tree.Name) (int, bool) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// clone returns a copy of the stack.
+func (stack savepointStack) clone() savepointStack {
+	if len(stack) == 0 {
+		return nil
+	}
+	return append(savepointStack(nil), stack...)
+}
+
+// isRestartSavepoint returns true if the provided savepoint name designates
+// a cockroach_restart savepoint. We accept everything with the desired prefix
+// because at least the C++ libpqxx appends sequence numbers to the savepoint
+// name specified by the user. With force_savepoint_restart, all savepoints are
+// restart savepoints.
+func (ex *connExecutor) isRestartSavepoint(name tree.Name) bool {
+	return ex.sessionData.ForceSavepointRestart ||
+		strings.HasPrefix(string(name), RestartSavepointName)
+}
+
+// findSavepoint returns the index of the savepoint with the given name on the
+// savepoint stack. A cockroach_restart savepoint is popped off the stack when
+// it is rolled back to, yet clients are allowed to RELEASE or ROLLBACK TO it
+// again without re-establishing it first; in that case -1 is returned.
+func (ex *connExecutor) findSavepoint(name tree.Name) (int, error) {
+	if idx, ok := ex.extraTxnState.savepoints.find(name); ok {
+		return idx, nil
+	}
+	if len(ex.extraTxnState.savepoints) == 0 && ex.isRestartSavepoint(name) {
+		return -1, nil
+	}
+	return -1, pgerror.Newf(pgcode.InvalidSavepointSpecification,
+		"savepoint %s does not exist", tree.ErrString(&name))
+}
+
+// execSavepointInOpenState runs a SAVEPOINT statement in the Open state.
+func (ex *connExecutor) execSavepointInOpenState(
+	ctx context.Context, s *tree.Savepoint,
+) (fsm.Event, fsm.EventPayload) {
+	if !ex.isRestartSavepoint(s.Name) {
+		if ex.implicitTxn() {
+			err := pgerror.Newf(pgcode.NoActiveSQLTransaction,
+				"SAVEPOINT can only be used in transaction blocks")
+			return ex.makeErrEvent(err, s)
+		}
+		token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
+		ex.extraTxnState.savepoints = append(ex.extraTxnState.savepoints, savepoint{
+			name:              s.Name,
+			kvToken:           token,
+			numDDL:            ex.extraTxnState.numDDL,
+			numDeferredChecks: ex.state.deferredChecks.Len(),
+		})
+		// No state transition is required.
+		return nil, nil
+	}
+
+	// The cockroach_restart savepoint needs to be the outermost savepoint: rolling
+	// back to it restarts the whole transaction.
+	if len(ex.extraTxnState.savepoints) > 0 {
+		err := pgerror.Newf(pgcode.Syntax,
+			"SAVEPOINT %s cannot be nested", tree.ErrString(&s.Name))
+		return ex.makeErrEvent(err, s)
+	}
+	// We want to disallow SAVEPOINTs to be issued after a KV transaction has
+	// started running. The client txn's statement count indicates how many
+	// statements have been executed as part of this transaction. It is
+	// desirable to allow metadata queries against vtables to proceed
+	// before starting a SAVEPOINT for better ORM compatibility.
+	// See also:
+	// https://github.com/cockroachdb/cockroach/issues/15012
+	meta := ex.state.mu.txn.GetTxnCoordMeta(ctx)
+	if meta.CommandCount > 0 {
+		err := pgerror.Newf(pgcode.Syntax,
+			"SAVEPOINT %s needs to be the first statement in a "+
+				"transaction", RestartSavepointName)
+		return ex.makeErrEvent(err, s)
+	}
+	ex.extraTxnState.savepoints = append(ex.extraTxnState.savepoints, savepoint{
+		name:   s.Name,
+		numDDL: ex.extraTxnState.numDDL,
+	})
+	// Note that Savepoint doesn't have a corresponding plan node.
+	// This here is all the execution there is.
+	return eventRetryIntentSet{}, nil /* payload */
+}
+
+// execReleaseSavepointInOpenState runs a RELEASE SAVEPOINT statement in the
+// Open state.
+func (ex *connExecutor) execReleaseSavepointInOpenState(
+	ctx context.Context, s *tree.ReleaseSavepoint, res RestrictedCommandResult,
+) (fsm.Event, fsm.EventPayload) {
+	idx, err := ex.findSavepoint(s.Savepoint)
+	if err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	if idx == -1 || ex.extraTxnState.savepoints[idx].isRestart() {
+		if !ex.machine.CurState().(stateOpen).RetryIntent.Get() {
+			return ex.makeErrEvent(errSavepointNotUsed, s)
+		}
+		// Releasing the cockroach_restart savepoint commits the transaction.
+		ev, payload := ex.commitSQLTransaction(ctx, s)
+		res.ResetStmtType((*tree.CommitTransaction)(nil))
+		return ev, payload
+	}
+
+	if err := ex.state.mu.txn.ReleaseSavepoint(ctx, ex.extraTxnState.savepoints[idx].kvToken); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	// Releasing a savepoint also releases all the savepoints established after
+	// it.
+	ex.extraTxnState.savepoints = ex.extraTxnState.savepoints[:idx]
+	return nil, nil
+}
+
+// execRollbackToSavepointInOpenState runs a ROLLBACK TO SAVEPOINT statement in
+// the Open state.
+func (ex *connExecutor) execRollbackToSavepointInOpenState(
+	ctx context.Context, s *tree.RollbackToSavepoint, res RestrictedCommandResult,
+) (fsm.Event, fsm.EventPayload) {
+	idx, err := ex.findSavepoint(s.Savepoint)
+	if err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	if idx == -1 || ex.extraTxnState.savepoints[idx].isRestart() {
+		if !ex.machine.CurState().(stateOpen).RetryIntent.Get() {
+			return ex.makeErrEvent(errSavepointNotUsed, s)
+		}
+		// Rolling back to the cockroach_restart savepoint restarts the
+		// transaction. The savepoint is discarded, together with all the
+		// savepoints nested inside it, so that it can be re-established.
+		ex.extraTxnState.savepoints = nil
+		res.ResetStmtType((*tree.Savepoint)(nil))
+		return eventTxnRestart{}, nil /* payload */
+	}
+
+	if err := ex.rollbackToSavepoint(ctx, idx); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	return nil, nil
+}
+
+// execRollbackToSavepointInAbortedState runs a ROLLBACK TO SAVEPOINT statement
+// targeting the (regular) savepoint at position idx on the stack in the
+// Aborted or RestartWait states. If successful, the transaction moves back to
+// the Open state.
+func (ex *connExecutor) execRollbackToSavepointInAbortedState(
+	ctx context.Context, idx int, inRestartWait bool,
+) (fsm.Event, fsm.EventPayload) {
+	makeErrEvent := func(err error) (fsm.Event, fsm.EventPayload) {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	if inRestartWait {
+		// The transaction has been restarted, so all the savepoints
+		// established since the cockroach_restart savepoint are gone.
+		sp := &ex.extraTxnState.savepoints[idx]
+		return makeErrEvent(pgerror.Newf(pgcode.InvalidSavepointSpecification,
+			"cannot roll back to savepoint %s after a transaction restart; "+
+				"use ROLLBACK TO SAVEPOINT %s", tree.ErrString(&sp.name), RestartSavepointName))
+	}
+	if err := ex.rollbackToSavepoint(ctx, idx); err != nil {
+		return makeErrEvent(err)
+	}
+	return eventSavepointRollback{}, nil /* payload */
+}
+
+// rollbackToSavepoint rolls back the KV transaction to the (regular) savepoint
+// at position idx on the stack. The savepoint remains established; all the
+// savepoints established after it are destroyed.
+func (ex *connExecutor) rollbackToSavepoint(ctx context.Context, idx int) error {
+	sp := &ex.extraTxnState.savepoints[idx]
+	if ex.extraTxnState.numDDL > sp.numDDL {
+		return unimplemented.NewWithIssueDetail(10735, "rollback-after-ddl",
+			"ROLLBACK TO SAVEPOINT not yet supported after DDL statements")
+	}
+	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, sp.kvToken); err != nil {
+		return err
+	}
+	ex.state.deferredChecks.Truncate(ctx, sp.numDeferredChecks)
+	ex.extraTxnState.savepoints = ex.extraTxnState.savepoints[:idx+1]
+	return nil
+}
+
+// rewindSavepoints restores the savepoint stack as it was at the rewind
+// position when an automatic retry is performed. The savepoints established
+// before the rewind position were established before the transaction
+// performed any writes; they are re-established in the restarted
+// transaction.
+func (ex *connExecutor) rewindSavepoints(ctx context.Context) error {
+	ex.extraTxnState.savepoints = ex.extraTxnState.savepointsAtTxnRewindPos.clone()
+	for i := range ex.extraTxnState.savepoints {
+		sp := &ex.extraTxnState.savepoints[i]
+		if sp.isRestart() {
+			continue
+		}
+		token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+		if err != nil {
+			return err
+		}
+		sp.kvToken = token
+	}
+	return nil
+}
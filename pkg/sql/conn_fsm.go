@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
 // Constants for the String() representation of the session states. Shared with
@@ -163,6 +164,11 @@ func (e eventTxnFinishPayload) toEvent() txnEvent {
 
 type eventTxnRestart struct{}
 
+// eventSavepointRollback is generated in the Aborted state after a successful
+// ROLLBACK TO SAVEPOINT targeting a savepoint other than cockroach_restart. It
+// moves the state back to Open.
+type eventSavepointRollback struct{}
+
 type eventNonRetriableErr struct {
 	IsCommit fsm.Bool
 }
@@ -212,13 +218,14 @@ type payloadWithError interface {
 	errorCause() error
 }
 
-func (eventRetryIntentSet) Event()  {}
-func (eventTxnStart) Event()        {}
-func (eventTxnFinish) Event()       {}
-func (eventTxnRestart) Event()      {}
-func (eventNonRetriableErr) Event() {}
-func (eventRetriableErr) Event()    {}
-func (eventTxnReleased) Event()     {}
+func (eventRetryIntentSet) Event()    {}
+func (eventTxnStart) Event()          {}
+func (eventTxnFinish) Event()         {}
+func (eventTxnRestart) Event()        {}
+func (eventNonRetriableErr) Event()   {}
+func (eventRetriableErr) Event()      {}
+func (eventTxnReleased) Event()       {}
+func (eventSavepointRollback) Event() {}
 
 // TxnStateTransitions describe the transitions used by a connExecutor's
 // fsm.Machine. Args.Extended is a txnState, which is muted by the Actions.
@@ -324,8 +331,11 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Next: stateAborted{RetryIntent: fsm.Var("retryIntent")},
 			Action: func(args fsm.Args) error {
 				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
-				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
+				// Note that we don't roll back the KV txn here: the client might
+				// recover from the error through ROLLBACK TO SAVEPOINT. The KV txn is
+				// rolled back when the SQL txn is finished. The txnErrored event
+				// releases the SQL-level resources held by the txn in the meantime.
+				ts.setAdvanceInfo(skipBatch, noRewind, txnErrored)
 				ts.txnAbortCount.Inc(1)
 				return nil
 			},
@@ -419,6 +429,16 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 				return nil
 			},
 		},
+		// ROLLBACK TO SAVEPOINT <not cockroach_restart>. The KV txn has already
+		// been rolled back to the savepoint; we just move back to Open.
+		eventSavepointRollback{}: {
+			Description: "ROLLBACK TO SAVEPOINT (not cockroach_restart) success",
+			Next:        stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.Var("retryIntent")},
+			Action: func(args fsm.Args) error {
+				args.Extended.(*txnState).setAdvanceInfo(advanceOne, noRewind, noEvent)
+				return nil
+			},
+		},
 	},
 	stateAborted{RetryIntent: fsm.True}: {
 		// ROLLBACK TO SAVEPOINT. We accept this in the Aborted state for the
@@ -429,6 +449,11 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Next:        stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.True},
 			Action: func(args fsm.Args) error {
 				ts := args.Extended.(*txnState)
+				// The KV txn was not rolled back when entering the Aborted state.
+				// We're abandoning it in favor of a new one, so roll it back now.
+				if err := ts.mu.txn.Rollback(ts.Ctx); err != nil {
+					log.Warningf(ts.Ctx, "txn rollback failed: %s", err)
+				}
 				ts.finishSQLTxn()
 
 				payload := args.Payload.(eventTxnStartPayload)
@@ -444,7 +469,7 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 					nil, /* txn */
 					args.Payload.(eventTxnStartPayload).tranCtx,
 				)
-				ts.setAdvanceInfo(advanceOne, noRewind, noEvent)
+				ts.setAdvanceInfo(advanceOne, noRewind, txnRestart)
 				return nil
 			},
 		},
@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -64,6 +65,7 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
 		sqlbase.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
 		sqlbase.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
+		sqlbase.CrdbInternalChangefeedStatusTableID:     crdbInternalChangefeedStatusTable,
 		sqlbase.CrdbInternalClusterQueriesTableID:       crdbInternalClusterQueriesTable,
 		sqlbase.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
 		sqlbase.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
@@ -341,6 +343,9 @@ CREATE TABLE crdb_internal.schema_changes (
 				case *sqlbase.DescriptorMutation_Constraint:
 					mutType = "CONSTRAINT VALIDATION"
 					targetName = tree.NewDString(d.Constraint.Name)
+				case *sqlbase.DescriptorMutation_PrimaryKeySwap:
+					mutType = "PRIMARY KEY SWAP"
+					targetID = tree.NewDInt(tree.DInt(int64(d.PrimaryKeySwap.NewPrimaryIndexID)))
 				}
 				if err := addRow(
 					tableID,
@@ -552,6 +557,74 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+// crdbInternalChangefeedStatusTable exposes the progress of the changefeed
+// processors running on this node, with one row per span they track.
+var crdbInternalChangefeedStatusTable = virtualSchemaTable{
+	comment: `changefeed span frontiers and sink progress (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.changefeed_status (
+  job_id           INT,               -- the changefeed job, NULL for a sinkless changefeed
+  processor        STRING NOT NULL,   -- either aggregator or frontier
+  sink_uri         STRING NOT NULL,   -- the sink, without its query parameters
+  start_pretty     STRING NOT NULL,   -- start of the tracked span
+  end_pretty       STRING NOT NULL,   -- end of the tracked span
+  resolved         DECIMAL,           -- resolved timestamp of the span, if any
+  lag              INTERVAL,          -- time between the resolved timestamp and now
+  slowest          BOOL NOT NULL,     -- whether the span is the furthest behind of the processor
+  emitted_messages INT NOT NULL,      -- messages emitted to the sink by the processor
+  emitted_bytes    INT NOT NULL       -- bytes emitted to the sink by the processor
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireSuperUser(ctx, "read crdb_internal.changefeed_status"); err != nil {
+			return err
+		}
+
+		// The changefeed metrics are only set up when the CCL code is linked in,
+		// otherwise no changefeeds can be running.
+		source, ok := p.ExecCfg().JobRegistry.MetricsStruct().Changefeed.(jobs.ChangefeedStatusSource)
+		if !ok {
+			return nil
+		}
+		now := timeutil.Now()
+		for _, status := range source.ChangefeedStatuses() {
+			jobID := tree.DNull
+			if status.JobID != 0 {
+				jobID = tree.NewDInt(tree.DInt(status.JobID))
+			}
+			slowest := -1
+			for i, span := range status.Spans {
+				if slowest == -1 || span.Timestamp.Less(status.Spans[slowest].Timestamp) {
+					slowest = i
+				}
+			}
+			for i, span := range status.Spans {
+				resolved, lag := tree.DNull, tree.DNull
+				if !span.Timestamp.IsEmpty() {
+					resolved = tree.TimestampToDecimal(span.Timestamp)
+					lag = &tree.DInterval{
+						Duration: duration.MakeDuration(now.Sub(span.Timestamp.GoTime()).Nanoseconds(), 0, 0),
+					}
+				}
+				if err := addRow(
+					jobID,
+					tree.NewDString(status.Processor),
+					tree.NewDString(status.SinkURI),
+					tree.NewDString(keys.PrettyPrint(nil /* valDirs */, span.Span.Key)),
+					tree.NewDString(keys.PrettyPrint(nil /* valDirs */, span.Span.EndKey)),
+					resolved,
+					lag,
+					tree.MakeDBool(i == slowest),
+					tree.NewDInt(tree.DInt(status.EmittedMessages)),
+					tree.NewDInt(tree.DInt(status.EmittedBytes)),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
 type stmtList []stmtKey
 
 func (s stmtList) Len() int {
@@ -0,0 +1,195 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+
+	params     []sqlbase.FunctionDescriptor_Param
+	returnType types.T
+	body       string
+	impure     bool
+}
+
+// CreateFunction creates a user-defined function written in SQL.
+// Privileges: CREATE on database.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	node := &createFunctionNode{
+		n:      n,
+		dbDesc: dbDesc,
+		// Functions are volatile unless specified otherwise.
+		impure: true,
+	}
+	if err := node.analyzeOptions(); err != nil {
+		return nil, err
+	}
+
+	seenParams := make(map[tree.Name]struct{}, len(n.Params))
+	node.params = make([]sqlbase.FunctionDescriptor_Param, len(n.Params))
+	for i, param := range n.Params {
+		if param.Name != "" {
+			if _, ok := seenParams[param.Name]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", param.Name)
+			}
+			seenParams[param.Name] = struct{}{}
+		}
+		typ, err := tree.ResolveType(param.Type, &p.semaCtx)
+		if err != nil {
+			return nil, err
+		}
+		node.params[i] = sqlbase.FunctionDescriptor_Param{Name: string(param.Name), Type: *typ}
+	}
+
+	returnType, err := tree.ResolveType(n.ReturnType, &p.semaCtx)
+	if err != nil {
+		return nil, err
+	}
+	node.returnType = *returnType
+
+	return node, nil
+}
+
+// analyzeOptions validates the options of the CREATE FUNCTION statement and
+// extracts the body and volatility of the function from them.
+func (n *createFunctionNode) analyzeOptions() error {
+	var language string
+	seenBody, seenVolatility := false, false
+	for _, opt := range n.n.Options {
+		switch opt.Name {
+		case tree.FuncOptLanguage:
+			if language != "" {
+				return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+			}
+			language = strings.ToLower(opt.StrVal)
+
+		case tree.FuncOptAs:
+			if seenBody {
+				return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+			}
+			seenBody = true
+			n.body = opt.StrVal
+
+		case tree.FuncOptImmutable, tree.FuncOptStable, tree.FuncOptVolatile:
+			if seenVolatility {
+				return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+			}
+			seenVolatility = true
+			// Stable functions return the same result for the same arguments
+			// within a single statement, which is all that the optimizer cares
+			// about.
+			n.impure = opt.Name == tree.FuncOptVolatile
+		}
+	}
+
+	if language == "" {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified")
+	}
+	if language != "sql" {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"language %q is not supported; only SQL functions can be created", language)
+	}
+	if !seenBody {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+
+	// The body must consist of a single SELECT statement. It is type checked
+	// when the function is used, since it is inlined into the calling query.
+	stmt, err := parser.ParseOne(n.body)
+	if err != nil {
+		return pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid function body")
+	}
+	if _, ok := stmt.AST.(*tree.Select); !ok {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"the body of a SQL function must be a SELECT statement, found %s",
+			stmt.AST.StatementTag())
+	}
+	// Store the body in canonical form.
+	n.body = tree.AsStringWithFlags(stmt.AST, tree.FmtParsable)
+	return nil
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	funcName := n.n.Name.Table()
+	fKey := sqlbase.NewTableKey(n.dbDesc.ID, funcName)
+	if exists, err := descExists(params.ctx, params.p.txn, fKey.Key()); err == nil && exists {
+		return pgerror.Newf(pgcode.DuplicateFunction, "function %q already exists", funcName)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	funcDesc := sqlbase.FunctionDescriptor{
+		Name:       funcName,
+		ParentID:   n.dbDesc.ID,
+		Params:     n.params,
+		ReturnType: n.returnType,
+		Body:       n.body,
+		Impure:     n.impure,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := params.p.createDescriptorWithID(
+		params.ctx, fKey.Key(), id, &funcDesc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	if err := funcDesc.Validate(); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor creation.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateFunction,
+		int32(funcDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
@@ -48,7 +48,16 @@ type createTableNode struct {
 // Privileges: CREATE on database.
 //   Notes: postgres/mysql require CREATE on database.
 func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNode, error) {
-	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Table)
+	isTemporary, err := n.Table.ResolveTemporaryStatus(n.Temporary, p.SessionData().SearchPath)
+	if err != nil {
+		return nil, err
+	}
+	n.Temporary = isTemporary
+
+	var dbDesc *DatabaseDescriptor
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		dbDesc, err = resolveTargetObject(ctx, p, &n.Table, n.Temporary)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +111,18 @@ type createTableRun struct {
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	tKey := sqlbase.NewTableKey(n.dbDesc.ID, n.n.Table.Table())
+	// Temporary tables are stored in system.namespace under the temporary
+	// schema of the session, which is created on first use.
+	parentID := n.dbDesc.ID
+	if n.n.Temporary {
+		var err error
+		parentID, err = params.p.getOrCreateTemporarySchema(params.ctx, n.dbDesc.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	tKey := sqlbase.NewTableKey(parentID, n.n.Table.Table())
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		if n.n.IfNotExists {
@@ -184,6 +204,11 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if n.n.Temporary {
+		desc.Temporary = true
+		desc.TemporarySchemaID = parentID
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx, key, id, &desc, params.EvalContext().Settings); err != nil {
@@ -196,6 +221,10 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if err := params.p.addTypeBackReferences(params.ctx, &desc); err != nil {
+		return err
+	}
+
 	for _, index := range desc.AllNonDropIndexes() {
 		if len(index.Interleave.Ancestors) > 0 {
 			if err := params.p.finalizeInterleave(params.ctx, &desc, index); err != nil {
@@ -466,6 +495,14 @@ func ResolveFK(
 	if err != nil {
 		return err
 	}
+	if target.Temporary != tbl.Temporary {
+		persistence := "permanent"
+		if tbl.Temporary {
+			persistence = "temporary"
+		}
+		return pgerror.Newf(pgcode.InvalidTableDefinition,
+			"constraints on %s tables may reference only %s tables", persistence, persistence)
+	}
 	if target.ID == tbl.ID {
 		// When adding a self-ref FK to an _existing_ table, we want to make sure
 		// we edit the same copy.
@@ -582,13 +619,23 @@ func ResolveFK(
 	}
 
 	ref := sqlbase.ForeignKeyReference{
-		Table:           target.ID,
-		Index:           targetIdxID,
-		Name:            constraintName,
-		SharedPrefixLen: int32(len(srcCols)),
-		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
-		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
-		Match:           sqlbase.CompositeKeyMatchMethodValue[d.Match],
+		Table:             target.ID,
+		Index:             targetIdxID,
+		Name:              constraintName,
+		SharedPrefixLen:   int32(len(srcCols)),
+		OnDelete:          sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
+		OnUpdate:          sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
+		Match:             sqlbase.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:        d.Deferrable != tree.NotDeferrable,
+		InitiallyDeferred: d.Deferrable == tree.DeferrableInitiallyDeferred,
+	}
+
+	// Triggers don't fire for the rows modified by cascading actions.
+	if len(tbl.Triggers) > 0 &&
+		(isCascadingFKAction(ref.OnDelete) || isCascadingFKAction(ref.OnUpdate)) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot add a cascading action to foreign key constraint %q on table %q, which has triggers",
+			constraintName, tbl.Name)
 	}
 
 	if ts != NewTable {
@@ -598,7 +645,7 @@ func ResolveFK(
 			ref.Validity = sqlbase.ConstraintValidity_Validating
 		}
 	}
-	backref := sqlbase.ForeignKeyReference{Table: tbl.ID}
+	backref := makeFKBackReference(tbl.ID, 0 /* indexID */, &ref)
 
 	var idx *sqlbase.IndexDescriptor
 	found := false
@@ -680,6 +727,23 @@ func ResolveFK(
 	return nil
 }
 
+// makeFKBackReference returns the back-reference, stored on the referenced
+// index, of the given foreign key constraint on the given index of the
+// referencing table. The back-reference of a deferrable constraint carries its
+// name and mode, for the checks performed on mutations of the referenced table
+// to be deferred too.
+func makeFKBackReference(
+	tableID sqlbase.ID, indexID sqlbase.IndexID, ref *sqlbase.ForeignKeyReference,
+) sqlbase.ForeignKeyReference {
+	backref := sqlbase.ForeignKeyReference{Table: tableID, Index: indexID}
+	if ref.Deferrable {
+		backref.Name = ref.Name
+		backref.Deferrable = true
+		backref.InitiallyDeferred = ref.InitiallyDeferred
+	}
+	return backref
+}
+
 // Adds an index to a table descriptor (that is in the process of being created)
 // that will support using `srcCols` as the referencing (src) side of an FK.
 func addIndexForFK(
@@ -1083,6 +1147,7 @@ func MakeTableDesc(
 	evalCtx *tree.EvalContext,
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := InitTableDescriptor(id, parentID, n.Table.Table(), creationTime, privileges)
+	desc.Temporary = n.Temporary
 
 	for _, def := range n.Defs {
 		if d, ok := def.(*tree.ColumnTableDef); ok {
@@ -1188,6 +1253,13 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
+			if d.Deferrable != tree.NotDeferrable {
+				// The index of a DEFERRABLE UNIQUE constraint can hold duplicate
+				// values until the constraint is checked, so it is not unique.
+				idx.Unique = false
+				idx.DeferrableUnique = true
+				idx.InitiallyDeferred = d.Deferrable == tree.DeferrableInitiallyDeferred
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
@@ -0,0 +1,130 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+	trigger   sqlbase.TableDescriptor_Trigger
+}
+
+// CreateTrigger creates a trigger on a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires TRIGGER on the table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &n.Table, true /*required*/, ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	if err := checkNoCascadingFKs(tableDesc); err != nil {
+		return nil, err
+	}
+
+	trigger := sqlbase.TableDescriptor_Trigger{
+		Name:       string(n.Name),
+		Before:     n.Before,
+		ForEachRow: n.ForEachRow,
+	}
+	for _, event := range n.Events {
+		var seen *bool
+		switch event.Type {
+		case tree.TriggerEventInsert:
+			seen = &trigger.OnInsert
+		case tree.TriggerEventUpdate:
+			seen = &trigger.OnUpdate
+		case tree.TriggerEventDelete:
+			seen = &trigger.OnDelete
+		}
+		if *seen {
+			return nil, pgerror.Newf(pgcode.Syntax, "duplicate trigger event %s", event.Type)
+		}
+		*seen = true
+
+		for _, colName := range event.Columns {
+			col, err := tableDesc.FindActiveColumnByName(string(colName))
+			if err != nil {
+				return nil, err
+			}
+			trigger.UpdateColumnIDs = append(trigger.UpdateColumnIDs, col.ID)
+		}
+	}
+
+	trigger.Statement, err = analyzeTriggerStatement(tableDesc, &trigger, n.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, trigger: trigger}, nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	if tableDesc.FindTriggerByName(n.trigger.Name) != -1 {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %q for relation %q already exists", n.trigger.Name, tableDesc.Name)
+	}
+
+	// Triggers are kept sorted by name, which is the order in which they fire.
+	idx := sort.Search(len(tableDesc.Triggers), func(i int) bool {
+		return tableDesc.Triggers[i].Name > n.trigger.Name
+	})
+	tableDesc.Triggers = append(tableDesc.Triggers, sqlbase.TableDescriptor_Trigger{})
+	copy(tableDesc.Triggers[idx+1:], tableDesc.Triggers[idx:])
+	tableDesc.Triggers[idx] = n.trigger
+
+	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
+		return err
+	}
+
+	if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
+		return err
+	}
+
+	// Record trigger creation in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogCreateTrigger,
+		int32(tableDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TableName   string
+			TriggerName string
+			Statement   string
+			User        string
+		}{n.n.Table.FQString(), n.trigger.Name, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
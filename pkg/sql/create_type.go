@@ -0,0 +1,175 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/enum"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createTypeNode struct {
+	n      *tree.CreateType
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateType creates a user-defined enum type.
+// Privileges: CREATE on database.
+func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createTypeNode{
+		n:      n,
+		dbDesc: dbDesc,
+	}, nil
+}
+
+func (n *createTypeNode) startExec(params runParams) error {
+	seenLabels := make(map[string]struct{}, len(n.n.EnumLabels))
+	for _, label := range n.n.EnumLabels {
+		if _, ok := seenLabels[label]; ok {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"enum definition contains duplicate value %q", label)
+		}
+		seenLabels[label] = struct{}{}
+	}
+
+	typeName := n.n.Name.Table()
+	tKey := sqlbase.NewTableKey(n.dbDesc.ID, typeName)
+	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
+		return pgerror.Newf(pgcode.DuplicateObject, "type %q already exists", typeName)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// The physical representations of the members are spread out so that new
+	// members can be added in between existing ones by ALTER TYPE.
+	physReps := enum.GenerateNEvenlySpacedBytes(len(n.n.EnumLabels))
+	members := make([]sqlbase.TypeDescriptor_EnumMember, len(n.n.EnumLabels))
+	for i, label := range n.n.EnumLabels {
+		members[i] = sqlbase.TypeDescriptor_EnumMember{
+			PhysicalRepresentation: physReps[i],
+			LogicalRepresentation:  label,
+		}
+	}
+
+	// Inherit permissions from the database descriptor.
+	typeDesc := sqlbase.TypeDescriptor{
+		Name:        typeName,
+		ParentID:    n.dbDesc.ID,
+		EnumMembers: members,
+		Privileges:  n.dbDesc.GetPrivileges(),
+	}
+	if err := params.p.createDescriptorWithID(
+		params.ctx, tKey.Key(), id, &typeDesc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	if err := typeDesc.Validate(); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is recorded in
+	// the same transaction as the type descriptor creation.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateType,
+		int32(typeDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTypeNode) Close(context.Context)        {}
+
+// addTypeBackReferences records, in the descriptors of the user-defined types
+// used by the columns of the given table, that the table references them. The
+// back-references are used to update the columns of the table when the types
+// are altered.
+func (p *planner) addTypeBackReferences(
+	ctx context.Context, tableDesc *sqlbase.MutableTableDescriptor,
+) error {
+	seen := make(map[sqlbase.ID]struct{})
+	addRef := func(col *sqlbase.ColumnDescriptor) error {
+		if !col.Type.UserDefined() {
+			return nil
+		}
+		typeID := sqlbase.ID(col.Type.StableTypeID())
+		if _, ok := seen[typeID]; ok {
+			return nil
+		}
+		seen[typeID] = struct{}{}
+		var typeDesc sqlbase.TypeDescriptor
+		if err := getDescriptorByID(ctx, p.txn, typeID, &typeDesc); err != nil {
+			return err
+		}
+		if !typeDesc.AddReferencingDescriptorID(tableDesc.ID) {
+			return nil
+		}
+		return p.writeTypeDesc(ctx, &typeDesc)
+	}
+	for i := range tableDesc.Columns {
+		if err := addRef(&tableDesc.Columns[i]); err != nil {
+			return err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if col := m.GetColumn(); col != nil {
+			if err := addRef(col); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeTypeDesc writes the given type descriptor in the planner's
+// transaction.
+func (p *planner) writeTypeDesc(ctx context.Context, typeDesc *sqlbase.TypeDescriptor) error {
+	if err := typeDesc.Validate(); err != nil {
+		return err
+	}
+	descKey := sqlbase.MakeDescMetadataKey(typeDesc.ID)
+	descDesc := sqlbase.WrapDescriptor(typeDesc)
+	b := &client.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", descKey, descDesc)
+	}
+	b.Put(descKey, descDesc)
+	return p.txn.Run(ctx, b)
+}
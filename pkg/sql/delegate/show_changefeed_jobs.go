@@ -0,0 +1,43 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// delegateShowChangefeedJobs implements SHOW CHANGEFEED JOBS, which lists the
+// changefeed jobs along with their high-water timestamp and, for running
+// changefeeds, how far behind the present it is. The per-span progress of a
+// changefeed is in crdb_internal.changefeed_status.
+func (d *delegator) delegateShowChangefeedJobs(
+	n *tree.ShowChangefeedJobs,
+) (tree.Statement, error) {
+	// The ordering matches SHOW JOBS: first the running jobs sorted by start
+	// time, then the completed jobs sorted by completion time.
+	return parse(fmt.Sprintf(
+		`SELECT job_id, description, user_name, status, running_status, created, started, finished,
+            modified, high_water_timestamp,
+            CASE WHEN status = '%[2]s' AND high_water_timestamp IS NOT NULL
+              THEN now() - (high_water_timestamp / 1e9)::INT::TIMESTAMPTZ
+            END AS lag,
+            error, coordinator_id
+		FROM crdb_internal.jobs
+		WHERE job_type = '%[1]s'
+		AND (finished IS NULL OR finished > now() - '12h':::interval)
+		ORDER BY COALESCE(finished, now()) DESC, started DESC`,
+		jobspb.TypeChangefeed, jobs.StatusRunning,
+	))
+}
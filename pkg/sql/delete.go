@@ -114,11 +114,11 @@ func (p *planner) Delete(
 	// Also, rowsNeeded determines which rows of the source we need
 	// in the table deleter.
 	var requestedCols []sqlbase.ColumnDescriptor
-	if rowsNeeded {
+	if rowsNeeded || len(desc.Triggers) > 0 {
 		// Note: in contrast to INSERT and UPDATE which also require the
 		// data if there are CHECK expressions, DELETE does not care about
 		// constraint checking (because the rows are being deleted after
-		// all).
+		// all). Row-level triggers are passed the complete deleted row.
 
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
@@ -229,7 +229,11 @@ func (d *deleteNode) startExec(params runParams) error {
 			params.EvalContext().Mon.MakeBoundAccount(),
 			sqlbase.ColTypeInfoFromResCols(d.columns), 0)
 	}
-	return d.run.td.init(params.p.txn, params.EvalContext())
+	if err := d.run.td.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	d.run.td.rd.DeferFKChecks(params.extendedEvalCtx.DeferredChecks)
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
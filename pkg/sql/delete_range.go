@@ -71,6 +71,11 @@ func maybeCreateDeleteFastNode(
 		return nil, false
 	}
 
+	// Triggers must be fired for the deleted rows.
+	if len(desc.Triggers) > 0 {
+		return nil, false
+	}
+
 	// Check whether the source plan is "simple": that it contains no remaining
 	// filtering, limiting, sorting, etc. Note that this logic must be kept in
 	// sync with the logic for setting scanNode.isDeleteSource (see doExpandPlan.)
@@ -143,7 +148,12 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 		return err
 	}
 	if err := d.fetcher.Init(
-		false, false, false, &params.p.alloc,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		&params.p.alloc,
 		row.FetcherTableArgs{
 			Desc:  d.desc,
 			Index: &d.desc.PrimaryIndex,
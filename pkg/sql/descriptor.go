@@ -194,10 +194,115 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.TypeDescriptor:
+		typ := desc.GetType()
+		if typ == nil {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"%q is not a type", desc.String())
+		}
+
+		if err := typ.Validate(); err != nil {
+			return err
+		}
+		*t = *typ
+	case *sqlbase.FunctionDescriptor:
+		fn := desc.GetFunction()
+		if fn == nil {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"%q is not a function", desc.String())
+		}
+
+		if err := fn.Validate(); err != nil {
+			return err
+		}
+		*t = *fn
 	}
 	return nil
 }
 
+// getDescriptorsFromIDs looks up the descriptors with the given IDs in a
+// single batch. The descriptor of an ID that does not exist is nil.
+func getDescriptorsFromIDs(
+	ctx context.Context, txn *client.Txn, ids []sqlbase.ID,
+) ([]*sqlbase.Descriptor, error) {
+	descs := make([]*sqlbase.Descriptor, len(ids))
+	if len(ids) == 0 {
+		return descs, nil
+	}
+	b := txn.NewBatch()
+	for _, id := range ids {
+		b.Get(sqlbase.MakeDescMetadataKey(id))
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	for i := range b.Results {
+		result := &b.Results[i]
+		if len(result.Rows) == 0 || result.Rows[0].Value == nil {
+			continue
+		}
+		desc := &sqlbase.Descriptor{}
+		if err := result.Rows[0].ValueProto(desc); err != nil {
+			return nil, err
+		}
+		descs[i] = desc
+	}
+	return descs, nil
+}
+
+// getDescriptorsInNamespace returns the descriptors of all the objects whose
+// names are stored under the given parent ID, such as the tables,
+// user-defined types and functions of a database. Names whose descriptor no longer exists
+// are skipped.
+func getDescriptorsInNamespace(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID,
+) ([]*sqlbase.Descriptor, error) {
+	log.Eventf(ctx, "fetching descriptors of the objects in namespace %d", parentID)
+	nameKey := sqlbase.MakeNameMetadataKey(parentID, "" /* name */)
+	kvs, err := txn.Scan(ctx, nameKey, nameKey.PrefixEnd(), 0 /* maxRows */)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]sqlbase.ID, 0, len(kvs))
+	for _, kv := range kvs {
+		if kv.Value == nil {
+			continue
+		}
+		ids = append(ids, sqlbase.ID(kv.ValueInt()))
+	}
+	descs, err := getDescriptorsFromIDs(ctx, txn, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := descs[:0]
+	for _, desc := range descs {
+		if desc != nil {
+			found = append(found, desc)
+		}
+	}
+	return found, nil
+}
+
+// GetAllDatabaseDescriptorIDs looks up and returns all available database
+// descriptor IDs.
+func GetAllDatabaseDescriptorIDs(ctx context.Context, txn *client.Txn) ([]sqlbase.ID, error) {
+	log.Eventf(ctx, "fetching all database descriptor IDs")
+	nameKey := sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, "" /* name */)
+	kvs, err := txn.Scan(ctx, nameKey, nameKey.PrefixEnd(), 0 /*maxRows */)
+	if err != nil {
+		return nil, err
+	}
+
+	descIDs := make([]sqlbase.ID, 0, len(kvs))
+	for _, kv := range kvs {
+		if kv.Value == nil {
+			continue
+		}
+		descIDs = append(descIDs, sqlbase.ID(kv.ValueInt()))
+	}
+	return descIDs, nil
+}
+
 // GetAllDescriptors looks up and returns all available descriptors.
 func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.DescriptorProto, error) {
 	log.Eventf(ctx, "fetching all descriptors")
@@ -218,6 +323,10 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Type:
+			descs[i] = desc.GetType()
+		case *sqlbase.Descriptor_Function:
+			descs[i] = desc.GetFunction()
 		default:
 			return nil, errors.AssertionFailedf("Descriptor.Union has unexpected type %T", t)
 		}
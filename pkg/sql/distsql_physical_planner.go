@@ -872,6 +872,9 @@ func initTableReaderSpec(
 		IsCheck:    n.isCheck,
 		Visibility: n.colCfg.visibility.toDistSQLScanVisibility(),
 
+		LockingStrength:   distsqlpb.ToScanLockingStrength(n.lockingStrength),
+		LockingWaitPolicy: distsqlpb.ToScanLockingWaitPolicy(n.lockingWaitPolicy),
+
 		// Retain the capacity of the spans slice.
 		Spans: s.Spans[:0],
 	}
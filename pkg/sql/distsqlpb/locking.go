@@ -0,0 +1,84 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package distsqlpb
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// ToScanLockingStrength converts a tree.LockingStrength to its corresponding
+// ScanLockingStrength.
+func ToScanLockingStrength(s tree.LockingStrength) ScanLockingStrength {
+	switch s {
+	case tree.ForNone:
+		return ScanLockingStrength_FOR_NONE
+	case tree.ForKeyShare:
+		return ScanLockingStrength_FOR_KEY_SHARE
+	case tree.ForShare:
+		return ScanLockingStrength_FOR_SHARE
+	case tree.ForNoKeyUpdate:
+		return ScanLockingStrength_FOR_NO_KEY_UPDATE
+	case tree.ForUpdate:
+		return ScanLockingStrength_FOR_UPDATE
+	default:
+		panic(fmt.Sprintf("unknown locking strength %s", s))
+	}
+}
+
+// ToScanLockingWaitPolicy converts a tree.LockingWaitPolicy to its
+// corresponding ScanLockingWaitPolicy.
+func ToScanLockingWaitPolicy(wp tree.LockingWaitPolicy) ScanLockingWaitPolicy {
+	switch wp {
+	case tree.LockWaitBlock:
+		return ScanLockingWaitPolicy_BLOCK
+	case tree.LockWaitSkip:
+		return ScanLockingWaitPolicy_SKIP
+	case tree.LockWaitError:
+		return ScanLockingWaitPolicy_ERROR
+	default:
+		panic(fmt.Sprintf("unknown locking wait policy %s", wp))
+	}
+}
+
+// KeyLockingStrength returns the strength of the unreplicated locks that KV
+// reads acquire for the locking strength. The key-level locks don't
+// distinguish between the weaker and stronger variants of shared and
+// exclusive row-level locks.
+func (s ScanLockingStrength) KeyLockingStrength() roachpb.KeyLockingStrength {
+	switch s {
+	case ScanLockingStrength_FOR_NONE:
+		return roachpb.NON_LOCKING
+	case ScanLockingStrength_FOR_KEY_SHARE, ScanLockingStrength_FOR_SHARE:
+		return roachpb.SHARED
+	case ScanLockingStrength_FOR_NO_KEY_UPDATE, ScanLockingStrength_FOR_UPDATE:
+		return roachpb.EXCLUSIVE
+	default:
+		panic(fmt.Sprintf("unknown locking strength %s", s))
+	}
+}
+
+// KeyLockingWaitPolicy returns the wait policy that KV reads use for the
+// locking wait policy.
+func (wp ScanLockingWaitPolicy) KeyLockingWaitPolicy() roachpb.KeyLockingWaitPolicy {
+	switch wp {
+	case ScanLockingWaitPolicy_BLOCK:
+		return roachpb.LOCK_WAIT_BLOCK
+	case ScanLockingWaitPolicy_SKIP:
+		return roachpb.LOCK_WAIT_SKIP
+	case ScanLockingWaitPolicy_ERROR:
+		return roachpb.LOCK_WAIT_ERROR
+	default:
+		panic(fmt.Sprintf("unknown locking wait policy %s", wp))
+	}
+}
@@ -97,6 +97,7 @@ func newColBatchScan(
 	fetcher := row.CFetcher{}
 	if _, _, err := initCRowFetcher(
 		&fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
+		spec.LockingStrength, spec.LockingWaitPolicy,
 		neededColumns, spec.IsCheck, spec.Visibility,
 	); err != nil {
 		return nil, err
@@ -122,6 +123,8 @@ func initCRowFetcher(
 	indexIdx int,
 	colIdxMap map[sqlbase.ColumnID]int,
 	reverseScan bool,
+	lockStr distsqlpb.ScanLockingStrength,
+	lockWaitPolicy distsqlpb.ScanLockingWaitPolicy,
 	valNeededForCol util.FastIntSet,
 	isCheck bool,
 	scanVisibility distsqlpb.ScanVisibility,
@@ -145,7 +148,12 @@ func initCRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := fetcher.Init(
-		reverseScan, true /* returnRangeInfo */, isCheck, tableArgs,
+		reverseScan,
+		lockStr.KeyLockingStrength(),
+		lockWaitPolicy.KeyLockingWaitPolicy(),
+		true, /* returnRangeInfo */
+		isCheck,
+		tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
@@ -123,8 +123,8 @@ func newIndexSkipTableReader(
 		ValNeededForCol:  neededColumns,
 	}
 
-	if err := t.fetcher.Init(t.reverse, true, /* returnRangeInfo */
-		false /* isCheck */, &t.alloc, tableArgs); err != nil {
+	if err := t.fetcher.Init(t.reverse, roachpb.NON_LOCKING, roachpb.LOCK_WAIT_BLOCK,
+		true /* returnRangeInfo */, false /* isCheck */, &t.alloc, tableArgs); err != nil {
 		return nil, err
 	}
 
@@ -104,6 +104,8 @@ func newIndexJoiner(
 		0, /* primary index */
 		ij.desc.ColumnIdxMapWithMutations(needMutations),
 		false, /* reverse */
+		distsqlpb.ScanLockingStrength_FOR_NONE,
+		distsqlpb.ScanLockingWaitPolicy_BLOCK,
 		ij.out.neededColumns(),
 		false, /* isCheck */
 		&ij.alloc,
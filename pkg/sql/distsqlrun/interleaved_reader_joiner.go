@@ -416,8 +416,8 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 		}
 	}
 
-	return irj.fetcher.Init(reverseScan, true /* returnRangeInfo */, true /* isCheck */, alloc,
-		args...)
+	return irj.fetcher.Init(reverseScan, roachpb.NON_LOCKING, roachpb.LOCK_WAIT_BLOCK,
+		true /* returnRangeInfo */, true /* isCheck */, alloc, args...)
 }
 
 func (irj *interleavedReaderJoiner) generateTrailingMeta(
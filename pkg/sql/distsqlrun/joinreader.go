@@ -193,6 +193,7 @@ func newJoinReader(
 	var fetcher row.Fetcher
 	_, _, err = initRowFetcher(
 		&fetcher, &jr.desc, int(spec.IndexIdx), jr.colIdxMap, false, /* reverse */
+		distsqlpb.ScanLockingStrength_FOR_NONE, distsqlpb.ScanLockingWaitPolicy_BLOCK,
 		neededRightCols, false /* isCheck */, &jr.alloc, spec.Visibility,
 	)
 	if err != nil {
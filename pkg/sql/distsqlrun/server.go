@@ -95,7 +95,10 @@ var settingUseTempStorageJoins = settings.RegisterBoolSetting(
 	true,
 )
 
-var settingWorkMemBytes = settings.RegisterByteSizeSetting(
+// SettingWorkMemBytes is the cluster setting that determines the limit on the
+// amount of memory used by a processor (or a local planNode) before falling
+// back to temp storage.
+var SettingWorkMemBytes = settings.RegisterByteSizeSetting(
 	"sql.distsql.temp_storage.workmem",
 	"maximum amount of memory in bytes a processor can use before falling back to temp storage",
 	64*1024*1024, /* 64MB */
@@ -65,7 +65,7 @@ func (s *sorterBase) init(
 		// The processor will overflow to disk if this limit is not enough.
 		limit := flowCtx.testingKnobs.MemoryLimitBytes
 		if limit <= 0 {
-			limit = settingWorkMemBytes.Get(&flowCtx.Settings.SV)
+			limit = SettingWorkMemBytes.Get(&flowCtx.Settings.SV)
 		}
 		limitedMon := mon.MakeMonitorInheritWithLimit(
 			"sortall-limited", limit, flowCtx.EvalCtx.Mon,
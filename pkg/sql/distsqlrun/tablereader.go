@@ -121,6 +121,7 @@ func newTableReader(
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
 	if _, _, err := initRowFetcher(
 		&fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
+		spec.LockingStrength, spec.LockingWaitPolicy,
 		neededColumns, spec.IsCheck, &tr.alloc, spec.Visibility,
 	); err != nil {
 		return nil, err
@@ -152,6 +153,8 @@ func initRowFetcher(
 	indexIdx int,
 	colIdxMap map[sqlbase.ColumnID]int,
 	reverseScan bool,
+	lockStr distsqlpb.ScanLockingStrength,
+	lockWaitPolicy distsqlpb.ScanLockingWaitPolicy,
 	valNeededForCol util.FastIntSet,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
@@ -176,7 +179,13 @@ func initRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := fetcher.Init(
-		reverseScan, true /* returnRangeInfo */, isCheck, alloc, tableArgs,
+		reverseScan,
+		lockStr.KeyLockingStrength(),
+		lockWaitPolicy.KeyLockingWaitPolicy(),
+		true, /* returnRangeInfo */
+		isCheck,
+		alloc,
+		tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
@@ -437,6 +437,8 @@ func (z *zigzagJoiner) setupInfo(spec *distsqlpb.ZigzagJoinerSpec, side int, col
 		int(info.index.ID)-1,
 		info.table.ColumnIdxMap(),
 		false, /* reverse */
+		distsqlpb.ScanLockingStrength_FOR_NONE,
+		distsqlpb.ScanLockingWaitPolicy_BLOCK,
 		neededCols,
 		false, /* check */
 		info.alloc,
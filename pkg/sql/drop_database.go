@@ -29,6 +29,13 @@ type dropDatabaseNode struct {
 	n      *tree.DropDatabase
 	dbDesc *sqlbase.DatabaseDescriptor
 	td     []toDelete
+
+	// tempSchemaNames are the names of the temporary schemas in the database.
+	tempSchemaNames []string
+	// typeDescs and funcDescs are the user-defined types and functions of the
+	// database, which are dropped along with it.
+	typeDescs []*sqlbase.TypeDescriptor
+	funcDescs []*sqlbase.FunctionDescriptor
 }
 
 // DropDatabase drops a database.
@@ -67,7 +74,36 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	if len(tbNames) > 0 {
+	// The temporary tables of all the sessions are dropped along with the
+	// database.
+	tempSchemaNames, err := getTemporarySchemaNames(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, scName := range tempSchemaNames {
+		tempTbNames, err := GetObjectNames(ctx, p.txn, p, dbDesc, scName, true /*explicitPrefix*/)
+		if err != nil {
+			return nil, err
+		}
+		tbNames = append(tbNames, tempTbNames...)
+	}
+
+	descs, err := getDescriptorsInNamespace(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+	var typeDescs []*sqlbase.TypeDescriptor
+	var funcDescs []*sqlbase.FunctionDescriptor
+	for _, desc := range descs {
+		if typeDesc := desc.GetType(); typeDesc != nil {
+			typeDescs = append(typeDescs, typeDesc)
+		}
+		if funcDesc := desc.GetFunction(); funcDesc != nil {
+			funcDescs = append(funcDescs, funcDesc)
+		}
+	}
+
+	if len(tbNames) > 0 || len(typeDescs) > 0 || len(funcDescs) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
@@ -84,6 +120,7 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 	}
 
 	td := make([]toDelete, 0, len(tbNames))
+	dbTableIDs := make(map[sqlbase.ID]struct{}, len(tbNames))
 	for i := range tbNames {
 		tbDesc, err := p.prepareDrop(ctx, &tbNames[i], false /*required*/, ResolveAnyDescType)
 		if err != nil {
@@ -100,6 +137,37 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 			}
 		}
 		td = append(td, toDelete{&tbNames[i], tbDesc})
+		dbTableIDs[tbDesc.ID] = struct{}{}
+	}
+
+	// The types of the database can only be dropped if the tables using them
+	// are dropped too.
+	for _, typeDesc := range typeDescs {
+		if err := p.CheckPrivilege(ctx, typeDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		for _, id := range typeDesc.ReferencingDescriptorIDs {
+			if _, ok := dbTableIDs[id]; ok {
+				continue
+			}
+			tableDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, id)
+			if err == sqlbase.ErrDescriptorNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			if tableDesc.Dropped() {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"cannot drop type %q because table %q depends on it", typeDesc.Name, tableDesc.Name)
+		}
+	}
+
+	for _, funcDesc := range funcDescs {
+		if err := p.CheckPrivilege(ctx, funcDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
 	}
 
 	td, err = p.filterCascadedTables(ctx, td)
@@ -107,7 +175,14 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td}, nil
+	return &dropDatabaseNode{
+		n:               n,
+		dbDesc:          dbDesc,
+		td:              td,
+		tempSchemaNames: tempSchemaNames,
+		typeDescs:       typeDescs,
+		funcDescs:       funcDescs,
+	}, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
@@ -168,6 +243,37 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 	b.Del(descKey)
 	b.Del(nameKey)
+	for _, scName := range n.tempSchemaNames {
+		schemaKey := sqlbase.NewSchemaKey(n.dbDesc.ID, scName).Key()
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", schemaKey)
+		}
+		b.Del(schemaKey)
+	}
+	for _, typeDesc := range n.typeDescs {
+		typeNameKey := sqlbase.NewTableKey(typeDesc.ParentID, typeDesc.Name).Key()
+		typeDescKey := sqlbase.MakeDescMetadataKey(typeDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", typeDescKey)
+			log.VEventf(ctx, 2, "Del %s", typeNameKey)
+		}
+		b.Del(typeDescKey)
+		b.Del(typeNameKey)
+		typeName := tree.MakeTableName(tree.Name(n.dbDesc.Name), tree.Name(typeDesc.Name))
+		tbNameStrings = append(tbNameStrings, typeName.FQString())
+	}
+	for _, funcDesc := range n.funcDescs {
+		funcNameKey := sqlbase.NewTableKey(funcDesc.ParentID, funcDesc.Name).Key()
+		funcDescKey := sqlbase.MakeDescMetadataKey(funcDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", funcDescKey)
+			log.VEventf(ctx, 2, "Del %s", funcNameKey)
+		}
+		b.Del(funcDescKey)
+		b.Del(funcNameKey)
+		funcName := tree.MakeTableName(tree.Name(n.dbDesc.Name), tree.Name(funcDesc.Name))
+		tbNameStrings = append(tbNameStrings, funcName.FQString())
+	}
 
 	// No job was created because no tables were dropped, so zone config can be
 	// immediately removed.
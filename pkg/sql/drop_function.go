@@ -0,0 +1,121 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropFunctionNode struct {
+	n        *tree.DropFunction
+	funcDesc *sqlbase.FunctionDescriptor
+}
+
+// DropFunction drops a user-defined function.
+// Privileges: DROP on function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	funcName := n.Name.Table()
+	funcDesc, err := getFunctionDescByName(ctx, p.txn, dbDesc.ID, funcName)
+	if err != nil {
+		return nil, err
+	}
+	if funcDesc == nil {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedFunction, "function %q does not exist", funcName)
+	}
+
+	// If parameter types were given, they must match the signature of the
+	// function.
+	if n.ParamTypes != nil {
+		match := len(n.ParamTypes) == len(funcDesc.Params)
+		for i := 0; match && i < len(n.ParamTypes); i++ {
+			typ, err := tree.ResolveType(n.ParamTypes[i], &p.semaCtx)
+			if err != nil {
+				return nil, err
+			}
+			match = typ.Equivalent(&funcDesc.Params[i].Type)
+		}
+		if !match {
+			if n.IfExists {
+				return newZeroNode(nil /* columns */), nil
+			}
+			typeNames := make([]string, len(n.ParamTypes))
+			for i, typ := range n.ParamTypes {
+				typeNames[i] = typ.SQLString()
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedFunction,
+				"function %s(%s) does not exist", funcName, strings.Join(typeNames, ", "))
+		}
+	}
+
+	if err := p.CheckPrivilege(ctx, funcDesc, privilege.DROP); err != nil {
+		return nil, err
+	}
+
+	return &dropFunctionNode{
+		n:        n,
+		funcDesc: funcDesc,
+	}, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	nameKey := sqlbase.NewTableKey(n.funcDesc.ParentID, n.funcDesc.Name).Key()
+	descKey := sqlbase.MakeDescMetadataKey(n.funcDesc.ID)
+
+	b := &client.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", descKey)
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+	}
+	b.Del(descKey)
+	b.Del(nameKey)
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// Log Drop Function event. This is an auditable log event and is recorded
+	// in the same transaction as the function descriptor deletion.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogDropFunction,
+		int32(n.funcDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
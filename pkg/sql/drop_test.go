@@ -772,9 +772,20 @@ func TestDropTable(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// A lot of garbage has been left behind to be cleaned up by the
-	// asynchronous path.
-	tests.CheckKeyCount(t, kvDB, tableSpan, 3*numRows)
+	// The data of the dropped table is deleted using range tombstones while
+	// waiting for the GC TTL, and the descriptor records that this happened
+	// so that the tombstones are only written once.
+	testutils.SucceedsSoon(t, func() error {
+		desc := &sqlbase.Descriptor{}
+		if err := kvDB.GetProto(ctx, sqlbase.MakeDescMetadataKey(tableDesc.ID), desc); err != nil {
+			return err
+		}
+		if !desc.GetTable().RangeTombstonesWritten {
+			return errors.New("range tombstones not yet written")
+		}
+		return nil
+	})
+	tests.CheckKeyCount(t, kvDB, tableSpan, 0)
 
 	if err := descExists(sqlDB, true, tableDesc.ID); err != nil {
 		t.Fatal(err)
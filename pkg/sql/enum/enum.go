@@ -0,0 +1,103 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package enum contains the logic used to generate the physical
+// representations of the members of user-defined enum types.
+//
+// The physical representation of an enum member is a byte string. Values of
+// an enum type are encoded, in both keys and values, using the physical
+// representation of their member, so the physical representations must sort
+// in the same order as the members were declared. Since members can be added
+// in between existing members by ALTER TYPE ... ADD VALUE, and existing
+// physical representations can never change once data has been written, new
+// physical representations are generated to fall between existing ones.
+//
+// To guarantee that there is always room between any two physical
+// representations, a physical representation never ends with a zero byte.
+package enum
+
+import "github.com/cockroachdb/errors"
+
+const (
+	// minToken is the smallest byte of a physical representation.
+	minToken int = 0
+	// maxToken is one more than the largest byte of a physical representation.
+	maxToken int = 256
+)
+
+// GenByteStringBetween generates a byte string that sorts strictly between
+// prev and next. An empty prev is treated as the smallest possible byte
+// string, and an empty next is treated as the largest possible byte string.
+// prev must sort strictly before next, and neither may end with a zero byte.
+func GenByteStringBetween(prev []byte, next []byte) []byte {
+	if len(prev) > 0 && len(next) > 0 && string(prev) >= string(next) {
+		panic(errors.AssertionFailedf("%x is not less than %x", prev, next))
+	}
+	result := make([]byte, 0, len(prev)+1)
+	// nextBounded is true as long as result is a prefix of next, in which case
+	// the bytes of next bound the next byte of result.
+	nextBounded := len(next) > 0
+	for i := 0; ; i++ {
+		lo := minToken
+		if i < len(prev) {
+			lo = int(prev[i])
+		}
+		hi := maxToken
+		if nextBounded && i < len(next) {
+			hi = int(next[i])
+		}
+		if hi-lo > 1 {
+			// There is room between the bounds: use the midpoint, which is never
+			// zero since it is strictly greater than lo.
+			return append(result, byte((lo+hi)/2))
+		}
+		// There is no room at this position, so copy the lower bound and look
+		// for room in the following positions. If the lower bound is strictly
+		// less than the upper bound, result now sorts before next regardless of
+		// the bytes that follow.
+		result = append(result, byte(lo))
+		if lo < hi {
+			nextBounded = false
+		}
+	}
+}
+
+// GenerateNEvenlySpacedBytes returns n physical representations, in
+// increasing order, that are spread evenly over the space of byte strings so
+// that new representations can be added between any two of them.
+func GenerateNEvenlySpacedBytes(n int) [][]byte {
+	if n == 0 {
+		return nil
+	}
+	// Find the smallest number of bytes in which n+1 evenly spaced values fit.
+	numBytes := 1
+	space := uint64(maxToken)
+	for space <= uint64(n) {
+		numBytes++
+		space *= uint64(maxToken)
+	}
+	step := space / uint64(n+1)
+	result := make([][]byte, n)
+	for i := range result {
+		val := step * uint64(i+1)
+		rep := make([]byte, numBytes)
+		for j := numBytes - 1; j >= 0; j-- {
+			rep[j] = byte(val % uint64(maxToken))
+			val /= uint64(maxToken)
+		}
+		// Trailing zero bytes can be trimmed without changing the order of the
+		// representations, and must be in order to leave room after them.
+		for len(rep) > 0 && rep[len(rep)-1] == 0 {
+			rep = rep[:len(rep)-1]
+		}
+		result[i] = rep
+	}
+	return result
+}
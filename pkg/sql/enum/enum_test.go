@@ -0,0 +1,91 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package enum
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func checkRep(t *testing.T, rep []byte) {
+	t.Helper()
+	if len(rep) == 0 || rep[len(rep)-1] == 0 {
+		t.Fatalf("invalid physical representation %x", rep)
+	}
+}
+
+func TestGenByteStringBetween(t *testing.T) {
+	testCases := []struct {
+		prev, next []byte
+	}{
+		{nil, nil},
+		{nil, []byte{1}},
+		{nil, []byte{0, 1}},
+		{[]byte{255}, nil},
+		{[]byte{255, 255}, nil},
+		{[]byte{1}, []byte{2}},
+		{[]byte{1}, []byte{1, 1}},
+		{[]byte{1, 255}, []byte{2}},
+		{[]byte{1, 255, 255}, []byte{2, 0, 1}},
+		{[]byte{127}, []byte{128}},
+	}
+	for _, tc := range testCases {
+		res := GenByteStringBetween(tc.prev, tc.next)
+		checkRep(t, res)
+		if len(tc.prev) > 0 && bytes.Compare(tc.prev, res) >= 0 {
+			t.Errorf("%x: expected result greater than %x", res, tc.prev)
+		}
+		if len(tc.next) > 0 && bytes.Compare(res, tc.next) >= 0 {
+			t.Errorf("%x: expected result less than %x", res, tc.next)
+		}
+	}
+}
+
+func TestGenByteStringBetweenRandomInsertions(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	reps := [][]byte{GenByteStringBetween(nil, nil)}
+	for i := 0; i < 1000; i++ {
+		pos := rng.Intn(len(reps) + 1)
+		var prev, next []byte
+		if pos > 0 {
+			prev = reps[pos-1]
+		}
+		if pos < len(reps) {
+			next = reps[pos]
+		}
+		res := GenByteStringBetween(prev, next)
+		checkRep(t, res)
+		reps = append(reps, nil)
+		copy(reps[pos+1:], reps[pos:])
+		reps[pos] = res
+	}
+	for i := 1; i < len(reps); i++ {
+		if bytes.Compare(reps[i-1], reps[i]) >= 0 {
+			t.Fatalf("representations out of order: %x >= %x", reps[i-1], reps[i])
+		}
+	}
+}
+
+func TestGenerateNEvenlySpacedBytes(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 255, 256, 1000} {
+		reps := GenerateNEvenlySpacedBytes(n)
+		if len(reps) != n {
+			t.Fatalf("expected %d representations, found %d", n, len(reps))
+		}
+		for i := range reps {
+			checkRep(t, reps[i])
+			if i > 0 && bytes.Compare(reps[i-1], reps[i]) >= 0 {
+				t.Fatalf("representations out of order: %x >= %x", reps[i-1], reps[i])
+			}
+		}
+	}
+}
@@ -13,6 +13,8 @@ package sql
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"net/url"
@@ -49,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -485,6 +488,10 @@ type ExecutorConfig struct {
 	InternalExecutor  *InternalExecutor
 	QueryCache        *querycache.C
 
+	// ProtectedTimestampProvider is used by jobs to protect the data they need
+	// to read from being garbage collected.
+	ProtectedTimestampProvider protectedts.Provider
+
 	TestingKnobs              ExecutorTestingKnobs
 	PGWireTestingKnobs        *PGWireTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
@@ -818,6 +825,20 @@ func (p *planner) EvalAsOfTimestamp(asOf tree.AsOfClause) (_ hlc.Timestamp, err
 	return ts, nil
 }
 
+// evalAsOf is like EvalAsOfTimestamp, but also accepts the bounded staleness
+// reads which single-statement SELECT queries may perform.
+func (p *planner) evalAsOf(asOf tree.AsOfClause) (tree.AsOfSystemTime, error) {
+	res, err := tree.EvalAsOf(asOf, &p.semaCtx, p.EvalContext())
+	if err != nil {
+		return tree.AsOfSystemTime{}, err
+	}
+	if now := p.execCfg.Clock.Now(); now.Less(res.Timestamp) {
+		return tree.AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", res.Timestamp, now)
+	}
+	return res, nil
+}
+
 // ParseHLC parses a string representation of an `hlc.Timestamp`.
 func ParseHLC(s string) (hlc.Timestamp, error) {
 	dec, _, err := apd.NewFromString(s)
@@ -829,10 +850,12 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 
 // isAsOf analyzes a statement to bypass the logic in newPlan(), since
 // that requires the transaction to be started already. If the returned
-// timestamp is not nil, it is the timestamp to which a transaction
-// should be set. The statements that will be checked are Select,
-// ShowTrace (of a Select statement), Scrub, Export, and CreateStats.
-func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
+// value is not nil, it holds the timestamp to which a transaction
+// should be set, or, for the bounded staleness reads which only Select
+// statements may perform, the minimum timestamp bound of the read. The
+// statements that will be checked are Select, ShowTrace (of a Select
+// statement), Scrub, Export, and CreateStats.
+func (p *planner) isAsOf(stmt tree.Statement) (*tree.AsOfSystemTime, error) {
 	var asOf tree.AsOfClause
 	switch s := stmt.(type) {
 	case *tree.Select:
@@ -851,14 +874,20 @@ func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
 			return nil, nil
 		}
 
-		asOf = sc.From.AsOf
+		res, err := p.evalAsOf(sc.From.AsOf)
+		return &res, err
 	case *tree.Scrub:
 		if s.AsOf.Expr == nil {
 			return nil, nil
 		}
 		asOf = s.AsOf
 	case *tree.Export:
-		return p.isAsOf(s.Query)
+		res, err := p.isAsOf(s.Query)
+		if err == nil && res != nil && res.BoundedStaleness {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"AS OF SYSTEM TIME: EXPORT does not support bounded staleness reads")
+		}
+		return res, err
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
 			return nil, nil
@@ -868,7 +897,7 @@ func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
 		return nil, nil
 	}
 	ts, err := p.EvalAsOfTimestamp(asOf)
-	return &ts, err
+	return &tree.AsOfSystemTime{Timestamp: ts}, err
 }
 
 // isSavepoint returns true if stmt is a SAVEPOINT statement.
@@ -947,34 +976,84 @@ type SessionArgs struct {
 // specified or left empty.
 func (s SessionArgs) isDefined() bool { return len(s.User) != 0 }
 
+// CancelKey is handed to a pgwire client in the BackendKeyData message when
+// its session starts. The client can later present it in an out-of-band
+// CancelRequest to cancel the queries running on that session. The key
+// carries the ID of the session's gateway node so that the CancelRequest can
+// be routed there from whichever node receives it.
+type CancelKey struct {
+	NodeID roachpb.NodeID
+	// Secret identifies the session among the sessions of its gateway node.
+	// Zero means that the session cannot be canceled through a cancel key.
+	Secret uint32
+}
+
+// generateCancelSecret returns a random, non-zero secret for a CancelKey. The
+// secret is the only thing authorizing a CancelRequest, so it must not be
+// predictable.
+func generateCancelSecret() (uint32, error) {
+	var buf [4]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return 0, err
+		}
+		if secret := binary.BigEndian.Uint32(buf[:]); secret != 0 {
+			return secret, nil
+		}
+	}
+}
+
 // SessionRegistry stores a set of all sessions on this node.
 // Use register() and deregister() to modify this registry.
 type SessionRegistry struct {
 	syncutil.Mutex
 	sessions map[ClusterWideID]registrySession
+	// cancelSecrets maps the secrets of the sessions' cancel keys to the
+	// sessions' IDs. In the unlikely event that two sessions get the same
+	// secret, only the first one can be canceled through its cancel key.
+	cancelSecrets map[uint32]ClusterWideID
 }
 
 // NewSessionRegistry creates a new SessionRegistry with an empty set
 // of sessions.
 func NewSessionRegistry() *SessionRegistry {
-	return &SessionRegistry{sessions: make(map[ClusterWideID]registrySession)}
+	return &SessionRegistry{
+		sessions:      make(map[ClusterWideID]registrySession),
+		cancelSecrets: make(map[uint32]ClusterWideID),
+	}
 }
 
 func (r *SessionRegistry) register(id ClusterWideID, s registrySession) {
 	r.Lock()
 	r.sessions[id] = s
+	if secret := s.cancelSecret(); secret != 0 {
+		if _, ok := r.cancelSecrets[secret]; !ok {
+			r.cancelSecrets[secret] = id
+		}
+	}
 	r.Unlock()
 }
 
 func (r *SessionRegistry) deregister(id ClusterWideID) {
 	r.Lock()
+	if s, ok := r.sessions[id]; ok {
+		if secret := s.cancelSecret(); secret != 0 && r.cancelSecrets[secret] == id {
+			delete(r.cancelSecrets, secret)
+		}
+	}
 	delete(r.sessions, id)
 	r.Unlock()
 }
 
 type registrySession interface {
 	user() string
+	// cancelSecret returns the secret of the session's cancel key, or zero if
+	// the session has none.
+	cancelSecret() uint32
 	cancelQuery(queryID ClusterWideID) bool
+	// cancelCurrentQueries cancels all the queries running on the session. It
+	// returns false if there were none.
+	cancelCurrentQueries() bool
 	cancelSession()
 	// serialize serializes a Session into a serverpb.Session
 	// that can be served over RPC.
@@ -1005,6 +1084,23 @@ func (r *SessionRegistry) CancelQuery(queryIDStr string, username string) (bool,
 	return false, fmt.Errorf("query ID %s not found", queryID)
 }
 
+// CancelQueryByKey looks up the session that was assigned the cancel key with
+// the given secret and cancels the queries running on it. No username is
+// needed; knowledge of the secret authorizes the cancellation.
+func (r *SessionRegistry) CancelQueryByKey(secret uint32) (bool, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	id, ok := r.cancelSecrets[secret]
+	if !ok {
+		return false, fmt.Errorf("no session found for cancel key")
+	}
+	if !r.sessions[id].cancelCurrentQueries() {
+		return false, fmt.Errorf("session %s has no active query", id)
+	}
+	return true, nil
+}
+
 // CancelSession looks up the specified session in the session registry and cancels it.
 func (r *SessionRegistry) CancelSession(sessionIDBytes []byte, username string) (bool, error) {
 	sessionID := BytesToClusterWideID(sessionIDBytes)
@@ -1820,7 +1916,14 @@ func (m *sessionDataMutator) SetSafeUpdates(val bool) {
 }
 
 func (m *sessionDataMutator) SetSearchPath(val sessiondata.SearchPath) {
-	m.data.SearchPath = val
+	// The temporary schema of the session outlives changes to the search path.
+	m.data.SearchPath = val.WithTemporarySchemaName(m.data.SearchPath.GetTemporarySchemaName())
+}
+
+// SetTemporarySchemaName records the name of the temporary schema of the
+// session, which is implicitly searched first by the search path.
+func (m *sessionDataMutator) SetTemporarySchemaName(scName string) {
+	m.data.SearchPath = m.data.SearchPath.WithTemporarySchemaName(scName)
 }
 
 func (m *sessionDataMutator) SetLocation(loc *time.Location) {
@@ -353,6 +353,7 @@ func doExpandPlan(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
@@ -367,13 +368,18 @@ func doExpandPlan(
 	case *createIndexNode:
 	case *CreateUserNode:
 	case *createViewNode:
+	case *createFunctionNode:
 	case *createSequenceNode:
 	case *createStatsNode:
+	case *createTriggerNode:
+	case *createTypeNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropFunctionNode:
 	case *dropSequenceNode:
+	case *dropTriggerNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -876,6 +882,7 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
@@ -890,13 +897,18 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *createIndexNode:
 	case *CreateUserNode:
 	case *createViewNode:
+	case *createFunctionNode:
 	case *createSequenceNode:
 	case *createStatsNode:
+	case *createTriggerNode:
+	case *createTypeNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropFunctionNode:
 	case *dropSequenceNode:
+	case *dropTriggerNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
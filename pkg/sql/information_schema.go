@@ -1212,6 +1212,12 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.Name)
 
 				for conName, c := range conInfo {
+					deferrable, initiallyDeferred := false, false
+					if c.FK != nil {
+						deferrable, initiallyDeferred = c.FK.Deferrable, c.FK.InitiallyDeferred
+					} else if c.Kind == sqlbase.ConstraintTypeUnique {
+						deferrable, initiallyDeferred = c.Index.DeferrableUnique, c.Index.InitiallyDeferred
+					}
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1220,8 +1226,8 @@ CREATE TABLE information_schema.table_constraints (
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(deferrable),        // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1413,6 +1419,16 @@ func forEachSchemaName(
 	for _, schema := range p.getVirtualTabler().getEntries() {
 		scNames = append(scNames, schema.desc.Name)
 	}
+	// Handle the temporary schema of the session, if it exists in the database.
+	if tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName(); tempSchemaName != "" {
+		schemaID, err := getTemporarySchemaID(ctx, p.txn, db.ID, tempSchemaName)
+		if err != nil {
+			return err
+		}
+		if schemaID != sqlbase.InvalidID {
+			scNames = append(scNames, tempSchemaName)
+		}
+	}
 	sort.Strings(scNames)
 	for _, sc := range scNames {
 		if err := fn(sc); err != nil {
@@ -1454,6 +1470,44 @@ func forEachDatabaseDesc(
 	return nil
 }
 
+// forEachTypeDesc retrieves all user-defined type descriptors and iterates
+// through them. For each type, the function will call fn with its respective
+// database and type descriptor. Only the types of the databases visible in
+// dbContext are considered.
+func forEachTypeDesc(
+	ctx context.Context,
+	p *planner,
+	dbContext *DatabaseDescriptor,
+	fn func(*sqlbase.DatabaseDescriptor, *sqlbase.TypeDescriptor) error,
+) error {
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+	dbDescs := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
+	for _, desc := range descs {
+		if dbDesc, ok := desc.(*sqlbase.DatabaseDescriptor); ok &&
+			(dbContext == nil || dbContext.ID == dbDesc.ID) &&
+			userCanSeeDatabase(ctx, p, dbDesc) {
+			dbDescs[dbDesc.ID] = dbDesc
+		}
+	}
+	for _, desc := range descs {
+		typDesc, ok := desc.(*sqlbase.TypeDescriptor)
+		if !ok {
+			continue
+		}
+		dbDesc, ok := dbDescs[typDesc.ParentID]
+		if !ok {
+			continue
+		}
+		if err := fn(dbDesc, typDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // forEachTableDesc retrieves all table descriptors from the current
 // database and all system databases and iterates through them. For
 // each table, the function will call fn with its respective database
@@ -1586,14 +1640,35 @@ func forEachTableDescWithTableLookupInternal(
 		}
 	}
 
-	// Physical descriptors next.
+	// Physical descriptors next. Only the temporary tables of the current
+	// session are visible, in the temporary schema of the session.
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	tempSchemaIDs := make(map[sqlbase.ID]sqlbase.ID)
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		dbDesc, parentExists := lCtx.dbDescs[table.GetParentID()]
 		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists {
 			continue
 		}
-		if err := fn(dbDesc, tree.PublicSchema, table, lCtx); err != nil {
+		scName := tree.PublicSchema
+		if table.Temporary {
+			if tempSchemaName == "" {
+				continue
+			}
+			schemaID, ok := tempSchemaIDs[dbDesc.ID]
+			if !ok {
+				schemaID, err = getTemporarySchemaID(ctx, p.txn, dbDesc.ID, tempSchemaName)
+				if err != nil {
+					return err
+				}
+				tempSchemaIDs[dbDesc.ID] = schemaID
+			}
+			if schemaID != table.TemporarySchemaID {
+				continue
+			}
+			scName = tempSchemaName
+		}
+		if err := fn(dbDesc, scName, table, lCtx); err != nil {
 			return err
 		}
 	}
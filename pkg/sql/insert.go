@@ -430,7 +430,13 @@ func (n *insertNode) startExec(params runParams) error {
 		}
 	}
 
-	return n.run.ti.init(params.p.txn, params.EvalContext())
+	if err := n.run.ti.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	n.run.ti.ri.DeferChecks(
+		params.extendedEvalCtx.DeferredChecks, n.run.ti.initStatementChecks(params.EvalContext()),
+	)
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -767,6 +767,84 @@ SELECT EXISTS(SELECT * FROM t.foo);
 	}
 }
 
+// Test that the leases held by a transaction are released when the
+// transaction encounters an error, even though the transaction can still be
+// recovered through ROLLBACK TO SAVEPOINT.
+func TestLeasesReleasedInAbortedTxn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	params, _ := tests.CreateTestServerParams()
+
+	fooRelease := make(chan struct{}, 10)
+	var tableID int64
+
+	params.Knobs = base.TestingKnobs{
+		SQLLeaseManager: &sql.LeaseManagerTestingKnobs{
+			LeaseStoreTestingKnobs: sql.LeaseStoreTestingKnobs{
+				RemoveOnceDereferenced: true,
+				LeaseReleasedEvent: func(id sqlbase.ID, _ sqlbase.DescriptorVersion, _ error) {
+					if int64(id) == atomic.LoadInt64(&tableID) {
+						fooRelease <- struct{}{}
+					}
+				},
+			},
+		},
+	}
+	s, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.foo (v INT);
+`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "foo")
+	atomic.StoreInt64(&tableID, int64(tableDesc.ID))
+
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`SAVEPOINT s`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`SELECT * FROM t.foo`); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-fooRelease:
+		t.Fatal("lease released while the txn is open")
+	default:
+	}
+
+	// Move the txn to the Aborted state. The lease on foo is released.
+	if _, err := tx.Exec(`SELECT 1/0`); !testutils.IsError(err, "division by zero") {
+		t.Fatalf("expected division by zero error, got: %v", err)
+	}
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease was not released when the txn was aborted")
+	case <-fooRelease:
+	}
+
+	// The txn can still be recovered and use the table again.
+	if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT s`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`INSERT INTO t.foo VALUES (1)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := sqlDB.QueryRow(`SELECT count(*) FROM t.foo`).Scan(&count); err != nil {
+		t.Fatal(err)
+	} else if count != 1 {
+		t.Fatalf("expected 1 row, got %d", count)
+	}
+}
+
 // Test that an AS OF SYSTEM TIME query uses the table cache.
 func TestAsOfSystemTimeUsesCache(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -135,6 +135,11 @@ type Table interface {
 
 	// InboundForeignKey returns the ith inbound foreign key reference.
 	InboundForeignKey(i int) ForeignKeyConstraint
+
+	// HasTriggers returns true if any triggers are defined on the table. The
+	// triggers are fired during execution, but the optimizer must avoid plans
+	// that bypass them (such as fast range deletes).
+	HasTriggers() bool
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -237,4 +242,8 @@ type ForeignKeyConstraint interface {
 
 	// MatchMethod returns the method used for comparing composite foreign keys.
 	MatchMethod() tree.CompositeKeyMatchMethod
+
+	// Deferrable is true if the checks of the constraint can be deferred to the
+	// end of the transaction.
+	Deferrable() bool
 }
@@ -50,6 +50,10 @@ type Builder struct {
 	// each relational subexpression when evalCtx.SessionData.SaveTablesPrefix is
 	// non-empty.
 	nameGen *memo.ExprNameGenerator
+
+	// withExprs is the set of With expressions which may be referenced elsewhere
+	// in the query (currently only the working tables of recursive CTEs).
+	withExprs []builtWithExpr
 }
 
 // New constructs an instance of the execution node builder using the
@@ -130,6 +134,35 @@ func (b *Builder) BuildScalar(ivh *tree.IndexedVarHelper) (tree.TypedExpr, error
 	return b.buildScalar(&ctx, scalar)
 }
 
+// builtWithExpr is metadata regarding a With expression which has already been
+// built, and which can be referenced by WithScan expressions.
+type builtWithExpr struct {
+	id opt.WithID
+	// outputCols maps the output ColumnIDs of the With expression to the ordinal
+	// positions they are output to. See execPlan.outputCols for more details.
+	outputCols opt.ColMap
+	bufferNode exec.BufferNode
+}
+
+func (b *Builder) addBuiltWithExpr(
+	id opt.WithID, outputCols opt.ColMap, bufferNode exec.BufferNode,
+) {
+	b.withExprs = append(b.withExprs, builtWithExpr{
+		id:         id,
+		outputCols: outputCols,
+		bufferNode: bufferNode,
+	})
+}
+
+func (b *Builder) findBuiltWithExpr(id opt.WithID) *builtWithExpr {
+	for i := range b.withExprs {
+		if b.withExprs[i].id == id {
+			return &b.withExprs[i]
+		}
+	}
+	return nil
+}
+
 func (b *Builder) decorrelationError() error {
 	return errors.Errorf("could not decorrelate subquery")
 }
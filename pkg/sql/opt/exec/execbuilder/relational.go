@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -216,6 +217,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.ProjectSetExpr:
 		ep, err = b.buildProjectSet(t)
 
+	case *memo.RecursiveCTEExpr:
+		ep, err = b.buildRecursiveCTE(t)
+
+	case *memo.WithScanExpr:
+		ep, err = b.buildWithScan(t)
+
 	case *memo.WindowExpr:
 		ep, err = b.buildWindow(t)
 
@@ -426,6 +433,7 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		ordering.ScanIsReverse(scan, &scan.RequiredPhysical().Ordering),
 		b.indexConstraintMaxResults(scan),
 		res.reqOrdering(scan),
+		scan.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1321,6 +1329,96 @@ func (b *Builder) buildMax1Row(max1Row *memo.Max1RowExpr) (execPlan, error) {
 
 }
 
+// buildRecursiveCTE builds the initial query of a recursive CTE right away;
+// the recursive query is built anew for every iteration, and reads the rows
+// produced by the previous iteration (the working table) through a WithScan.
+func (b *Builder) buildRecursiveCTE(rec *memo.RecursiveCTEExpr) (execPlan, error) {
+	initial, err := b.buildRelational(rec.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+	// Make sure we have the columns in the correct order.
+	initial, err = b.ensureColumns(initial, rec.InitialCols, nil /* colNames */, nil /* provided */)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The rows of the working table, and of the result, are laid out in the
+	// order of OutCols.
+	var outputCols opt.ColMap
+	for i, col := range rec.OutCols {
+		outputCols.Set(int(col), i)
+	}
+
+	// To implement exec.RecursiveCTEIterationFn, we create a separate Builder
+	// for each iteration. The withExprs slice is capped so that the inner
+	// builders never append into our backing array.
+	innerBldTemplate := &Builder{
+		factory:          b.factory,
+		mem:              b.mem,
+		catalog:          b.catalog,
+		evalCtx:          b.evalCtx,
+		disableTelemetry: true,
+		withExprs:        b.withExprs[:len(b.withExprs):len(b.withExprs)],
+	}
+	fn := func(bufferRef exec.BufferNode) (_ exec.Plan, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				// See the comment in Build.
+				if ok, e := errorutil.ShouldCatch(r); ok {
+					err = e
+				} else {
+					panic(r)
+				}
+			}
+		}()
+
+		innerBld := *innerBldTemplate
+		innerBld.addBuiltWithExpr(rec.WithID, outputCols, bufferRef)
+		plan, err := innerBld.buildRelational(rec.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure columns are output in the same order as the working table.
+		plan, err = innerBld.ensureColumns(plan, rec.RecursiveCols, nil /* colNames */, nil /* provided */)
+		if err != nil {
+			return nil, err
+		}
+		return innerBld.factory.ConstructPlan(plan.root, innerBld.subqueries, innerBld.postqueries)
+	}
+
+	label := fmt.Sprintf("working buffer (%s)", rec.Name)
+	node, err := b.factory.ConstructRecursiveCTE(initial.root, fn, label)
+	if err != nil {
+		return execPlan{}, err
+	}
+	return execPlan{root: node, outputCols: outputCols}, nil
+}
+
+func (b *Builder) buildWithScan(withScan *memo.WithScanExpr) (execPlan, error) {
+	e := b.findBuiltWithExpr(withScan.With)
+	if e == nil {
+		return execPlan{}, errors.AssertionFailedf(
+			"couldn't find With expression with ID %d", log.Safe(withScan.With),
+		)
+	}
+
+	label := fmt.Sprintf("working buffer (%s)", withScan.Name)
+	node, err := b.factory.ConstructScanBuffer(e.bufferNode, label)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The columns of the With expression are remapped to the (fresh) output
+	// columns of this WithScan.
+	var outputCols opt.ColMap
+	for i := range withScan.InCols {
+		idx, _ := e.outputCols.Get(int(withScan.InCols[i]))
+		outputCols.Set(int(withScan.OutCols[i]), idx)
+	}
+	return execPlan{root: node, outputCols: outputCols}, nil
+}
+
 func (b *Builder) buildProjectSet(projectSet *memo.ProjectSetExpr) (execPlan, error) {
 	input, err := b.buildRelational(projectSet.Input)
 	if err != nil {
@@ -32,6 +32,17 @@ type Node interface{}
 // trees (see ConstructPlan).
 type Plan interface{}
 
+// BufferNode is a node that holds rows which can be read (possibly multiple
+// times) by nodes created with ConstructScanBuffer.
+type BufferNode interface {
+	Node
+}
+
+// RecursiveCTEIterationFn creates a plan for an iteration of WITH RECURSIVE,
+// given the result of the last iteration (as a BufferNode that can be used
+// with ConstructScanBuffer).
+type RecursiveCTEIterationFn func(bufferRef BufferNode) (Plan, error)
+
 // Factory defines the interface for building an execution plan, which consists
 // of a tree of execution nodes (currently a sql.planNode tree).
 //
@@ -54,6 +65,8 @@ type Factory interface {
 	//     the scan.
 	//   - If maxResults > 0, the scan is guaranteed to return at most maxResults
 	//     rows.
+	//   - If locking is provided, the scan should use the specified row-level
+	//     locking mode.
 	ConstructScan(
 		table cat.Table,
 		index cat.Index,
@@ -63,6 +76,7 @@ type Factory interface {
 		reverse bool,
 		maxResults uint64,
 		reqOrdering OutputOrdering,
+		locking *tree.LockingItem,
 	) (Node, error)
 
 	// ConstructVirtualScan returns a node that represents the scan of a virtual
@@ -260,6 +274,19 @@ type Factory interface {
 	// given node.
 	ConstructWindow(input Node, window WindowInfo) (Node, error)
 
+	// ConstructRecursiveCTE returns a node that executes a recursive CTE:
+	//   - the initial plan is run first; its results are emitted and form the
+	//     initial working table.
+	//   - the fn is called to generate a plan for each iteration, which reads
+	//     the working table through ConstructScanBuffer. The results of each
+	//     iteration are emitted and form the next working table.
+	//   - the iterations stop when an iteration produces no rows.
+	ConstructRecursiveCTE(initial Node, fn RecursiveCTEIterationFn, label string) (Node, error)
+
+	// ConstructScanBuffer returns a node that reads the rows held by the given
+	// BufferNode.
+	ConstructScanBuffer(ref BufferNode, label string) (Node, error)
+
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
 
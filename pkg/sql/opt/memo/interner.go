@@ -460,6 +460,10 @@ func (h *hasher) HashValuesID(val opt.ValuesID) {
 	h.HashUint64(uint64(val))
 }
 
+func (h *hasher) HashWithID(val opt.WithID) {
+	h.HashUint64(uint64(val))
+}
+
 func (h *hasher) HashScanLimit(val ScanLimit) {
 	h.HashUint64(uint64(val))
 }
@@ -470,6 +474,13 @@ func (h *hasher) HashScanFlags(val ScanFlags) {
 	h.HashUint64(uint64(val.Index))
 }
 
+func (h *hasher) HashLockingItem(val *tree.LockingItem) {
+	if val != nil {
+		h.HashUint64(uint64(val.Strength))
+		h.HashUint64(uint64(val.WaitPolicy))
+	}
+}
+
 func (h *hasher) HashJoinFlags(val JoinFlags) {
 	h.HashBool(val.DisallowHashJoin)
 	h.HashBool(val.DisallowMergeJoin)
@@ -752,6 +763,10 @@ func (h *hasher) IsValuesIDEqual(l, r opt.ValuesID) bool {
 	return l == r
 }
 
+func (h *hasher) IsWithIDEqual(l, r opt.WithID) bool {
+	return l == r
+}
+
 func (h *hasher) IsScanLimitEqual(l, r ScanLimit) bool {
 	return l == r
 }
@@ -760,6 +775,13 @@ func (h *hasher) IsScanFlagsEqual(l, r ScanFlags) bool {
 	return l == r
 }
 
+func (h *hasher) IsLockingItemEqual(l, r *tree.LockingItem) bool {
+	if l == nil || r == nil {
+		return l == r
+	}
+	return l.Strength == r.Strength && l.WaitPolicy == r.WaitPolicy
+}
+
 func (h *hasher) IsJoinFlagsEqual(l, r JoinFlags) bool {
 	return l == r
 }
@@ -333,6 +333,11 @@ func TestInterner(t *testing.T) {
 			{val1: opt.SchemaID(0), val2: opt.SchemaID(1), equal: false},
 		}},
 
+		{hashFn: in.hasher.HashWithID, eqFn: in.hasher.IsWithIDEqual, variations: []testVariation{
+			{val1: opt.WithID(0), val2: opt.WithID(0), equal: true},
+			{val1: opt.WithID(0), val2: opt.WithID(1), equal: false},
+		}},
+
 		{hashFn: in.hasher.HashScanLimit, eqFn: in.hasher.IsScanLimitEqual, variations: []testVariation{
 			{val1: ScanLimit(100), val2: ScanLimit(100), equal: true},
 			{val1: ScanLimit(0), val2: ScanLimit(1), equal: false},
@@ -345,6 +350,14 @@ func TestInterner(t *testing.T) {
 			{val1: ScanFlags{NoIndexJoin: true, Index: 1}, val2: ScanFlags{NoIndexJoin: false, Index: 1}, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashLockingItem, eqFn: in.hasher.IsLockingItemEqual, variations: []testVariation{
+			{val1: (*tree.LockingItem)(nil), val2: (*tree.LockingItem)(nil), equal: true},
+			{val1: (*tree.LockingItem)(nil), val2: &tree.LockingItem{Strength: tree.ForUpdate}, equal: false},
+			{val1: &tree.LockingItem{Strength: tree.ForUpdate}, val2: &tree.LockingItem{Strength: tree.ForUpdate}, equal: true},
+			{val1: &tree.LockingItem{Strength: tree.ForUpdate}, val2: &tree.LockingItem{Strength: tree.ForShare}, equal: false},
+			{val1: &tree.LockingItem{Strength: tree.ForUpdate}, val2: &tree.LockingItem{Strength: tree.ForUpdate, WaitPolicy: tree.LockWaitError}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashPointer, eqFn: in.hasher.IsPointerEqual, variations: []testVariation{
 			{val1: unsafe.Pointer((*tree.Subquery)(nil)), val2: unsafe.Pointer((*tree.Subquery)(nil)), equal: true},
 			{val1: unsafe.Pointer(&tree.Subquery{}), val2: unsafe.Pointer(&tree.Subquery{}), equal: false},
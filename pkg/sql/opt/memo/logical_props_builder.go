@@ -1053,6 +1053,45 @@ func (b *logicalPropsBuilder) buildProjectSetProps(
 	}
 }
 
+func (b *logicalPropsBuilder) buildRecursiveCTEProps(
+	rec *RecursiveCTEExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, rec, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	rel.OutputCols = rec.OutCols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// No known FDs.
+
+	// Cardinality
+	// -----------
+	// At least the cardinality of the initial query.
+	rel.Cardinality = props.AnyCardinality.AtLeast(rec.Initial.Relational().Cardinality)
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildUnknown(rel)
+	}
+}
+
+func (b *logicalPropsBuilder) buildWithScanProps(withScan *WithScanExpr, rel *props.Relational) {
+	// The working table changes on every iteration of the recursive CTE, so
+	// nothing is known about it.
+	b.buildBasicProps(withScan, withScan.OutCols, rel)
+}
+
 func (b *logicalPropsBuilder) buildInsertProps(ins *InsertExpr, rel *props.Relational) {
 	b.buildMutationProps(ins, rel)
 }
@@ -1235,6 +1274,12 @@ func BuildSharedProps(mem *Memo, e opt.Expr, shared *props.Shared) {
 			shared.CanHaveSideEffects = true
 		}
 
+	case *UDFExpr:
+		if t.Impure {
+			// Volatile functions can return different value on each call.
+			shared.CanHaveSideEffects = true
+		}
+
 	default:
 		if opt.IsMutationOp(e) {
 			shared.CanHaveSideEffects = true
@@ -1277,6 +1322,12 @@ func BuildSharedProps(mem *Memo, e opt.Expr, shared *props.Shared) {
 			BuildSharedProps(mem, e.Child(i), shared)
 		}
 	}
+
+	if udf, ok := e.(*UDFExpr); ok {
+		// The parameter columns referenced by the body of a user-defined
+		// function are bound by the function itself.
+		shared.OuterCols.DifferenceWith(udf.Params.ToSet())
+	}
 }
 
 // hasOuterCols returns true if the given expression has outer columns (i.e.
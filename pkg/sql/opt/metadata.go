@@ -80,6 +80,9 @@ type Metadata struct {
 	// values is the highest id for a Values clause that has been assigned.
 	values ValuesID
 
+	// withs is the highest id for a With binding that has been assigned.
+	withs WithID
+
 	// deps stores information about all data source objects depended on by the
 	// query, as well as the privileges required to access them. The objects are
 	// deduplicated: any name/object pair shows up at most once.
@@ -150,6 +153,7 @@ func (md *Metadata) CopyFrom(from *Metadata) {
 
 	md.sequences = append(md.sequences, from.sequences...)
 	md.deps = append(md.deps, from.deps...)
+	md.withs = from.withs
 }
 
 // AddDependency tracks one of the catalog data sources on which the query
@@ -431,6 +435,20 @@ func (md *Metadata) NextValuesID() ValuesID {
 	return md.values
 }
 
+// WithID uniquely identifies a With binding within the scope of a query, such
+// as the working table of a recursive CTE. Expressions that refer to the
+// binding (see WithScan) use this id to identify it.
+//
+// See the comment for Metadata for more details on identifiers.
+type WithID uint64
+
+// NextWithID returns a fresh WithID which is guaranteed to never have been
+// allocated prior in this memo.
+func (md *Metadata) NextWithID() WithID {
+	md.withs++
+	return md.withs
+}
+
 // AddView adds a new reference to a view used by the query.
 func (md *Metadata) AddView(v cat.View) {
 	md.views = append(md.views, v)
@@ -13,6 +13,7 @@ package norm
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/errors"
 )
 
@@ -286,3 +287,107 @@ func (c *CustomFuncs) inlineProjections(e opt.Expr, projections memo.Projections
 
 	return replace(e)
 }
+
+// CanInlineUDF returns true if the call to a user-defined function with the
+// given arguments can be replaced by the body of the function. This is the
+// case if each argument is a variable, a constant or a placeholder, which can
+// be duplicated at no cost, or else if the corresponding parameter is
+// referenced at most once by the body, outside of any subquery. An argument
+// that can have side effects must be referenced exactly once.
+func (c *CustomFuncs) CanInlineUDF(
+	args memo.ScalarListExpr, body opt.ScalarExpr, private *memo.UDFPrivate,
+) bool {
+	for i, arg := range args {
+		switch arg.Op() {
+		case opt.VariableOp, opt.PlaceholderOp:
+			continue
+		}
+		if opt.IsConstValueOp(arg) {
+			continue
+		}
+
+		refs, inSubquery := c.countParamRefs(body, private.Params[i], false /* inSubquery */)
+		if refs > 1 || inSubquery {
+			return false
+		}
+		if refs == 0 {
+			var p props.Shared
+			memo.BuildSharedProps(c.mem, arg, &p)
+			if p.CanHaveSideEffects {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// countParamRefs returns the number of references to the given parameter
+// column in the given expression, and whether any of them is located within a
+// relational expression (i.e. a subquery).
+func (c *CustomFuncs) countParamRefs(
+	e opt.Expr, param opt.ColumnID, inSubquery bool,
+) (refs int, refInSubquery bool) {
+	switch t := e.(type) {
+	case *memo.VariableExpr:
+		if t.Col == param {
+			return 1, inSubquery
+		}
+		return 0, false
+	case memo.RelExpr:
+		if !t.Relational().OuterCols.Contains(param) {
+			return 0, false
+		}
+		inSubquery = true
+	}
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		childRefs, childInSubquery := c.countParamRefs(e.Child(i), param, inSubquery)
+		refs += childRefs
+		refInSubquery = refInSubquery || childInSubquery
+	}
+	return refs, refInSubquery
+}
+
+// InlineUDF replaces the references to the parameters of a user-defined
+// function in its body with the corresponding arguments, and returns the
+// resulting expression.
+func (c *CustomFuncs) InlineUDF(
+	args memo.ScalarListExpr, body opt.ScalarExpr, private *memo.UDFPrivate,
+) opt.ScalarExpr {
+	var replace ReplaceFunc
+	replace = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			for i, param := range private.Params {
+				if v.Col == param {
+					return args[i]
+				}
+			}
+			return v
+		}
+		return c.f.Replace(e, replace)
+	}
+	return replace(body).(opt.ScalarExpr)
+}
+
+// ConvertUDFToSubquery constructs a subquery which projects the arguments of
+// a call to a user-defined function as the parameter columns of the function,
+// and then computes its body.
+func (c *CustomFuncs) ConvertUDFToSubquery(
+	args memo.ScalarListExpr, body opt.ScalarExpr, private *memo.UDFPrivate,
+) opt.ScalarExpr {
+	paramProjections := make(memo.ProjectionsExpr, len(args))
+	for i, arg := range args {
+		paramProjections[i] = memo.ProjectionsItem{
+			Element:    arg,
+			ColPrivate: memo.ColPrivate{Col: private.Params[i]},
+		}
+	}
+	input := c.f.ConstructProject(c.ConstructNoColsRow(), paramProjections, opt.ColSet{})
+
+	resultCol := c.f.Metadata().AddColumn(private.Name, private.Typ)
+	input = c.f.ConstructProject(input, memo.ProjectionsExpr{{
+		Element:    body,
+		ColPrivate: memo.ColPrivate{Col: resultCol},
+	}}, opt.ColSet{})
+
+	return c.f.ConstructSubquery(input, &memo.SubqueryPrivate{})
+}
@@ -98,6 +98,17 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// Row-level triggers are passed the complete existing row, so no FetchCols
+	// can be pruned.
+	if op != opt.InsertOp && tabMeta.Table.HasTriggers() {
+		for ord, col := range private.FetchCols {
+			if col != 0 {
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+		return cols
+	}
+
 	// Retain any FetchCols that are needed for ReturnCols. If a RETURN column
 	// is needed, then:
 	//   1. For Delete, the corresponding FETCH column is always needed, since
@@ -106,6 +106,11 @@ type Builder struct {
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
+
+	// udfs contains the names of the user-defined functions whose bodies are
+	// currently being built. It is used to detect recursive functions, which
+	// cannot be inlined.
+	udfs map[string]struct{}
 }
 
 // New creates a new Builder structure initialized with the given
@@ -199,10 +204,10 @@ func (b *Builder) buildStmt(
 		return b.buildInsert(stmt, inScope)
 
 	case *tree.ParenSelect:
-		return b.buildSelect(stmt.Select, desiredTypes, inScope)
+		return b.buildSelect(stmt.Select, noRowLocking, desiredTypes, inScope)
 
 	case *tree.Select:
-		return b.buildSelect(stmt, desiredTypes, inScope)
+		return b.buildSelect(stmt, noRowLocking, desiredTypes, inScope)
 
 	case *tree.Update:
 		return b.buildUpdate(stmt, inScope)
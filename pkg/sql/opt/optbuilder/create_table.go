@@ -26,7 +26,12 @@ import (
 // statement.
 func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outScope *scope) {
 	b.DisableMemoReuse = true
-	sch, resName := b.resolveSchemaForCreate(&ct.Table)
+	isTemp, err := ct.Table.ResolveTemporaryStatus(ct.Temporary, b.evalCtx.SessionData.SearchPath)
+	if err != nil {
+		panic(err)
+	}
+	ct.Temporary = isTemp
+	sch, resName := b.resolveSchemaForCreate(&ct.Table, ct.Temporary)
 	// TODO(radu): we are modifying the AST in-place here. We should be storing
 	// the resolved name separately.
 	ct.Table.TableNamePrefix = resName
@@ -40,7 +45,7 @@ func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outSco
 	var inputCols physical.Presentation
 	if ct.As() {
 		// Build the input query.
-		outScope := b.buildSelect(ct.AsSource, nil /* desiredTypes */, inScope)
+		outScope := b.buildSelect(ct.AsSource, noRowLocking, nil /* desiredTypes */, inScope)
 
 		numColNames := len(ct.AsColumnNames)
 		numColumns := len(outScope.cols)
@@ -0,0 +1,172 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// lockingSpec maintains a collection of FOR [KEY] UPDATE/SHARE items that
+// apply to a given scope. Locking clauses can be applied to the lockingSpec
+// as they come into scope in the AST. The lockingSpec can then be consulted
+// to determine whether any relations in the current scope are configured to
+// use row-level locking.
+type lockingSpec []*tree.LockingItem
+
+// noRowLocking indicates that no row-level locking has been specified.
+var noRowLocking lockingSpec
+
+// isSet returns whether the spec contains any row-level locking modes.
+func (lm lockingSpec) isSet() bool {
+	return len(lm) != 0
+}
+
+// get returns the row-level locking mode of the spec. If the spec contains
+// multiple locking modes, they are combined into the strongest of them. If the
+// spec was the outcome of a filter operation, it contains a single mode.
+func (lm lockingSpec) get() *tree.LockingItem {
+	if !lm.isSet() {
+		return nil
+	}
+	if len(lm) == 1 {
+		return lm[0]
+	}
+	// Multiple locking items apply to the same relation. Combine them using
+	// the strongest strength and the most restrictive wait policy.
+	li := &tree.LockingItem{}
+	for _, li2 := range lm {
+		li.Strength = li.Strength.Max(li2.Strength)
+		li.WaitPolicy = li.WaitPolicy.Max(li2.WaitPolicy)
+	}
+	return li
+}
+
+// apply merges the locking clause into the current locking spec. The effect
+// of applying new locking clauses to an existing spec is always to strengthen
+// the locking approaches it represents, either through increasing locking
+// strength or through more restrictive wait policies.
+func (lm *lockingSpec) apply(locking tree.LockingClause) {
+	// Don't modify the original slice, which may be shared by other scopes.
+	if len(locking) > 0 {
+		*lm = append((*lm)[:len(*lm):len(*lm)], locking...)
+	}
+}
+
+// filter returns the desired row-level locking mode for the specified table
+// as a new consolidated lockingSpec. If no matching locking mode is found
+// then the resulting spec will remain un-set. If a matching locking mode for
+// the table is found then the resulting spec will contain exclusively that
+// locking mode and will no longer be restricted to specific target relations.
+func (lm lockingSpec) filter(alias tree.Name) lockingSpec {
+	var ret lockingSpec
+	for _, li := range lm {
+		if len(li.Targets) == 0 {
+			// If no targets are specified, the clause affects all tables.
+			ret = append(ret, li)
+			continue
+		}
+		// If targets are specified, the clause affects only those tables.
+		for i := range li.Targets {
+			if li.Targets[i].TableName == alias {
+				ret = append(ret, &tree.LockingItem{
+					Strength:   li.Strength,
+					WaitPolicy: li.WaitPolicy,
+				})
+				break
+			}
+		}
+	}
+	if len(ret) > 1 {
+		ret = lockingSpec{ret.get()}
+	}
+	return ret
+}
+
+// withoutTargets returns a new lockingSpec with all locking clauses that
+// apply only to a subset of tables removed.
+func (lm lockingSpec) withoutTargets() lockingSpec {
+	return lm.filter("")
+}
+
+// validateLockingInFrom checks the locking spec of a SELECT clause against
+// its FROM clause and the other clauses it is incompatible with. It is called
+// once the FROM clause has been built and the projection list analyzed.
+func (b *Builder) validateLockingInFrom(
+	sel *tree.SelectClause, locking lockingSpec, fromScope *scope,
+) {
+	if !locking.isSet() {
+		return
+	}
+
+	switch {
+	case sel.Distinct:
+		b.raiseLockingContextError(locking, "DISTINCT clause")
+
+	case len(sel.GroupBy) > 0:
+		b.raiseLockingContextError(locking, "GROUP BY clause")
+
+	case sel.Having != nil:
+		b.raiseLockingContextError(locking, "HAVING clause")
+
+	case fromScope.hasAggregates():
+		b.raiseLockingContextError(locking, "aggregate functions")
+
+	case len(fromScope.windows) != 0:
+		b.raiseLockingContextError(locking, "window functions")
+
+	case len(fromScope.srfs) != 0:
+		b.raiseLockingContextError(locking, "set-returning functions in the target list")
+	}
+
+	for _, li := range locking {
+		// Validate locking targets by checking that all targets are well-formed
+		// and all point to real relations present in the FROM clause.
+		for i := range li.Targets {
+			target := &li.Targets[i]
+
+			// Insist on unqualified alias names here, like Postgres does.
+			if target.CatalogName != "" || target.SchemaName != "" {
+				panic(pgerror.Newf(pgcode.Syntax,
+					"%s must specify unqualified relation names", li.Strength))
+			}
+
+			found := false
+			for j := range fromScope.cols {
+				if fromScope.cols[j].table.TableName == target.TableName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				panic(pgerror.Newf(pgcode.UndefinedTable,
+					"relation %q in %s clause not found in FROM clause",
+					target.TableName, li.Strength))
+			}
+		}
+	}
+}
+
+// rejectIfLocking raises a locking error if a locking clause was specified.
+func (b *Builder) rejectIfLocking(locking lockingSpec, context string) {
+	if locking.isSet() {
+		b.raiseLockingContextError(locking, context)
+	}
+}
+
+// raiseLockingContextError raises an error indicating that a row-level
+// locking clause is not permitted in the specified context. locking.isSet()
+// must be true.
+func (b *Builder) raiseLockingContextError(locking lockingSpec, context string) {
+	panic(pgerror.Newf(pgcode.FeatureNotSupported,
+		"%s is not allowed with %s", locking.get().Strength, context))
+}
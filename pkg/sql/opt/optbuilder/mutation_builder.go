@@ -191,6 +191,7 @@ func (mb *mutationBuilder) buildInputForUpdateOrDelete(
 		mb.b.addTable(mb.tab, &mb.alias),
 		nil, /* ordinals */
 		nil, /* indexFlags */
+		noRowLocking,
 		includeMutations,
 		inScope,
 	)
@@ -751,6 +752,16 @@ func (mb *mutationBuilder) buildFKChecks() {
 		return
 	}
 
+	// The checks of deferrable constraints may have to be deferred to the end
+	// of the transaction, which is only supported by the execution-time checks.
+	// Since those are disabled for the mutation as soon as one check is
+	// planned, fall back to them for all the constraints.
+	for i, n := 0, mb.tab.OutboundForeignKeyCount(); i < n; i++ {
+		if mb.tab.OutboundForeignKey(i).Deferrable() {
+			return
+		}
+	}
+
 	for i, n := 0, mb.tab.OutboundForeignKeyCount(); i < n; i++ {
 		fk := mb.tab.OutboundForeignKey(i)
 		numCols := fk.ColumnCount()
@@ -778,6 +789,7 @@ func (mb *mutationBuilder) buildFKChecks() {
 			refTabMeta,
 			refOrdinals,
 			&tree.IndexFlags{IgnoreForeignKeys: true},
+			noRowLocking,
 			includeMutations,
 			mb.b.allocScope(),
 		)
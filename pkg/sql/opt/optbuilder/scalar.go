@@ -445,6 +445,11 @@ func (b *Builder) buildFunction(
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
 	}
 
+	if def.UserDefined {
+		out = b.buildUDF(f, def, args)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
 	// Construct a private FuncOpDef that refers to a resolved function overload.
 	out = b.factory.ConstructFunction(args, &memo.FunctionPrivate{
 		Name:       def.Name,
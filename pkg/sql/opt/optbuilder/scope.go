@@ -90,6 +90,11 @@ type scope struct {
 	// context is the current context in the SQL query (e.g., "SELECT" or
 	// "HAVING"). It is used for error messages.
 	context string
+
+	// udfParams is true if the columns of this scope are the parameters of a
+	// user-defined function, whose body is built in a child scope. Placeholders
+	// in the body ($1, $2, etc) refer to these columns.
+	udfParams bool
 }
 
 // cteSource represents a CTE in the given query.
@@ -102,6 +107,11 @@ type cteSource struct {
 	// to only having a single reference to a given CTE, so if this is set then
 	// this CTE has already been referenced and may not be referenced again.
 	used bool
+
+	// withID is set for the working table of a recursive CTE, which is only
+	// visible to the recursive query. References to it are built as WithScan
+	// expressions.
+	withID opt.WithID
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -809,6 +819,21 @@ func makeUntypedTuple(labels []string, texprs []tree.TypedExpr) *tree.Tuple {
 	return &tree.Tuple{Exprs: exprs, Labels: labels}
 }
 
+// findUDFParam returns the parameter column referenced by the placeholder with
+// the given index if the scope is part of the body of a user-defined function,
+// or nil otherwise.
+func (s *scope) findUDFParam(idx tree.PlaceholderIdx) *scopeColumn {
+	for ; s != nil; s = s.parent {
+		if s.udfParams {
+			if int(idx) >= len(s.cols) {
+				panic(pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter $%d", idx+1))
+			}
+			return &s.cols[idx]
+		}
+	}
+	return nil
+}
+
 // VisitPre is part of the Visitor interface.
 //
 // NB: This code is adapted from sql/select_name_resolution.go and
@@ -848,8 +873,13 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		}
 		return false, colI.(*scopeColumn)
 
+	case *tree.Placeholder:
+		if col := s.findUDFParam(t.Idx); col != nil {
+			return false, col
+		}
+
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := tree.ResolveFunctionReference(&t.Func, s.builder.semaCtx)
 		if err != nil {
 			panic(err)
 		}
@@ -41,10 +41,14 @@ const (
 // including two for the left and right table scans, at least one for the join
 // condition, and one for the join itself.
 //
+// The locking spec contains the row-level locking modes which apply to the
+// table expression. Modes that target specific relations are filtered down to
+// the data sources they name.
+//
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildDataSource(
-	texpr tree.TableExpr, indexFlags *tree.IndexFlags, inScope *scope,
+	texpr tree.TableExpr, indexFlags *tree.IndexFlags, locking lockingSpec, inScope *scope,
 ) (outScope *scope) {
 	// NB: The case statements are sorted lexicographically.
 	switch source := texpr.(type) {
@@ -54,7 +58,14 @@ func (b *Builder) buildDataSource(
 			indexFlags = source.IndexFlags
 		}
 
-		outScope = b.buildDataSource(source.Expr, indexFlags, inScope)
+		// Filter the locking spec down to the modes that target the alias, if
+		// one was given. Locking modes that target the underlying relation name
+		// don't apply once it has been aliased.
+		if source.As.Alias != "" {
+			locking = locking.filter(source.As.Alias)
+		}
+
+		outScope = b.buildDataSource(source.Expr, indexFlags, locking, inScope)
 
 		if source.Ordinality {
 			outScope = b.buildWithOrdinality("ordinality", outScope)
@@ -66,13 +77,16 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.JoinTableExpr:
-		return b.buildJoin(source, inScope)
+		return b.buildJoin(source, locking, inScope)
 
 	case *tree.TableName:
 		tn := source
 
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
+			if cte.withID != 0 {
+				return b.buildWithScan(cte, inScope)
+			}
 			if cte.used {
 				panic(unimplementedWithIssueDetailf(21084, "", "unsupported multiple use of CTE clause %q", tn))
 			}
@@ -88,26 +102,33 @@ func (b *Builder) buildDataSource(
 		}
 
 		ds, resName := b.resolveDataSource(tn, privilege.SELECT)
+		locking = locking.filter(tn.TableName)
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(tabMeta, nil /* ordinals */, indexFlags, excludeMutations, inScope)
+			return b.buildScan(tabMeta, nil /* ordinals */, indexFlags, locking, excludeMutations, inScope)
 		case cat.View:
-			return b.buildView(t, inScope)
+			return b.buildView(t, locking, inScope)
 		case cat.Sequence:
+			if locking.isSet() {
+				panic(pgerror.Newf(pgcode.WrongObjectType,
+					"%s is not allowed with sequences", locking.get().Strength))
+			}
 			return b.buildSequenceSelect(t, inScope)
 		default:
 			panic(errors.AssertionFailedf("unknown DataSource type %T", ds))
 		}
 
 	case *tree.ParenTableExpr:
-		return b.buildDataSource(source.Expr, indexFlags, inScope)
+		return b.buildDataSource(source.Expr, indexFlags, locking, inScope)
 
 	case *tree.RowsFromExpr:
 		return b.buildZip(source.Items, inScope)
 
 	case *tree.Subquery:
-		outScope = b.buildStmt(source.Select, nil /* desiredTypes */, inScope)
+		// Locking modes that target specific relations only apply to relations
+		// in this statement, not to those of the subquery.
+		outScope = b.buildSelectStmt(source.Select, locking.withoutTargets(), nil /* desiredTypes */, inScope)
 
 		// Treat the subquery result as an anonymous data source (i.e. column names
 		// are not qualified). Remove hidden columns, as they are not accessible
@@ -129,7 +150,7 @@ func (b *Builder) buildDataSource(
 		ds := b.resolveDataSourceRef(source, privilege.SELECT)
 		switch t := ds.(type) {
 		case cat.Table:
-			outScope = b.buildScanFromTableRef(t, source, indexFlags, inScope)
+			outScope = b.buildScanFromTableRef(t, source, indexFlags, locking, inScope)
 		default:
 			panic(unimplementedWithIssueDetailf(35708, fmt.Sprintf("%T", t), "view and sequence numeric refs are not supported"))
 		}
@@ -142,7 +163,11 @@ func (b *Builder) buildDataSource(
 }
 
 // buildView parses the view query text and builds it as a Select expression.
-func (b *Builder) buildView(view cat.View, inScope *scope) (outScope *scope) {
+// Any row-level locking modes in the locking spec apply to all relations of
+// the view's query.
+func (b *Builder) buildView(
+	view cat.View, locking lockingSpec, inScope *scope,
+) (outScope *scope) {
 	// Cache the AST so that multiple references won't need to reparse.
 	if b.views == nil {
 		b.views = make(map[cat.View]*tree.Select)
@@ -179,7 +204,7 @@ func (b *Builder) buildView(view cat.View, inScope *scope) (outScope *scope) {
 		defer func() { b.skipSelectPrivilegeChecks = false }()
 	}
 
-	outScope = b.buildSelect(sel, nil /* desiredTypes */, &scope{builder: b})
+	outScope = b.buildSelect(sel, locking.withoutTargets(), nil /* desiredTypes */, &scope{builder: b})
 
 	// Update data source name to be the name of the view. And if view columns
 	// are specified, then update names of output columns.
@@ -256,7 +281,11 @@ func (b *Builder) renameSource(as tree.AliasClause, scope *scope) {
 // Note, the query SELECT * FROM [53() as t] is unsupported. Column lists must
 // be non-empty
 func (b *Builder) buildScanFromTableRef(
-	tab cat.Table, ref *tree.TableRef, indexFlags *tree.IndexFlags, inScope *scope,
+	tab cat.Table,
+	ref *tree.TableRef,
+	indexFlags *tree.IndexFlags,
+	locking lockingSpec,
+	inScope *scope,
 ) (outScope *scope) {
 	if ref.Columns != nil && len(ref.Columns) == 0 {
 		panic(pgerror.Newf(pgcode.Syntax,
@@ -289,7 +318,7 @@ func (b *Builder) buildScanFromTableRef(
 	}
 
 	tabMeta := b.addTable(tab, tab.Name())
-	return b.buildScan(tabMeta, ordinals, indexFlags, excludeMutations, inScope)
+	return b.buildScan(tabMeta, ordinals, indexFlags, locking, excludeMutations, inScope)
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
@@ -308,12 +337,16 @@ func (b *Builder) addTable(tab cat.Table, alias *tree.TableName) *opt.TableMeta
 // list are projected by the scan. Otherwise, all columns from the table are
 // projected.
 //
+// If the locking spec is set, then the scan acquires row-level locks on the
+// rows it reads, using the spec's strength and wait policy.
+//
 // See Builder.buildStmt for a description of the remaining input and return
 // values.
 func (b *Builder) buildScan(
 	tabMeta *opt.TableMeta,
 	ordinals []int,
 	indexFlags *tree.IndexFlags,
+	locking lockingSpec,
 	scanMutationCols bool,
 	inScope *scope,
 ) (outScope *scope) {
@@ -364,6 +397,10 @@ func (b *Builder) buildScan(
 			panic(pgerror.Newf(pgcode.Syntax,
 				"index flags not allowed with virtual tables"))
 		}
+		if locking.isSet() {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"%s not allowed with virtual tables", locking.get().Strength))
+		}
 		private := memo.VirtualScanPrivate{Table: tabID, Cols: tabColIDs}
 		outScope.expr = b.factory.ConstructVirtualScan(&private)
 	} else {
@@ -394,6 +431,9 @@ func (b *Builder) buildScan(
 				private.Flags.Direction = indexFlags.Direction
 			}
 		}
+		if locking.isSet() {
+			private.Locking = locking.get()
+		}
 		outScope.expr = b.factory.ConstructScan(&private)
 		b.addCheckConstraintsToScan(outScope, tabMeta)
 	}
@@ -476,13 +516,12 @@ func (b *Builder) buildWithOrdinality(colName string, inScope *scope) (outScope
 	return inScope
 }
 
-func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
+func (b *Builder) buildCTE(with *tree.With, inScope *scope) (outScope *scope) {
 	outScope = inScope.push()
 
+	ctes := with.CTEList
 	outScope.ctes = make(map[string]*cteSource)
 	for i := range ctes {
-		cteScope := b.buildStmt(ctes[i].Stmt, nil /* desiredTypes */, outScope)
-		cols := cteScope.cols
 		name := ctes[i].Name.Alias
 
 		if _, ok := outScope.ctes[name.String()]; ok {
@@ -492,6 +531,15 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 			)
 		}
 
+		var cteScope *scope
+		if with.Recursive {
+			cteScope = b.buildRecursiveCTE(ctes[i], outScope)
+		}
+		if cteScope == nil {
+			cteScope = b.buildStmt(ctes[i].Stmt, nil /* desiredTypes */, outScope)
+		}
+		cols := cteScope.cols
+
 		// Names for the output columns can optionally be specified.
 		if ctes[i].Name.Cols != nil {
 			if len(cteScope.cols) != len(ctes[i].Name.Cols) {
@@ -528,6 +576,150 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 	return outScope
 }
 
+// buildRecursiveCTE builds a CTE of a WITH RECURSIVE clause that has the form:
+//
+//   <initial query> UNION ALL <recursive query>
+//
+// The recursive query can refer to the CTE itself (at most once); such
+// references read the "working table", i.e. the rows produced by the previous
+// iteration. Returns nil if the statement doesn't have this form, in which case
+// it must be built as a regular CTE.
+func (b *Builder) buildRecursiveCTE(cte *tree.CTE, inScope *scope) (outScope *scope) {
+	sel, ok := cte.Stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil
+	}
+	clause, ok := sel.Select.(*tree.UnionClause)
+	if !ok || clause.Type != tree.UnionOp {
+		return nil
+	}
+	name := cte.Name.Alias
+
+	// The initial query can't refer to the CTE.
+	initialScope := b.buildSelect(clause.Left, noRowLocking, nil /* desiredTypes */, inScope)
+	initialScope.removeHiddenCols()
+
+	if cte.Name.Cols != nil && len(cte.Name.Cols) != len(initialScope.cols) {
+		panic(pgerror.Newf(
+			pgcode.InvalidColumnReference,
+			"source %q has %d columns available but %d columns specified",
+			name, len(initialScope.cols), len(cte.Name.Cols),
+		))
+	}
+
+	// Synthesize the output columns of the CTE, which are also the columns of
+	// the working table. They have the types of the initial query columns.
+	outScope = inScope.push()
+	tableName := tree.MakeUnqualifiedTableName(name)
+	for i := range initialScope.cols {
+		colName := string(initialScope.cols[i].name)
+		if cte.Name.Cols != nil {
+			colName = string(cte.Name.Cols[i])
+		}
+		col := b.synthesizeColumn(
+			outScope, colName, initialScope.cols[i].typ, nil /* expr */, nil, /* scalar */
+		)
+		col.table = tableName
+	}
+
+	// Build the recursive query in a scope where the CTE refers to the working
+	// table.
+	withID := b.factory.Metadata().NextWithID()
+	workingTable := &cteSource{name: cte.Name, cols: outScope.cols, withID: withID}
+	recursiveInScope := inScope.push()
+	recursiveInScope.ctes = map[string]*cteSource{name.String(): workingTable}
+	recursiveScope := b.buildSelect(clause.Right, noRowLocking, nil /* desiredTypes */, recursiveInScope)
+	recursiveScope.removeHiddenCols()
+
+	if !workingTable.used {
+		// The CTE doesn't refer to itself, so it is a regular UNION.
+		return b.buildSetOp(clause.Type, clause.All, inScope, initialScope, recursiveScope)
+	}
+
+	if !clause.All {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"recursive query %q must use UNION ALL", tree.ErrString(&name)))
+	}
+
+	if len(recursiveScope.cols) != len(outScope.cols) {
+		panic(pgerror.Newf(
+			pgcode.Syntax,
+			"each %v query must have the same number of columns: %d vs %d",
+			clause.Type, len(initialScope.cols), len(recursiveScope.cols),
+		))
+	}
+	propagateTypes := false
+	for i := range outScope.cols {
+		typ, recursiveTyp := outScope.cols[i].typ, recursiveScope.cols[i].typ
+		if recursiveTyp.Family() == types.UnknownFamily && typ.Family() != types.UnknownFamily {
+			propagateTypes = true
+		} else if !typ.Equivalent(recursiveTyp) {
+			panic(pgerror.Newf(pgcode.DatatypeMismatch,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				tree.ErrString(&name), i+1, typ, recursiveTyp,
+			))
+		}
+	}
+	if propagateTypes {
+		recursiveScope = b.propagateTypes(recursiveScope, outScope)
+	}
+
+	// The recursive query is planned anew for every iteration, so it can't
+	// contain mutations or refer to outer columns.
+	recursive := recursiveScope.expr.(memo.RelExpr)
+	if recursive.Relational().CanMutate {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"recursive query %q must not contain data-modifying statements", tree.ErrString(&name)))
+	}
+	if !recursive.Relational().OuterCols.Empty() {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"recursive query %q must not refer to outer columns", tree.ErrString(&name)))
+	}
+
+	outScope.expr = b.factory.ConstructRecursiveCTE(
+		initialScope.expr.(memo.RelExpr),
+		recursive,
+		&memo.RecursiveCTEPrivate{
+			Name:          string(name),
+			WithID:        withID,
+			InitialCols:   colsToColList(initialScope.cols),
+			RecursiveCols: colsToColList(recursiveScope.cols),
+			OutCols:       colsToColList(outScope.cols),
+		},
+	)
+	return outScope
+}
+
+// buildWithScan builds a reference to the working table of a recursive CTE.
+// It can only appear inside the recursive query of that CTE.
+func (b *Builder) buildWithScan(cte *cteSource, inScope *scope) (outScope *scope) {
+	if cte.used {
+		panic(pgerror.Newf(pgcode.InvalidRecursion,
+			"recursive reference to query %q must not appear more than once",
+			tree.ErrString(&cte.name.Alias)))
+	}
+	cte.used = true
+
+	// Synthesize new columns, since the working table columns are the output
+	// columns of the recursive CTE.
+	outScope = inScope.push()
+	inCols := make(opt.ColList, len(cte.cols))
+	for i := range cte.cols {
+		inCols[i] = cte.cols[i].id
+		col := b.synthesizeColumn(
+			outScope, string(cte.cols[i].name), cte.cols[i].typ, nil /* expr */, nil, /* scalar */
+		)
+		col.table = cte.cols[i].table
+	}
+	outScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    cte.withID,
+		Name:    string(cte.name.Alias),
+		InCols:  inCols,
+		OutCols: colsToColList(outScope.cols),
+	})
+	return outScope
+}
+
 // checkCTEUsage ensures that a CTE that contains a mutation (like INSERT) is
 // used at least once by the query. Otherwise, it might not be executed.
 func (b *Builder) checkCTEUsage(inScope *scope) {
@@ -545,20 +737,22 @@ func (b *Builder) checkCTEUsage(inScope *scope) {
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildSelectStmt(
-	stmt tree.SelectStatement, desiredTypes []*types.T, inScope *scope,
+	stmt tree.SelectStatement, locking lockingSpec, desiredTypes []*types.T, inScope *scope,
 ) (outScope *scope) {
 	// NB: The case statements are sorted lexicographically.
 	switch stmt := stmt.(type) {
 	case *tree.ParenSelect:
-		return b.buildSelect(stmt.Select, desiredTypes, inScope)
+		return b.buildSelect(stmt.Select, locking, desiredTypes, inScope)
 
 	case *tree.SelectClause:
-		return b.buildSelectClause(stmt, nil /* orderBy */, desiredTypes, inScope)
+		return b.buildSelectClause(stmt, nil /* orderBy */, locking, desiredTypes, inScope)
 
 	case *tree.UnionClause:
+		b.rejectIfLocking(locking, "UNION/INTERSECT/EXCEPT")
 		return b.buildUnion(stmt, desiredTypes, inScope)
 
 	case *tree.ValuesClause:
+		b.rejectIfLocking(locking, "VALUES")
 		return b.buildValuesClause(stmt, desiredTypes, inScope)
 
 	default:
@@ -572,12 +766,13 @@ func (b *Builder) buildSelectStmt(
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildSelect(
-	stmt *tree.Select, desiredTypes []*types.T, inScope *scope,
+	stmt *tree.Select, locking lockingSpec, desiredTypes []*types.T, inScope *scope,
 ) (outScope *scope) {
 	wrapped := stmt.Select
 	orderBy := stmt.OrderBy
 	limit := stmt.Limit
 	with := stmt.With
+	locking.apply(stmt.Locking)
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
@@ -607,22 +802,25 @@ func (b *Builder) buildSelect(
 			}
 			limit = stmt.Limit
 		}
+		locking.apply(stmt.Locking)
 	}
 
 	if with != nil {
-		inScope = b.buildCTE(with.CTEList, inScope)
+		inScope = b.buildCTE(with, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
 	// NB: The case statements are sorted lexicographically.
 	switch t := stmt.Select.(type) {
 	case *tree.SelectClause:
-		outScope = b.buildSelectClause(t, orderBy, desiredTypes, inScope)
+		outScope = b.buildSelectClause(t, orderBy, locking, desiredTypes, inScope)
 
 	case *tree.UnionClause:
+		b.rejectIfLocking(locking, "UNION/INTERSECT/EXCEPT")
 		outScope = b.buildUnion(t, desiredTypes, inScope)
 
 	case *tree.ValuesClause:
+		b.rejectIfLocking(locking, "VALUES")
 		outScope = b.buildValuesClause(t, desiredTypes, inScope)
 
 	default:
@@ -661,9 +859,13 @@ func (b *Builder) buildSelect(
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildSelectClause(
-	sel *tree.SelectClause, orderBy tree.OrderBy, desiredTypes []*types.T, inScope *scope,
+	sel *tree.SelectClause,
+	orderBy tree.OrderBy,
+	locking lockingSpec,
+	desiredTypes []*types.T,
+	inScope *scope,
 ) (outScope *scope) {
-	fromScope := b.buildFrom(sel.From, inScope)
+	fromScope := b.buildFrom(sel.From, locking, inScope)
 	b.processWindowDefs(sel, fromScope)
 	b.buildWhere(sel.Where, fromScope)
 
@@ -684,6 +886,7 @@ func (b *Builder) buildSelectClause(
 	var groupingCols []scopeColumn
 	var having opt.ScalarExpr
 	needsAgg := b.needsAggregation(sel, fromScope)
+	b.validateLockingInFrom(sel, locking, fromScope)
 	if needsAgg {
 		// Grouping columns must be built before building the projection list so
 		// we can check that any column references that appear in the SELECT list
@@ -726,7 +929,7 @@ func (b *Builder) buildSelectClause(
 //
 // See Builder.buildStmt for a description of the remaining input and return
 // values.
-func (b *Builder) buildFrom(from *tree.From, inScope *scope) (outScope *scope) {
+func (b *Builder) buildFrom(from *tree.From, locking lockingSpec, inScope *scope) (outScope *scope) {
 	// The root AS OF clause is recognized and handled by the executor. The only
 	// thing that must be done at this point is to ensure that if any timestamps
 	// are specified, the root SELECT was an AS OF SYSTEM TIME and that the time
@@ -736,7 +939,7 @@ func (b *Builder) buildFrom(from *tree.From, inScope *scope) (outScope *scope) {
 	}
 
 	if len(from.Tables) > 0 {
-		outScope = b.buildFromTables(from.Tables, inScope)
+		outScope = b.buildFromTables(from.Tables, locking, inScope)
 	} else {
 		outScope = inScope.push()
 		outScope.expr = b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
@@ -792,15 +995,17 @@ func (b *Builder) buildWhere(where *tree.Where, inScope *scope) {
 //
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
-func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outScope *scope) {
+func (b *Builder) buildFromTables(
+	tables tree.TableExprs, locking lockingSpec, inScope *scope,
+) (outScope *scope) {
 	// If there are any lateral data sources, we need to build the join tree
 	// left-deep instead of right-deep.
 	for i := range tables {
 		if b.exprIsLateral(tables[i]) {
-			return b.buildFromWithLateral(tables, inScope)
+			return b.buildFromWithLateral(tables, locking, inScope)
 		}
 	}
-	return b.buildFromTablesRightDeep(tables, inScope)
+	return b.buildFromTablesRightDeep(tables, locking, inScope)
 }
 
 // buildFromTablesRightDeep recursively builds a series of InnerJoin
@@ -821,16 +1026,16 @@ func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outSc
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildFromTablesRightDeep(
-	tables tree.TableExprs, inScope *scope,
+	tables tree.TableExprs, locking lockingSpec, inScope *scope,
 ) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, inScope)
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, locking, inScope)
 
 	// Recursively build table join.
 	tables = tables[1:]
 	if len(tables) == 0 {
 		return outScope
 	}
-	tableScope := b.buildFromTablesRightDeep(tables, inScope)
+	tableScope := b.buildFromTablesRightDeep(tables, locking, inScope)
 
 	// Check that the same table name is not used multiple times.
 	b.validateJoinTableNames(outScope, tableScope)
@@ -869,8 +1074,10 @@ func (b *Builder) exprIsLateral(t tree.TableExpr) bool {
 //
 //   buildFromTablesRightDeep: a JOIN (b JOIN c)
 //   buildFromWithLateral:     (a JOIN b) JOIN c
-func (b *Builder) buildFromWithLateral(tables tree.TableExprs, inScope *scope) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, inScope)
+func (b *Builder) buildFromWithLateral(
+	tables tree.TableExprs, locking lockingSpec, inScope *scope,
+) (outScope *scope) {
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, locking, inScope)
 	for i := 1; i < len(tables); i++ {
 		scope := inScope
 		// Lateral expressions need to be able to refer to the expressions that
@@ -878,7 +1085,7 @@ func (b *Builder) buildFromWithLateral(tables tree.TableExprs, inScope *scope) (
 		if b.exprIsLateral(tables[i]) {
 			scope = outScope
 		}
-		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, scope)
+		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, locking, scope)
 
 		// Check that the same table name is not used multiple times.
 		b.validateJoinTableNames(outScope, tableScope)
@@ -896,10 +1103,11 @@ func (b *Builder) buildFromWithLateral(tables tree.TableExprs, inScope *scope) (
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
-	ts, err := tree.EvalAsOfTimestamp(asOf, b.semaCtx, b.evalCtx)
+	asOfRes, err := tree.EvalAsOf(asOf, b.semaCtx, b.evalCtx)
 	if err != nil {
 		panic(err)
 	}
+	ts := asOfRes.Timestamp
 
 	if b.semaCtx.AsOfTimestamp == nil {
 		panic(pgerror.Newf(pgcode.Syntax,
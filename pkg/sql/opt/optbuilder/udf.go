@@ -0,0 +1,160 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// buildUDF builds a call to a user-defined function written in SQL, with the
+// given (already built) arguments. The body of the function is built in a new
+// scope, which does not have access to the columns of the calling query. The
+// parameters of the function are represented by new columns in that scope;
+// they can be referenced from the body by name, or by position ($1, $2, etc).
+//
+// The resulting UDF expression is always normalized away, either by inlining
+// the arguments into the body, or by converting the call into a subquery (see
+// the InlineUDF and ConvertUDFToSubquery rules).
+func (b *Builder) buildUDF(
+	f *tree.FuncExpr, def *tree.FunctionDefinition, args memo.ScalarListExpr,
+) opt.ScalarExpr {
+	overload := f.ResolvedOverload()
+	returnType := f.ResolvedType()
+
+	// The function may be altered or dropped, which is not detected by the
+	// memo staleness checks.
+	b.DisableMemoReuse = true
+
+	if _, ok := b.udfs[def.Name]; ok {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"recursive call to function %s() is not supported", def.Name))
+	}
+	if b.udfs == nil {
+		b.udfs = make(map[string]struct{})
+	}
+	b.udfs[def.Name] = struct{}{}
+	defer delete(b.udfs, def.Name)
+
+	stmt, err := parser.ParseOne(overload.SQLBody)
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.Syntax,
+			"failed to parse body of function %s()", def.Name))
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(errors.AssertionFailedf("expected SELECT statement"))
+	}
+
+	paramTypes := overload.Types.(tree.ArgTypes)
+	paramScope := b.allocScope()
+	paramScope.udfParams = true
+	params := make(opt.ColList, len(paramTypes))
+	for i := range paramTypes {
+		col := b.synthesizeColumn(
+			paramScope, overload.ParamNames[i], paramTypes[i].Typ, nil /* expr */, nil, /* scalar */
+		)
+		params[i] = col.id
+	}
+
+	var body opt.ScalarExpr
+	var bodyType *types.T
+	if expr, ok := simpleUDFBody(sel, b.semaCtx); ok {
+		// The body computes a single expression without a FROM clause, so it
+		// can be built as a scalar expression. This is the common case, which
+		// allows the body to be fully inlined into the calling query.
+		bodyScope := paramScope.push()
+		texpr := bodyScope.resolveType(expr, returnType)
+		bodyType = texpr.ResolvedType()
+		body = b.buildScalar(texpr, bodyScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+	} else {
+		// Otherwise, the body is built as a subquery which returns the first row
+		// of the result.
+		bodyScope := b.buildSelect(sel, noRowLocking, []*types.T{returnType}, paramScope)
+		if len(bodyScope.cols) != 1 {
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function %s(): the body must return exactly one column",
+				def.Name))
+		}
+		bodyType = bodyScope.cols[0].typ
+		input := b.factory.ConstructLimit(
+			bodyScope.expr, b.factory.ConstructConst(tree.NewDInt(1)), bodyScope.makeOrderingChoice(),
+		)
+		input = b.constructProject(input, bodyScope.cols)
+		body = b.factory.ConstructSubquery(input, &memo.SubqueryPrivate{})
+	}
+
+	if bodyType.Family() != types.UnknownFamily && !bodyType.Equivalent(returnType) {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function %s(): declared to return %s, but the body returns %s",
+			def.Name, returnType, bodyType))
+	}
+	if !bodyType.Identical(returnType) {
+		body = b.factory.ConstructCast(body, returnType)
+	}
+
+	return b.factory.ConstructUDF(args, body, &memo.UDFPrivate{
+		Name:   def.Name,
+		Params: params,
+		Typ:    returnType,
+		Impure: def.Impure,
+	})
+}
+
+// simpleUDFBody returns the expression computed by the body of a user-defined
+// function if the body is a simple SELECT of a single scalar expression,
+// without a FROM clause or any other clause that would require building it as
+// a relational expression.
+func simpleUDFBody(sel *tree.Select, semaCtx *tree.SemaContext) (tree.Expr, bool) {
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, false
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || len(clause.Exprs) != 1 || len(clause.From.Tables) != 0 ||
+		clause.Where != nil || clause.GroupBy != nil || clause.Having != nil ||
+		clause.Window != nil || clause.Distinct || clause.DistinctOn != nil {
+		return nil, false
+	}
+	expr := clause.Exprs[0].Expr
+
+	// Aggregate, window and set-generating functions require a relational
+	// context.
+	simple := true
+	_, err := tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+		if !simple {
+			return false, e, nil
+		}
+		switch t := e.(type) {
+		case *tree.FuncExpr:
+			def, err := tree.ResolveFunctionReference(&t.Func, semaCtx)
+			if err != nil {
+				return false, e, err
+			}
+			if t.WindowDef != nil || def.Class != tree.NormalClass {
+				simple = false
+			}
+		case *tree.Subquery:
+			// Subqueries are built separately.
+			return false, e, nil
+		}
+		return true, e, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return expr, simple
+}
@@ -28,19 +28,27 @@ import (
 func (b *Builder) buildUnion(
 	clause *tree.UnionClause, desiredTypes []*types.T, inScope *scope,
 ) (outScope *scope) {
-	leftScope := b.buildSelect(clause.Left, desiredTypes, inScope)
-	rightScope := b.buildSelect(clause.Right, desiredTypes, inScope)
+	leftScope := b.buildSelect(clause.Left, noRowLocking, desiredTypes, inScope)
+	rightScope := b.buildSelect(clause.Right, noRowLocking, desiredTypes, inScope)
 
 	// Remove any hidden columns, as they are not included in the Union.
 	leftScope.removeHiddenCols()
 	rightScope.removeHiddenCols()
 
+	return b.buildSetOp(clause.Type, clause.All, inScope, leftScope, rightScope)
+}
+
+// buildSetOp builds a set operation (UNION, INTERSECT or EXCEPT, with or
+// without ALL) between the given, already built, left and right scopes.
+func (b *Builder) buildSetOp(
+	unionType tree.UnionType, all bool, inScope, leftScope, rightScope *scope,
+) (outScope *scope) {
 	// Check that the number of columns matches.
 	if len(leftScope.cols) != len(rightScope.cols) {
 		panic(pgerror.Newf(
 			pgcode.Syntax,
 			"each %v query must have the same number of columns: %d vs %d",
-			clause.Type, len(leftScope.cols), len(rightScope.cols),
+			unionType, len(leftScope.cols), len(rightScope.cols),
 		))
 	}
 
@@ -52,7 +60,7 @@ func (b *Builder) buildUnion(
 	// synthesize new columns to contain these values. This is not necessary for
 	// INTERSECT or EXCEPT, since these operations are basically filters on the
 	// left relation.
-	newColsNeeded := clause.Type == tree.UnionOp
+	newColsNeeded := unionType == tree.UnionOp
 	if newColsNeeded {
 		outScope.cols = make([]scopeColumn, 0, len(leftScope.cols))
 	}
@@ -78,11 +86,11 @@ func (b *Builder) buildUnion(
 			l.typ.Family() == types.UnknownFamily ||
 			r.typ.Family() == types.UnknownFamily) {
 			panic(pgerror.Newf(pgcode.DatatypeMismatch,
-				"%v types %s and %s cannot be matched", clause.Type, l.typ, r.typ))
+				"%v types %s and %s cannot be matched", unionType, l.typ, r.typ))
 		}
 		if l.hidden != r.hidden {
 			// This should never happen.
-			panic(errors.AssertionFailedf("%v types cannot be matched", clause.Type))
+			panic(errors.AssertionFailedf("%v types cannot be matched", unionType))
 		}
 
 		var typ *types.T
@@ -126,8 +134,8 @@ func (b *Builder) buildUnion(
 	right := rightScope.expr.(memo.RelExpr)
 	private := memo.SetPrivate{LeftCols: leftCols, RightCols: rightCols, OutCols: newCols}
 
-	if clause.All {
-		switch clause.Type {
+	if all {
+		switch unionType {
 		case tree.UnionOp:
 			outScope.expr = b.factory.ConstructUnionAll(left, right, &private)
 		case tree.IntersectOp:
@@ -136,7 +144,7 @@ func (b *Builder) buildUnion(
 			outScope.expr = b.factory.ConstructExceptAll(left, right, &private)
 		}
 	} else {
-		switch clause.Type {
+		switch unionType {
 		case tree.UnionOp:
 			outScope.expr = b.factory.ConstructUnion(left, right, &private)
 		case tree.IntersectOp:
@@ -409,8 +409,11 @@ func (b *Builder) resolveAndBuildScalar(
 
 // resolveSchemaForCreate returns the schema that will contain a newly created
 // catalog object with the given name. If the current user does not have the
-// CREATE privilege, then resolveSchemaForCreate raises an error.
-func (b *Builder) resolveSchemaForCreate(name *tree.TableName) (cat.Schema, cat.SchemaName) {
+// CREATE privilege, then resolveSchemaForCreate raises an error. Temporary
+// objects may also be created in the temporary schema of the session.
+func (b *Builder) resolveSchemaForCreate(
+	name *tree.TableName, isTemporary bool,
+) (cat.Schema, cat.SchemaName) {
 	flags := cat.Flags{AvoidDescriptorCaches: true}
 	sch, resName, err := b.catalog.ResolveSchema(b.ctx, flags, &name.TableNamePrefix)
 	if err != nil {
@@ -428,8 +431,9 @@ func (b *Builder) resolveSchemaForCreate(name *tree.TableName) (cat.Schema, cat.
 		panic(err)
 	}
 
-	// Only allow creation of objects in the public schema.
-	if resName.Schema() != tree.PublicSchema {
+	// Only allow creation of objects in the public schema. The schema of
+	// temporary objects has already been validated by ResolveTemporaryStatus.
+	if resName.Schema() != tree.PublicSchema && !isTemporary {
 		panic(pgerror.Newf(pgcode.InvalidName,
 			"schema cannot be modified: %q", tree.ErrString(&resName)))
 	}
@@ -150,12 +150,14 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"SchemaID":       {fullName: "opt.SchemaID", passByVal: true},
 		"SequenceID":     {fullName: "opt.SequenceID", passByVal: true},
 		"ValuesID":       {fullName: "opt.ValuesID", passByVal: true},
+		"WithID":         {fullName: "opt.WithID", passByVal: true},
 		"Ordering":       {fullName: "opt.Ordering", passByVal: true},
 		"OrderingChoice": {fullName: "physical.OrderingChoice", passByVal: true},
 		"TupleOrdinal":   {fullName: "memo.TupleOrdinal", passByVal: true},
 		"ScanLimit":      {fullName: "memo.ScanLimit", passByVal: true},
 		"ScanFlags":      {fullName: "memo.ScanFlags", passByVal: true},
 		"JoinFlags":      {fullName: "memo.JoinFlags", passByVal: true},
+		"LockingItem":    {fullName: "*tree.LockingItem", isPointer: true},
 		"WindowFrame":    {fullName: "memo.WindowFrame", passByVal: true},
 		"ExplainOptions": {fullName: "tree.ExplainOptions", passByVal: true},
 		"StatementType":  {fullName: "tree.StatementType", passByVal: true},
@@ -335,6 +335,7 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		referencedColumnOrdinals: toCols,
 		validated:                true,
 		matchMethod:              d.Match,
+		deferrable:               d.Deferrable != tree.NotDeferrable,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
@@ -917,6 +917,9 @@ func (o *Optimizer) disableRules(probability float64) {
 		// TODO(radu): the DistinctOn execution path should be fixed up so it
 		// supports distinct on an empty column set.
 		int(opt.EliminateDistinctOnNoColumns),
+		// Needed to prevent execbuilder error, since UDF expressions cannot be
+		// executed directly.
+		int(opt.ConvertUDFToSubquery),
 	)
 
 	for i := opt.RuleName(1); i < opt.NumRuleNames; i++ {
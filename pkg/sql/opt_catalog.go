@@ -595,6 +595,7 @@ func newOptTable(
 					numCols:         int(fk.SharedPrefixLen),
 					validity:        fk.Validity,
 					match:           fk.Match,
+					deferrable:      fk.Deferrable,
 				})
 			}
 			for j := range idxDesc.ReferencedBy {
@@ -608,6 +609,7 @@ func newOptTable(
 					numCols:         int(fk.SharedPrefixLen),
 					validity:        fk.Validity,
 					match:           fk.Match,
+					deferrable:      fk.Deferrable,
 				})
 			}
 		}
@@ -839,6 +841,11 @@ func (ot *optTable) InboundForeignKey(i int) cat.ForeignKeyConstraint {
 	return &ot.inboundFKs[i]
 }
 
+// HasTriggers is part of the cat.Table interface.
+func (ot *optTable) HasTriggers() bool {
+	return len(ot.desc.Triggers) > 0
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID sqlbase.ColumnID) (int, error) {
@@ -1196,9 +1203,10 @@ type optForeignKeyConstraint struct {
 	referencedTable cat.StableID
 	referencedIndex sqlbase.IndexID
 
-	numCols  int
-	validity sqlbase.ConstraintValidity
-	match    sqlbase.ForeignKeyReference_Match
+	numCols    int
+	validity   sqlbase.ConstraintValidity
+	match      sqlbase.ForeignKeyReference_Match
+	deferrable bool
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1269,3 +1277,8 @@ func (fk *optForeignKeyConstraint) Validated() bool {
 func (fk *optForeignKeyConstraint) MatchMethod() tree.CompositeKeyMatchMethod {
 	return sqlbase.ForeignKeyReferenceMatchValue[fk.match]
 }
+
+// Deferrable is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrable() bool {
+	return fk.deferrable
+}
@@ -375,6 +375,7 @@ func (p *planner) propagateFilters(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
@@ -389,14 +390,19 @@ func (p *planner) propagateFilters(
 	case *createIndexNode:
 	case *CreateUserNode:
 	case *createViewNode:
+	case *createFunctionNode:
 	case *createSequenceNode:
 	case *createStatsNode:
+	case *createTriggerNode:
+	case *createTypeNode:
 	case *deleteRangeNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropFunctionNode:
 	case *dropSequenceNode:
+	case *dropTriggerNode:
 	case *DropUserNode:
 	case *hookFnNode:
 	case *valuesNode:
@@ -206,6 +206,7 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *deleteRangeNode:
 	case *renameColumnNode:
@@ -221,13 +222,18 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *createIndexNode:
 	case *CreateUserNode:
 	case *createViewNode:
+	case *createFunctionNode:
 	case *createSequenceNode:
 	case *createStatsNode:
+	case *createTriggerNode:
+	case *createTypeNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropFunctionNode:
 	case *dropSequenceNode:
+	case *dropTriggerNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -242,7 +248,7 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *scanBufferNode:
 	case *unsplitAllNode:
 
-	case *applyJoinNode, *lookupJoinNode, *zigzagJoinNode, *saveTableNode:
+	case *applyJoinNode, *lookupJoinNode, *zigzagJoinNode, *saveTableNode, *recursiveCTENode:
 		// These nodes are only planned by the optimizer.
 
 	default:
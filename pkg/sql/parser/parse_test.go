@@ -151,6 +151,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE RESTRICT ON UPDATE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE SET DEFAULT ON UPDATE CASCADE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE CASCADE ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE SET NULL ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other MATCH FULL)`},
@@ -168,6 +170,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
+		{`CREATE TABLE a (b INT8, UNIQUE (b) DEFERRABLE INITIALLY IMMEDIATE)`},
+		{`CREATE TABLE a (b INT8, CONSTRAINT c UNIQUE (b) DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
@@ -241,6 +245,11 @@ func TestParse(t *testing.T) {
 
 		{`CREATE TABLE a AS SELECT * FROM b`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8)`},
+		{`CREATE TEMPORARY TABLE pg_temp.a (b INT8)`},
+		{`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a AS SELECT * FROM b`},
 		{`CREATE TABLE a AS SELECT * FROM b ORDER BY c`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b ORDER BY c`},
 		{`CREATE TABLE a AS SELECT * FROM b LIMIT 3`},
@@ -267,6 +276,19 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 
+		{`CREATE TYPE a AS ENUM ('a', 'b', 'c')`},
+		{`CREATE TYPE a AS ENUM ()`},
+		{`CREATE TYPE db.sc.a AS ENUM ('a''b')`},
+
+		{`CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+		{`CREATE FUNCTION f(a INT8, b STRING) RETURNS INT8 LANGUAGE sql IMMUTABLE AS 'SELECT a + length(b)'`},
+		{`CREATE FUNCTION db.sc.f(INT8, INT8) RETURNS BOOL AS 'SELECT $1 < $2' STABLE LANGUAGE sql`},
+		{`CREATE FUNCTION f(a INT8[]) RETURNS STRING LANGUAGE sql VOLATILE AS 'SELECT ''a'''`},
+
+		{`CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW EXECUTE 'SELECT 1'`},
+		{`CREATE TRIGGER t AFTER DELETE ON db.sc.a FOR EACH STATEMENT EXECUTE 'DELETE FROM b'`},
+		{`CREATE TRIGGER t AFTER INSERT OR UPDATE OF b, c OR DELETE ON a FOR EACH ROW EXECUTE 'INSERT INTO log VALUES (new.b)'`},
+
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
 		{`CREATE SEQUENCE IF NOT EXISTS a`},
@@ -346,6 +368,13 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION a.f(INT8, STRING)`},
+		{`DROP FUNCTION IF EXISTS f(INT8)`},
+		{`DROP TRIGGER t ON a`},
+		{`DROP TRIGGER IF EXISTS t ON db.sc.a`},
+
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -421,6 +450,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW JOBS`},
 		{`SHOW AUTOMATIC JOBS`},
 		{`EXPLAIN SHOW AUTOMATIC JOBS`},
+		{`SHOW CHANGEFEED JOBS`},
+		{`EXPLAIN SHOW CHANGEFEED JOBS`},
 		{`SHOW CLUSTER QUERIES`},
 		{`EXPLAIN SHOW CLUSTER QUERIES`},
 		{`SHOW ALL CLUSTER QUERIES`},
@@ -694,6 +725,7 @@ func TestParse(t *testing.T) {
 		{`SELECT TIMESTAMP 'foo', 'foo'::TIMESTAMP`},
 		{`SELECT TIMESTAMPTZ 'foo', 'foo'::TIMESTAMPTZ`},
 		{`SELECT JSONB 'foo', 'foo'::JSONB`},
+		{`SELECT notatype 'foo', 'foo'::notatype`},
 
 		{`SELECT 'foo'::DECIMAL(1)`},
 		{`SELECT 'foo'::DECIMAL(2,1)`},
@@ -737,12 +769,15 @@ func TestParse(t *testing.T) {
 
 		{`SELECT "FROM" FROM t`},
 		{`SELECT CAST(1 AS STRING)`},
+		{`SELECT CAST(1 AS notatype)`},
 		{`SELECT ANNOTATE_TYPE(1, STRING)`},
+		{`SELECT ANNOTATE_TYPE(1, notatype)`},
 		{`SELECT a FROM t AS bar`},
 		{`SELECT a FROM t AS bar (bar1)`},
 		{`SELECT a FROM t AS bar (bar1, bar2, bar3)`},
 		{`SELECT a FROM t WITH ORDINALITY`},
 		{`SELECT a FROM t WITH ORDINALITY AS bar`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a) SELECT x FROM a`},
 		{`SELECT a FROM (SELECT 1 FROM t)`},
 		{`SELECT a FROM (SELECT 1 FROM t) AS bar`},
 		{`SELECT a FROM (SELECT 1 FROM t) AS bar (bar1)`},
@@ -961,6 +996,19 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t LIMIT a`},
 		{`SELECT a FROM t OFFSET b`},
 		{`SELECT a FROM t LIMIT a OFFSET b`},
+
+		{`SELECT a FROM t FOR UPDATE`},
+		{`SELECT a FROM t FOR NO KEY UPDATE`},
+		{`SELECT a FROM t FOR SHARE`},
+		{`SELECT a FROM t FOR KEY SHARE`},
+		{`SELECT a FROM t FOR UPDATE OF t`},
+		{`SELECT a FROM t, u FOR UPDATE OF t, db.public.u`},
+		{`SELECT a FROM t FOR UPDATE SKIP LOCKED`},
+		{`SELECT a FROM t FOR SHARE NOWAIT`},
+		{`SELECT a FROM t, u FOR UPDATE OF t NOWAIT FOR SHARE OF u SKIP LOCKED`},
+		{`SELECT a FROM t ORDER BY a LIMIT 1 FOR UPDATE`},
+		{`WITH a AS (SELECT 1) SELECT * FROM t FOR UPDATE`},
+		{`SELECT * FROM t WHERE a = (SELECT b FROM u FOR SHARE)`},
 		{`SELECT DISTINCT * FROM t`},
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SELECT DISTINCT ON (a, b) c FROM t`},
@@ -972,6 +1020,9 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a, b DEFERRED`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
@@ -1092,6 +1143,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP STORED`},
+		{`ALTER TABLE a ALTER PRIMARY KEY USING COLUMNS (b, c DESC)`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
 		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE STRING COLLATE en USING b::STRING`},
@@ -1223,6 +1275,11 @@ func TestParse(t *testing.T) {
 		{`COMMENT ON DATABASE foo IS 'a'`},
 		{`COMMENT ON DATABASE foo IS NULL`},
 
+		{`ALTER TYPE a ADD VALUE 'b'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b'`},
+		{`ALTER TYPE a ADD VALUE 'b' BEFORE 'a'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b' AFTER 'a'`},
+
 		{`ALTER SEQUENCE a RENAME TO b`},
 		{`EXPLAIN ALTER SEQUENCE a RENAME TO b`},
 		{`ALTER SEQUENCE IF EXISTS a RENAME TO b`},
@@ -1293,6 +1350,8 @@ func TestParse(t *testing.T) {
 		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM TABLE a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
+		{`EXPORT INTO PARQUET 'a' WITH compression = 'snappy' FROM TABLE a`},
+		{`EXPORT INTO JSON 'a' WITH chunk_rows = '100' FROM SELECT * FROM a`},
 
 		{`SET ROW (1, true, NULL)`},
 
@@ -1305,6 +1364,9 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED INTO 'sink' AS SELECT a, b + 1 AS c FROM foo`},
+		{`CREATE CHANGEFEED INTO 'sink' WITH bar = 'baz' AS SELECT * FROM db.foo WHERE a > 1`},
+		{`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo WHERE b`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -1335,8 +1397,24 @@ func TestParse2(t *testing.T) {
 	}{
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
+		{`CREATE FUNCTION f(a INT, b TEXT) RETURNS INTEGER LANGUAGE SQL AS 'SELECT a'`,
+			`CREATE FUNCTION f(a INT8, b STRING) RETURNS INT8 LANGUAGE sql AS 'SELECT a'`},
+		{`CREATE TRIGGER t AFTER UPDATE ON a EXECUTE 'SELECT 1'`,
+			`CREATE TRIGGER t AFTER UPDATE ON a FOR EACH STATEMENT EXECUTE 'SELECT 1'`},
+		{`CREATE TRIGGER t BEFORE DELETE ON a FOR ROW EXECUTE 'SELECT 1'`,
+			`CREATE TRIGGER t BEFORE DELETE ON a FOR EACH ROW EXECUTE 'SELECT 1'`},
+		{`SELECT 'f'::"blah", foo''`,
+			`SELECT 'f'::blah, foo ''`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
+		{`CREATE TEMP TABLE a (b INT8)`,
+			`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE LOCAL TEMP TABLE a (b INT8)`,
+			`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE GLOBAL TEMPORARY TABLE a AS SELECT * FROM b`,
+			`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
+		{`CREATE UNLOGGED TABLE a (b INT8)`,
+			`CREATE TABLE a (b INT8)`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
 			`CREATE DATABASE a TEMPLATE = 'invalid'`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
@@ -1519,6 +1597,13 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM t LIMIT 2 * a OFFSET b`},
 		{`SELECT a FROM t FETCH FIRST (2 * a) ROWS ONLY OFFSET b`,
 			`SELECT a FROM t LIMIT 2 * a OFFSET b`},
+		// We allow the locking clause before LIMIT, but always output it last.
+		{`SELECT a FROM t FOR UPDATE LIMIT 1`,
+			`SELECT a FROM t LIMIT 1 FOR UPDATE`},
+		{`SELECT a FROM t FOR UPDATE OFFSET 1`,
+			`SELECT a FROM t OFFSET 1 FOR UPDATE`},
+		{`SELECT a FROM t FOR READ ONLY`,
+			`SELECT a FROM t`},
 		// Double negation. See #1800.
 		{`SELECT *,-/* comment */-5`,
 			`SELECT *, 5`},
@@ -2003,6 +2088,22 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH SIMPLE ON DELETE SET NULL ON UPDATE RESTRICT)`,
 			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE SET NULL ON UPDATE RESTRICT)`,
 		},
+		{
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`,
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, UNIQUE (b) INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, UNIQUE (b) DEFERRABLE INITIALLY DEFERRED)`,
+		},
 		{
 			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other MATCH SIMPLE)`,
 			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other)`,
@@ -2285,11 +2386,6 @@ DETAIL: source SQL:
 SELECT 1e-
        ^
 HINT: try \h SELECT`},
-		{"SELECT foo''",
-			`at or near "": syntax error: type does not exist
-DETAIL: source SQL:
-SELECT foo''
-          ^`},
 		{
 			`SELECT 0x FROM t`,
 			`lexical error: invalid hexadecimal numeric literal
@@ -2396,20 +2492,6 @@ ALTER TABLE t RENAME COLUMN x TO family
                                  ^
 HINT: try \h ALTER TABLE`,
 		},
-		{
-			`SELECT CAST(1.2+2.3 AS notatype)`,
-			`at or near "notatype": syntax error: type does not exist
-DETAIL: source SQL:
-SELECT CAST(1.2+2.3 AS notatype)
-                       ^`,
-		},
-		{
-			`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`,
-			`at or near "notatype": syntax error: type does not exist
-DETAIL: source SQL:
-SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
-                              ^`,
-		},
 		{
 			`CREATE USER foo WITH PASSWORD`,
 			`at or near "EOF": syntax error
@@ -2476,13 +2558,6 @@ DETAIL: source SQL:
 SELECT 1 + ANY ARRAY[1, 2, 3]
                              ^`,
 		},
-		{
-			`SELECT 'f'::"blah"`,
-			`at or near "blah": syntax error: type does not exist
-DETAIL: source SQL:
-SELECT 'f'::"blah"
-            ^`,
-		},
 		// Ensure that the support for ON ROLE <namelist> doesn't leak
 		// where it should not be recognized.
 		{
@@ -2890,8 +2965,7 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE EXTENSION a`, 0, `create extension a`},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`},
-		{`CREATE FUNCTION a`, 17511, `create`},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`},
+		{`CREATE OR REPLACE FUNCTION a`, 17511, `create or replace function`},
 		{`CREATE LANGUAGE a`, 17511, `create language a`},
 		{`CREATE MATERIALIZED VIEW a`, 24747, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`},
@@ -2901,7 +2975,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE SERVER a`, 0, `create server`},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`},
-		{`CREATE TRIGGER a`, 28296, `create`},
 
 		{`DROP AGGREGATE a`, 0, `drop aggregate`},
 		{`DROP CAST a`, 0, `drop cast`},
@@ -2911,7 +2984,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`},
-		{`DROP FUNCTION a`, 17511, `drop `},
 		{`DROP LANGUAGE a`, 17511, `drop language a`},
 		{`DROP OPERATOR a`, 0, `drop operator`},
 		{`DROP PUBLICATION a`, 0, `drop publication`},
@@ -2920,7 +2992,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP SERVER a`, 0, `drop server`},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`},
-		{`DROP TRIGGER a`, 28296, `drop`},
 		{`DROP TYPE a`, 27793, `drop type`},
 
 		{`DISCARD PLANS`, 0, `discard plans`},
@@ -2928,12 +2999,9 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DISCARD TEMP`, 0, `discard temp`},
 		{`DISCARD TEMPORARY`, 0, `discard temp`},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`},
 		{`SET LOCAL foo = bar`, 32562, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`},
 
-		{`CREATE TEMP TABLE a(b INT8)`, 5807, ``},
-		{`CREATE UNLOGGED TABLE a(b INT8)`, 0, `create unlogged`},
 		{`CREATE TEMP VIEW a AS SELECT b`, 5807, ``},
 		{`CREATE TEMP SEQUENCE a`, 5807, ``},
 
@@ -2952,14 +3020,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`},
-
 		{`CREATE SEQUENCE a AS DOUBLE PRECISION`, 25110, `FLOAT8`},
 		{`CREATE SEQUENCE a OWNED BY b`, 26382, ``},
 
@@ -2967,7 +3027,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`},
 
 		{`CREATE TYPE a AS (b)`, 27792, ``},
-		{`CREATE TYPE a AS ENUM (b)`, 24873, ``},
 		{`CREATE TYPE a AS RANGE b`, 27791, ``},
 		{`CREATE TYPE a (b)`, 27793, `base`},
 		{`CREATE TYPE a`, 27793, `shell`},
@@ -2986,7 +3045,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
 		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``},
 
-		{`SELECT * FROM a FOR UPDATE`, 6583, ``},
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`},
 
 		{`SELECT 123 AT TIME ZONE 'b'`, 32005, ``},
@@ -3042,7 +3100,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`INSERT INTO a VALUES (1) ON CONFLICT (x) WHERE x > 3 DO NOTHING`, 32557, ``},
 
-		{`WITH RECURSIVE a AS (TABLE b) SELECT c`, 21085, ``},
 
 		{`UPDATE foo SET (a, a.b) = (1, 2)`, 27792, ``},
 		{`UPDATE foo SET a.b = 1`, 27792, ``},
@@ -879,7 +879,9 @@ func Tokens(sql string) (tokens []TokenString, ok bool) {
 		if lval.id == 0 {
 			break
 		}
-		tokens = append(tokens, TokenString{TokenID: lval.id, Str: lval.str})
+		tokens = append(tokens, TokenString{
+			TokenID: lval.id, Str: lval.str, Start: int(lval.pos), End: s.pos,
+		})
 	}
 	return tokens, true
 }
@@ -888,6 +890,8 @@ func Tokens(sql string) (tokens []TokenString, ok bool) {
 type TokenString struct {
 	TokenID int32
 	Str     string
+	// Start and End are the byte offsets of the token in the input string.
+	Start, End int
 }
 
 // LastLexicalToken returns the last lexical token. If the string has no lexical
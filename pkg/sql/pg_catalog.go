@@ -544,7 +544,7 @@ CREATE TABLE pg_catalog.pg_class (
 					tree.DBoolFalse, // relhasoids
 					tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // relhaspkey
 					tree.DBoolFalse, // relhasrules
-					tree.DBoolFalse, // relhastriggers
+					tree.MakeDBool(tree.DBool(len(table.Triggers) > 0)), // relhastriggers
 					tree.DBoolFalse, // relhassubclass
 					zeroVal,         // relfrozenxid
 					tree.DNull,      // relacl
@@ -732,6 +732,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 				consrc := tree.DNull
 				conbin := tree.DNull
 				condef := tree.DNull
+				condeferrable := tree.DBoolFalse
+				condeferred := tree.DBoolFalse
 
 				// Determine constraint kind-specific fields.
 				var err error
@@ -766,6 +768,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 					if r, ok := fkMatchMap[con.FK.Match]; ok {
 						confmatchtype = r
 					}
+					condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+					condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 					columnIDs := con.Index.ColumnIDs
 					if int(con.FK.SharedPrefixLen) > len(columnIDs) {
 						return errors.AssertionFailedf(
@@ -799,10 +803,17 @@ CREATE TABLE pg_catalog.pg_constraint (
 					if conkey, err = colIDArrayToDatum(con.Index.ColumnIDs); err != nil {
 						return err
 					}
+					condeferrable = tree.MakeDBool(tree.DBool(con.Index.DeferrableUnique))
+					condeferred = tree.MakeDBool(tree.DBool(con.Index.InitiallyDeferred))
 					f := tree.NewFmtCtx(tree.FmtSimple)
 					f.WriteString("UNIQUE (")
 					con.Index.ColNamesFormat(f)
 					f.WriteByte(')')
+					if con.Index.InitiallyDeferred {
+						f.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+					} else if con.Index.DeferrableUnique {
+						f.WriteString(" DEFERRABLE INITIALLY IMMEDIATE")
+					}
 					condef = tree.NewDString(f.CloseAndGetString())
 
 				case sqlbase.ConstraintTypeCheck:
@@ -821,8 +832,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 					dNameOrNull(conName), // conname
 					namespaceOid,         // connamespace
 					contype,              // contype
-					tree.DBoolFalse,      // condeferrable
-					tree.DBoolFalse,      // condeferred
+					condeferrable,        // condeferrable
+					condeferred,          // condeferred
 					tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 					tblOid,         // conrelid
 					oidZero,        // contypid
@@ -1112,7 +1123,7 @@ CREATE TABLE pg_catalog.pg_shdescription (
 }
 
 var pgCatalogEnumTable = virtualSchemaTable{
-	comment: `enum types and labels (empty - feature does not exist)
+	comment: `enum types and labels
 https://www.postgresql.org/docs/9.5/catalog-pg-enum.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_enum (
@@ -1121,9 +1132,23 @@ CREATE TABLE pg_catalog.pg_enum (
   enumsortorder FLOAT,
   enumlabel STRING
 )`,
-	populate: func(_ context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Enum types are not currently supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTypeDesc(ctx, p, dbContext, func(_ *DatabaseDescriptor, typDesc *sqlbase.TypeDescriptor) error {
+			typOid := tree.NewDOid(tree.DInt(types.StableTypeIDToOID(uint32(typDesc.ID))))
+			for i := range typDesc.EnumMembers {
+				member := &typDesc.EnumMembers[i]
+				if err := addRow(
+					h.EnumEntryOid(typOid, member.PhysicalRepresentation), // oid
+					typOid, // enumtypid
+					tree.NewDFloat(tree.DFloat(float64(i+1))),     // enumsortorder
+					tree.NewDString(member.LogicalRepresentation), // enumlabel
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	},
 }
 
@@ -1286,7 +1311,7 @@ CREATE TABLE pg_catalog.pg_index (
 						h.IndexOid(db, scName, table, index), // indexrelid
 						tableOid,                             // indrelid
 						tree.NewDInt(tree.DInt(len(index.ColumnNames))),                                          // indnatts
-						tree.MakeDBool(tree.DBool(index.Unique)),                                                 // indisunique
+						tree.MakeDBool(tree.DBool(index.Unique || index.DeferrableUnique)),                       // indisunique
 						tree.MakeDBool(tree.DBool(table.IsPhysicalTable() && index.ID == table.PrimaryIndex.ID)), // indisprimary
 						tree.DBoolFalse,                          // indisexclusion
 						tree.MakeDBool(tree.DBool(index.Unique)), // indimmediate
@@ -1980,7 +2005,7 @@ CREATE TABLE pg_catalog.pg_tables (
 					tree.DNull,                // tablespace
 					tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // hasindexes
 					tree.DBoolFalse, // hasrules
-					tree.DBoolFalse, // hastriggers
+					tree.MakeDBool(tree.DBool(len(table.Triggers) > 0)), // hastriggers
 					tree.DBoolFalse, // rowsecurity
 				)
 			})
@@ -2012,7 +2037,7 @@ CREATE TABLE pg_catalog.pg_tablespace (
 }
 
 var pgCatalogTriggerTable = virtualSchemaTable{
-	comment: `triggers (empty - feature does not exist)
+	comment: `triggers (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-trigger.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_trigger (
@@ -2036,8 +2061,58 @@ CREATE TABLE pg_catalog.pg_trigger (
 	tgnewtable NAME
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Triggers are unsupported.
-		return nil
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables have no triggers */
+			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
+				for i := range table.Triggers {
+					trigger := &table.Triggers[i]
+					// The bits of tgtype are defined by TRIGGER_TYPE_* in
+					// src/include/catalog/pg_trigger.h in Postgres.
+					var tgtype tree.DInt
+					if trigger.ForEachRow {
+						tgtype |= 1 << 0
+					}
+					if trigger.Before {
+						tgtype |= 1 << 1
+					}
+					if trigger.OnInsert {
+						tgtype |= 1 << 2
+					}
+					if trigger.OnDelete {
+						tgtype |= 1 << 3
+					}
+					if trigger.OnUpdate {
+						tgtype |= 1 << 4
+					}
+					tgattr, err := colIDArrayToVector(trigger.UpdateColumnIDs)
+					if err != nil {
+						return err
+					}
+					if err := addRow(
+						h.TriggerOid(table, trigger), // oid
+						defaultOid(table.ID),         // tgrelid
+						tree.NewDName(trigger.Name),  // tgname
+						oidZero,                      // tgfoid
+						tree.NewDInt(tgtype),         // tgtype
+						tree.NewDString("O"),         // tgenabled
+						tree.DBoolFalse,              // tgisinternal
+						oidZero,                      // tgconstrrelid
+						oidZero,                      // tgconstrindid
+						oidZero,                      // tgconstraint
+						tree.DBoolFalse,              // tgdeferrable
+						tree.DBoolFalse,              // tginitdeferred
+						zeroVal,                      // tgnargs
+						tgattr,                       // tgattr
+						tree.DNull,                   // tgargs
+						tree.DNull,                   // tgqual
+						tree.DNull,                   // tgoldtable
+						tree.DNull,                   // tgnewtable
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
 }
 
@@ -2052,7 +2127,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typTypeComposite
 	_ = typTypeDomain
-	_ = typTypeEnum
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -2075,7 +2149,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = typCategoryComposite
-	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
 	_ = typCategoryBitString
@@ -2122,7 +2195,7 @@ CREATE TABLE pg_catalog.pg_type (
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		h := makeOidHasher()
-		return forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
+		if err := forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
 			nspOid := h.NamespaceOid(db, pgCatalogName)
 
 			for o, typ := range types.OidToType {
@@ -2197,6 +2270,49 @@ CREATE TABLE pg_catalog.pg_type (
 				}
 			}
 			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Add a row for each user-defined enum type.
+		return forEachTypeDesc(ctx, p, dbContext, func(db *DatabaseDescriptor, typDesc *sqlbase.TypeDescriptor) error {
+			nspOid := h.NamespaceOid(db, tree.PublicSchema)
+			return addRow(
+				tree.NewDOid(tree.DInt(types.StableTypeIDToOID(uint32(typDesc.ID)))), // oid
+				tree.NewDName(typDesc.Name), // typname
+				nspOid,                      // typnamespace
+				tree.DNull,                  // typowner
+				negOneVal,                   // typlen
+				tree.DBoolFalse,             // typbyval
+				typTypeEnum,                 // typtype
+				typCategoryEnum,             // typcategory
+				tree.DBoolFalse,             // typispreferred
+				tree.DBoolTrue,              // typisdefined
+				typDelim,                    // typdelim
+				oidZero,                     // typrelid
+				oidZero,                     // typelem
+				oidZero,                     // typarray
+
+				// regproc references
+				h.RegProc("enum_in"),   // typinput
+				h.RegProc("enum_out"),  // typoutput
+				h.RegProc("enum_recv"), // typreceive
+				h.RegProc("enum_send"), // typsend
+				oidZero,                // typmodin
+				oidZero,                // typmodout
+				oidZero,                // typanalyze
+
+				tree.DNull,      // typalign
+				tree.DNull,      // typstorage
+				tree.DBoolFalse, // typnotnull
+				oidZero,         // typbasetype
+				negOneVal,       // typtypmod
+				zeroVal,         // typndims
+				oidZero,         // typcollation
+				tree.DNull,      // typdefaultbin
+				tree.DNull,      // typdefault
+				tree.DNull,      // typacl
+			)
 		})
 	},
 }
@@ -2371,6 +2487,7 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.IntervalFamily:    typCategoryTimespan,
 	types.JsonFamily:        typCategoryUserDefined,
 	types.DecimalFamily:     typCategoryNumeric,
+	types.EnumFamily:        typCategoryEnum,
 	types.StringFamily:      typCategoryString,
 	types.TimestampFamily:   typCategoryDateTime,
 	types.TimestampTZFamily: typCategoryDateTime,
@@ -2498,6 +2615,8 @@ const (
 	userTypeTag
 	collationTypeTag
 	operatorTypeTag
+	enumEntryTypeTag
+	triggerTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2619,6 +2738,15 @@ func (h oidHasher) UniqueConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) TriggerOid(
+	table *sqlbase.TableDescriptor, trigger *sqlbase.TableDescriptor_Trigger,
+) *tree.DOid {
+	h.writeTypeTag(triggerTypeTag)
+	h.writeTable(table)
+	h.writeStr(trigger.Name)
+	return h.getOid()
+}
+
 func (h oidHasher) BuiltinOid(name string, builtin *tree.Overload) *tree.DOid {
 	h.writeTypeTag(functionTypeTag)
 	h.writeStr(name)
@@ -2656,6 +2784,13 @@ func (h oidHasher) OperatorOid(name string, leftType, rightType, returnType *tre
 	return h.getOid()
 }
 
+func (h oidHasher) EnumEntryOid(typOid *tree.DOid, physicalRep []byte) *tree.DOid {
+	h.writeTypeTag(enumEntryTypeTag)
+	h.writeOID(typOid)
+	h.writeStr(string(physicalRep))
+	return h.getOid()
+}
+
 func defaultOid(id sqlbase.ID) *tree.DOid {
 	return tree.NewDOid(tree.DInt(id))
 }
@@ -629,6 +629,16 @@ func (c *conn) sendInitialConnData(
 		return sql.ConnectionHandler{}, err
 	}
 
+	// Send the key that the client can use to cancel the queries running on
+	// this connection. See Server.handleCancel.
+	cancelKey := connHandler.GetCancelKey()
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgBackendKeyData)
+	c.msgBuilder.putInt32(int32(cancelKey.NodeID))
+	c.msgBuilder.putInt32(int32(cancelKey.Secret))
+	if err := c.msgBuilder.finishMsg(c.conn); err != nil {
+		return sql.ConnectionHandler{}, err
+	}
+
 	// An initial readyForQuery message is part of the handshake.
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(byte(sql.IdleTxnBlock))
@@ -2303,11 +2303,117 @@ func TestCancelRequest(t *testing.T) {
 	if _, err := fe.Receive(); err != io.EOF {
 		t.Fatalf("unexpected: %v", err)
 	}
-	if count := telemetry.GetRawFeatureCounts()["pgwire.unimplemented.cancel_request"]; count != 1 {
+	if count := telemetry.GetRawFeatureCounts()["pgwire.cancel_request"]; count != 1 {
 		t.Fatalf("expected 1 cancel request, got %d", count)
 	}
 }
 
+// TestCancelRequestCancelsQuery verifies that a CancelRequest carrying the key
+// from a session's BackendKeyData message cancels the query running on that
+// session, even when the request is sent to another node.
+func TestCancelRequestCancelsQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.TODO()
+	tc := serverutils.StartTestCluster(t, 2, /* numNodes */
+		base.TestClusterArgs{
+			ReplicationMode: base.ReplicationManual,
+			ServerArgs:      base.TestServerArgs{Insecure: true},
+		})
+	defer tc.Stopper().Stop(ctx)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", tc.Server(1).ServingAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fe, err := pgproto3.NewFrontend(conn, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fe.Send(&pgproto3.StartupMessage{
+		ProtocolVersion: 196608, // Version 3.0
+		Parameters: map[string]string{
+			"user": security.RootUser,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var keyData *pgproto3.BackendKeyData
+	for {
+		msg, err := fe.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kd, ok := msg.(*pgproto3.BackendKeyData); ok {
+			keyData = &pgproto3.BackendKeyData{ProcessID: kd.ProcessID, SecretKey: kd.SecretKey}
+		}
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			break
+		}
+	}
+	if keyData == nil {
+		t.Fatal("no BackendKeyData received during the handshake")
+	}
+	if nodeID := tc.Server(1).NodeID(); keyData.ProcessID != uint32(nodeID) {
+		t.Fatalf("expected process ID %d, got %d", nodeID, keyData.ProcessID)
+	}
+
+	const queryToCancel = "SELECT pg_sleep(600)"
+	if err := fe.Send(&pgproto3.Query{String: queryToCancel}); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		sqlDB.QueryRow(t,
+			`SELECT count(*) FROM [SHOW CLUSTER QUERIES] WHERE query = $1`, queryToCancel,
+		).Scan(&count)
+		if count != 1 {
+			return errors.Errorf("expected query to be running, found %d instances", count)
+		}
+		return nil
+	})
+
+	// Send the cancel request to the node that does not own the session, so
+	// that it has to be forwarded.
+	cancelConn, err := d.DialContext(ctx, "tcp", tc.Server(0).ServingAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelConn.Close()
+	const versionCancel = 80877102
+	var cancelReq []byte
+	for _, v := range []uint32{16, versionCancel, keyData.ProcessID, keyData.SecretKey} {
+		cancelReq = append(cancelReq, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+	if _, err := cancelConn.Write(cancelReq); err != nil {
+		t.Fatal(err)
+	}
+	// The server closes the connection once it has processed the request.
+	if _, err := ioutil.ReadAll(cancelConn); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		msg, err := fe.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errMsg, ok := msg.(*pgproto3.ErrorResponse); ok {
+			if !strings.Contains(errMsg.Message, "query execution canceled") {
+				t.Fatalf("unexpected error: %s", errMsg.Message)
+			}
+			break
+		}
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			t.Fatal("query completed without being canceled")
+		}
+	}
+}
+
 func TestFailPrepareFailsTxn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -493,7 +494,7 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 	if version != version30 {
 		if version == versionCancel {
 			telemetry.Inc(sqltelemetry.CancelRequestCounter)
-			_ = conn.Close()
+			s.handleCancel(ctx, conn, &buf)
 			return nil
 		}
 		return sendErr(fmt.Errorf("unknown protocol version %d", version))
@@ -547,6 +548,38 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 	return nil
 }
 
+// handleCancel serves a pgwire CancelRequest. The request carries the
+// sql.CancelKey that the client received in the BackendKeyData message when its
+// session started, and the queries running on that session are canceled
+// wherever in the cluster the session lives. As mandated by the protocol,
+// nothing is sent back to the client; the connection is simply closed.
+func (s *Server) handleCancel(ctx context.Context, conn net.Conn, buf *pgwirebase.ReadBuffer) {
+	defer func() { _ = conn.Close() }()
+
+	nodeID, err := buf.GetUint32()
+	if err != nil {
+		log.Warningf(ctx, "malformed cancel request: %v", err)
+		return
+	}
+	secret, err := buf.GetUint32()
+	if err != nil {
+		log.Warningf(ctx, "malformed cancel request: %v", err)
+		return
+	}
+
+	resp, err := s.execCfg.StatusServer.CancelQueryByKey(ctx, &serverpb.CancelQueryByKeyRequest{
+		NodeId: fmt.Sprintf("%d", nodeID),
+		Secret: secret,
+	})
+	if err != nil {
+		log.Warningf(ctx, "error serving cancel request for node %d: %v", nodeID, err)
+		return
+	}
+	if !resp.Canceled {
+		log.VEventf(ctx, 2, "cancel request for node %d had no effect: %s", nodeID, resp.Error)
+	}
+}
+
 // -1 for the sentinel in case someone wants to set it to 0.
 const connResultsBufferSizeUnsetSentinel = -1
 
@@ -140,6 +140,10 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		// Enum values are sent as their labels.
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DDate:
 		s := v.Date.String()
 		b.putInt32(int32(len(s)))
@@ -399,6 +403,10 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		// Enum values are sent as their labels.
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DTimestamp:
 		b.putInt32(8)
 		b.putInt64(timeToPgBinary(v.Time, nil))
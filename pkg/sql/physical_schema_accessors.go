@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -75,8 +76,10 @@ func (a UncachedPhysicalAccessor) GetDatabaseDesc(
 
 // IsValidSchema implements the SchemaAccessor interface.
 func (a UncachedPhysicalAccessor) IsValidSchema(dbDesc *DatabaseDescriptor, scName string) bool {
-	// At this point, only the public schema is recognized.
-	return scName == tree.PublicSchema
+	// At this point, only the public schema and the temporary schemas are
+	// recognized.
+	return scName == tree.PublicSchema || scName == sessiondata.PgTempSchemaName ||
+		sessiondata.IsTemporarySchemaName(scName)
 }
 
 // GetObjectNames implements the SchemaAccessor interface.
@@ -95,21 +98,52 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 		return nil, nil
 	}
 
+	// Objects in a temporary schema are stored under the ID of the schema.
+	parentID := dbDesc.ID
+	if scName != tree.PublicSchema {
+		var err error
+		parentID, err = getTemporarySchemaID(ctx, txn, dbDesc.ID, scName)
+		if err != nil || parentID == sqlbase.InvalidID {
+			return nil, err
+		}
+	}
+
 	log.Eventf(ctx, "fetching list of objects for %q", dbDesc.Name)
-	prefix := sqlbase.MakeNameMetadataKey(dbDesc.ID, "")
+	prefix := sqlbase.MakeNameMetadataKey(parentID, "")
 	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
 	if err != nil {
 		return nil, err
 	}
 
-	var tableNames tree.TableNames
+	var names []string
+	var ids []sqlbase.ID
 	for _, row := range sr {
 		_, tableName, err := encoding.DecodeUnsafeStringAscending(
 			bytes.TrimPrefix(row.Key, prefix), nil)
 		if err != nil {
 			return nil, err
 		}
-		tn := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(tableName))
+		if scName == tree.PublicSchema && sessiondata.IsTemporarySchemaName(tableName) {
+			// The temporary schemas are stored alongside the objects of the
+			// public schema.
+			continue
+		}
+		names = append(names, tableName)
+		ids = append(ids, sqlbase.ID(row.ValueInt()))
+	}
+
+	// User-defined types and functions share the namespace of tables, but are
+	// not objects that can be listed alongside them.
+	descs, err := getDescriptorsFromIDs(ctx, txn, ids)
+	if err != nil {
+		return nil, err
+	}
+	var tableNames tree.TableNames
+	for i, tableName := range names {
+		if descs[i] != nil && descs[i].GetTable() == nil {
+			continue
+		}
+		tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(tableName))
 		tn.ExplicitCatalog = flags.explicitPrefix
 		tn.ExplicitSchema = flags.explicitPrefix
 		tableNames = append(tableNames, tn)
@@ -121,8 +155,9 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 func (a UncachedPhysicalAccessor) GetObjectDesc(
 	ctx context.Context, txn *client.Txn, name *ObjectName, flags ObjectLookupFlags,
 ) (ObjectDescriptor, error) {
-	// At this point, only the public schema is recognized.
-	if name.Schema() != tree.PublicSchema {
+	// At this point, only the public schema and the temporary schemas are
+	// recognized.
+	if name.Schema() != tree.PublicSchema && !sessiondata.IsTemporarySchemaName(name.Schema()) {
 		if flags.required {
 			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(name))
 		}
@@ -136,13 +171,28 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 		return nil, err
 	}
 
+	// Objects in a temporary schema are stored under the ID of the schema.
+	parentID := dbID
+	if name.Schema() != tree.PublicSchema {
+		parentID, err = getTemporarySchemaID(ctx, txn, dbID, name.Schema())
+		if err != nil {
+			return nil, err
+		}
+		if parentID == sqlbase.InvalidID {
+			if flags.required {
+				return nil, sqlbase.NewUndefinedRelationError(name)
+			}
+			return nil, nil
+		}
+	}
+
 	// Try to use the system name resolution bypass. This avoids a hotspot.
 	// Note: we can only bypass name to ID resolution. The desc
 	// lookup below must still go through KV because system descriptors
 	// can be modified on a running cluster.
-	descID := sqlbase.LookupSystemTableDescriptorID(dbID, name.Table())
+	descID := sqlbase.LookupSystemTableDescriptorID(parentID, name.Table())
 	if descID == sqlbase.InvalidID {
-		descID, err = getDescriptorID(ctx, txn, sqlbase.NewTableKey(dbID, name.Table()))
+		descID, err = getDescriptorID(ctx, txn, sqlbase.NewTableKey(parentID, name.Table()))
 		if err != nil {
 			return nil, err
 		}
@@ -32,6 +32,11 @@ func planColumns(plan planNode) sqlbase.ResultColumns {
 	return getPlanColumns(plan, false)
 }
 
+// PlanColumns is the exported version of planColumns. Useful for CCL hooks.
+func PlanColumns(plan PlanNode) sqlbase.ResultColumns {
+	return planColumns(plan)
+}
+
 // planMutableColumns is similar to planColumns() but returns a
 // ResultColumns slice that can be modified by the caller.
 func planMutableColumns(plan planNode) sqlbase.ResultColumns {
@@ -120,6 +125,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 	// valueNode helper.
 	case *bufferNode:
 		return getPlanColumns(n.plan, mut)
+	case *recursiveCTENode:
+		return getPlanColumns(n.initial, mut)
 	case *distinctNode:
 		return getPlanColumns(n.plan, mut)
 	case *filterNode:
@@ -91,6 +91,7 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *applyJoinNode:
 	case *bufferNode:
 	case *scanBufferNode:
+	case *recursiveCTENode:
 
 	// Every other node simply has no guarantees on its output rows.
 	case *CreateUserNode:
@@ -98,6 +99,7 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *alterIndexNode:
 	case *alterSequenceNode:
 	case *alterTableNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *cancelQueriesNode:
 	case *cancelSessionsNode:
@@ -107,16 +109,21 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *controlJobsNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createFunctionNode:
 	case *createSequenceNode:
 	case *createStatsNode:
+	case *createTypeNode:
 	case *createTableNode:
+	case *createTriggerNode:
 	case *createViewNode:
 	case *delayedNode:
 	case *deleteRangeNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropFunctionNode:
 	case *dropSequenceNode:
 	case *dropTableNode:
+	case *dropTriggerNode:
 	case *dropViewNode:
 	case *errorIfRowsNode:
 	case *explainDistSQLNode:
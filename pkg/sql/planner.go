@@ -40,6 +40,9 @@ type extendedEvalContext struct {
 
 	SessionMutator *sessionDataMutator
 
+	// SessionID is the ID of the session running the statement.
+	SessionID ClusterWideID
+
 	// VirtualSchemas can be used to access virtual tables.
 	VirtualSchemas VirtualTabler
 
@@ -65,6 +68,11 @@ type extendedEvalContext struct {
 
 	SchemaChangers *schemaChangerCollection
 
+	// DeferredChecks accumulates the constraint checks deferred to the end of
+	// the transaction. It is nil for implicit transactions, in which all the
+	// checks are performed by the end of the statement.
+	DeferredChecks *row.DeferredChecks
+
 	schemaAccessors *schemaInterface
 }
 
@@ -134,6 +142,11 @@ type planner struct {
 	// want to do 1PC transactions have to implement the autoCommitNode interface.
 	autoCommit bool
 
+	// boundedStaleness is set if the statement performs a bounded staleness
+	// read, in which case semaCtx.AsOfTimestamp is the minimum timestamp bound
+	// of the read.
+	boundedStaleness bool
+
 	// discardRows is set if we want to discard any results rather than sending
 	// them back to the client. Used for testing/benchmarking. Note that the
 	// resulting schema or the plan are not affected.
@@ -247,6 +260,8 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
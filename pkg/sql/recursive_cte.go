@@ -0,0 +1,154 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// recursiveCTENode implements the logic for a recursive CTE. The initial
+// query is evaluated first; its results are emitted and also saved in a
+// "working" table. Then, so long as the working table is not empty, the
+// recursive query is evaluated with the current contents of the working table
+// substituted for the recursive self-reference; the resulting rows are emitted
+// and saved as the next iteration's working table.
+//
+// The recursive query tree is regenerated each time using a callback
+// (implemented by the execbuilder).
+//
+// The working tables are backed by disk once they no longer fit in memory.
+type recursiveCTENode struct {
+	initial planNode
+
+	genIterationFn exec.RecursiveCTEIterationFn
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
+
+	run recursiveCTERun
+}
+
+type recursiveCTERun struct {
+	// workingRows contains the rows produced by the last iteration (or by the
+	// initial query), which are being emitted.
+	workingRows rowContainerHelper
+	// nextRows is used to accumulate the rows produced by the current
+	// iteration. It becomes the working table once the iteration is done.
+	nextRows rowContainerHelper
+
+	iterator   *rowContainerIterator
+	currentRow tree.Datums
+
+	initialDone bool
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	typs := planTypes(n.initial)
+	n.run.workingRows.init(typs, params.extendedEvalCtx, "cte" /* opName */)
+	n.run.nextRows.init(typs, params.extendedEvalCtx, "cte" /* opName */)
+	return nil
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	if err := params.p.cancelChecker.Check(); err != nil {
+		return false, err
+	}
+
+	if !n.run.initialDone {
+		// The rows of the initial query form the first working table.
+		for {
+			ok, err := n.initial.Next(params)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				break
+			}
+			if err := n.run.workingRows.addRow(params.ctx, n.initial.Values()); err != nil {
+				return false, err
+			}
+		}
+		n.run.initialDone = true
+		n.run.iterator = newRowContainerIterator(params.ctx, n.run.workingRows)
+	}
+
+	for n.run.iterator != nil {
+		row, err := n.run.iterator.next()
+		if err != nil {
+			return false, err
+		}
+		if row != nil {
+			n.run.currentRow = row
+			return true, nil
+		}
+		n.run.iterator.close()
+		n.run.iterator = nil
+
+		// All the rows of the working table have been emitted. We are done if
+		// the working table is empty; otherwise, run the next iteration.
+		if n.run.workingRows.len() == 0 {
+			break
+		}
+		if err := n.runIteration(params); err != nil {
+			return false, err
+		}
+		n.run.iterator = newRowContainerIterator(params.ctx, n.run.workingRows)
+	}
+	return false, nil
+}
+
+// runIteration generates and runs the plan for the next iteration, which reads
+// the current working table. The rows it produces become the new working
+// table.
+func (n *recursiveCTENode) runIteration(params runParams) error {
+	if err := n.run.nextRows.clear(params.ctx); err != nil {
+		return err
+	}
+
+	buf := &bufferNode{
+		// The plan here is only used for its columns, which are the same for the
+		// initial and the recursive queries.
+		plan:         n.initial,
+		bufferedRows: n.run.workingRows,
+		label:        n.label,
+	}
+	newPlan, err := n.genIterationFn(buf)
+	if err != nil {
+		return err
+	}
+
+	resultWriter := newCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+		return n.run.nextRows.addRow(ctx, row)
+	})
+	if err := runPlanInsidePlan(params, newPlan.(*planTop), resultWriter); err != nil {
+		return err
+	}
+
+	n.run.workingRows, n.run.nextRows = n.run.nextRows, n.run.workingRows
+	return nil
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	return n.run.currentRow
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	n.initial.Close(ctx)
+	if n.run.iterator != nil {
+		n.run.iterator.close()
+		n.run.iterator = nil
+	}
+	n.run.workingRows.close(ctx)
+	n.run.nextRows.close(ctx)
+}
@@ -156,6 +156,17 @@ func (p *planner) renameColumn(
 		}
 	}
 
+	// Rename the column in the NEW and OLD references of triggers.
+	for i := range tableDesc.Triggers {
+		var err error
+		tableDesc.Triggers[i].Statement, err = renameTriggerRowRefs(
+			tableDesc.Triggers[i].Statement, *oldName, *newName,
+		)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnDescriptor(col, string(*newName))
 
@@ -86,6 +86,15 @@ type renderNode struct {
 	_ util.NoCopy
 }
 
+// checkNoLocking returns an error if the locking clause is not empty.
+func checkNoLocking(locking tree.LockingClause) error {
+	if len(locking) > 0 {
+		return unimplemented.Newf("select.locking",
+			"%s is only supported by the cost-based optimizer", locking[0].Strength)
+	}
+	return nil
+}
+
 // Select selects rows from a SELECT/UNION/VALUES, ordering and/or limiting them.
 func (p *planner) Select(
 	ctx context.Context, n *tree.Select, desiredTypes []*types.T,
@@ -95,7 +104,16 @@ func (p *planner) Select(
 	orderBy := n.OrderBy
 	with := n.With
 
+	// Row-level locking is only supported by the optimizer. Refuse it here
+	// rather than silently reading without acquiring locks.
+	if err := checkNoLocking(n.Locking); err != nil {
+		return nil, err
+	}
+
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
+		if err := checkNoLocking(s.Select.Locking); err != nil {
+			return nil, err
+		}
 		wrapped = s.Select.Select
 		if s.Select.With != nil {
 			if with != nil {
@@ -483,10 +501,11 @@ func (p *planner) getTimestamp(asOf tree.AsOfClause) (hlc.Timestamp, bool, error
 		// level. We accept AS OF SYSTEM TIME in multiple places (e.g. in
 		// subqueries or view queries) but they must all point to the same
 		// timestamp.
-		ts, err := p.EvalAsOfTimestamp(asOf)
+		res, err := p.evalAsOf(asOf)
 		if err != nil {
 			return hlc.MaxTimestamp, false, err
 		}
+		ts := res.Timestamp
 		if ts != *p.semaCtx.AsOfTimestamp {
 			return hlc.MaxTimestamp, false,
 				unimplemented.NewWithIssue(35712,
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
 
@@ -77,6 +78,13 @@ func GetObjectNames(
 	scName string,
 	explicitPrefix bool,
 ) (res TableNames, err error) {
+	if scName == sessiondata.PgTempSchemaName {
+		// The pg_temp alias refers to the temporary schema of the session.
+		scName = sc.CurrentSearchPath().GetTemporarySchemaName()
+		if scName == "" {
+			return nil, nil
+		}
+	}
 	return sc.LogicalSchemaAccessor().GetObjectNames(ctx, txn, dbDesc, scName,
 		DatabaseListFlags{
 			CommonLookupFlags: sc.CommonLookupFlags(true /*required*/),
@@ -215,6 +223,14 @@ func (p *planner) ResolveUncachedTableDescriptor(
 // resolution.
 func ResolveTargetObject(
 	ctx context.Context, sc SchemaResolver, tn *ObjectName,
+) (res *DatabaseDescriptor, err error) {
+	return resolveTargetObject(ctx, sc, tn, false /* isTemporary */)
+}
+
+// resolveTargetObject implements ResolveTargetObject. If isTemporary is set,
+// the object may also be created in the temporary schema of the session.
+func resolveTargetObject(
+	ctx context.Context, sc SchemaResolver, tn *ObjectName, isTemporary bool,
 ) (res *DatabaseDescriptor, err error) {
 	found, descI, err := tn.ResolveTarget(ctx, sc, sc.CurrentDatabase(), sc.CurrentSearchPath())
 	if err != nil {
@@ -230,7 +246,7 @@ func ResolveTargetObject(
 		err = errors.WithHint(err, "verify that the current database and search_path are valid and/or the target database exists")
 		return nil, err
 	}
-	if tn.Schema() != tree.PublicSchema {
+	if tn.Schema() != tree.PublicSchema && !isTemporary {
 		return nil, pgerror.Newf(pgcode.InvalidName,
 			"schema cannot be modified: %q", tree.ErrString(&tn.TableNamePrefix))
 	}
@@ -246,6 +262,115 @@ func (p *planner) ResolveUncachedDatabase(
 	return res, err
 }
 
+// ResolveType implements the tree.TypeReferenceResolver interface. Type names
+// are resolved in the current database.
+func (p *planner) ResolveType(name string) (*types.T, error) {
+	ctx := p.EvalContext().Ctx()
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := getTypeDescByName(ctx, p.txn, dbDesc.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	return desc.MakeTypesT(), nil
+}
+
+// getTypeDescByName looks up the descriptor of the user-defined type with the
+// given name in the given database. An error is returned if no such type
+// exists.
+func getTypeDescByName(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID, name string,
+) (*sqlbase.TypeDescriptor, error) {
+	id, err := getDescriptorID(ctx, txn, sqlbase.NewTableKey(parentID, name))
+	if err != nil {
+		return nil, err
+	}
+	if id == sqlbase.InvalidID {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "type %q does not exist", name)
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := txn.GetProto(ctx, sqlbase.MakeDescMetadataKey(id), desc); err != nil {
+		return nil, err
+	}
+	typeDesc := desc.GetType()
+	if typeDesc == nil {
+		// The name refers to another kind of object, such as a table.
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "type %q does not exist", name)
+	}
+	return typeDesc, nil
+}
+
+// ResolveFunction implements the tree.FunctionReferenceResolver interface.
+// Only unqualified function names are supported; they are resolved in the
+// current database.
+func (p *planner) ResolveFunction(name *tree.UnresolvedName) (*tree.FunctionDefinition, error) {
+	if name.NumParts != 1 || p.CurrentDatabase() == "" {
+		return nil, nil
+	}
+	ctx := p.EvalContext().Ctx()
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), false /* required */)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+	desc, err := getFunctionDescByName(ctx, p.txn, dbDesc.ID, name.Parts[0])
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	return makeFunctionDefinition(desc), nil
+}
+
+// getFunctionDescByName looks up the descriptor of the user-defined function
+// with the given name in the given database. nil is returned if no such
+// function exists.
+func getFunctionDescByName(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID, name string,
+) (*sqlbase.FunctionDescriptor, error) {
+	id, err := getDescriptorID(ctx, txn, sqlbase.NewTableKey(parentID, name))
+	if err != nil || id == sqlbase.InvalidID {
+		return nil, err
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := txn.GetProto(ctx, sqlbase.MakeDescMetadataKey(id), desc); err != nil {
+		return nil, err
+	}
+	// The function descriptor is nil if the name refers to another kind of
+	// object, such as a table.
+	return desc.GetFunction(), nil
+}
+
+// makeFunctionDefinition returns the definition used to type check and plan
+// calls to the given user-defined function. The body of the function is
+// inlined into the calling query by the optimizer; the heuristic planner
+// cannot evaluate it.
+func makeFunctionDefinition(desc *sqlbase.FunctionDescriptor) *tree.FunctionDefinition {
+	argTypes := make(tree.ArgTypes, len(desc.Params))
+	paramNames := make([]string, len(desc.Params))
+	for i := range desc.Params {
+		argTypes[i].Name = desc.Params[i].Name
+		argTypes[i].Typ = &desc.Params[i].Type
+		paramNames[i] = desc.Params[i].Name
+	}
+	name := desc.Name
+	props := tree.FunctionProperties{
+		// SQL functions are called even if some of their arguments are NULL.
+		NullableArgs: true,
+		Impure:       desc.Impure,
+		UserDefined:  true,
+	}
+	return tree.NewFunctionDefinition(name, &props, []tree.Overload{{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(&desc.ReturnType),
+		SQLBody:    desc.Body,
+		ParamNames: paramNames,
+		Fn: func(*tree.EvalContext, tree.Datums) (tree.Datum, error) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"user-defined function %s() can only be used by the cost-based optimizer", name)
+		},
+	}})
+}
+
 // ResolveRequiredType can be passed to the ResolveExistingObject function to
 // require the returned descriptor to be of a specific type.
 type ResolveRequiredType int
@@ -283,6 +408,14 @@ func (p *planner) LookupObject(
 	ctx context.Context, requireMutable bool, dbName, scName, tbName string,
 ) (found bool, objMeta tree.NameResolutionResult, err error) {
 	sc := p.LogicalSchemaAccessor()
+	if scName == sessiondata.PgTempSchemaName {
+		// The pg_temp alias refers to the temporary schema of the session, if
+		// one has been created.
+		scName = p.SessionData().SearchPath.GetTemporarySchemaName()
+		if scName == "" {
+			return false, nil, nil
+		}
+	}
 	p.tableName = tree.MakeTableNameWithSchema(tree.Name(dbName), tree.Name(scName), tree.Name(tbName))
 	objDesc, err := sc.GetObjectDesc(ctx, p.txn, &p.tableName, p.ObjectLookupFlags(false /*required*/, requireMutable))
 	return objDesc != nil, objDesc, err
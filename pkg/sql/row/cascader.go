@@ -374,6 +374,8 @@ func (c *cascader) addIndexPKRowFetcher(
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
 		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -437,6 +439,8 @@ func (c *cascader) addRowDeleter(
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
 		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -497,6 +501,8 @@ func (c *cascader) addRowUpdater(
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
 		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
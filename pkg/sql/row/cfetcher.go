@@ -161,6 +161,13 @@ type CFetcher struct {
 	// or not when StartScan is invoked.
 	reverse bool
 
+	// lockStr represents the row-level locking mode to use when fetching rows.
+	lockStr roachpb.KeyLockingStrength
+
+	// lockWaitPolicy represents the policy to use when a row is locked by
+	// another transaction.
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy
+
 	// maxKeysPerRow memoizes the maximum number of keys per row
 	// out of all the tables. This is used to calculate the kvBatchFetcher's
 	// firstBatchLimit.
@@ -226,14 +233,20 @@ type CFetcher struct {
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
 // index.
 func (rf *CFetcher) Init(
-	reverse,
-	returnRangeInfo bool, isCheck bool, tables ...FetcherTableArgs,
+	reverse bool,
+	lockStr roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy,
+	returnRangeInfo bool,
+	isCheck bool,
+	tables ...FetcherTableArgs,
 ) error {
 	if len(tables) == 0 {
 		return errors.AssertionFailedf("no tables to fetch from")
 	}
 
 	rf.reverse = reverse
+	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
 	rf.returnRangeInfo = returnRangeInfo
 
 	if len(tables) > 1 {
@@ -260,12 +273,16 @@ func (rf *CFetcher) Init(
 			return errors.Errorf("unhandled type %+v", &colDescriptors[i].Type)
 		}
 	}
+	// Indexes using the primary index encoding are decoded like primary indexes,
+	// with one key per column family.
+	isSecondaryIndex := tableArgs.IsSecondaryIndex &&
+		tableArgs.Index.EncodingType != sqlbase.PrimaryIndexEncoding
 	table := cTableInfo{
 		spans:            tableArgs.Spans,
 		desc:             tableArgs.Desc,
 		colIdxMap:        m,
 		index:            tableArgs.Index,
-		isSecondaryIndex: tableArgs.IsSecondaryIndex,
+		isSecondaryIndex: isSecondaryIndex,
 		cols:             colDescriptors,
 		typs:             typs,
 
@@ -427,7 +444,16 @@ func (rf *CFetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVBatchFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVBatchFetcher(
+		txn,
+		spans,
+		rf.reverse,
+		limitBatches,
+		firstBatchLimit,
+		rf.lockStr,
+		rf.lockWaitPolicy,
+		rf.returnRangeInfo,
+	)
 	if err != nil {
 		return err
 	}
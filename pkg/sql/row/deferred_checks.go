@@ -0,0 +1,298 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package row
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// deferredChecksBatchSize is the maximum number of deferred checks sent to KV
+// in a single batch.
+const deferredChecksBatchSize = 10000
+
+// DeferredChecks accumulates the checks of deferrable constraints that are run
+// after the mutations that issued them: the FK existence checks of the
+// deferrable FK constraints, and the uniqueness checks of the DEFERRABLE
+// UNIQUE constraints.
+//
+// A DeferredChecks is kept for each SQL transaction, to which the checks of
+// the constraints deferred to the end of the transaction are queued. A
+// constraint declared DEFERRABLE INITIALLY DEFERRED is deferred unless the
+// transaction sets it IMMEDIATE with SET CONSTRAINTS, and a constraint
+// declared DEFERRABLE INITIALLY IMMEDIATE is only deferred if the transaction
+// sets it DEFERRED. Its checks are run by RunChecks when the transaction
+// commits.
+//
+// The table writers also keep a DeferredChecks, to which the uniqueness checks
+// of the DEFERRABLE UNIQUE constraints that are not deferred are queued. Its
+// checks are run once all the rows of the statement have been written.
+//
+// The checks are queued by the row writers that have been given the
+// DeferredChecks (see Inserter.DeferChecks and friends). The writers that are
+// not given the DeferredChecks, such as the ones performing cascading actions,
+// run all their FK existence checks immediately, and refuse to write to
+// DEFERRABLE UNIQUE constraints.
+type DeferredChecks struct {
+	mu syncutil.Mutex
+
+	// allMode is the mode set by the last SET CONSTRAINTS ALL statement of the
+	// transaction, if any.
+	allMode constraintsMode
+	// modes contains the modes set for individual constraints by the SET
+	// CONSTRAINTS statements executed after the last SET CONSTRAINTS ALL.
+	modes map[string]constraintsMode
+
+	// checks are the deferred checks, in the order in which they were queued.
+	checks []deferredCheck
+	// acc accounts for the memory of the queued checks.
+	acc mon.BoundAccount
+}
+
+// constraintsMode is the mode of a deferrable constraint set by SET
+// CONSTRAINTS.
+type constraintsMode int
+
+const (
+	// constraintsModeUnset uses the mode the constraint was declared with.
+	constraintsModeUnset constraintsMode = iota
+	constraintsModeImmediate
+	constraintsModeDeferred
+)
+
+// deferredCheck is a deferred check for a mutated row. Exactly one of fk and
+// unique is set.
+type deferredCheck struct {
+	fk     *fkExistenceCheckBaseHelper
+	unique *uniqueCheckHelper
+	// row is the mutated row for an FK existence check, and the values of the
+	// index columns for a uniqueness check.
+	row tree.Datums
+}
+
+// sizeOfDeferredCheck is the memory size of a deferredCheck, not including
+// its row.
+const sizeOfDeferredCheck = int64(unsafe.Sizeof(deferredCheck{}))
+
+// memSize returns the memory size of the check and its row.
+func (c *deferredCheck) memSize() int64 {
+	sz := sizeOfDeferredCheck + rowcontainer.SizeOfDatum*int64(len(c.row))
+	for _, d := range c.row {
+		sz += int64(d.Size())
+	}
+	return sz
+}
+
+// constraint returns the name of the constraint checked by the check, and
+// whether it is initially deferred.
+func (c *deferredCheck) constraint() (name string, initiallyDeferred bool) {
+	if c.fk != nil {
+		return c.fk.ref.Name, c.fk.ref.InitiallyDeferred
+	}
+	return c.unique.index.Name, c.unique.index.InitiallyDeferred
+}
+
+// Init prepares the DeferredChecks for use. The memory of the queued checks is
+// accounted against the given account, which the DeferredChecks takes
+// ownership of.
+func (d *DeferredChecks) Init(acc mon.BoundAccount) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.acc = acc
+}
+
+// SetConstraintsMode records the effect of a SET CONSTRAINTS statement. If
+// all is set, names is ignored and the mode applies to all the deferrable
+// constraints.
+func (d *DeferredChecks) SetConstraintsMode(all bool, names tree.NameList, deferred bool) {
+	mode := constraintsModeImmediate
+	if deferred {
+		mode = constraintsModeDeferred
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if all {
+		d.allMode = mode
+		d.modes = nil
+		return
+	}
+	if d.modes == nil {
+		d.modes = make(map[string]constraintsMode, len(names))
+	}
+	for _, name := range names {
+		d.modes[string(name)] = mode
+	}
+}
+
+// isDeferred returns whether the checks of the given deferrable constraint are
+// currently deferred.
+func (d *DeferredChecks) isDeferred(name string, initiallyDeferred bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.isDeferredLocked(name, initiallyDeferred)
+}
+
+func (d *DeferredChecks) isDeferredLocked(name string, initiallyDeferred bool) bool {
+	mode := d.allMode
+	if m, ok := d.modes[name]; ok {
+		mode = m
+	}
+	switch mode {
+	case constraintsModeImmediate:
+		return false
+	case constraintsModeDeferred:
+		return true
+	default:
+		return initiallyDeferred
+	}
+}
+
+// add queues the given check. Its row is copied, since the row writers reuse
+// their buffers. An error is returned if the memory of the check can't be
+// accounted for.
+func (d *DeferredChecks) add(ctx context.Context, c deferredCheck) error {
+	c.row = append(tree.Datums(nil), c.row...)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.acc.Grow(ctx, c.memSize()); err != nil {
+		return err
+	}
+	d.checks = append(d.checks, c)
+	return nil
+}
+
+// Len returns the number of queued checks.
+func (d *DeferredChecks) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.checks)
+}
+
+// Truncate discards the checks queued after the first n ones. It is used when
+// the writes that queued them are rolled back.
+func (d *DeferredChecks) Truncate(ctx context.Context, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n >= len(d.checks) {
+		return
+	}
+	var sz int64
+	for i := n; i < len(d.checks); i++ {
+		sz += d.checks[i].memSize()
+	}
+	d.acc.Shrink(ctx, sz)
+	d.checks = d.checks[:n]
+}
+
+// Reset discards all the queued checks and the modes set by SET CONSTRAINTS,
+// and releases the memory of the checks. It must be called before the monitor
+// of the account given to Init is stopped.
+func (d *DeferredChecks) Reset(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.allMode = constraintsModeUnset
+	d.modes = nil
+	d.checks = nil
+	d.acc.Clear(ctx)
+}
+
+// RunChecks runs all the queued checks in the given transaction. An error is
+// returned for the first check that fails, in order of addition: a
+// pgcode.ForeignKeyViolation for an FK existence check, and a
+// pgcode.UniqueViolation for a uniqueness check. The checks are discarded when
+// they pass.
+func (d *DeferredChecks) RunChecks(ctx context.Context, txn *client.Txn) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := runDeferredChecks(ctx, txn, d.checks); err != nil {
+		return err
+	}
+	d.checks = nil
+	d.acc.Clear(ctx)
+	return nil
+}
+
+// RunImmediateChecks runs the queued checks of the constraints that are not
+// deferred anymore, for example because of a SET CONSTRAINTS ... IMMEDIATE
+// statement. The checks of the constraints that are still deferred remain
+// queued.
+func (d *DeferredChecks) RunImmediateChecks(ctx context.Context, txn *client.Txn) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var immediate, deferred []deferredCheck
+	var sz int64
+	for _, c := range d.checks {
+		if d.isDeferredLocked(c.constraint()) {
+			deferred = append(deferred, c)
+		} else {
+			immediate = append(immediate, c)
+			sz += c.memSize()
+		}
+	}
+	if err := runDeferredChecks(ctx, txn, immediate); err != nil {
+		return err
+	}
+	d.checks = deferred
+	d.acc.Shrink(ctx, sz)
+	return nil
+}
+
+// runDeferredChecks runs the given checks in batches.
+func runDeferredChecks(ctx context.Context, txn *client.Txn, checks []deferredCheck) error {
+	checker := fkExistenceBatchChecker{txn: txn}
+	for len(checks) > 0 {
+		n := len(checks)
+		if n > deferredChecksBatchSize {
+			n = deferredChecksBatchSize
+		}
+		for i := range checks[:n] {
+			c := &checks[i]
+			var span roachpb.Span
+			var err error
+			if c.fk != nil {
+				span, err = c.fk.spanForValues(c.row)
+			} else {
+				span, err = c.unique.spanForValues(c.row)
+			}
+			if err != nil {
+				return err
+			}
+			checker.batch.Add(&roachpb.ScanRequest{
+				RequestHeader: roachpb.RequestHeaderFromSpan(span),
+			})
+		}
+		br, pErr := txn.Send(ctx, checker.batch)
+		if pErr != nil {
+			return pErr.GoError()
+		}
+		for i, resp := range br.Responses {
+			c := &checks[i]
+			scan := resp.GetInner().(*roachpb.ScanResponse)
+			if c.fk != nil {
+				if err := checker.checkResponse(ctx, c.fk, scan, c.row, c.row); err != nil {
+					return err
+				}
+			} else if len(scan.Rows) > 1 {
+				return c.unique.violationError(c.row)
+			}
+		}
+		checker.reset()
+		checks = checks[n:]
+	}
+	return nil
+}
@@ -123,6 +123,14 @@ func makeRowDeleterWithoutCascader(
 	return rd, nil
 }
 
+// DeferFKChecks makes the Deleter queue the FK existence checks of the
+// deferred constraints in the given DeferredChecks instead of running them.
+func (rd *Deleter) DeferFKChecks(deferred *DeferredChecks) {
+	if rd.Fks.checker != nil {
+		rd.Fks.checker.deferred = deferred
+	}
+}
+
 // DeleteRow adds to the batch the kv operations necessary to delete a table row
 // with the given values. It also will cascade as required and check for
 // orphaned rows. The bytesMonitor is only used if cascading/fk checking and can
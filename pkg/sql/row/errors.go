@@ -65,6 +65,16 @@ func ConvertBatchError(
 	return origPErr.GoError()
 }
 
+// convertLockNotAvailableError returns a user friendly error if the KV error
+// was returned because a locking read with the NOWAIT wait policy encountered
+// a lock held by another transaction.
+func convertLockNotAvailableError(err error) error {
+	if _, ok := err.(*roachpb.WriteIntentError); ok {
+		return pgerror.Wrapf(err, pgcode.LockNotAvailable, "could not obtain lock on row")
+	}
+	return err
+}
+
 // NewUniquenessConstraintViolationError creates an error that represents a
 // violation of a UNIQUE constraint.
 func NewUniquenessConstraintViolationError(
@@ -109,7 +119,13 @@ func NewUniquenessConstraintViolationError(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &sqlbase.DatumAlloc{}, tableArgs,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		&sqlbase.DatumAlloc{},
+		tableArgs,
 	); err != nil {
 		return err
 	}
@@ -179,6 +179,13 @@ type Fetcher struct {
 	// or not when StartScan is invoked.
 	reverse bool
 
+	// lockStr represents the row-level locking mode to use when fetching rows.
+	lockStr roachpb.KeyLockingStrength
+
+	// lockWaitPolicy represents the policy to use when a row is locked by
+	// another transaction.
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy
+
 	// maxKeysPerRow memoizes the maximum number of keys per row
 	// out of all the tables. This is used to calculate the kvBatchFetcher's
 	// firstBatchLimit.
@@ -242,7 +249,10 @@ func (rf *Fetcher) Reset() {
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
 // index.
 func (rf *Fetcher) Init(
-	reverse, returnRangeInfo bool,
+	reverse bool,
+	lockStr roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy,
+	returnRangeInfo bool,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
 	tables ...FetcherTableArgs,
@@ -252,6 +262,8 @@ func (rf *Fetcher) Init(
 	}
 
 	rf.reverse = reverse
+	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
 	rf.returnRangeInfo = returnRangeInfo
 	rf.alloc = alloc
 	rf.isCheck = isCheck
@@ -273,12 +285,16 @@ func (rf *Fetcher) Init(
 	for tableIdx, tableArgs := range tables {
 		oldTable := rf.tables[tableIdx]
 
+		// Indexes using the primary index encoding are decoded like primary indexes,
+		// with one key per column family.
+		isSecondaryIndex := tableArgs.IsSecondaryIndex &&
+			tableArgs.Index.EncodingType != sqlbase.PrimaryIndexEncoding
 		table := tableInfo{
 			spans:            tableArgs.Spans,
 			desc:             tableArgs.Desc,
 			colIdxMap:        tableArgs.ColIdxMap,
 			index:            tableArgs.Index,
-			isSecondaryIndex: tableArgs.IsSecondaryIndex,
+			isSecondaryIndex: isSecondaryIndex,
 			cols:             tableArgs.Cols,
 			row:              make(sqlbase.EncDatumRow, len(tableArgs.Cols)),
 			decodedRow:       make(tree.Datums, len(tableArgs.Cols)),
@@ -448,7 +464,14 @@ func (rf *Fetcher) StartScan(
 
 	rf.traceKV = traceKV
 	f, err := makeKVBatchFetcher(
-		txn, spans, rf.reverse, limitBatches, rf.firstBatchLimit(limitHint), rf.returnRangeInfo,
+		txn,
+		spans,
+		rf.reverse,
+		limitBatches,
+		rf.firstBatchLimit(limitHint),
+		rf.lockStr,
+		rf.lockWaitPolicy,
+		rf.returnRangeInfo,
 	)
 	if err != nil {
 		return err
@@ -527,6 +550,8 @@ func (rf *Fetcher) StartInconsistentScan(
 		rf.reverse,
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
+		rf.lockStr,
+		rf.lockWaitPolicy,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
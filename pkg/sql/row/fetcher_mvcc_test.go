@@ -106,7 +106,12 @@ func TestRowFetcherMVCCMetadata(t *testing.T) {
 	}
 	var rf row.Fetcher
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, true /* isCheck */, &sqlbase.DatumAlloc{},
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		true,  /* isCheck */
+		&sqlbase.DatumAlloc{},
 		args...,
 	); err != nil {
 		t.Fatal(err)
@@ -70,8 +70,8 @@ func initFetcher(
 
 	fetcherArgs := makeFetcherArgs(entries)
 
-	if err := fetcher.Init(reverseScan, false /*reverse*/, false, /* isCheck */
-		alloc, fetcherArgs...); err != nil {
+	if err := fetcher.Init(reverseScan, roachpb.NON_LOCKING, roachpb.LOCK_WAIT_BLOCK,
+		false /* returnRangeInfo */, false /* isCheck */, alloc, fetcherArgs...); err != nil {
 		return nil, err
 	}
 
@@ -1038,8 +1038,8 @@ func TestRowFetcherReset(t *testing.T) {
 	// didn't reset.
 
 	fetcherArgs := makeFetcherArgs(args)
-	if err := resetFetcher.Init(false, false /*reverse*/, false, /* isCheck */
-		&da, fetcherArgs...); err != nil {
+	if err := resetFetcher.Init(false /* reverse */, roachpb.NON_LOCKING, roachpb.LOCK_WAIT_BLOCK,
+		false /* returnRangeInfo */, false /* isCheck */, &da, fetcherArgs...); err != nil {
 		t.Fatal(err)
 	}
 
@@ -39,6 +39,10 @@ type fkExistenceBatchChecker struct {
 	// batchIdxToFk maps the index of the check request/response in the kv batch
 	// to the fkExistenceCheckBaseHelper that created it.
 	batchIdxToFk []*fkExistenceCheckBaseHelper
+
+	// deferred, if set, receives the checks of the constraints that are
+	// deferred to the end of the transaction, instead of the batch.
+	deferred *DeferredChecks
 }
 
 // reset starts a new batch.
@@ -51,6 +55,10 @@ func (f *fkExistenceBatchChecker) reset() {
 func (f *fkExistenceBatchChecker) addCheck(
 	ctx context.Context, row tree.Datums, source *fkExistenceCheckBaseHelper, traceKV bool,
 ) error {
+	if f.deferred != nil && source.ref.Deferrable &&
+		f.deferred.isDeferred(source.ref.Name, source.ref.InitiallyDeferred) {
+		return f.deferred.add(ctx, deferredCheck{fk: source, row: row})
+	}
 	span, err := source.spanForValues(row)
 	if err != nil {
 		return err
@@ -88,47 +96,64 @@ func (f *fkExistenceBatchChecker) runCheck(
 	}
 
 	// Process the responses.
-	fetcher := SpanKVFetcher{}
 	for i, resp := range br.Responses {
-		fk := f.batchIdxToFk[i]
-		fetcher.KVs = resp.GetInner().(*roachpb.ScanResponse).Rows
-		if err := fk.rf.StartScanFrom(ctx, &fetcher); err != nil {
+		if err := f.checkResponse(
+			ctx, f.batchIdxToFk[i], resp.GetInner().(*roachpb.ScanResponse), oldRow, newRow,
+		); err != nil {
 			return err
 		}
+	}
 
-		switch fk.dir {
-		case CheckInserts:
-			// If we're inserting, then there's a violation if the scan found nothing.
-			if fk.rf.kvEnd {
-				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
-					fk.valuesScratch[valueIdx] = newRow[fk.ids[colID]]
-				}
-				return pgerror.Newf(pgcode.ForeignKeyViolation,
-					"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
-					fk.valuesScratch, fk.searchTable.Name, fk.searchIdx.Name,
-					fk.searchIdx.ColumnNames[:fk.prefixLen], f.txn.ID())
+	return nil
+}
+
+// checkResponse processes the response to the existence check issued by the
+// given fkExistenceCheckBaseHelper. oldRow and newRow are only used to
+// populate the error message when the check fails.
+func (f *fkExistenceBatchChecker) checkResponse(
+	ctx context.Context,
+	fk *fkExistenceCheckBaseHelper,
+	resp *roachpb.ScanResponse,
+	oldRow tree.Datums,
+	newRow tree.Datums,
+) error {
+	fetcher := SpanKVFetcher{KVs: resp.Rows}
+	if err := fk.rf.StartScanFrom(ctx, &fetcher); err != nil {
+		return err
+	}
+
+	switch fk.dir {
+	case CheckInserts:
+		// If we're inserting, then there's a violation if the scan found nothing.
+		if fk.rf.kvEnd {
+			for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+				fk.valuesScratch[valueIdx] = newRow[fk.ids[colID]]
 			}
+			return pgerror.Newf(pgcode.ForeignKeyViolation,
+				"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
+				fk.valuesScratch, fk.searchTable.Name, fk.searchIdx.Name,
+				fk.searchIdx.ColumnNames[:fk.prefixLen], f.txn.ID())
+		}
 
-		case CheckDeletes:
-			// If we're deleting, then there's a violation if the scan found something.
-			if !fk.rf.kvEnd {
-				if oldRow == nil {
-					return pgerror.Newf(pgcode.ForeignKeyViolation,
-						"foreign key violation: non-empty columns %s referenced in table %q",
-						fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
-				}
-
-				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
-					fk.valuesScratch[valueIdx] = oldRow[fk.ids[colID]]
-				}
+	case CheckDeletes:
+		// If we're deleting, then there's a violation if the scan found something.
+		if !fk.rf.kvEnd {
+			if oldRow == nil {
 				return pgerror.Newf(pgcode.ForeignKeyViolation,
-					"foreign key violation: values %v in columns %s referenced in table %q",
-					fk.valuesScratch, fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
+					"foreign key violation: non-empty columns %s referenced in table %q",
+					fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
 			}
 
-		default:
-			return errors.AssertionFailedf("impossible case: fkExistenceCheckBaseHelper has dir=%v", fk.dir)
+			for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+				fk.valuesScratch[valueIdx] = oldRow[fk.ids[colID]]
+			}
+			return pgerror.Newf(pgcode.ForeignKeyViolation,
+				"foreign key violation: values %v in columns %s referenced in table %q",
+				fk.valuesScratch, fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
 		}
+
+	default:
+		return errors.AssertionFailedf("impossible case: fkExistenceCheckBaseHelper has dir=%v", fk.dir)
 	}
 
 	return nil
@@ -28,6 +28,7 @@ type Inserter struct {
 	InsertCols            []sqlbase.ColumnDescriptor
 	InsertColIDtoRowIndex map[sqlbase.ColumnID]int
 	Fks                   fkExistenceCheckForInsert
+	uniqueChecks          uniqueChecks
 
 	// For allocation avoidance.
 	marshaled []roachpb.Value
@@ -52,6 +53,7 @@ func MakeInserter(
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
 		marshaled:             make([]roachpb.Value, len(insertCols)),
+		uniqueChecks:          makeUniqueChecks(tableDesc),
 	}
 
 	for i, col := range tableDesc.PrimaryIndex.ColumnIDs {
@@ -70,6 +72,18 @@ func MakeInserter(
 	return ri, nil
 }
 
+// DeferChecks makes the Inserter queue the FK existence checks of the deferred
+// constraints in deferred instead of running them, and the uniqueness checks
+// of the DEFERRABLE UNIQUE constraints in deferred or statement, depending on
+// whether the constraint is deferred. Either can be nil.
+func (ri *Inserter) DeferChecks(deferred, statement *DeferredChecks) {
+	if ri.Fks.checker != nil {
+		ri.Fks.checker.deferred = deferred
+	}
+	ri.uniqueChecks.deferred = deferred
+	ri.uniqueChecks.statement = statement
+}
+
 // insertCPutFn is used by insertRow when conflicts (i.e. the key already exists)
 // should generate errors.
 func insertCPutFn(
@@ -157,6 +171,10 @@ func (ri *Inserter) InsertRow(
 		}
 	}
 
+	if err := ri.uniqueChecks.addAllChecks(ctx, values, ri.InsertColIDtoRowIndex); err != nil {
+		return err
+	}
+
 	primaryIndexKey, secondaryIndexEntries, err := ri.Helper.encodeIndexes(ri.InsertColIDtoRowIndex, values)
 	if err != nil {
 		return err
@@ -52,6 +52,11 @@ type txnKVFetcher struct {
 	firstBatchLimit int64
 	useBatchLimit   bool
 	reverse         bool
+	// lockStr represents the locking mode to use when fetching KVs.
+	lockStr roachpb.KeyLockingStrength
+	// lockWaitPolicy represents the policy to use when a KV is locked by
+	// another transaction.
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
@@ -138,12 +143,18 @@ func (f *txnKVFetcher) getBatchSizeForIdx(batchIdx int) int64 {
 // Subsequent batches are larger, up to kvBatchSize.
 //
 // Batch limits can only be used if the spans are ordered.
+//
+// If lockStr is not NON_LOCKING, the scans acquire unreplicated locks of that
+// strength on the keys they read, and lockWaitPolicy determines how they deal
+// with keys locked by other transactions.
 func makeKVBatchFetcher(
 	txn *client.Txn,
 	spans roachpb.Spans,
 	reverse bool,
 	useBatchLimit bool,
 	firstBatchLimit int64,
+	lockStr roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	sendFn := func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
@@ -154,7 +165,7 @@ func makeKVBatchFetcher(
 		return res, nil
 	}
 	return makeKVBatchFetcherWithSendFunc(
-		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, returnRangeInfo,
+		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, returnRangeInfo,
 	)
 }
 
@@ -166,6 +177,8 @@ func makeKVBatchFetcherWithSendFunc(
 	reverse bool,
 	useBatchLimit bool,
 	firstBatchLimit int64,
+	lockStr roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
@@ -217,6 +230,8 @@ func makeKVBatchFetcherWithSendFunc(
 		reverse:         reverse,
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
+		lockStr:         lockStr,
+		lockWaitPolicy:  lockWaitPolicy,
 		returnRangeInfo: returnRangeInfo,
 	}, nil
 }
@@ -226,11 +241,13 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = f.lockWaitPolicy
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStr
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -238,6 +255,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		scans := make([]roachpb.ScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStr
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -265,6 +283,9 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 
 	br, err := f.sendFn(ctx, ba)
 	if err != nil {
+		if f.lockWaitPolicy == roachpb.LOCK_WAIT_ERROR {
+			err = convertLockNotAvailableError(err)
+		}
 		return err
 	}
 	if br != nil {
@@ -0,0 +1,156 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package row
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// uniqueCheckHelper checks that the values of a DEFERRABLE UNIQUE constraint
+// are unique. The index of such a constraint uses the non-unique encoding, so
+// the check scans the index entries with the given values, and fails if there
+// is more than one.
+type uniqueCheckHelper struct {
+	tableDesc *sqlbase.ImmutableTableDescriptor
+	index     *sqlbase.IndexDescriptor
+
+	// colMap maps the IDs of the columns of the index to their position in the
+	// values given to spanForValues.
+	colMap map[sqlbase.ColumnID]int
+
+	// prefix is the KV key prefix of the index.
+	prefix []byte
+}
+
+// spanForValues returns the span of the index entries with the given values of
+// the index columns.
+func (h *uniqueCheckHelper) spanForValues(values tree.Datums) (roachpb.Span, error) {
+	key, _, err := sqlbase.EncodePartialIndexKey(
+		h.tableDesc.TableDesc(), h.index, len(h.index.ColumnIDs), h.colMap, values, h.prefix,
+	)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	// The index can't be interleaved (see MakeTableDesc), so the span
+	// doesn't include the entries of other tables.
+	return roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}, nil
+}
+
+// violationError returns the error reported when the given values of the index
+// columns are not unique.
+func (h *uniqueCheckHelper) violationError(values tree.Datums) error {
+	valStrs := make([]string, 0, len(values))
+	for _, val := range values {
+		valStrs = append(valStrs, val.String())
+	}
+	return pgerror.Newf(pgcode.UniqueViolation,
+		"duplicate key value (%s)=(%s) violates unique constraint %q",
+		strings.Join(h.index.ColumnNames, ","),
+		strings.Join(valStrs, ","),
+		h.index.Name)
+}
+
+// uniqueChecks queues the uniqueness checks of the DEFERRABLE UNIQUE
+// constraints of a table for the rows written by a row writer.
+type uniqueChecks struct {
+	helpers []uniqueCheckHelper
+
+	// deferred receives the checks of the constraints that are deferred to the
+	// end of the transaction, and statement the checks of the other ones.
+	deferred  *DeferredChecks
+	statement *DeferredChecks
+
+	// valuesScratch holds the values of the index columns of a check.
+	valuesScratch tree.Datums
+}
+
+// makeUniqueChecks creates the uniqueChecks for the DEFERRABLE UNIQUE
+// constraints of the given table.
+func makeUniqueChecks(tableDesc *sqlbase.ImmutableTableDescriptor) uniqueChecks {
+	var u uniqueChecks
+	for i := range tableDesc.Indexes {
+		index := &tableDesc.Indexes[i]
+		if !index.DeferrableUnique {
+			continue
+		}
+		colMap := make(map[sqlbase.ColumnID]int, len(index.ColumnIDs))
+		for j, colID := range index.ColumnIDs {
+			colMap[colID] = j
+		}
+		u.helpers = append(u.helpers, uniqueCheckHelper{
+			tableDesc: tableDesc,
+			index:     index,
+			colMap:    colMap,
+			prefix:    sqlbase.MakeIndexKeyPrefix(tableDesc.TableDesc(), index.ID),
+		})
+	}
+	return u
+}
+
+// forIndex returns the uniqueCheckHelper of the given index, or nil if the
+// index is not the index of a DEFERRABLE UNIQUE constraint.
+func (u *uniqueChecks) forIndex(id sqlbase.IndexID) *uniqueCheckHelper {
+	for i := range u.helpers {
+		if u.helpers[i].index.ID == id {
+			return &u.helpers[i]
+		}
+	}
+	return nil
+}
+
+// addAllChecks queues the checks of all the constraints for the given row.
+func (u *uniqueChecks) addAllChecks(
+	ctx context.Context, row tree.Datums, colIDtoRowIndex map[sqlbase.ColumnID]int,
+) error {
+	for i := range u.helpers {
+		if err := u.addCheck(ctx, &u.helpers[i], row, colIDtoRowIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCheck queues the check of the constraint of the given helper for the
+// given row. Rows with a NULL value in the index columns are not checked, as
+// NULL values are never equal.
+func (u *uniqueChecks) addCheck(
+	ctx context.Context,
+	h *uniqueCheckHelper,
+	row tree.Datums,
+	colIDtoRowIndex map[sqlbase.ColumnID]int,
+) error {
+	u.valuesScratch = u.valuesScratch[:0]
+	for _, colID := range h.index.ColumnIDs {
+		idx, ok := colIDtoRowIndex[colID]
+		if !ok || row[idx] == tree.DNull {
+			return nil
+		}
+		u.valuesScratch = append(u.valuesScratch, row[idx])
+	}
+	checks := u.statement
+	if u.deferred != nil && u.deferred.isDeferred(h.index.Name, h.index.InitiallyDeferred) {
+		checks = u.deferred
+	}
+	if checks == nil {
+		return unimplemented.NewWithIssuef(31632,
+			"cannot write to table %q with deferrable unique constraint %q in this context",
+			h.tableDesc.Name, h.index.Name)
+	}
+	return checks.add(ctx, deferredCheck{unique: h, row: u.valuesScratch})
+}
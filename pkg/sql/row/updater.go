@@ -39,8 +39,9 @@ type Updater struct {
 	rd Deleter
 	ri Inserter
 
-	Fks      fkExistenceCheckForUpdate
-	cascader *cascader
+	Fks          fkExistenceCheckForUpdate
+	uniqueChecks uniqueChecks
+	cascader     *cascader
 
 	// For allocation avoidance.
 	marshaled       []roachpb.Value
@@ -181,6 +182,7 @@ func makeUpdaterWithoutCascader(
 		primaryKeyColChange:   primaryKeyColChange,
 		marshaled:             make([]roachpb.Value, len(updateCols)),
 		newValues:             make([]tree.Datum, len(tableCols)),
+		uniqueChecks:          makeUniqueChecks(tableDesc),
 	}
 
 	if primaryKeyColChange {
@@ -268,6 +270,21 @@ func makeUpdaterWithoutCascader(
 	return ru, nil
 }
 
+// DeferChecks makes the Updater queue the FK existence checks of the deferred
+// constraints in deferred instead of running them, and the uniqueness checks
+// of the DEFERRABLE UNIQUE constraints in deferred or statement, depending on
+// whether the constraint is deferred. Either can be nil.
+func (ru *Updater) DeferChecks(deferred, statement *DeferredChecks) {
+	if ru.Fks.checker != nil {
+		ru.Fks.checker.deferred = deferred
+	}
+	ru.uniqueChecks.deferred = deferred
+	ru.uniqueChecks.statement = statement
+	if ru.primaryKeyColChange {
+		ru.ri.DeferChecks(deferred, statement)
+	}
+}
+
 // UpdateRow adds to the batch the kv operations necessary to update a table row
 // with the given values.
 //
@@ -413,8 +430,9 @@ func (ru *Updater) UpdateRow(
 
 		// We're skipping inverted indexes in this loop, but appending the inverted index entry to the back of
 		// newSecondaryIndexEntries to process later. For inverted indexes we need to remove all old entries before adding
-		// new ones.
-		if index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// new ones. Indexes using the primary index encoding can also have more than one entry per row, and are handled
+		// the same way.
+		if index.Type == sqlbase.IndexDescriptor_INVERTED || index.EncodingType == sqlbase.PrimaryIndexEncoding {
 			newSecondaryIndexEntries = append(newSecondaryIndexEntries, *newSecondaryIndexEntry)
 			oldSecondaryIndexEntries = append(oldSecondaryIndexEntries, *oldSecondaryIndexEntry)
 
@@ -424,6 +442,11 @@ func (ru *Updater) UpdateRow(
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, oldSecondaryIndexEntry.Key) {
 			ru.Fks.addCheckForIndex(ru.Helper.Indexes[i].ID, ru.Helper.Indexes[i].Type)
+			if h := ru.uniqueChecks.forIndex(index.ID); h != nil {
+				if err := ru.uniqueChecks.addCheck(ctx, h, ru.newValues, ru.FetchColIDtoRowIndex); err != nil {
+					return nil, err
+				}
+			}
 			if traceKV {
 				log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
 			}
@@ -119,6 +119,11 @@ type scanNode struct {
 
 	// Indicates if this scan is the source for a delete node.
 	isDeleteSource bool
+
+	// lockingStrength and lockingWaitPolicy represent the row-level locking
+	// mode of the Scan.
+	lockingStrength   tree.LockingStrength
+	lockingWaitPolicy tree.LockingWaitPolicy
 }
 
 // scanVisibility represents which table columns should be included in a scan.
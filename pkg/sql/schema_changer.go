@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -505,6 +506,52 @@ func (sc *SchemaChanger) truncateTable(
 	return nil
 }
 
+// deleteTableDataUsingRangeTombstones deletes the data of a dropped table by
+// writing an MVCC range tombstone to each of its ranges, which takes O(1)
+// writes per range regardless of the amount of data. The caller records in the
+// table descriptor that this was done so that it only happens once.
+func (sc *SchemaChanger) deleteTableDataUsingRangeTombstones(
+	ctx context.Context, table *sqlbase.TableDescriptor,
+) error {
+	tableKey := roachpb.RKey(keys.MakeTablePrefix(uint32(table.ID)))
+	tableSpan := roachpb.RSpan{Key: tableKey, EndKey: tableKey.PrefixEnd()}
+
+	ri := kv.NewRangeIterator(sc.execCfg.DistSender)
+	for ri.Seek(ctx, tableSpan.Key, kv.Ascending); ; ri.Next(ctx) {
+		if !ri.Valid() {
+			return ri.Error().GoError()
+		}
+
+		// Send a separate non-transactional request to each range, each of
+		// which writes a single range tombstone.
+		startKey, endKey := ri.Desc().StartKey, ri.Desc().EndKey
+		if startKey.Less(tableSpan.Key) {
+			startKey = tableSpan.Key
+		}
+		if tableSpan.EndKey.Less(endKey) {
+			endKey = tableSpan.EndKey
+		}
+		var b client.Batch
+		b.AddRawRequest(&roachpb.DeleteRangeRequest{
+			RequestHeader: roachpb.RequestHeader{
+				Key:    startKey.AsRawKey(),
+				EndKey: endKey.AsRawKey(),
+			},
+			UseRangeTombstone: true,
+		})
+		log.VEventf(ctx, 2, "DeleteRange using range tombstone %s - %s", startKey, endKey)
+		if err := sc.db.Run(ctx, &b); err != nil {
+			return err
+		}
+
+		if !ri.NeedAnother(tableSpan) {
+			break
+		}
+	}
+
+	return nil
+}
+
 // maybe Drop a table. Return nil if successfully dropped.
 func (sc *SchemaChanger) maybeDropTable(
 	ctx context.Context, inSession bool, table *sqlbase.TableDescriptor, evalCtx *extendedEvalContext,
@@ -517,6 +564,29 @@ func (sc *SchemaChanger) maybeDropTable(
 	// scheduled the changer for this table. If that's the case,
 	// we still need to wait for the deadline to expire.
 	if table.DropTime != 0 {
+		// Delete the table data right away so that it no longer counts as live
+		// data and is garbage collected along with the range tombstones once
+		// the GC TTL has passed. Historical reads continue to observe it. The
+		// descriptor records that this was done so that subsequent passes,
+		// which run until the GC TTL expires, don't write the tombstones again.
+		if !table.RangeTombstonesWritten &&
+			sc.execCfg.Settings.Version.IsActive(cluster.VersionRangeTombstones) {
+			if err := sc.deleteTableDataUsingRangeTombstones(ctx, table); err != nil {
+				return err
+			}
+			if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+				tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+				if err != nil {
+					return err
+				}
+				tableDesc.RangeTombstonesWritten = true
+				return txn.Put(ctx, sqlbase.MakeDescMetadataKey(tableDesc.ID), sqlbase.WrapDescriptor(tableDesc))
+			}); err != nil {
+				return err
+			}
+			table.RangeTombstonesWritten = true
+		}
+
 		var timeRemaining time.Duration
 		if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 			timeRemaining = 0
@@ -705,6 +775,128 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 	return nil
 }
 
+// maybeMakeEnumMembersWritable makes the members of the enum types used by the
+// table that were added by ALTER TYPE ... ADD VALUE writable. Such members are
+// read-only until no node uses a version of a table referencing the type that
+// predates the members, since these nodes cannot decode values of them. The
+// members are made writable in the type descriptor and in all the referencing
+// tables at once, so that values of them can't be copied into a table in which
+// they are still read-only.
+func (sc *SchemaChanger) maybeMakeEnumMembersWritable(
+	ctx context.Context, table *sqlbase.TableDescriptor,
+) error {
+	if table.Dropped() {
+		return nil
+	}
+	var typeIDs []sqlbase.ID
+	seen := make(map[sqlbase.ID]struct{})
+	addType := func(col *sqlbase.ColumnDescriptor) {
+		if col.Type.Family() != types.EnumFamily || !hasReadOnlyEnumMembers(&col.Type) {
+			return
+		}
+		typeID := sqlbase.ID(col.Type.StableTypeID())
+		if _, ok := seen[typeID]; !ok {
+			seen[typeID] = struct{}{}
+			typeIDs = append(typeIDs, typeID)
+		}
+	}
+	for i := range table.Columns {
+		addType(&table.Columns[i])
+	}
+	for _, m := range table.Mutations {
+		if col := m.GetColumn(); col != nil {
+			addType(col)
+		}
+	}
+
+	for _, typeID := range typeIDs {
+		var tableIDs []sqlbase.ID
+		if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			tableIDs = nil
+			var typeDesc sqlbase.TypeDescriptor
+			if err := getDescriptorByID(ctx, txn, typeID, &typeDesc); err != nil {
+				return err
+			}
+			for _, id := range typeDesc.ReferencingDescriptorIDs {
+				tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+				if err != nil {
+					if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+						continue
+					}
+					return err
+				}
+				if !tableDesc.Dropped() {
+					tableIDs = append(tableIDs, id)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// PublishMultiple waits until there is a single version of each of the
+		// tables before bumping their versions.
+		if _, err := sc.leaseMgr.PublishMultiple(
+			ctx,
+			tableIDs,
+			func(descs map[sqlbase.ID]*sqlbase.MutableTableDescriptor) error {
+				updated := false
+				updateCol := func(col *sqlbase.ColumnDescriptor) {
+					if col.Type.StableTypeID() == uint32(typeID) && hasReadOnlyEnumMembers(&col.Type) {
+						col.Type = *makeEnumMembersWritable(&col.Type)
+						updated = true
+					}
+				}
+				for _, desc := range descs {
+					for i := range desc.Columns {
+						updateCol(&desc.Columns[i])
+					}
+					for _, m := range desc.Mutations {
+						if col := m.GetColumn(); col != nil {
+							updateCol(col)
+						}
+					}
+				}
+				if !updated {
+					return errDidntUpdateDescriptor
+				}
+				return nil
+			},
+			func(txn *client.Txn) error {
+				var typeDesc sqlbase.TypeDescriptor
+				if err := getDescriptorByID(ctx, txn, typeID, &typeDesc); err != nil {
+					return err
+				}
+				typeDesc.MakeEnumMembersWritable()
+				return txn.Put(ctx, sqlbase.MakeDescMetadataKey(typeID), sqlbase.WrapDescriptor(&typeDesc))
+			},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasReadOnlyEnumMembers returns whether any member of the given enum type is
+// read-only.
+func hasReadOnlyEnumMembers(typ *types.T) bool {
+	for i := range typ.EnumPhysicalRepresentations() {
+		if typ.EnumIsMemberReadOnly(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// makeEnumMembersWritable returns a copy of the given enum type in which all
+// members are writable.
+func makeEnumMembersWritable(typ *types.T) *types.T {
+	return types.MakeEnum(
+		typ.StableTypeID(), typ.Name(),
+		typ.EnumPhysicalRepresentations(), typ.EnumLogicalRepresentations(), nil, /* isReadOnly */
+	)
+}
+
 func (sc *SchemaChanger) maybeGCMutations(
 	ctx context.Context, inSession bool, table *sqlbase.TableDescriptor,
 ) error {
@@ -938,6 +1130,10 @@ func (sc *SchemaChanger) exec(
 		return err
 	}
 
+	if err := sc.maybeMakeEnumMembersWritable(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	// Wait for the schema change to propagate to all nodes after this function
 	// returns, so that the new schema is live everywhere. This is not needed for
 	// correctness but is done to make the UI experience/tests predictable.
@@ -1214,8 +1410,12 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 	// Get the other tables whose foreign key backreferences need to be removed.
 	// We make a call to PublishMultiple to handle the situation to add Foreign Key backreferences.
 	var fksByBackrefTable map[sqlbase.ID][]*sqlbase.ConstraintToUpdate
+	// A primary key swap moves the foreign keys of the replaced indexes, so the
+	// other tables taking part in those foreign keys need to be updated too.
+	var fkTablesForPrimaryKeySwap map[sqlbase.ID]struct{}
 	err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		fksByBackrefTable = make(map[sqlbase.ID][]*sqlbase.ConstraintToUpdate)
+		fkTablesForPrimaryKeySwap = make(map[sqlbase.ID]struct{})
 
 		desc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
 		if err != nil {
@@ -1235,22 +1435,43 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 					fksByBackrefTable[constraint.ForeignKey.Table] = append(fksByBackrefTable[constraint.ForeignKey.Table], constraint)
 				}
 			}
+			if mutation.GetPrimaryKeySwap() != nil && mutation.Direction == sqlbase.DescriptorMutation_ADD {
+				for _, idx := range desc.AllNonDropIndexes() {
+					if idx.ForeignKey.IsSet() && idx.ForeignKey.Table != desc.ID {
+						fkTablesForPrimaryKeySwap[idx.ForeignKey.Table] = struct{}{}
+					}
+					for _, ref := range idx.ReferencedBy {
+						if ref.Table != desc.ID {
+							fkTablesForPrimaryKeySwap[ref.Table] = struct{}{}
+						}
+					}
+				}
+			}
 		}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	tableIDsToUpdate := make([]sqlbase.ID, 0, len(fksByBackrefTable)+1)
+	tableIDsToUpdate := make([]sqlbase.ID, 0, len(fksByBackrefTable)+len(fkTablesForPrimaryKeySwap)+1)
 	tableIDsToUpdate = append(tableIDsToUpdate, sc.tableID)
 	for id := range fksByBackrefTable {
 		tableIDsToUpdate = append(tableIDsToUpdate, id)
 	}
+	for id := range fkTablesForPrimaryKeySwap {
+		if _, ok := fksByBackrefTable[id]; !ok {
+			tableIDsToUpdate = append(tableIDsToUpdate, id)
+		}
+	}
 
+	// A completed primary key swap queues up the old indexes for deletion under
+	// a new mutation ID, which needs its own job.
+	var cleanupMutationID sqlbase.MutationID
 	update := func(descs map[sqlbase.ID]*sqlbase.MutableTableDescriptor) error {
 		// Reset vars here because update function can be called multiple times in a retry.
 		isRollback = false
 		jobSucceeded = true
+		cleanupMutationID = sqlbase.InvalidMutationID
 
 		i := 0
 		scDesc, ok := descs[sc.tableID]
@@ -1282,7 +1503,7 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 				mutation.Direction == sqlbase.DescriptorMutation_ADD &&
 				constraint.ForeignKey.Validity == sqlbase.ConstraintValidity_Unvalidated {
 				// Add backreference on the referenced table (which could be the same table)
-				backref := sqlbase.ForeignKeyReference{Table: sc.tableID, Index: constraint.ForeignKeyIndex}
+				backref := makeFKBackReference(sc.tableID, constraint.ForeignKeyIndex, &constraint.ForeignKey)
 				backrefTable, ok := descs[constraint.ForeignKey.Table]
 				if !ok {
 					return errors.AssertionFailedf("required table with ID %d not provided to update closure", sc.tableID)
@@ -1293,6 +1514,20 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 				}
 				backrefIdx.ReferencedBy = append(backrefIdx.ReferencedBy, backref)
 			}
+			if swap := mutation.GetPrimaryKeySwap(); swap != nil && mutation.Direction == sqlbase.DescriptorMutation_ADD {
+				cleanupMutationID = scDesc.ClusterVersion.NextMutationID
+				replacements, err := scDesc.PrimaryKeySwapFKReplacements(swap)
+				if err != nil {
+					return err
+				}
+				for id := range fkTablesForPrimaryKeySwap {
+					fkTable, ok := descs[id]
+					if !ok {
+						return errors.AssertionFailedf("required table with ID %d not provided to update closure", id)
+					}
+					sqlbase.RemapForeignKeyIndexes(fkTable, sc.tableID, replacements)
+				}
+			}
 			if err := scDesc.MakeMutationComplete(mutation); err != nil {
 				return err
 			}
@@ -1331,6 +1566,12 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 			}
 		}
 
+		if cleanupMutationID != sqlbase.InvalidMutationID {
+			if err := sc.createPrimaryKeySwapCleanupJob(ctx, txn, cleanupMutationID); err != nil {
+				return err
+			}
+		}
+
 		schemaChangeEventType := EventLogFinishSchemaChange
 		if isRollback {
 			schemaChangeEventType = EventLogFinishSchemaRollback
@@ -1357,6 +1598,56 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 	return descs[sc.tableID], nil
 }
 
+// createPrimaryKeySwapCleanupJob creates the job responsible for dropping the
+// indexes replaced by a primary key change, and records it in the table
+// descriptor.
+func (sc *SchemaChanger) createPrimaryKeySwapCleanupJob(
+	ctx context.Context, txn *client.Txn, mutationID sqlbase.MutationID,
+) error {
+	// Read the table descriptor from the store. The Version of the
+	// descriptor has already been incremented in the transaction and
+	// this descriptor can be modified without incrementing the version.
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+	if err != nil {
+		return err
+	}
+	span := tableDesc.PrimaryIndexSpan()
+	var spanList []jobspb.ResumeSpanList
+	for _, m := range tableDesc.Mutations {
+		if m.MutationID == mutationID {
+			spanList = append(spanList,
+				jobspb.ResumeSpanList{
+					ResumeSpans: []roachpb.Span{span},
+				},
+			)
+		}
+	}
+	payload := sc.job.Payload()
+	cleanupJob := sc.jobRegistry.NewJob(jobs.Record{
+		Description:   fmt.Sprintf("CLEANUP JOB for '%s'", payload.Description),
+		Username:      payload.Username,
+		DescriptorIDs: payload.DescriptorIDs,
+		Details:       jobspb.SchemaChangeDetails{ResumeSpanList: spanList},
+		Progress:      jobspb.SchemaChangeProgress{},
+	})
+	if err := cleanupJob.WithTxn(txn).Created(ctx); err != nil {
+		return err
+	}
+	// Set the transaction back to nil so that this job can
+	// be used in other transactions.
+	cleanupJob.WithTxn(nil)
+
+	tableDesc.MutationJobs = append(tableDesc.MutationJobs, sqlbase.TableDescriptor_MutationJob{
+		MutationID: mutationID, JobID: *cleanupJob.ID()})
+
+	// write descriptor, the version has already been incremented.
+	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
+	descVal := sqlbase.WrapDescriptor(tableDesc)
+	b := txn.NewBatch()
+	b.Put(descKey, descVal)
+	return txn.Run(ctx, b)
+}
+
 // notFirstInLine returns true whenever the schema change has been queued
 // up for execution after another schema change.
 func (sc *SchemaChanger) notFirstInLine(
@@ -1424,8 +1715,12 @@ func (sc *SchemaChanger) reverseMutations(ctx context.Context, causingError erro
 
 	// Get the other tables whose foreign key backreferences need to be removed.
 	var fksByBackrefTable map[sqlbase.ID][]*sqlbase.ConstraintToUpdate
+	// A primary key swap moves the foreign keys of the replaced indexes, so the
+	// other tables taking part in those foreign keys need to be updated too.
+	var fkTablesForPrimaryKeySwap map[sqlbase.ID]struct{}
 	err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		fksByBackrefTable = make(map[sqlbase.ID][]*sqlbase.ConstraintToUpdate)
+		fkTablesForPrimaryKeySwap = make(map[sqlbase.ID]struct{})
 		var err error
 		desc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
 		if err != nil {
@@ -1739,6 +1739,44 @@ return without an error.`,
 		},
 	),
 
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"max_staleness", types.Interval}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return nil, errBoundedStalenessOutsideAsOf(tree.WithMaxStalenessFunctionName)
+			},
+			Info: `When used in an AS OF SYSTEM TIME clause, performs a bounded staleness read
+which observes data at most ` + "`max_staleness`" + ` older than the start of the statement.
+
+The read is served at the newest timestamp within this bound at which the
+closest replica can serve it without coordinating with the leaseholder, and
+falls back to the leaseholder only if no such timestamp exists. Bounded
+staleness reads are only supported for single-statement SELECT queries which
+read a single row.`,
+		},
+	),
+
+	tree.WithMinTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"min_timestamp", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return nil, errBoundedStalenessOutsideAsOf(tree.WithMinTimestampFunctionName)
+			},
+			Info: `When used in an AS OF SYSTEM TIME clause, performs a bounded staleness read
+which observes data no older than ` + "`min_timestamp`" + `.
+
+The read is served at the newest timestamp within this bound at which the
+closest replica can serve it without coordinating with the leaseholder, and
+falls back to the leaseholder only if no such timestamp exists. Bounded
+staleness reads are only supported for single-statement SELECT queries which
+read a single row.`,
+		},
+	),
+
 	"cluster_logical_timestamp": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -4639,6 +4677,14 @@ func checkPrivilegedUser(ctx *tree.EvalContext) error {
 // if an enterprise license is not installed.
 var EvalFollowerReadOffset func(clusterID uuid.UUID, _ *cluster.Settings) (time.Duration, error)
 
+// errBoundedStalenessOutsideAsOf is returned when one of the bounded staleness
+// functions is evaluated. They are only meaningful in an AS OF SYSTEM TIME
+// clause, where tree.EvalAsOf interprets them without evaluating them.
+func errBoundedStalenessOutsideAsOf(name string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"%s can only be used in an AS OF SYSTEM TIME clause", name)
+}
+
 func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
 	if EvalFollowerReadOffset == nil {
 		return time.Time{}, pgerror.New(pgcode.FeatureNotSupported,
@@ -0,0 +1,69 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lex"
+
+// AlterType represents an ALTER TYPE statement.
+type AlterType struct {
+	Type TableName
+	Cmd  AlterTypeCmd
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterType) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TYPE ")
+	ctx.FormatNode(&node.Type)
+	ctx.FormatNode(node.Cmd)
+}
+
+// AlterTypeCmd represents a type modification operation.
+type AlterTypeCmd interface {
+	NodeFormatter
+	// Placeholder function to ensure that only desired types
+	// (AlterType*) conform to the AlterTypeCmd interface.
+	alterTypeCmd()
+}
+
+func (*AlterTypeAddValue) alterTypeCmd() {}
+
+var _ AlterTypeCmd = &AlterTypeAddValue{}
+
+// AlterTypeAddValue represents an ALTER TYPE ADD VALUE command.
+type AlterTypeAddValue struct {
+	NewVal      string
+	IfNotExists bool
+	Placement   *AlterTypeAddValuePlacement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAddValue) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD VALUE ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.NewVal, ctx.flags.EncodeFlags())
+	if node.Placement != nil {
+		if node.Placement.Before {
+			ctx.WriteString(" BEFORE ")
+		} else {
+			ctx.WriteString(" AFTER ")
+		}
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Placement.ExistingVal, ctx.flags.EncodeFlags())
+	}
+}
+
+// AlterTypeAddValuePlacement represents the placement clause for an ALTER
+// TYPE ADD VALUE command ([BEFORE | AFTER] value).
+type AlterTypeAddValuePlacement struct {
+	Before      bool
+	ExistingVal string
+}
@@ -29,13 +29,53 @@ import (
 // reads.
 const FollowerReadTimestampFunctionName = "experimental_follower_read_timestamp"
 
-var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions or " +
-	FollowerReadTimestampFunctionName + " are allowed")
+// WithMaxStalenessFunctionName and WithMinTimestampFunctionName are the names
+// of the functions which can be used with AOST clauses to perform bounded
+// staleness reads. Instead of reading at a timestamp chosen up front, such a
+// read is served at the newest timestamp, at or above a minimum bound, that the
+// closest replica can serve locally.
+const (
+	WithMaxStalenessFunctionName = "with_max_staleness"
+	WithMinTimestampFunctionName = "with_min_timestamp"
+)
+
+var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions, " +
+	FollowerReadTimestampFunctionName + ", " + WithMaxStalenessFunctionName + " or " +
+	WithMinTimestampFunctionName + " are allowed")
+
+// AsOfSystemTime is the result of evaluating an AS OF SYSTEM TIME clause.
+type AsOfSystemTime struct {
+	// Timestamp is the timestamp at which the query reads. For bounded
+	// staleness reads, it is the minimum timestamp at which the query may read.
+	Timestamp hlc.Timestamp
+	// BoundedStaleness is set if the clause uses with_max_staleness or
+	// with_min_timestamp, in which case the timestamp at which the query reads
+	// is chosen by the replica which serves it.
+	BoundedStaleness bool
+}
 
 // EvalAsOfTimestamp evaluates the timestamp argument to an AS OF SYSTEM TIME query.
+// Bounded staleness reads are rejected; see EvalAsOf.
 func EvalAsOfTimestamp(
 	asOf AsOfClause, semaCtx *SemaContext, evalCtx *EvalContext,
 ) (tsss hlc.Timestamp, err error) {
+	res, err := EvalAsOf(asOf, semaCtx, evalCtx)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if res.BoundedStaleness {
+		return hlc.Timestamp{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"AS OF SYSTEM TIME: %s and %s can only be used with single-statement SELECT queries",
+			WithMaxStalenessFunctionName, WithMinTimestampFunctionName)
+	}
+	return res.Timestamp, nil
+}
+
+// EvalAsOf evaluates the argument to an AS OF SYSTEM TIME query, which may
+// specify a bounded staleness read.
+func EvalAsOf(
+	asOf AsOfClause, semaCtx *SemaContext, evalCtx *EvalContext,
+) (AsOfSystemTime, error) {
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
 	// context.
@@ -44,7 +84,8 @@ func EvalAsOfTimestamp(
 	scalarProps.Require("AS OF SYSTEM TIME", RejectSpecial|RejectSubqueries)
 
 	// In order to support the follower reads feature we permit this expression
-	// to be a simple invocation of the `FollowerReadTimestampFunction`.
+	// to be a simple invocation of the `FollowerReadTimestampFunction` or of
+	// one of the bounded staleness functions.
 	// Over time we could expand the set of allowed functions or expressions.
 	// All non-function expressions must be const and must TypeCheck into a
 	// string.
@@ -52,33 +93,79 @@ func EvalAsOfTimestamp(
 	if fe, ok := asOf.Expr.(*FuncExpr); ok {
 		def, err := fe.Func.Resolve(semaCtx.SearchPath)
 		if err != nil {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
-		}
-		if def.Name != FollowerReadTimestampFunctionName {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
-		if te, err = fe.TypeCheck(semaCtx, types.TimestampTZ); err != nil {
-			return hlc.Timestamp{}, err
+		switch def.Name {
+		case FollowerReadTimestampFunctionName:
+			if te, err = fe.TypeCheck(semaCtx, types.TimestampTZ); err != nil {
+				return AsOfSystemTime{}, err
+			}
+		case WithMaxStalenessFunctionName, WithMinTimestampFunctionName:
+			return evalBoundedStaleness(fe, def.Name, semaCtx, evalCtx)
+		default:
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 	} else {
 		var err error
 		te, err = asOf.Expr.TypeCheck(semaCtx, types.String)
 		if err != nil {
-			return hlc.Timestamp{}, err
+			return AsOfSystemTime{}, err
 		}
 		if !IsConst(evalCtx, te) {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 	}
 
 	d, err := te.Eval(evalCtx)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return AsOfSystemTime{}, err
 	}
 
 	stmtTimestamp := evalCtx.GetStmtTimestamp()
 	ts, err := DatumToHLC(evalCtx, stmtTimestamp, d)
-	return ts, errors.Wrap(err, "AS OF SYSTEM TIME")
+	return AsOfSystemTime{Timestamp: ts}, errors.Wrap(err, "AS OF SYSTEM TIME")
+}
+
+// evalBoundedStaleness evaluates an invocation of with_max_staleness or
+// with_min_timestamp into the minimum timestamp bound of a bounded staleness
+// read. The functions themselves are never evaluated, only their argument is,
+// which must be constant.
+func evalBoundedStaleness(
+	fe *FuncExpr, name string, semaCtx *SemaContext, evalCtx *EvalContext,
+) (AsOfSystemTime, error) {
+	typedFe, err := fe.TypeCheck(semaCtx, types.TimestampTZ)
+	if err != nil {
+		return AsOfSystemTime{}, err
+	}
+	arg := typedFe.(*FuncExpr).Exprs[0].(TypedExpr)
+	if !IsConst(evalCtx, arg) {
+		return AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: only constant arguments are allowed for %s", name)
+	}
+	d, err := arg.Eval(evalCtx)
+	if err != nil {
+		return AsOfSystemTime{}, err
+	}
+
+	var ts hlc.Timestamp
+	switch d := d.(type) {
+	case *DInterval:
+		if d.Duration.Compare(duration.Duration{}) <= 0 {
+			return AsOfSystemTime{}, errors.Errorf(
+				"AS OF SYSTEM TIME: interval value %v must be positive for %s", d, name)
+		}
+		ts.WallTime = duration.Add(evalCtx, evalCtx.GetStmtTimestamp(), d.Duration.Mul(-1)).UnixNano()
+	case *DTimestampTZ:
+		ts.WallTime = d.UnixNano()
+	default:
+		return AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: expected interval or timestamp for %s, got %s", name, d.ResolvedType())
+	}
+	if ts.WallTime <= 0 {
+		return AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: timestamp before 1970-01-01T00:00:00Z is invalid")
+	}
+	return AsOfSystemTime{Timestamp: ts, BoundedStaleness: true}, nil
 }
 
 // DatumToHLC performs the conversion from a Datum to an HLC timestamp.
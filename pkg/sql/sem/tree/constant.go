@@ -60,6 +60,11 @@ func isConstant(expr Expr) bool {
 func typeCheckConstant(c Constant, ctx *SemaContext, desired *types.T) (ret TypedExpr, err error) {
 	avail := c.AvailableTypes()
 	if desired.Family() != types.AnyFamily {
+		// String literals can be typed as any user-defined enum type; whether the
+		// literal names a member of the enum is checked during resolution.
+		if canStrValBecomeEnum(c, desired) {
+			return c.ResolveAsType(ctx, desired)
+		}
 		for _, typ := range avail {
 			if desired.Equivalent(typ) {
 				return c.ResolveAsType(ctx, desired)
@@ -94,6 +99,9 @@ func naturalConstantType(c Constant) *types.T {
 // canConstantBecome returns whether the provided Constant can become resolved
 // as the provided type.
 func canConstantBecome(c Constant, typ *types.T) bool {
+	if canStrValBecomeEnum(c, typ) {
+		return true
+	}
 	avail := c.AvailableTypes()
 	for _, availTyp := range avail {
 		if availTyp.Equivalent(typ) {
@@ -103,6 +111,16 @@ func canConstantBecome(c Constant, typ *types.T) bool {
 	return false
 }
 
+// canStrValBecomeEnum returns whether the provided Constant is a string
+// literal that can be resolved as the provided user-defined enum type.
+func canStrValBecomeEnum(c Constant, typ *types.T) bool {
+	if typ.Family() != types.EnumFamily || typ.StableTypeID() == 0 {
+		return false
+	}
+	s, ok := c.(*StrVal)
+	return ok && !s.scannedAsBytes
+}
+
 // NumVal represents a constant numeric value.
 type NumVal struct {
 	constant.Value
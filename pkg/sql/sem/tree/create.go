@@ -580,6 +580,7 @@ func (*CheckConstraintTableDef) constraintTableDef()      {}
 type UniqueConstraintTableDef struct {
 	IndexTableDef
 	PrimaryKey bool
+	Deferrable DeferrableMode
 }
 
 // Format implements the NodeFormatter interface.
@@ -608,6 +609,10 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Deferrable != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Deferrable.String())
+	}
 }
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -677,14 +682,36 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// DeferrableMode specifies whether the checks of a constraint can be deferred
+// to the end of the transaction, and whether they are by default.
+type DeferrableMode int
+
+// The values for DeferrableMode.
+const (
+	NotDeferrable DeferrableMode = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var deferrableModeName = [...]string{
+	NotDeferrable:                "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE INITIALLY IMMEDIATE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d DeferrableMode) String() string {
+	return deferrableModeName[d]
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name       Name
+	Table      TableName
+	FromCols   NameList
+	ToCols     NameList
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable DeferrableMode
 }
 
 // Format implements the NodeFormatter interface.
@@ -712,6 +739,11 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+
+	if node.Deferrable != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Deferrable.String())
+	}
 }
 
 // SetName implements the TableDef interface.
@@ -890,6 +922,7 @@ type CreateTable struct {
 	Defs          TableDefs
 	AsSource      *Select
 	AsColumnNames NameList // Only to be used in conjunction with AsSource
+	Temporary     bool
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -900,7 +933,11 @@ func (node *CreateTable) As() bool {
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TABLE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -991,6 +1028,188 @@ func (node *CreateTable) HoistConstraints() {
 	}
 }
 
+// CreateType represents a CREATE TYPE ... AS ENUM statement.
+type CreateType struct {
+	Name       TableName
+	EnumLabels []string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TYPE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" AS ENUM (")
+	for i, label := range node.EnumLabels {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, label, ctx.flags.EncodeFlags())
+	}
+	ctx.WriteByte(')')
+}
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Name       TableName
+	Params     FuncParams
+	ReturnType *types.T
+	Options    FunctionOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE FUNCTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	ctx.WriteString(node.ReturnType.SQLString())
+	ctx.FormatNode(&node.Options)
+}
+
+// FuncParam represents a parameter in a CREATE FUNCTION statement.
+type FuncParam struct {
+	// Name is empty for unnamed parameters.
+	Name Name
+	Type *types.T
+}
+
+// FuncParams represents a list of function parameters.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		param := &(*node)[i]
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		if param.Name != "" {
+			ctx.FormatNode(&param.Name)
+			ctx.WriteByte(' ')
+		}
+		ctx.WriteString(param.Type.SQLString())
+	}
+}
+
+// FunctionOptions represents a list of function options.
+type FunctionOptions []FunctionOption
+
+// Format implements the NodeFormatter interface.
+func (node *FunctionOptions) Format(ctx *FmtCtx) {
+	for i := range *node {
+		option := &(*node)[i]
+		ctx.WriteByte(' ')
+		switch option.Name {
+		case FuncOptLanguage:
+			ctx.WriteString(option.Name)
+			ctx.WriteByte(' ')
+			ctx.FormatNameP(&option.StrVal)
+		case FuncOptAs:
+			ctx.WriteString(option.Name)
+			ctx.WriteByte(' ')
+			lex.EncodeSQLStringWithFlags(&ctx.Buffer, option.StrVal, ctx.flags.EncodeFlags())
+		case FuncOptImmutable, FuncOptStable, FuncOptVolatile:
+			ctx.WriteString(option.Name)
+		default:
+			panic(errors.AssertionFailedf("unexpected FunctionOption: %v", option))
+		}
+	}
+}
+
+// FunctionOption represents an option on a CREATE FUNCTION statement.
+type FunctionOption struct {
+	Name string
+
+	StrVal string
+}
+
+// Names of options on CREATE FUNCTION.
+const (
+	FuncOptLanguage  = "LANGUAGE"
+	FuncOptAs        = "AS"
+	FuncOptImmutable = "IMMUTABLE"
+	FuncOptStable    = "STABLE"
+	FuncOptVolatile  = "VOLATILE"
+)
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name   Name
+	Table  TableName
+	Before bool
+	Events TriggerEvents
+	// ForEachRow is false for statement-level triggers.
+	ForEachRow bool
+	Statement  string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	if node.Before {
+		ctx.WriteString(" BEFORE ")
+	} else {
+		ctx.WriteString(" AFTER ")
+	}
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.ForEachRow {
+		ctx.WriteString(" FOR EACH ROW")
+	} else {
+		ctx.WriteString(" FOR EACH STATEMENT")
+	}
+	ctx.WriteString(" EXECUTE ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Statement, ctx.flags.EncodeFlags())
+}
+
+// TriggerEventType is the type of statement that fires a trigger.
+type TriggerEventType int
+
+// TriggerEventType values.
+const (
+	TriggerEventInsert TriggerEventType = iota
+	TriggerEventUpdate
+	TriggerEventDelete
+)
+
+var triggerEventTypeName = [...]string{
+	TriggerEventInsert: "INSERT",
+	TriggerEventUpdate: "UPDATE",
+	TriggerEventDelete: "DELETE",
+}
+
+func (t TriggerEventType) String() string {
+	return triggerEventTypeName[t]
+}
+
+// TriggerEvent represents an event in a CREATE TRIGGER statement.
+type TriggerEvent struct {
+	Type TriggerEventType
+	// Columns is the list of columns of an UPDATE OF event.
+	Columns NameList
+}
+
+// TriggerEvents represents a list of trigger events.
+type TriggerEvents []TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i := range *node {
+		event := &(*node)[i]
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.WriteString(event.Type.String())
+		if len(event.Columns) > 0 {
+			ctx.WriteString(" OF ")
+			ctx.FormatNode(&event.Columns)
+		}
+	}
+}
+
 // CreateSequence represents a CREATE SEQUENCE statement.
 type CreateSequence struct {
 	IfNotExists bool
@@ -1620,6 +1620,167 @@ func (d *DIPAddr) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DEnum is the datum of a user-defined enum type. It holds both the physical
+// representation of the value, which is used to encode and order values of
+// the type, and its logical representation, which is the label of the enum
+// member.
+type DEnum struct {
+	// EnumTyp is the user-defined enum type of the value.
+	EnumTyp *types.T
+	// PhysicalRep is the physical representation of the enum member.
+	PhysicalRep []byte
+	// LogicalRep is the label of the enum member.
+	LogicalRep string
+}
+
+// MakeDEnumFromPhysicalRepresentation creates a DEnum of the given type from
+// the physical representation of one of its members.
+func MakeDEnumFromPhysicalRepresentation(typ *types.T, rep []byte) (*DEnum, error) {
+	physReps := typ.EnumPhysicalRepresentations()
+	for i := range physReps {
+		if bytes.Equal(physReps[i], rep) {
+			return &DEnum{
+				EnumTyp:     typ,
+				PhysicalRep: physReps[i],
+				LogicalRep:  typ.EnumLogicalRepresentations()[i],
+			}, nil
+		}
+	}
+	return nil, errors.AssertionFailedf(
+		"could not find %v in physical representations of enum %s", rep, typ.Name())
+}
+
+// MakeDEnumFromLogicalRepresentation creates a DEnum of the given type from
+// the label of one of its members. Read-only members cannot be created this
+// way, since values of them must not be written until all nodes know about
+// them.
+func MakeDEnumFromLogicalRepresentation(typ *types.T, rep string) (*DEnum, error) {
+	logReps := typ.EnumLogicalRepresentations()
+	for i := range logReps {
+		if logReps[i] == rep {
+			if typ.EnumIsMemberReadOnly(i) {
+				return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+					"enum value %q is not yet public", rep)
+			}
+			return &DEnum{
+				EnumTyp:     typ,
+				PhysicalRep: typ.EnumPhysicalRepresentations()[i],
+				LogicalRep:  logReps[i],
+			}, nil
+		}
+	}
+	return nil, pgerror.Newf(pgcode.InvalidTextRepresentation,
+		"invalid input value for enum %s: %q", typ.Name(), rep)
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DEnum) ResolvedType() *types.T {
+	return d.EnumTyp
+}
+
+// Compare implements the Datum interface.
+func (d *DEnum) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DEnum)
+	if !ok || d.EnumTyp.StableTypeID() != v.EnumTyp.StableTypeID() {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.PhysicalRep, v.PhysicalRep)
+}
+
+// memberIdx returns the position of the member of the enum type that d holds.
+func (d *DEnum) memberIdx() int {
+	physReps := d.EnumTyp.EnumPhysicalRepresentations()
+	for i := range physReps {
+		if bytes.Equal(physReps[i], d.PhysicalRep) {
+			return i
+		}
+	}
+	panic(errors.AssertionFailedf(
+		"could not find %v in physical representations of enum %s", d.PhysicalRep, d.EnumTyp.Name()))
+}
+
+// makeMember returns the member of the enum type at the given position.
+func (d *DEnum) makeMember(idx int) *DEnum {
+	return &DEnum{
+		EnumTyp:     d.EnumTyp,
+		PhysicalRep: d.EnumTyp.EnumPhysicalRepresentations()[idx],
+		LogicalRep:  d.EnumTyp.EnumLogicalRepresentations()[idx],
+	}
+}
+
+// Prev implements the Datum interface.
+func (d *DEnum) Prev(_ *EvalContext) (Datum, bool) {
+	idx := d.memberIdx()
+	if idx == 0 {
+		return nil, false
+	}
+	return d.makeMember(idx - 1), true
+}
+
+// Next implements the Datum interface.
+func (d *DEnum) Next(_ *EvalContext) (Datum, bool) {
+	idx := d.memberIdx()
+	if idx == len(d.EnumTyp.EnumPhysicalRepresentations())-1 {
+		return nil, false
+	}
+	return d.makeMember(idx + 1), true
+}
+
+// IsMax implements the Datum interface.
+func (d *DEnum) IsMax(_ *EvalContext) bool {
+	return d.memberIdx() == len(d.EnumTyp.EnumPhysicalRepresentations())-1
+}
+
+// IsMin implements the Datum interface.
+func (d *DEnum) IsMin(_ *EvalContext) bool {
+	return d.memberIdx() == 0
+}
+
+// Min implements the Datum interface.
+func (d *DEnum) Min(_ *EvalContext) (Datum, bool) {
+	if len(d.EnumTyp.EnumPhysicalRepresentations()) == 0 {
+		return nil, false
+	}
+	return d.makeMember(0), true
+}
+
+// Max implements the Datum interface.
+func (d *DEnum) Max(_ *EvalContext) (Datum, bool) {
+	n := len(d.EnumTyp.EnumPhysicalRepresentations())
+	if n == 0 {
+		return nil, false
+	}
+	return d.makeMember(n - 1), true
+}
+
+// AmbiguousFormat implements the Datum interface. Enum values are formatted
+// without a type annotation since the name of a user-defined type can only be
+// resolved by a planner; string literals are instead typed as enums from the
+// context in which they appear, for example from the type of the column in a
+// DEFAULT expression.
+func (*DEnum) AmbiguousFormat() bool {
+	return false
+}
+
+// Format implements the NodeFormatter interface.
+func (d *DEnum) Format(ctx *FmtCtx) {
+	buf, f := &ctx.Buffer, ctx.flags
+	if f.HasFlags(fmtRawStrings) {
+		buf.WriteString(d.LogicalRep)
+	} else {
+		lex.EncodeSQLStringWithFlags(buf, d.LogicalRep, f.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DEnum) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.PhysicalRep)) + uintptr(len(d.LogicalRep))
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate struct {
@@ -2456,6 +2617,8 @@ func AsJSON(d Datum) (json.JSON, error) {
 		return json.FromString(string(*t)), nil
 	case *DCollatedString:
 		return json.FromString(t.Contents), nil
+	case *DEnum:
+		return json.FromString(t.LogicalRep), nil
 	case *DJSON:
 		return t.JSON, nil
 	case *DArray:
@@ -3597,6 +3760,7 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.UuidFamily:           {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DInt(0)), fixedSize},
+	types.EnumFamily:           {unsafe.Sizeof(DEnum{}), variableSize},
 
 	// TODO(jordan,justin): This seems suspicious.
 	types.ArrayFamily: {unsafe.Sizeof(DString("")), variableSize},
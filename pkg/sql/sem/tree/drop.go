@@ -19,6 +19,8 @@
 
 package tree
 
+import "github.com/cockroachdb/cockroach/pkg/sql/types"
+
 // DropBehavior represents options for dropping schema elements.
 type DropBehavior int
 
@@ -139,6 +141,51 @@ func (node *DropSequence) Format(ctx *FmtCtx) {
 	}
 }
 
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Name     TableName
+	IfExists bool
+	// ParamTypes is nil if no parameter list was specified.
+	ParamTypes []*types.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	if node.ParamTypes != nil {
+		ctx.WriteByte('(')
+		for i, typ := range node.ParamTypes {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.WriteString(typ.SQLString())
+		}
+		ctx.WriteByte(')')
+	}
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name     Name
+	Table    TableName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+}
+
 // DropUser represents a DROP USER statement
 type DropUser struct {
 	Names    Exprs
@@ -90,6 +90,10 @@ type FunctionProperties struct {
 	// determined without extra context. This is used for formatting builtins
 	// with the FmtParsable directive.
 	AmbiguousReturnType bool
+
+	// UserDefined is set to true for functions created with CREATE FUNCTION.
+	// Their definitions are looked up in the catalog rather than in FunDefs.
+	UserDefined bool
 }
 
 // FunctionClass specifies the class of the builtin function.
@@ -121,8 +125,11 @@ func NewFunctionDefinition(
 			// Builtins with a preferred overload are always ambiguous.
 			props.AmbiguousReturnType = true
 		}
-		// Produce separate telemetry for each overload.
-		def[i].counter = sqltelemetry.BuiltinCounter(name, def[i].Signature(false))
+		// Produce separate telemetry for each overload. User-defined functions
+		// are not counted, since their names are chosen by users.
+		if !props.UserDefined {
+			def[i].counter = sqltelemetry.BuiltinCounter(name, def[i].Signature(false))
+		}
 
 		overloads[i] = &def[i]
 	}
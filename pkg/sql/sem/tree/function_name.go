@@ -13,6 +13,8 @@ package tree
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -62,6 +64,48 @@ func (fn *ResolvableFunctionReference) Resolve(
 	}
 }
 
+// ResolveFunctionReference is like fn.Resolve, but also consults the
+// FunctionResolver of the given SemaContext, if any, for function names that
+// do not refer to a builtin. Builtins take precedence over user-defined
+// functions with the same name.
+//
+// A reference that was previously resolved to a user-defined function is
+// resolved again, since the function may have been dropped or re-created
+// since then (e.g. between two executions of a prepared statement).
+func ResolveFunctionReference(
+	fn *ResolvableFunctionReference, ctx *SemaContext,
+) (*FunctionDefinition, error) {
+	var searchPath sessiondata.SearchPath
+	var resolver FunctionReferenceResolver
+	if ctx != nil {
+		searchPath = ctx.SearchPath
+		resolver = ctx.FunctionResolver
+	}
+	if def, ok := fn.FunctionReference.(*FunctionDefinition); ok && def.UserDefined {
+		if resolver == nil {
+			return def, nil
+		}
+		fn.FunctionReference = &UnresolvedName{NumParts: 1, Parts: NameParts{def.Name}}
+	}
+	def, err := fn.Resolve(searchPath)
+	if err == nil || resolver == nil || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return def, err
+	}
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok {
+		return nil, err
+	}
+	udf, resolveErr := resolver.ResolveFunction(name)
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	if udf == nil {
+		return nil, err
+	}
+	fn.FunctionReference = udf
+	return udf, nil
+}
+
 // WrapFunction creates a new ResolvableFunctionReference
 // holding a pre-resolved function. Helper for grammar rules.
 func WrapFunction(n string) ResolvableFunctionReference {
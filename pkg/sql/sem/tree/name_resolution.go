@@ -366,6 +366,32 @@ func (t *TableName) ResolveTarget(
 	return found, scMeta, err
 }
 
+// ResolveTemporaryStatus determines whether a new table is temporary, either
+// because the TEMPORARY keyword was specified or because the table is created
+// explicitly in the temporary schema of the session, through the pg_temp
+// alias or by name.
+func (t *TableName) ResolveTemporaryStatus(
+	explicitTemp bool, searchPath sessiondata.SearchPath,
+) (bool, error) {
+	if !t.ExplicitSchema {
+		return explicitTemp, nil
+	}
+	scName := t.Schema()
+	if scName == sessiondata.PgTempSchemaName ||
+		(scName != "" && scName == searchPath.GetTemporarySchemaName()) {
+		return true, nil
+	}
+	if sessiondata.IsTemporarySchemaName(scName) {
+		return false, pgerror.New(pgcode.InvalidTableDefinition,
+			"cannot create relations in temporary schemas of other sessions")
+	}
+	if explicitTemp {
+		return false, pgerror.New(pgcode.InvalidTableDefinition,
+			"cannot create temporary relation in non-temporary schema")
+	}
+	return false, nil
+}
+
 // Resolve is used for table prefixes. This is adequate for table
 // patterns with stars, e.g. AllTablesSelector.
 func (tp *TableNamePrefix) Resolve(
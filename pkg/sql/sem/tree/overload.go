@@ -51,6 +51,16 @@ type Overload struct {
 	Fn            func(*EvalContext, Datums) (Datum, error)
 	Generator     GeneratorFactory
 
+	// SQLBody is the body of a user-defined function written in SQL. It is a
+	// single SELECT statement, which is inlined into the calling query by the
+	// optimizer.
+	SQLBody string
+
+	// ParamNames are the names of the parameters of a user-defined function,
+	// in order. Unnamed parameters have an empty name; all parameters can also
+	// be referenced in SQLBody by position ($1, $2, etc).
+	ParamNames []string
+
 	// counter, if non-nil, should be incremented upon successful
 	// type check of expressions using this overload.
 	counter telemetry.Counter
@@ -64,6 +64,8 @@ func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return ParseDDate(ctx, s)
 	case types.DecimalFamily:
 		return ParseDDecimal(s)
+	case types.EnumFamily:
+		return MakeDEnumFromLogicalRepresentation(t, s)
 	case types.FloatFamily:
 		return ParseDFloat(s)
 	case types.INetFamily:
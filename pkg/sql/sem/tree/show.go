@@ -204,6 +204,14 @@ func (node *ShowJobs) Format(ctx *FmtCtx) {
 	ctx.WriteString("JOBS")
 }
 
+// ShowChangefeedJobs represents a SHOW CHANGEFEED JOBS statement
+type ShowChangefeedJobs struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowChangefeedJobs) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW CHANGEFEED JOBS")
+}
+
 // ShowSessions represents a SHOW SESSIONS statement
 type ShowSessions struct {
 	All     bool
@@ -59,9 +59,45 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// TypeResolver is used to resolve references to user-defined types. If
+	// nil, no user-defined types can be referenced.
+	TypeResolver TypeReferenceResolver
+
+	// FunctionResolver is used to resolve references to user-defined
+	// functions. If nil, no user-defined functions can be referenced.
+	FunctionResolver FunctionReferenceResolver
+
 	Properties SemaProperties
 }
 
+// TypeReferenceResolver is the interface used to resolve references, by
+// name, to user-defined types.
+type TypeReferenceResolver interface {
+	// ResolveType returns the type with the given name, or an error if no such
+	// type exists.
+	ResolveType(name string) (*types.T, error)
+}
+
+// FunctionReferenceResolver is the interface used to resolve references, by
+// name, to user-defined functions.
+type FunctionReferenceResolver interface {
+	// ResolveFunction returns the definition of the user-defined function with
+	// the given name, or nil if no such function exists.
+	ResolveFunction(name *UnresolvedName) (*FunctionDefinition, error)
+}
+
+// ResolveType resolves typ if it is a placeholder for a user-defined type that
+// has not been resolved yet, and returns it unchanged otherwise.
+func ResolveType(typ *types.T, ctx *SemaContext) (*types.T, error) {
+	if !typ.IsUnresolvedUserDefinedType() {
+		return typ, nil
+	}
+	if ctx == nil || ctx.TypeResolver == nil {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "type %q does not exist", typ.Name())
+	}
+	return ctx.TypeResolver.ResolveType(typ.Name())
+}
+
 // SemaProperties is a holder for required and derived properties
 // during semantic analysis. It provides scoping semantics via its
 // Restore() method, see below.
@@ -446,6 +482,11 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ *types.T) (TypedExpr, error)
 	// The desired type provided to a CastExpr is ignored. Instead,
 	// types.Any is passed to the child of the cast. There are two
 	// exceptions, described below.
+	typ, err := ResolveType(expr.Type, ctx)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = typ
 	desired := types.Any
 	switch {
 	case isConstant(expr.Expr):
@@ -530,6 +571,11 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Type
 
 // TypeCheck implements the Expr interface.
 func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
+	typ, err := ResolveType(expr.Type, ctx)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = typ
 	subExpr, err := typeCheckAndRequire(ctx, expr.Expr, expr.Type,
 		fmt.Sprintf("type annotation for %v as %s, found", expr.Expr, expr.Type))
 	if err != nil {
@@ -811,11 +857,7 @@ func CheckIsWindowOrAgg(def *FunctionDefinition) error {
 
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	var searchPath sessiondata.SearchPath
-	if ctx != nil {
-		searchPath = ctx.SearchPath
-	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := ResolveFunctionReference(&expr.Func, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1362,6 +1404,10 @@ func (d *DUuid) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { retur
 // identity function for Datum.
 func (d *DIPAddr) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DEnum) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DDate) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
@@ -10,7 +10,10 @@
 
 package sessiondata
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // PgDatabaseName is the name of the default postgres system database.
 const PgDatabaseName = "postgres"
@@ -22,29 +25,73 @@ const DefaultDatabaseName = "defaultdb"
 // PgCatalogName is the name of the pg_catalog system schema.
 const PgCatalogName = "pg_catalog"
 
+// PgTempSchemaName is the alias for temporary schemas across sessions.
+const PgTempSchemaName = "pg_temp"
+
+// temporarySchemaPrefix is the prefix of the names of the temporary schemas
+// of the sessions.
+const temporarySchemaPrefix = PgTempSchemaName + "_"
+
+// TemporarySchemaName returns the name of the temporary schema of the session
+// with the given identifier, in the form pg_temp_<hi>_<lo>.
+func TemporarySchemaName(sessionIDHi, sessionIDLo uint64) string {
+	return fmt.Sprintf("%s%d_%d", temporarySchemaPrefix, sessionIDHi, sessionIDLo)
+}
+
+// IsTemporarySchemaName returns true if the given name is the name of the
+// temporary schema of some session.
+func IsTemporarySchemaName(name string) bool {
+	return strings.HasPrefix(name, temporarySchemaPrefix)
+}
+
 // SearchPath represents a list of namespaces to search builtins in.
 // The names must be normalized (as per Name.Normalize) already.
 type SearchPath struct {
-	paths             []string
-	containsPgCatalog bool
+	paths                []string
+	containsPgCatalog    bool
+	containsPgTempSchema bool
+	tempSchemaName       string
 }
 
 // MakeSearchPath returns a new immutable SearchPath struct. The paths slice
 // must not be modified after hand-off to MakeSearchPath.
 func MakeSearchPath(paths []string) SearchPath {
 	containsPgCatalog := false
+	containsPgTempSchema := false
 	for _, e := range paths {
-		if e == PgCatalogName {
+		switch e {
+		case PgCatalogName:
 			containsPgCatalog = true
-			break
+		case PgTempSchemaName:
+			containsPgTempSchema = true
 		}
 	}
 	return SearchPath{
-		paths:             paths,
-		containsPgCatalog: containsPgCatalog,
+		paths:                paths,
+		containsPgCatalog:    containsPgCatalog,
+		containsPgTempSchema: containsPgTempSchema,
+	}
+}
+
+// WithTemporarySchemaName returns a new immutable SearchPath struct with
+// the tempSchemaName supplied and the same paths as before.
+// This should be called every time a session creates a temporary schema
+// for the first time.
+func (s SearchPath) WithTemporarySchemaName(tempSchemaName string) SearchPath {
+	return SearchPath{
+		paths:                s.paths,
+		containsPgCatalog:    s.containsPgCatalog,
+		containsPgTempSchema: s.containsPgTempSchema,
+		tempSchemaName:       tempSchemaName,
 	}
 }
 
+// GetTemporarySchemaName returns the temporary schema specific to the current
+// session, or an empty string if the session has not created one.
+func (s SearchPath) GetTemporarySchemaName() string {
+	return s.tempSchemaName
+}
+
 // Iter returns an iterator through the search path. We must include the
 // implicit pg_catalog at the beginning of the search path, unless it has been
 // explicitly set later by the user.
@@ -53,17 +100,22 @@ func MakeSearchPath(paths []string) SearchPath {
 // searched in the specified order. If pg_catalog is not in the path then it
 // will be searched before searching any of the path items."
 // - https://www.postgresql.org/docs/9.1/static/runtime-config-client.html
+//
+// Likewise, the temporary schema of the session, if any, is searched first
+// unless it is mentioned explicitly in the path through the pg_temp alias.
 func (s SearchPath) Iter() SearchPathIter {
-	if s.containsPgCatalog {
-		return SearchPathIter{paths: s.paths, i: 0}
+	return SearchPathIter{
+		paths:                s.paths,
+		implicitPgCatalog:    !s.containsPgCatalog,
+		implicitPgTempSchema: !s.containsPgTempSchema,
+		tempSchemaName:       s.tempSchemaName,
 	}
-	return SearchPathIter{paths: s.paths, i: -1}
 }
 
 // IterWithoutImplicitPGCatalog is the same as Iter, but does not include the
-// implicit pg_catalog.
+// implicit pg_catalog and temporary schema.
 func (s SearchPath) IterWithoutImplicitPGCatalog() SearchPathIter {
-	return SearchPathIter{paths: s.paths, i: 0}
+	return SearchPathIter{paths: s.paths, tempSchemaName: s.tempSchemaName}
 }
 
 // GetPathArray returns the underlying path array of this SearchPath. The
@@ -74,7 +126,9 @@ func (s SearchPath) GetPathArray() []string {
 
 // Equals returns true if two SearchPaths are the same.
 func (s SearchPath) Equals(other *SearchPath) bool {
-	if s.containsPgCatalog != other.containsPgCatalog {
+	if s.containsPgCatalog != other.containsPgCatalog ||
+		s.containsPgTempSchema != other.containsPgTempSchema ||
+		s.tempSchemaName != other.tempSchemaName {
 		return false
 	}
 	if len(s.paths) != len(other.paths) {
@@ -100,19 +154,37 @@ func (s SearchPath) String() string {
 // iterator, and then repeatedly call the Next method in order to iterate over
 // each search path.
 type SearchPathIter struct {
-	paths []string
-	i     int
+	paths                []string
+	implicitPgCatalog    bool
+	implicitPgTempSchema bool
+	tempSchemaName       string
+	i                    int
 }
 
 // Next returns the next search path, or false if there are no remaining paths.
 func (iter *SearchPathIter) Next() (path string, ok bool) {
-	if iter.i == -1 {
-		iter.i++
+	if iter.implicitPgTempSchema {
+		iter.implicitPgTempSchema = false
+		if iter.tempSchemaName != "" {
+			return iter.tempSchemaName, true
+		}
+	}
+	if iter.implicitPgCatalog {
+		iter.implicitPgCatalog = false
 		return PgCatalogName, true
 	}
-	if iter.i < len(iter.paths) {
+	for iter.i < len(iter.paths) {
 		iter.i++
-		return iter.paths[iter.i-1], true
+		path := iter.paths[iter.i-1]
+		if path == PgTempSchemaName {
+			// The pg_temp alias refers to the temporary schema of the session,
+			// which is skipped if the session has not created one yet.
+			if iter.tempSchemaName == "" {
+				continue
+			}
+			return iter.tempSchemaName, true
+		}
+		return path, true
 	}
 	return "", false
 }
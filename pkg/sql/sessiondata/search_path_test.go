@@ -77,3 +77,65 @@ func TestSearchPathEquals(t *testing.T) {
 	d := MakeSearchPath([]string{"x"})
 	assert.False(t, a1.Equals(&d))
 }
+
+func TestImpliedSearchPathWithTemporarySchema(t *testing.T) {
+	tempSchemaName := TemporarySchemaName(1, 2)
+	testCases := []struct {
+		explicitSearchPath                         []string
+		expectedSearchPath                         []string
+		expectedSearchPathWithoutImplicitPgCatalog []string
+	}{
+		{[]string{}, []string{tempSchemaName, `pg_catalog`}, []string{}},
+		{[]string{`pg_catalog`}, []string{tempSchemaName, `pg_catalog`}, []string{`pg_catalog`}},
+		{[]string{`foobar`}, []string{tempSchemaName, `pg_catalog`, `foobar`}, []string{`foobar`}},
+		{
+			[]string{`foobar`, `pg_temp`},
+			[]string{`pg_catalog`, `foobar`, tempSchemaName},
+			[]string{`foobar`, tempSchemaName},
+		},
+		{
+			[]string{`pg_temp`, `pg_catalog`, `foobar`},
+			[]string{tempSchemaName, `pg_catalog`, `foobar`},
+			[]string{tempSchemaName, `pg_catalog`, `foobar`},
+		},
+	}
+
+	for _, tc := range testCases {
+		searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName(tempSchemaName)
+		t.Run(strings.Join(tc.explicitSearchPath, ","), func(t *testing.T) {
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.Iter()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPath, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPath, actualSearchPath)
+			}
+		})
+
+		t.Run(strings.Join(tc.explicitSearchPath, ",")+"/no-pg-catalog", func(t *testing.T) {
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.IterWithoutImplicitPGCatalog()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath)
+			}
+		})
+	}
+
+	// Without a temporary schema, the pg_temp alias is skipped.
+	iter := MakeSearchPath([]string{`pg_temp`, `foobar`}).Iter()
+	var actualSearchPath []string
+	for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+		actualSearchPath = append(actualSearchPath, p)
+	}
+	assert.Equal(t, []string{`pg_catalog`, `foobar`}, actualSearchPath)
+}
+
+func TestIsTemporarySchemaName(t *testing.T) {
+	assert.True(t, IsTemporarySchemaName(TemporarySchemaName(1, 2)))
+	assert.False(t, IsTemporarySchemaName(PgTempSchemaName))
+	assert.False(t, IsTemporarySchemaName("public"))
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetConstraints sets the mode of the deferrable constraints for the rest of
+// the current transaction. Outside of an explicit transaction, it has no
+// effect. When constraints are set IMMEDIATE, their pending checks are run
+// right away.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	deferred := p.extendedEvalCtx.DeferredChecks
+	if deferred == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+	deferred.SetConstraintsMode(n.All, n.Names, n.Deferred)
+	if !n.Deferred {
+		if err := deferred.RunImmediateChecks(ctx, p.txn); err != nil {
+			return nil, err
+		}
+	}
+	return newZeroNode(nil /* columns */), nil
+}
@@ -55,6 +55,7 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 	"range_min_bytes": {types.Int, func(c *config.ZoneConfig, d tree.Datum) { c.RangeMinBytes = proto.Int64(int64(tree.MustBeDInt(d))) }},
 	"range_max_bytes": {types.Int, func(c *config.ZoneConfig, d tree.Datum) { c.RangeMaxBytes = proto.Int64(int64(tree.MustBeDInt(d))) }},
 	"num_replicas":    {types.Int, func(c *config.ZoneConfig, d tree.Datum) { c.NumReplicas = proto.Int32(int32(tree.MustBeDInt(d))) }},
+	"num_voters":      {types.Int, func(c *config.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) }},
 	"gc.ttlseconds": {types.Int, func(c *config.ZoneConfig, d tree.Datum) {
 		c.GC = &config.GCPolicy{TTLSeconds: int32(tree.MustBeDInt(d))}
 	}},
@@ -467,6 +468,7 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 	// RangeMinBytes and RangeMaxBytes must be set together
 	// LeasePreferences cannot be set unless Constraints are explicitly set
 	// Per-replica constraints cannot be set unless num_replicas is explicitly set
+	// num_voters cannot be set unless num_replicas is explicitly set
 	if err := zoneToWrite.ValidateTandemFields(); err != nil {
 		err = errors.Wrap(err, "could not validate zone config")
 		err = pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
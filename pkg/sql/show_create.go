@@ -99,6 +99,11 @@ func printForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.InitiallyDeferred {
+		buf.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	} else if fk.Deferrable {
+		buf.WriteString(" DEFERRABLE INITIALLY IMMEDIATE")
+	}
 	return nil
 }
 
@@ -179,7 +184,22 @@ func ShowCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
-			f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			if idx.DeferrableUnique {
+				// The index of a DEFERRABLE UNIQUE constraint can only be created
+				// by the constraint.
+				f.WriteString("CONSTRAINT ")
+				f.FormatNameP(&idx.Name)
+				f.WriteString(" UNIQUE (")
+				idx.ColNamesFormat(f)
+				f.WriteByte(')')
+				if len(idx.StoreColumnNames) > 0 {
+					f.WriteString(" STORING (")
+					formatQuoteNames(&f.Buffer, idx.StoreColumnNames...)
+					f.WriteByte(')')
+				}
+			} else {
+				f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			}
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
 			if err := showCreateInterleave(ctx, idx, &f.Buffer, dbPrefix, lCtx); err != nil {
@@ -190,6 +210,11 @@ func ShowCreateTable(
 			); err != nil {
 				return "", err
 			}
+			if idx.InitiallyDeferred {
+				f.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+			} else if idx.DeferrableUnique {
+				f.WriteString(" DEFERRABLE INITIALLY IMMEDIATE")
+			}
 		}
 	}
 
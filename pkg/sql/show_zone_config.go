@@ -204,6 +204,11 @@ func generateZoneConfigIntrospectionValues(
 			f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
 			useComma = true
 		}
+		if zone.NumVoters != nil {
+			writeComma(f, useComma)
+			f.Printf("\tnum_voters = %d", *zone.NumVoters)
+			useComma = true
+		}
 		if !zone.InheritedConstraints {
 			writeComma(f, useComma)
 			f.Printf("\tconstraints = %s", lex.EscapeSQLString(constraints))
@@ -144,5 +144,6 @@ const (
 	PgCatalogStatActivityTableID
 	PgCatalogSecurityLabelTableID
 	PgCatalogSharedSecurityLabelTableID
-	MinVirtualID = PgCatalogSharedSecurityLabelTableID
+	CrdbInternalChangefeedStatusTableID
+	MinVirtualID = CrdbInternalChangefeedStatusTableID
 )
@@ -766,6 +766,10 @@ func EncodeSecondaryIndex(
 ) ([]IndexEntry, error) {
 	secondaryIndexKeyPrefix := MakeIndexKeyPrefix(tableDesc, secondaryIndex.ID)
 
+	if secondaryIndex.EncodingType == PrimaryIndexEncoding {
+		return encodePrimaryEncodedIndex(tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
+	}
+
 	var containsNull = false
 	var secondaryKeys [][]byte
 	var err error
@@ -851,6 +855,90 @@ func EncodeSecondaryIndex(
 	return entries, nil
 }
 
+// encodePrimaryEncodedIndex encodes the entries for a secondary index that uses
+// the primary index encoding: the key only contains the index columns, and one
+// entry is produced per column family, exactly as for the primary index.
+// Family 0 is always written, so that it can act as the row sentinel.
+func encodePrimaryEncodedIndex(
+	tableDesc *TableDescriptor,
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []tree.Datum,
+	keyPrefix []byte,
+) ([]IndexEntry, error) {
+	indexKey, _, err := EncodeIndexKey(tableDesc, index, colMap, values, keyPrefix)
+	if err != nil {
+		return []IndexEntry{}, err
+	}
+
+	// Key columns are not stored in the value, unless their key encoding is
+	// lossy (composite).
+	skipColumn := func(colID ColumnID, val tree.Datum) bool {
+		if !index.ContainsColumnID(colID) {
+			return true
+		}
+		for _, id := range index.ColumnIDs {
+			if id == colID {
+				cdatum, ok := val.(tree.CompositeDatum)
+				return !ok || !cdatum.IsComposite()
+			}
+		}
+		return false
+	}
+
+	var entries []IndexEntry
+	for i := range tableDesc.Families {
+		family := &tableDesc.Families[i]
+		// MakeFamilyKey appends to its argument; make sure every family key gets
+		// its own copy of the index key.
+		familyKey := keys.MakeFamilyKey(indexKey[:len(indexKey):len(indexKey)], uint32(family.ID))
+
+		if len(family.ColumnIDs) == 1 && family.ColumnIDs[0] == family.DefaultColumnID {
+			// Storage optimization to store DefaultColumnID directly as a value.
+			val := findColumnValue(family.DefaultColumnID, colMap, values)
+			if val == tree.DNull || skipColumn(family.DefaultColumnID, val) {
+				continue
+			}
+			col, err := tableDesc.FindColumnByID(family.DefaultColumnID)
+			if err != nil {
+				return []IndexEntry{}, err
+			}
+			value, err := MarshalColumnValue(col, val)
+			if err != nil {
+				return []IndexEntry{}, err
+			}
+			entries = append(entries, IndexEntry{Key: familyKey, Value: value})
+			continue
+		}
+
+		familyColumnIDs := append([]ColumnID(nil), family.ColumnIDs...)
+		sort.Slice(familyColumnIDs, func(i, j int) bool { return familyColumnIDs[i] < familyColumnIDs[j] })
+
+		var entryValue []byte
+		var lastColID ColumnID
+		for _, colID := range familyColumnIDs {
+			val := findColumnValue(colID, colMap, values)
+			if val == tree.DNull || skipColumn(colID, val) {
+				continue
+			}
+			colIDDiff := colID - lastColID
+			lastColID = colID
+			entryValue, err = EncodeTableValue(entryValue, colIDDiff, val, nil)
+			if err != nil {
+				return []IndexEntry{}, err
+			}
+		}
+
+		if family.ID != 0 && len(entryValue) == 0 {
+			continue
+		}
+		entry := IndexEntry{Key: familyKey}
+		entry.Value.SetTuple(entryValue)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // EncodeSecondaryIndexes encodes key/values for the secondary indexes. colMap
 // maps ColumnIDs to indices in `values`. secondaryIndexEntries is the return
 // value (passed as a parameter so the caller can reuse between rows) and is
@@ -11,6 +11,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -72,12 +73,41 @@ func (c ColumnIDs) Len() int           { return len(c) }
 func (c ColumnIDs) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
 func (c ColumnIDs) Less(i, j int) bool { return c[i] < c[j] }
 
+// Equals returns true if the input list is equal to this list.
+func (c ColumnIDs) Equals(input ColumnIDs) bool {
+	if len(input) != len(c) {
+		return false
+	}
+	for i := range input {
+		if input[i] != c[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // FamilyID is a custom type for ColumnFamilyDescriptor IDs.
 type FamilyID uint32
 
 // IndexID is a custom type for IndexDescriptor IDs.
 type IndexID tree.IndexID
 
+// IndexDescriptorEncodingType is a custom type to represent different encoding types
+// for secondary indexes.
+type IndexDescriptorEncodingType uint32
+
+const (
+	// SecondaryIndexEncoding corresponds to the standard way of encoding secondary
+	// indexes as described in
+	// https://github.com/cockroachdb/cockroach/blob/master/docs/tech-notes/encoding.md.
+	SecondaryIndexEncoding IndexDescriptorEncodingType = iota
+	// PrimaryIndexEncoding corresponds to when a secondary index is encoded using
+	// the primary index encoding, with one kv per column family. This is used
+	// for the new primary index of a table undergoing a primary key change, and
+	// for the old primary index while it is being dropped.
+	PrimaryIndexEncoding
+)
+
 // DescriptorVersion is a custom type for TableDescriptor Versions.
 type DescriptorVersion uint32
 
@@ -571,11 +601,16 @@ func (desc *TableDescriptor) IsPhysicalTable() bool {
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
 // given index. For secondary indexes, we always only use one, but for primary
-// indexes, we can encode up to one kv per column family.
+// indexes (and secondary indexes using the primary index encoding), we can
+// encode up to one kv per column family.
 func (desc *TableDescriptor) KeysPerRow(indexID IndexID) int {
 	if desc.PrimaryIndex.ID == indexID {
 		return len(desc.Families)
 	}
+	if idx, err := desc.FindIndexByID(indexID); err == nil &&
+		idx.EncodingType == PrimaryIndexEncoding {
+		return len(desc.Families)
+	}
 	return 1
 }
 
@@ -962,10 +997,16 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 			// by ContainsColumnID.
 			index.ExtraColumnIDs = nil
 			index.StoreColumnIDs = nil
+			// An index using the primary index encoding is keyed only by its own
+			// columns, and stores the remaining columns of the table regardless of
+			// whether they are part of the current primary index.
+			isPrimaryEncoded := index.EncodingType == PrimaryIndexEncoding
 			var extraColumnIDs []ColumnID
-			for _, primaryColID := range desc.PrimaryIndex.ColumnIDs {
-				if !index.ContainsColumnID(primaryColID) {
-					extraColumnIDs = append(extraColumnIDs, primaryColID)
+			if !isPrimaryEncoded {
+				for _, primaryColID := range desc.PrimaryIndex.ColumnIDs {
+					if !index.ContainsColumnID(primaryColID) {
+						extraColumnIDs = append(extraColumnIDs, primaryColID)
+					}
 				}
 			}
 			index.ExtraColumnIDs = extraColumnIDs
@@ -975,7 +1016,7 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 				if err != nil {
 					return err
 				}
-				if desc.PrimaryIndex.ContainsColumnID(col.ID) {
+				if !isPrimaryEncoded && desc.PrimaryIndex.ContainsColumnID(col.ID) {
 					// If the primary index contains a stored column, we don't need to
 					// store it - it's already part of the index.
 					err = pgerror.Newf(
@@ -1335,6 +1376,10 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 		return errors.AssertionFailedf("invalid parent ID %d", errors.Safe(desc.ParentID))
 	}
 
+	if desc.Temporary && desc.TemporarySchemaID == 0 {
+		return errors.AssertionFailedf("invalid temporary schema ID %d", errors.Safe(desc.TemporarySchemaID))
+	}
+
 	// We maintain forward compatibility, so if you see this error message with a
 	// version older that what this client supports, then there's a
 	// MaybeFillInDescriptor missing from some codepath.
@@ -1416,6 +1461,12 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 					"mutation in state %s, direction %s, constraint %v",
 					errors.Safe(m.State), errors.Safe(m.Direction), desc.Constraint.Name)
 			}
+		case *DescriptorMutation_PrimaryKeySwap:
+			if unSetEnums {
+				return errors.AssertionFailedf(
+					"mutation in state %s, direction %s, primary key swap to index %d",
+					errors.Safe(m.State), errors.Safe(m.Direction), errors.Safe(desc.PrimaryKeySwap.NewPrimaryIndexID))
+			}
 		default:
 			return errors.AssertionFailedf(
 				"mutation in state %s, direction %s, and no column/index descriptor",
@@ -1438,6 +1489,9 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 		if err := desc.validatePartitioning(); err != nil {
 			return err
 		}
+		if err := desc.validateTriggers(columnIDs); err != nil {
+			return err
+		}
 	}
 
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
@@ -1515,6 +1569,39 @@ func (desc *TableDescriptor) validateColumnFamilies(
 	return colIDToFamilyID, nil
 }
 
+// validateTriggers validates that the triggers of the table are well formed:
+// their names are unique, they fire on at least one event, and the columns of
+// UPDATE OF triggers exist.
+func (desc *TableDescriptor) validateTriggers(columnIDs map[ColumnID]string) error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if err := validateName(trigger.Name, "trigger"); err != nil {
+			return err
+		}
+		if _, ok := names[trigger.Name]; ok {
+			return fmt.Errorf("duplicate trigger name: %q", trigger.Name)
+		}
+		names[trigger.Name] = struct{}{}
+
+		if !trigger.OnInsert && !trigger.OnUpdate && !trigger.OnDelete {
+			return fmt.Errorf("trigger %q does not fire on any event", trigger.Name)
+		}
+		if len(trigger.UpdateColumnIDs) > 0 && !trigger.OnUpdate {
+			return fmt.Errorf("trigger %q has update columns but does not fire on UPDATE", trigger.Name)
+		}
+		for _, colID := range trigger.UpdateColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return fmt.Errorf("trigger %q contains unknown column \"%d\"", trigger.Name, colID)
+			}
+		}
+		if trigger.Statement == "" {
+			return fmt.Errorf("trigger %q has no statement", trigger.Name)
+		}
+	}
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -2183,6 +2270,18 @@ func (desc *TableDescriptor) FindCheckByName(
 	return nil, fmt.Errorf("check %q does not exist", name)
 }
 
+// FindTriggerByName finds the trigger with the specified name. It returns the
+// index of the trigger in the Triggers slice, or -1 if there is no such
+// trigger.
+func (desc *TableDescriptor) FindTriggerByName(name string) int {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // RenameIndexDescriptor renames an index descriptor.
 func (desc *MutableTableDescriptor) RenameIndexDescriptor(
 	index *IndexDescriptor, name string,
@@ -2444,6 +2543,9 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 			default:
 				return errors.Errorf("unsupported constraint type: %d", t.Constraint.ConstraintType)
 			}
+
+		case *DescriptorMutation_PrimaryKeySwap:
+			return desc.performPrimaryKeySwap(t.PrimaryKeySwap)
 		}
 
 	case DescriptorMutation_DROP:
@@ -2457,6 +2559,179 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 	return nil
 }
 
+// performPrimaryKeySwap makes the index being built for a primary key change
+// the primary index of the table, and replaces the secondary indexes with
+// their rewritten counterparts. The old primary index and the old secondary
+// indexes are queued up for deletion through new DROP mutations. The foreign
+// key references of the replaced indexes move to the indexes returned by
+// PrimaryKeySwapFKReplacements; references held by other descriptors are
+// updated through RemapForeignKeyIndexes.
+func (desc *MutableTableDescriptor) performPrimaryKeySwap(swap *PrimaryKeySwap) error {
+	if len(swap.OldIndexes) != len(swap.NewIndexes) {
+		return errors.AssertionFailedf(
+			"primary key swap has %d old indexes but %d new indexes",
+			len(swap.OldIndexes), len(swap.NewIndexes))
+	}
+	replacements, err := desc.PrimaryKeySwapFKReplacements(swap)
+	if err != nil {
+		return err
+	}
+	for oldID, newID := range replacements {
+		oldIndex, err := desc.FindIndexByID(oldID)
+		if err != nil {
+			return err
+		}
+		newIndex, err := desc.FindIndexByID(newID)
+		if err != nil {
+			return err
+		}
+		newIndex.ForeignKey = oldIndex.ForeignKey
+		newIndex.ReferencedBy = append(newIndex.ReferencedBy, oldIndex.ReferencedBy...)
+		oldIndex.ForeignKey, oldIndex.ReferencedBy = ForeignKeyReference{}, nil
+	}
+	RemapForeignKeyIndexes(desc, desc.ID, replacements)
+	removeIndex := func(id IndexID) (*IndexDescriptor, error) {
+		for i := range desc.Indexes {
+			if desc.Indexes[i].ID == id {
+				idx := protoutil.Clone(&desc.Indexes[i]).(*IndexDescriptor)
+				desc.Indexes = append(desc.Indexes[:i], desc.Indexes[i+1:]...)
+				return idx, nil
+			}
+		}
+		return nil, errors.AssertionFailedf("index %d is not a public secondary index", id)
+	}
+
+	// The old primary index keeps using the primary index encoding while it is
+	// being dropped, so it must store all the columns that are not part of its
+	// key.
+	oldPrimaryIndex := protoutil.Clone(&desc.PrimaryIndex).(*IndexDescriptor)
+	oldPrimaryIndex.EncodingType = PrimaryIndexEncoding
+	oldPrimaryIndex.StoreColumnIDs, oldPrimaryIndex.StoreColumnNames = nil, nil
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if !oldPrimaryIndex.ContainsColumnID(col.ID) {
+			oldPrimaryIndex.StoreColumnIDs = append(oldPrimaryIndex.StoreColumnIDs, col.ID)
+			oldPrimaryIndex.StoreColumnNames = append(oldPrimaryIndex.StoreColumnNames, col.Name)
+		}
+	}
+
+	newPrimaryIndex, err := removeIndex(swap.NewPrimaryIndexID)
+	if err != nil {
+		return err
+	}
+	newPrimaryIndex.Name = PrimaryKeyIndexName
+	newPrimaryIndex.EncodingType = SecondaryIndexEncoding
+	newPrimaryIndex.StoreColumnIDs, newPrimaryIndex.StoreColumnNames = nil, nil
+	desc.PrimaryIndex = *newPrimaryIndex
+	if err := desc.AddIndexMutation(oldPrimaryIndex, DescriptorMutation_DROP); err != nil {
+		return err
+	}
+
+	// Each rewritten index takes over the name of the index it replaces.
+	for i := range swap.OldIndexes {
+		oldIndex, err := removeIndex(swap.OldIndexes[i])
+		if err != nil {
+			return err
+		}
+		newIndex, err := desc.FindIndexByID(swap.NewIndexes[i])
+		if err != nil {
+			return err
+		}
+		newIndex.Name = oldIndex.Name
+		if err := desc.AddIndexMutation(oldIndex, DescriptorMutation_DROP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrimaryKeySwapFKReplacements maps each index replaced by the given primary
+// key swap that participates in a foreign key to the index taking over its
+// references: a rewritten secondary index replaces the index it was built
+// from, and the unique index added on the old primary key columns replaces
+// the old primary index. It must be called before the swap is performed, once
+// the indexes added alongside it are public.
+func (desc *TableDescriptor) PrimaryKeySwapFKReplacements(
+	swap *PrimaryKeySwap,
+) (map[IndexID]IndexID, error) {
+	hasFKs := func(idx *IndexDescriptor) bool {
+		return idx.ForeignKey.IsSet() || len(idx.ReferencedBy) > 0
+	}
+	replacements := make(map[IndexID]IndexID)
+	for i := range swap.OldIndexes {
+		oldIndex, err := desc.FindIndexByID(swap.OldIndexes[i])
+		if err != nil {
+			return nil, err
+		}
+		if hasFKs(oldIndex) {
+			replacements[swap.OldIndexes[i]] = swap.NewIndexes[i]
+		}
+	}
+	if !hasFKs(&desc.PrimaryIndex) {
+		return replacements, nil
+	}
+	isSwapIndex := func(id IndexID) bool {
+		if id == swap.NewPrimaryIndexID {
+			return true
+		}
+		for i := range swap.OldIndexes {
+			if id == swap.OldIndexes[i] || id == swap.NewIndexes[i] {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range desc.Indexes {
+		idx := &desc.Indexes[i]
+		if idx.Unique && !isSwapIndex(idx.ID) &&
+			ColumnIDs(idx.ColumnIDs).Equals(ColumnIDs(desc.PrimaryIndex.ColumnIDs)) {
+			replacements[desc.PrimaryIndex.ID] = idx.ID
+			return replacements, nil
+		}
+	}
+	return nil, errors.AssertionFailedf(
+		"no unique index on the columns of primary index %q to move its foreign keys to",
+		desc.PrimaryIndex.Name)
+}
+
+// RemapForeignKeyIndexes updates the foreign key references in desc that
+// point at an index of the table with the given ID according to replacements,
+// which maps the IDs of replaced indexes to the IDs of their replacements.
+func RemapForeignKeyIndexes(
+	desc *MutableTableDescriptor, tableID ID, replacements map[IndexID]IndexID,
+) {
+	remap := func(idx *IndexDescriptor) {
+		if idx.ForeignKey.IsSet() && idx.ForeignKey.Table == tableID {
+			if newID, ok := replacements[idx.ForeignKey.Index]; ok {
+				idx.ForeignKey.Index = newID
+			}
+		}
+		for i := range idx.ReferencedBy {
+			ref := &idx.ReferencedBy[i]
+			if ref.Table != tableID {
+				continue
+			}
+			if newID, ok := replacements[ref.Index]; ok {
+				ref.Index = newID
+			}
+		}
+	}
+	remap(&desc.PrimaryIndex)
+	for i := range desc.Indexes {
+		remap(&desc.Indexes[i])
+	}
+}
+
+// AddPrimaryKeySwapMutation adds a PrimaryKeySwap mutation to desc.Mutations.
+// The swap is performed once all the indexes it references have been added.
+func (desc *MutableTableDescriptor) AddPrimaryKeySwapMutation(swap *PrimaryKeySwap) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_PrimaryKeySwap{PrimaryKeySwap: swap},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
 // AddCheckMutation adds a check constraint mutation to desc.Mutations.
 func (desc *MutableTableDescriptor) AddCheckMutation(ck *TableDescriptor_CheckConstraint) {
 	m := DescriptorMutation{
@@ -2771,6 +3046,156 @@ func (desc *DatabaseDescriptor) Validate() error {
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+// SetID implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *TypeDescriptor) TypeName() string {
+	return "type"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// This is a stub since auditing is not supported for types.
+func (desc *TypeDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the type descriptor is well formed. Checks include
+// verifying that the enum members are unique and sorted by their physical
+// representations.
+func (desc *TypeDescriptor) Validate() error {
+	if err := validateName(desc.Name, "type"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return errors.AssertionFailedf("invalid type ID %d", errors.Safe(desc.ID))
+	}
+	if desc.ParentID == 0 {
+		return errors.AssertionFailedf("invalid parent ID %d", errors.Safe(desc.ParentID))
+	}
+	labels := make(map[string]struct{}, len(desc.EnumMembers))
+	for i := range desc.EnumMembers {
+		member := &desc.EnumMembers[i]
+		if _, ok := labels[member.LogicalRepresentation]; ok {
+			return errors.AssertionFailedf("duplicate enum member %q", member.LogicalRepresentation)
+		}
+		labels[member.LogicalRepresentation] = struct{}{}
+		if i > 0 && bytes.Compare(
+			desc.EnumMembers[i-1].PhysicalRepresentation, member.PhysicalRepresentation) >= 0 {
+			return errors.AssertionFailedf("enum members of type %q are not sorted", desc.Name)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// MakeTypesT creates a types.T from the type descriptor. The returned type
+// carries the metadata needed to encode, decode and display its values.
+func (desc *TypeDescriptor) MakeTypesT() *types.T {
+	physReps := make([][]byte, len(desc.EnumMembers))
+	logReps := make([]string, len(desc.EnumMembers))
+	var isReadOnly []bool
+	for i := range desc.EnumMembers {
+		physReps[i] = desc.EnumMembers[i].PhysicalRepresentation
+		logReps[i] = desc.EnumMembers[i].LogicalRepresentation
+		if desc.EnumMembers[i].Capability == TypeDescriptor_EnumMember_READ_ONLY {
+			if isReadOnly == nil {
+				isReadOnly = make([]bool, len(desc.EnumMembers))
+			}
+			isReadOnly[i] = true
+		}
+	}
+	return types.MakeEnum(uint32(desc.ID), desc.Name, physReps, logReps, isReadOnly)
+}
+
+// HasReadOnlyEnumMembers returns whether any member of the enum type is still
+// read-only. See TypeDescriptor_EnumMember_READ_ONLY.
+func (desc *TypeDescriptor) HasReadOnlyEnumMembers() bool {
+	for i := range desc.EnumMembers {
+		if desc.EnumMembers[i].Capability == TypeDescriptor_EnumMember_READ_ONLY {
+			return true
+		}
+	}
+	return false
+}
+
+// MakeEnumMembersWritable marks all members of the enum type as writable.
+func (desc *TypeDescriptor) MakeEnumMembersWritable() {
+	for i := range desc.EnumMembers {
+		desc.EnumMembers[i].Capability = TypeDescriptor_EnumMember_ALL
+	}
+}
+
+// AddReferencingDescriptorID adds the given table ID to the list of
+// descriptors referencing the type, if it is not already present. It returns
+// whether the ID was added.
+func (desc *TypeDescriptor) AddReferencingDescriptorID(id ID) bool {
+	for _, refID := range desc.ReferencingDescriptorIDs {
+		if refID == id {
+			return false
+		}
+	}
+	desc.ReferencingDescriptorIDs = append(desc.ReferencingDescriptorIDs, id)
+	return true
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// This is a stub since auditing is not supported for functions.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the function descriptor is well formed. Checks
+// include verifying that the parameter names are unique and that the function
+// has a body.
+func (desc *FunctionDescriptor) Validate() error {
+	if err := validateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return errors.AssertionFailedf("invalid function ID %d", errors.Safe(desc.ID))
+	}
+	if desc.ParentID == 0 {
+		return errors.AssertionFailedf("invalid parent ID %d", errors.Safe(desc.ParentID))
+	}
+	names := make(map[string]struct{}, len(desc.Params))
+	for i := range desc.Params {
+		name := desc.Params[i].Name
+		if name == "" {
+			continue
+		}
+		if _, ok := names[name]; ok {
+			return errors.AssertionFailedf("duplicate parameter name %q", name)
+		}
+		names[name] = struct{}{}
+	}
+	if desc.Body == "" {
+		return errors.AssertionFailedf("function %q has no body", desc.Name)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
 // GetID returns the ID of the descriptor.
 func (desc *Descriptor) GetID() ID {
 	switch t := desc.Union.(type) {
@@ -2778,6 +3203,10 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Type:
+		return t.Type.ID
+	case *Descriptor_Function:
+		return t.Function.ID
 	default:
 		return 0
 	}
@@ -2790,6 +3219,10 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Type:
+		return t.Type.Name
+	case *Descriptor_Function:
+		return t.Function.Name
 	default:
 		return ""
 	}
@@ -2853,7 +3286,17 @@ func (desc TableDescriptor) GetDescMetadataKey() roachpb.Key {
 
 // GetNameMetadataKey returns the namespace key for the table.
 func (desc TableDescriptor) GetNameMetadataKey() roachpb.Key {
-	return MakeNameMetadataKey(desc.ParentID, desc.Name)
+	return MakeNameMetadataKey(desc.GetNamespaceParentID(), desc.Name)
+}
+
+// GetNamespaceParentID returns the ID under which the name of the table is
+// recorded in the namespace: the ID of the temporary schema for temporary
+// tables, and the ID of the parent database otherwise.
+func (desc *TableDescriptor) GetNamespaceParentID() ID {
+	if desc.Temporary {
+		return desc.TemporarySchemaID
+	}
+	return desc.ParentID
 }
 
 // SQLString returns the SQL statement describing the column.
@@ -3264,3 +3707,26 @@ func (tk TableKey) Key() roachpb.Key {
 func (tk TableKey) Name() string {
 	return tk.name
 }
+
+// SchemaKey implements DescriptorKey interface. Only the temporary schemas of
+// the sessions are recorded in the namespace; they share the namespace of
+// their parent database with the tables.
+type SchemaKey struct {
+	parentID ID
+	name     string
+}
+
+// NewSchemaKey returns a new SchemaKey.
+func NewSchemaKey(parentID ID, name string) SchemaKey {
+	return SchemaKey{parentID, name}
+}
+
+// Key implements DescriptorKey interface.
+func (sk SchemaKey) Key() roachpb.Key {
+	return MakeNameMetadataKey(sk.parentID, sk.name)
+}
+
+// Name implements DescriptorKey interface.
+func (sk SchemaKey) Name() string {
+	return sk.name
+}
@@ -224,6 +224,14 @@ CREATE TABLE system.comments (
    comment   STRING NOT NULL, -- the comment
    PRIMARY KEY (type, object_id, sub_id)
 );`
+
+	ProtectedTimestampsRecordsTableSchema = `
+CREATE TABLE system.protected_ts_records (
+   id        UUID NOT NULL PRIMARY KEY, -- the ID of the record
+   ts        DECIMAL NOT NULL,          -- the protected timestamp
+   job_id    INT8 NOT NULL,             -- the ID of the job owning the record
+   spans     BYTES NOT NULL             -- the encoded spans protected by the record
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -264,6 +272,8 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.LocationsTableID:       privilege.ReadWriteData,
 	keys.RoleMembersTableID:     privilege.ReadWriteData,
 	keys.CommentsTableID:        privilege.ReadWriteData,
+
+	keys.ProtectedTimestampsRecordsTableID: privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -860,6 +870,38 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// ProtectedTimestampsRecordsTable is the descriptor for the protected
+	// timestamp records table.
+	ProtectedTimestampsRecordsTable = TableDescriptor{
+		Name:     "protected_ts_records",
+		ID:       keys.ProtectedTimestampsRecordsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Uuid},
+			{Name: "ts", ID: 2, Type: *types.Decimal},
+			{Name: "job_id", ID: 3, Type: *types.Int},
+			{Name: "spans", ID: 4, Type: *types.Bytes},
+		},
+		NextColumnID: 5,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ID:          0,
+				ColumnNames: []string{"id", "ts", "job_id", "spans"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("id"),
+		NextIndexID:  2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.ProtectedTimestampsRecordsTableID],
+		),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -905,6 +947,10 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	// The CommentsTable has been introduced in 2.2. It was added here since it
 	// was introduced, but it's also created as a migration for older clusters.
 	target.AddDescriptor(keys.SystemDatabaseID, &CommentsTable)
+
+	// The ProtectedTimestampsRecordsTable was introduced in 19.2, and is also
+	// created as a migration for older clusters.
+	target.AddDescriptor(keys.SystemDatabaseID, &ProtectedTimestampsRecordsTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
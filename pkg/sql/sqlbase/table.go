@@ -102,6 +102,11 @@ func ValidateColumnDefType(t *types.T) error {
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily:
 		// These types are OK.
 
+	case types.EnumFamily:
+		if t.IsUnresolvedUserDefinedType() {
+			return errors.AssertionFailedf("unresolved type %s", t.Name())
+		}
+
 	default:
 		return pgerror.Newf(pgcode.InvalidTableDefinition,
 			"value type %s cannot be used for table columns", t.String())
@@ -151,11 +156,15 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != tree.NotNull && !d.PrimaryKey,
 	}
 
-	// Validate and assign column type.
-	err := ValidateColumnDefType(d.Type)
+	// Resolve, validate and assign column type.
+	typ, err := tree.ResolveType(d.Type, semaCtx)
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	d.Type = typ
+	if err := ValidateColumnDefType(d.Type); err != nil {
+		return nil, nil, nil, err
+	}
 	col.Type = *d.Type
 
 	var typedExpr tree.TypedExpr
@@ -338,7 +347,7 @@ func (desc *TableDescriptor) collectConstraintInfo(
 			detail.Columns = index.ColumnNames
 			detail.Index = index
 			info[index.Name] = detail
-		} else if index.Unique {
+		} else if index.Unique || index.DeferrableUnique {
 			if _, ok := info[index.Name]; ok {
 				return nil, pgerror.Newf(pgcode.DuplicateObject,
 					"duplicate constraint name: %q", index.Name)
@@ -20,7 +20,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -141,7 +143,7 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		log.Infof(ctx, "reading mutable descriptor on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
+	if tn.SchemaName != tree.PublicSchemaName && !sessiondata.IsTemporarySchemaName(tn.Schema()) {
 		if flags.required {
 			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
 		}
@@ -164,7 +166,19 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		}
 	}
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	// Objects in a temporary schema are stored under the ID of the schema.
+	parentID := dbID
+	if tn.SchemaName != tree.PublicSchemaName {
+		parentID, err = getTemporarySchemaID(ctx, txn, dbID, tn.Schema())
+		if err != nil || parentID == sqlbase.InvalidID {
+			if err == nil && flags.required {
+				err = sqlbase.NewUndefinedRelationError(tn)
+			}
+			return nil, err
+		}
+	}
+
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, err
 	} else if mut := table.MutableTableDescriptor; mut != nil {
 		log.VEventf(ctx, 2, "found uncommitted table %d", mut.ID)
@@ -197,7 +211,7 @@ func (tc *TableCollection) getTableVersion(
 		log.Infof(ctx, "planner acquiring lease on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
+	if tn.SchemaName != tree.PublicSchemaName && !sessiondata.IsTemporarySchemaName(tn.Schema()) {
 		if flags.required {
 			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
 		}
@@ -220,6 +234,18 @@ func (tc *TableCollection) getTableVersion(
 		}
 	}
 
+	// Objects in a temporary schema are stored under the ID of the schema.
+	parentID := dbID
+	if tn.SchemaName != tree.PublicSchemaName {
+		parentID, err = getTemporarySchemaID(ctx, txn, dbID, tn.Schema())
+		if err != nil || parentID == sqlbase.InvalidID {
+			if err == nil && flags.required {
+				err = sqlbase.NewUndefinedRelationError(tn)
+			}
+			return nil, err
+		}
+	}
+
 	// TODO(vivek): Ideally we'd avoid caching for only the
 	// system.descriptor and system.lease tables, because they are
 	// used for acquiring leases, creating a chicken&egg problem.
@@ -230,7 +256,7 @@ func (tc *TableCollection) getTableVersion(
 	avoidCache := flags.avoidCached || testDisableTableLeases ||
 		(tn.Catalog() == sqlbase.SystemDB.Name && tn.TableName.String() != sqlbase.RoleMembersTable.Name)
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, err
 	} else if immut := table.ImmutableTableDescriptor; immut != nil {
 		// If not forcing to resolve using KV, tables being added aren't visible.
@@ -265,14 +291,14 @@ func (tc *TableCollection) getTableVersion(
 	// transaction.
 	for _, table := range tc.leasedTables {
 		if table.Name == string(tn.TableName) &&
-			table.ParentID == dbID {
+			table.GetNamespaceParentID() == parentID {
 			log.VEventf(ctx, 2, "found table in table collection for table '%s'", tn)
 			return table, nil
 		}
 	}
 
 	origTimestamp := txn.OrigTimestamp()
-	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, dbID, tn.Table())
+	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, parentID, tn.Table())
 	if err != nil {
 		// Read the descriptor from the store in the face of some specific errors
 		// because of a known limitation of AcquireByName. See the known
@@ -391,6 +417,16 @@ func (tc *TableCollection) releaseLeases(ctx context.Context) {
 	}
 }
 
+// maxLeasedModificationTime returns the latest modification time of the
+// tables leased by the TableCollection.
+func (tc *TableCollection) maxLeasedModificationTime() hlc.Timestamp {
+	var ts hlc.Timestamp
+	for _, table := range tc.leasedTables {
+		ts.Forward(table.ModificationTime)
+	}
+	return ts
+}
+
 // releaseTables releases all tables currently held by the TableCollection.
 func (tc *TableCollection) releaseTables(ctx context.Context) {
 	tc.releaseLeases(ctx)
@@ -518,7 +554,7 @@ func (tc *TableCollection) getUncommittedDatabaseID(
 // cache and go to KV (where the descriptor prior to the DROP may
 // still exist).
 func (tc *TableCollection) getUncommittedTable(
-	dbID sqlbase.ID, tn *tree.TableName, required bool,
+	parentID sqlbase.ID, tn *tree.TableName, required bool,
 ) (refuseFurtherLookup bool, table uncommittedTable, err error) {
 	// Walk latest to earliest so that a DROP TABLE followed by a CREATE TABLE
 	// with the same name will result in the CREATE TABLE being seen.
@@ -532,7 +568,7 @@ func (tc *TableCollection) getUncommittedTable(
 		// effect of it.
 		for _, drain := range mutTbl.DrainingNames {
 			if drain.Name == string(tn.TableName) &&
-				drain.ParentID == dbID {
+				drain.ParentID == parentID {
 				// Table name has gone away.
 				if required {
 					// If it's required here, say it doesn't exist.
@@ -546,7 +582,7 @@ func (tc *TableCollection) getUncommittedTable(
 
 		// Do we know about a table with this name?
 		if mutTbl.Name == string(tn.TableName) &&
-			mutTbl.ParentID == dbID {
+			mutTbl.GetNamespaceParentID() == parentID {
 			// Right state?
 			if err = filterTableState(mutTbl.TableDesc()); err != nil && err != errTableAdding {
 				if !required {
@@ -124,6 +124,11 @@ type tableWriterBase struct {
 	b *client.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// triggers fires the triggers of the table, if any.
+	triggers *tableTriggers
+	// checks accumulates the uniqueness checks of the DEFERRABLE UNIQUE
+	// constraints that are run once all the rows have been written.
+	checks row.DeferredChecks
 }
 
 func (tb *tableWriterBase) init(txn *client.Txn) {
@@ -131,6 +136,39 @@ func (tb *tableWriterBase) init(txn *client.Txn) {
 	tb.b = txn.NewBatch()
 }
 
+// initStatementChecks prepares the checks run once all the rows have been
+// written, whose memory is accounted against the monitor of the given
+// EvalContext. It returns the DeferredChecks to pass to the row writer.
+func (tb *tableWriterBase) initStatementChecks(evalCtx *tree.EvalContext) *row.DeferredChecks {
+	if evalCtx == nil {
+		return nil
+	}
+	tb.checks.Init(evalCtx.Mon.MakeBoundAccount())
+	return &tb.checks
+}
+
+// initTriggers prepares the triggers of the table that can be fired by the
+// given kinds of mutations, and fires the BEFORE STATEMENT triggers.
+// updateCols are the columns updated by the table writer, if any. Table
+// writers that are used internally (e.g. by TRUNCATE) are initialized without
+// an EvalContext and do not fire triggers.
+func (tb *tableWriterBase) initTriggers(
+	evalCtx *tree.EvalContext,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	events []tree.TriggerEventType,
+	updateCols []sqlbase.ColumnDescriptor,
+) error {
+	if evalCtx == nil || len(tableDesc.Triggers) == 0 {
+		return nil
+	}
+	triggers, err := newTableTriggers(evalCtx, tb.txn, tableDesc, events, updateCols)
+	if err != nil || triggers == nil {
+		return err
+	}
+	tb.triggers = triggers
+	return triggers.beforeStatement(evalCtx.Context)
+}
+
 // flushAndStartNewBatch shares the common flushAndStartNewBatch()
 // code between extendedTableWriters.
 func (tb *tableWriterBase) flushAndStartNewBatch(
@@ -151,10 +189,11 @@ func (tb *tableWriterBase) curBatchSize() int { return tb.batchSize }
 func (tb *tableWriterBase) finalize(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor,
 ) (err error) {
-	if tb.autoCommit == autoCommitEnabled {
+	if tb.autoCommit == autoCommitEnabled && tb.triggers == nil && tb.checks.Len() == 0 {
 		// An auto-txn can commit the transaction with the batch. This is an
 		// optimization to avoid an extra round-trip to the transaction
-		// coordinator.
+		// coordinator. It is not possible if checks need to be run or triggers
+		// need to be fired once the rows have been written.
 		err = tb.txn.CommitInBatch(ctx, tb.b)
 	} else {
 		err = tb.txn.Run(ctx, tb.b)
@@ -163,9 +202,24 @@ func (tb *tableWriterBase) finalize(
 	if err != nil {
 		return row.ConvertBatchError(ctx, tableDesc, tb.b)
 	}
+	if err := tb.checks.RunChecks(ctx, tb.txn); err != nil {
+		return err
+	}
+	if tb.triggers != nil {
+		return tb.triggers.afterStatement(ctx)
+	}
 	return nil
 }
 
+// close frees the resources held by the triggers, if any, and the checks.
+func (tb *tableWriterBase) close(ctx context.Context) {
+	if tb.triggers != nil {
+		tb.triggers.close(ctx)
+		tb.triggers = nil
+	}
+	tb.checks.Reset(ctx)
+}
+
 func (tb *tableWriterBase) enableAutoCommit() {
 	tb.autoCommit = autoCommitEnabled
 }
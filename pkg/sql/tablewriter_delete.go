@@ -41,9 +41,11 @@ func (*tableDeleter) desc() string { return "deleter" }
 func (td *tableDeleter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
 
 // init is part of the tableWriter interface.
-func (td *tableDeleter) init(txn *client.Txn, _ *tree.EvalContext) error {
+func (td *tableDeleter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
 	td.tableWriterBase.init(txn)
-	return nil
+	return td.initTriggers(
+		evalCtx, td.tableDesc(), []tree.TriggerEventType{tree.TriggerEventDelete}, nil, /* updateCols */
+	)
 }
 
 // flushAndStartNewBatch is part of the extendedTableWriter interface.
@@ -61,6 +63,11 @@ func (td *tableDeleter) atBatchEnd(_ context.Context, _ bool) error { return nil
 
 func (td *tableDeleter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	td.batchSize++
+	if td.triggers != nil {
+		if err := td.triggers.onDelete(ctx, td.rd.FetchCols, values); err != nil {
+			return err
+		}
+	}
 	return td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV)
 }
 
@@ -162,7 +169,13 @@ func (td *tableDeleter) deleteAllRowsScan(
 		ValNeededForCol: valNeededForCol,
 	}
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		td.alloc,
+		tableArgs,
 	); err != nil {
 		return resume, err
 	}
@@ -274,7 +287,13 @@ func (td *tableDeleter) deleteIndexScan(
 		ValNeededForCol: valNeededForCol,
 	}
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		td.alloc,
+		tableArgs,
 	); err != nil {
 		return resume, err
 	}
@@ -308,4 +327,6 @@ func (td *tableDeleter) tableDesc() *sqlbase.ImmutableTableDescriptor {
 	return td.rd.Helper.TableDesc
 }
 
-func (td *tableDeleter) close(_ context.Context) {}
+func (td *tableDeleter) close(ctx context.Context) {
+	td.tableWriterBase.close(ctx)
+}
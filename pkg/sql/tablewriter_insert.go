@@ -30,14 +30,21 @@ type tableInserter struct {
 func (*tableInserter) desc() string { return "inserter" }
 
 // init is part of the tableWriter interface.
-func (ti *tableInserter) init(txn *client.Txn, _ *tree.EvalContext) error {
+func (ti *tableInserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
 	ti.tableWriterBase.init(txn)
-	return nil
+	return ti.initTriggers(
+		evalCtx, ti.tableDesc(), []tree.TriggerEventType{tree.TriggerEventInsert}, nil, /* updateCols */
+	)
 }
 
 // row is part of the tableWriter interface.
 func (ti *tableInserter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	ti.batchSize++
+	if ti.triggers != nil {
+		if err := ti.triggers.onInsert(ctx, ti.ri.InsertCols, values); err != nil {
+			return err
+		}
+	}
 	return ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV)
 }
 
@@ -60,7 +67,9 @@ func (ti *tableInserter) tableDesc() *sqlbase.ImmutableTableDescriptor {
 }
 
 // close is part of the tableWriter interface.
-func (ti *tableInserter) close(_ context.Context) {}
+func (ti *tableInserter) close(ctx context.Context) {
+	ti.tableWriterBase.close(ctx)
+}
 
 // walkExprs is part of the tableWriter interface.
 func (ti *tableInserter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
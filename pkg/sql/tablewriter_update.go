@@ -30,9 +30,11 @@ type tableUpdater struct {
 func (*tableUpdater) desc() string { return "updater" }
 
 // init is part of the tableWriter interface.
-func (tu *tableUpdater) init(txn *client.Txn, _ *tree.EvalContext) error {
+func (tu *tableUpdater) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
 	tu.tableWriterBase.init(txn)
-	return nil
+	return tu.initTriggers(
+		evalCtx, tu.tableDesc(), []tree.TriggerEventType{tree.TriggerEventUpdate}, tu.ru.UpdateCols,
+	)
 }
 
 // row is part of the tableWriter interface.
@@ -48,6 +50,13 @@ func (tu *tableUpdater) rowForUpdate(
 	ctx context.Context, oldValues, updateValues tree.Datums, traceKV bool,
 ) (tree.Datums, error) {
 	tu.batchSize++
+	if tu.triggers != nil {
+		if err := tu.triggers.onUpdate(
+			ctx, tu.ru.FetchCols, oldValues, tu.ru.UpdateCols, updateValues,
+		); err != nil {
+			return nil, err
+		}
+	}
 	return tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, row.CheckFKs, traceKV)
 }
 
@@ -70,7 +79,9 @@ func (tu *tableUpdater) tableDesc() *sqlbase.ImmutableTableDescriptor {
 }
 
 // close is part of the tableWriter interface.
-func (tu *tableUpdater) close(_ context.Context) {}
+func (tu *tableUpdater) close(ctx context.Context) {
+	tu.tableWriterBase.close(ctx)
+}
 
 // walkExprs is part of the tableWriter interface.
 func (tu *tableUpdater) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
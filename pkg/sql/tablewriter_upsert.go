@@ -107,9 +107,26 @@ func (tu *tableUpserterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) er
 
 	tu.indexKeyPrefix = sqlbase.MakeIndexKeyPrefix(tableDesc.TableDesc(), tableDesc.PrimaryIndex.ID)
 
+	// Like its FK constraints, an upsert checks the DEFERRABLE UNIQUE
+	// constraints by the end of the statement, even when they are deferred.
+	tu.ri.DeferChecks(nil /* deferred */, tu.initStatementChecks(evalCtx))
+
 	return nil
 }
 
+// initUpsertTriggers prepares the triggers of the table that can be fired by
+// an upsert that updates the given columns of conflicting rows. If no columns
+// are updated, only INSERT triggers are fired.
+func (tu *tableUpserterBase) initUpsertTriggers(
+	evalCtx *tree.EvalContext, updateCols []sqlbase.ColumnDescriptor,
+) error {
+	events := []tree.TriggerEventType{tree.TriggerEventInsert}
+	if len(updateCols) > 0 {
+		events = append(events, tree.TriggerEventUpdate)
+	}
+	return tu.initTriggers(evalCtx, tu.tableDesc(), events, updateCols)
+}
+
 func (tu *tableUpserterBase) tableDesc() *sqlbase.ImmutableTableDescriptor {
 	return tu.ri.Helper.TableDesc
 }
@@ -148,6 +165,7 @@ func (tu *tableUpserterBase) curBatchSize() int { return tu.insertRows.Len() }
 
 // close is part of the tableWriter interface.
 func (tu *tableUpserterBase) close(ctx context.Context) {
+	tu.tableWriterBase.close(ctx)
 	tu.insertRows.Close(ctx)
 	if tu.existingRows != nil {
 		tu.existingRows.Close(ctx)
@@ -299,6 +317,7 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 		if err != nil {
 			return err
 		}
+		tu.ru.DeferChecks(nil /* deferred */, &tu.checks)
 
 		// t.ru.fetchCols can also contain columns undergoing mutation.
 		tu.fetchCols = tu.ru.FetchCols
@@ -327,7 +346,13 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 	}
 
 	if err := tu.fetcher.Init(
-		false /* reverse */, false /*returnRangeInfo*/, false /* isCheck */, tu.alloc, tableArgs,
+		false, /* reverse */
+		roachpb.NON_LOCKING,
+		roachpb.LOCK_WAIT_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		tu.alloc,
+		tableArgs,
 	); err != nil {
 		return err
 	}
@@ -340,7 +365,12 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 	tu.existingRows = rowcontainer.NewRowContainer(
 		tu.evalCtx.Mon.MakeBoundAccount(), pkColTypeInfo, tu.insertRows.Len(),
 	)
-	return nil
+
+	var updateCols []sqlbase.ColumnDescriptor
+	if len(tu.updateCols) > 0 {
+		updateCols = tu.ru.UpdateCols
+	}
+	return tu.initUpsertTriggers(evalCtx, updateCols)
 }
 
 // atBatchEnd is part of the extendedTableWriter interface.
@@ -587,6 +617,14 @@ func (tu *tableUpserter) updateConflictingRow(
 		}
 	}
 
+	if tu.triggers != nil {
+		if err := tu.triggers.onUpdate(
+			ctx, tu.ru.FetchCols, conflictingRowValues, tu.ru.UpdateCols, updateValues,
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	// Queue the update in KV. This also returns an "update row"
 	// containing the updated values for every column in the
 	// table. This is useful for RETURNING, which we collect below.
@@ -683,6 +721,12 @@ func (tu *tableUpserter) insertNonConflictingRow(
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 	traceKV bool,
 ) (resultRow tree.Datums, err error) {
+	if tu.triggers != nil {
+		if err := tu.triggers.onInsert(ctx, tu.ri.InsertCols, insertRow); err != nil {
+			return nil, err
+		}
+	}
+
 	// Perform the insert proper.
 	if err := tu.ri.InsertRow(
 		ctx, b, insertRow, false /* ignoreConflicts */, row.CheckFKs, traceKV); err != nil {
@@ -90,7 +90,11 @@ func (tu *optTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) err
 		evalCtx,
 		tu.alloc,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	tu.ru.DeferChecks(nil /* deferred */, &tu.checks)
+	return tu.initUpsertTriggers(evalCtx, tu.ru.UpdateCols)
 }
 
 // desc is part of the tableWriter interface.
@@ -149,6 +153,12 @@ func (tu *optTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error
 func (tu *optTableUpserter) insertNonConflictingRow(
 	ctx context.Context, b *client.Batch, insertRow tree.Datums, overwrite, traceKV bool,
 ) error {
+	if tu.triggers != nil {
+		if err := tu.triggers.onInsert(ctx, tu.ri.InsertCols, insertRow); err != nil {
+			return err
+		}
+	}
+
 	// Perform the insert proper.
 	if err := tu.ri.InsertRow(
 		ctx, b, insertRow, overwrite, row.CheckFKs, traceKV); err != nil {
@@ -195,6 +205,14 @@ func (tu *optTableUpserter) updateConflictingRow(
 		return err
 	}
 
+	if tu.triggers != nil {
+		if err := tu.triggers.onUpdate(
+			ctx, tu.ru.FetchCols, fetchRow, tu.ru.UpdateCols, updateValues,
+		); err != nil {
+			return err
+		}
+	}
+
 	// Queue the update in KV. This also returns an "update row"
 	// containing the updated values for every column in the
 	// table. This is useful for RETURNING, which we collect below.
@@ -48,7 +48,10 @@ func (tu *strictTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext)
 		return err
 	}
 
-	return tu.getUniqueIndexes()
+	if err := tu.getUniqueIndexes(); err != nil {
+		return err
+	}
+	return tu.initUpsertTriggers(evalCtx, nil /* updateCols */)
 }
 
 // atBatchEnd is part of the extendedTableWriter interface.
@@ -65,6 +68,12 @@ func (tu *strictTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) err
 			continue
 		}
 
+		if tu.triggers != nil {
+			if err := tu.triggers.onInsert(ctx, tu.ri.InsertCols, insertRow); err != nil {
+				return err
+			}
+		}
+
 		if err := tu.ri.InsertRow(ctx, tu.b, insertRow, true, row.CheckFKs, traceKV); err != nil {
 			return err
 		}
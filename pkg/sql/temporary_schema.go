@@ -0,0 +1,329 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/errors"
+)
+
+// Temporary tables live in a per-session, per-database temporary schema named
+// pg_temp_<session ID>. The schema has no descriptor: it only exists as an
+// entry (database ID, schema name) -> schema ID in system.namespace, and the
+// temporary tables of the session are stored in system.namespace under the
+// schema ID instead of the database ID.
+//
+// The temporary schemas of a session, and the objects in them, are removed
+// when the session ends. Sessions that end abruptly (for example, because
+// their node crashed) leave their temporary schemas behind; these are
+// eventually removed by the TemporaryObjectCleaner.
+
+// TempObjectCleanupInterval is the interval at which the TemporaryObjectCleaner
+// looks for temporary schemas of sessions that no longer exist.
+var TempObjectCleanupInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.temp_object_cleaner.cleanup_interval",
+	"how often to clean up orphaned temporary objects",
+	30*time.Minute,
+)
+
+// temporarySchemaName returns the name of the temporary schema of the session
+// with the given ID.
+func temporarySchemaName(sessionID ClusterWideID) string {
+	return sessiondata.TemporarySchemaName(sessionID.Hi, sessionID.Lo)
+}
+
+// temporarySchemaSessionID returns the ID of the session that owns the
+// temporary schema with the given name.
+func temporarySchemaSessionID(scName string) (ClusterWideID, error) {
+	parts := strings.Split(strings.TrimPrefix(scName, sessiondata.PgTempSchemaName+"_"), "_")
+	if len(parts) != 2 {
+		return ClusterWideID{}, errors.Errorf("malformed temporary schema name %q", scName)
+	}
+	hi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	lo, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	return ClusterWideID{Uint128: uint128.FromInts(hi, lo)}, nil
+}
+
+// getTemporarySchemaID returns the ID of the temporary schema with the given
+// name in the given database, or InvalidID if the schema does not exist.
+func getTemporarySchemaID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	return getDescriptorID(ctx, txn, sqlbase.NewSchemaKey(dbID, scName))
+}
+
+// getTemporarySchemaNames returns the names of the temporary schemas of all
+// the sessions in the given database.
+func getTemporarySchemaNames(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) ([]string, error) {
+	prefix := sqlbase.MakeNameMetadataKey(dbID, "")
+	kvs, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, kv := range kvs {
+		_, name, err := encoding.DecodeUnsafeStringAscending(
+			bytes.TrimPrefix(kv.Key, prefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		if sessiondata.IsTemporarySchemaName(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// getOrCreateTemporarySchema returns the ID of the temporary schema of the
+// session in the given database, creating the schema if needed.
+//
+// The name of the schema is recorded in the session data as soon as the
+// schema is created, so that the rest of the transaction can resolve it. The
+// connExecutor restores the previous name if the transaction doesn't commit.
+func (p *planner) getOrCreateTemporarySchema(
+	ctx context.Context, dbID sqlbase.ID,
+) (sqlbase.ID, error) {
+	tempSchemaName := temporarySchemaName(p.ExtendedEvalContext().SessionID)
+	schemaID, err := getTemporarySchemaID(ctx, p.txn, dbID, tempSchemaName)
+	if err != nil || schemaID != sqlbase.InvalidID {
+		return schemaID, err
+	}
+
+	// Nodes running older versions don't know that temporary schemas are
+	// entries of system.namespace, and would resolve the temporary tables as
+	// tables of the database.
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionTemporarySchemas) {
+		return sqlbase.InvalidID, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			`temporary tables require all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionTemporarySchemas),
+		)
+	}
+
+	id, err := GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+	if err != nil {
+		return sqlbase.InvalidID, err
+	}
+	key := sqlbase.NewSchemaKey(dbID, tempSchemaName).Key()
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "CPut %s -> %d", key, id)
+	}
+	if err := p.txn.CPut(ctx, key, id, nil); err != nil {
+		return sqlbase.InvalidID, err
+	}
+	p.sessionDataMutator.SetTemporarySchemaName(tempSchemaName)
+	return id, nil
+}
+
+// cleanupSessionTempObjects removes the temporary schemas of the session
+// with the given ID, and the objects in them, from all the databases.
+func cleanupSessionTempObjects(
+	ctx context.Context, db *client.DB, ie sqlutil.InternalExecutor, sessionID ClusterWideID,
+) error {
+	tempSchemaName := temporarySchemaName(sessionID)
+	return db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		dbIDs, err := GetAllDatabaseDescriptorIDs(ctx, txn)
+		if err != nil {
+			return err
+		}
+		for _, dbID := range dbIDs {
+			if err := cleanupSchemaObjects(ctx, txn, ie, dbID, tempSchemaName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// cleanupSchemaObjects drops the objects of the given temporary schema in the
+// given database, and then removes the schema itself.
+func cleanupSchemaObjects(
+	ctx context.Context,
+	txn *client.Txn,
+	ie sqlutil.InternalExecutor,
+	dbID sqlbase.ID,
+	tempSchemaName string,
+) error {
+	schemaID, err := getTemporarySchemaID(ctx, txn, dbID, tempSchemaName)
+	if err != nil || schemaID == sqlbase.InvalidID {
+		return err
+	}
+	dbDesc, err := sqlbase.GetDatabaseDescFromID(ctx, txn, dbID)
+	if err != nil {
+		return err
+	}
+
+	prefix := sqlbase.MakeNameMetadataKey(schemaID, "")
+	kvs, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return err
+	}
+	for _, kv := range kvs {
+		_, tableName, err := encoding.DecodeUnsafeStringAscending(
+			bytes.TrimPrefix(kv.Key, prefix), nil)
+		if err != nil {
+			return err
+		}
+		tn := tree.MakeTableNameWithSchema(
+			tree.Name(dbDesc.Name), tree.Name(tempSchemaName), tree.Name(tableName))
+		if _, err := ie.Exec(
+			ctx, "delete-temp-table", txn, "DROP TABLE IF EXISTS "+tn.FQString()+" CASCADE",
+		); err != nil {
+			return err
+		}
+	}
+
+	// The schema may exist even if it does not contain any objects, for
+	// example if all the temporary tables of the session were dropped.
+	return txn.Del(ctx, sqlbase.NewSchemaKey(dbID, tempSchemaName).Key())
+}
+
+// isMeta1LeaseholderFunc returns whether the node holds the lease on the
+// meta1 range at the given timestamp.
+type isMeta1LeaseholderFunc func(hlc.Timestamp) (bool, error)
+
+// TemporaryObjectCleaner periodically removes the temporary schemas, and the
+// objects in them, of the sessions that no longer exist. Only the node that
+// holds the lease on the meta1 range does the cleanup, so that at most one
+// node does it at a time.
+type TemporaryObjectCleaner struct {
+	settings           *cluster.Settings
+	db                 *client.DB
+	ie                 sqlutil.InternalExecutor
+	statusServer       serverpb.StatusServer
+	isMeta1Leaseholder isMeta1LeaseholderFunc
+	clock              *hlc.Clock
+}
+
+// NewTemporaryObjectCleaner initializes the TemporaryObjectCleaner with the
+// required arguments, but does not start it.
+func NewTemporaryObjectCleaner(
+	settings *cluster.Settings,
+	db *client.DB,
+	ie sqlutil.InternalExecutor,
+	statusServer serverpb.StatusServer,
+	isMeta1Leaseholder isMeta1LeaseholderFunc,
+	clock *hlc.Clock,
+) *TemporaryObjectCleaner {
+	return &TemporaryObjectCleaner{
+		settings:           settings,
+		db:                 db,
+		ie:                 ie,
+		statusServer:       statusServer,
+		isMeta1Leaseholder: isMeta1Leaseholder,
+		clock:              clock,
+	}
+}
+
+// doTemporaryObjectCleanup removes the temporary schemas of the sessions that
+// are not active anymore.
+func (c *TemporaryObjectCleaner) doTemporaryObjectCleanup(ctx context.Context) error {
+	isLeaseholder, err := c.isMeta1Leaseholder(c.clock.Now())
+	if err != nil || !isLeaseholder {
+		return err
+	}
+
+	// Collect the sessions that own a temporary schema.
+	sessionIDs := make(map[ClusterWideID]struct{})
+	if err := c.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		dbIDs, err := GetAllDatabaseDescriptorIDs(ctx, txn)
+		if err != nil {
+			return err
+		}
+		for _, dbID := range dbIDs {
+			names, err := getTemporarySchemaNames(ctx, txn, dbID)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				id, err := temporarySchemaSessionID(name)
+				if err != nil {
+					log.Warningf(ctx, "skipping temporary schema: %v", err)
+					continue
+				}
+				sessionIDs[id] = struct{}{}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	// Remove the sessions that are still active. If the status of some nodes
+	// could not be retrieved, skip this round: the temporary schemas of their
+	// sessions cannot be told apart from orphaned ones.
+	response, err := c.statusServer.ListSessions(ctx, &serverpb.ListSessionsRequest{})
+	if err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		return errors.Errorf("could not list the sessions of all nodes: %s", response.Errors[0].Message)
+	}
+	for _, session := range response.Sessions {
+		delete(sessionIDs, BytesToClusterWideID(session.ID))
+	}
+
+	for sessionID := range sessionIDs {
+		log.Infof(ctx, "cleaning up temporary objects of session %s", sessionID)
+		if err := cleanupSessionTempObjects(ctx, c.db, c.ie, sessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs the TemporaryObjectCleaner in the background.
+func (c *TemporaryObjectCleaner) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-time.After(TempObjectCleanupInterval.Get(&c.settings.SV)):
+				if err := c.doTemporaryObjectCleanup(ctx); err != nil {
+					log.Warningf(ctx, "failed to clean temporary objects: %v", err)
+				}
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,542 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Triggers are SQL statements that are executed when the rows of a table are
+// modified. They are stored on the TableDescriptor and fired by the table
+// writers (see tableWriterBase.initTriggers) through the internal executor, in
+// the transaction of the triggering statement:
+//
+//  - BEFORE STATEMENT triggers fire when the table writer is initialized.
+//  - BEFORE ROW triggers fire before each row is written.
+//  - AFTER ROW triggers are buffered, and fire once all the rows have been
+//    written, followed by the AFTER STATEMENT triggers.
+//
+// The statement of a row-level trigger can reference the values of the
+// modified row as NEW.<column> and OLD.<column>. Before the statement is
+// executed, these references are substituted with the values of the row.
+// Triggers cannot modify the row that is written: since the values of the
+// computed columns and the CHECK constraints are evaluated before the table
+// writer, a BEFORE ROW trigger that changed NEW could bypass them. An AFTER
+// ROW trigger can UPDATE the row instead.
+//
+// Triggers don't fire for the rows modified by the cascading actions of
+// foreign key constraints, which don't go through the table writers. A
+// trigger can't be created on a table with such constraints, and such a
+// constraint can't be added to a table with triggers (see
+// checkNoCascadingFKs).
+
+// maxTriggerDepth is the maximum nesting depth of triggers. It bounds the
+// recursion of triggers that (directly or indirectly) modify their own table.
+const maxTriggerDepth = 16
+
+// triggerDepthKey is the context key under which the nesting depth of the
+// currently executing trigger is stored.
+type triggerDepthKey struct{}
+
+// triggerRowRef is a reference to a column of the NEW or OLD row in the
+// statement of a row-level trigger.
+type triggerRowRef struct {
+	// start and end are the byte offsets of the reference in the statement.
+	start, end int
+	old        bool
+	col        tree.Name
+}
+
+// findTriggerRowRefs returns the references to the columns of the NEW and OLD
+// rows in the given trigger statement.
+func findTriggerRowRefs(stmt string) ([]triggerRowRef, error) {
+	tokens, ok := parser.Tokens(stmt)
+	if !ok {
+		return nil, errors.AssertionFailedf("failed to scan trigger statement")
+	}
+	var refs []triggerRowRef
+	for i, tok := range tokens {
+		if tok.TokenID == parser.PLACEHOLDER {
+			return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+				"trigger statements cannot contain placeholders")
+		}
+		if tok.TokenID != parser.IDENT || (tok.Str != "new" && tok.Str != "old") {
+			continue
+		}
+		if i > 0 && tokens[i-1].TokenID == '.' {
+			// A column of a table named "new" or "old".
+			continue
+		}
+		if i+2 >= len(tokens) || tokens[i+1].TokenID != '.' {
+			continue
+		}
+		col := tokens[i+2]
+		if col.TokenID != parser.IDENT && col.TokenID != lex.GetKeywordID(col.Str) {
+			continue
+		}
+		refs = append(refs, triggerRowRef{
+			start: tok.Start,
+			end:   col.End,
+			old:   tok.Str == "old",
+			col:   tree.Name(col.Str),
+		})
+	}
+	return refs, nil
+}
+
+// analyzeTriggerStatement validates the statement of the given trigger on the
+// given table, and returns it in canonical form.
+func analyzeTriggerStatement(
+	desc *sqlbase.MutableTableDescriptor, trigger *sqlbase.TableDescriptor_Trigger, sql string,
+) (string, error) {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return "", pgerror.Wrap(err, pgcode.InvalidObjectDefinition, "invalid trigger statement")
+	}
+	switch stmt.AST.(type) {
+	case *tree.Insert, *tree.Update, *tree.Delete, *tree.Select:
+	default:
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"the statement of a trigger must be an INSERT, UPSERT, UPDATE, DELETE or SELECT statement, found %s",
+			stmt.AST.StatementTag())
+	}
+	canonical := tree.AsStringWithFlags(stmt.AST, tree.FmtParsable)
+
+	refs, err := findTriggerRowRefs(canonical)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		switch {
+		case !trigger.ForEachRow:
+			return "", pgerror.New(pgcode.InvalidObjectDefinition,
+				"statement-level triggers cannot reference NEW or OLD")
+		case ref.old && !trigger.OnUpdate && !trigger.OnDelete:
+			return "", pgerror.New(pgcode.InvalidObjectDefinition,
+				"INSERT triggers cannot reference OLD")
+		case !ref.old && !trigger.OnInsert && !trigger.OnUpdate:
+			return "", pgerror.New(pgcode.InvalidObjectDefinition,
+				"DELETE triggers cannot reference NEW")
+		}
+		if _, err := desc.FindActiveColumnByName(string(ref.col)); err != nil {
+			return "", err
+		}
+	}
+	return canonical, nil
+}
+
+// renameTriggerRowRefs returns the given trigger statement, with the
+// references to the given column of the NEW and OLD rows renamed.
+func renameTriggerRowRefs(stmt string, oldName, newName tree.Name) (string, error) {
+	refs, err := findTriggerRowRefs(stmt)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	last := 0
+	for _, ref := range refs {
+		if ref.col != oldName {
+			continue
+		}
+		buf.WriteString(stmt[last:ref.start])
+		if ref.old {
+			buf.WriteString("old.")
+		} else {
+			buf.WriteString("new.")
+		}
+		buf.WriteString(newName.String())
+		last = ref.end
+	}
+	buf.WriteString(stmt[last:])
+	return buf.String(), nil
+}
+
+// findTriggerReferencingColumn returns the name of a trigger of the given
+// table that references the given column, either in the column list of an
+// UPDATE OF event or in its statement.
+func findTriggerReferencingColumn(
+	desc *sqlbase.MutableTableDescriptor, col *sqlbase.ColumnDescriptor,
+) (string, bool, error) {
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		for _, id := range trigger.UpdateColumnIDs {
+			if id == col.ID {
+				return trigger.Name, true, nil
+			}
+		}
+		refs, err := findTriggerRowRefs(trigger.Statement)
+		if err != nil {
+			return "", false, err
+		}
+		for _, ref := range refs {
+			if string(ref.col) == col.Name {
+				return trigger.Name, true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
+// triggerFiresOn returns true if the given trigger fires for the given event,
+// when the triggering statement updates the given columns.
+func triggerFiresOn(
+	trigger *sqlbase.TableDescriptor_Trigger,
+	event tree.TriggerEventType,
+	updateCols []sqlbase.ColumnDescriptor,
+) bool {
+	switch event {
+	case tree.TriggerEventInsert:
+		return trigger.OnInsert
+	case tree.TriggerEventDelete:
+		return trigger.OnDelete
+	}
+	if !trigger.OnUpdate {
+		return false
+	}
+	if len(trigger.UpdateColumnIDs) == 0 {
+		return true
+	}
+	for _, id := range trigger.UpdateColumnIDs {
+		for i := range updateCols {
+			if updateCols[i].ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCascadingFKAction returns whether the given action of a foreign key
+// constraint modifies the referencing rows.
+func isCascadingFKAction(action sqlbase.ForeignKeyReference_Action) bool {
+	switch action {
+	case sqlbase.ForeignKeyReference_CASCADE,
+		sqlbase.ForeignKeyReference_SET_NULL,
+		sqlbase.ForeignKeyReference_SET_DEFAULT:
+		return true
+	}
+	return false
+}
+
+// checkNoCascadingFKs returns an error if the rows of the given table, which
+// has or is getting triggers, can be modified by the cascading actions of its
+// foreign key constraints, since triggers don't fire for these rows.
+func checkNoCascadingFKs(desc *sqlbase.MutableTableDescriptor) error {
+	for _, idx := range desc.AllNonDropIndexes() {
+		fk := &idx.ForeignKey
+		if fk.IsSet() && (isCascadingFKAction(fk.OnDelete) || isCascadingFKAction(fk.OnUpdate)) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"triggers are not supported on table %q, whose rows are modified by the cascading "+
+					"action of foreign key constraint %q", desc.Name, fk.Name)
+		}
+	}
+	return nil
+}
+
+// boundTrigger is a trigger that can be fired by the current statement.
+type boundTrigger struct {
+	*sqlbase.TableDescriptor_Trigger
+
+	// events is the set of events for which the trigger fires, indexed by
+	// tree.TriggerEventType.
+	events [3]bool
+
+	refs []triggerRowRef
+	// refIdxs and refTypes are the ordinals in the public columns of the table
+	// and the types of the columns referenced by refs.
+	refIdxs  []int
+	refTypes []*types.T
+}
+
+// statementForRow returns the statement of the trigger, with the references
+// to the NEW and OLD rows substituted with the values of the given rows. The
+// rows contain the values of the public columns of the table; either of them
+// can be nil, in which case NULL values are used.
+func (bt *boundTrigger) statementForRow(oldRow, newRow tree.Datums) string {
+	if len(bt.refs) == 0 {
+		return bt.Statement
+	}
+	var buf strings.Builder
+	last := 0
+	for i, ref := range bt.refs {
+		buf.WriteString(bt.Statement[last:ref.start])
+		row := newRow
+		if ref.old {
+			row = oldRow
+		}
+		var d tree.Datum = tree.DNull
+		if row != nil {
+			d = row[bt.refIdxs[i]]
+		}
+		buf.WriteString("CAST(")
+		buf.WriteString(tree.AsStringWithFlags(d, tree.FmtParsable))
+		buf.WriteString(" AS ")
+		buf.WriteString(bt.refTypes[i].SQLString())
+		buf.WriteString(")")
+		last = ref.end
+	}
+	buf.WriteString(bt.Statement[last:])
+	return buf.String()
+}
+
+// tableTriggers fires the triggers of a table for the rows modified by a
+// table writer.
+type tableTriggers struct {
+	ie      *SessionBoundInternalExecutor
+	txn     *client.Txn
+	evalCtx *tree.EvalContext
+
+	triggers []boundTrigger
+
+	// colIdx maps the IDs of the public columns of the table to their ordinal.
+	colIdx map[sqlbase.ColumnID]int
+
+	// afterRows buffers the rows for the AFTER ROW triggers. Each row consists
+	// of the event, followed by the old and the new values of the public
+	// columns of the table.
+	afterRows *rowcontainer.RowContainer
+	// rowBuf is used to prepare the rows passed to the triggers.
+	rowBuf tree.Datums
+}
+
+// newTableTriggers returns the triggers of the given table that can be fired
+// by a statement performing the given kinds of mutations, or nil if there are
+// none. updateCols are the columns updated by the statement.
+func newTableTriggers(
+	evalCtx *tree.EvalContext,
+	txn *client.Txn,
+	desc *sqlbase.ImmutableTableDescriptor,
+	events []tree.TriggerEventType,
+	updateCols []sqlbase.ColumnDescriptor,
+) (*tableTriggers, error) {
+	ie, ok := evalCtx.InternalExecutor.(*SessionBoundInternalExecutor)
+	if !ok {
+		return nil, errors.AssertionFailedf(
+			"cannot fire the triggers of table %q without a session-bound internal executor", desc.Name)
+	}
+	t := &tableTriggers{
+		ie:      ie,
+		txn:     txn,
+		evalCtx: evalCtx,
+		colIdx:  make(map[sqlbase.ColumnID]int, len(desc.Columns)),
+	}
+	for i := range desc.Columns {
+		t.colIdx[desc.Columns[i].ID] = i
+	}
+
+	needAfterRows := false
+	for i := range desc.Triggers {
+		bt := boundTrigger{TableDescriptor_Trigger: &desc.Triggers[i]}
+		fires := false
+		for _, event := range events {
+			if triggerFiresOn(bt.TableDescriptor_Trigger, event, updateCols) {
+				bt.events[event] = true
+				fires = true
+			}
+		}
+		if !fires {
+			continue
+		}
+		if bt.ForEachRow {
+			refs, err := findTriggerRowRefs(bt.Statement)
+			if err != nil {
+				return nil, err
+			}
+			bt.refs = refs
+			bt.refIdxs = make([]int, len(refs))
+			bt.refTypes = make([]*types.T, len(refs))
+			for j, ref := range refs {
+				col, err := desc.FindActiveColumnByName(string(ref.col))
+				if err != nil {
+					return nil, pgerror.Wrapf(err, pgcode.TriggeredActionException, "trigger %q", bt.Name)
+				}
+				bt.refIdxs[j] = t.colIdx[col.ID]
+				bt.refTypes[j] = &col.Type
+			}
+			needAfterRows = needAfterRows || !bt.Before
+		}
+		t.triggers = append(t.triggers, bt)
+	}
+	if len(t.triggers) == 0 {
+		return nil, nil
+	}
+
+	t.rowBuf = make(tree.Datums, 1+2*len(desc.Columns))
+	if needAfterRows {
+		colTypes := make([]types.T, len(t.rowBuf))
+		colTypes[0] = *types.Int
+		for i := range desc.Columns {
+			colTypes[1+i] = desc.Columns[i].Type
+			colTypes[1+len(desc.Columns)+i] = desc.Columns[i].Type
+		}
+		t.afterRows = rowcontainer.NewRowContainer(
+			evalCtx.Mon.MakeBoundAccount(), sqlbase.ColTypeInfoFromColTypes(colTypes), 0,
+		)
+	}
+	return t, nil
+}
+
+// beforeStatement fires the BEFORE STATEMENT triggers.
+func (t *tableTriggers) beforeStatement(ctx context.Context) error {
+	for i := range t.triggers {
+		if bt := &t.triggers[i]; bt.Before && !bt.ForEachRow {
+			if err := t.fire(ctx, bt, nil /* oldRow */, nil /* newRow */); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// afterStatement fires the AFTER ROW triggers for all the buffered rows,
+// followed by the AFTER STATEMENT triggers. It must be called once all the
+// rows have been written.
+func (t *tableTriggers) afterStatement(ctx context.Context) error {
+	if t.afterRows != nil {
+		numCols := len(t.colIdx)
+		for r := 0; r < t.afterRows.Len(); r++ {
+			row := t.afterRows.At(r)
+			event := tree.TriggerEventType(tree.MustBeDInt(row[0]))
+			oldRow, newRow := row[1:1+numCols], row[1+numCols:]
+			for i := range t.triggers {
+				if bt := &t.triggers[i]; !bt.Before && bt.ForEachRow && bt.events[event] {
+					if err := t.fire(ctx, bt, oldRow, newRow); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	for i := range t.triggers {
+		if bt := &t.triggers[i]; !bt.Before && !bt.ForEachRow {
+			if err := t.fire(ctx, bt, nil /* oldRow */, nil /* newRow */); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// onInsert fires the row-level triggers for a row that is about to be
+// inserted. The values of the row are ordered by cols.
+func (t *tableTriggers) onInsert(
+	ctx context.Context, cols []sqlbase.ColumnDescriptor, values tree.Datums,
+) error {
+	oldRow, newRow := t.resetRowBuf()
+	t.setRowValues(newRow, cols, values)
+	return t.onRow(ctx, tree.TriggerEventInsert, nil /* oldRow */, newRow)
+}
+
+// onUpdate fires the row-level triggers for a row that is about to be
+// updated. The existing values of the row are ordered by fetchCols, and the
+// updated values by updateCols.
+func (t *tableTriggers) onUpdate(
+	ctx context.Context,
+	fetchCols []sqlbase.ColumnDescriptor,
+	oldValues tree.Datums,
+	updateCols []sqlbase.ColumnDescriptor,
+	updateValues tree.Datums,
+) error {
+	oldRow, newRow := t.resetRowBuf()
+	t.setRowValues(oldRow, fetchCols, oldValues)
+	copy(newRow, oldRow)
+	t.setRowValues(newRow, updateCols, updateValues)
+	return t.onRow(ctx, tree.TriggerEventUpdate, oldRow, newRow)
+}
+
+// onDelete fires the row-level triggers for a row that is about to be
+// deleted. The values of the row are ordered by cols.
+func (t *tableTriggers) onDelete(
+	ctx context.Context, cols []sqlbase.ColumnDescriptor, values tree.Datums,
+) error {
+	oldRow, _ := t.resetRowBuf()
+	t.setRowValues(oldRow, cols, values)
+	return t.onRow(ctx, tree.TriggerEventDelete, oldRow, nil /* newRow */)
+}
+
+// resetRowBuf sets all the values in rowBuf to NULL, and returns the slices of
+// it that hold the old and new values of the public columns.
+func (t *tableTriggers) resetRowBuf() (oldRow, newRow tree.Datums) {
+	for i := range t.rowBuf {
+		t.rowBuf[i] = tree.DNull
+	}
+	numCols := len(t.colIdx)
+	return t.rowBuf[1 : 1+numCols], t.rowBuf[1+numCols:]
+}
+
+// setRowValues sets the values of the public columns in row from the given
+// values, which are ordered by cols. Values of non-public columns are ignored.
+func (t *tableTriggers) setRowValues(
+	row tree.Datums, cols []sqlbase.ColumnDescriptor, values tree.Datums,
+) {
+	for i := range cols {
+		if idx, ok := t.colIdx[cols[i].ID]; ok {
+			row[idx] = values[i]
+		}
+	}
+}
+
+// onRow fires the BEFORE ROW triggers for the given event, and buffers the
+// row for the AFTER ROW triggers.
+func (t *tableTriggers) onRow(
+	ctx context.Context, event tree.TriggerEventType, oldRow, newRow tree.Datums,
+) error {
+	for i := range t.triggers {
+		if bt := &t.triggers[i]; bt.Before && bt.ForEachRow && bt.events[event] {
+			if err := t.fire(ctx, bt, oldRow, newRow); err != nil {
+				return err
+			}
+		}
+	}
+	if t.afterRows != nil {
+		t.rowBuf[0] = tree.NewDInt(tree.DInt(event))
+		if _, err := t.afterRows.AddRow(ctx, t.rowBuf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fire executes the statement of the given trigger for the given rows.
+func (t *tableTriggers) fire(
+	ctx context.Context, bt *boundTrigger, oldRow, newRow tree.Datums,
+) error {
+	depth, _ := ctx.Value(triggerDepthKey{}).(int)
+	if depth >= maxTriggerDepth {
+		return pgerror.Newf(pgcode.StatementTooComplex,
+			"trigger %q exceeded the maximum trigger nesting depth of %d", bt.Name, maxTriggerDepth)
+	}
+	ctx = context.WithValue(ctx, triggerDepthKey{}, depth+1)
+
+	if _, err := t.ie.Exec(ctx, "trigger", t.txn, bt.statementForRow(oldRow, newRow)); err != nil {
+		return pgerror.Wrapf(err, pgcode.TriggeredActionException, "trigger %q", bt.Name)
+	}
+	return nil
+}
+
+// close frees the resources held by the triggers.
+func (t *tableTriggers) close(ctx context.Context) {
+	if t.afterRows != nil {
+		t.afterRows.Close(ctx)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -94,6 +95,11 @@ type txnState struct {
 	// The schema change closures to run when this txn is done.
 	schemaChangers schemaChangerCollection
 
+	// deferredChecks accumulates the checks of the constraints that are
+	// deferred to the commit of the transaction. Their memory is accounted
+	// against mon.
+	deferredChecks row.DeferredChecks
+
 	// adv is overwritten after every transition. It represents instructions for
 	// for moving the cursor over the stream of input statements to the next
 	// statement to be executed.
@@ -104,10 +110,6 @@ type txnState struct {
 	// txnAbortCount is incremented whenever the state transitions to
 	// stateAborted.
 	txnAbortCount *metric.Counter
-
-	// activeSavepointName stores the name of the active savepoint,
-	// or is empty if no savepoint is active.
-	activeSavepointName tree.Name
 }
 
 // txnType represents the type of a SQL transaction.
@@ -201,6 +203,7 @@ func (ts *txnState) resetForNewSQLTxn(
 	ts.Ctx, ts.cancel = contextutil.WithCancel(txnCtx)
 
 	ts.mon.Start(ts.Ctx, tranCtx.connMon, mon.BoundAccount{} /* reserved */)
+	ts.deferredChecks.Init(ts.mon.MakeBoundAccount())
 	ts.mu.Lock()
 	if txn == nil {
 		ts.mu.txn = client.NewTxn(ts.Ctx, tranCtx.db, tranCtx.nodeID, client.RootTxn)
@@ -227,6 +230,7 @@ func (ts *txnState) resetForNewSQLTxn(
 // the current SQL txn. This needs to be called before resetForNewSQLTxn() is
 // called for starting another SQL txn.
 func (ts *txnState) finishSQLTxn() {
+	ts.deferredChecks.Reset(ts.Ctx)
 	ts.mon.Stop(ts.Ctx)
 	if ts.cancel != nil {
 		ts.cancel()
@@ -265,8 +269,10 @@ func (ts *txnState) finishSQLTxn() {
 // but still want to clean up other stuff.
 func (ts *txnState) finishExternalTxn() {
 	if ts.Ctx == nil {
+		ts.deferredChecks.Reset(ts.connCtx)
 		ts.mon.Stop(ts.connCtx)
 	} else {
+		ts.deferredChecks.Reset(ts.Ctx)
 		ts.mon.Stop(ts.Ctx)
 	}
 	if ts.cancel != nil {
@@ -378,6 +384,11 @@ const (
 	// This event is produced both when entering the RetryWait state and sometimes
 	// when exiting it.
 	txnRestart
+	// txnErrored means that the transaction encountered a non-retriable error
+	// and the connection entered the Aborted state. Unlike with txnAborted, the
+	// KV txn is kept: the client might still recover from the error through
+	// ROLLBACK TO SAVEPOINT and go on to commit the transaction.
+	txnErrored
 )
 
 // advanceInfo represents instructions for the connExecutor about what statement
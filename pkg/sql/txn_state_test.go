@@ -581,7 +581,7 @@ func TestTransitions(t *testing.T) {
 			expState:  stateAborted{RetryIntent: fsm.False},
 			expAdv: expAdvance{
 				expCode: skipBatch,
-				expEv:   txnAborted,
+				expEv:   txnErrored,
 			},
 			expTxn: &expKVTxn{},
 		},
@@ -651,7 +651,7 @@ func TestTransitions(t *testing.T) {
 			expState: stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.True},
 			expAdv: expAdvance{
 				expCode: advanceOne,
-				expEv:   noEvent,
+				expEv:   txnRestart,
 			},
 			expTxn: &expKVTxn{},
 		},
@@ -670,12 +670,28 @@ func TestTransitions(t *testing.T) {
 			expState: stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.True},
 			expAdv: expAdvance{
 				expCode: advanceOne,
-				expEv:   noEvent,
+				expEv:   txnRestart,
 			},
 			expTxn: &expKVTxn{
 				tsNanos: proto.Int64(now.WallTime),
 			},
 		},
+		{
+			// The txn is recovered through a ROLLBACK TO SAVEPOINT targeting a
+			// savepoint other than cockroach_restart while in Aborted.
+			name: "Aborted->Open (savepoint rollback)",
+			init: func() (fsm.State, *txnState, error) {
+				s, ts := testCon.createAbortedState(retryIntentNotSet)
+				return s, ts, nil
+			},
+			ev:       eventSavepointRollback{},
+			expState: stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.False},
+			expAdv: expAdvance{
+				expCode: advanceOne,
+				expEv:   noEvent,
+			},
+			expTxn: &expKVTxn{},
+		},
 		//
 		// Tests starting from the RestartWait state.
 		//
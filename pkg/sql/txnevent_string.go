@@ -13,11 +13,12 @@ func _() {
 	_ = x[txnCommit-2]
 	_ = x[txnAborted-3]
 	_ = x[txnRestart-4]
+	_ = x[txnErrored-5]
 }
 
-const _txnEvent_name = "noEventtxnStarttxnCommittxnAbortedtxnRestart"
+const _txnEvent_name = "noEventtxnStarttxnCommittxnAbortedtxnRestarttxnErrored"
 
-var _txnEvent_index = [...]uint8{0, 7, 15, 24, 34, 44}
+var _txnEvent_index = [...]uint8{0, 7, 15, 24, 34, 44, 54}
 
 func (i txnEvent) String() string {
 	if i < 0 || i >= txnEvent(len(_txnEvent_index)-1) {
@@ -151,9 +151,32 @@ var familyToOid = map[Family]oid.Oid{
 	JsonFamily:           oid.T_jsonb,
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
+	EnumFamily:           oid.T_anyenum,
 	AnyFamily:            oid.T_anyelement,
 }
 
+// userDefinedTypeOIDOffset is the offset that is added to the descriptor ID
+// of a user-defined type to compute its OID. OIDs below the offset are
+// reserved for builtin types, so that user-defined types never collide with
+// them.
+const userDefinedTypeOIDOffset = 100000
+
+// StableTypeIDToOID returns the OID of the user-defined type with the given
+// descriptor ID.
+func StableTypeIDToOID(id uint32) oid.Oid {
+	return oid.Oid(id + userDefinedTypeOIDOffset)
+}
+
+// UserDefinedTypeOIDToID returns the descriptor ID of the user-defined type
+// with the given OID. It returns false if the OID does not belong to a
+// user-defined type.
+func UserDefinedTypeOIDToID(o oid.Oid) (uint32, bool) {
+	if o < userDefinedTypeOIDOffset {
+		return 0, false
+	}
+	return uint32(o) - userDefinedTypeOIDOffset, true
+}
+
 // ArrayOids is a set of all oids which correspond to an array type.
 var ArrayOids = map[oid.Oid]struct{}{}
 
@@ -339,6 +339,12 @@ var (
 	AnyCollatedString = &T{InternalType: InternalType{
 		Family: CollatedStringFamily, Oid: oid.T_text, Locale: &emptyLocale}}
 
+	// AnyEnum is a special type used only during static analysis as a wildcard
+	// type that matches any user-defined enum type. Execution-time values
+	// should never have this type.
+	AnyEnum = &T{InternalType: InternalType{
+		Family: EnumFamily, Oid: oid.T_anyenum, Locale: &emptyLocale}}
+
 	// EmptyTuple is the tuple type with no fields. Note that this is different
 	// than AnyTuple, which is a wildcard type.
 	EmptyTuple = &T{InternalType: InternalType{
@@ -698,6 +704,51 @@ func MakeLabeledTuple(contents []T, labels []string) *T {
 	}}
 }
 
+// MakeEnum constructs a new instance of an EnumFamily type for the
+// user-defined type with the given descriptor ID and name. The physical and
+// logical representations of the members of the enum must be given in
+// declaration order. isReadOnly, if not nil, marks the members that are
+// read-only; see EnumIsMemberReadOnly.
+func MakeEnum(
+	typeID uint32, name string, physicalReps [][]byte, logicalReps []string, isReadOnly []bool,
+) *T {
+	if len(physicalReps) != len(logicalReps) {
+		panic(errors.AssertionFailedf(
+			"enum physical and logical representations must be of same length: %v, %v",
+			physicalReps, logicalReps))
+	}
+	if isReadOnly != nil && len(isReadOnly) != len(physicalReps) {
+		panic(errors.AssertionFailedf(
+			"enum read-only flags must be of same length as its members: %v, %v",
+			isReadOnly, logicalReps))
+	}
+	return &T{InternalType: InternalType{
+		Family: EnumFamily,
+		Oid:    StableTypeIDToOID(typeID),
+		Locale: &emptyLocale,
+		UDTMetadata: &UserDefinedTypeMetadata{
+			Name:                        name,
+			StableTypeID:                typeID,
+			EnumPhysicalRepresentations: physicalReps,
+			EnumLogicalRepresentations:  logicalReps,
+			EnumIsMemberReadOnly:        isReadOnly,
+		},
+	}}
+}
+
+// MakeUnresolvedUserDefinedType constructs a placeholder for a reference, by
+// name, to a user-defined type that has not been resolved yet. The parser
+// produces such placeholders for type names it does not know about; they must
+// be resolved into the actual type (see tree.ResolveType) before use.
+func MakeUnresolvedUserDefinedType(name string) *T {
+	return &T{InternalType: InternalType{
+		Family:      EnumFamily,
+		Oid:         oid.T_anyenum,
+		Locale:      &emptyLocale,
+		UDTMetadata: &UserDefinedTypeMetadata{Name: name},
+	}}
+}
+
 // Family specifies a group of types that are compatible with one another. Types
 // in the same family can be compared, assigned, etc., but may differ from one
 // another in width, precision, locale, and other attributes. For example, it is
@@ -799,6 +850,57 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// UserDefined returns whether or not this type is a user-defined type.
+func (t *T) UserDefined() bool {
+	return t.InternalType.UDTMetadata != nil
+}
+
+// IsUnresolvedUserDefinedType returns whether this type is a placeholder
+// for a user-defined type that has not been resolved yet. See
+// MakeUnresolvedUserDefinedType.
+func (t *T) IsUnresolvedUserDefinedType() bool {
+	return t.UserDefined() && t.InternalType.UDTMetadata.StableTypeID == 0
+}
+
+// StableTypeID returns the ID of the descriptor of a user-defined type. This is
+// 0 for builtin types.
+func (t *T) StableTypeID() uint32 {
+	if t.InternalType.UDTMetadata == nil {
+		return 0
+	}
+	return t.InternalType.UDTMetadata.StableTypeID
+}
+
+// EnumPhysicalRepresentations returns the physical representations of the
+// members of an enum type, in declaration order. This is nil for non-ENUM
+// types.
+func (t *T) EnumPhysicalRepresentations() [][]byte {
+	if t.InternalType.UDTMetadata == nil {
+		return nil
+	}
+	return t.InternalType.UDTMetadata.EnumPhysicalRepresentations
+}
+
+// EnumLogicalRepresentations returns the labels of the members of an enum
+// type, in declaration order. This is nil for non-ENUM types.
+func (t *T) EnumLogicalRepresentations() []string {
+	if t.InternalType.UDTMetadata == nil {
+		return nil
+	}
+	return t.InternalType.UDTMetadata.EnumLogicalRepresentations
+}
+
+// EnumIsMemberReadOnly returns whether the member of an enum type at the given
+// position is read-only. The labels of read-only members cannot be used as
+// input values yet, since some nodes may not know about the members; values of
+// them can still be decoded.
+func (t *T) EnumIsMemberReadOnly(idx int) bool {
+	if t.InternalType.UDTMetadata == nil {
+		return false
+	}
+	return t.InternalType.UDTMetadata.isMemberReadOnly(idx)
+}
+
 // Name returns a single word description of the type that describes it
 // succinctly, but without all the details, such as width, locale, etc. The name
 // is sometimes the same as the name returned by SQLStandardName, but is more
@@ -830,6 +932,11 @@ func (t *T) Name() string {
 		return "date"
 	case DecimalFamily:
 		return "decimal"
+	case EnumFamily:
+		if !t.UserDefined() {
+			return "anyenum"
+		}
+		return t.InternalType.UDTMetadata.Name
 	case FloatFamily:
 		switch t.Width() {
 		case 64:
@@ -903,6 +1010,9 @@ func (t *T) Name() string {
 //   int4[]       _int4
 //
 func (t *T) PGName() string {
+	if t.UserDefined() {
+		return t.Name()
+	}
 	name, ok := oid.TypeName[t.Oid()]
 	if ok {
 		return strings.ToLower(name)
@@ -982,6 +1092,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			(typmod>>16)&0xffff,
 			typmod&0xffff,
 		)
+	case EnumFamily:
+		return t.Name()
 
 	case FloatFamily:
 		switch t.Width() {
@@ -1154,6 +1266,10 @@ func (t *T) SQLString() string {
 	case JsonFamily:
 		// Only binary JSON is currently supported.
 		return "JSONB"
+	case EnumFamily:
+		var buf bytes.Buffer
+		lex.EncodeRestrictedSQLIdent(&buf, t.Name(), lex.EncNoFlags)
+		return buf.String()
 	case TimestampFamily, TimestampTZFamily:
 		if t.Precision() != -1 {
 			return fmt.Sprintf("%s(%d)", strings.ToUpper(t.Name()), t.Precision())
@@ -1228,6 +1344,16 @@ func (t *T) Equivalent(other *T) bool {
 		if !t.ArrayContents().Equivalent(other.ArrayContents()) {
 			return false
 		}
+
+	case EnumFamily:
+		// If either enum is the wildcard enum, it's equivalent to any other enum
+		// type. Otherwise, the enums must be the same user-defined type.
+		if !t.UserDefined() || !other.UserDefined() {
+			return true
+		}
+		if t.StableTypeID() != other.StableTypeID() {
+			return false
+		}
 	}
 
 	return true
@@ -1315,9 +1441,52 @@ func (t *InternalType) Identical(other *InternalType) bool {
 			return false
 		}
 	}
+	if t.UDTMetadata != nil && other.UDTMetadata != nil {
+		if !t.UDTMetadata.identical(other.UDTMetadata) {
+			return false
+		}
+	} else if t.UDTMetadata != nil {
+		return false
+	} else if other.UDTMetadata != nil {
+		return false
+	}
 	return t.Oid == other.Oid
 }
 
+// identical returns true if the two user-defined type metadata describe the
+// same type with exactly the same members.
+func (m *UserDefinedTypeMetadata) identical(other *UserDefinedTypeMetadata) bool {
+	if m.Name != other.Name || m.StableTypeID != other.StableTypeID {
+		return false
+	}
+	if len(m.EnumPhysicalRepresentations) != len(other.EnumPhysicalRepresentations) ||
+		len(m.EnumLogicalRepresentations) != len(other.EnumLogicalRepresentations) {
+		return false
+	}
+	for i := range m.EnumPhysicalRepresentations {
+		if !bytes.Equal(m.EnumPhysicalRepresentations[i], other.EnumPhysicalRepresentations[i]) {
+			return false
+		}
+	}
+	for i := range m.EnumLogicalRepresentations {
+		if m.EnumLogicalRepresentations[i] != other.EnumLogicalRepresentations[i] {
+			return false
+		}
+	}
+	for i := range m.EnumPhysicalRepresentations {
+		if m.isMemberReadOnly(i) != other.isMemberReadOnly(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// isMemberReadOnly returns whether the enum member at the given position is
+// read-only.
+func (m *UserDefinedTypeMetadata) isMemberReadOnly(idx int) bool {
+	return idx < len(m.EnumIsMemberReadOnly) && m.EnumIsMemberReadOnly[idx]
+}
+
 // Unmarshal deserializes a type from the given byte representation using gogo
 // protobuf serialization rules. It is backwards-compatible with formats used
 // by older versions of CRDB.
@@ -1665,6 +1834,8 @@ func (t *T) IsAmbiguous() bool {
 		return true
 	case CollatedStringFamily:
 		return t.Locale() == ""
+	case EnumFamily:
+		return t.StableTypeID() == 0
 	case TupleFamily:
 		if len(t.TupleContents()) == 0 {
 			return true
@@ -1699,6 +1870,8 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 	switch t.Family() {
 	case JsonFamily:
 		return false, 23468
+	case EnumFamily:
+		return false, 0
 	default:
 		return true, 0
 	}
@@ -1708,6 +1881,9 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 // type of an ArrayFamily-typed column. If not, it returns an error.
 func CheckArrayElementType(t *T) error {
 	if ok, issueNum := IsValidArrayElementType(t); !ok {
+		if issueNum == 0 {
+			return unimplemented.Newf(t.String(), "arrays of %s not allowed", t)
+		}
 		return unimplemented.NewWithIssueDetailf(issueNum, t.String(),
 			"arrays of %s not allowed", t)
 	}
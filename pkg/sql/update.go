@@ -501,7 +501,13 @@ func (u *updateNode) startExec(params runParams) error {
 			params.EvalContext().Mon.MakeBoundAccount(),
 			sqlbase.ColTypeInfoFromResCols(u.columns), 0)
 	}
-	return u.run.tu.init(params.p.txn, params.EvalContext())
+	if err := u.run.tu.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	u.run.tu.ru.DeferChecks(
+		params.extendedEvalCtx.DeferredChecks, u.run.tu.initStatementChecks(params.EvalContext()),
+	)
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
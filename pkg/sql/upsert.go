@@ -197,7 +197,9 @@ func (p *planner) newUpsertNode(
 			len(ri.InsertCols) == len(desc.Columns) &&
 			// We cannot use the fast path if we also have a RETURNING clause, because
 			// RETURNING wants to see only the updated rows.
-			!needRows
+			!needRows &&
+			// Triggers must be fired with the previous values of updated rows.
+			len(desc.Triggers) == 0
 
 		if enableFastPath {
 			// We then use the super-simple, super-fast writer. There's not
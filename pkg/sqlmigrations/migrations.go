@@ -206,6 +206,13 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "propagate the ts purge interval to the new setting names",
 		workFn: retireOldTsPurgeIntervalSettings,
 	},
+	{
+		// Introduced in v19.2.
+		name:                "create system.protected_ts_records table",
+		workFn:              createProtectedTimestampsRecordsTable,
+		includedInBootstrap: true,
+		newDescriptorIDs:    staticIDs(keys.ProtectedTimestampsRecordsTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -550,6 +557,10 @@ func createCommentTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.CommentsTable)
 }
 
+func createProtectedTimestampsRecordsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.ProtectedTimestampsRecordsTable)
+}
+
 var reportingOptOut = envutil.EnvOrDefaultBool("COCKROACH_SKIP_ENABLING_DIAGNOSTIC_REPORTING", false)
 
 func runStmtAsRootWithRetry(
@@ -764,6 +775,8 @@ func upgradeDescsWithFn(
 							b.Put(kv.Key, sqlbase.WrapDescriptor(database))
 						}
 					}
+				case *sqlbase.Descriptor_Type, *sqlbase.Descriptor_Function:
+					// User-defined types and functions have no format to upgrade.
 
 				default:
 					return errors.Errorf("Descriptor.Union has unexpected type %T", t)
@@ -53,6 +53,14 @@ const (
 	removeDeadReplicaPriority             float64 = 1000
 	removeDecommissioningReplicaPriority  float64 = 200
 	removeExtraReplicaPriority            float64 = 100
+
+	// priorities for repair operations on non-voting replicas. These are only
+	// computed once the voting replicas need no repair, so they are lower than
+	// all of the above.
+	addMissingNonVoterPriority            float64 = 60
+	removeDeadNonVoterPriority            float64 = 50
+	removeDecommissioningNonVoterPriority float64 = 40
+	removeExtraNonVoterPriority           float64 = 30
 )
 
 // MinLeaseTransferStatsDuration configures the minimum amount of time a
@@ -99,15 +107,25 @@ const (
 	AllocatorRemoveDead
 	AllocatorRemoveDecommissioning
 	AllocatorConsiderRebalance
+	AllocatorAddNonVoter
+	AllocatorRemoveNonVoter
+	AllocatorRemoveDeadNonVoter
+	AllocatorRemoveDecommissioningNonVoter
+	AllocatorPromoteNonVoter
 )
 
 var allocatorActionNames = map[AllocatorAction]string{
-	AllocatorNoop:                  "noop",
-	AllocatorRemove:                "remove",
-	AllocatorAdd:                   "add",
-	AllocatorRemoveDead:            "remove dead",
-	AllocatorRemoveDecommissioning: "remove decommissioning",
-	AllocatorConsiderRebalance:     "consider rebalance",
+	AllocatorNoop:                          "noop",
+	AllocatorRemove:                        "remove",
+	AllocatorAdd:                           "add",
+	AllocatorRemoveDead:                    "remove dead",
+	AllocatorRemoveDecommissioning:         "remove decommissioning",
+	AllocatorConsiderRebalance:             "consider rebalance",
+	AllocatorAddNonVoter:                   "add non-voter",
+	AllocatorRemoveNonVoter:                "remove non-voter",
+	AllocatorRemoveDeadNonVoter:            "remove dead non-voter",
+	AllocatorRemoveDecommissioningNonVoter: "remove decommissioning non-voter",
+	AllocatorPromoteNonVoter:               "promote non-voter",
 }
 
 func (a AllocatorAction) String() string {
@@ -284,9 +302,31 @@ func GetNeededReplicas(zoneConfigReplicaCount int32, clusterNodes int) int {
 	return need
 }
 
+// GetNeededNonVoters calculates the number of non-voting replicas a range
+// should have given the number of non-voters in its zone config, the number of
+// voting replicas it needs and the number of nodes available for
+// up-replication. Unlike voters, non-voters don't affect the range's quorum,
+// so any number of them is acceptable.
+func GetNeededNonVoters(zoneConfigNonVoterCount int32, numVoters, clusterNodes int) int {
+	need := int(zoneConfigNonVoterCount)
+	if clusterNodes-numVoters < need {
+		need = clusterNodes - numVoters
+	}
+	if need < 0 {
+		need = 0
+	}
+	return need
+}
+
 // ComputeAction determines the exact operation needed to repair the
 // supplied range, as governed by the supplied zone configuration. It
 // returns the required action that should be taken and a priority.
+//
+// Repairs of the voting replicas take precedence: the non-voting replicas are
+// only considered once the voters are as configured. A missing voter is added
+// by promoting a live non-voter if there is one, which doesn't require a
+// snapshot. Raft can't demote a voter, so a voter in excess is removed, and a
+// non-voter is then added in its place if needed.
 func (a *Allocator) ComputeAction(
 	ctx context.Context, zone *config.ZoneConfig, rangeInfo RangeInfo,
 ) (AllocatorAction, float64) {
@@ -294,13 +334,26 @@ func (a *Allocator) ComputeAction(
 		// Do nothing if storePool is nil for some unittests.
 		return AllocatorNoop, 0
 	}
+	action, priority := a.computeVoterAction(ctx, zone, rangeInfo)
+	if action != AllocatorConsiderRebalance {
+		return action, priority
+	}
+	return a.computeNonVoterAction(ctx, zone, rangeInfo)
+}
+
+// computeVoterAction determines the operation needed to repair the voting
+// replicas of the supplied range.
+func (a *Allocator) computeVoterAction(
+	ctx context.Context, zone *config.ZoneConfig, rangeInfo RangeInfo,
+) (AllocatorAction, float64) {
 	// TODO(mrtracy): Handle non-homogeneous and mismatched attribute sets.
 
-	have := len(rangeInfo.Desc.Replicas().Unwrap())
+	voters := rangeInfo.Desc.Replicas().Voters()
+	have := len(voters)
 	decommissioningReplicas := a.storePool.decommissioningReplicas(
-		rangeInfo.Desc.RangeID, rangeInfo.Desc.Replicas().Unwrap())
+		rangeInfo.Desc.RangeID, voters)
 	clusterNodes := a.storePool.ClusterNodeCount()
-	need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+	need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 	desiredQuorum := computeQuorum(need)
 	quorum := computeQuorum(have)
 
@@ -309,6 +362,13 @@ func (a *Allocator) ComputeAction(
 		// Priority is adjusted by the difference between the current replica
 		// count and the quorum of the desired replica count.
 		priority := addMissingReplicaPriority + float64(desiredQuorum-have)
+		liveNonVoters, _ := a.storePool.liveAndDeadReplicas(
+			rangeInfo.Desc.RangeID, rangeInfo.Desc.Replicas().NonVoters())
+		if len(liveNonVoters) > 0 {
+			log.VEventf(ctx, 3, "AllocatorPromoteNonVoter - missing replica need=%d, have=%d, priority=%.2f",
+				need, have, priority)
+			return AllocatorPromoteNonVoter, priority
+		}
 		log.VEventf(ctx, 3, "AllocatorAdd - missing replica need=%d, have=%d, priority=%.2f", need, have, priority)
 		return AllocatorAdd, priority
 	}
@@ -324,7 +384,7 @@ func (a *Allocator) ComputeAction(
 	}
 
 	liveReplicas, deadReplicas := a.storePool.liveAndDeadReplicas(
-		rangeInfo.Desc.RangeID, rangeInfo.Desc.Replicas().Unwrap())
+		rangeInfo.Desc.RangeID, voters)
 	if len(liveReplicas) < quorum {
 		// Do not take any removal action if we do not have a quorum of live
 		// replicas.
@@ -382,6 +442,55 @@ func (a *Allocator) ComputeAction(
 	return AllocatorConsiderRebalance, 0
 }
 
+// computeNonVoterAction determines the operation needed to repair the
+// non-voting replicas of the supplied range. It must only be called once the
+// voting replicas need no repair.
+func (a *Allocator) computeNonVoterAction(
+	ctx context.Context, zone *config.ZoneConfig, rangeInfo RangeInfo,
+) (AllocatorAction, float64) {
+	nonVoters := rangeInfo.Desc.Replicas().NonVoters()
+	have := len(nonVoters)
+	clusterNodes := a.storePool.ClusterNodeCount()
+	need := GetNeededNonVoters(
+		zone.GetNumNonVoters(), len(rangeInfo.Desc.Replicas().Voters()), clusterNodes)
+
+	if have < need {
+		priority := addMissingNonVoterPriority
+		log.VEventf(ctx, 3, "AllocatorAddNonVoter - missing non-voter need=%d, have=%d, priority=%.2f",
+			need, have, priority)
+		return AllocatorAddNonVoter, priority
+	}
+
+	// Non-voters don't count towards quorum, so dead and decommissioning
+	// non-voters are removed before being replaced.
+	_, deadNonVoters := a.storePool.liveAndDeadReplicas(rangeInfo.Desc.RangeID, nonVoters)
+	if len(deadNonVoters) > 0 {
+		priority := removeDeadNonVoterPriority
+		log.VEventf(ctx, 3, "AllocatorRemoveDeadNonVoter - dead=%d, priority=%.2f",
+			len(deadNonVoters), priority)
+		return AllocatorRemoveDeadNonVoter, priority
+	}
+
+	decommissioningNonVoters := a.storePool.decommissioningReplicas(
+		rangeInfo.Desc.RangeID, nonVoters)
+	if len(decommissioningNonVoters) > 0 {
+		priority := removeDecommissioningNonVoterPriority
+		log.VEventf(ctx, 3, "AllocatorRemoveDecommissioningNonVoter - num_decommissioning=%d, priority=%.2f",
+			len(decommissioningNonVoters), priority)
+		return AllocatorRemoveDecommissioningNonVoter, priority
+	}
+
+	if have > need {
+		priority := removeExtraNonVoterPriority
+		log.VEventf(ctx, 3, "AllocatorRemoveNonVoter - need=%d, have=%d, priority=%.2f",
+			need, have, priority)
+		return AllocatorRemoveNonVoter, priority
+	}
+
+	// Nothing needs to be done, but we may want to rebalance the voters.
+	return AllocatorConsiderRebalance, 0
+}
+
 type decisionDetails struct {
 	Target   string
 	Existing string `json:",omitempty"`
@@ -422,6 +531,44 @@ func (a *Allocator) AllocateTarget(
 	}
 }
 
+// PromoteTarget returns the non-voting replica, among the supplied ones, that
+// is the best suited to be promoted to a voter of the range, given the
+// existing voters.
+func (a *Allocator) PromoteTarget(
+	ctx context.Context,
+	zone *config.ZoneConfig,
+	existingVoters []roachpb.ReplicaDescriptor,
+	nonVoters []roachpb.ReplicaDescriptor,
+	rangeInfo RangeInfo,
+) (roachpb.ReplicaDescriptor, string, error) {
+	if len(nonVoters) == 0 {
+		return roachpb.ReplicaDescriptor{}, "", errors.Errorf(
+			"must supply at least one non-voter to allocator.PromoteTarget()")
+	}
+	storeIDs := make(roachpb.StoreIDSlice, len(nonVoters))
+	for i, nonVoter := range nonVoters {
+		storeIDs[i] = nonVoter.StoreID
+	}
+	sl, aliveStoreCount, throttled := a.storePool.getStoreListFromIDs(
+		storeIDs, rangeInfo.Desc.RangeID, storeFilterNone)
+
+	target, details := a.allocateTargetFromList(
+		ctx, sl, zone, existingVoters, rangeInfo, a.scorerOptions())
+	if target != nil {
+		for _, nonVoter := range nonVoters {
+			if nonVoter.StoreID == target.StoreID {
+				return nonVoter, details, nil
+			}
+		}
+	}
+	return roachpb.ReplicaDescriptor{}, "", &allocatorError{
+		constraints:      zone.Constraints,
+		existingReplicas: len(existingVoters),
+		aliveStores:      aliveStoreCount,
+		throttledStores:  len(throttled),
+	}
+}
+
 func (a *Allocator) allocateTargetFromList(
 	ctx context.Context,
 	sl StoreList,
@@ -556,17 +703,19 @@ func (a Allocator) RebalanceTarget(
 	// NB: The len(replicas) > 1 check allows rebalancing of ranges with only a
 	// single replica. This is a corner case which could happen in practice and
 	// also affects tests.
-	if len(rangeInfo.Desc.Replicas().Unwrap()) > 1 {
+	//
+	// Only the voting replicas are rebalanced, and only they matter for quorum.
+	if voters := rangeInfo.Desc.Replicas().Voters(); len(voters) > 1 {
 		var numLiveReplicas int
 		for _, s := range sl.stores {
-			for _, repl := range rangeInfo.Desc.Replicas().Unwrap() {
+			for _, repl := range voters {
 				if s.StoreID == repl.StoreID {
 					numLiveReplicas++
 					break
 				}
 			}
 		}
-		newQuorum := computeQuorum(len(rangeInfo.Desc.Replicas().Unwrap()) + 1)
+		newQuorum := computeQuorum(len(voters) + 1)
 		if numLiveReplicas < newQuorum {
 			// Don't rebalance as we won't be able to make quorum after the rebalance
 			// until the new replica has been caught up.
@@ -624,7 +773,7 @@ func (a Allocator) RebalanceTarget(
 		// If we can't (e.g. because we're the leaseholder but not the raft leader),
 		// it's better to simulate the removal with the info that we do have than to
 		// assume that the rebalance is ok (#20241).
-		replicaCandidates := newReplicas.Unwrap()
+		replicaCandidates := newReplicas.Voters()
 		if raftStatus != nil && raftStatus.Progress != nil {
 			replicaCandidates = simulateFilterUnremovableReplicas(
 				raftStatus, replicaCandidates, newReplica.ReplicaID)
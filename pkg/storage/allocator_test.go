@@ -4547,6 +4547,190 @@ func TestAllocatorComputeActionRemoveDead(t *testing.T) {
 	}
 }
 
+func TestAllocatorComputeActionNonVoters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zone := config.ZoneConfig{
+		NumReplicas: proto.Int32(4),
+		NumVoters:   proto.Int32(3),
+	}
+	voters := []roachpb.ReplicaDescriptor{
+		{StoreID: 1, NodeID: 1, ReplicaID: 1},
+		{StoreID: 2, NodeID: 2, ReplicaID: 2},
+		{StoreID: 3, NodeID: 3, ReplicaID: 3},
+	}
+	withNonVoters := func(storeIDs ...roachpb.StoreID) roachpb.RangeDescriptor {
+		repls := append([]roachpb.ReplicaDescriptor(nil), voters...)
+		for _, storeID := range storeIDs {
+			repls = append(repls, roachpb.ReplicaDescriptor{
+				StoreID:   storeID,
+				NodeID:    roachpb.NodeID(storeID),
+				ReplicaID: roachpb.ReplicaID(storeID),
+				Type:      roachpb.ReplicaType_NON_VOTER.Enum(),
+			})
+		}
+		return roachpb.RangeDescriptor{InternalReplicas: repls}
+	}
+
+	testCases := []struct {
+		desc           roachpb.RangeDescriptor
+		live           []roachpb.StoreID
+		dead           []roachpb.StoreID
+		expectedAction AllocatorAction
+	}{
+		// Voters are as configured, but the non-voter is missing.
+		{
+			desc:           withNonVoters(),
+			live:           []roachpb.StoreID{1, 2, 3, 4},
+			expectedAction: AllocatorAddNonVoter,
+		},
+		// A voter is dead, which takes precedence over the missing non-voter.
+		{
+			desc:           withNonVoters(),
+			live:           []roachpb.StoreID{1, 2, 4, 5},
+			dead:           []roachpb.StoreID{3},
+			expectedAction: AllocatorAdd,
+		},
+		// The non-voter is dead.
+		{
+			desc:           withNonVoters(4),
+			live:           []roachpb.StoreID{1, 2, 3, 5},
+			dead:           []roachpb.StoreID{4},
+			expectedAction: AllocatorRemoveDeadNonVoter,
+		},
+		// There is one non-voter too many.
+		{
+			desc:           withNonVoters(4, 5),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 5},
+			expectedAction: AllocatorRemoveNonVoter,
+		},
+		// Everything is as configured.
+		{
+			desc:           withNonVoters(4),
+			live:           []roachpb.StoreID{1, 2, 3, 4},
+			expectedAction: AllocatorConsiderRebalance,
+		},
+	}
+
+	stopper, _, sp, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+
+	for i, tcase := range testCases {
+		mockStorePool(sp, tcase.live, nil, tcase.dead, nil, nil)
+
+		action, _ := a.ComputeAction(ctx, &zone, RangeInfo{Desc: &tcase.desc})
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %s, got action %s", i, tcase.expectedAction, action)
+		}
+	}
+}
+
+// TestAllocatorComputeActionChangeNumVoters verifies that the voters and
+// non-voters of a range converge when num_voters is raised or lowered.
+func TestAllocatorComputeActionChangeNumVoters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// replicas returns a range descriptor with voters on the first numVoters
+	// stores and non-voters on the following numNonVoters stores.
+	replicas := func(numVoters, numNonVoters int) roachpb.RangeDescriptor {
+		var repls []roachpb.ReplicaDescriptor
+		for i := 1; i <= numVoters+numNonVoters; i++ {
+			repl := roachpb.ReplicaDescriptor{
+				StoreID:   roachpb.StoreID(i),
+				NodeID:    roachpb.NodeID(i),
+				ReplicaID: roachpb.ReplicaID(i),
+			}
+			if i > numVoters {
+				repl.Type = roachpb.ReplicaType_NON_VOTER.Enum()
+			}
+			repls = append(repls, repl)
+		}
+		return roachpb.RangeDescriptor{InternalReplicas: repls}
+	}
+
+	testCases := []struct {
+		numVoters      int32
+		desc           roachpb.RangeDescriptor
+		dead           []roachpb.StoreID
+		expectedAction AllocatorAction
+	}{
+		// num_voters is raised from 3 to 5: the non-voters are promoted.
+		{5, replicas(3, 2), nil, AllocatorPromoteNonVoter},
+		{5, replicas(4, 1), nil, AllocatorPromoteNonVoter},
+		{5, replicas(5, 0), nil, AllocatorConsiderRebalance},
+		// Dead non-voters aren't promoted.
+		{5, replicas(4, 1), []roachpb.StoreID{5}, AllocatorAdd},
+		// num_voters is lowered from 5 to 3: the extra voters are removed, and
+		// non-voters are added in their place.
+		{3, replicas(5, 0), nil, AllocatorRemove},
+		{3, replicas(4, 0), nil, AllocatorRemove},
+		{3, replicas(3, 0), nil, AllocatorAddNonVoter},
+		{3, replicas(3, 1), nil, AllocatorAddNonVoter},
+		{3, replicas(3, 2), nil, AllocatorConsiderRebalance},
+	}
+
+	stopper, _, sp, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+
+	for i, tcase := range testCases {
+		var live []roachpb.StoreID
+		for storeID := roachpb.StoreID(1); storeID <= 5; storeID++ {
+			isDead := false
+			for _, dead := range tcase.dead {
+				isDead = isDead || dead == storeID
+			}
+			if !isDead {
+				live = append(live, storeID)
+			}
+		}
+		mockStorePool(sp, live, nil, tcase.dead, nil, nil)
+
+		zone := config.ZoneConfig{
+			NumReplicas: proto.Int32(5),
+			NumVoters:   proto.Int32(tcase.numVoters),
+		}
+		action, _ := a.ComputeAction(ctx, &zone, RangeInfo{Desc: &tcase.desc})
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %s, got action %s", i, tcase.expectedAction, action)
+		}
+	}
+}
+
+func TestAllocatorPromoteTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(sameDCStores, t)
+
+	voters := []roachpb.ReplicaDescriptor{
+		{StoreID: 1, NodeID: 1, ReplicaID: 1},
+		{StoreID: 2, NodeID: 2, ReplicaID: 2},
+	}
+	nonVoters := []roachpb.ReplicaDescriptor{
+		{StoreID: 4, NodeID: 4, ReplicaID: 4, Type: roachpb.ReplicaType_NON_VOTER.Enum()},
+	}
+	rangeInfo := testRangeInfo(
+		append(append([]roachpb.ReplicaDescriptor(nil), voters...), nonVoters...), firstRange)
+
+	// The only candidates are the non-voters, even though other stores could
+	// hold a new voter.
+	target, _, err := a.PromoteTarget(ctx, &simpleZoneConfig, voters, nonVoters, rangeInfo)
+	if err != nil {
+		t.Fatalf("unable to find a non-voter to promote: %+v", err)
+	}
+	if target.StoreID != 4 {
+		t.Errorf("expected the non-voter on store 4 to be promoted, got %+v", target)
+	}
+
+	if _, _, err := a.PromoteTarget(ctx, &simpleZoneConfig, voters, nil, rangeInfo); err == nil {
+		t.Errorf("expected an error without non-voters")
+	}
+}
+
 func TestAllocatorComputeActionDecommission(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -4981,6 +5165,34 @@ func TestAllocatorGetNeededReplicas(t *testing.T) {
 	}
 }
 
+func TestAllocatorGetNeededNonVoters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		zoneNonVoters int32
+		numVoters     int
+		clusterNodes  int
+		expected      int
+	}{
+		{0, 3, 3, 0},
+		{0, 3, 5, 0},
+		{1, 3, 3, 0},
+		{1, 3, 4, 1},
+		{2, 3, 4, 1},
+		{2, 3, 5, 2},
+		{2, 3, 7, 2},
+		{2, 5, 3, 0},
+	}
+
+	for _, tc := range testCases {
+		if e, a := tc.expected, GetNeededNonVoters(tc.zoneNonVoters, tc.numVoters, tc.clusterNodes); e != a {
+			t.Errorf(
+				"GetNeededNonVoters(nonVoters=%d, numVoters=%d, clusterNodes=%d) got %d; want %d",
+				tc.zoneNonVoters, tc.numVoters, tc.clusterNodes, a, e)
+		}
+	}
+}
+
 func makeDescriptor(storeList []roachpb.StoreID) roachpb.RangeDescriptor {
 	desc := roachpb.RangeDescriptor{
 		EndKey: roachpb.RKey(keys.SystemPrefix),
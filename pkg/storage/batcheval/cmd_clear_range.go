@@ -11,6 +11,7 @@
 package batcheval
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/kr/pretty"
 )
@@ -43,6 +45,9 @@ func declareKeysClearRange(
 	// We look up the range descriptor key to check whether the span
 	// is equal to the entire range for fast stats updating.
 	spans.Add(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
+	// The MVCC range tombstones contained in the span are removed.
+	prefix := keys.RangeTombstonePrefix(header.RangeID)
+	spans.Add(spanset.SpanReadWrite, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
 }
 
 // ClearRange wipes all MVCC versions of keys covered by the specified
@@ -73,6 +78,12 @@ func ClearRange(
 	}
 	cArgs.Stats.Subtract(statsDelta)
 
+	// Remove the range tombstones contained in the span, which no longer
+	// delete anything once the span is cleared.
+	if err := clearRangeTombstones(ctx, batch, cArgs, args.Key, args.EndKey); err != nil {
+		return result.Result{}, err
+	}
+
 	// If the total size of data to be cleared is less than
 	// clearRangeBytesThreshold, clear the individual values manually,
 	// instead of using a range tombstone (inefficient for small ranges).
@@ -138,7 +149,7 @@ func computeStatsDelta(
 	// compute stats across the key span to be cleared.
 	if !fast || util.RaceEnabled {
 		iter := batch.NewIterator(engine.IterOptions{UpperBound: to.Key})
-		computed, err := iter.ComputeStats(from, to, delta.LastUpdateNanos)
+		computed, err := computeStatsWithRangeTombstones(ctx, batch, iter, desc.RangeID, from, to, delta.LastUpdateNanos)
 		iter.Close()
 		if err != nil {
 			return enginepb.MVCCStats{}, err
@@ -155,3 +166,53 @@ func computeStatsDelta(
 
 	return delta, nil
 }
+
+// computeStatsWithRangeTombstones computes the stats of the span, taking into
+// account the MVCC range tombstones of the range.
+func computeStatsWithRangeTombstones(
+	ctx context.Context,
+	batch engine.Reader,
+	iter engine.Iterator,
+	rangeID roachpb.RangeID,
+	from, to engine.MVCCKey,
+	nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	ms, err := iter.ComputeStats(from, to, nowNanos)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	tombstones, err := engine.MVCCScanRangeTombstones(ctx, batch, rangeID, from.Key, to.Key)
+	if err != nil || len(tombstones) == 0 {
+		return ms, err
+	}
+	tombstoneMS, err := engine.ComputeRangeTombstoneStats(iter, tombstones, from.Key, to.Key, nowNanos)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	ms.Add(tombstoneMS)
+	return ms, nil
+}
+
+// clearRangeTombstones removes the MVCC range tombstones of the range that are
+// contained in [key, endKey).
+func clearRangeTombstones(
+	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, key, endKey roachpb.Key,
+) error {
+	rangeID := cArgs.EvalCtx.GetRangeID()
+	tombstones, err := engine.MVCCScanRangeTombstones(ctx, batch, rangeID, key, endKey)
+	if err != nil {
+		return err
+	}
+	for _, t := range tombstones {
+		if bytes.Compare(t.StartKey, key) < 0 || bytes.Compare(endKey, t.EndKey) < 0 {
+			continue
+		}
+		if err := engine.MVCCDelete(
+			ctx, batch, cArgs.Stats, keys.RangeTombstoneKey(rangeID, t.StartKey, t.Timestamp),
+			hlc.Timestamp{}, nil,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.ConditionalPut, DefaultDeclareKeys, ConditionalPut)
+	RegisterCommand(roachpb.ConditionalPut, declareKeysReadRangeTombstones, ConditionalPut)
 }
 
 // ConditionalPut sets the value for a specified key only if
@@ -29,7 +29,22 @@ func ConditionalPut(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.ConditionalPutRequest)
-	h := cArgs.Header
+
+	rt, err := checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	h := rt.header
+	if deleted, err := rt.deletedValue(ctx, batch, args.Key); err != nil {
+		return result.Result{}, err
+	} else if deleted != nil {
+		// The existing value was deleted by a range tombstone, so the condition
+		// is evaluated against a missing value.
+		if args.ExpValue != nil && !args.AllowIfDoesNotExist {
+			return result.Result{}, &roachpb.ConditionFailedError{}
+		}
+		return result.Result{}, rt.finish(engine.MVCCPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, h.Txn))
+	}
 
 	if h.DistinctSpans {
 		if b, ok := batch.(engine.Batch); ok {
@@ -42,7 +57,7 @@ func ConditionalPut(
 	}
 	handleMissing := engine.CPutMissingBehavior(args.AllowIfDoesNotExist)
 	if args.Blind {
-		return result.Result{}, engine.MVCCBlindConditionalPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.ExpValue, handleMissing, h.Txn)
+		return result.Result{}, rt.finish(engine.MVCCBlindConditionalPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.ExpValue, handleMissing, h.Txn))
 	}
-	return result.Result{}, engine.MVCCConditionalPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.ExpValue, handleMissing, h.Txn)
+	return result.Result{}, rt.finish(engine.MVCCConditionalPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.ExpValue, handleMissing, h.Txn))
 }
@@ -19,7 +19,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Delete, DefaultDeclareKeys, Delete)
+	RegisterCommand(roachpb.Delete, declareKeysReadRangeTombstones, Delete)
 }
 
 // Delete deletes the key and value specified by key.
@@ -27,7 +27,11 @@ func Delete(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.DeleteRequest)
-	h := cArgs.Header
 
-	return result.Result{}, engine.MVCCDelete(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, h.Txn)
+	rt, err := checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	h := rt.header
+	return result.Result{}, rt.finish(engine.MVCCDelete(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, h.Txn))
 }
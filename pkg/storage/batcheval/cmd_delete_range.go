@@ -13,14 +13,33 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
 )
 
 func init() {
-	RegisterCommand(roachpb.DeleteRange, DefaultDeclareKeys, DeleteRange)
+	RegisterCommand(roachpb.DeleteRange, declareKeysDeleteRange, DeleteRange)
+}
+
+func declareKeysDeleteRange(
+	desc *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
+) {
+	if !req.(*roachpb.DeleteRangeRequest).UseRangeTombstone {
+		declareKeysReadRangeTombstones(desc, header, req, spans)
+		return
+	}
+	DefaultDeclareKeys(desc, header, req, spans)
+	prefix := keys.RangeTombstonePrefix(header.RangeID)
+	spans.Add(spanset.SpanReadWrite, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	// We look up the range descriptor key to check whether the span
+	// is equal to the entire range for fast stats updating.
+	spans.Add(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
 }
 
 // DeleteRange deletes the range of key/value pairs specified by
@@ -29,13 +48,22 @@ func DeleteRange(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.DeleteRangeRequest)
-	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		return result.Result{}, deleteRangeUsingTombstone(ctx, batch, cArgs)
+	}
+
+	rt := rangeTombstoneWrite{header: cArgs.Header}
 	var timestamp hlc.Timestamp
 	if !args.Inline {
-		timestamp = h.Timestamp
+		var err error
+		if rt, err = checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span()); err != nil {
+			return result.Result{}, err
+		}
+		timestamp = rt.header.Timestamp
 	}
+	h := rt.header
 	deleted, resumeSpan, num, err := engine.MVCCDeleteRange(
 		ctx, batch, cArgs.Stats, args.Key, args.EndKey, cArgs.MaxKeys, timestamp, h.Txn, args.ReturnKeys,
 	)
@@ -47,5 +75,32 @@ func DeleteRange(
 		reply.ResumeSpan = resumeSpan
 		reply.ResumeReason = roachpb.RESUME_KEY_LIMIT
 	}
-	return result.Result{}, err
+	return result.Result{}, rt.finish(err)
+}
+
+// deleteRangeUsingTombstone deletes the span of the DeleteRangeRequest by
+// writing an MVCC range tombstone. See engine.MVCCDeleteRangeUsingTombstone.
+func deleteRangeUsingTombstone(
+	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs,
+) error {
+	args := cArgs.Args.(*roachpb.DeleteRangeRequest)
+	h := cArgs.Header
+	if h.Txn != nil {
+		return errors.New("cannot write range tombstone within a transaction")
+	}
+	if args.Inline || args.ReturnKeys {
+		return errors.New("cannot write range tombstone with inline or return_keys set")
+	}
+
+	// If the range tombstone covers the entire range, its effect on the stats
+	// can be determined from the stats of the range. See computeStatsDelta.
+	var msCovered *enginepb.MVCCStats
+	if desc := cArgs.EvalCtx.Desc(); desc.StartKey.Equal(args.Key) && desc.EndKey.Equal(args.EndKey) {
+		ms := cArgs.EvalCtx.GetMVCCStats()
+		ms.SysCount, ms.SysBytes = 0, 0
+		msCovered = &ms
+	}
+	return engine.MVCCDeleteRangeUsingTombstone(
+		ctx, batch, cArgs.Stats, cArgs.EvalCtx.GetRangeID(), args.Key, args.EndKey, h.Timestamp, msCovered,
+	)
 }
@@ -222,7 +222,11 @@ func EndTransaction(
 				}
 				// Use alwaysReturn==true because the transaction is definitely
 				// aborted, no matter what happens to this command.
-				return result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison), nil
+				res := result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison)
+				if err := res.MergeAndDestroy(releaseLocksResult(args, reply.Txn)); err != nil {
+					return result.Result{}, err
+				}
+				return res, nil
 			}
 			// If the transaction was previously aborted by a concurrent writer's
 			// push, any intents written are still open. It's only now that we know
@@ -256,8 +260,12 @@ func EndTransaction(
 			)
 		}
 
-		// Update the existing txn with the supplied txn.
+		// Update the existing txn with the supplied txn. The record's list of
+		// ignored seqnum ranges may have been written by an earlier request, so
+		// the list on the request, which reflects all savepoint rollbacks
+		// performed by the coordinator, is the one used to resolve intents.
 		reply.Txn.Update(h.Txn)
+		reply.Txn.IgnoredSeqNums = h.Txn.IgnoredSeqNums
 	}
 
 	var pd result.Result
@@ -369,9 +377,20 @@ func EndTransaction(
 	if err := pd.MergeAndDestroy(intentsResult); err != nil {
 		return result.Result{}, err
 	}
+	if err := pd.MergeAndDestroy(releaseLocksResult(args, reply.Txn)); err != nil {
+		return result.Result{}, err
+	}
 	return pd, nil
 }
 
+// releaseLocksResult returns a Result releasing the unreplicated locks held by
+// the transaction in its intent spans if it has been finalized. Only locks on
+// this range are affected; those on other ranges are released when the
+// transaction's external intents are resolved.
+func releaseLocksResult(args *roachpb.EndTransactionRequest, txn *roachpb.Transaction) result.Result {
+	return result.FromResolvedLocks(roachpb.AsIntents(args.IntentSpans, txn)...)
+}
+
 // IsEndTransactionExceedingDeadline returns true if the transaction
 // exceeded its deadline.
 func IsEndTransactionExceedingDeadline(t hlc.Timestamp, args *roachpb.EndTransactionRequest) bool {
@@ -476,7 +495,12 @@ func resolveLocalIntents(
 				externalIntents = append(externalIntents, span)
 				return nil
 			}
-			intent := roachpb.Intent{Span: span, Txn: txn.TxnMeta, Status: txn.Status}
+			intent := roachpb.Intent{
+				Span:           span,
+				Txn:            txn.TxnMeta,
+				Status:         txn.Status,
+				IgnoredSeqNums: txn.IgnoredSeqNums,
+			}
 			if len(span.EndKey) == 0 {
 				// For single-key intents, do a KeyAddress-aware check of
 				// whether it's contained in our Range.
@@ -841,10 +865,26 @@ func splitTrigger(
 			rightMS, err := rditer.ComputeStatsForRange(
 				&split.RightDesc, batch, ts.WallTime,
 			)
-			return rightMS, errors.Wrap(
-				err,
-				"unable to compute stats for RHS range after split",
-			)
+			if err != nil {
+				return enginepb.MVCCStats{}, errors.Wrap(
+					err,
+					"unable to compute stats for RHS range after split",
+				)
+			}
+			// The range tombstones of the RHS are only copied from the LHS
+			// in splitTriggerHelper, so account for the LHS's range tombstones
+			// that overlap the RHS instead.
+			lhsTombstonesDesc := split.RightDesc
+			lhsTombstonesDesc.RangeID = split.LeftDesc.RangeID
+			tombstoneMS, err := rditer.ComputeRangeTombstoneStats(ctx, &lhsTombstonesDesc, batch, ts.WallTime)
+			if err != nil {
+				return enginepb.MVCCStats{}, errors.Wrap(
+					err,
+					"unable to compute range tombstone stats for RHS range after split",
+				)
+			}
+			rightMS.Add(tombstoneMS)
+			return rightMS, nil
 		},
 	}
 	return splitTriggerHelper(ctx, rec, batch, h, split, ts)
@@ -891,6 +931,17 @@ func splitTriggerHelper(
 		return enginepb.MVCCStats{}, result.Result{}, err
 	}
 
+	// Initialize the RHS range's MVCC range tombstones by copying the ones of
+	// the LHS that overlap it, clipped to the RHS. The LHS keeps its range
+	// tombstones unchanged, as the post-split keyspace of the LHS is frozen;
+	// range tombstones are always clipped to the bounds of the range when
+	// they're used.
+	if err := copyRangeTombstones(
+		ctx, batch, h.AbsPostSplitRight(), split.LeftDesc.RangeID, split.RightDesc,
+	); err != nil {
+		return enginepb.MVCCStats{}, result.Result{}, err
+	}
+
 	// Note: we don't copy the queue last processed times. This means
 	// we'll process the RHS range in consistency and time series
 	// maintenance queues again possibly sooner than if we copied. The
@@ -1055,11 +1106,18 @@ func mergeTrigger(
 	); err != nil {
 		return result.Result{}, err
 	}
+	mergedDesc := merge.RightDesc
+	mergedDesc.RangeID = merge.LeftDesc.RangeID
+	if err := copyRangeTombstones(
+		ctx, batch, ms, merge.RightDesc.RangeID, mergedDesc,
+	); err != nil {
+		return result.Result{}, err
+	}
 
 	// The stats for the merged range are the sum of the LHS and RHS stats, less
 	// the RHS's replicated range ID stats. The only replicated range ID keys we
-	// copy from the RHS are the keys in the abort span, and we've already
-	// accounted for those stats above.
+	// copy from the RHS are the keys in the abort span and the range
+	// tombstones, and we've already accounted for those stats above.
 	ms.Add(merge.RightMVCCStats)
 	{
 		ridPrefix := keys.MakeRangeIDReplicatedPrefix(merge.RightDesc.RangeID)
@@ -1083,6 +1141,38 @@ func mergeTrigger(
 	return pd, nil
 }
 
+// copyRangeTombstones copies the MVCC range tombstones of the range with ID
+// srcRangeID that overlap the span of dest to the range described by dest,
+// clipping them to its span.
+func copyRangeTombstones(
+	ctx context.Context,
+	batch engine.ReadWriter,
+	ms *enginepb.MVCCStats,
+	srcRangeID roachpb.RangeID,
+	dest roachpb.RangeDescriptor,
+) error {
+	start, end := dest.StartKey.AsRawKey(), dest.EndKey.AsRawKey()
+	tombstones, err := engine.MVCCScanRangeTombstones(ctx, batch, srcRangeID, start, end)
+	if err != nil {
+		return err
+	}
+	for _, t := range tombstones {
+		if bytes.Compare(t.StartKey, start) < 0 {
+			t.StartKey = start
+		}
+		if bytes.Compare(end, t.EndKey) < 0 {
+			t.EndKey = end
+		}
+		if err := engine.MVCCPutProto(
+			ctx, batch, ms, keys.RangeTombstoneKey(dest.RangeID, t.StartKey, t.Timestamp),
+			hlc.Timestamp{}, nil, &t,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func changeReplicasTrigger(
 	ctx context.Context, rec EvalContext, batch engine.Batch, change *roachpb.ChangeReplicasTrigger,
 ) result.Result {
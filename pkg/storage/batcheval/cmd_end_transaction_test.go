@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -56,6 +57,8 @@ func TestEndTransactionUpdatesTransactionRecord(t *testing.T) {
 	restartedAndPushedHeaderTxn := txn.Clone()
 	restartedAndPushedHeaderTxn.Restart(-1, 0, ts2)
 	restartedAndPushedHeaderTxn.Timestamp.Forward(ts3)
+	rolledBackHeaderTxn := txn.Clone()
+	rolledBackHeaderTxn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}
 
 	pendingRecord := func() *roachpb.TransactionRecord {
 		record := txn.AsRecord()
@@ -500,6 +503,27 @@ func TestEndTransactionUpdatesTransactionRecord(t *testing.T) {
 			// Expected result.
 			expTxn: committedRecord,
 		},
+		{
+			// The transaction rolled back to a savepoint after its record was
+			// heartbeated, merging the record's ignored seqnum ranges into a
+			// single range. The shorter list on the request must be used.
+			name: "record pending with stale ignored seqnums, try commit",
+			// Replica state.
+			existingTxn: func() *roachpb.TransactionRecord {
+				record := *pendingRecord
+				record.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}, {Start: 5, End: 6}}
+				return &record
+			}(),
+			// Request state.
+			headerTxn: rolledBackHeaderTxn,
+			commit:    true,
+			// Expected result.
+			expTxn: func() *roachpb.TransactionRecord {
+				record := *committedRecord
+				record.IgnoredSeqNums = rolledBackHeaderTxn.IgnoredSeqNums
+				return &record
+			}(),
+		},
 		{
 			// The transaction's commit timestamp was increased during its
 			// lifetime, but it hasn't refreshed up to its new commit timestamp.
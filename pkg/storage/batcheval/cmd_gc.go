@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
 )
 
 func init() {
@@ -35,6 +36,13 @@ func declareKeysGC(
 	for _, key := range gcr.Keys {
 		spans.Add(spanset.SpanReadWrite, roachpb.Span{Key: key.Key})
 	}
+	if len(gcr.RangeTombstones) > 0 {
+		for _, t := range gcr.RangeTombstones {
+			spans.Add(spanset.SpanReadWrite, roachpb.Span{Key: t.StartKey, EndKey: t.EndKey})
+		}
+		prefix := keys.RangeTombstonePrefix(header.RangeID)
+		spans.Add(spanset.SpanReadWrite, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
 	// Be smart here about blocking on the threshold keys. The GC queue can send an empty
 	// request first to bump the thresholds, and then another one that actually does work
 	// but can avoid declaring these keys below.
@@ -46,8 +54,10 @@ func declareKeysGC(
 
 // GC iterates through the list of keys to garbage collect
 // specified in the arguments. MVCCGarbageCollect is invoked on each
-// listed key along with the expiration timestamp. The GC metadata
-// specified in the args is persisted after GC.
+// listed key along with the expiration timestamp. The versions deleted
+// by the listed MVCC range tombstones are removed along with them by
+// MVCCGarbageCollectRangeTombstone. The GC metadata specified in the
+// args is persisted after GC.
 func GC(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
@@ -73,6 +83,25 @@ func GC(
 		return result.Result{}, err
 	}
 
+	// Garbage collect the versions deleted by range tombstones, which must
+	// not be above the GC threshold.
+	if len(args.RangeTombstones) > 0 {
+		gcThreshold := cArgs.EvalCtx.GetGCThreshold()
+		gcThreshold.Forward(args.Threshold)
+		desc := cArgs.EvalCtx.Desc()
+		for _, t := range args.RangeTombstones {
+			if gcThreshold.Less(t.Timestamp) {
+				return result.Result{}, errors.Errorf(
+					"range tombstone at %s is above the GC threshold %s", t.Timestamp, gcThreshold)
+			}
+			if err := engine.MVCCGarbageCollectRangeTombstone(
+				ctx, batch, cArgs.Stats, desc.RangeID, desc.RSpan(), t,
+			); err != nil {
+				return result.Result{}, err
+			}
+		}
+	}
+
 	// Protect against multiple GC requests arriving out of order; we track
 	// the maximum timestamps.
 
@@ -20,7 +20,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Get, DefaultDeclareKeys, Get)
+	RegisterCommand(roachpb.Get, declareKeysReadRangeTombstones, Get)
 }
 
 // Get returns the value for a specified key.
@@ -31,11 +31,22 @@ func Get(
 	h := cArgs.Header
 	reply := resp.(*roachpb.GetResponse)
 
+	rangeTombstones, err := rangeTombstonesForSpan(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	locked := skipLockedFilter(cArgs, args.KeyLocking)
 	val, intent, err := engine.MVCCGet(ctx, batch, args.Key, h.Timestamp, engine.MVCCGetOptions{
-		Inconsistent:   h.ReadConsistency != roachpb.CONSISTENT,
-		IgnoreSequence: shouldIgnoreSequenceNums(),
-		Txn:            h.Txn,
+		Inconsistent:    h.ReadConsistency != roachpb.CONSISTENT,
+		IgnoreSequence:  shouldIgnoreSequenceNums(),
+		Txn:             h.Txn,
+		RangeTombstones: rangeTombstones,
 	})
+	if _, ok := err.(*roachpb.WriteIntentError); ok && locked != nil {
+		// The key has an intent of another transaction, which is a lock that
+		// the read skips.
+		val, intent, err = nil, nil, nil
+	}
 	if err != nil {
 		return result.Result{}, err
 	}
@@ -44,6 +55,17 @@ func Get(
 		intents = append(intents, *intent)
 	}
 
+	// Skip the key if it's locked by another transaction and the read skips
+	// locked keys. Otherwise, lock the key if it exists and this is a locking
+	// read.
+	if locked != nil && locked(args.Key) {
+		val = nil
+	}
+	var lockKeys []roachpb.Key
+	if val != nil && args.KeyLocking != roachpb.NON_LOCKING {
+		lockKeys = []roachpb.Key{args.Key}
+	}
+
 	reply.Value = val
 	if h.ReadConsistency == roachpb.READ_UNCOMMITTED {
 		var intentVals []roachpb.KeyValue
@@ -58,7 +80,13 @@ func Get(
 			}
 		}
 	}
-	return result.FromIntents(intents, args), err
+	res := result.FromIntents(intents, args)
+	if mergeErr := res.MergeAndDestroy(
+		result.FromAcquiredLocks(h.Txn, args.KeyLocking, lockKeys),
+	); mergeErr != nil {
+		return result.Result{}, mergeErr
+	}
+	return res, err
 }
 
 func shouldIgnoreSequenceNums() bool {
@@ -19,7 +19,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Increment, DefaultDeclareKeys, Increment)
+	RegisterCommand(roachpb.Increment, declareKeysReadRangeTombstones, Increment)
 }
 
 // Increment increments the value (interpreted as varint64 encoded) and
@@ -29,10 +29,26 @@ func Increment(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.IncrementRequest)
-	h := cArgs.Header
 	reply := resp.(*roachpb.IncrementResponse)
 
+	rt, err := checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	h := rt.header
+	if deleted, err := rt.deletedValue(ctx, batch, args.Key); err != nil {
+		return result.Result{}, err
+	} else if deleted != nil {
+		// The existing value was deleted by a range tombstone, so zero is
+		// incremented.
+		var newValue roachpb.Value
+		newValue.SetInt(args.Increment)
+		newValue.InitChecksum(args.Key)
+		reply.NewValue = args.Increment
+		return result.Result{}, rt.finish(engine.MVCCPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, newValue, h.Txn))
+	}
+
 	newVal, err := engine.MVCCIncrement(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, h.Txn, args.Increment)
 	reply.NewValue = newVal
-	return result.Result{}, err
+	return result.Result{}, rt.finish(err)
 }
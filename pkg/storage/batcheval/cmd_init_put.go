@@ -19,7 +19,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.InitPut, DefaultDeclareKeys, InitPut)
+	RegisterCommand(roachpb.InitPut, declareKeysReadRangeTombstones, InitPut)
 }
 
 // InitPut sets the value for a specified key only if it doesn't exist. It
@@ -30,7 +30,22 @@ func InitPut(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.InitPutRequest)
-	h := cArgs.Header
+
+	rt, err := checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	h := rt.header
+	if deleted, err := rt.deletedValue(ctx, batch, args.Key); err != nil {
+		return result.Result{}, err
+	} else if deleted != nil {
+		// The existing value was deleted by a range tombstone, which counts as
+		// a tombstone.
+		if args.FailOnTombstones {
+			return result.Result{}, &roachpb.ConditionFailedError{ActualValue: deleted}
+		}
+		return result.Result{}, rt.finish(engine.MVCCPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, h.Txn))
+	}
 
 	if h.DistinctSpans {
 		if b, ok := batch.(engine.Batch); ok {
@@ -42,7 +57,7 @@ func InitPut(
 		}
 	}
 	if args.Blind {
-		return result.Result{}, engine.MVCCBlindInitPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.FailOnTombstones, h.Txn)
+		return result.Result{}, rt.finish(engine.MVCCBlindInitPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.FailOnTombstones, h.Txn))
 	}
-	return result.Result{}, engine.MVCCInitPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.FailOnTombstones, h.Txn)
+	return result.Result{}, rt.finish(engine.MVCCInitPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, args.Value, args.FailOnTombstones, h.Txn))
 }
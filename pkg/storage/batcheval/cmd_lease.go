@@ -82,7 +82,8 @@ func evalNewLease(
 
 	// Verify that requesting replica is part of the current replica set.
 	desc := rec.Desc()
-	if _, ok := desc.GetReplicaDescriptor(lease.Replica.StoreID); !ok {
+	repDesc, ok := desc.GetReplicaDescriptor(lease.Replica.StoreID)
+	if !ok {
 		return newFailedLeaseTrigger(isTransfer),
 			&roachpb.LeaseRejectedError{
 				Existing:  prevLease,
@@ -90,6 +91,16 @@ func evalNewLease(
 				Message:   "replica not found",
 			}
 	}
+	// Non-voting replicas don't participate in raft elections, so they can't
+	// hold the lease.
+	if repDesc.GetType() == roachpb.ReplicaType_NON_VOTER {
+		return newFailedLeaseTrigger(isTransfer),
+			&roachpb.LeaseRejectedError{
+				Existing:  prevLease,
+				Requested: lease,
+				Message:   "replica is a non-voter",
+			}
+	}
 
 	// Requests should not set the sequence number themselves. Set the sequence
 	// number here based on whether the lease is equivalent to the one it's
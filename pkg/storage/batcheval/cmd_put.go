@@ -20,7 +20,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Put, DefaultDeclareKeys, Put)
+	RegisterCommand(roachpb.Put, declareKeysReadRangeTombstones, Put)
 }
 
 // Put sets the value for a specified key.
@@ -28,13 +28,18 @@ func Put(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
 	args := cArgs.Args.(*roachpb.PutRequest)
-	h := cArgs.Header
 	ms := cArgs.Stats
 
+	rt := rangeTombstoneWrite{header: cArgs.Header}
 	var ts hlc.Timestamp
 	if !args.Inline {
-		ts = h.Timestamp
+		var err error
+		if rt, err = checkRangeTombstonesForWrite(ctx, batch, cArgs, args.Span()); err != nil {
+			return result.Result{}, err
+		}
+		ts = rt.header.Timestamp
 	}
+	h := rt.header
 	if h.DistinctSpans {
 		if b, ok := batch.(engine.Batch); ok {
 			// Use the distinct batch for both blind and normal ops so that we don't
@@ -45,7 +50,7 @@ func Put(
 		}
 	}
 	if args.Blind {
-		return result.Result{}, engine.MVCCBlindPut(ctx, batch, ms, args.Key, ts, args.Value, h.Txn)
+		return result.Result{}, rt.finish(engine.MVCCBlindPut(ctx, batch, ms, args.Key, ts, args.Value, h.Txn))
 	}
-	return result.Result{}, engine.MVCCPut(ctx, batch, ms, args.Key, ts, args.Value, h.Txn)
+	return result.Result{}, rt.finish(engine.MVCCPut(ctx, batch, ms, args.Key, ts, args.Value, h.Txn))
 }
@@ -21,7 +21,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Refresh, DefaultDeclareKeys, Refresh)
+	RegisterCommand(roachpb.Refresh, declareKeysReadRangeTombstones, Refresh)
 }
 
 // Refresh checks the key for more recently written values than the
@@ -37,13 +37,20 @@ func Refresh(
 		return result.Result{}, errors.Errorf("no transaction specified to %s", args.Method())
 	}
 
+	rangeTombstones, err := rangeTombstonesForSpan(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+
 	// Get the most recent committed value and return any intent by
 	// specifying consistent=false. Note that we include tombstones,
-	// which must be considered as updates on refresh.
+	// which must be considered as updates on refresh. This includes the
+	// deletion of the value by a range tombstone.
 	log.VEventf(ctx, 2, "refresh %s @[%s-%s]", args.Span(), h.Txn.OrigTimestamp, h.Txn.Timestamp)
 	val, intent, err := engine.MVCCGet(ctx, batch, args.Key, h.Txn.Timestamp, engine.MVCCGetOptions{
-		Inconsistent: true,
-		Tombstones:   true,
+		Inconsistent:    true,
+		Tombstones:      true,
+		RangeTombstones: rangeTombstones,
 	})
 
 	if err != nil {
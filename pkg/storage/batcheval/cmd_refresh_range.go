@@ -21,7 +21,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.RefreshRange, DefaultDeclareKeys, RefreshRange)
+	RegisterCommand(roachpb.RefreshRange, declareKeysReadRangeTombstones, RefreshRange)
 }
 
 // RefreshRange scans the key range specified by start key through end
@@ -38,15 +38,22 @@ func RefreshRange(
 		return result.Result{}, errors.Errorf("no transaction specified to %s", args.Method())
 	}
 
+	rangeTombstones, err := rangeTombstonesForSpan(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+
 	// Iterate over values until we discover any value written at or after the
 	// original timestamp, but before or at the current timestamp. Note that we
 	// iterate inconsistently without using the txn. This reads only committed
 	// values and returns all intents, including those from the txn itself. Note
 	// that we include tombstones, which must be considered as updates on refresh.
+	// This includes the deletion of values by range tombstones.
 	log.VEventf(ctx, 2, "refresh %s @[%s-%s]", args.Span(), h.Txn.OrigTimestamp, h.Txn.Timestamp)
 	intents, err := engine.MVCCIterate(ctx, batch, args.Key, args.EndKey, h.Txn.Timestamp, engine.MVCCScanOptions{
-		Inconsistent: true,
-		Tombstones:   true,
+		Inconsistent:    true,
+		Tombstones:      true,
+		RangeTombstones: rangeTombstones,
 	}, func(kv roachpb.KeyValue) (bool, error) {
 		// TODO(nvanbenschoten): This is pessimistic. We only need to check
 		//   !ts.Less(h.Txn.PrevRefreshTimestamp)
@@ -131,6 +131,7 @@ func TestRefreshRangeTimeBoundIterator(t *testing.T) {
 	// resulting in an error from RefreshRange.
 	var resp roachpb.RefreshRangeResponse
 	_, err = RefreshRange(ctx, db, CommandArgs{
+		EvalCtx: &mockEvalCtx{desc: &roachpb.RangeDescriptor{RangeID: 1}},
 		Args: &roachpb.RefreshRangeRequest{
 			RequestHeader: roachpb.RequestHeader{
 				Key:    k,
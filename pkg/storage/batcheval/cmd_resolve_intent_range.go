@@ -43,9 +43,10 @@ func ResolveIntentRange(
 	}
 
 	intent := roachpb.Intent{
-		Span:   args.Span(),
-		Txn:    args.IntentTxn,
-		Status: args.Status,
+		Span:           args.Span(),
+		Txn:            args.IntentTxn,
+		Status:         args.Status,
+		IgnoredSeqNums: args.IgnoredSeqNums,
 	}
 
 	iterAndBuf := engine.GetIterAndBuf(batch, engine.IterOptions{UpperBound: args.EndKey})
@@ -64,7 +65,8 @@ func ResolveIntentRange(
 		reply.ResumeReason = roachpb.RESUME_KEY_LIMIT
 	}
 
-	var res result.Result
+	// Release any unreplicated locks held by the finalized transaction.
+	res := result.FromResolvedLocks(intent)
 	res.Local.Metrics = resolveToMetricType(args.Status, args.Poison)
 
 	if WriteAbortSpanOnResolve(args.Status) {
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/locktable"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
@@ -41,6 +42,7 @@ type mockEvalCtx struct {
 	gcThreshold      hlc.Timestamp
 	term, firstIndex uint64
 	canCreateTxnFn   func() (bool, hlc.Timestamp, roachpb.TransactionAbortedReason)
+	lockTable        *locktable.Table
 }
 
 func (m *mockEvalCtx) String() string {
@@ -70,6 +72,9 @@ func (m *mockEvalCtx) AbortSpan() *abortspan.AbortSpan {
 func (m *mockEvalCtx) GetTxnWaitQueue() *txnwait.Queue {
 	panic("unimplemented")
 }
+func (m *mockEvalCtx) GetLockTable() *locktable.Table {
+	return m.lockTable
+}
 func (m *mockEvalCtx) NodeID() roachpb.NodeID {
 	panic("unimplemented")
 }
@@ -20,7 +20,7 @@ import (
 )
 
 func init() {
-	RegisterCommand(roachpb.Scan, DefaultDeclareKeys, Scan)
+	RegisterCommand(roachpb.Scan, declareKeysReadRangeTombstones, Scan)
 }
 
 // Scan scans the key range specified by start key through end key
@@ -37,34 +37,87 @@ func Scan(
 	var err error
 	var intents []roachpb.Intent
 	var resumeSpan *roachpb.Span
+	// The keys to lock if this is a locking read. Keys locked by other
+	// transactions, either by unreplicated locks or by intents, are filtered
+	// out of the result if the read skips them.
+	var lockKeys []roachpb.Key
+	locked := skipLockedFilter(cArgs, args.KeyLocking)
+
+	rangeTombstones, err := rangeTombstonesForSpan(ctx, batch, cArgs, args.Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	opts := engine.MVCCScanOptions{
+		Inconsistent:    h.ReadConsistency != roachpb.CONSISTENT,
+		IgnoreSequence:  shouldIgnoreSequenceNums(),
+		Txn:             h.Txn,
+		RangeTombstones: rangeTombstones,
+	}
 
 	switch args.ScanFormat {
 	case roachpb.BATCH_RESPONSE:
 		var kvData []byte
 		var numKvs int64
-		kvData, numKvs, resumeSpan, intents, err = engine.MVCCScanToBytes(
-			ctx, batch, args.Key, args.EndKey, cArgs.MaxKeys, h.Timestamp,
-			engine.MVCCScanOptions{
-				Inconsistent:   h.ReadConsistency != roachpb.CONSISTENT,
-				IgnoreSequence: shouldIgnoreSequenceNums(),
-				Txn:            h.Txn,
+		resumeSpan, err = scanSkippingIntents(
+			args.Span(), cArgs.MaxKeys, false /* reverse */, locked != nil,
+			func(span roachpb.Span, maxKeys int64) (int64, *roachpb.Span, error) {
+				data, n, resumeSpan, spanIntents, err := engine.MVCCScanToBytes(
+					ctx, batch, span.Key, span.EndKey, maxKeys, h.Timestamp, opts)
+				if err != nil {
+					return 0, nil, err
+				}
+				// Avoid copying the data unless locked keys were skipped.
+				if kvData == nil {
+					kvData = data
+				} else {
+					kvData = append(kvData, data...)
+				}
+				numKvs += n
+				intents = append(intents, spanIntents...)
+				return n, resumeSpan, nil
 			})
 		if err != nil {
 			return result.Result{}, err
 		}
+		if locked != nil {
+			if kvData, numKvs, err = filterLockedBatchResponse(kvData, locked); err != nil {
+				return result.Result{}, err
+			}
+		}
+		if args.KeyLocking != roachpb.NON_LOCKING {
+			if lockKeys, err = batchResponseKeys(kvData); err != nil {
+				return result.Result{}, err
+			}
+		}
 		reply.NumKeys = numKvs
 		reply.BatchResponses = [][]byte{kvData}
 	case roachpb.KEY_VALUES:
 		var rows []roachpb.KeyValue
-		rows, resumeSpan, intents, err = engine.MVCCScan(
-			ctx, batch, args.Key, args.EndKey, cArgs.MaxKeys, h.Timestamp, engine.MVCCScanOptions{
-				Inconsistent:   h.ReadConsistency != roachpb.CONSISTENT,
-				IgnoreSequence: shouldIgnoreSequenceNums(),
-				Txn:            h.Txn,
+		resumeSpan, err = scanSkippingIntents(
+			args.Span(), cArgs.MaxKeys, false /* reverse */, locked != nil,
+			func(span roachpb.Span, maxKeys int64) (int64, *roachpb.Span, error) {
+				spanRows, resumeSpan, spanIntents, err := engine.MVCCScan(
+					ctx, batch, span.Key, span.EndKey, maxKeys, h.Timestamp, opts)
+				if err != nil {
+					return 0, nil, err
+				}
+				if rows == nil {
+					rows = spanRows
+				} else {
+					rows = append(rows, spanRows...)
+				}
+				intents = append(intents, spanIntents...)
+				return int64(len(spanRows)), resumeSpan, nil
 			})
 		if err != nil {
 			return result.Result{}, err
 		}
+		if locked != nil {
+			rows = filterLockedRows(rows, locked)
+		}
+		if args.KeyLocking != roachpb.NON_LOCKING {
+			lockKeys = rowKeys(rows)
+		}
 		reply.NumKeys = int64(len(rows))
 		reply.Rows = rows
 	default:
@@ -76,8 +129,14 @@ func Scan(
 		reply.ResumeReason = roachpb.RESUME_KEY_LIMIT
 	}
 
+	res := result.FromIntents(intents, args)
+	if err := res.MergeAndDestroy(
+		result.FromAcquiredLocks(h.Txn, args.KeyLocking, lockKeys),
+	); err != nil {
+		return result.Result{}, err
+	}
 	if h.ReadConsistency == roachpb.READ_UNCOMMITTED {
 		reply.IntentRows, err = CollectIntentRows(ctx, batch, cArgs, intents)
 	}
-	return result.FromIntents(intents, args), err
+	return res, err
 }
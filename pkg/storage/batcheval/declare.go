@@ -23,9 +23,12 @@ import (
 func DefaultDeclareKeys(
 	desc *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
 ) {
-	if roachpb.IsReadOnly(req) {
+	if roachpb.IsReadOnly(req) && !roachpb.IsLocking(req) {
 		spans.Add(spanset.SpanReadOnly, req.Header().Span())
 	} else {
+		// Locking reads declare read-write latches so that they serialize
+		// with the writes and locking reads which may conflict with the locks
+		// they acquire.
 		spans.Add(spanset.SpanReadWrite, req.Header().Span())
 	}
 }
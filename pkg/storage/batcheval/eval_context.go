@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/locktable"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -54,6 +55,7 @@ type EvalContext interface {
 	DB() *client.DB
 	AbortSpan() *abortspan.AbortSpan
 	GetTxnWaitQueue() *txnwait.Queue
+	GetLockTable() *locktable.Table
 	GetLimiters() *Limiters
 
 	NodeID() roachpb.NodeID
@@ -0,0 +1,167 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// skipLockedFilter returns a function reporting whether a key is locked by
+// another transaction in a way that conflicts with a locking read of the
+// specified strength. It returns nil unless the request is a locking read
+// which skips locked keys instead of waiting on them.
+func skipLockedFilter(cArgs CommandArgs, str roachpb.KeyLockingStrength) func(roachpb.Key) bool {
+	h := cArgs.Header
+	if str == roachpb.NON_LOCKING || h.WaitPolicy != roachpb.LOCK_WAIT_SKIP {
+		return nil
+	}
+	var txnID uuid.UUID
+	if h.Txn != nil {
+		txnID = h.Txn.ID
+	}
+	lt := cArgs.EvalCtx.GetLockTable()
+	return func(key roachpb.Key) bool {
+		return len(lt.FindConflicts(roachpb.Span{Key: key}, txnID, str)) > 0
+	}
+}
+
+// scanSkippingIntents calls scan on the span, skipping the keys with intents
+// of other transactions if skipLocked is set. Such intents are locks as well,
+// so instead of failing the read with a WriteIntentError, a read that skips
+// locked keys scans around them: whenever scan returns a WriteIntentError, the
+// keys of its intents are excluded and the remainder of the span is scanned
+// again in the direction of the scan. scan is called on the sub-spans between
+// the skipped keys with the number of keys it may still return, and returns the
+// number of keys it read and its resume span. scan must only accumulate its
+// results when it succeeds. The resume span of the whole scan is returned.
+func scanSkippingIntents(
+	span roachpb.Span,
+	maxKeys int64,
+	reverse bool,
+	skipLocked bool,
+	scan func(span roachpb.Span, maxKeys int64) (int64, *roachpb.Span, error),
+) (*roachpb.Span, error) {
+	if !skipLocked {
+		_, resumeSpan, err := scan(span, maxKeys)
+		return resumeSpan, err
+	}
+	// The keys to skip, which are sorted in the direction of the scan, and the
+	// remainder of the span that still needs to be scanned.
+	var skip []roachpb.Key
+	remaining := span
+	for {
+		// Scan up to the next key to skip.
+		sub := remaining
+		var next roachpb.Key
+		if len(skip) > 0 {
+			next = skip[0]
+			if reverse {
+				sub.Key = next.Next()
+			} else {
+				sub.EndKey = next
+			}
+		}
+		numKeys, resumeSpan, err := scan(sub, maxKeys)
+		if wiErr, ok := err.(*roachpb.WriteIntentError); ok && len(wiErr.Intents) > 0 {
+			for _, intent := range wiErr.Intents {
+				skip = append(skip, intent.Key)
+			}
+			sort.Slice(skip, func(i, j int) bool {
+				if reverse {
+					return skip[j].Compare(skip[i]) < 0
+				}
+				return skip[i].Compare(skip[j]) < 0
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		maxKeys -= numKeys
+		if resumeSpan != nil {
+			if reverse {
+				return &roachpb.Span{Key: span.Key, EndKey: resumeSpan.EndKey}, nil
+			}
+			return &roachpb.Span{Key: resumeSpan.Key, EndKey: span.EndKey}, nil
+		}
+		if next == nil {
+			return nil, nil
+		}
+		// Continue after the skipped key.
+		skip = skip[1:]
+		if reverse {
+			remaining.EndKey = next
+		} else {
+			remaining.Key = next.Next()
+		}
+	}
+}
+
+// filterLockedRows removes the rows for locked keys in place.
+func filterLockedRows(rows []roachpb.KeyValue, locked func(roachpb.Key) bool) []roachpb.KeyValue {
+	filtered := rows[:0]
+	for _, row := range rows {
+		if !locked(row.Key) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// filterLockedBatchResponse removes the key/value pairs for locked keys from
+// the result of an MVCCScanToBytes call. It returns the new data and the
+// number of key/value pairs it contains.
+func filterLockedBatchResponse(
+	data []byte, locked func(roachpb.Key) bool,
+) ([]byte, int64, error) {
+	filtered := make([]byte, 0, len(data))
+	var numKvs int64
+	for len(data) > 0 {
+		key, _, rest, err := engine.MVCCScanDecodeKeyValue(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !locked(key.Key) {
+			filtered = append(filtered, data[:len(data)-len(rest)]...)
+			numKvs++
+		}
+		data = rest
+	}
+	return filtered, numKvs, nil
+}
+
+// batchResponseKeys returns the keys in the result of an MVCCScanToBytes
+// call.
+func batchResponseKeys(data []byte) ([]roachpb.Key, error) {
+	var keys []roachpb.Key
+	for len(data) > 0 {
+		key, _, rest, err := engine.MVCCScanDecodeKeyValue(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.Key)
+		data = rest
+	}
+	return keys, nil
+}
+
+// rowKeys returns the keys of the rows.
+func rowKeys(rows []roachpb.KeyValue) []roachpb.Key {
+	keys := make([]roachpb.Key, len(rows))
+	for i := range rows {
+		keys[i] = rows[i].Key
+	}
+	return keys
+}
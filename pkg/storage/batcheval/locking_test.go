@@ -0,0 +1,273 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/locktable"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockingReads verifies that locking reads report the locks they acquire
+// and that reads which skip locked keys filter out the keys locked by other
+// transactions.
+func TestLockingReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	defer eng.Close()
+	desc := &roachpb.RangeDescriptor{RangeID: 1}
+
+	ts := hlc.Timestamp{WallTime: 1}
+	for _, k := range []string{"a", "b", "c", "d"} {
+		v := roachpb.MakeValueFromString(k)
+		require.NoError(t, engine.MVCCPut(ctx, eng, nil, roachpb.Key(k), ts, v, nil))
+	}
+
+	txn := roachpb.MakeTransaction("test", roachpb.Key("a"), 0, ts.Next(), 0)
+	other := roachpb.MakeTransaction("other", roachpb.Key("a"), 0, ts.Next(), 0)
+	lt := locktable.New()
+	lt.Acquire(roachpb.Key("b"), &other.TxnMeta, roachpb.EXCLUSIVE)
+	lt.Acquire(roachpb.Key("c"), &other.TxnMeta, roachpb.SHARED)
+
+	keys := func(ks ...string) []roachpb.Key {
+		var res []roachpb.Key
+		for _, k := range ks {
+			res = append(res, roachpb.Key(k))
+		}
+		return res
+	}
+
+	testCases := []struct {
+		str        roachpb.KeyLockingStrength
+		waitPolicy roachpb.KeyLockingWaitPolicy
+		exp        []roachpb.Key
+	}{
+		{roachpb.NON_LOCKING, roachpb.LOCK_WAIT_SKIP, keys("a", "b", "c", "d")},
+		{roachpb.SHARED, roachpb.LOCK_WAIT_BLOCK, keys("a", "b", "c", "d")},
+		{roachpb.SHARED, roachpb.LOCK_WAIT_SKIP, keys("a", "c", "d")},
+		{roachpb.EXCLUSIVE, roachpb.LOCK_WAIT_SKIP, keys("a", "d")},
+	}
+	for _, tc := range testCases {
+		for _, format := range []roachpb.ScanFormat{roachpb.KEY_VALUES, roachpb.BATCH_RESPONSE} {
+			for _, reverse := range []bool{false, true} {
+				name := fmt.Sprintf("%s/%s/%s/reverse=%t", tc.str, tc.waitPolicy, format, reverse)
+				t.Run(name, func(t *testing.T) {
+					var cArgs CommandArgs
+					cArgs.EvalCtx = &mockEvalCtx{desc: desc, lockTable: lt}
+					cArgs.Header = roachpb.Header{Txn: &txn, Timestamp: txn.Timestamp, WaitPolicy: tc.waitPolicy}
+					cArgs.MaxKeys = 10
+					span := roachpb.RequestHeader{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")}
+
+					var res result.Result
+					var err error
+					var rh roachpb.ResponseHeader
+					var rows []roachpb.KeyValue
+					var batchResponses [][]byte
+					if reverse {
+						cArgs.Args = &roachpb.ReverseScanRequest{RequestHeader: span, ScanFormat: format, KeyLocking: tc.str}
+						var resp roachpb.ReverseScanResponse
+						res, err = ReverseScan(ctx, eng, cArgs, &resp)
+						rh, rows, batchResponses = resp.ResponseHeader, resp.Rows, resp.BatchResponses
+					} else {
+						cArgs.Args = &roachpb.ScanRequest{RequestHeader: span, ScanFormat: format, KeyLocking: tc.str}
+						var resp roachpb.ScanResponse
+						res, err = Scan(ctx, eng, cArgs, &resp)
+						rh, rows, batchResponses = resp.ResponseHeader, resp.Rows, resp.BatchResponses
+					}
+					require.NoError(t, err)
+
+					var scanned []roachpb.Key
+					if format == roachpb.KEY_VALUES {
+						scanned = rowKeys(rows)
+					} else {
+						require.Len(t, batchResponses, 1)
+						scanned, err = batchResponseKeys(batchResponses[0])
+						require.NoError(t, err)
+					}
+					exp := append([]roachpb.Key(nil), tc.exp...)
+					if reverse {
+						for i, j := 0, len(exp)-1; i < j; i, j = i+1, j-1 {
+							exp[i], exp[j] = exp[j], exp[i]
+						}
+					}
+					require.Equal(t, exp, scanned)
+					require.Equal(t, int64(len(exp)), rh.NumKeys)
+
+					locks := res.Local.DetachAcquiredLocks()
+					if tc.str == roachpb.NON_LOCKING {
+						require.Empty(t, locks)
+						return
+					}
+					require.Len(t, locks, 1)
+					require.Equal(t, txn.ID, locks[0].Txn.ID)
+					require.Equal(t, tc.str, locks[0].Strength)
+					require.Equal(t, exp, locks[0].Keys)
+				})
+			}
+		}
+	}
+
+	// A locking Get which skips locked keys doesn't return or lock a key
+	// locked by another transaction.
+	for _, k := range []string{"a", "b"} {
+		var cArgs CommandArgs
+		cArgs.EvalCtx = &mockEvalCtx{desc: desc, lockTable: lt}
+		cArgs.Header = roachpb.Header{Txn: &txn, Timestamp: txn.Timestamp, WaitPolicy: roachpb.LOCK_WAIT_SKIP}
+		cArgs.Args = &roachpb.GetRequest{
+			RequestHeader: roachpb.RequestHeader{Key: roachpb.Key(k)}, KeyLocking: roachpb.EXCLUSIVE,
+		}
+		var resp roachpb.GetResponse
+		res, err := Get(ctx, eng, cArgs, &resp)
+		require.NoError(t, err)
+		locks := res.Local.DetachAcquiredLocks()
+		if k == "b" {
+			require.Nil(t, resp.Value)
+			require.Empty(t, locks)
+		} else {
+			require.NotNil(t, resp.Value)
+			require.Len(t, locks, 1)
+			require.Equal(t, keys(k), locks[0].Keys)
+		}
+	}
+}
+
+// TestLockingReadsSkipIntents verifies that reads which skip locked keys also
+// skip the keys with intents of other transactions, instead of failing with a
+// WriteIntentError.
+func TestLockingReadsSkipIntents(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	defer eng.Close()
+	desc := &roachpb.RangeDescriptor{RangeID: 1}
+
+	ts := hlc.Timestamp{WallTime: 1}
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		v := roachpb.MakeValueFromString(k)
+		require.NoError(t, engine.MVCCPut(ctx, eng, nil, roachpb.Key(k), ts, v, nil))
+	}
+
+	txn := roachpb.MakeTransaction("test", roachpb.Key("a"), 0, ts.Next(), 0)
+	other := roachpb.MakeTransaction("other", roachpb.Key("a"), 0, ts.Next(), 0)
+	for _, k := range []string{"b", "d"} {
+		v := roachpb.MakeValueFromString("other")
+		require.NoError(t, engine.MVCCPut(ctx, eng, nil, roachpb.Key(k), other.Timestamp, v, &other))
+	}
+	lt := locktable.New()
+
+	keys := func(ks ...string) []roachpb.Key {
+		var res []roachpb.Key
+		for _, k := range ks {
+			res = append(res, roachpb.Key(k))
+		}
+		return res
+	}
+
+	testCases := []struct {
+		waitPolicy roachpb.KeyLockingWaitPolicy
+		reverse    bool
+		maxKeys    int64
+		exp        []roachpb.Key
+		expResume  bool
+	}{
+		{roachpb.LOCK_WAIT_SKIP, false, 10, keys("a", "c", "e"), false},
+		{roachpb.LOCK_WAIT_SKIP, true, 10, keys("e", "c", "a"), false},
+		{roachpb.LOCK_WAIT_SKIP, false, 2, keys("a", "c"), true},
+		{roachpb.LOCK_WAIT_SKIP, true, 2, keys("e", "c"), true},
+		{roachpb.LOCK_WAIT_BLOCK, false, 10, nil, false},
+	}
+	for _, tc := range testCases {
+		for _, format := range []roachpb.ScanFormat{roachpb.KEY_VALUES, roachpb.BATCH_RESPONSE} {
+			name := fmt.Sprintf("%s/%s/reverse=%t/max=%d", tc.waitPolicy, format, tc.reverse, tc.maxKeys)
+			t.Run(name, func(t *testing.T) {
+				var cArgs CommandArgs
+				cArgs.EvalCtx = &mockEvalCtx{desc: desc, lockTable: lt}
+				cArgs.Header = roachpb.Header{Txn: &txn, Timestamp: txn.Timestamp, WaitPolicy: tc.waitPolicy}
+				cArgs.MaxKeys = tc.maxKeys
+				span := roachpb.RequestHeader{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")}
+
+				var err error
+				var rh roachpb.ResponseHeader
+				var rows []roachpb.KeyValue
+				var batchResponses [][]byte
+				if tc.reverse {
+					cArgs.Args = &roachpb.ReverseScanRequest{
+						RequestHeader: span, ScanFormat: format, KeyLocking: roachpb.EXCLUSIVE,
+					}
+					var resp roachpb.ReverseScanResponse
+					_, err = ReverseScan(ctx, eng, cArgs, &resp)
+					rh, rows, batchResponses = resp.ResponseHeader, resp.Rows, resp.BatchResponses
+				} else {
+					cArgs.Args = &roachpb.ScanRequest{
+						RequestHeader: span, ScanFormat: format, KeyLocking: roachpb.EXCLUSIVE,
+					}
+					var resp roachpb.ScanResponse
+					_, err = Scan(ctx, eng, cArgs, &resp)
+					rh, rows, batchResponses = resp.ResponseHeader, resp.Rows, resp.BatchResponses
+				}
+				if tc.waitPolicy == roachpb.LOCK_WAIT_BLOCK {
+					require.IsType(t, &roachpb.WriteIntentError{}, err)
+					return
+				}
+				require.NoError(t, err)
+
+				var scanned []roachpb.Key
+				if format == roachpb.KEY_VALUES {
+					scanned = rowKeys(rows)
+				} else {
+					require.Len(t, batchResponses, 1)
+					scanned, err = batchResponseKeys(batchResponses[0])
+					require.NoError(t, err)
+				}
+				require.Equal(t, tc.exp, scanned)
+				require.Equal(t, int64(len(tc.exp)), rh.NumKeys)
+				if !tc.expResume {
+					require.Nil(t, rh.ResumeSpan)
+					return
+				}
+				// The resume span covers the rest of the requested span.
+				require.NotNil(t, rh.ResumeSpan)
+				if tc.reverse {
+					require.Equal(t, span.Key, rh.ResumeSpan.Key)
+					require.True(t, rh.ResumeSpan.EndKey.Compare(roachpb.Key("c")) <= 0)
+				} else {
+					require.True(t, rh.ResumeSpan.Key.Compare(roachpb.Key("c")) > 0)
+					require.Equal(t, span.EndKey, rh.ResumeSpan.EndKey)
+				}
+			})
+		}
+	}
+
+	// A locking Get which skips locked keys doesn't return a key with an
+	// intent of another transaction.
+	var cArgs CommandArgs
+	cArgs.EvalCtx = &mockEvalCtx{desc: desc, lockTable: lt}
+	cArgs.Header = roachpb.Header{Txn: &txn, Timestamp: txn.Timestamp, WaitPolicy: roachpb.LOCK_WAIT_SKIP}
+	cArgs.Args = &roachpb.GetRequest{
+		RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("b")}, KeyLocking: roachpb.EXCLUSIVE,
+	}
+	var resp roachpb.GetResponse
+	res, err := Get(ctx, eng, cArgs, &resp)
+	require.NoError(t, err)
+	require.Nil(t, resp.Value)
+	require.Empty(t, res.Local.DetachAcquiredLocks())
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// declareKeysReadRangeTombstones is like DefaultDeclareKeys, but additionally
+// declares a read of the MVCC range tombstones of the range, which reads of
+// non-local keys take into account.
+func declareKeysReadRangeTombstones(
+	desc *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
+) {
+	DefaultDeclareKeys(desc, header, req, spans)
+	if !keys.IsLocal(req.Header().Key) {
+		prefix := keys.RangeTombstonePrefix(header.RangeID)
+		spans.Add(spanset.SpanReadOnly, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+}
+
+// rangeTombstonesForSpan returns the MVCC range tombstones of the range that
+// overlap the span, to be passed to the MVCCGet and MVCCScan families of
+// functions. Local keys are never covered by range tombstones.
+func rangeTombstonesForSpan(
+	ctx context.Context, reader engine.Reader, cArgs CommandArgs, span roachpb.Span,
+) ([]enginepb.MVCCRangeTombstone, error) {
+	if keys.IsLocal(span.Key) {
+		return nil, nil
+	}
+	endKey := span.EndKey
+	if len(endKey) == 0 {
+		endKey = span.Key.Next()
+	}
+	return engine.MVCCScanRangeTombstones(ctx, reader, cArgs.EvalCtx.GetRangeID(), span.Key, endKey)
+}
+
+// rangeTombstoneWrite describes the effect of the MVCC range tombstones of
+// the range on a write, which the engine's write functions are unaware of.
+// See checkRangeTombstonesForWrite.
+type rangeTombstoneWrite struct {
+	// header is the header to perform the write with.
+	header roachpb.Header
+	// tooOld is set if the write was forwarded past a range tombstone.
+	tooOld *roachpb.WriteTooOldError
+	// tombstones are the range tombstones overlapping the written span.
+	tombstones []enginepb.MVCCRangeTombstone
+}
+
+// checkRangeTombstonesForWrite checks a write to the span against the MVCC
+// range tombstones of the range. Like a committed value, a range tombstone at
+// or above the read timestamp of the write forces the write above it: the
+// returned header has its timestamp, or the provisional commit timestamp of
+// its transaction, forwarded past the tombstone, and finish returns a
+// WriteTooOldError once the write has been performed. This mirrors the
+// handling of committed values in mvccPutInternal.
+func checkRangeTombstonesForWrite(
+	ctx context.Context, reader engine.Reader, cArgs CommandArgs, span roachpb.Span,
+) (rangeTombstoneWrite, error) {
+	w := rangeTombstoneWrite{header: cArgs.Header}
+	tombstones, err := rangeTombstonesForSpan(ctx, reader, cArgs, span)
+	if err != nil || len(tombstones) == 0 {
+		return w, err
+	}
+	w.tombstones = tombstones
+	// The engine accounts for a write over a version deleted by a range
+	// tombstone as if the version were still live.
+	if cArgs.Stats != nil {
+		cArgs.Stats.ContainsEstimates = true
+	}
+
+	readTimestamp := w.header.Timestamp
+	var tooOldTS hlc.Timestamp
+	for _, t := range tombstones {
+		if !t.Timestamp.Less(readTimestamp) {
+			tooOldTS.Forward(t.Timestamp)
+		}
+	}
+	if tooOldTS == (hlc.Timestamp{}) {
+		return w, nil
+	}
+	writeTimestamp := tooOldTS.Next()
+	if txn := w.header.Txn; txn != nil {
+		// The transaction keeps reading at its original timestamp, but writes
+		// its intent above the range tombstone.
+		txn = txn.Clone()
+		txn.Timestamp.Forward(writeTimestamp)
+		writeTimestamp = txn.Timestamp
+		w.header.Txn = txn
+	} else {
+		// Outside of a transaction, the read timestamp advances as well.
+		w.header.Timestamp = writeTimestamp
+	}
+	w.tooOld = &roachpb.WriteTooOldError{
+		Timestamp: readTimestamp, ActualTimestamp: writeTimestamp,
+	}
+	return w, nil
+}
+
+// deletedValue returns a deletion tombstone at the timestamp of the deletion
+// if the version of the key visible to the write was deleted by a range
+// tombstone, and nil otherwise. Read-modify-write operations must treat such
+// a key as deleted, which the engine, which only knows about the deleted
+// version, doesn't do.
+func (w *rangeTombstoneWrite) deletedValue(
+	ctx context.Context, reader engine.Reader, key roachpb.Key,
+) (*roachpb.Value, error) {
+	if len(w.tombstones) == 0 {
+		return nil, nil
+	}
+	val, _, err := engine.MVCCGet(ctx, reader, key, w.header.Timestamp, engine.MVCCGetOptions{
+		Txn: w.header.Txn,
+	})
+	if err != nil {
+		if _, ok := err.(*roachpb.WriteIntentError); ok {
+			// Leave the conflicting intent to the write.
+			return nil, nil
+		}
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+	ts, ok := engine.CoveringRangeTombstone(w.tombstones, key, val.Timestamp, w.header.Timestamp)
+	if !ok {
+		return nil, nil
+	}
+	return &roachpb.Value{Timestamp: ts}, nil
+}
+
+// finish returns the error to return from a write performed with the header
+// of w, given the error returned by the write itself.
+func (w *rangeTombstoneWrite) finish(err error) error {
+	if w.tooOld == nil {
+		return err
+	}
+	if err == nil {
+		return w.tooOld
+	}
+	if tErr, ok := err.(*roachpb.WriteTooOldError); ok {
+		tErr.ActualTimestamp.Forward(w.tooOld.ActualTimestamp)
+	}
+	return err
+}
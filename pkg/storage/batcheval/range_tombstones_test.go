@@ -0,0 +1,221 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// TestRangeTombstonesRefresh verifies that refreshes consider the deletion of
+// values by range tombstones as updates.
+func TestRangeTombstonesRefresh(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	db := engine.NewInMem(roachpb.Attributes{}, 10<<20)
+	defer db.Close()
+	evalCtx := &mockEvalCtx{desc: &roachpb.RangeDescriptor{RangeID: 1}}
+
+	// A value at ts10 is deleted by a range tombstone at ts30.
+	k := roachpb.Key("b")
+	if err := engine.MVCCPut(
+		ctx, db, nil, k, hlc.Timestamp{WallTime: 10}, roachpb.MakeValueFromString("v"), nil,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.MVCCDeleteRangeUsingTombstone(
+		ctx, db, nil, evalCtx.GetRangeID(), roachpb.Key("a"), roachpb.Key("c"), hlc.Timestamp{WallTime: 30}, nil,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		orig, ts int64
+		expErr   string
+	}{
+		// The deletion happened between the original and the refreshed
+		// timestamp of the transaction.
+		{orig: 20, ts: 40, expErr: "encountered recently written key"},
+		// The deletion happened after the refreshed timestamp.
+		{orig: 20, ts: 25},
+		// The deletion happened before the original timestamp.
+		{orig: 40, ts: 50},
+	} {
+		h := roachpb.Header{Txn: &roachpb.Transaction{
+			TxnMeta:       enginepb.TxnMeta{ID: uuid.MakeV4(), Timestamp: hlc.Timestamp{WallTime: tc.ts}},
+			OrigTimestamp: hlc.Timestamp{WallTime: tc.orig},
+		}}
+		_, err := Refresh(ctx, db, CommandArgs{
+			EvalCtx: evalCtx,
+			Header:  h,
+			Args:    &roachpb.RefreshRequest{RequestHeader: roachpb.RequestHeader{Key: k}},
+		}, &roachpb.RefreshResponse{})
+		if !testutils.IsError(err, tc.expErr) {
+			t.Errorf("refresh @[%d-%d]: expected error %q, got %v", tc.orig, tc.ts, tc.expErr, err)
+		}
+		_, err = RefreshRange(ctx, db, CommandArgs{
+			EvalCtx: evalCtx,
+			Header:  h,
+			Args: &roachpb.RefreshRangeRequest{
+				RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")},
+			},
+		}, &roachpb.RefreshRangeResponse{})
+		if !testutils.IsError(err, tc.expErr) {
+			t.Errorf("refresh range @[%d-%d]: expected error %q, got %v", tc.orig, tc.ts, tc.expErr, err)
+		}
+	}
+}
+
+// TestRangeTombstonesWrite verifies that writes treat values deleted by range
+// tombstones as deleted and are pushed above range tombstones.
+func TestRangeTombstonesWrite(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	k := roachpb.Key("b")
+	v1 := roachpb.MakeValueFromString("v1")
+	v2 := roachpb.MakeValueFromString("v2")
+	evalCtx := &mockEvalCtx{desc: &roachpb.RangeDescriptor{RangeID: 1}}
+	// setup returns an engine in which a value at ts1 is deleted by a range
+	// tombstone at ts3.
+	setup := func(t *testing.T, val roachpb.Value) engine.Engine {
+		db := engine.NewInMem(roachpb.Attributes{}, 10<<20)
+		if err := engine.MVCCPut(ctx, db, nil, k, hlc.Timestamp{WallTime: 1}, val, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.MVCCDeleteRangeUsingTombstone(
+			ctx, db, nil, evalCtx.GetRangeID(), roachpb.Key("a"), roachpb.Key("c"), hlc.Timestamp{WallTime: 3}, nil,
+		); err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+	cArgs := func(ts hlc.Timestamp, req roachpb.Request) CommandArgs {
+		return CommandArgs{EvalCtx: evalCtx, Header: roachpb.Header{Timestamp: ts}, Args: req}
+	}
+	get := func(t *testing.T, db engine.Reader, ts hlc.Timestamp) *roachpb.Value {
+		t.Helper()
+		val, _, err := engine.MVCCGet(ctx, db, k, ts, engine.MVCCGetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val
+	}
+	reqHeader := roachpb.RequestHeader{Key: k}
+
+	t.Run("cput", func(t *testing.T) {
+		db := setup(t, v1)
+		defer db.Close()
+		_, err := ConditionalPut(ctx, db, cArgs(hlc.Timestamp{WallTime: 4}, &roachpb.ConditionalPutRequest{
+			RequestHeader: reqHeader, Value: v2, ExpValue: &v1,
+		}), &roachpb.ConditionalPutResponse{})
+		if _, ok := err.(*roachpb.ConditionFailedError); !ok {
+			t.Fatalf("expected ConditionFailedError, got %v", err)
+		}
+		if _, err := ConditionalPut(ctx, db, cArgs(hlc.Timestamp{WallTime: 4}, &roachpb.ConditionalPutRequest{
+			RequestHeader: reqHeader, Value: v2,
+		}), &roachpb.ConditionalPutResponse{}); err != nil {
+			t.Fatal(err)
+		}
+		if val := get(t, db, hlc.Timestamp{WallTime: 5}); val == nil || !val.EqualData(v2) {
+			t.Fatalf("expected %v, got %v", v2, val)
+		}
+	})
+
+	t.Run("initput", func(t *testing.T) {
+		db := setup(t, v1)
+		defer db.Close()
+		_, err := InitPut(ctx, db, cArgs(hlc.Timestamp{WallTime: 4}, &roachpb.InitPutRequest{
+			RequestHeader: reqHeader, Value: v2, FailOnTombstones: true,
+		}), &roachpb.InitPutResponse{})
+		if _, ok := err.(*roachpb.ConditionFailedError); !ok {
+			t.Fatalf("expected ConditionFailedError, got %v", err)
+		}
+		if _, err := InitPut(ctx, db, cArgs(hlc.Timestamp{WallTime: 4}, &roachpb.InitPutRequest{
+			RequestHeader: reqHeader, Value: v2,
+		}), &roachpb.InitPutResponse{}); err != nil {
+			t.Fatal(err)
+		}
+		if val := get(t, db, hlc.Timestamp{WallTime: 5}); val == nil || !val.EqualData(v2) {
+			t.Fatalf("expected %v, got %v", v2, val)
+		}
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		var five roachpb.Value
+		five.SetInt(5)
+		db := setup(t, five)
+		defer db.Close()
+		var resp roachpb.IncrementResponse
+		if _, err := Increment(ctx, db, cArgs(hlc.Timestamp{WallTime: 4}, &roachpb.IncrementRequest{
+			RequestHeader: reqHeader, Increment: 1,
+		}), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.NewValue != 1 {
+			t.Fatalf("expected 1, got %d", resp.NewValue)
+		}
+	})
+
+	t.Run("put-below-tombstone", func(t *testing.T) {
+		db := setup(t, v1)
+		defer db.Close()
+		_, err := Put(ctx, db, cArgs(hlc.Timestamp{WallTime: 2}, &roachpb.PutRequest{
+			RequestHeader: reqHeader, Value: v2,
+		}), &roachpb.PutResponse{})
+		wtoErr, ok := err.(*roachpb.WriteTooOldError)
+		if !ok {
+			t.Fatalf("expected WriteTooOldError, got %v", err)
+		}
+		if exp := hlc.Timestamp{WallTime: 3, Logical: 1}; wtoErr.ActualTimestamp != exp {
+			t.Fatalf("expected actual timestamp %s, got %s", exp, wtoErr.ActualTimestamp)
+		}
+		// The value was written above the range tombstone.
+		if val := get(t, db, hlc.Timestamp{WallTime: 4}); val == nil || !val.EqualData(v2) {
+			t.Fatalf("expected %v, got %v", v2, val)
+		}
+	})
+
+	t.Run("txn-put-below-tombstone", func(t *testing.T) {
+		db := setup(t, v1)
+		defer db.Close()
+		txn := roachpb.MakeTransaction("test", k, 0, hlc.Timestamp{WallTime: 2}, 0)
+		_, err := Put(ctx, db, CommandArgs{
+			EvalCtx: evalCtx,
+			Header:  roachpb.Header{Timestamp: txn.OrigTimestamp, Txn: &txn},
+			Args:    &roachpb.PutRequest{RequestHeader: reqHeader, Value: v2},
+		}, &roachpb.PutResponse{})
+		wtoErr, ok := err.(*roachpb.WriteTooOldError)
+		if !ok {
+			t.Fatalf("expected WriteTooOldError, got %v", err)
+		}
+		if exp := hlc.Timestamp{WallTime: 3, Logical: 1}; wtoErr.ActualTimestamp != exp {
+			t.Fatalf("expected actual timestamp %s, got %s", exp, wtoErr.ActualTimestamp)
+		}
+		// The intent was written above the range tombstone.
+		_, intent, err := engine.MVCCGet(ctx, db, k, hlc.Timestamp{WallTime: 4}, engine.MVCCGetOptions{Inconsistent: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if intent == nil || intent.Txn.ID != txn.ID {
+			t.Fatalf("expected intent of %s, got %v", txn.ID, intent)
+		}
+	})
+}
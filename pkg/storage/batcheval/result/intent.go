@@ -10,7 +10,10 @@
 
 package result
 
-import "github.com/cockroachdb/cockroach/pkg/roachpb"
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+)
 
 // IntentsWithArg contains a request and the intents it discovered.
 type IntentsWithArg struct {
@@ -48,3 +51,42 @@ func FromEndTxn(txn *roachpb.Transaction, alwaysReturn, poison bool) Result {
 	pd.Local.EndTxns = &[]EndTxnIntents{{Txn: *txn, Always: alwaysReturn, Poison: poison}}
 	return pd
 }
+
+// AcquiredLocks contains the keys on which a locking read acquired
+// unreplicated locks of the given strength on behalf of a transaction.
+type AcquiredLocks struct {
+	Txn      enginepb.TxnMeta
+	Strength roachpb.KeyLockingStrength
+	Keys     []roachpb.Key
+}
+
+// FromAcquiredLocks creates a Result communicating that the transaction
+// acquired unreplicated locks on the keys.
+func FromAcquiredLocks(
+	txn *roachpb.Transaction, str roachpb.KeyLockingStrength, keys []roachpb.Key,
+) Result {
+	var pd Result
+	if txn == nil || str == roachpb.NON_LOCKING || len(keys) == 0 {
+		return pd
+	}
+	pd.Local.AcquiredLocks = &[]AcquiredLocks{{Txn: txn.TxnMeta, Strength: str, Keys: keys}}
+	return pd
+}
+
+// FromResolvedLocks creates a Result communicating that the unreplicated
+// locks held by the intents' finalized transaction in the intents' spans
+// should be released.
+func FromResolvedLocks(intents ...roachpb.Intent) Result {
+	var pd Result
+	var resolved []roachpb.Intent
+	for _, intent := range intents {
+		if intent.Status.IsFinalized() {
+			resolved = append(resolved, intent)
+		}
+	}
+	if len(resolved) == 0 {
+		return pd
+	}
+	pd.Local.ResolvedLocks = &resolved
+	return pd
+}
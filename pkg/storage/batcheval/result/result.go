@@ -64,13 +64,22 @@ type LocalResult struct {
 	// EndTransaction or PushTxn. This is a pointer to allow the zero
 	// (and as an unwelcome side effect, all) values to be compared.
 	UpdatedTxns *[]*roachpb.Transaction
+
+	// AcquiredLocks stores the unreplicated locks acquired by locking reads.
+	// They are added to the leaseholder's lock table. ResolvedLocks stores the
+	// spans in which the unreplicated locks of finalized transactions should
+	// be released, after calls to ResolveIntent(Range) or EndTransaction.
+	// These are pointers to allow the zero (and as an unwelcome side effect,
+	// all) values to be compared.
+	AcquiredLocks *[]AcquiredLocks
+	ResolvedLocks *[]roachpb.Intent
 }
 
 func (lResult *LocalResult) String() string {
 	if lResult == nil {
 		return "LocalResult: nil"
 	}
-	var numIntents, numEndTxns, numUpdatedTxns int
+	var numIntents, numEndTxns, numUpdatedTxns, numAcquiredLocks, numResolvedLocks int
 	if lResult.Intents != nil {
 		numIntents = len(*lResult.Intents)
 	}
@@ -80,10 +89,18 @@ func (lResult *LocalResult) String() string {
 	if lResult.UpdatedTxns != nil {
 		numUpdatedTxns = len(*lResult.UpdatedTxns)
 	}
+	if lResult.AcquiredLocks != nil {
+		numAcquiredLocks = len(*lResult.AcquiredLocks)
+	}
+	if lResult.ResolvedLocks != nil {
+		numResolvedLocks = len(*lResult.ResolvedLocks)
+	}
 	return fmt.Sprintf("LocalResult (reply: %v, #intents: %d, #endTxns: %d #updated txns: %d, "+
+		"#acquired locks: %d, #resolved locks: %d, "+
 		"GossipFirstRange:%t MaybeGossipSystemConfig:%t MaybeAddToSplitQueue:%t "+
 		"MaybeGossipNodeLiveness:%s MaybeWatchForMerge:%t",
-		lResult.Reply, numIntents, numEndTxns, numUpdatedTxns, lResult.GossipFirstRange,
+		lResult.Reply, numIntents, numEndTxns, numUpdatedTxns,
+		numAcquiredLocks, numResolvedLocks, lResult.GossipFirstRange,
 		lResult.MaybeGossipSystemConfig, lResult.MaybeAddToSplitQueue,
 		lResult.MaybeGossipNodeLiveness, lResult.MaybeWatchForMerge)
 }
@@ -114,6 +131,34 @@ func (lResult *LocalResult) DetachIntents() []IntentsWithArg {
 	return r
 }
 
+// DetachAcquiredLocks returns (and removes) the unreplicated locks acquired
+// by locking reads from the local result.
+func (lResult *LocalResult) DetachAcquiredLocks() []AcquiredLocks {
+	if lResult == nil {
+		return nil
+	}
+	var r []AcquiredLocks
+	if lResult.AcquiredLocks != nil {
+		r = *lResult.AcquiredLocks
+	}
+	lResult.AcquiredLocks = nil
+	return r
+}
+
+// DetachResolvedLocks returns (and removes) the spans in which unreplicated
+// locks should be released from the local result.
+func (lResult *LocalResult) DetachResolvedLocks() []roachpb.Intent {
+	if lResult == nil {
+		return nil
+	}
+	var r []roachpb.Intent
+	if lResult.ResolvedLocks != nil {
+		r = *lResult.ResolvedLocks
+	}
+	lResult.ResolvedLocks = nil
+	return r
+}
+
 // DetachEndTxns returns (and removes) the EndTxnIntent objects from
 // the local result. If alwaysOnly is true, the slice is filtered to
 // include only those which have specified returnAlways=true, meaning
@@ -342,6 +387,24 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Local.UpdatedTxns = nil
 
+	if q.Local.AcquiredLocks != nil {
+		if p.Local.AcquiredLocks == nil {
+			p.Local.AcquiredLocks = q.Local.AcquiredLocks
+		} else {
+			*p.Local.AcquiredLocks = append(*p.Local.AcquiredLocks, *q.Local.AcquiredLocks...)
+		}
+	}
+	q.Local.AcquiredLocks = nil
+
+	if q.Local.ResolvedLocks != nil {
+		if p.Local.ResolvedLocks == nil {
+			p.Local.ResolvedLocks = q.Local.ResolvedLocks
+		} else {
+			*p.Local.ResolvedLocks = append(*p.Local.ResolvedLocks, *q.Local.ResolvedLocks...)
+		}
+	}
+	q.Local.ResolvedLocks = nil
+
 	if q.LogicalOpLog != nil {
 		if p.LogicalOpLog == nil {
 			p.LogicalOpLog = q.LogicalOpLog
@@ -368,7 +368,52 @@ func TestClosedTimestampCantServeForNonTransactionalReadRequest(t *testing.T) {
 	verifyNotLeaseHolderErrors(t, baQueryTxn, repls, 2)
 }
 
-func verifyNotLeaseHolderErrors(
+// TestClosedTimestampCanServeBoundedStalenessRead verifies that all replicas
+// serve a bounded staleness read once their closed timestamp reaches the read's
+// minimum timestamp bound, and that only the leaseholder serves it before.
+func TestClosedTimestampCanServeBoundedStalenessRead(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if util.RaceEnabled {
+		// Limiting how long transactions can run does not work
+		// well with race unless we're extremely lenient, which
+		// drives up the test duration.
+		t.Skip("skipping under race")
+	}
+
+	ctx := context.Background()
+	tc, db0, desc, repls := setupTestClusterForClosedTimestampTesting(ctx, t, testingTargetDuration)
+	defer tc.Stopper().Stop(ctx)
+
+	if _, err := db0.Exec(`INSERT INTO cttest.kv VALUES(1, $1)`, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The batch timestamp is assigned by Store.Send, which we bypass, and is
+	// then replaced by the timestamp chosen by the replica.
+	minBound := tc.Server(0).Clock().Now()
+	baRead := makeReadBatchRequestForDesc(desc, tc.Server(0).Clock().Now())
+	baRead.BoundedStaleness = &roachpb.BoundedStalenessHeader{MinTimestampBound: minBound}
+
+	// The followers can't have closed the minimum timestamp bound yet, so only
+	// the leaseholder serves the read.
+	verifyNotLeaseHolderErrors(t, baRead, repls, 2)
+
+	testutils.SucceedsSoon(t, func() error {
+		return verifyCanReadFromAllRepls(ctx, t, baRead, repls, respFuncs(
+			expectRows(1),
+			func(resp *roachpb.BatchResponse, _ *roachpb.Error) (bool, error) {
+				if resp.Timestamp.Less(minBound) {
+					return false, errors.Errorf(
+						"read served at %s, below min timestamp bound %s", resp.Timestamp, minBound)
+				}
+				return false, nil
+			},
+		))
+	})
+}
+
+func verifyNotLeaseHolderErrors(func verifyNotLeaseHolderErrors(
 	t *testing.T, ba roachpb.BatchRequest, repls []*storage.Replica, expectedNLEs int,
 ) {
 	g, ctx := errgroup.WithContext(context.Background())
@@ -166,15 +166,30 @@ func (meta *MVCCMetadata) AddToIntentHistory(seq TxnSeq, val []byte) {
 }
 
 // GetPrevIntentSeq goes through the intent history and finds the previous
-// intent's sequence number given the current sequence.
-func (meta *MVCCMetadata) GetPrevIntentSeq(seq TxnSeq) (TxnSeq, bool) {
-	index := sort.Search(len(meta.IntentHistory), func(i int) bool {
-		return meta.IntentHistory[i].Sequence >= seq
-	})
-	if index > 0 && index < len(meta.IntentHistory) {
-		return meta.IntentHistory[index-1].Sequence, true
+// intent given the current sequence. Intents written at sequence numbers
+// that fall within one of the ignored ranges are skipped.
+func (meta *MVCCMetadata) GetPrevIntentSeq(
+	seq TxnSeq, ignored []IgnoredSeqNumRange,
+) (MVCCMetadata_SequencedIntent, bool) {
+	end := len(meta.IntentHistory)
+	for {
+		index := sort.Search(end, func(i int) bool {
+			return meta.IntentHistory[i].Sequence >= seq
+		})
+		if index == 0 {
+			// No intent exists such that the sequence is less than the given
+			// sequence, so there is no previous value in the intent history.
+			return MVCCMetadata_SequencedIntent{}, false
+		}
+		candidate := index - 1
+		if TxnSeqIsIgnored(meta.IntentHistory[candidate].Sequence, ignored) {
+			// This entry was part of an ignored range. Skip it and try the
+			// search again, using the current position as the new upper bound.
+			end = candidate
+			continue
+		}
+		return meta.IntentHistory[candidate], true
 	}
-	return 0, false
 }
 
 // GetIntentValue goes through the intent history and finds the value
@@ -188,3 +203,19 @@ func (meta *MVCCMetadata) GetIntentValue(seq TxnSeq) ([]byte, bool) {
 	}
 	return nil, false
 }
+
+// TxnSeqIsIgnored returns true iff the sequence number overlaps with any
+// range in the ignored list. The list is expected to be sorted and its
+// ranges non-overlapping, as maintained by
+// roachpb.Transaction.AddIgnoredSeqNumRange.
+func TxnSeqIsIgnored(seq TxnSeq, ignored []IgnoredSeqNumRange) bool {
+	// Search from the end since recently rolled back writes are the ones
+	// most likely to be encountered.
+	for i := len(ignored) - 1; i >= 0; i-- {
+		if seq < ignored[i].Start {
+			continue
+		}
+		return seq <= ignored[i].End
+	}
+	return false
+}
@@ -13,8 +13,10 @@ package engine
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -651,6 +653,9 @@ type MVCCGetOptions struct {
 	// TODO(nvanbenschoten): Remove all references to IgnoreSequence in 20.1.
 	IgnoreSequence bool
 	Txn            *roachpb.Transaction
+	// RangeTombstones are the MVCC range tombstones of the range that
+	// overlap the key. See the documentation for MVCCGet.
+	RangeTombstones []enginepb.MVCCRangeTombstone
 }
 
 // MVCCGet returns the most recent value for the specified key whose timestamp
@@ -668,6 +673,11 @@ type MVCCGetOptions struct {
 //
 // Note that transactional gets must be consistent. Put another way, only
 // non-transactional gets may be inconsistent.
+//
+// If range tombstones are provided, a value deleted by one of them as of the
+// supplied timestamp is treated as if it had been shadowed by a deletion
+// tombstone at the range tombstone's timestamp. See
+// MVCCDeleteRangeUsingTombstone.
 func MVCCGet(
 	ctx context.Context, eng Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions,
 ) (*roachpb.Value, *roachpb.Intent, error) {
@@ -678,6 +688,19 @@ func MVCCGet(
 	iter := eng.NewIterator(IterOptions{Prefix: true})
 	value, intent, err := iter.MVCCGet(key, timestamp, opts)
 	iter.Close()
+	if err == nil && value != nil && len(opts.RangeTombstones) > 0 && len(value.RawBytes) > 0 {
+		deletedTS, deleted, rtErr := checkRangeTombstones(
+			opts.RangeTombstones, key, value.Timestamp, timestamp, opts.Txn)
+		if rtErr != nil {
+			return nil, nil, rtErr
+		}
+		if deleted {
+			value = nil
+			if opts.Tombstones {
+				value = &roachpb.Value{Timestamp: deletedTS}
+			}
+		}
+	}
 	return value, intent, err
 }
 
@@ -1189,13 +1212,12 @@ func replayTransactionalWrite(
 
 	// If the valueFn is specified, we must apply it to the would-be value at the key.
 	if valueFn != nil {
-		prevSeq, prevValueWritten := meta.GetPrevIntentSeq(txn.Sequence)
+		prevIntent, prevValueWritten := meta.GetPrevIntentSeq(txn.Sequence, txn.IgnoredSeqNums)
 		if prevValueWritten {
 			// If the previous value was found in the IntentHistory,
 			// simply apply the value function to the historic value
 			// to get the would-be value.
-			prevVal, _ := meta.GetIntentValue(prevSeq)
-			value, err = valueFn(&roachpb.Value{RawBytes: prevVal})
+			value, err = valueFn(&roachpb.Value{RawBytes: prevIntent.Value})
 			if err != nil {
 				return err
 			}
@@ -1398,7 +1420,32 @@ func mvccPutInternal(
 			// version.  For example, a conditional put within same
 			// transaction should read previous write.
 			if valueFn != nil {
-				value, err = valueFn(existingVal)
+				visibleVal := existingVal
+				// If the existing intent's value was written at a sequence
+				// number that has since been rolled back to a savepoint, the
+				// value visible to this write is the latest one that was not
+				// rolled back. That's either an earlier entry in the intent
+				// history or, if there is none, the committed value beneath the
+				// intent. The rolled back value is still recorded in the intent
+				// history below; reads skip over it.
+				if txn.Epoch == meta.Txn.Epoch &&
+					enginepb.TxnSeqIsIgnored(prevIntentSequence, txn.IgnoredSeqNums) {
+					if prevIntent, ok := meta.GetPrevIntentSeq(prevIntentSequence, txn.IgnoredSeqNums); ok {
+						visibleVal = &roachpb.Value{RawBytes: prevIntent.Value}
+					} else {
+						// Perform an inconsistent read to ignore our own intent
+						// and see the last committed value instead.
+						committedBuf := newGetBuffer()
+						defer committedBuf.release()
+						committedBuf.meta = buf.meta
+						visibleVal, _, _, err = mvccGetInternal(
+							ctx, iter, metaKey, readTimestamp, false /* consistent */, safeValue, nil /* txn */, committedBuf)
+						if err != nil {
+							return err
+						}
+					}
+				}
+				value, err = valueFn(visibleVal)
 				if err != nil {
 					return err
 				}
@@ -1928,6 +1975,11 @@ func mvccScanToKvs(
 		kvs[i].Value.RawBytes = rawBytes
 		kvs[i].Value.Timestamp = k.Timestamp
 	}
+	if len(opts.RangeTombstones) > 0 {
+		if kvs, err = filterRangeTombstonesFromKvs(kvs, timestamp, opts); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 	return kvs, resumeSpan, intents, err
 }
 
@@ -1978,6 +2030,9 @@ type MVCCScanOptions struct {
 	IgnoreSequence bool
 	Reverse        bool
 	Txn            *roachpb.Transaction
+	// RangeTombstones are the MVCC range tombstones of the range that
+	// overlap the scanned span. See the documentation for MVCCScan.
+	RangeTombstones []enginepb.MVCCRangeTombstone
 }
 
 // MVCCScan scans the key range [key, endKey) in the provided engine up to some
@@ -2011,6 +2066,12 @@ type MVCCScanOptions struct {
 //
 // Note that transactional scans must be consistent. Put another way, only
 // non-transactional scans may be inconsistent.
+//
+// If range tombstones are provided, values deleted by one of them as of the
+// supplied timestamp are treated as if they had been shadowed by a deletion
+// tombstone at the range tombstone's timestamp. Such values still count
+// towards max, so a scan may return fewer than max results along with a
+// resume span.
 func MVCCScan(
 	ctx context.Context,
 	engine Reader,
@@ -2035,7 +2096,13 @@ func MVCCScanToBytes(
 ) ([]byte, int64, *roachpb.Span, []roachpb.Intent, error) {
 	iter := engine.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
-	return iter.MVCCScan(key, endKey, max, timestamp, opts)
+	kvData, numKVs, resumeSpan, intents, err := iter.MVCCScan(key, endKey, max, timestamp, opts)
+	if err == nil && len(opts.RangeTombstones) > 0 {
+		if kvData, numKVs, err = filterRangeTombstonesFromBytes(kvData, timestamp, opts); err != nil {
+			return nil, 0, nil, nil, err
+		}
+	}
+	return kvData, numKVs, resumeSpan, intents, err
 }
 
 // MVCCIterate iterates over the key range [start,end). At each step of the
@@ -2319,6 +2386,24 @@ func mvccResolveWriteIntent(
 		return false, nil
 	}
 
+	// If the transaction rolled back the write that provides the intent's
+	// current value, restore the latest write from the intent history that
+	// was not rolled back before committing or pushing the intent. If all of
+	// the transaction's writes to this key were rolled back, the intent is
+	// removed below just as if it had been aborted.
+	if (commit || pushed) && epochsMatch && len(intent.IgnoredSeqNums) > 0 {
+		removeIntent, newMetaKeySize, newMetaValSize, err := mvccMaybeRewriteIntentHistory(
+			engine, ms, metaKey, intent.IgnoredSeqNums, buf, origMetaKeySize, origMetaValSize)
+		if err != nil {
+			return false, err
+		}
+		if removeIntent {
+			commit, pushed = false, false
+		} else {
+			origMetaKeySize, origMetaValSize = newMetaKeySize, newMetaValSize
+		}
+	}
+
 	// If we're committing, or if the commit timestamp of the intent has been moved forward, and if
 	// the proposed epoch matches the existing epoch: update the meta.Txn. For commit, it's set to
 	// nil; otherwise, we update its value. We may have to update the actual version value (remove old
@@ -2465,6 +2550,68 @@ func mvccResolveWriteIntent(
 	return true, nil
 }
 
+// mvccMaybeRewriteIntentHistory is called when resolving an intent whose
+// transaction has rolled back some of its writes to savepoints. If the
+// intent's current value was written at one of the ignored sequence numbers,
+// the latest write in the intent history that was not ignored is restored as
+// the intent's value and the metadata in buf.meta is updated accordingly.
+// Returns true if no such write exists and the intent should be removed
+// altogether. Otherwise, returns the sizes of the (possibly rewritten)
+// metadata key and value.
+func mvccMaybeRewriteIntentHistory(
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	metaKey MVCCKey,
+	ignoredSeqNums []enginepb.IgnoredSeqNumRange,
+	buf *putBuffer,
+	origMetaKeySize, origMetaValSize int64,
+) (removeIntent bool, metaKeySize, metaValSize int64, _ error) {
+	meta := &buf.meta
+	if !enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, ignoredSeqNums) {
+		// The latest write was not rolled back. Nothing to do here.
+		return false, origMetaKeySize, origMetaValSize, nil
+	}
+
+	// Find the latest write in the history that was not rolled back.
+	i := len(meta.IntentHistory) - 1
+	for ; i >= 0; i-- {
+		if !enginepb.TxnSeqIsIgnored(meta.IntentHistory[i].Sequence, ignoredSeqNums) {
+			break
+		}
+	}
+	if i < 0 {
+		// Every write to this key was rolled back.
+		return true, 0, 0, nil
+	}
+
+	// Put the write from that history entry back into the intent, both in
+	// the metadata and in the provisional value itself.
+	orig := *meta
+	restoredVal := meta.IntentHistory[i].Value
+	txnMeta := *meta.Txn
+	txnMeta.Sequence = meta.IntentHistory[i].Sequence
+	meta.Txn = &txnMeta
+	meta.IntentHistory = meta.IntentHistory[:i]
+	meta.Deleted = len(restoredVal) == 0
+	meta.ValBytes = int64(len(restoredVal))
+
+	versionKey := MVCCKey{Key: metaKey.Key, Timestamp: hlc.Timestamp(meta.Timestamp)}
+	if err := engine.Put(versionKey, restoredVal); err != nil {
+		return false, 0, 0, err
+	}
+	metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, meta)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	// The rewrite is accounted for as the transaction overwriting its own
+	// intent at the same timestamp.
+	if ms != nil {
+		ms.Add(updateStatsOnPut(metaKey.Key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+			metaKeySize, metaValSize, &orig, meta))
+	}
+	return false, metaKeySize, metaValSize, nil
+}
+
 // IterAndBuf used to pass iterators and buffers between MVCC* calls, allowing
 // reuse without the callers needing to know the particulars.
 type IterAndBuf struct {
@@ -2700,6 +2847,495 @@ func MVCCGarbageCollect(
 	return nil
 }
 
+// MVCCDeleteRangeUsingTombstone deletes all versioned keys in [key, endKey)
+// at the given timestamp by writing a single MVCC range tombstone for the
+// range with the given ID, instead of one deletion tombstone per key. Reads
+// at or above the timestamp that are passed the range's tombstones (see
+// MVCCScanRangeTombstones) do not observe any value written at or below it,
+// while reads below the timestamp are unaffected.
+//
+// The deletion is not transactional. A WriteIntentError is returned if an
+// intent is found in the span and a WriteTooOldError if a value or range
+// tombstone at or above the timestamp is found. Inline values are not
+// versioned and are left untouched.
+//
+// The range's live stats are adjusted for all keys that the tombstone
+// deletes. If the span covers the entire range, the caller may pass the
+// range's stats (without the system stats) as msCovered; if they contain
+// no intents and are not estimates, they are used in lieu of scanning the
+// span, which makes the operation O(1) but doesn't detect conflicting
+// values. If no key is newly deleted by the tombstone, nothing is written
+// at all, which makes retries of the operation idempotent.
+//
+// NOTE: like ClearRange, range tombstones should only be used on spans that
+// are guaranteed to not see future writes, such as those of dropped or
+// truncated tables. The write functions of this package are unaware of range
+// tombstones; writes are checked against them during command evaluation,
+// which marks the stats of a range as estimates once they are written over.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	rangeID roachpb.RangeID,
+	key, endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+	msCovered *enginepb.MVCCStats,
+) error {
+	if timestamp == (hlc.Timestamp{}) {
+		return errors.Errorf("cannot write range tombstone over [%s,%s) at zero timestamp", key, endKey)
+	}
+	if keys.IsLocal(key) {
+		return errors.Errorf("cannot write range tombstone over local span [%s,%s)", key, endKey)
+	}
+
+	existing, err := MVCCScanRangeTombstones(ctx, rw, rangeID, key, endKey)
+	if err != nil {
+		return err
+	}
+	var tooOldTS hlc.Timestamp
+	for _, t := range existing {
+		if !t.Timestamp.Less(timestamp) {
+			tooOldTS.Forward(t.Timestamp)
+		}
+	}
+	if tooOldTS != (hlc.Timestamp{}) {
+		return &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: tooOldTS.Next()}
+	}
+
+	// Values deleted by the tombstone become non-live at its timestamp.
+	var delta enginepb.MVCCStats
+	delta.AgeTo(timestamp.WallTime)
+	if msCovered != nil && !msCovered.ContainsEstimates && msCovered.IntentCount == 0 {
+		// The live keys in the range are exactly the ones the tombstone deletes:
+		// keys deleted by an existing range tombstone are already non-live.
+		delta.LiveBytes = -msCovered.LiveBytes
+		delta.LiveCount = -msCovered.LiveCount
+	} else {
+		iter := rw.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
+		defer iter.Close()
+
+		var meta enginepb.MVCCMetadata
+		var intents []roachpb.Intent
+		for iter.Seek(MakeMVCCMetadataKey(key)); ; iter.NextKey() {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			unsafeKey := iter.UnsafeKey()
+			if !unsafeKey.IsValue() {
+				// An explicit meta record is either an intent or an inline value.
+				if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+					return err
+				}
+				if meta.Txn != nil {
+					intents = append(intents, roachpb.Intent{
+						Span:   roachpb.Span{Key: append(roachpb.Key(nil), unsafeKey.Key...)},
+						Status: roachpb.PENDING,
+						Txn:    *meta.Txn,
+					})
+				}
+				continue
+			}
+			if !unsafeKey.Timestamp.Less(timestamp) {
+				tooOldTS.Forward(unsafeKey.Timestamp)
+				continue
+			}
+			valSize := int64(len(iter.UnsafeValue()))
+			if valSize == 0 {
+				// Already deleted.
+				continue
+			}
+			if _, ok := CoveringRangeTombstone(existing, unsafeKey.Key, unsafeKey.Timestamp, timestamp); ok {
+				// Already deleted by an existing range tombstone.
+				continue
+			}
+			delta.LiveBytes -= int64(len(unsafeKey.Key)) + 1 + mvccVersionTimestampSize + valSize
+			delta.LiveCount--
+		}
+		if len(intents) > 0 {
+			return &roachpb.WriteIntentError{Intents: intents}
+		}
+		if tooOldTS != (hlc.Timestamp{}) {
+			return &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: tooOldTS.Next()}
+		}
+	}
+	if delta.LiveCount == 0 {
+		log.VEventf(ctx, 2, "range tombstone over [%s,%s) at %s deletes no keys", key, endKey, timestamp)
+		return nil
+	}
+
+	tombstone := enginepb.MVCCRangeTombstone{
+		StartKey:  key,
+		EndKey:    endKey,
+		Timestamp: timestamp,
+	}
+	if err := MVCCPutProto(
+		ctx, rw, ms, keys.RangeTombstoneKey(rangeID, key, timestamp), hlc.Timestamp{}, nil, &tombstone,
+	); err != nil {
+		return err
+	}
+	if ms != nil {
+		ms.Add(delta)
+	}
+	rw.LogLogicalOp(MVCCDeleteRangeOpType, MVCCLogicalOpDetails{
+		Key:       key,
+		EndKey:    endKey,
+		Timestamp: timestamp,
+	})
+	return nil
+}
+
+// MVCCScanRangeTombstones returns the MVCC range tombstones of the range with
+// the given ID that overlap [key, endKey), ordered by start key and
+// timestamp. Note that the returned tombstones are not clipped to the span.
+func MVCCScanRangeTombstones(
+	ctx context.Context, reader Reader, rangeID roachpb.RangeID, key, endKey roachpb.Key,
+) ([]enginepb.MVCCRangeTombstone, error) {
+	prefix := keys.RangeTombstonePrefix(rangeID)
+	var tombstones []enginepb.MVCCRangeTombstone
+	_, err := MVCCIterate(ctx, reader, prefix, prefix.PrefixEnd(), hlc.Timestamp{}, MVCCScanOptions{},
+		func(kv roachpb.KeyValue) (bool, error) {
+			var t enginepb.MVCCRangeTombstone
+			if err := kv.Value.GetProto(&t); err != nil {
+				return false, err
+			}
+			if bytes.Compare(t.StartKey, endKey) < 0 && bytes.Compare(key, t.EndKey) < 0 {
+				tombstones = append(tombstones, t)
+			}
+			return false, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return tombstones, nil
+}
+
+// CoveringRangeTombstone returns the timestamp of the earliest of the given
+// range tombstones that deletes the version of key written at valueTS, as
+// observed by a read at readTS. Inline values are never deleted by range
+// tombstones.
+func CoveringRangeTombstone(
+	tombstones []enginepb.MVCCRangeTombstone, key roachpb.Key, valueTS, readTS hlc.Timestamp,
+) (hlc.Timestamp, bool) {
+	var ts hlc.Timestamp
+	var found bool
+	if valueTS == (hlc.Timestamp{}) {
+		return ts, false
+	}
+	for i := range tombstones {
+		t := &tombstones[i]
+		if bytes.Compare(key, t.StartKey) < 0 || bytes.Compare(key, t.EndKey) >= 0 {
+			continue
+		}
+		if t.Timestamp.Less(valueTS) || readTS.Less(t.Timestamp) {
+			continue
+		}
+		if !found || t.Timestamp.Less(ts) {
+			ts, found = t.Timestamp, true
+		}
+	}
+	return ts, found
+}
+
+// checkRangeTombstones returns whether the version of key written at valueTS
+// is deleted by one of the given range tombstones as of readTS, along with
+// the timestamp of the deletion. If it isn't but a transactional reader could
+// not tell whether the deletion happened before it, a
+// ReadWithinUncertaintyIntervalError is returned instead.
+func checkRangeTombstones(
+	tombstones []enginepb.MVCCRangeTombstone,
+	key roachpb.Key,
+	valueTS, readTS hlc.Timestamp,
+	txn *roachpb.Transaction,
+) (hlc.Timestamp, bool, error) {
+	if ts, ok := CoveringRangeTombstone(tombstones, key, valueTS, readTS); ok {
+		return ts, true, nil
+	}
+	if txn != nil && readTS.Less(txn.MaxTimestamp) {
+		if ts, ok := CoveringRangeTombstone(tombstones, key, valueTS, txn.MaxTimestamp); ok {
+			return hlc.Timestamp{}, false, roachpb.NewReadWithinUncertaintyIntervalError(readTS, ts, txn)
+		}
+	}
+	return hlc.Timestamp{}, false, nil
+}
+
+// filterRangeTombstonesFromKvs removes the values deleted by the range
+// tombstones in opts from the result of a scan, or replaces them by deletion
+// tombstones in tombstones mode.
+func filterRangeTombstonesFromKvs(
+	kvs []roachpb.KeyValue, timestamp hlc.Timestamp, opts MVCCScanOptions,
+) ([]roachpb.KeyValue, error) {
+	filtered := kvs[:0]
+	for _, kv := range kvs {
+		if len(kv.Value.RawBytes) > 0 {
+			deletedTS, deleted, err := checkRangeTombstones(
+				opts.RangeTombstones, kv.Key, kv.Value.Timestamp, timestamp, opts.Txn)
+			if err != nil {
+				return nil, err
+			}
+			if deleted {
+				if !opts.Tombstones {
+					continue
+				}
+				kv.Value = roachpb.Value{Timestamp: deletedTS}
+			}
+		}
+		filtered = append(filtered, kv)
+	}
+	return filtered, nil
+}
+
+// filterRangeTombstonesFromBytes is like filterRangeTombstonesFromKvs, but
+// operates on the result of Iterator.MVCCScan.
+func filterRangeTombstonesFromBytes(
+	kvData []byte, timestamp hlc.Timestamp, opts MVCCScanOptions,
+) ([]byte, int64, error) {
+	filtered := make([]byte, 0, len(kvData))
+	var numKVs int64
+	for len(kvData) > 0 {
+		k, rawBytes, rest, err := MVCCScanDecodeKeyValue(kvData)
+		if err != nil {
+			return nil, 0, err
+		}
+		kv := kvData[:len(kvData)-len(rest)]
+		kvData = rest
+		if len(rawBytes) > 0 {
+			deletedTS, deleted, err := checkRangeTombstones(
+				opts.RangeTombstones, k.Key, k.Timestamp, timestamp, opts.Txn)
+			if err != nil {
+				return nil, 0, err
+			}
+			if deleted {
+				if !opts.Tombstones {
+					continue
+				}
+				encKey := EncodeKey(MVCCKey{Key: k.Key, Timestamp: deletedTS})
+				var lenBuf [8]byte
+				binary.LittleEndian.PutUint32(lenBuf[4:], uint32(len(encKey)))
+				filtered = append(filtered, lenBuf[:]...)
+				filtered = append(filtered, encKey...)
+				numKVs++
+				continue
+			}
+		}
+		filtered = append(filtered, kv...)
+		numKVs++
+	}
+	return filtered, numKVs, nil
+}
+
+// MVCCGarbageCollectRangeTombstone removes all versions of keys in [key,
+// endKey) that are deleted by the range tombstone of the range with the given
+// ID with the given timestamp. If the span covers the range tombstone's
+// entire span (within the bounds of the range), the range tombstone itself
+// is removed as well. The tombstone's timestamp must not be above the GC
+// threshold, which the caller is responsible for checking. If no such range
+// tombstone exists, nothing is removed.
+//
+// The GC queue removes the versions deleted by a range tombstone in a
+// sequence of calls with adjacent spans, the last of which covers the entire
+// range tombstone to remove it along with any versions left behind.
+func MVCCGarbageCollectRangeTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	rangeID roachpb.RangeID,
+	desc roachpb.RSpan,
+	gcTombstone enginepb.MVCCRangeTombstone,
+) error {
+	tombstones, err := MVCCScanRangeTombstones(
+		ctx, rw, rangeID, desc.Key.AsRawKey(), desc.EndKey.AsRawKey())
+	if err != nil {
+		return err
+	}
+	key, endKey := clipToRSpan(gcTombstone.StartKey, gcTombstone.EndKey, desc)
+	var tombstone *enginepb.MVCCRangeTombstone
+	for i := range tombstones {
+		t := &tombstones[i]
+		if t.Timestamp == gcTombstone.Timestamp &&
+			bytes.Compare(t.StartKey, key) <= 0 && bytes.Compare(endKey, t.EndKey) <= 0 {
+			tombstone = t
+			break
+		}
+	}
+	if tombstone == nil || bytes.Compare(key, endKey) >= 0 {
+		log.VEventf(ctx, 2, "range tombstone over [%s,%s) at %s not found",
+			gcTombstone.StartKey, gcTombstone.EndKey, gcTombstone.Timestamp)
+		return nil
+	}
+
+	iter := rw.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
+	defer iter.Close()
+
+	var count int64
+	var meta enginepb.MVCCMetadata
+	iter.Seek(MakeMVCCMetadataKey(key))
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() {
+			if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+				return err
+			}
+			if meta.Txn != nil {
+				return errors.Errorf("request to GC intent at %q", unsafeKey.Key)
+			}
+			// Inline values aren't deleted by range tombstones.
+			iter.NextKey()
+			continue
+		}
+
+		// See MVCCGarbageCollect and ComputeStatsGo for the GCBytesAge
+		// accounting. The latest version of the key is removed only if the
+		// tombstone (or an older one) deletes it, in which case it became
+		// non-live when it was deleted.
+		curKey := append(roachpb.Key(nil), unsafeKey.Key...)
+		metaKeySize := int64(len(curKey)) + 1
+		first := true
+		var prevNanos int64
+		for ; ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			unsafeKey = iter.UnsafeKey()
+			if !unsafeKey.Key.Equal(curKey) {
+				break
+			}
+			valSize := int64(len(iter.UnsafeValue()))
+			fromNS := prevNanos
+			if valSize == 0 {
+				fromNS = unsafeKey.Timestamp.WallTime
+			}
+			if !tombstone.Timestamp.Less(unsafeKey.Timestamp) {
+				if first {
+					if valSize > 0 {
+						deletedTS, _ := CoveringRangeTombstone(
+							tombstones, curKey, unsafeKey.Timestamp, tombstone.Timestamp)
+						fromNS = deletedTS.WallTime
+					}
+					if ms != nil {
+						ms.Add(updateStatsOnGC(curKey, metaKeySize, 0, &meta, fromNS))
+					}
+				}
+				if ms != nil {
+					ms.Add(updateStatsOnGC(curKey, mvccVersionTimestampSize, valSize, nil, fromNS))
+				}
+				if err := rw.Clear(unsafeKey); err != nil {
+					return err
+				}
+				count++
+			}
+			first = false
+			prevNanos = unsafeKey.Timestamp.WallTime
+		}
+	}
+
+	tKey, tEndKey := clipToRSpan(tombstone.StartKey, tombstone.EndKey, desc)
+	if bytes.Compare(key, tKey) <= 0 && bytes.Compare(tEndKey, endKey) <= 0 {
+		if err := MVCCDelete(
+			ctx, rw, ms, keys.RangeTombstoneKey(rangeID, tombstone.StartKey, tombstone.Timestamp),
+			hlc.Timestamp{}, nil,
+		); err != nil {
+			return err
+		}
+		count++
+	}
+	log.Eventf(ctx, "GC of range tombstone over [%s,%s) at %s deleted %d entries",
+		key, endKey, tombstone.Timestamp, count)
+	return nil
+}
+
+// clipToRSpan returns the intersection of [key, endKey) with the given span.
+func clipToRSpan(key, endKey roachpb.Key, span roachpb.RSpan) (roachpb.Key, roachpb.Key) {
+	if start := span.Key.AsRawKey(); bytes.Compare(key, start) < 0 {
+		key = start
+	}
+	if end := span.EndKey.AsRawKey(); bytes.Compare(end, endKey) < 0 {
+		endKey = end
+	}
+	return key, endKey
+}
+
+// ComputeRangeTombstoneStats returns the adjustment to the stats computed for
+// [start, end) by ComputeStatsGo or Iterator.ComputeStats that accounts for
+// the given range tombstones: every key whose latest version is deleted by a
+// range tombstone is non-live and accrues GCBytesAge from the timestamp of
+// the earliest such range tombstone on.
+func ComputeRangeTombstoneStats(
+	iter SimpleIterator,
+	tombstones []enginepb.MVCCRangeTombstone,
+	start, end roachpb.Key,
+	nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	var ms enginepb.MVCCStats
+	for _, span := range mergeRangeTombstoneSpans(tombstones, start, end) {
+		endKey := MakeMVCCMetadataKey(span.EndKey)
+		for iter.Seek(MakeMVCCMetadataKey(span.Key)); ; iter.NextKey() {
+			if ok, err := iter.Valid(); err != nil {
+				return enginepb.MVCCStats{}, err
+			} else if !ok || !iter.UnsafeKey().Less(endKey) {
+				break
+			}
+			unsafeKey := iter.UnsafeKey()
+			valSize := int64(len(iter.UnsafeValue()))
+			if !unsafeKey.IsValue() || valSize == 0 {
+				// Intents, inline values and deleted keys aren't affected.
+				continue
+			}
+			deletedTS, ok := CoveringRangeTombstone(
+				tombstones, unsafeKey.Key, unsafeKey.Timestamp, hlc.MaxTimestamp)
+			if !ok {
+				continue
+			}
+			totalBytes := int64(len(unsafeKey.Key)) + 1 + mvccVersionTimestampSize + valSize
+			ms.LiveBytes -= totalBytes
+			ms.LiveCount--
+			ms.GCBytesAge += totalBytes * (nowNanos/1E9 - deletedTS.WallTime/1E9)
+		}
+	}
+	ms.LastUpdateNanos = nowNanos
+	return ms, nil
+}
+
+// mergeRangeTombstoneSpans returns the sorted, non-overlapping union of the
+// spans of the given range tombstones, clipped to [start, end).
+func mergeRangeTombstoneSpans(
+	tombstones []enginepb.MVCCRangeTombstone, start, end roachpb.Key,
+) []roachpb.Span {
+	spans := make([]roachpb.Span, 0, len(tombstones))
+	for _, t := range tombstones {
+		span := roachpb.Span{Key: t.StartKey, EndKey: t.EndKey}
+		if bytes.Compare(span.Key, start) < 0 {
+			span.Key = start
+		}
+		if bytes.Compare(end, span.EndKey) < 0 {
+			span.EndKey = end
+		}
+		if bytes.Compare(span.Key, span.EndKey) < 0 {
+			spans = append(spans, span)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return bytes.Compare(spans[i].Key, spans[j].Key) < 0 })
+	merged := spans[:0]
+	for _, span := range spans {
+		if n := len(merged); n > 0 && bytes.Compare(span.Key, merged[n-1].EndKey) <= 0 {
+			if bytes.Compare(merged[n-1].EndKey, span.EndKey) < 0 {
+				merged[n-1].EndKey = span.EndKey
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
 // MVCCFindSplitKey finds a key from the given span such that the left side of
 // the split is roughly targetSize bytes. The returned key will never be chosen
 // from the key ranges listed in keys.NoSplitSpans.
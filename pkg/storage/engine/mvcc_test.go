@@ -2146,6 +2146,135 @@ func TestMVCCDeleteRangeReturnKeys(t *testing.T) {
 	}
 }
 
+func TestMVCCDeleteRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	const rangeID = roachpb.RangeID(1)
+	ms := &enginepb.MVCCStats{}
+	ts1 := hlc.Timestamp{WallTime: 1E9}
+	ts2 := hlc.Timestamp{WallTime: 2E9}
+	ts3 := hlc.Timestamp{WallTime: 3E9}
+
+	for _, kv := range []struct {
+		key roachpb.Key
+		val roachpb.Value
+	}{
+		{testKey1, value1},
+		{testKey2, value2},
+		{testKey3, value3},
+		{testKey4, value4},
+	} {
+		if err := MVCCPut(ctx, engine, ms, kv.key, ts1, kv.val, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanTombstones := func() []enginepb.MVCCRangeTombstone {
+		t.Helper()
+		tombstones, err := MVCCScanRangeTombstones(ctx, engine, rangeID, keyMin, keyMax)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tombstones
+	}
+	scanKeys := func(ts hlc.Timestamp, tombstones bool) []roachpb.Key {
+		t.Helper()
+		kvs, _, _, err := MVCCScan(ctx, engine, keyMin, keyMax, math.MaxInt64, ts,
+			MVCCScanOptions{Tombstones: tombstones, RangeTombstones: scanTombstones()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []roachpb.Key
+		for _, kv := range kvs {
+			keys = append(keys, kv.Key)
+		}
+		return keys
+	}
+	// assertStats compares the stats against those computed from the engine,
+	// taking the range tombstones into account.
+	assertStats := func(debug string) {
+		t.Helper()
+		iter := engine.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+		defer iter.Close()
+		expMS, err := ComputeStatsGo(iter, MVCCKey{}, MVCCKey{Key: roachpb.KeyMax}, ts3.WallTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adjMS, err := ComputeRangeTombstoneStats(iter, scanTombstones(), keyMin, keyMax, ts3.WallTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expMS.Add(adjMS)
+		msCpy := *ms
+		msCpy.AgeTo(ts3.WallTime)
+		if !msCpy.Equal(expMS) {
+			t.Errorf("%s: diff(ms, expMS) = %s", debug, pretty.Diff(msCpy, expMS))
+		}
+	}
+
+	// Delete [testKey2, testKey4) using a range tombstone.
+	if err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, rangeID, testKey2, testKey4, ts2, nil, /* msCovered */
+	); err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, scanTombstones(), 1)
+	require.Equal(t, []roachpb.Key{testKey1, testKey4}, scanKeys(ts2, false /* tombstones */))
+	require.Equal(t, []roachpb.Key{testKey1, testKey2, testKey3, testKey4},
+		scanKeys(ts2, true /* tombstones */))
+	require.Equal(t, []roachpb.Key{testKey1, testKey2, testKey3, testKey4},
+		scanKeys(ts1, false /* tombstones */))
+	value, _, err := MVCCGet(ctx, engine, testKey3, ts3, MVCCGetOptions{RangeTombstones: scanTombstones()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != nil {
+		t.Fatalf("expected %s to be deleted, found %v", testKey3, value)
+	}
+	assertStats("after range tombstone")
+
+	// Deleting the span again doesn't write anything since all of its keys are
+	// already deleted, while a range tombstone that deletes additional keys is
+	// written.
+	if err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, rangeID, testKey2, testKey4, ts2.Next(), nil, /* msCovered */
+	); err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, scanTombstones(), 1)
+	if err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, rangeID, testKey1, testKey3, ts3, nil, /* msCovered */
+	); err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, scanTombstones(), 2)
+	require.Equal(t, []roachpb.Key{testKey4}, scanKeys(ts3, false /* tombstones */))
+	assertStats("after overlapping range tombstones")
+
+	// A range tombstone beneath an existing one is rejected.
+	err = MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, rangeID, testKey3, testKey5, ts1.Next(), nil, /* msCovered */
+	)
+	if _, ok := err.(*roachpb.WriteTooOldError); !ok {
+		t.Fatalf("expected WriteTooOldError, got %v", err)
+	}
+
+	// GC the range tombstones, which removes the values they delete.
+	desc := roachpb.RSpan{Key: roachpb.RKeyMin, EndKey: roachpb.RKeyMax}
+	for _, tombstone := range scanTombstones() {
+		if err := MVCCGarbageCollectRangeTombstone(ctx, engine, ms, rangeID, desc, tombstone); err != nil {
+			t.Fatal(err)
+		}
+	}
+	require.Len(t, scanTombstones(), 0)
+	require.Equal(t, []roachpb.Key{testKey4}, scanKeys(ts1, true /* tombstones */))
+	assertStats("after GC")
+}
+
 func TestMVCCDeleteRangeFailed(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
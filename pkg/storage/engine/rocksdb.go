@@ -2614,10 +2614,25 @@ func goToCTxn(txn *roachpb.Transaction) C.DBTxn {
 		r.epoch = C.uint32_t(txn.Epoch)
 		r.sequence = C.int32_t(txn.Sequence)
 		r.max_timestamp = goToCTimestamp(txn.MaxTimestamp)
+		r.ignored_seqnums = goToCIgnoredSeqNums(txn.IgnoredSeqNums)
 	}
 	return r
 }
 
+// goToCIgnoredSeqNums converts a list of ignored seqnum ranges to a
+// DBIgnoredSeqNums. Note that this is not a copy: the C struct points
+// directly into the Go slice, which relies on enginepb.IgnoredSeqNumRange
+// having the same memory layout as DBIgnoredSeqNumRange.
+func goToCIgnoredSeqNums(b []enginepb.IgnoredSeqNumRange) C.DBIgnoredSeqNums {
+	if len(b) == 0 {
+		return C.DBIgnoredSeqNums{ranges: nil, len: 0}
+	}
+	return C.DBIgnoredSeqNums{
+		ranges: (*C.DBIgnoredSeqNumRange)(unsafe.Pointer(&b[0])),
+		len:    C.int(len(b)),
+	}
+}
+
 func goToCIterOptions(opts IterOptions) C.DBIterOptions {
 	return C.DBIterOptions{
 		prefix:             C.bool(opts.Prefix),
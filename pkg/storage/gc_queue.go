@@ -11,6 +11,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -162,12 +163,20 @@ func makeGCQueueScore(
 
 	desc, zone := repl.DescAndZone()
 
+	// Data protected by protected timestamp records can't be garbage
+	// collected, so score the replica as of the latest timestamp which
+	// can be used for a GC run.
+	gcTimestamp, canGC := repl.checkProtectedTimestampsForGC(ctx, now, *zone.GC)
+
 	// Use desc.RangeID for fuzzing the final score, so that different ranges
 	// have slightly different priorities and even symmetrical workloads don't
 	// trigger GC at the same time.
 	r := makeGCQueueScoreImpl(
-		ctx, int64(desc.RangeID), now, ms, zone.GC.TTLSeconds,
+		ctx, int64(desc.RangeID), gcTimestamp, ms, zone.GC.TTLSeconds,
 	)
+	if !canGC {
+		r.ShouldQueue = false
+	}
 	if (gcThreshold != hlc.Timestamp{}) {
 		r.LikelyLastGC = time.Duration(now.WallTime - gcThreshold.Add(r.TTL.Nanoseconds(), 0).WallTime)
 	}
@@ -463,6 +472,107 @@ func processAbortSpan(
 	return gcKeys
 }
 
+// processRangeTombstones removes the range tombstones of the range at or
+// below the GC threshold along with all of the values they delete, and
+// returns the range tombstones which were removed. The values deleted by a
+// range tombstone are removed in chunks of adjacent spans to bound the size
+// of the individual GC requests, followed by a final request for the entire
+// range tombstone which also removes the tombstone itself.
+func processRangeTombstones(
+	ctx context.Context,
+	snap engine.Reader,
+	desc *roachpb.RangeDescriptor,
+	threshold hlc.Timestamp,
+	gcer GCer,
+	infoMu *lockableGCInfo,
+) ([]enginepb.MVCCRangeTombstone, error) {
+	tombstones, err := engine.MVCCScanRangeTombstones(
+		ctx, snap, desc.RangeID, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey())
+	if err != nil {
+		return nil, err
+	}
+	var gcTombstones []enginepb.MVCCRangeTombstone
+	for _, t := range tombstones {
+		if threshold.Less(t.Timestamp) {
+			continue
+		}
+		if err := processRangeTombstone(ctx, snap, desc, t, gcer, infoMu); err != nil {
+			// The values deleted by the range tombstone are considered for GC
+			// individually instead.
+			log.Warning(ctx, err)
+			continue
+		}
+		gcTombstones = append(gcTombstones, t)
+	}
+	return gcTombstones, nil
+}
+
+// processRangeTombstone removes the given range tombstone and the values it
+// deletes. See processRangeTombstones.
+func processRangeTombstone(
+	ctx context.Context,
+	snap engine.Reader,
+	desc *roachpb.RangeDescriptor,
+	t enginepb.MVCCRangeTombstone,
+	gcer GCer,
+	infoMu *lockableGCInfo,
+) error {
+	key, endKey := roachpb.Key(t.StartKey), roachpb.Key(t.EndKey)
+	if start := desc.StartKey.AsRawKey(); key.Compare(start) < 0 {
+		key = start
+	}
+	if end := desc.EndKey.AsRawKey(); end.Compare(endKey) < 0 {
+		endKey = end
+	}
+	iter := snap.NewIterator(engine.IterOptions{LowerBound: key, UpperBound: endKey})
+	defer iter.Close()
+
+	var numKeys int
+	var keyBytes, valBytes, chunkBytes int64
+	var curKey roachpb.Key
+	chunkStart := key
+	for iter.Seek(engine.MakeMVCCMetadataKey(key)); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() || t.Timestamp.Less(unsafeKey.Timestamp) {
+			continue
+		}
+		if !bytes.Equal(unsafeKey.Key, curKey) {
+			curKey = append(roachpb.Key(nil), unsafeKey.Key...)
+			// Finish the current chunk once its versions exceed the target
+			// size, at a key boundary.
+			if chunkBytes >= gcKeyVersionChunkBytes {
+				chunk := t
+				chunk.StartKey, chunk.EndKey = chunkStart, curKey
+				if err := gcer.GCRangeTombstones(ctx, []enginepb.MVCCRangeTombstone{chunk}); err != nil {
+					return err
+				}
+				chunkStart = curKey
+				chunkBytes = 0
+			}
+			numKeys++
+		}
+		size := int64(unsafeKey.EncodedSize())
+		keyBytes += size
+		valBytes += int64(len(iter.UnsafeValue()))
+		chunkBytes += size
+	}
+	if err := gcer.GCRangeTombstones(ctx, []enginepb.MVCCRangeTombstone{t}); err != nil {
+		return err
+	}
+
+	infoMu.Lock()
+	defer infoMu.Unlock()
+	infoMu.NumKeysAffected += numKeys
+	infoMu.AffectedVersionsKeyBytes += keyBytes
+	infoMu.AffectedVersionsValBytes += valBytes
+	return nil
+}
+
 // NoopGCer implements GCer by doing nothing.
 type NoopGCer struct{}
 
@@ -474,6 +584,11 @@ func (NoopGCer) SetGCThreshold(context.Context, GCThreshold) error { return nil
 // GC implements storage.GCer.
 func (NoopGCer) GC(context.Context, []roachpb.GCRequest_GCKey) error { return nil }
 
+// GCRangeTombstones implements storage.GCer.
+func (NoopGCer) GCRangeTombstones(context.Context, []enginepb.MVCCRangeTombstone) error {
+	return nil
+}
+
 type replicaGCer struct {
 	repl  *Replica
 	count int32 // update atomically
@@ -523,6 +638,17 @@ func (r *replicaGCer) GC(ctx context.Context, keys []roachpb.GCRequest_GCKey) er
 	return r.send(ctx, req)
 }
 
+func (r *replicaGCer) GCRangeTombstones(
+	ctx context.Context, tombstones []enginepb.MVCCRangeTombstone,
+) error {
+	if len(tombstones) == 0 {
+		return nil
+	}
+	req := r.template()
+	req.RangeTombstones = tombstones
+	return r.send(ctx, req)
+}
+
 // process iterates through all keys in a replica's range, calling the garbage
 // collector for each key and associated set of values. GC'd keys are batched
 // into GC calls. Extant intents are resolved if intents are older than
@@ -558,7 +684,15 @@ func (gcq *gcQueue) process(ctx context.Context, repl *Replica, sysCfg *config.S
 	// Lookup the descriptor and GC policy for the zone containing this key range.
 	desc, zone := repl.DescAndZone()
 
-	info, err := RunGC(ctx, desc, snap, now, *zone.GC, &replicaGCer{repl: repl},
+	// Hold the GC threshold back below the data protected by protected
+	// timestamp records.
+	gcTimestamp, canGC := repl.checkProtectedTimestampsForGC(ctx, now, *zone.GC)
+	if !canGC {
+		log.VEventf(ctx, 2, "not gc'ing replica %s: protected timestamps unknown", repl)
+		return nil
+	}
+
+	info, err := RunGC(ctx, desc, snap, now, gcTimestamp, *zone.GC, &replicaGCer{repl: repl},
 		func(ctx context.Context, intents []roachpb.Intent) error {
 			intentCount, err := repl.store.intentResolver.CleanupIntents(ctx, intents, now, roachpb.PUSH_ABORT)
 			if err == nil {
@@ -623,7 +757,8 @@ type GCInfo struct {
 	// ResolveTotal is the total number of attempted intent resolutions in
 	// this cycle.
 	ResolveTotal int
-	// Threshold is the computed expiration timestamp. Equal to `Now - Policy`.
+	// Threshold is the computed expiration timestamp. Equal to `Now - Policy`,
+	// unless held back by protected timestamp records.
 	Threshold hlc.Timestamp
 	// AffectedVersionsKeyBytes is the number of (fully encoded) bytes deleted from keys in the storage engine.
 	// Note that this does not account for compression that the storage engine uses to store data on disk. Real
@@ -666,6 +801,7 @@ type GCThreshold struct {
 type GCer interface {
 	SetGCThreshold(context.Context, GCThreshold) error
 	GC(context.Context, []roachpb.GCRequest_GCKey) error
+	GCRangeTombstones(context.Context, []enginepb.MVCCRangeTombstone) error
 }
 
 // RunGC runs garbage collection for the specified descriptor on the
@@ -674,11 +810,15 @@ type GCer interface {
 // cleanupIntentsFn to resolve intents synchronously, and
 // cleanupTxnIntentsAsyncFn to asynchronously cleanup intents and
 // associated transaction record on success.
+//
+// The age of intents and transaction records is computed relative to now,
+// while the GC threshold of the keys is computed relative to gcTimestamp,
+// which may be held back below now by protected timestamp records.
 func RunGC(
 	ctx context.Context,
 	desc *roachpb.RangeDescriptor,
 	snap engine.Reader,
-	now hlc.Timestamp,
+	now, gcTimestamp hlc.Timestamp,
 	policy config.GCPolicy,
 	gcer GCer,
 	cleanupIntentsFn cleanupIntentsFunc,
@@ -696,7 +836,7 @@ func RunGC(
 	intentExp := now.Add(-intentAgeThreshold.Nanoseconds(), 0)
 	txnExp := now.Add(-storagebase.TxnCleanupThreshold.Nanoseconds(), 0)
 
-	gc := engine.MakeGarbageCollector(now, policy)
+	gc := engine.MakeGarbageCollector(gcTimestamp, policy)
 	infoMu.Threshold = gc.Threshold
 
 	if err := gcer.SetGCThreshold(ctx, GCThreshold{
@@ -706,6 +846,14 @@ func RunGC(
 		return GCInfo{}, errors.Wrap(err, "failed to set GC thresholds")
 	}
 
+	// Remove the range tombstones at or below the GC threshold along with the
+	// values they delete. The keys whose latest version is deleted by one of
+	// them are skipped below.
+	gcRangeTombstones, err := processRangeTombstones(ctx, snap, desc, gc.Threshold, gcer, &infoMu)
+	if err != nil {
+		return GCInfo{}, err
+	}
+
 	var batchGCKeys []roachpb.GCRequest_GCKey
 	var batchGCKeysBytes int64
 	var expBaseKey roachpb.Key
@@ -732,6 +880,13 @@ func RunGC(
 				// In the event that there's an active intent, send for
 				// intent resolution if older than the threshold.
 				startIdx := 1
+				if _, ok := engine.CoveringRangeTombstone(
+					gcRangeTombstones, keys[1].Key, keys[1].Timestamp, hlc.MaxTimestamp,
+				); ok && meta.Txn == nil {
+					// All versions of the key were removed along with the
+					// range tombstone which deletes its latest version.
+					return
+				}
 				if meta.Txn != nil {
 					// Keep track of intent to resolve if older than the intent
 					// expiration threshold.
@@ -502,7 +502,7 @@ func TestGCQueueProcess(t *testing.T) {
 		}
 
 		now := tc.Clock().Now()
-		return RunGC(ctx, desc, snap, now, *zone.GC,
+		return RunGC(ctx, desc, snap, now, now, *zone.GC,
 			NoopGCer{},
 			func(ctx context.Context, intents []roachpb.Intent) error {
 				return nil
@@ -905,6 +905,58 @@ func TestGCQueueIntentResolution(t *testing.T) {
 	})
 }
 
+// TestGCQueueIntentResolutionWithProtectedTimestamps verifies that old
+// intents are resolved even if the GC threshold is held back by the protected
+// timestamp cache.
+func TestGCQueueIntentResolutionWithProtectedTimestamps(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	cache := &fakeProtectedTimestampCache{}
+	tsc := TestStoreConfig(nil)
+	tsc.ProtectedTimestampCache = cache
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc.StartWithStoreConfig(t, stopper, tsc)
+
+	tc.manualClock.Set(48 * 60 * 60 * 1E9) // 2d past the epoch
+	now := tc.Clock().Now().WallTime
+	// The cache was refreshed long before the intent was written, so the GC
+	// threshold is held back far below it.
+	cache.readAt = makeTS(now-2*intentAgeThreshold.Nanoseconds(), 0)
+
+	txn := newTransaction("txn", roachpb.Key("a"), 1, tc.Clock())
+	intentResolveTS := makeTS(now-intentAgeThreshold.Nanoseconds(), 0)
+	txn.OrigTimestamp = intentResolveTS
+	txn.Timestamp = intentResolveTS
+	pArgs := putArgs(roachpb.Key("a"), []byte("value"))
+	assignSeqNumsForReqs(txn, &pArgs)
+	if _, err := tc.SendWrappedWith(roachpb.Header{Txn: txn}, &pArgs); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := tc.gossip.GetSystemConfig()
+	if cfg == nil {
+		t.Fatal("config not set")
+	}
+	gcQ := newGCQueue(tc.store, tc.gossip)
+	if err := gcQ.process(ctx, tc.repl, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		meta := &enginepb.MVCCMetadata{}
+		ok, _, _, err := tc.store.Engine().GetProto(engine.MakeMVCCMetadataKey(roachpb.Key("a")), meta)
+		if err != nil {
+			return err
+		}
+		if ok && meta.Txn != nil {
+			return errors.Errorf("intent on %s not resolved", roachpb.Key("a"))
+		}
+		return nil
+	})
+}
+
 func TestGCQueueLastProcessedTimestamps(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.Background()
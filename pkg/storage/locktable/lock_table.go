@@ -0,0 +1,177 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package locktable provides an in-memory table of the unreplicated locks
+// that locking reads (e.g. those issued by SELECT ... FOR UPDATE) acquire on
+// the keys of a range.
+//
+// Unreplicated locks are only held on the range's leaseholder. They are not
+// persisted and are lost when the lease changes hands or the range splits or
+// merges. This is safe because the locks are only an optimization: they let
+// a transaction that read a key queue conflicting writers behind it instead
+// of having to restart when one of them writes to the key first. Serializable
+// isolation is still provided by the timestamp cache and write intents.
+package locktable
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/google/btree"
+)
+
+// Holder is a transaction holding a lock on a key.
+type Holder struct {
+	Txn      enginepb.TxnMeta
+	Strength roachpb.KeyLockingStrength
+}
+
+// lock is the set of transactions holding a lock on a single key.
+type lock struct {
+	key     roachpb.Key
+	holders []Holder
+}
+
+// Less implements the btree.Item interface.
+func (l *lock) Less(i btree.Item) bool {
+	return l.key.Compare(i.(*lock).key) < 0
+}
+
+// Table is a table of the unreplicated locks held on the keys of a range. It
+// is safe for concurrent use.
+type Table struct {
+	mu struct {
+		syncutil.Mutex
+		locks *btree.BTree
+	}
+}
+
+// New creates an empty lock table.
+func New() *Table {
+	t := &Table{}
+	t.mu.locks = btree.New(16 /* degree */)
+	return t
+}
+
+// Conflicts returns whether a lock held with strength held conflicts with a
+// request of strength req from another transaction. Writes conflict like
+// EXCLUSIVE locking reads.
+func Conflicts(held, req roachpb.KeyLockingStrength) bool {
+	return held == roachpb.EXCLUSIVE || req == roachpb.EXCLUSIVE
+}
+
+// Acquire acquires a lock of the specified strength on the key on behalf of
+// the transaction. If the transaction already holds a lock on the key, the
+// lock is upgraded to the stronger of the two strengths.
+func (t *Table) Acquire(key roachpb.Key, txn *enginepb.TxnMeta, str roachpb.KeyLockingStrength) {
+	if str == roachpb.NON_LOCKING {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var l *lock
+	if i := t.mu.locks.Get(&lock{key: key}); i != nil {
+		l = i.(*lock)
+	} else {
+		l = &lock{key: append(roachpb.Key(nil), key...)}
+		t.mu.locks.ReplaceOrInsert(l)
+	}
+	for i := range l.holders {
+		h := &l.holders[i]
+		if h.Txn.ID != txn.ID {
+			continue
+		}
+		if h.Txn.Epoch <= txn.Epoch {
+			h.Txn = *txn
+		}
+		if str > h.Strength {
+			h.Strength = str
+		}
+		return
+	}
+	l.holders = append(l.holders, Holder{Txn: *txn, Strength: str})
+}
+
+// FindConflicts returns the locks held by transactions other than txnID on
+// keys in the span which conflict with a request of strength str. Each
+// conflicting lock is returned as a pending intent so that the caller can
+// handle it like an intent, i.e. by pushing the transaction holding it. The
+// txnID is the zero UUID for non-transactional requests.
+func (t *Table) FindConflicts(
+	span roachpb.Span, txnID uuid.UUID, str roachpb.KeyLockingStrength,
+) []roachpb.Intent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var intents []roachpb.Intent
+	t.forEachLocked(span, func(l *lock) {
+		for _, h := range l.holders {
+			if h.Txn.ID != txnID && Conflicts(h.Strength, str) {
+				intents = append(intents, roachpb.Intent{
+					Span:   roachpb.Span{Key: l.key},
+					Txn:    h.Txn,
+					Status: roachpb.PENDING,
+				})
+			}
+		}
+	})
+	return intents
+}
+
+// Release releases all locks held by the transaction on keys in the span.
+func (t *Table) Release(span roachpb.Span, txnID uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var empty []*lock
+	t.forEachLocked(span, func(l *lock) {
+		for i := range l.holders {
+			if l.holders[i].Txn.ID == txnID {
+				l.holders = append(l.holders[:i], l.holders[i+1:]...)
+				break
+			}
+		}
+		if len(l.holders) == 0 {
+			empty = append(empty, l)
+		}
+	})
+	// The btree can't be modified while iterating over it.
+	for _, l := range empty {
+		t.mu.locks.Delete(l)
+	}
+}
+
+// Clear releases all locks in the table.
+func (t *Table) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mu.locks.Clear(false /* addNodesToFreelist */)
+}
+
+// Len returns the number of locked keys in the table.
+func (t *Table) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.mu.locks.Len()
+}
+
+// forEachLocked calls fn with each lock on a key in the span. A span without
+// an EndKey only covers its Key. The table's mutex must be held.
+func (t *Table) forEachLocked(span roachpb.Span, fn func(*lock)) {
+	if len(span.EndKey) == 0 {
+		if i := t.mu.locks.Get(&lock{key: span.Key}); i != nil {
+			fn(i.(*lock))
+		}
+		return
+	}
+	t.mu.locks.AscendRange(&lock{key: span.Key}, &lock{key: span.EndKey}, func(i btree.Item) bool {
+		fn(i.(*lock))
+		return true
+	})
+}
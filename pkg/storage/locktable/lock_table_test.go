@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package locktable
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTxn() enginepb.TxnMeta {
+	return enginepb.TxnMeta{ID: uuid.MakeV4(), Key: roachpb.Key("anchor")}
+}
+
+func span(key, endKey string) roachpb.Span {
+	s := roachpb.Span{Key: roachpb.Key(key)}
+	if endKey != "" {
+		s.EndKey = roachpb.Key(endKey)
+	}
+	return s
+}
+
+func conflictKeys(intents []roachpb.Intent) []string {
+	var keys []string
+	for _, intent := range intents {
+		keys = append(keys, string(intent.Key))
+	}
+	return keys
+}
+
+func TestLockTableConflicts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn1, txn2 := makeTxn(), makeTxn()
+	lt := New()
+	lt.Acquire(roachpb.Key("a"), &txn1, roachpb.EXCLUSIVE)
+	lt.Acquire(roachpb.Key("c"), &txn1, roachpb.SHARED)
+	lt.Acquire(roachpb.Key("c"), &txn2, roachpb.SHARED)
+	lt.Acquire(roachpb.Key("e"), &txn2, roachpb.NON_LOCKING)
+	require.Equal(t, 2, lt.Len())
+
+	testCases := []struct {
+		span roachpb.Span
+		txn  uuid.UUID
+		str  roachpb.KeyLockingStrength
+		exp  []string
+	}{
+		// A transaction never conflicts with its own locks.
+		{span("a", "z"), txn1.ID, roachpb.EXCLUSIVE, []string{"c"}},
+		{span("a", ""), txn1.ID, roachpb.EXCLUSIVE, nil},
+		// SHARED locks only conflict with EXCLUSIVE requests.
+		{span("a", "z"), txn2.ID, roachpb.SHARED, []string{"a"}},
+		{span("b", "z"), txn2.ID, roachpb.SHARED, nil},
+		{span("b", "z"), txn2.ID, roachpb.EXCLUSIVE, []string{"c"}},
+		// Non-transactional requests conflict with all holders.
+		{span("a", "z"), uuid.UUID{}, roachpb.EXCLUSIVE, []string{"a", "c", "c"}},
+		{span("c", ""), uuid.UUID{}, roachpb.EXCLUSIVE, []string{"c", "c"}},
+		// The EndKey is exclusive.
+		{span("a", "c"), uuid.UUID{}, roachpb.EXCLUSIVE, []string{"a"}},
+		{span("d", "z"), uuid.UUID{}, roachpb.EXCLUSIVE, nil},
+	}
+	for _, tc := range testCases {
+		intents := lt.FindConflicts(tc.span, tc.txn, tc.str)
+		require.Equal(t, tc.exp, conflictKeys(intents), "%s", tc.span)
+		for _, intent := range intents {
+			require.Equal(t, roachpb.PENDING, intent.Status)
+			require.NotEqual(t, tc.txn, intent.Txn.ID)
+		}
+	}
+}
+
+func TestLockTableUpgrade(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn1, txn2 := makeTxn(), makeTxn()
+	lt := New()
+	lt.Acquire(roachpb.Key("a"), &txn1, roachpb.SHARED)
+	require.Empty(t, lt.FindConflicts(span("a", ""), txn2.ID, roachpb.SHARED))
+
+	// Re-acquiring with a stronger strength upgrades the lock, and re-acquiring
+	// with a weaker strength doesn't downgrade it.
+	lt.Acquire(roachpb.Key("a"), &txn1, roachpb.EXCLUSIVE)
+	lt.Acquire(roachpb.Key("a"), &txn1, roachpb.SHARED)
+	require.Equal(t, []string{"a"}, conflictKeys(lt.FindConflicts(span("a", ""), txn2.ID, roachpb.SHARED)))
+
+	// The holder's TxnMeta is updated to its latest epoch.
+	txn1.Epoch++
+	lt.Acquire(roachpb.Key("a"), &txn1, roachpb.SHARED)
+	intents := lt.FindConflicts(span("a", ""), txn2.ID, roachpb.SHARED)
+	require.Len(t, intents, 1)
+	require.Equal(t, txn1, intents[0].Txn)
+	require.Equal(t, 1, lt.Len())
+}
+
+func TestLockTableRelease(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn1, txn2 := makeTxn(), makeTxn()
+	lt := New()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		lt.Acquire(roachpb.Key(k), &txn1, roachpb.SHARED)
+	}
+	lt.Acquire(roachpb.Key("b"), &txn2, roachpb.SHARED)
+	require.Equal(t, 4, lt.Len())
+
+	// Releasing another transaction's locks is a no-op.
+	lt.Release(span("a", "z"), uuid.MakeV4())
+	require.Equal(t, 4, lt.Len())
+
+	// Keys remain locked as long as another transaction holds a lock on them.
+	lt.Release(span("a", "c"), txn1.ID)
+	require.Equal(t, 3, lt.Len())
+	require.Equal(t, []string{"b", "c", "d"},
+		conflictKeys(lt.FindConflicts(span("a", "z"), uuid.UUID{}, roachpb.EXCLUSIVE)))
+
+	lt.Release(span("d", ""), txn1.ID)
+	require.Equal(t, []string{"b", "c"},
+		conflictKeys(lt.FindConflicts(span("a", "z"), uuid.UUID{}, roachpb.EXCLUSIVE)))
+
+	lt.Clear()
+	require.Equal(t, 0, lt.Len())
+	require.Empty(t, lt.FindConflicts(span("a", "z"), uuid.UUID{}, roachpb.EXCLUSIVE))
+}
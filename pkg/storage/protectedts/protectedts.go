@@ -0,0 +1,107 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package protectedts houses the interfaces and basic definitions used by the
+// protected timestamp subsystem.
+//
+// A protected timestamp record prevents the GC queue from garbage collecting
+// the MVCC history of a set of spans at and above a timestamp, which lets
+// long-running operations like incremental backups, changefeed catch-up scans
+// and AS OF SYSTEM TIME exports outlive the GC TTL of the data they read. The
+// records are stored in the system.protected_ts_records table and are owned by
+// jobs: a job creates its record in a transaction which creates or updates the
+// job, and releases it when it finishes. Records whose job no longer exists or
+// has finished are released by the Reconciler.
+//
+// Records are consulted by the GC queue through the Cache, which periodically
+// polls the records table. The GC queue computes the GC threshold of a range as
+// if the current time were the timestamp at which the Cache was last
+// refreshed, so a record which is unknown to the Cache because it was written
+// after the refresh is respected as long as it commits within the GC TTL of
+// its spans after its timestamp. Protect enforces this with a deadline on the
+// writing transaction, so a record which was written successfully is
+// guaranteed to be respected.
+package protectedts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// ErrNotExists is returned from Release and GetRecord when a record does
+// not exist.
+var ErrNotExists = errors.New("protected timestamp record does not exist")
+
+// ErrExists is returned from Protect when a record with the same ID already
+// exists.
+var ErrExists = errors.New("protected timestamp record already exists")
+
+// Record is a protected timestamp record. It protects the MVCC history of its
+// spans at and above its timestamp from garbage collection.
+type Record struct {
+	// ID uniquely identifies the record.
+	ID uuid.UUID
+	// Timestamp is the timestamp at and above which the MVCC history of the
+	// spans is protected.
+	Timestamp hlc.Timestamp
+	// JobID is the ID of the job which owns the record.
+	JobID int64
+	// Spans are the spans protected by the record.
+	Spans []roachpb.Span
+}
+
+// Provider is the central coordinator of the protected timestamp subsystem.
+// It provides transactional access to the records for clients and an
+// eventually consistent view of them for the GC queue.
+type Provider interface {
+	Storage
+	Cache
+
+	// Start starts the background refresh of the Cache.
+	Start(context.Context, *stop.Stopper) error
+}
+
+// Storage provides transactional access to the protected timestamp records.
+type Storage interface {
+	// Protect writes the record. It returns ErrExists if a record with the
+	// same ID already exists. The transaction is given a deadline of the
+	// record's timestamp plus the smallest GC TTL of its spans, and Protect
+	// fails if the transaction's timestamp is already past it.
+	Protect(ctx context.Context, txn *client.Txn, r *Record) error
+
+	// GetRecord retrieves the record with the specified ID. It returns
+	// ErrNotExists if no such record exists.
+	GetRecord(ctx context.Context, txn *client.Txn, id uuid.UUID) (*Record, error)
+
+	// Release removes the record with the specified ID. It returns
+	// ErrNotExists if no such record exists.
+	Release(ctx context.Context, txn *client.Txn, id uuid.UUID) error
+
+	// GetRecords retrieves all of the records.
+	GetRecords(ctx context.Context, txn *client.Txn) ([]Record, error)
+}
+
+// Cache provides a periodically refreshed view of the protected timestamp
+// records. It is used by the GC queue.
+type Cache interface {
+	// Iterate calls it for every record which protects a span overlapping
+	// [from, to). It returns the timestamp at which the records were read.
+	// The timestamp is empty if the records have not been read yet.
+	Iterate(from, to roachpb.Key, it func(*Record)) (asOf hlc.Timestamp)
+
+	// Refresh reads the records again.
+	Refresh(ctx context.Context) error
+}
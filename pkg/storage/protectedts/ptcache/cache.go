@@ -0,0 +1,115 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ptcache implements protectedts.Cache by periodically polling the
+// protected timestamp records.
+package ptcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Cache implements protectedts.Cache.
+type Cache struct {
+	db       *client.DB
+	storage  protectedts.Storage
+	settings *cluster.Settings
+
+	mu struct {
+		syncutil.RWMutex
+
+		// readAt is the timestamp at which the records were read.
+		readAt  hlc.Timestamp
+		records []protectedts.Record
+	}
+}
+
+var _ protectedts.Cache = (*Cache)(nil)
+
+// New returns a new Cache which reads the records from the provided Storage.
+// The Cache is empty until it is refreshed.
+func New(settings *cluster.Settings, db *client.DB, storage protectedts.Storage) *Cache {
+	return &Cache{
+		db:       db,
+		storage:  storage,
+		settings: settings,
+	}
+}
+
+// Iterate implements protectedts.Cache.
+func (c *Cache) Iterate(from, to roachpb.Key, it func(*protectedts.Record)) hlc.Timestamp {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sp := roachpb.Span{Key: from, EndKey: to}
+	for i := range c.mu.records {
+		r := &c.mu.records[i]
+		for _, rSp := range r.Spans {
+			if sp.Overlaps(rSp) {
+				it(r)
+				break
+			}
+		}
+	}
+	return c.mu.readAt
+}
+
+// Refresh implements protectedts.Cache.
+func (c *Cache) Refresh(ctx context.Context) error {
+	var records []protectedts.Record
+	var readAt hlc.Timestamp
+	if err := c.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		records, err = c.storage.GetRecords(ctx, txn)
+		readAt = txn.OrigTimestamp()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The refreshes are serialized by the poll loop, but a concurrent call to
+	// Refresh may have read the records at a later timestamp.
+	if readAt.Less(c.mu.readAt) {
+		return nil
+	}
+	c.mu.readAt = readAt
+	c.mu.records = records
+	return nil
+}
+
+// Start runs the Cache's poll loop in the background. The Cache is refreshed
+// immediately and then every PollInterval.
+func (c *Cache) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			if err := c.Refresh(ctx); err != nil {
+				log.Warningf(ctx, "failed to refresh protected timestamps: %v", err)
+			}
+			select {
+			case <-time.After(protectedts.PollInterval.Get(&c.settings.SV)):
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
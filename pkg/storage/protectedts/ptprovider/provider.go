@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ptprovider encapsulates the concrete implementation of the
+// protectedts.Provider.
+package ptprovider
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts/ptcache"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts/ptstorage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// Config configures the Provider.
+type Config struct {
+	Settings             *cluster.Settings
+	DB                   *client.DB
+	InternalExecutor     sqlutil.InternalExecutor
+	SystemConfigProvider config.SystemConfigProvider
+}
+
+type provider struct {
+	protectedts.Storage
+	cache *ptcache.Cache
+}
+
+// New creates a new protectedts.Provider.
+func New(cfg Config) protectedts.Provider {
+	storage := ptstorage.New(cfg.InternalExecutor, cfg.SystemConfigProvider)
+	return &provider{
+		Storage: storage,
+		cache:   ptcache.New(cfg.Settings, cfg.DB, storage),
+	}
+}
+
+// Iterate implements protectedts.Cache.
+func (p *provider) Iterate(from, to roachpb.Key, it func(*protectedts.Record)) hlc.Timestamp {
+	return p.cache.Iterate(from, to, it)
+}
+
+// Refresh implements protectedts.Cache.
+func (p *provider) Refresh(ctx context.Context) error {
+	return p.cache.Refresh(ctx)
+}
+
+// Start implements protectedts.Provider.
+func (p *provider) Start(ctx context.Context, stopper *stop.Stopper) error {
+	p.cache.Start(ctx, stopper)
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ptreconcile provides logic to release the protected timestamp
+// records of jobs which no longer exist or have finished.
+package ptreconcile
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// Reconciler periodically releases the protected timestamp records whose job
+// no longer exists in the jobs.Registry or is in a terminal state. A job which
+// exits without releasing its record, for example because its node crashed
+// while it was finishing or because it was canceled from another node, would
+// otherwise keep the record's spans from being garbage collected forever. Only the node which holds the lease on the meta1 range runs the
+// reconciliation, so that at most one node does it at a time.
+type Reconciler struct {
+	settings           *cluster.Settings
+	db                 *client.DB
+	storage            protectedts.Storage
+	registry           *jobs.Registry
+	isMeta1Leaseholder func(hlc.Timestamp) (bool, error)
+	clock              *hlc.Clock
+}
+
+// New initializes the Reconciler with the required arguments, but does not
+// start it.
+func New(
+	settings *cluster.Settings,
+	db *client.DB,
+	storage protectedts.Storage,
+	registry *jobs.Registry,
+	isMeta1Leaseholder func(hlc.Timestamp) (bool, error),
+	clock *hlc.Clock,
+) *Reconciler {
+	return &Reconciler{
+		settings:           settings,
+		db:                 db,
+		storage:            storage,
+		registry:           registry,
+		isMeta1Leaseholder: isMeta1Leaseholder,
+		clock:              clock,
+	}
+}
+
+// reconcile releases the records whose job no longer exists or has finished.
+// Records are expected to be written in a transaction which creates or
+// updates their job, so a record whose job can't be found is guaranteed to be
+// orphaned, and a finished job no longer needs its record.
+func (r *Reconciler) reconcile(ctx context.Context) error {
+	isLeaseholder, err := r.isMeta1Leaseholder(r.clock.Now())
+	if err != nil || !isLeaseholder {
+		return err
+	}
+
+	var records []protectedts.Record
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		records, err = r.storage.GetRecords(ctx, txn)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for i := range records {
+		rec := &records[i]
+		status, err := r.jobStatus(ctx, rec.JobID)
+		if err != nil {
+			return err
+		}
+		if status != "" && !status.Terminal() {
+			continue
+		}
+		log.Infof(ctx, "releasing protected timestamp record %s of %s job %d",
+			rec.ID, statusOrMissing(status), rec.JobID)
+		if err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			return r.storage.Release(ctx, txn, rec.ID)
+		}); err != nil && err != protectedts.ErrNotExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobStatus returns the status of the job, or an empty status if the job does
+// not exist.
+func (r *Reconciler) jobStatus(ctx context.Context, jobID int64) (jobs.Status, error) {
+	job, err := r.registry.LoadJob(ctx, jobID)
+	if jobs.HasJobNotFoundError(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	var status jobs.Status
+	if err := job.Update(ctx, func(_ *client.Txn, md jobs.JobMetadata, _ *jobs.JobUpdater) error {
+		status = md.Status
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func statusOrMissing(status jobs.Status) string {
+	if status == "" {
+		return "missing"
+	}
+	return string(status)
+}
+
+// Start runs the Reconciler in the background.
+func (r *Reconciler) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-time.After(protectedts.ReconciliationInterval.Get(&r.settings.SV)):
+				if err := r.reconcile(ctx); err != nil {
+					log.Warningf(ctx, "failed to reconcile protected timestamp records: %v", err)
+				}
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ptstorage implements protectedts.Storage on top of the
+// system.protected_ts_records table.
+package ptstorage
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	protectQuery = `
+INSERT INTO system.protected_ts_records (id, ts, job_id, spans)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING`
+
+	getRecordQuery = `
+SELECT id, ts, job_id, spans FROM system.protected_ts_records WHERE id = $1`
+
+	getRecordsQuery = `
+SELECT id, ts, job_id, spans FROM system.protected_ts_records`
+
+	releaseQuery = `
+DELETE FROM system.protected_ts_records WHERE id = $1`
+)
+
+// storage implements protectedts.Storage.
+type storage struct {
+	ex  sqlutil.InternalExecutor
+	scp config.SystemConfigProvider
+}
+
+var _ protectedts.Storage = (*storage)(nil)
+
+// New creates a new Storage which uses the provided InternalExecutor to
+// access the records and the provided SystemConfigProvider to look up the
+// GC TTL of their spans.
+func New(ex sqlutil.InternalExecutor, scp config.SystemConfigProvider) protectedts.Storage {
+	return &storage{ex: ex, scp: scp}
+}
+
+// Protect implements protectedts.Storage.
+func (p *storage) Protect(ctx context.Context, txn *client.Txn, r *protectedts.Record) error {
+	if txn == nil {
+		return errors.New("must provide a non-nil transaction")
+	}
+	if err := validateRecord(r); err != nil {
+		return err
+	}
+	// The GC queue doesn't know about the record until its cache is refreshed
+	// after the record commits. Until then, the GC threshold may advance up to
+	// the GC TTL below the commit timestamp, so the record must commit within
+	// the GC TTL of its timestamp.
+	cfg := p.scp.GetSystemConfig()
+	if cfg == nil {
+		return errors.New("system config not yet available")
+	}
+	ttlSeconds, err := minGCTTLSeconds(cfg, r.Spans)
+	if err != nil {
+		return errors.Wrapf(err, "failed to look up GC TTL of record %v", r.ID)
+	}
+	deadline := hlc.Timestamp{WallTime: r.Timestamp.WallTime + int64(ttlSeconds)*1E9}
+	if !txn.OrigTimestamp().Less(deadline) {
+		return errors.Errorf("cannot protect %v: timestamp is older than the GC TTL of %ds",
+			r.Timestamp, ttlSeconds)
+	}
+	txn.UpdateDeadlineMaybe(ctx, deadline)
+	rows, err := p.ex.Exec(ctx, "protectedts-protect", txn, protectQuery,
+		tree.NewDUuid(tree.DUuid{UUID: r.ID}),
+		tree.TimestampToDecimal(r.Timestamp),
+		r.JobID,
+		encodeSpans(r.Spans))
+	if err != nil {
+		return errors.Wrapf(err, "failed to write record %v", r.ID)
+	}
+	if rows == 0 {
+		return protectedts.ErrExists
+	}
+	return nil
+}
+
+// GetRecord implements protectedts.Storage.
+func (p *storage) GetRecord(
+	ctx context.Context, txn *client.Txn, id uuid.UUID,
+) (*protectedts.Record, error) {
+	if txn == nil {
+		return nil, errors.New("must provide a non-nil transaction")
+	}
+	row, err := p.ex.QueryRow(ctx, "protectedts-get-record", txn, getRecordQuery,
+		tree.NewDUuid(tree.DUuid{UUID: id}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read record %v", id)
+	}
+	if row == nil {
+		return nil, protectedts.ErrNotExists
+	}
+	var r protectedts.Record
+	if err := rowToRecord(row, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Release implements protectedts.Storage.
+func (p *storage) Release(ctx context.Context, txn *client.Txn, id uuid.UUID) error {
+	if txn == nil {
+		return errors.New("must provide a non-nil transaction")
+	}
+	rows, err := p.ex.Exec(ctx, "protectedts-release", txn, releaseQuery,
+		tree.NewDUuid(tree.DUuid{UUID: id}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to release record %v", id)
+	}
+	if rows == 0 {
+		return protectedts.ErrNotExists
+	}
+	return nil
+}
+
+// GetRecords implements protectedts.Storage.
+func (p *storage) GetRecords(ctx context.Context, txn *client.Txn) ([]protectedts.Record, error) {
+	if txn == nil {
+		return nil, errors.New("must provide a non-nil transaction")
+	}
+	rows, err := p.ex.Query(ctx, "protectedts-get-records", txn, getRecordsQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read records")
+	}
+	records := make([]protectedts.Record, len(rows))
+	for i, row := range rows {
+		if err := rowToRecord(row, &records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// validateRecord checks that the record can be written.
+func validateRecord(r *protectedts.Record) error {
+	if r.ID == uuid.Nil {
+		return errors.New("invalid nil ID")
+	}
+	if (r.Timestamp == hlc.Timestamp{}) {
+		return errors.New("invalid zero value timestamp")
+	}
+	if len(r.Spans) == 0 {
+		return errors.New("invalid empty set of spans")
+	}
+	for _, sp := range r.Spans {
+		if !sp.Valid() {
+			return errors.Errorf("invalid span %s", sp)
+		}
+	}
+	return nil
+}
+
+// minGCTTLSeconds returns the smallest GC TTL of the zones, including their
+// subzones, which overlap the spans.
+func minGCTTLSeconds(cfg *config.SystemConfig, spans []roachpb.Span) (int32, error) {
+	ttl := int32(math.MaxInt32)
+	update := func(policy *config.GCPolicy) {
+		if policy != nil && policy.TTLSeconds < ttl {
+			ttl = policy.TTLSeconds
+		}
+	}
+	for _, sp := range spans {
+		start, err := keys.Addr(sp.Key)
+		if err != nil {
+			return 0, err
+		}
+		end := start.Next()
+		if len(sp.EndKey) > 0 {
+			if end, err = keys.Addr(sp.EndKey); err != nil {
+				return 0, err
+			}
+		}
+		// Zone configs only change at split keys.
+		for key := start; key != nil; key = cfg.ComputeSplitKey(key, end) {
+			zone, err := cfg.GetZoneConfigForKey(key)
+			if err != nil {
+				return 0, err
+			}
+			update(zone.GC)
+			for i := range zone.Subzones {
+				update(zone.Subzones[i].Config.GC)
+			}
+		}
+	}
+	return ttl, nil
+}
+
+// rowToRecord decodes a row of the records table into r.
+func rowToRecord(row tree.Datums, r *protectedts.Record) error {
+	r.ID = row[0].(*tree.DUuid).UUID
+	ts, err := tree.DecimalToHLC(&row[1].(*tree.DDecimal).Decimal)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse timestamp of record %v", r.ID)
+	}
+	r.Timestamp = ts
+	r.JobID = int64(tree.MustBeDInt(row[2]))
+	spans, err := decodeSpans([]byte(tree.MustBeDBytes(row[3])))
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode spans of record %v", r.ID)
+	}
+	r.Spans = spans
+	return nil
+}
+
+// encodeSpans encodes the spans as a sequence of start and end keys.
+func encodeSpans(spans []roachpb.Span) []byte {
+	var buf []byte
+	for _, sp := range spans {
+		buf = encoding.EncodeBytesAscending(buf, sp.Key)
+		buf = encoding.EncodeBytesAscending(buf, sp.EndKey)
+	}
+	return buf
+}
+
+// decodeSpans decodes spans encoded by encodeSpans.
+func decodeSpans(buf []byte) ([]roachpb.Span, error) {
+	var spans []roachpb.Span
+	for len(buf) > 0 {
+		var sp roachpb.Span
+		var err error
+		if buf, sp.Key, err = encoding.DecodeBytesAscending(buf, nil); err != nil {
+			return nil, err
+		}
+		if buf, sp.EndKey, err = encoding.DecodeBytesAscending(buf, nil); err != nil {
+			return nil, err
+		}
+		if len(sp.EndKey) == 0 {
+			sp.EndKey = nil
+		}
+		spans = append(spans, sp)
+	}
+	return spans, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ptstorage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// TestProtectGCTTL verifies that Protect rejects records whose timestamp is
+// older than the GC TTL of their spans.
+func TestProtectGCTTL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	p := s.ExecutorConfig().(sql.ExecutorConfig).ProtectedTimestampProvider
+
+	sqlDB.Exec(t, `CREATE DATABASE test`)
+	sqlDB.Exec(t, `CREATE TABLE test.t (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `CREATE TABLE test.u (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `ALTER TABLE test.t CONFIGURE ZONE USING gc.ttlseconds = 60`)
+	tableSpan := sqlbase.GetTableDescriptor(kvDB, "test", "t").TableSpan()
+	otherSpan := sqlbase.GetTableDescriptor(kvDB, "test", "u").TableSpan()
+
+	protect := func(ts hlc.Timestamp, sp roachpb.Span) error {
+		r := protectedts.Record{
+			ID:        uuid.MakeV4(),
+			Timestamp: ts,
+			Spans:     []roachpb.Span{sp},
+		}
+		return kvDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			return p.Protect(ctx, txn, &r)
+		})
+	}
+	tenMinutesAgo := s.Clock().Now().Add(-(10 * time.Minute).Nanoseconds(), 0)
+
+	// The default GC TTL covers the timestamp.
+	testutils.SucceedsSoon(t, func() error {
+		return protect(tenMinutesAgo, otherSpan)
+	})
+	if err := protect(s.Clock().Now().Add(-(26 * time.Hour).Nanoseconds(), 0), otherSpan); !testutils.IsError(
+		err, "timestamp is older than the GC TTL",
+	) {
+		t.Fatalf("expected the record to be rejected, got %v", err)
+	}
+	// The zone config of the table only allows protecting the last minute.
+	testutils.SucceedsSoon(t, func() error {
+		if err := protect(tenMinutesAgo, tableSpan); !testutils.IsError(
+			err, "timestamp is older than the GC TTL of 60s",
+		) {
+			return errors.Errorf("expected the record to be rejected, got %v", err)
+		}
+		return nil
+	})
+	if err := protect(s.Clock().Now(), tableSpan); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package protectedts
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/pkg/errors"
+)
+
+// PollInterval is the interval at which the Cache reads the protected
+// timestamp records.
+var PollInterval = settings.RegisterNonNegativeDurationSetting(
+	"kv.protectedts.poll_interval",
+	"the interval at which the protected timestamp records are polled",
+	2*time.Minute,
+)
+
+// ReconciliationInterval is the interval at which the Reconciler looks for
+// protected timestamp records whose job no longer exists or has finished.
+var ReconciliationInterval = settings.RegisterValidatedDurationSetting(
+	"kv.protectedts.reconciliation.interval",
+	"the interval at which protected timestamp records of missing or finished jobs are released",
+	5*time.Minute,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("kv.protectedts.reconciliation.interval must be positive: %s", v)
+		}
+		return nil
+	},
+)
@@ -13,6 +13,7 @@ package rangefeed
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -117,6 +118,12 @@ type Processor struct {
 	eventC   chan event
 	stopC    chan *roachpb.Error
 	stoppedC chan struct{}
+
+	// diffRegs is the number of registrations that asked for the previous
+	// values of keys. It is incremented before a registration is handed to the
+	// Processor goroutine, so that NeedPrevVal reflects the registration by
+	// the time Register returns. Accessed atomically.
+	diffRegs int32
 }
 
 // event is a union of different event types that the Processor goroutine needs
@@ -224,13 +231,13 @@ func (p *Processor) Start(stopper *stop.Stopper, rtsIter engine.SimpleIterator)
 						r.catchupIter.Close() // clean up
 					}
 					r.disconnect(roachpb.NewError(err))
-					p.reg.Unregister(&r)
+					p.unregister(&r)
 				}
 
 			// Respond to unregistration requests; these come from registrations that
 			// encounter an error during their output loop.
 			case r := <-p.unregC:
-				p.reg.Unregister(r)
+				p.unregister(r)
 
 			// Respond to answers about the processor goroutine state.
 			case <-p.lenReqC:
@@ -334,7 +341,14 @@ func (p *Processor) sendStop(pErr *roachpb.Error) {
 // provided an error when the registration closes.
 //
 // The optionally provided "catch-up" iterator is used to read changes from the
-// engine which occurred after the provided start timestamp.
+// engine which occurred after the provided start timestamp. The range
+// tombstones provided along with it are the MVCC range tombstones of the
+// range at the time the iterator was created; those newer than the start
+// timestamp are output as part of the catch-up scan.
+//
+// If withDiff is set, the RangeFeedValue events sent to the stream carry the
+// previous value of each key. Callers that populate the logical ops passed to
+// ConsumeLogicalOps must consult NeedPrevVal to learn whether to read them.
 //
 // If the method returns false, the processor will have been stopped, so calling
 // Stop is not necessary.
@@ -344,6 +358,8 @@ func (p *Processor) Register(
 	span roachpb.RSpan,
 	startTS hlc.Timestamp,
 	catchupIter engine.SimpleIterator,
+	catchupRangeTombstones []enginepb.MVCCRangeTombstone,
+	withDiff bool,
 	stream Stream,
 	errC chan<- *roachpb.Error,
 ) bool {
@@ -353,9 +369,12 @@ func (p *Processor) Register(
 	p.syncEventC()
 
 	r := newRegistration(
-		span.AsRawSpanWithNoLocals(), startTS, catchupIter, p.Config.EventChanCap,
-		p.Metrics, stream, errC,
+		span.AsRawSpanWithNoLocals(), startTS, catchupIter, catchupRangeTombstones, withDiff,
+		p.Config.EventChanCap, p.Metrics, stream, errC,
 	)
+	if withDiff {
+		atomic.AddInt32(&p.diffRegs, 1)
+	}
 	select {
 	case p.regC <- r:
 		return true
@@ -364,6 +383,26 @@ func (p *Processor) Register(
 	}
 }
 
+// unregister removes the registration from the registry. Must only be called
+// from the Processor goroutine.
+func (p *Processor) unregister(r *registration) {
+	p.reg.Unregister(r)
+	if r.withDiff {
+		atomic.AddInt32(&p.diffRegs, -1)
+	}
+}
+
+// NeedPrevVal returns whether any registration attached to the processor asked
+// for the previous values of keys, in which case the MVCCWriteValueOp and
+// MVCCCommitIntentOp operations passed to ConsumeLogicalOps must have their
+// PrevValue populated. Safe to call on nil Processor.
+func (p *Processor) NeedPrevVal() bool {
+	if p == nil {
+		return false
+	}
+	return atomic.LoadInt32(&p.diffRegs) > 0
+}
+
 // Len returns the number of registrations attached to the processor.
 func (p *Processor) Len() int {
 	if p == nil {
@@ -496,7 +535,7 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 		switch t := op.GetValue().(type) {
 		case *enginepb.MVCCWriteValueOp:
 			// Publish the new value directly.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value)
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue)
 
 		case *enginepb.MVCCWriteIntentOp:
 			// No updates to publish.
@@ -506,7 +545,7 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 
 		case *enginepb.MVCCCommitIntentOp:
 			// Publish the newly committed value.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value)
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue)
 
 		case *enginepb.MVCCAbortIntentOp:
 			// No updates to publish.
@@ -514,6 +553,10 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 		case *enginepb.MVCCAbortTxnOp:
 			// No updates to publish.
 
+		case *enginepb.MVCCDeleteRangeOp:
+			// Publish the range deletion directly.
+			p.publishDeleteRange(ctx, t.StartKey, t.EndKey, t.Timestamp)
+
 		default:
 			panic(fmt.Sprintf("unknown logical op %T", t))
 		}
@@ -539,7 +582,7 @@ func (p *Processor) initResolvedTS(ctx context.Context) {
 }
 
 func (p *Processor) publishValue(
-	ctx context.Context, key roachpb.Key, timestamp hlc.Timestamp, value []byte,
+	ctx context.Context, key roachpb.Key, timestamp hlc.Timestamp, value, prevValue []byte,
 ) {
 	if !p.Span.ContainsKey(roachpb.RKey(key)) {
 		log.Fatalf(ctx, "key %v not in Processor's key range %v", key, p.Span)
@@ -553,6 +596,25 @@ func (p *Processor) publishValue(
 			RawBytes:  value,
 			Timestamp: timestamp,
 		},
+		PrevValue: roachpb.Value{
+			RawBytes: prevValue,
+		},
+	})
+	p.reg.PublishToOverlapping(span, &event)
+}
+
+func (p *Processor) publishDeleteRange(
+	ctx context.Context, startKey, endKey roachpb.Key, timestamp hlc.Timestamp,
+) {
+	span := roachpb.Span{Key: startKey, EndKey: endKey}
+	if !p.Span.ContainsKeyRange(roachpb.RKey(startKey), roachpb.RKey(endKey)) {
+		log.Fatalf(ctx, "span %s not in Processor's key range %v", span, p.Span)
+	}
+
+	var event roachpb.RangeFeedEvent
+	event.MustSetValue(&roachpb.RangeFeedDeleteRange{
+		Span:      span,
+		Timestamp: timestamp,
 	})
 	p.reg.PublishToOverlapping(span, &event)
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,6 +48,17 @@ func writeValueOpWithKV(key roachpb.Key, ts hlc.Timestamp, val []byte) enginepb.
 	})
 }
 
+func writeValueOpWithPrevValue(
+	key roachpb.Key, ts hlc.Timestamp, val, prevValue []byte,
+) enginepb.MVCCLogicalOp {
+	return makeLogicalOp(&enginepb.MVCCWriteValueOp{
+		Key:       key,
+		Timestamp: ts,
+		Value:     val,
+		PrevValue: prevValue,
+	})
+}
+
 func writeValueOp(ts hlc.Timestamp) enginepb.MVCCLogicalOp {
 	return writeValueOpWithKV(roachpb.Key("a"), ts, nil /* val */)
 }
@@ -117,6 +129,16 @@ func rangeFeedValue(key roachpb.Key, val roachpb.Value) *roachpb.RangeFeedEvent
 	})
 }
 
+func rangeFeedValueWithPrev(
+	key roachpb.Key, val roachpb.Value, prevValue []byte,
+) *roachpb.RangeFeedEvent {
+	return makeRangeFeedEvent(&roachpb.RangeFeedValue{
+		Key:       key,
+		Value:     val,
+		PrevValue: roachpb.Value{RawBytes: prevValue},
+	})
+}
+
 func rangeFeedCheckpoint(span roachpb.Span, ts hlc.Timestamp) *roachpb.RangeFeedEvent {
 	return makeRangeFeedEvent(&roachpb.RangeFeedCheckpoint{
 		Span:       span,
@@ -186,7 +208,9 @@ func TestProcessorBasic(t *testing.T) {
 	r1OK := p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r1Stream,
 		r1ErrC,
 	)
@@ -301,7 +325,9 @@ func TestProcessorBasic(t *testing.T) {
 	r2OK := p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("c"), EndKey: roachpb.RKey("z")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r2Stream,
 		r2ErrC,
 	)
@@ -371,7 +397,9 @@ func TestProcessorBasic(t *testing.T) {
 	r3OK := p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("c"), EndKey: roachpb.RKey("z")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r3Stream,
 		r3ErrC,
 	)
@@ -394,7 +422,68 @@ func TestNilProcessor(t *testing.T) {
 	// The following should panic because they are not safe
 	// to call on a nil Processor.
 	require.Panics(t, func() { p.Start(stop.NewStopper(), nil) })
-	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, nil, nil) })
+	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, nil, false, nil, nil) })
+}
+
+func TestProcessorWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	p, stopper := newTestProcessor(nil /* rtsIter */)
+	defer stopper.Stop(context.Background())
+	require.False(t, p.NeedPrevVal())
+
+	// Add a registration with diff and another without.
+	r1Stream := newTestStream()
+	r1ErrC := make(chan *roachpb.Error, 1)
+	p.Register(
+		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
+		hlc.Timestamp{WallTime: 1},
+		nil,  /* catchUpIter */
+		nil,  /* catchUpRangeTombstones */
+		true, /* withDiff */
+		r1Stream,
+		r1ErrC,
+	)
+	require.True(t, p.NeedPrevVal())
+	r2Stream := newTestStream()
+	r2ErrC := make(chan *roachpb.Error, 1)
+	p.Register(
+		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
+		hlc.Timestamp{WallTime: 1},
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
+		r2Stream,
+		r2ErrC,
+	)
+	p.syncEventAndRegistrations()
+	r1Stream.Events()
+	r2Stream.Events()
+
+	// Only the registration with diff sees the previous value.
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 5}}
+	p.ConsumeLogicalOps(
+		writeValueOpWithPrevValue(roachpb.Key("c"), val.Timestamp, val.RawBytes, []byte("prev")),
+	)
+	p.syncEventAndRegistrations()
+	require.Equal(t,
+		[]*roachpb.RangeFeedEvent{rangeFeedValueWithPrev(roachpb.Key("c"), val, []byte("prev"))},
+		r1Stream.Events(),
+	)
+	require.Equal(t,
+		[]*roachpb.RangeFeedEvent{rangeFeedValue(roachpb.Key("c"), val)},
+		r2Stream.Events(),
+	)
+
+	// Once the registration with diff goes away, previous values are no longer
+	// needed.
+	r1Stream.Cancel()
+	require.NotNil(t, <-r1ErrC)
+	testutils.SucceedsSoon(t, func() error {
+		if p.NeedPrevVal() {
+			return errors.New("expected previous values to no longer be needed")
+		}
+		return nil
+	})
 }
 
 func TestProcessorSlowConsumer(t *testing.T) {
@@ -411,7 +500,9 @@ func TestProcessorSlowConsumer(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r1Stream,
 		r1ErrC,
 	)
@@ -420,7 +511,9 @@ func TestProcessorSlowConsumer(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("z")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r2Stream,
 		r2ErrC,
 	)
@@ -539,7 +632,9 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		nil,   /* catchUpRangeTombstones */
+		false, /* withDiff */
 		r1Stream,
 		make(chan *roachpb.Error, 1),
 	)
@@ -783,7 +878,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 			runtime.Gosched()
 			s := newTestStream()
 			errC := make(chan<- *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, nil, false /* withDiff */, s, errC)
 		}()
 		go func() {
 			defer wg.Done()
@@ -852,7 +947,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 			s := newTestStream()
 			regs[s] = firstIdx
 			errC := make(chan *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, nil, false /* withDiff */, s, errC)
 			regDone <- struct{}{}
 		}
 	}()
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -53,10 +54,12 @@ type Stream interface {
 // has finished.
 type registration struct {
 	// Input.
-	span             roachpb.Span
-	catchupIter      engine.SimpleIterator
-	catchupTimestamp hlc.Timestamp
-	metrics          *Metrics
+	span                   roachpb.Span
+	catchupIter            engine.SimpleIterator
+	catchupRangeTombstones []enginepb.MVCCRangeTombstone
+	catchupTimestamp       hlc.Timestamp
+	withDiff               bool
+	metrics                *Metrics
 
 	// Output.
 	stream Stream
@@ -86,19 +89,23 @@ func newRegistration(
 	span roachpb.Span,
 	startTS hlc.Timestamp,
 	catchupIter engine.SimpleIterator,
+	catchupRangeTombstones []enginepb.MVCCRangeTombstone,
+	withDiff bool,
 	bufferSz int,
 	metrics *Metrics,
 	stream Stream,
 	errC chan<- *roachpb.Error,
 ) registration {
 	r := registration{
-		span:             span,
-		catchupIter:      catchupIter,
-		metrics:          metrics,
-		stream:           stream,
-		errC:             errC,
-		buf:              make(chan *roachpb.RangeFeedEvent, bufferSz),
-		catchupTimestamp: startTS,
+		span:                   span,
+		catchupIter:            catchupIter,
+		catchupRangeTombstones: catchupRangeTombstones,
+		withDiff:               withDiff,
+		metrics:                metrics,
+		stream:                 stream,
+		errC:                   errC,
+		buf:                    make(chan *roachpb.RangeFeedEvent, bufferSz),
+		catchupTimestamp:       startTS,
 	}
 	r.mu.Locker = &syncutil.Mutex{}
 	r.mu.caughtUp = true
@@ -111,6 +118,7 @@ func newRegistration(
 // If overflowed is already set, events are ignored and not written to the
 // buffer.
 func (r *registration) publish(event *roachpb.RangeFeedEvent) {
+	event = r.maybeStripEvent(event)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.mu.overflowed {
@@ -126,6 +134,21 @@ func (r *registration) publish(event *roachpb.RangeFeedEvent) {
 	}
 }
 
+// maybeStripEvent returns the event without the information that the
+// registration did not ask for. The event is shared with other registrations,
+// so it is copied before being modified.
+func (r *registration) maybeStripEvent(event *roachpb.RangeFeedEvent) *roachpb.RangeFeedEvent {
+	t, ok := event.GetValue().(*roachpb.RangeFeedValue)
+	if !ok || r.withDiff || !t.PrevValue.IsPresent() {
+		return event
+	}
+	valCopy := *t
+	valCopy.PrevValue = roachpb.Value{}
+	var ret roachpb.RangeFeedEvent
+	ret.MustSetValue(&valCopy)
+	return &ret
+}
+
 // disconnect cancels the output loop context for the registration and passes an
 // error to the output error stream for the registration. This also sets the
 // disconnected flag on the registration, preventing it from being disconnected
@@ -201,7 +224,9 @@ func (r *registration) runOutputLoop(ctx context.Context) {
 // runCatchupScan starts a catchup scan which will output entries for all
 // recorded changes in the replica that are newer than the catchupTimeStamp.
 // This uses the iterator provided when the registration was originally created;
-// after the scan completes, the iterator will be closed.
+// after the scan completes, the iterator will be closed. The range tombstones
+// provided along with the iterator are output after all values, in timestamp
+// order.
 func (r *registration) runCatchupScan() error {
 	if r.catchupIter == nil {
 		return nil
@@ -220,9 +245,13 @@ func (r *registration) runCatchupScan() error {
 	// Iterator will encounter historical values for each key in
 	// reverse-chronological order. To output in chronological order, store
 	// events for the same key until a different key is encountered, then output
-	// the encountered values in reverse.
+	// the encountered values in reverse. If the registration asked for previous
+	// values, each version encountered is the previous value of the event
+	// before it in reorderBuf, including the first version at or before the
+	// registration's starting timestamp.
 	reorderBuf := make([]roachpb.RangeFeedEvent, 0, 5)
 	var lastKey []byte
+	needPrevVal := false
 	outputEvents := func() error {
 		for i := len(reorderBuf) - 1; i >= 0; i-- {
 			e := reorderBuf[i]
@@ -272,23 +301,33 @@ func (r *registration) runCatchupScan() error {
 			unsafeVal = meta.RawBytes
 		} else if !r.catchupTimestamp.Less(unsafeKey.Timestamp) {
 			// At or before the registration's exclusive starting timestamp.
-			// Ignore.
+			// Ignore, unless this is the previous value of the oldest event
+			// for the key.
+			if needPrevVal && bytes.Equal(unsafeKey.Key, lastKey) {
+				setPrevValue(&reorderBuf[len(reorderBuf)-1], unsafeVal, &a)
+				needPrevVal = false
+			}
 			continue
 		}
 
-		var key, val []byte
-		a, key = a.Copy(unsafeKey.Key, 0)
-		a, val = a.Copy(unsafeVal, 0)
-		ts := unsafeKey.Timestamp
-
 		// Output values in order
-		if !bytes.Equal(key, lastKey) {
+		if !bytes.Equal(unsafeKey.Key, lastKey) {
 			if err := outputEvents(); err != nil {
 				return err
 			}
-			lastKey = key
+			needPrevVal = false
+		} else if needPrevVal {
+			setPrevValue(&reorderBuf[len(reorderBuf)-1], unsafeVal, &a)
 		}
 
+		var key, val []byte
+		a, key = a.Copy(unsafeKey.Key, 0)
+		a, val = a.Copy(unsafeVal, 0)
+		ts := unsafeKey.Timestamp
+		lastKey = key
+		// Inline values have no history, so they have no previous value.
+		needPrevVal = r.withDiff && unsafeKey.IsValue()
+
 		var event roachpb.RangeFeedEvent
 		event.MustSetValue(&roachpb.RangeFeedValue{
 			Key: key,
@@ -301,7 +340,56 @@ func (r *registration) runCatchupScan() error {
 	}
 
 	// Output events for the last key encountered.
-	return outputEvents()
+	if err := outputEvents(); err != nil {
+		return err
+	}
+	return r.outputCatchupRangeTombstones()
+}
+
+// outputCatchupRangeTombstones outputs a RangeFeedDeleteRange event for each
+// of the registration's catch-up range tombstones that is newer than the
+// catchupTimestamp, clipped to the registration's span.
+func (r *registration) outputCatchupRangeTombstones() error {
+	tombstones := append([]enginepb.MVCCRangeTombstone(nil), r.catchupRangeTombstones...)
+	sort.Slice(tombstones, func(i, j int) bool {
+		return tombstones[i].Timestamp.Less(tombstones[j].Timestamp)
+	})
+	for _, t := range tombstones {
+		if !r.catchupTimestamp.Less(t.Timestamp) {
+			continue
+		}
+		span := roachpb.Span{Key: t.StartKey, EndKey: t.EndKey}
+		if bytes.Compare(span.Key, r.span.Key) < 0 {
+			span.Key = r.span.Key
+		}
+		if bytes.Compare(r.span.EndKey, span.EndKey) < 0 {
+			span.EndKey = r.span.EndKey
+		}
+		if bytes.Compare(span.Key, span.EndKey) >= 0 {
+			continue
+		}
+		var event roachpb.RangeFeedEvent
+		event.MustSetValue(&roachpb.RangeFeedDeleteRange{
+			Span:      span,
+			Timestamp: t.Timestamp,
+		})
+		if err := r.stream.Send(&event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPrevValue sets the previous value of the given catch-up scan event. The
+// value is copied using the provided allocator. Deletion tombstones are not
+// set, which matches the previous values of live events.
+func setPrevValue(event *roachpb.RangeFeedEvent, prevVal []byte, a *bufalloc.ByteAllocator) {
+	if len(prevVal) == 0 {
+		return
+	}
+	var val []byte
+	*a, val = a.Copy(prevVal, 0)
+	event.GetValue().(*roachpb.RangeFeedValue).PrevValue.RawBytes = val
 }
 
 // ID implements interval.Interface.
@@ -360,6 +448,10 @@ func (reg *registry) PublishToOverlapping(span roachpb.Span, event *roachpb.Rang
 		// Only publish values to registrations with starting
 		// timestamps equal to or greater than the value's timestamp.
 		minTS = t.Value.Timestamp
+	case *roachpb.RangeFeedDeleteRange:
+		// Only publish range deletions to registrations with starting
+		// timestamps equal to or greater than the deletion's timestamp.
+		minTS = t.Timestamp
 	case *roachpb.RangeFeedCheckpoint:
 		// Always publish checkpoint notifications, regardless of a registration's
 		// starting timestamp.
@@ -204,6 +204,10 @@ func (rts *resolvedTimestamp) consumeLogicalOp(op enginepb.MVCCLogicalOp) bool {
 		}
 		return rts.intentQ.Del(t.TxnID)
 
+	case *enginepb.MVCCDeleteRangeOp:
+		rts.assertOpAboveRTS(op, t.Timestamp)
+		return false
+
 	default:
 		panic(fmt.Sprintf("unknown logical op %T", t))
 	}
@@ -11,6 +11,8 @@
 package rditer
 
 import (
+	"context"
+
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
@@ -18,7 +20,8 @@ import (
 
 // ComputeStatsForRange computes the stats for a given range by
 // iterating over all key ranges for the given range that should
-// be accounted for in its stats.
+// be accounted for in its stats, taking into account the MVCC range
+// tombstones of the range.
 func ComputeStatsForRange(
 	d *roachpb.RangeDescriptor, e engine.Reader, nowNanos int64,
 ) (enginepb.MVCCStats, error) {
@@ -33,5 +36,29 @@ func ComputeStatsForRange(
 		}
 		ms.Add(msDelta)
 	}
+	msDelta, err := ComputeRangeTombstoneStats(context.TODO(), d, e, nowNanos)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	ms.Add(msDelta)
 	return ms, nil
 }
+
+// ComputeRangeTombstoneStats returns the adjustment to the stats of the given
+// range computed without regard to its MVCC range tombstones. See
+// engine.ComputeRangeTombstoneStats.
+func ComputeRangeTombstoneStats(
+	ctx context.Context,
+	d *roachpb.RangeDescriptor,
+	e engine.Reader,
+	nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	start, end := d.StartKey.AsRawKey(), d.EndKey.AsRawKey()
+	tombstones, err := engine.MVCCScanRangeTombstones(ctx, e, d.RangeID, start, end)
+	if err != nil || len(tombstones) == 0 {
+		return enginepb.MVCCStats{}, err
+	}
+	iter := e.NewIterator(engine.IterOptions{UpperBound: end})
+	defer iter.Close()
+	return engine.ComputeRangeTombstoneStats(iter, tombstones, start, end, nowNanos)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/locktable"
 	"github.com/cockroachdb/cockroach/pkg/storage/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanlatch"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
@@ -157,6 +158,7 @@ type Replica struct {
 	store        *Store
 	abortSpan    *abortspan.AbortSpan // Avoids anomalous reads after abort
 	txnWaitQueue *txnwait.Queue       // Queues push txn attempts by txn ID
+	lockTable    *locktable.Table     // Unreplicated locks held on the leaseholder
 
 	// leaseholderStats tracks all incoming BatchRequests to the replica and which
 	// localities they come from in order to aid in lease rebalancing decisions.
@@ -708,6 +710,11 @@ func (r *Replica) GetTxnWaitQueue() *txnwait.Queue {
 	return r.txnWaitQueue
 }
 
+// GetLockTable returns the Replica's locktable.Table.
+func (r *Replica) GetLockTable() *locktable.Table {
+	return r.lockTable
+}
+
 // GetTerm returns the term of the given index in the raft log.
 func (r *Replica) GetTerm(i uint64) (uint64, error) {
 	r.mu.RLock()
@@ -1008,6 +1015,9 @@ func (r *Replica) checkBatchRequest(ba *roachpb.BatchRequest, isReadOnly bool) e
 		return errors.New("Replica.checkBatchRequest: batch does not have timestamp assigned")
 	}
 	consistent := ba.ReadConsistency == roachpb.CONSISTENT
+	if ba.BoundedStaleness != nil && (!isReadOnly || !consistent || ba.Txn != nil) {
+		return errors.New("bounded staleness reads must be consistent, read-only and non-transactional")
+	}
 	if isReadOnly {
 		if !consistent && ba.Txn != nil {
 			// Disallow any inconsistent reads within txns.
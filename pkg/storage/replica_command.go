@@ -848,13 +848,20 @@ func (r *Replica) ChangeReplicas(
 	reason storagepb.RangeLogEventReason,
 	details string,
 ) (updatedDesc *roachpb.RangeDescriptor, _ error) {
-	return r.changeReplicas(ctx, changeType, target, desc, SnapshotRequest_REBALANCE, reason, details)
+	return r.changeReplicas(
+		ctx, changeType, target, roachpb.ReplicaType_VOTER, desc, SnapshotRequest_REBALANCE, reason, details,
+	)
 }
 
+// changeReplicas is like ChangeReplicas, but additionally allows specifying
+// the type of an added replica and the priority of its preemptive snapshot.
+// The replica type is ignored when removing a replica. Adding a voter where
+// there is a non-voting replica promotes it.
 func (r *Replica) changeReplicas(
 	ctx context.Context,
 	changeType roachpb.ReplicaChangeType,
 	target roachpb.ReplicationTarget,
+	replicaType roachpb.ReplicaType,
 	desc *roachpb.RangeDescriptor,
 	priority SnapshotRequest_Priority,
 	reason storagepb.RangeLogEventReason,
@@ -892,6 +899,17 @@ func (r *Replica) changeReplicas(
 
 	switch changeType {
 	case roachpb.ADD_REPLICA:
+		// Adding a voter on the store of a non-voting replica promotes it. The
+		// replica already has the data of the range, so no snapshot is sent, and
+		// raft turns the learner into a voter.
+		if repDescIdx != -1 && replicaType == roachpb.ReplicaType_VOTER &&
+			repDesc.GetType() == roachpb.ReplicaType_NON_VOTER {
+			updatedDesc.RemoveReplica(repDesc.NodeID, repDesc.StoreID)
+			repDesc.Type = nil
+			updatedDesc.AddReplica(repDesc)
+			break
+		}
+
 		// If the replica exists on the remote node, no matter in which store,
 		// abort the replica add.
 		if nodeUsed {
@@ -900,6 +918,17 @@ func (r *Replica) changeReplicas(
 			}
 			return nil, errors.Errorf("%s: unable to add replica %v; node already has a replica", r, repDesc)
 		}
+		switch replicaType {
+		case roachpb.ReplicaType_VOTER:
+		case roachpb.ReplicaType_NON_VOTER:
+			if !r.store.ClusterSettings().Version.IsActive(cluster.VersionNonVoterReplicas) {
+				return nil, errors.Errorf("%s: unable to add non-voting replica %v "+
+					"until the cluster version is upgraded", r, repDesc)
+			}
+			repDesc.Type = &replicaType
+		default:
+			return nil, errors.Errorf("%s: unable to add replica %v of type %s", r, repDesc, replicaType)
+		}
 
 		// Send a pre-emptive snapshot. Note that the replica to which this
 		// snapshot is addressed has not yet had its replica ID initialized; this
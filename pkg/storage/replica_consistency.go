@@ -510,6 +510,11 @@ func (r *Replica) sha512(
 			}
 			ms.Add(spanMS)
 		}
+		tombstoneMS, err := rditer.ComputeRangeTombstoneStats(ctx, &desc, snap, 0 /* nowNanos */)
+		if err != nil {
+			return nil, err
+		}
+		ms.Add(tombstoneMS)
 	}
 
 	var result replicaHash
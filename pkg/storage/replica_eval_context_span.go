@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/locktable"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
@@ -78,6 +79,11 @@ func (rec *SpanSetReplicaEvalContext) GetTxnWaitQueue() *txnwait.Queue {
 	return rec.i.GetTxnWaitQueue()
 }
 
+// GetLockTable returns the locktable.Table.
+func (rec *SpanSetReplicaEvalContext) GetLockTable() *locktable.Table {
+	return rec.i.GetLockTable()
+}
+
 // NodeID returns the NodeID.
 func (rec *SpanSetReplicaEvalContext) NodeID() roachpb.NodeID {
 	return rec.i.NodeID()
@@ -80,6 +80,40 @@ func (r *Replica) canServeFollowerRead(
 	return nil
 }
 
+// setBoundedStalenessTimestamp chooses the timestamp at which the bounded
+// staleness read in the batch is evaluated and assigns it to the batch. The
+// leaseholder serves the read at the present time. Other replicas serve it at
+// their closed timestamp if that satisfies the batch's minimum timestamp bound,
+// and otherwise return a NotLeaseHolderError so that the DistSender falls back
+// to the leaseholder.
+func (r *Replica) setBoundedStalenessTimestamp(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) *roachpb.Error {
+	minBound := ba.BoundedStaleness.MinTimestampBound
+	if _, pErr := r.redirectOnOrAcquireLease(ctx); pErr != nil {
+		lErr, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError)
+		if !ok || lErr.LeaseHolder == nil || lErr.Lease.Type() != roachpb.LeaseEpoch ||
+			!FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+			return pErr
+		}
+		maxClosed := r.maxClosed(ctx)
+		if maxClosed.Less(minBound) {
+			// Signal the clients that we want an update so that future requests
+			// can be served locally.
+			r.store.cfg.ClosedTimestamp.Clients.Request(lErr.LeaseHolder.NodeID, r.RangeID)
+			return pErr
+		}
+		log.Eventf(ctx, "serving bounded staleness read via follower read at %s", maxClosed)
+		r.store.metrics.FollowerReadsCount.Inc(1)
+		ba.Timestamp = maxClosed
+		return nil
+	}
+	ba.Timestamp = r.store.Clock().Now()
+	ba.Timestamp.Forward(minBound)
+	log.Eventf(ctx, "serving bounded staleness read on leaseholder at %s", ba.Timestamp)
+	return nil
+}
+
 // maxClosed returns the maximum closed timestamp for this range.
 // It is computed as the most recent of the known closed timestamp for the
 // current lease holder for this range as tracked by the closed timestamp
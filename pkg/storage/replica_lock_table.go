@@ -0,0 +1,74 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// checkLockConflicts returns a WriteIntentError if a write or locking read in
+// the batch conflicts with an unreplicated lock held by another transaction.
+// The conflicting locks are reported as intents so that the store pushes the
+// transactions holding them like it would for any other intent. The method
+// must be called while holding latches and the lease.
+func (r *Replica) checkLockConflicts(ba *roachpb.BatchRequest) *roachpb.Error {
+	if r.lockTable.Len() == 0 {
+		return nil
+	}
+	var txnID uuid.UUID
+	if ba.Txn != nil {
+		txnID = ba.Txn.ID
+	}
+	for i, union := range ba.Requests {
+		req := union.GetInner()
+		var str roachpb.KeyLockingStrength
+		switch {
+		case roachpb.IsTransactionWrite(req):
+			str = roachpb.EXCLUSIVE
+		case roachpb.IsLocking(req):
+			if ba.WaitPolicy == roachpb.LOCK_WAIT_SKIP {
+				// Locking reads which skip locked keys filter them out during
+				// evaluation instead.
+				continue
+			}
+			str = req.(roachpb.LockingReadRequest).KeyLockingStrength()
+		default:
+			continue
+		}
+		if intents := r.lockTable.FindConflicts(req.Header().Span(), txnID, str); len(intents) > 0 {
+			pErr := roachpb.NewError(&roachpb.WriteIntentError{Intents: intents})
+			pErr.SetErrorIndex(int32(i))
+			return pErr
+		}
+	}
+	return nil
+}
+
+// acquireLocks adds the unreplicated locks acquired by locking reads to the
+// lock table.
+func (r *Replica) acquireLocks(locks []result.AcquiredLocks) {
+	for i := range locks {
+		l := &locks[i]
+		for _, key := range l.Keys {
+			r.lockTable.Acquire(key, &l.Txn, l.Strength)
+		}
+	}
+}
+
+// releaseLocks releases the unreplicated locks held by the finalized
+// transactions of the intents in the intents' spans.
+func (r *Replica) releaseLocks(intents []roachpb.Intent) {
+	for _, intent := range intents {
+		r.lockTable.Release(intent.Span, intent.Txn.ID)
+	}
+}
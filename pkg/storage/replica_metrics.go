@@ -117,7 +117,7 @@ func calcReplicaMetrics(
 	m.Ticking = ticking
 
 	m.RangeCounter, m.Unavailable, m.Underreplicated, m.Overreplicated =
-		calcRangeCounter(storeID, desc, livenessMap, *zone.NumReplicas, clusterNodes)
+		calcRangeCounter(storeID, desc, livenessMap, zone.GetNumVoters(), zone.GetNumNonVoters(), clusterNodes)
 
 	// The raft leader computes the number of raft entries that replicas are
 	// behind.
@@ -153,7 +153,7 @@ func calcRangeCounter(
 	storeID roachpb.StoreID,
 	desc *roachpb.RangeDescriptor,
 	livenessMap IsLiveMap,
-	numReplicas int32,
+	numVoters, numNonVoters int32,
 	clusterNodes int,
 ) (rangeCounter, unavailable, underreplicated, overreplicated bool) {
 	for _, rd := range desc.Replicas().Unwrap() {
@@ -165,11 +165,13 @@ func calcRangeCounter(
 	// We also compute an estimated per-range count of under-replicated and
 	// unavailable ranges for each range based on the liveness table.
 	if rangeCounter {
-		liveReplicas := calcLiveReplicas(desc, livenessMap)
-		if liveReplicas < desc.Replicas().QuorumSize() {
+		// Only the voting replicas count towards quorum.
+		if calcLiveReplicas(desc.Replicas().Voters(), livenessMap) < desc.Replicas().QuorumSize() {
 			unavailable = true
 		}
-		needed := GetNeededReplicas(numReplicas, clusterNodes)
+		liveReplicas := calcLiveReplicas(desc.Replicas().All(), livenessMap)
+		neededVoters := GetNeededReplicas(numVoters, clusterNodes)
+		needed := neededVoters + GetNeededNonVoters(numNonVoters, neededVoters, clusterNodes)
 		if needed > liveReplicas {
 			underreplicated = true
 		} else if needed < liveReplicas {
@@ -179,11 +181,12 @@ func calcRangeCounter(
 	return
 }
 
-// calcLiveReplicas returns a count of the live replicas; a live replica is
-// determined by checking its node in the provided liveness map.
-func calcLiveReplicas(desc *roachpb.RangeDescriptor, livenessMap IsLiveMap) int {
+// calcLiveReplicas returns a count of the live replicas among the provided
+// ones; a live replica is determined by checking its node in the provided
+// liveness map.
+func calcLiveReplicas(repls []roachpb.ReplicaDescriptor, livenessMap IsLiveMap) int {
 	var live int
-	for _, rd := range desc.Replicas().Unwrap() {
+	for _, rd := range repls {
 		if livenessMap[rd.NodeID].IsLive {
 			live++
 		}
@@ -347,6 +347,8 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		// Also clear and disable the push transaction queue. Any waiters
 		// must be redirected to the new lease holder.
 		r.txnWaitQueue.Clear(true /* disable */)
+		// Unreplicated locks are only held on the leaseholder.
+		r.lockTable.Clear()
 	}
 
 	// If we're the current raft leader, may want to transfer the leadership to
@@ -605,6 +607,16 @@ func (r *Replica) handleLocalEvalResult(ctx context.Context, lResult result.Loca
 		}
 	}
 
+	if lResult.AcquiredLocks != nil {
+		r.acquireLocks(*lResult.AcquiredLocks)
+		lResult.AcquiredLocks = nil
+	}
+
+	if lResult.ResolvedLocks != nil {
+		r.releaseLocks(*lResult.ResolvedLocks)
+		lResult.ResolvedLocks = nil
+	}
+
 	if (lResult != result.LocalResult{}) {
 		log.Fatalf(ctx, "unhandled field in LocalEvalResult: %s", pretty.Diff(lResult, result.LocalResult{}))
 	}
@@ -446,7 +446,7 @@ func (b *propBuf) FlushLockedWithRaftGroup(raftGroup *raft.RawNode) error {
 			}
 
 			if err := raftGroup.ProposeConfChange(raftpb.ConfChange{
-				Type:    changeTypeInternalToRaft[crt.ChangeType],
+				Type:    confChangeTypeForTrigger(&crt.ChangeReplicasTrigger),
 				NodeID:  uint64(crt.Replica.ReplicaID),
 				Context: encodedCtx,
 			}); err != nil && err != raft.ErrProposalDropped {
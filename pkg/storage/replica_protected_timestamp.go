@@ -0,0 +1,71 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// checkProtectedTimestampsForGC determines the timestamp that the GC queue
+// may use as the current time when computing the new GC threshold of the
+// replica, such that the threshold doesn't advance past the data protected by
+// the protected timestamp records which overlap the replica. It returns false
+// if the replica can't be garbage collected because the protected timestamp
+// cache hasn't been populated yet.
+func (r *Replica) checkProtectedTimestampsForGC(
+	ctx context.Context, now hlc.Timestamp, policy config.GCPolicy,
+) (gcTimestamp hlc.Timestamp, ok bool) {
+	cache := r.store.cfg.ProtectedTimestampCache
+	if cache == nil {
+		return now, true
+	}
+
+	r.mu.RLock()
+	desc := r.descRLocked()
+	gcThreshold := *r.mu.state.GCThreshold
+	r.mu.RUnlock()
+
+	gcTimestamp = now
+	readAt := cache.Iterate(desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(),
+		func(rec *protectedts.Record) {
+			// A record at or below the current GC threshold doesn't protect
+			// anything anymore.
+			if !gcThreshold.Less(rec.Timestamp) {
+				return
+			}
+			if ts := gcTimestampForThreshold(rec.Timestamp.Prev(), policy); ts.Less(gcTimestamp) {
+				gcTimestamp = ts
+			}
+		})
+	if (readAt == hlc.Timestamp{}) {
+		log.VEventf(ctx, 2, "protected timestamp cache not yet populated")
+		return hlc.Timestamp{}, false
+	}
+	// Records written after the cache was refreshed are unknown, so don't GC
+	// data which was live at the time of the refresh.
+	if readAt.Less(gcTimestamp) {
+		gcTimestamp = readAt
+	}
+	return gcTimestamp, true
+}
+
+// gcTimestampForThreshold returns the timestamp which, when used as the
+// current time of a GC run with the specified policy, results in the GC
+// threshold not exceeding the specified threshold.
+func gcTimestampForThreshold(threshold hlc.Timestamp, policy config.GCPolicy) hlc.Timestamp {
+	ttlNanos := int64(policy.TTLSeconds) * 1E9
+	return threshold.Add(ttlNanos, 0)
+}
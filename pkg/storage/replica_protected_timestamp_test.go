@@ -0,0 +1,114 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+type fakeProtectedTimestampCache struct {
+	readAt  hlc.Timestamp
+	records []protectedts.Record
+}
+
+func (c *fakeProtectedTimestampCache) Iterate(
+	from, to roachpb.Key, it func(*protectedts.Record),
+) hlc.Timestamp {
+	sp := roachpb.Span{Key: from, EndKey: to}
+	for i := range c.records {
+		for _, rSp := range c.records[i].Spans {
+			if sp.Overlaps(rSp) {
+				it(&c.records[i])
+				break
+			}
+		}
+	}
+	return c.readAt
+}
+
+func (c *fakeProtectedTimestampCache) Refresh(context.Context) error { return nil }
+
+func TestCheckProtectedTimestampsForGC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	cache := &fakeProtectedTimestampCache{}
+	tsc := TestStoreConfig(nil)
+	tsc.ProtectedTimestampCache = cache
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc.StartWithStoreConfig(t, stopper, tsc)
+
+	policy := config.GCPolicy{TTLSeconds: int32(time.Hour.Seconds())}
+	now := hlc.Timestamp{WallTime: (10 * time.Hour).Nanoseconds()}
+	makeRecord := func(ts hlc.Timestamp) protectedts.Record {
+		return protectedts.Record{
+			ID:        uuid.MakeV4(),
+			Timestamp: ts,
+			Spans:     []roachpb.Span{{Key: roachpb.Key("a"), EndKey: roachpb.Key("b")}},
+		}
+	}
+
+	// The replica can't be garbage collected until the cache is populated.
+	if _, ok := tc.repl.checkProtectedTimestampsForGC(ctx, now, policy); ok {
+		t.Fatal("expected GC to be disallowed before the cache is populated")
+	}
+
+	// Without records, the GC timestamp is the time of the last refresh.
+	cache.readAt = now.Add(-time.Minute.Nanoseconds(), 0)
+	if ts, ok := tc.repl.checkProtectedTimestampsForGC(ctx, now, policy); !ok || ts != cache.readAt {
+		t.Fatalf("expected GC timestamp %s, got %s (ok=%t)", cache.readAt, ts, ok)
+	}
+	cache.readAt = now
+	if ts, ok := tc.repl.checkProtectedTimestampsForGC(ctx, now, policy); !ok || ts != now {
+		t.Fatalf("expected GC timestamp %s, got %s (ok=%t)", now, ts, ok)
+	}
+
+	// Records hold the GC threshold back below their timestamp. Only the
+	// earliest record matters.
+	protected := hlc.Timestamp{WallTime: (9*time.Hour + 30*time.Minute).Nanoseconds(), Logical: 3}
+	cache.records = []protectedts.Record{
+		makeRecord(protected.Add(time.Minute.Nanoseconds(), 0)),
+		makeRecord(protected),
+	}
+	ts, ok := tc.repl.checkProtectedTimestampsForGC(ctx, now, policy)
+	if !ok {
+		t.Fatal("expected GC to be allowed")
+	}
+	if threshold := engine.MakeGarbageCollector(ts, policy).Threshold; !threshold.Less(protected) {
+		t.Fatalf("expected GC threshold %s to be below the protected timestamp %s", threshold, protected)
+	}
+	if exp := gcTimestampForThreshold(protected.Prev(), policy); ts != exp {
+		t.Fatalf("expected GC timestamp %s, got %s", exp, ts)
+	}
+
+	// A record which would have required a GC threshold below the current
+	// one doesn't hold anything back.
+	cache.records = []protectedts.Record{makeRecord(hlc.Timestamp{WallTime: 1})}
+	tc.repl.mu.Lock()
+	tc.repl.mu.state.GCThreshold = &hlc.Timestamp{WallTime: 2}
+	tc.repl.mu.Unlock()
+	if ts, ok := tc.repl.checkProtectedTimestampsForGC(ctx, now, policy); !ok || ts != now {
+		t.Fatalf("expected GC timestamp %s, got %s (ok=%t)", now, ts, ok)
+	}
+}
@@ -65,11 +65,7 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 	if raft.IsEmptyHardState(hs) || err != nil {
 		return raftpb.HardState{}, raftpb.ConfState{}, err
 	}
-	var cs raftpb.ConfState
-	for _, rep := range r.mu.state.Desc.Replicas().Unwrap() {
-		cs.Nodes = append(cs.Nodes, uint64(rep.ReplicaID))
-	}
-
+	cs := confStateFromDesc(r.mu.state.Desc)
 	return hs, cs, nil
 }
 
@@ -497,6 +493,21 @@ type IncomingSnapshot struct {
 	snapType                       SnapshotRequest_Type
 }
 
+// confStateFromDesc synthesizes the raftpb.ConfState of the range from its
+// descriptor. Voters are raft voters, while learners and non-voters are raft
+// learners.
+func confStateFromDesc(desc *roachpb.RangeDescriptor) raftpb.ConfState {
+	var cs raftpb.ConfState
+	for _, rep := range desc.Replicas().All() {
+		if rep.GetType() == roachpb.ReplicaType_VOTER {
+			cs.Nodes = append(cs.Nodes, uint64(rep.ReplicaID))
+		} else {
+			cs.Learners = append(cs.Learners, uint64(rep.ReplicaID))
+		}
+	}
+	return cs
+}
+
 // snapshot creates an OutgoingSnapshot containing a rocksdb snapshot for the
 // given range. Note that snapshot() is called without Replica.raftMu held.
 func snapshot(
@@ -535,10 +546,7 @@ func snapshot(
 	}
 
 	// Synthesize our raftpb.ConfState from desc.
-	var cs raftpb.ConfState
-	for _, rep := range desc.Replicas().Unwrap() {
-		cs.Nodes = append(cs.Nodes, uint64(rep.ReplicaID))
-	}
+	cs := confStateFromDesc(desc)
 
 	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
 	if err != nil {
@@ -629,6 +629,12 @@ func (r *Replica) requestLeaseLocked(
 		return r.mu.pendingLeaseRequest.newResolvedHandle(roachpb.NewError(
 			newNotLeaseHolderError(nil, r.store.StoreID(), r.mu.state.Desc)))
 	}
+	if repDesc.GetType() == roachpb.ReplicaType_NON_VOTER {
+		// Non-voting replicas can't hold the lease. Requests they can't serve as
+		// follower reads have to be redirected to a voter.
+		return r.mu.pendingLeaseRequest.newResolvedHandle(roachpb.NewError(
+			newNotLeaseHolderError(nil, r.store.StoreID(), r.mu.state.Desc)))
+	}
 	return r.mu.pendingLeaseRequest.InitOrJoinRequest(
 		ctx, repDesc, status, r.mu.state.Desc.StartKey.AsRawKey(), false /* transfer */)
 }
@@ -674,6 +680,9 @@ func (r *Replica) AdminTransferLease(ctx context.Context, target roachpb.StoreID
 		if nextLeaseHolder, ok = desc.GetReplicaDescriptor(target); !ok {
 			return nil, nil, errors.Errorf("unable to find store %d in range %+v", target, desc)
 		}
+		if nextLeaseHolder.GetType() == roachpb.ReplicaType_NON_VOTER {
+			return nil, nil, errors.Errorf("unable to transfer lease to non-voting replica %s", nextLeaseHolder)
+		}
 
 		if nextLease, ok := r.mu.pendingLeaseRequest.RequestPending(); ok &&
 			nextLease.Replica != nextLeaseHolder {
@@ -195,9 +195,18 @@ func (r *Replica) RangeFeed(
 		return roachpb.NewErrorf("expiration-based leases are incompatible with rangefeeds")
 	}
 
-	// Register the stream with a catch-up iterator.
+	// Register the stream with a catch-up iterator and the range tombstones
+	// that it should observe.
 	var catchUpIter engine.SimpleIterator
+	var catchUpRangeTombstones []enginepb.MVCCRangeTombstone
 	if usingCatchupIter {
+		var err error
+		catchUpRangeTombstones, err = engine.MVCCScanRangeTombstones(
+			ctx, r.Engine(), r.RangeID, args.Span.Key, args.Span.EndKey)
+		if err != nil {
+			r.raftMu.Unlock()
+			return roachpb.NewError(err)
+		}
 		innerIter := r.Engine().NewIterator(engine.IterOptions{
 			UpperBound: args.Span.EndKey,
 			// RangeFeed originally intended to use the time-bound iterator
@@ -217,7 +226,8 @@ func (r *Replica) RangeFeed(
 		iterSemRelease = nil
 	}
 	p := r.registerWithRangefeedRaftMuLocked(
-		ctx, rspan, args.Timestamp, catchUpIter, lockedStream, errC,
+		ctx, rspan, args.Timestamp, catchUpIter, catchUpRangeTombstones, args.WithDiff,
+		lockedStream, errC,
 	)
 	r.raftMu.Unlock()
 
@@ -275,6 +285,8 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	span roachpb.RSpan,
 	startTS hlc.Timestamp,
 	catchupIter engine.SimpleIterator,
+	catchupRangeTombstones []enginepb.MVCCRangeTombstone,
+	withDiff bool,
 	stream rangefeed.Stream,
 	errC chan<- *roachpb.Error,
 ) *rangefeed.Processor {
@@ -284,7 +296,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	r.rangefeedMu.RLock()
 	p := r.rangefeedMu.proc
 	if p != nil {
-		reg := p.Register(span, startTS, catchupIter, stream, errC)
+		reg := p.Register(span, startTS, catchupIter, catchupRangeTombstones, withDiff, stream, errC)
 		r.rangefeedMu.RUnlock()
 		if reg {
 			// Registered successfully with an existing processor.
@@ -331,7 +343,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// any other goroutines are able to stop the processor. In other words,
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
-	reg := p.Register(span, startTS, catchupIter, stream, errC)
+	reg := p.Register(span, startTS, catchupIter, catchupRangeTombstones, withDiff, stream, errC)
 	if !reg {
 		catchupIter.Close() // clean up
 		select {
@@ -431,19 +443,21 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(ctx context.Context, ops *stora
 	// When reading straight from the Raft log, some logical ops will not be
 	// fully populated. Read from the engine (under raftMu) to populate all
 	// fields.
+	needPrevVal := p.NeedPrevVal()
 	for _, op := range ops.Ops {
 		var key []byte
 		var ts hlc.Timestamp
-		var valPtr *[]byte
+		var valPtr, prevValPtr *[]byte
 		switch t := op.GetValue().(type) {
 		case *enginepb.MVCCWriteValueOp:
-			key, ts, valPtr = t.Key, t.Timestamp, &t.Value
+			key, ts, valPtr, prevValPtr = t.Key, t.Timestamp, &t.Value, &t.PrevValue
 		case *enginepb.MVCCCommitIntentOp:
-			key, ts, valPtr = t.Key, t.Timestamp, &t.Value
+			key, ts, valPtr, prevValPtr = t.Key, t.Timestamp, &t.Value, &t.PrevValue
 		case *enginepb.MVCCWriteIntentOp,
 			*enginepb.MVCCUpdateIntentOp,
 			*enginepb.MVCCAbortIntentOp,
-			*enginepb.MVCCAbortTxnOp:
+			*enginepb.MVCCAbortTxnOp,
+			*enginepb.MVCCDeleteRangeOp:
 			// Nothing to do.
 			continue
 		default:
@@ -464,6 +478,22 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(ctx context.Context, ops *stora
 			return
 		}
 		*valPtr = val.RawBytes
+
+		// If any registration asked for previous values, read the newest
+		// version of the key below the new value. Deletion tombstones are
+		// not returned, so a missing previous value is left empty.
+		if needPrevVal {
+			prevVal, _, err := engine.MVCCGet(ctx, r.Engine(), key, ts.Prev(), engine.MVCCGetOptions{})
+			if err != nil {
+				r.disconnectRangefeedWithErr(p, roachpb.NewErrorf(
+					"error consuming %T for key %v @ ts %v: %v", op, key, ts, err,
+				))
+				return
+			}
+			if prevVal != nil {
+				*prevValPtr = prevVal.RawBytes
+			}
+		}
 	}
 
 	// Pass the ops to the rangefeed processor.
@@ -32,7 +32,13 @@ func (r *Replica) executeReadOnlyBatch(
 	// If the read is not inconsistent, the read requires the range lease or
 	// permission to serve via follower reads.
 	var status storagepb.LeaseStatus
-	if ba.ReadConsistency.RequiresReadLease() {
+	if ba.BoundedStaleness != nil {
+		// Bounded staleness reads pick their timestamp depending on whether
+		// this replica holds the range lease.
+		if pErr := r.setBoundedStalenessTimestamp(ctx, ba); pErr != nil {
+			return nil, pErr
+		}
+	} else if ba.ReadConsistency.RequiresReadLease() {
 		if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
 			if nErr := r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
 				return nil, nErr
@@ -81,6 +87,12 @@ func (r *Replica) executeReadOnlyBatch(
 		return nil, roachpb.NewError(err)
 	}
 
+	// Locking reads must wait for conflicting unreplicated locks held by other
+	// transactions to be released.
+	if pErr := r.checkLockConflicts(ba); pErr != nil {
+		return nil, pErr
+	}
+
 	// Evaluate read-only batch command. It checks for matching key range; note
 	// that holding readOnlyCmdMu throughout is important to avoid reads from the
 	// "wrong" key range being served after the range has been split.
@@ -93,6 +105,12 @@ func (r *Replica) executeReadOnlyBatch(
 	defer readOnly.Close()
 	br, result, pErr = evaluateBatch(ctx, storagebase.CmdIDKey(""), readOnly, rec, nil, ba, true /* readOnly */)
 
+	// Add the locks acquired by locking reads to the lock table. This happens
+	// while still holding latches so that conflicting requests observe them.
+	if locks := result.Local.DetachAcquiredLocks(); len(locks) > 0 && pErr == nil {
+		r.acquireLocks(locks)
+	}
+
 	// A merge is (likely) about to be carried out, and this replica
 	// needs to block all traffic until the merge either commits or
 	// aborts. See docs/tech-notes/range-merges.md.
@@ -116,6 +116,12 @@ func (r *Replica) executeWriteBatch(
 	}
 	r.limitTxnMaxTimestamp(ctx, ba, status)
 
+	// Writes and locking reads must wait for conflicting unreplicated locks
+	// held by other transactions to be released.
+	if pErr := r.checkLockConflicts(ba); pErr != nil {
+		return nil, pErr
+	}
+
 	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
 	defer untrack(ctx, 0, 0, 0) // covers all error returns below
 
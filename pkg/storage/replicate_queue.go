@@ -215,7 +215,7 @@ func (rq *replicateQueue) shouldQueue(
 	if lease, _ := repl.GetLease(); repl.IsLeaseValid(lease, now) {
 		if rq.canTransferLease() &&
 			rq.allocator.ShouldTransferLease(
-				ctx, zone, desc.Replicas().Unwrap(), lease.Replica.StoreID, desc.RangeID, repl.leaseholderStats) {
+				ctx, zone, desc.Replicas().Voters(), lease.Replica.StoreID, desc.RangeID, repl.leaseholderStats) {
 			log.VEventf(ctx, 2, "lease transfer needed, enqueuing")
 			return true, 0
 		}
@@ -278,12 +278,12 @@ func (rq *replicateQueue) processOneChange(
 	desc, zone := repl.DescAndZone()
 
 	// Avoid taking action if the range has too many dead replicas to make
-	// quorum.
-	liveReplicas, deadReplicas := rq.allocator.storePool.liveAndDeadReplicas(
-		desc.RangeID, desc.Replicas().Unwrap())
+	// quorum. Only the voting replicas count towards quorum.
+	liveVoters, deadVoters := rq.allocator.storePool.liveAndDeadReplicas(
+		desc.RangeID, desc.Replicas().Voters())
 	{
 		quorum := desc.Replicas().QuorumSize()
-		if lr := len(liveReplicas); lr < quorum {
+		if lr := len(liveVoters); lr < quorum {
 			return false, newQuorumError(
 				"range requires a replication change, but lacks a quorum of live replicas (%d/%d)", lr, quorum)
 		}
@@ -296,10 +296,14 @@ func (rq *replicateQueue) processOneChange(
 	case AllocatorNoop:
 		break
 	case AllocatorAdd:
+		// Only include the live voters, since the dead voters should soon be
+		// removed. The non-voters are included so that the new voter isn't placed
+		// on one of their nodes.
+		existing := append(liveVoters, desc.Replicas().NonVoters()...)
 		newStore, details, err := rq.allocator.AllocateTarget(
 			ctx,
 			zone,
-			liveReplicas, // only include liveReplicas, since deadReplicas should soon be removed
+			existing,
 			rangeInfo,
 		)
 		if err != nil {
@@ -311,8 +315,8 @@ func (rq *replicateQueue) processOneChange(
 		}
 
 		clusterNodes := rq.allocator.storePool.ClusterNodeCount()
-		need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
-		willHave := len(desc.Replicas().Unwrap()) + 1
+		need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
+		willHave := len(desc.Replicas().Voters()) + 1
 
 		// Only up-replicate if there are suitable allocation targets such
 		// that, either the replication goal is met, or it is possible to get to the
@@ -351,6 +355,7 @@ func (rq *replicateQueue) processOneChange(
 			ctx,
 			repl,
 			newReplica,
+			roachpb.ReplicaType_VOTER,
 			desc,
 			SnapshotRequest_RECOVERY,
 			storagepb.ReasonRangeUnderReplicated,
@@ -385,7 +390,7 @@ func (rq *replicateQueue) processOneChange(
 				// If we've lost raft leadership, we're unlikely to regain it so give up immediately.
 				return false, &benignError{errors.Errorf("not raft leader while range needs removal")}
 			}
-			candidates = filterUnremovableReplicas(raftStatus, desc.Replicas().Unwrap(), lastReplAdded)
+			candidates = filterUnremovableReplicas(raftStatus, desc.Replicas().Voters(), lastReplAdded)
 			log.VEventf(ctx, 3, "filtered unremovable replicas from %v to get %v as candidates for removal: %s",
 				desc.Replicas(), candidates, rangeRaftProgress(raftStatus, desc.Replicas().Unwrap()))
 			if len(candidates) > 0 {
@@ -465,7 +470,7 @@ func (rq *replicateQueue) processOneChange(
 		}
 	case AllocatorRemoveDecommissioning:
 		decommissioningReplicas := rq.allocator.storePool.decommissioningReplicas(
-			desc.RangeID, desc.Replicas().Unwrap())
+			desc.RangeID, desc.Replicas().Voters())
 		if len(decommissioningReplicas) == 0 {
 			log.VEventf(ctx, 1, "range of replica %s was identified as having decommissioning replicas, "+
 				"but no decommissioning replicas were found", repl)
@@ -508,11 +513,11 @@ func (rq *replicateQueue) processOneChange(
 			}
 		}
 	case AllocatorRemoveDead:
-		if len(deadReplicas) == 0 {
+		if len(deadVoters) == 0 {
 			log.VEventf(ctx, 1, "range of replica %s was identified as having dead replicas, but no dead replicas were found", repl)
 			break
 		}
-		deadReplica := deadReplicas[0]
+		deadReplica := deadVoters[0]
 		rq.metrics.RemoveDeadReplicaCount.Inc(1)
 		log.VEventf(ctx, 1, "removing dead replica %+v from store", deadReplica)
 		target := roachpb.ReplicationTarget{
@@ -544,6 +549,7 @@ func (rq *replicateQueue) processOneChange(
 					ctx,
 					repl,
 					rebalanceReplica,
+					roachpb.ReplicaType_VOTER,
 					desc,
 					SnapshotRequest_REBALANCE,
 					storagepb.ReasonRebalance,
@@ -582,6 +588,121 @@ func (rq *replicateQueue) processOneChange(
 		// No action was necessary and no rebalance target was found. Return
 		// without re-queuing this replica.
 		return false, nil
+	case AllocatorPromoteNonVoter:
+		liveNonVoters, _ := rq.allocator.storePool.liveAndDeadReplicas(
+			desc.RangeID, desc.Replicas().NonVoters())
+		promoteNonVoter, details, err := rq.allocator.PromoteTarget(
+			ctx, zone, liveVoters, liveNonVoters, rangeInfo)
+		if err != nil {
+			return false, err
+		}
+		target := roachpb.ReplicationTarget{
+			NodeID:  promoteNonVoter.NodeID,
+			StoreID: promoteNonVoter.StoreID,
+		}
+		rq.metrics.AddReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "promoting non-voter %+v due to under-replication: %s",
+			target, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+		// The promoted replica stays on its store, so the store pool needs no
+		// update.
+		if !dryRun {
+			if _, err := repl.changeReplicas(
+				ctx, roachpb.ADD_REPLICA, target, roachpb.ReplicaType_VOTER, desc,
+				SnapshotRequest_RECOVERY, storagepb.ReasonRangeUnderReplicated, details,
+			); err != nil {
+				return false, err
+			}
+		}
+	case AllocatorAddNonVoter:
+		newStore, details, err := rq.allocator.AllocateTarget(
+			ctx,
+			zone,
+			desc.Replicas().All(),
+			rangeInfo,
+		)
+		if err != nil {
+			return false, err
+		}
+		newNonVoter := roachpb.ReplicationTarget{
+			NodeID:  newStore.Node.NodeID,
+			StoreID: newStore.StoreID,
+		}
+		rq.metrics.AddReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "adding non-voter %+v due to under-replication: %s",
+			newNonVoter, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+		if err := rq.addReplica(
+			ctx,
+			repl,
+			newNonVoter,
+			roachpb.ReplicaType_NON_VOTER,
+			desc,
+			SnapshotRequest_RECOVERY,
+			storagepb.ReasonRangeUnderReplicated,
+			details,
+			dryRun,
+		); err != nil {
+			return false, err
+		}
+	case AllocatorRemoveNonVoter:
+		// Non-voters can't hold the lease, so unlike in the AllocatorRemove case
+		// the local replica is never the removal target.
+		removeNonVoter, details, err := rq.allocator.RemoveTarget(
+			ctx, zone, desc.Replicas().NonVoters(), rangeInfo)
+		if err != nil {
+			return false, err
+		}
+		rq.metrics.RemoveReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing non-voter %+v due to over-replication: %s",
+			removeNonVoter, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+		target := roachpb.ReplicationTarget{
+			NodeID:  removeNonVoter.NodeID,
+			StoreID: removeNonVoter.StoreID,
+		}
+		if err := rq.removeReplica(
+			ctx, repl, target, desc, storagepb.ReasonRangeOverReplicated, details, dryRun,
+		); err != nil {
+			return false, err
+		}
+	case AllocatorRemoveDeadNonVoter:
+		_, deadNonVoters := rq.allocator.storePool.liveAndDeadReplicas(
+			desc.RangeID, desc.Replicas().NonVoters())
+		if len(deadNonVoters) == 0 {
+			log.VEventf(ctx, 1, "range of replica %s was identified as having dead non-voters, "+
+				"but no dead non-voters were found", repl)
+			break
+		}
+		deadNonVoter := deadNonVoters[0]
+		rq.metrics.RemoveDeadReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing dead non-voter %+v from store", deadNonVoter)
+		target := roachpb.ReplicationTarget{
+			NodeID:  deadNonVoter.NodeID,
+			StoreID: deadNonVoter.StoreID,
+		}
+		if err := rq.removeReplica(
+			ctx, repl, target, desc, storagepb.ReasonStoreDead, "", dryRun,
+		); err != nil {
+			return false, err
+		}
+	case AllocatorRemoveDecommissioningNonVoter:
+		decommissioningNonVoters := rq.allocator.storePool.decommissioningReplicas(
+			desc.RangeID, desc.Replicas().NonVoters())
+		if len(decommissioningNonVoters) == 0 {
+			log.VEventf(ctx, 1, "range of replica %s was identified as having decommissioning non-voters, "+
+				"but no decommissioning non-voters were found", repl)
+			break
+		}
+		decommissioningNonVoter := decommissioningNonVoters[0]
+		rq.metrics.RemoveReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing decommissioning non-voter %+v from store", decommissioningNonVoter)
+		target := roachpb.ReplicationTarget{
+			NodeID:  decommissioningNonVoter.NodeID,
+			StoreID: decommissioningNonVoter.StoreID,
+		}
+		if err := rq.removeReplica(
+			ctx, repl, target, desc, storagepb.ReasonStoreDecommissioning, "", dryRun,
+		); err != nil {
+			return false, err
+		}
 	}
 
 	return true, nil
@@ -600,7 +721,8 @@ func (rq *replicateQueue) findTargetAndTransferLease(
 	zone *config.ZoneConfig,
 	opts transferLeaseOptions,
 ) (bool, error) {
-	candidates := filterBehindReplicas(repl.RaftStatus(), desc.Replicas().Unwrap())
+	// Non-voting replicas can't hold the lease.
+	candidates := filterBehindReplicas(repl.RaftStatus(), desc.Replicas().Voters())
 	target := rq.allocator.TransferLeaseTarget(
 		ctx,
 		zone,
@@ -647,6 +769,7 @@ func (rq *replicateQueue) addReplica(
 	ctx context.Context,
 	repl *Replica,
 	target roachpb.ReplicationTarget,
+	replicaType roachpb.ReplicaType,
 	desc *roachpb.RangeDescriptor,
 	priority SnapshotRequest_Priority,
 	reason storagepb.RangeLogEventReason,
@@ -656,7 +779,9 @@ func (rq *replicateQueue) addReplica(
 	if dryRun {
 		return nil
 	}
-	if _, err := repl.changeReplicas(ctx, roachpb.ADD_REPLICA, target, desc, priority, reason, details); err != nil {
+	if _, err := repl.changeReplicas(
+		ctx, roachpb.ADD_REPLICA, target, replicaType, desc, priority, reason, details,
+	); err != nil {
 		return err
 	}
 	rangeInfo := rangeInfoForRepl(repl, desc)
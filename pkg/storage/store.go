@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/idalloc"
 	"github.com/cockroachdb/cockroach/pkg/storage/intentresolver"
+	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/storage/raftentry"
 	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/storage/tscache"
@@ -100,6 +101,16 @@ var changeTypeInternalToRaft = map[roachpb.ReplicaChangeType]raftpb.ConfChangeTy
 	roachpb.REMOVE_REPLICA: raftpb.ConfChangeRemoveNode,
 }
 
+// confChangeTypeForTrigger returns the raft ConfChangeType corresponding to
+// the ChangeReplicasTrigger. Non-voting replicas are added to the raft group
+// as raft learners, which receive the log but don't vote.
+func confChangeTypeForTrigger(crt *roachpb.ChangeReplicasTrigger) raftpb.ConfChangeType {
+	if crt.ChangeType == roachpb.ADD_REPLICA && crt.Replica.GetType() == roachpb.ReplicaType_NON_VOTER {
+		return raftpb.ConfChangeAddLearnerNode
+	}
+	return changeTypeInternalToRaft[crt.ChangeType]
+}
+
 var storeSchedulerConcurrency = envutil.EnvOrDefaultInt(
 	"COCKROACH_SCHEDULER_CONCURRENCY", 8*runtime.NumCPU())
 
@@ -644,6 +655,11 @@ type StoreConfig struct {
 	// maintenance queue to dispatch individual maintenance tasks.
 	TimeSeriesDataStore TimeSeriesDataStore
 
+	// ProtectedTimestampCache is consulted by the GC queue to avoid garbage
+	// collecting data protected by protected timestamp records. If nil, no
+	// data is protected.
+	ProtectedTimestampCache protectedts.Cache
+
 	// CoalescedHeartbeatsInterval is the interval for which heartbeat messages
 	// are queued and then sent as a single coalesced heartbeat; it is a
 	// fraction of the RaftTickInterval so that heartbeats don't get delayed by
@@ -2245,6 +2261,11 @@ func (s *Store) SplitRange(
 	// txnWaitQueue after we clear it.
 	leftRepl.txnWaitQueue.Clear(false /* disable */)
 
+	// Unreplicated locks are not carried over to the RHS, so drop them
+	// altogether. They are only an optimization and the transactions holding
+	// them still write intents before committing.
+	leftRepl.lockTable.Clear()
+
 	// The rangefeed processor will no longer be provided logical ops for
 	// its entire range, so it needs to be shut down and all registrations
 	// need to retry.
@@ -2347,6 +2368,8 @@ func (s *Store) MergeRange(
 	// Clear the wait queue to redirect the queued transactions to the
 	// left-hand replica, if necessary.
 	rightRepl.txnWaitQueue.Clear(true /* disable */)
+	// Unreplicated locks are not carried over to the LHS.
+	rightRepl.lockTable.Clear()
 
 	leftLease, _ := leftRepl.GetLease()
 	rightLease, _ := rightRepl.GetLease()
@@ -3005,8 +3028,13 @@ func (s *Store) Send(
 			// Process and resolve write intent error. We do this here because
 			// this is the code path with the requesting client waiting.
 			if pErr.Index != nil {
+				if ba.WaitPolicy == roachpb.LOCK_WAIT_ERROR {
+					// The request asked not to wait on conflicting locks or
+					// intents.
+					return nil, pErr
+				}
 				var pushType roachpb.PushTxnType
-				if ba.IsWrite() {
+				if ba.IsWrite() || ba.IsLocking() {
 					pushType = roachpb.PUSH_ABORT
 				} else {
 					pushType = roachpb.PUSH_TIMESTAMP
@@ -387,8 +387,9 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
 			desc.RangeID, replWithStats.qps)
 
-		// Check all the other replicas in order of increasing qps.
-		replicas := desc.Replicas().DeepCopy().Unwrap()
+		// Check all the other replicas in order of increasing qps. Non-voting
+		// replicas can't hold the lease.
+		replicas := desc.Replicas().DeepCopy().Voters()
 		sort.Slice(replicas, func(i, j int) bool {
 			var iQPS, jQPS float64
 			if desc := storeMap[replicas[i].StoreID]; desc != nil {
@@ -421,7 +422,7 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			preferred := sr.rq.allocator.preferredLeaseholders(zone, desc.Replicas().Unwrap())
+			preferred := sr.rq.allocator.preferredLeaseholders(zone, desc.Replicas().Voters())
 			if len(preferred) > 0 && !storeHasReplica(candidate.StoreID, preferred) {
 				log.VEventf(ctx, 3, "s%d not a preferred leaseholder for r%d; preferred: %v",
 					candidate.StoreID, desc.RangeID, preferred)
@@ -434,7 +435,7 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				filteredStoreList,
 				*localDesc,
 				candidate.StoreID,
-				desc.Replicas().Unwrap(),
+				desc.Replicas().Voters(),
 				replWithStats.repl.leaseholderStats,
 			) {
 				log.VEventf(ctx, 3, "r%d is on s%d due to follow-the-workload; skipping",
@@ -492,6 +493,14 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
 			desc.RangeID, replWithStats.qps)
 
+		// Relocating a range replaces all of its replicas with voters, so ranges
+		// with non-voting replicas are left to the replicate queue.
+		if len(desc.Replicas().NonVoters()) > 0 {
+			log.VEventf(ctx, 3, "r%d has non-voting replicas, not considering it for replica rebalance",
+				desc.RangeID)
+			continue
+		}
+
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
 		desiredReplicas := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
 		targets := make([]roachpb.ReplicationTarget, 0, desiredReplicas)
@@ -158,6 +158,21 @@ func (ls *Stores) GetReplicaForRangeID(rangeID roachpb.RangeID) (*Replica, error
 	return replica, nil
 }
 
+// IsMeta1Leaseholder returns whether one of the stores holds a valid lease
+// on the first range, which contains the meta1 addressing records, at the
+// given timestamp. It is used to elect a single node to run cluster-wide
+// background tasks.
+func (ls *Stores) IsMeta1Leaseholder(now hlc.Timestamp) (bool, error) {
+	repl, err := ls.GetReplicaForRangeID(1)
+	if _, ok := err.(*roachpb.RangeNotFoundError); ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return repl.OwnsValidLease(now), nil
+}
+
 // Send implements the client.Sender interface. The store is looked up from the
 // store map using the ID specified in the request.
 func (ls *Stores) Send(